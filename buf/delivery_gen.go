@@ -1,3 +1,3 @@
 package buf
 
-//go:generate buf generate ../../protos/im --template buf.gen.delivery.yaml --path ../../protos/im/api/delivery
+//go:generate buf generate ../proto --template buf.gen.delivery.yaml --path ../proto/api/delivery
@@ -0,0 +1,4 @@
+package buf
+
+// Generate Group base API
+//go:generate buf generate ../../protos/im --template ./buf.gen.group.yaml --path ../../protos/im/service/group/v1
@@ -18,6 +18,7 @@ func Run() error {
 		Usage: "Microservice for Webitel platform",
 		Commands: []*cli.Command{
 			serverCmd(),
+			poisonCmd(),
 		},
 	}
 
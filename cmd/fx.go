@@ -3,11 +3,19 @@ package cmd
 import (
 	"github.com/webitel/im-delivery-service/config"
 	webiteldi "github.com/webitel/im-delivery-service/infra/client/di"
+	"github.com/webitel/im-delivery-service/infra/keyring"
+	"github.com/webitel/im-delivery-service/infra/redis"
 	grpcsrv "github.com/webitel/im-delivery-service/infra/server/grpc"
+	httpsrv "github.com/webitel/im-delivery-service/infra/server/http"
 	"github.com/webitel/im-delivery-service/infra/tls"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
 	"github.com/webitel/im-delivery-service/internal/domain/registry"
 	amqpdi "github.com/webitel/im-delivery-service/internal/handler/amqp"
 	grpchandler "github.com/webitel/im-delivery-service/internal/handler/grpc"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"github.com/webitel/im-delivery-service/internal/handler/lp"
+	"github.com/webitel/im-delivery-service/internal/handler/sse"
+	"github.com/webitel/im-delivery-service/internal/handler/ws"
 	servicedi "github.com/webitel/im-delivery-service/internal/service/di"
 	"github.com/webitel/webitel-go-kit/infra/discovery"
 	"go.uber.org/fx"
@@ -15,6 +23,20 @@ import (
 
 func NewApp(cfg *config.Config) *fx.App {
 	return fx.New(
+		common(cfg),
+		roleModules(cfg),
+	)
+}
+
+// common is wired on every node regardless of role.
+func common(cfg *config.Config) fx.Option {
+	// [OPTIONAL_DIRECTORIES] Deployments without a groups service boot without the client.
+	var groups fx.Option = fx.Options()
+	if cfg.Enrichment.Groups {
+		groups = webiteldi.GroupsModule
+	}
+
+	return fx.Options(
 		fx.Provide(
 			func() *config.Config { return cfg },
 			ProvideLogger,
@@ -23,12 +45,48 @@ func NewApp(cfg *config.Config) *fx.App {
 			ProvidePubSub,
 		),
 		fx.Invoke(func(discovery discovery.DiscoveryProvider) error { return nil }),
+		event.WithUUIDGenerator(event.GeneratorByName(cfg.Delivery.EventIDs)),
 		tls.Module,
+		keyring.Module,
 		webiteldi.Module,
+		groups,
+	)
+}
+
+// roleModules selects the delivery graph or the observer graph.
+// [OBSERVER] No Hub, no transports, no gRPC server: consume, enrich and export only.
+func roleModules(cfg *config.Config) fx.Option {
+	if cfg.IsObserver() {
+		return fx.Options(
+			servicedi.ObserverModule,
+			amqpdi.ObserverModule,
+		)
+	}
+
+	modules := []fx.Option{
 		servicedi.Module,
 		registry.Module,
 		grpchandler.Module,
 		grpcsrv.Module,
 		amqpdi.Module,
+	}
+	// [HTTP_TRANSPORTS] WS, LP and SSE share one router; LP and SSE sit behind httpauth.
+	if cfg.Service.HTTPAddress != "" {
+		modules = append(modules,
+			httpsrv.Module,
+			httpauth.Module,
+			ws.Module,
+			lp.Module,
+			sse.Module,
+		)
+	}
+	modules = append(modules,
+		// [SHUTDOWN_ORDER] Root-level, so it stops first: router, then Hub drain, then gRPC.
+		amqpdi.ShutdownSequence,
 	)
+	// [GLOBAL_PRESENCE] Opt-in: without it IsConnectedGlobal answers for this node only.
+	if cfg.Delivery.Presence.Global {
+		modules = append(modules, redis.Module)
+	}
+	return fx.Options(modules...)
 }
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/urfave/cli/v2"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/infra/pubsub"
+	"github.com/webitel/im-delivery-service/infra/pubsub/factory/amqp"
+	pubsubadapter "github.com/webitel/im-delivery-service/internal/adapter/pubsub"
+	amqphandler "github.com/webitel/im-delivery-service/internal/handler/amqp"
+)
+
+// replayIdle ends a replay run once the parking queue stays quiet this long.
+const replayIdle = 2 * time.Second
+
+func poisonCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "poison",
+		Usage: "Inspect and requeue poisoned AMQP messages",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "replay",
+				Usage: "Republish parked messages to their original exchange and routing key",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "topic",
+						Usage: "Replay only messages poisoned on this source topic (empty = all)",
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Value: 100,
+						Usage: "Maximum number of messages to replay",
+					},
+					&cli.StringFlag{
+						Name:  "config_file",
+						Usage: "Path to the configuration file",
+					},
+				},
+				Action: replayPoison,
+			},
+		},
+	}
+}
+
+func replayPoison(c *cli.Context) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	wmLogger := watermill.NewSlogLogger(logger)
+
+	f, err := amqp.NewFactory(cfg.Pubsub.URL, wmLogger)
+	if err != nil {
+		return err
+	}
+	// [NO_RUN] The router only satisfies the provider; the tool consumes directly.
+	router, err := message.NewRouter(message.RouterConfig{}, wmLogger)
+	if err != nil {
+		return err
+	}
+	provider, err := pubsub.NewDefaultProvider(router, f)
+	if err != nil {
+		return err
+	}
+
+	// The parking queue is bound on the delivery exchange; observer nodes park on their own.
+	sub, err := pubsubadapter.NewSubscriberProvider(provider).
+		BuildShared(amqphandler.PoisonParkingQueue, amqphandler.DeliveryExchange, amqphandler.DeliveryPoisonTopic)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	// [CONFIRMS] A replayed message is acked off the parking queue only once the broker has it.
+	pp := pubsubadapter.NewPublisherProvider(provider, pubsubadapter.WithPublisherConfirms(true))
+	defer pp.Close()
+
+	report, err := amqphandler.ReplayPoisoned(c.Context, sub, pp.Get, amqphandler.ReplayOptions{
+		Topic:      c.String("topic"),
+		Limit:      c.Int("limit"),
+		MaxReplays: cfg.Pubsub.Poison.MaxReplays,
+		Idle:       replayIdle,
+	})
+	logger.Info("POISON_REPLAY_DONE",
+		slog.Int("replayed", report.Replayed),
+		slog.Int("skipped", report.Skipped),
+		slog.Int("capped", report.Capped),
+	)
+	if err != nil {
+		return fmt.Errorf("poison replay: %w", err)
+	}
+	return nil
+}
@@ -240,16 +240,19 @@ func ProvidePubSub(cfg *config.Config, l *slog.Logger, lc fx.Lifecycle) (pubsub.
 	if err != nil {
 		return nil, err
 	}
+	// [LIFECYCLE_CONTEXT] Same contract as the amqp pipeline's router: bound to the app, drained on stop.
+	runCtx, cancel := context.WithCancel(context.Background())
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			go func() {
-				if err := router.Run(context.Background()); err != nil {
+				if err := router.Run(runCtx); err != nil {
 					l.Error("watermill router failed", slog.Any("error", err))
 				}
 			}()
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
+			defer cancel()
 			return router.Close()
 		},
 	})
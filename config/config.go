@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"log"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/pflag"
@@ -17,12 +20,31 @@ type Config struct {
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Consul   ConsulConfig   `mapstructure:"consul"`
 	Pubsub   PubsubConfig   `mapstructure:"pubsub"`
+	Delivery DeliveryConfig `mapstructure:"delivery"`
+	Signing  SigningConfig  `mapstructure:"signing"`
+
+	Enrichment EnrichmentConfig `mapstructure:"enrichment"`
+
+	Deprecation DeprecationConfig `mapstructure:"deprecation"`
 }
 
+// EnvProduction is the environment name in which test-only features refuse to activate.
+const EnvProduction = "production"
+
+// Node roles. An observer consumes, enriches and exports events but serves no clients.
+const (
+	RoleDelivery = "delivery"
+	RoleObserver = "observer"
+)
+
 type ServiceConfig struct {
-	ID         string           `mapstructure:"id"`
-	Address    string           `mapstructure:"addr"`
-	Connection ConnectionConfig `mapstructure:"conn"`
+	ID          string           `mapstructure:"id"`
+	Address     string           `mapstructure:"addr"`
+	HTTPAddress string           `mapstructure:"http_addr"` // WS, LP and SSE transports ("" disables them)
+	Environment string           `mapstructure:"env"`
+	Region      string           `mapstructure:"region"`
+	Role        string           `mapstructure:"role"` // delivery | observer
+	Connection  ConnectionConfig `mapstructure:"conn"`
 }
 
 type ConnectionConfig struct {
@@ -61,13 +83,304 @@ type ConsulConfig struct {
 	PublicAddress string `mapstructure:"grpc_addr"`
 }
 
+// EnrichmentConfig bounds the peer profile cache in front of the contact/bot/channel directories.
+type EnrichmentConfig struct {
+	CacheSize   int           `mapstructure:"cache_size"`   // Entries per cache (resolved and negative)
+	CacheTTL    time.Duration `mapstructure:"cache_ttl"`    // Lifetime of a resolved profile; renames show up after this
+	NegativeTTL time.Duration `mapstructure:"negative_ttl"` // Lifetime of a failed/empty lookup (0 disables negative caching)
+
+	ContactTimeout time.Duration `mapstructure:"contact_timeout"` // Budget of one contact-service lookup
+	Breaker        BreakerConfig `mapstructure:"breaker"`
+
+	Groups bool `mapstructure:"groups"` // Resolve group and channel peers through im-group-service
+}
+
+// BreakerConfig trips the contact lookup breaker on the failure rate over a rolling window.
+type BreakerConfig struct {
+	MinRequests      uint32        `mapstructure:"min_requests"`       // Calls in the window before the ratio is judged
+	FailureRatio     float64       `mapstructure:"failure_ratio"`      // Failing share that opens the breaker (0..1)
+	Window           time.Duration `mapstructure:"window"`             // Rolling window while closed
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"`       // Time open before probing (half-open)
+	HalfOpenRequests uint32        `mapstructure:"half_open_requests"` // Probes allowed while half-open
+}
+
 type PubsubConfig struct {
 	URL    string `mapstructure:"broker_url"`
 	Driver string `mapstructure:"broker_driver"`
+	// PublishTimeout bounds each broker publish round-trip.
+	PublishTimeout time.Duration `mapstructure:"publish_timeout"`
+	// RouterCloseTimeout bounds how long shutdown waits for in-flight handlers.
+	RouterCloseTimeout time.Duration `mapstructure:"router_close_timeout"`
+	// Confirm makes every publish wait for the broker's confirmation.
+	Confirm bool `mapstructure:"confirm"`
+	// PublishRetry bounds re-publishing after a broker error.
+	PublishRetry PublishRetryConfig `mapstructure:"publish_retry"`
+	// Outbox buffers re-publishes while the broker is unavailable.
+	Outbox OutboxConfig `mapstructure:"outbox"`
+	// Handler is the retry/throttle/timeout policy of every AMQP consumer.
+	Handler HandlerPolicy `mapstructure:"handler"`
+	// Handlers overrides Handler per consumer, keyed by handler name (e.g. on_thread_typing).
+	Handlers map[string]HandlerPolicy `mapstructure:"handlers"`
+	// Poison bounds the replay of poisoned messages.
+	Poison PoisonConfig `mapstructure:"poison"`
+	// Dedup suppresses redelivered messages already handed to their recipient.
+	Dedup DedupConfig `mapstructure:"dedup"`
+}
+
+// DedupConfig bounds the consumer's memory of recently delivered messages.
+type DedupConfig struct {
+	Size int           `mapstructure:"size"` // Remembered {delivery, user_id} pairs (0 disables)
+	TTL  time.Duration `mapstructure:"ttl"`  // How long a pair is remembered; covers the broker's redelivery window
+}
+
+// PoisonConfig bounds the poison replay tooling.
+type PoisonConfig struct {
+	MaxReplays int `mapstructure:"max_replays"` // Replays of one message before it stays parked
+}
+
+// HandlerPolicy bounds the retries, rate and runtime of one AMQP consumer.
+type HandlerPolicy struct {
+	MaxRetries      int           `mapstructure:"max_retries"`      // Retries after the first attempt; -1 in an override means none
+	InitialInterval time.Duration `mapstructure:"initial_interval"` // Backoff before the first retry
+	MaxInterval     time.Duration `mapstructure:"max_interval"`     // Upper bound of a single backoff
+	Multiplier      float64       `mapstructure:"multiplier"`       // Backoff growth per retry
+	Throttle        int64         `mapstructure:"throttle"`         // Messages per second (0 = unlimited)
+	Timeout         time.Duration `mapstructure:"timeout"`          // Per-attempt handler deadline (0 = none)
+}
+
+// Merge overlays the non-zero fields of o onto p.
+func (p HandlerPolicy) Merge(o HandlerPolicy) HandlerPolicy {
+	switch {
+	case o.MaxRetries < 0:
+		p.MaxRetries = 0
+	case o.MaxRetries > 0:
+		p.MaxRetries = o.MaxRetries
+	}
+	if o.InitialInterval > 0 {
+		p.InitialInterval = o.InitialInterval
+	}
+	if o.MaxInterval > 0 {
+		p.MaxInterval = o.MaxInterval
+	}
+	if o.Multiplier > 0 {
+		p.Multiplier = o.Multiplier
+	}
+	if o.Throttle > 0 {
+		p.Throttle = o.Throttle
+	}
+	if o.Timeout > 0 {
+		p.Timeout = o.Timeout
+	}
+	return p
+}
+
+// HandlerOverride returns the configured override of a consumer. Config keys are
+// case-insensitive, so the name is matched that way.
+func (c PubsubConfig) HandlerOverride(name string) (HandlerPolicy, bool) {
+	for k, p := range c.Handlers {
+		if strings.EqualFold(k, name) {
+			return p, true
+		}
+	}
+	return HandlerPolicy{}, false
+}
+
+// OutboxConfig sizes the in-memory publish outbox.
+type OutboxConfig struct {
+	Size         int           `mapstructure:"size"`          // Parked messages before the lowest-priority oldest is shed (0 disables)
+	FlushTimeout time.Duration `mapstructure:"flush_timeout"` // Shutdown wait for the outbox to drain
+}
+
+// PublishRetryConfig is the exponential backoff applied to failed publishes.
+type PublishRetryConfig struct {
+	Attempts   int           `mapstructure:"attempts"`    // Total attempts, including the first (1 disables retries)
+	Backoff    time.Duration `mapstructure:"backoff"`     // Pause before the first retry; doubled after each
+	MaxBackoff time.Duration `mapstructure:"max_backoff"` // Upper bound of a single pause
+}
+
+type DeliveryConfig struct {
+	Buffer    BufferConfig    `mapstructure:"buffer"`
+	Shaping   ShapingConfig   `mapstructure:"shaping"`
+	Residency ResidencyConfig `mapstructure:"residency"`
+	Import    ImportConfig    `mapstructure:"import"`
+	Reconnect ReconnectConfig `mapstructure:"reconnect"`
+	Analytics AnalyticsConfig `mapstructure:"analytics"`
+	E2EE      E2EEConfig      `mapstructure:"e2ee"`
+	Slow      SlowConfig      `mapstructure:"slow"`
+	Pause     PauseConfig     `mapstructure:"pause"`
+	Presence  PresenceConfig  `mapstructure:"presence"`
+	Push      PushConfig      `mapstructure:"push"`
+	WS        WSConfig        `mapstructure:"ws"`
+	LP        LPConfig        `mapstructure:"lp"`
+	Receipts  ReceiptsConfig  `mapstructure:"receipts"`
+	EventIDs  string          `mapstructure:"event_ids"` // random | sequential | counter
+
+	// SendTimeout bounds a single gRPC stream write; a stalled peer is disconnected (0 disables).
+	SendTimeout time.Duration `mapstructure:"send_timeout"`
+
+	// Heartbeat is the idle gap after which a stream gets a ping event (0 disables).
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+
+	// DrainTimeout bounds the shutdown drain: sessions are notified and mailboxes
+	// flushed for at most this long before the Hub closes them.
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+}
+
+// PauseConfig bounds domain maintenance pauses.
+type PauseConfig struct {
+	MaxEventsPerDomain int           `mapstructure:"max_events_per_domain"` // Holding buffer size; overflow is shed
+	MaxDuration        time.Duration `mapstructure:"max_duration"`          // Longest window a pause may request
+}
+
+// WSConfig hardens the WebSocket upgrade.
+type WSConfig struct {
+	AllowedOrigins  []string `mapstructure:"allowed_origins"`   // "https://app.example.com", "*.example.com"; the request host is always allowed
+	AllowAll        bool     `mapstructure:"allow_all"`         // [DEV_ONLY] Accept any Origin
+	ReadBufferSize  int      `mapstructure:"read_buffer_size"`  // Upgrader read buffer (0 = gorilla default)
+	WriteBufferSize int      `mapstructure:"write_buffer_size"` // Upgrader write buffer (0 = gorilla default)
+	MaxHeaderBytes  int      `mapstructure:"max_header_bytes"`  // Larger upgrade requests get 431 (0 = unbounded)
+}
+
+// LPConfig holds the long-poll defaults; clients may override them per request
+// within fixed bounds (wait 1s..90s, limit 1..100).
+type LPConfig struct {
+	Wait  time.Duration `mapstructure:"wait"`  // How long a poll waits for the first event
+	Limit int           `mapstructure:"limit"` // Most events returned by one poll
+}
+
+// ReceiptsConfig selects the event kinds whose wire delivery is acknowledged on the bus.
+// Kinds use the EventKind names (e.g. "MessageCreated"); none disables receipts.
+type ReceiptsConfig struct {
+	Kinds    []string `mapstructure:"kinds"`
+	Exchange string   `mapstructure:"exchange"` // Exchange receipts are published to ("" = the delivery exchange)
+}
+
+// PushConfig bounds the service-to-service PushEvent RPC.
+type PushConfig struct {
+	MaxPayloadBytes int `mapstructure:"max_payload_bytes"` // Larger payloads are rejected (0 disables the RPC)
+}
+
+// PresenceConfig drives presence-change publishing to the bus.
+type PresenceConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Grace   time.Duration `mapstructure:"grace"`  // Offline notices wait this long for a reconnect
+	Global  bool          `mapstructure:"global"` // Mirror sessions into the Redis presence registry
+	TTL     time.Duration `mapstructure:"ttl"`    // Lifetime of a node's presence keys without a heartbeat
+}
+
+// SlowConfig drives slow-delivery exemplar capture.
+type SlowConfig struct {
+	ThresholdMs int `mapstructure:"threshold_ms"` // End-to-end latency that qualifies a delivery (0 disables)
+	TopK        int `mapstructure:"top_k"`        // Exemplars kept per kind per minute
+}
+
+// E2EEConfig toggles end-to-end encrypted message relay.
+type E2EEConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// AnalyticsConfig drives sampled analytics export. No rates means no work on the hot path.
+type AnalyticsConfig struct {
+	Rates []SamplingRate `mapstructure:"rates"`
+}
+
+// SamplingRate is the sampled share of one event kind, optionally per domain (0 = any domain).
+type SamplingRate struct {
+	DomainID int64   `mapstructure:"domain_id" json:"domain_id"`
+	Kind     string  `mapstructure:"kind" json:"kind"`
+	Rate     float64 `mapstructure:"rate" json:"rate"` // 0..1
+}
+
+// ReconnectConfig tunes the backoff hints sent on retryable disconnects.
+type ReconnectConfig struct {
+	Capacity   int           `mapstructure:"capacity"`    // Soft user capacity used for load-based widening
+	BaseWindow time.Duration `mapstructure:"base_window"` // Jitter window on an idle node
+	MaxWindow  time.Duration `mapstructure:"max_window"`  // Upper bound after widening
+}
+
+// ImportConfig bounds the bulk migration import path.
+type ImportConfig struct {
+	RatePerSecond int `mapstructure:"rate_per_second"` // Records admitted per second (0 = unbounded)
+	SpillPerUser  int `mapstructure:"spill_per_user"`  // Records kept for an offline user until first connect
+}
+
+// ResidencyConfig restricts which regions may hold sessions for specific domains.
+type ResidencyConfig struct {
+	Domains []DomainResidency `mapstructure:"domains"`
+}
+
+// DomainResidency lists the regions allowed to serve a domain.
+type DomainResidency struct {
+	DomainID int64    `mapstructure:"domain_id"`
+	Regions  []string `mapstructure:"regions"`
+}
+
+// ShapingConfig holds synthetic delivery shaping rules for client UX testing.
+// [STAGING_ONLY] Rules are ignored when service.env is "production".
+type ShapingConfig struct {
+	Rules []ShapingRule `mapstructure:"rules"`
+}
+
+// ShapingRule delays events of one kind (or "*") for one domain (or 0 for all).
+type ShapingRule struct {
+	DomainID       int64   `mapstructure:"domain_id" json:"domain_id"`
+	Kind           string  `mapstructure:"kind" json:"kind"`
+	DelayMs        int     `mapstructure:"delay_ms" json:"delay_ms"`
+	JitterMs       int     `mapstructure:"jitter_ms" json:"jitter_ms"`
+	ReorderMs      int     `mapstructure:"reorder_ms" json:"reorder_ms"`           // Max extra hold so later events can overtake
+	ReorderPercent float64 `mapstructure:"reorder_percent" json:"reorder_percent"` // Share of events held, 0..100
+}
+
+// BufferConfig drives per-connection buffer right-sizing.
+// Table keys are "<transport>" or "<transport>:<platform>" (e.g. "grpc", "grpc_batch", "ws:ios").
+type BufferConfig struct {
+	Min   int            `mapstructure:"min"`
+	Max   int            `mapstructure:"max"`
+	Table map[string]int `mapstructure:"table"`
+}
+
+// DeprecationConfig stages the removal of legacy features.
+type DeprecationConfig struct {
+	Reject      []string      `mapstructure:"reject"`       // Feature names escalated from warn to reject
+	LogInterval time.Duration `mapstructure:"log_interval"` // Minimum gap between warnings per feature
+}
+
+// SigningConfig holds HMAC keys for tokens and webhook signatures.
+// KeyFile (a mounted secret, hot-reloaded) takes precedence over inline keys.
+type SigningConfig struct {
+	ActiveKey string            `mapstructure:"active_key"`
+	Keys      map[string]string `mapstructure:"keys"` // key id -> base64 secret
+	KeyFile   string            `mapstructure:"key_file"`
+}
+
+// reloadHooks run, in registration order, after a changed config file is applied.
+var reloadHooks struct {
+	sync.Mutex
+	fns []func(*Config)
+}
+
+// OnReload registers fn to run after the config file is reloaded and validated.
+// Hooks re-apply settings that are copied out of the Config at startup.
+func OnReload(fn func(*Config)) {
+	reloadHooks.Lock()
+	defer reloadHooks.Unlock()
+	reloadHooks.fns = append(reloadHooks.fns, fn)
+}
+
+func runReloadHooks(cfg *Config) {
+	reloadHooks.Lock()
+	fns := slices.Clone(reloadHooks.fns)
+	reloadHooks.Unlock()
+
+	for _, fn := range fns {
+		fn(cfg)
+	}
 }
 
 func LoadConfig() (*Config, error) {
 	defineFlags()
+	// Subcommand flags (e.g. poison replay --topic) belong to the CLI, not to the config.
+	pflag.CommandLine.ParseErrorsWhitelist.UnknownFlags = true
 	pflag.Parse()
 
 	viper.AutomaticEnv()
@@ -103,6 +416,7 @@ func LoadConfig() (*Config, error) {
 
 			*cfg = *newCfg
 			log.Println("Config reloaded successfully")
+			runReloadHooks(cfg)
 		})
 
 		viper.WatchConfig()
@@ -124,16 +438,81 @@ func defineFlags() {
 
 	pflag.String("service.id", "", "Service ID")
 	pflag.String("service.addr", "localhost:8080", "Service address")
+	pflag.String("service.http_addr", "localhost:8081", "HTTP address of the WS, long-poll and SSE transports (empty disables them)")
+	pflag.String("service.env", EnvProduction, "Deployment environment (production, staging, development)")
+	pflag.String("service.region", "", "Region label of this node (data residency)")
+	pflag.String("service.role", RoleDelivery, "Node role: delivery (serves clients) or observer (consume and export only)")
 
 	pflag.String("log.level", "info", "Log level")
 	pflag.Bool("log.json", false, "Log in JSON format")
 	pflag.String("log.file", "", "Log file path")
 
 	pflag.String("postgres.dsn", "", "Postgres DSN")
+	pflag.Int("enrichment.cache_size", 10000, "Peer profiles kept in the enrichment cache")
+	pflag.Duration("enrichment.cache_ttl", 5*time.Minute, "Lifetime of a cached peer profile")
+	pflag.Duration("enrichment.negative_ttl", 30*time.Second, "Lifetime of a cached failed or empty peer lookup (0 disables)")
+	pflag.Duration("enrichment.contact_timeout", 800*time.Millisecond, "Budget of one contact-service lookup")
+	pflag.Uint32("enrichment.breaker.min_requests", 20, "Contact lookups in the window before the failure rate can trip the breaker")
+	pflag.Float64("enrichment.breaker.failure_ratio", 0.5, "Failing share of contact lookups that opens the breaker")
+	pflag.Duration("enrichment.breaker.window", 10*time.Second, "Rolling window for the contact breaker counts")
+	pflag.Duration("enrichment.breaker.open_timeout", 15*time.Second, "Time the contact breaker stays open before probing")
+	pflag.Uint32("enrichment.breaker.half_open_requests", 3, "Probe lookups allowed while the contact breaker is half-open")
+	pflag.Bool("enrichment.groups", false, "Resolve group and channel peers through im-group-service")
 	pflag.String("redis.addr", "localhost:6379", "Redis address")
 	pflag.String("consul.addr", "localhost:8500", "Consul address")
 	pflag.String("pubsub.broker_url", "", "PubSub broker URL")
 	pflag.String("pubsub.broker_driver", "amqp", "PubSub broker Driver")
+	pflag.Duration("pubsub.publish_timeout", 5*time.Second, "Deadline for a single broker publish")
+	pflag.Duration("pubsub.router_close_timeout", 5*time.Second, "Shutdown wait for in-flight message handlers")
+	pflag.Bool("pubsub.confirm", true, "Wait for broker publisher confirms")
+	pflag.Int("pubsub.publish_retry.attempts", 3, "Publish attempts before an event is dropped (1 disables retries)")
+	pflag.Duration("pubsub.publish_retry.backoff", 100*time.Millisecond, "Pause before the first publish retry; doubled after each")
+	pflag.Duration("pubsub.publish_retry.max_backoff", time.Second, "Longest pause between publish retries")
+	pflag.Int("pubsub.handler.max_retries", 3, "Retries of a failed AMQP handler before the message goes to the poison topic")
+	pflag.Duration("pubsub.handler.initial_interval", 2*time.Second, "Backoff before the first handler retry")
+	pflag.Duration("pubsub.handler.max_interval", 15*time.Second, "Longest backoff between handler retries")
+	pflag.Float64("pubsub.handler.multiplier", 2, "Handler retry backoff growth")
+	pflag.Int64("pubsub.handler.throttle", 100, "Messages per second each AMQP handler processes (0 = unlimited)")
+	pflag.Duration("pubsub.handler.timeout", 30*time.Second, "Deadline of one AMQP handler attempt (0 = none)")
+	pflag.Int("pubsub.poison.max_replays", 3, "Times one poisoned message may be replayed before it stays parked")
+	pflag.Int("pubsub.dedup.size", 10000, "Recently delivered messages remembered to suppress redeliveries (0 disables)")
+	pflag.Duration("pubsub.dedup.ttl", 10*time.Minute, "How long a delivered message is remembered for duplicate suppression")
+	pflag.Int("pubsub.outbox.size", 10000, "Messages buffered while the broker is unavailable (0 disables)")
+	pflag.Duration("pubsub.outbox.flush_timeout", 5*time.Second, "Shutdown wait for the publish outbox to drain")
+	pflag.Int("delivery.buffer.min", 16, "Minimum per-connection buffer size")
+	pflag.Int("delivery.buffer.max", 4096, "Maximum per-connection buffer size")
+	pflag.Int("delivery.import.rate_per_second", 500, "Bulk import throughput cap")
+	pflag.Int("delivery.import.spill_per_user", 200, "Imported records kept per offline user")
+	pflag.Duration("delivery.drain_timeout", 10*time.Second, "Longest shutdown drain before sessions are closed")
+	pflag.Duration("delivery.send_timeout", 5*time.Second, "Deadline for one gRPC stream write before the stream is closed (0 disables)")
+	pflag.Duration("delivery.heartbeat", 30*time.Second, "Idle time before a stream receives a ping event (0 disables)")
+	pflag.String("delivery.event_ids", "random", "Event ID strategy: random, sequential (UUIDv7) or counter")
+	pflag.Int("delivery.pause.max_events_per_domain", 10000, "Events held per paused domain before overflow")
+	pflag.Duration("delivery.pause.max_duration", time.Hour, "Longest maintenance pause a domain may request")
+	pflag.Bool("delivery.presence.enabled", true, "Publish presence changes to the bus")
+	pflag.Duration("delivery.presence.grace", 10*time.Second, "Grace window before an offline presence change is published")
+	pflag.Bool("delivery.presence.global", false, "Maintain cluster-wide presence keys in Redis")
+	pflag.Duration("delivery.presence.ttl", 90*time.Second, "Lifetime of global presence keys; refreshed every ttl/3")
+	pflag.Int("delivery.push.max_payload_bytes", 64<<10, "Largest payload accepted by PushEvent (0 disables it)")
+	pflag.StringSlice("delivery.ws.allowed_origins", nil, "Origins allowed to open WebSockets (exact or *.domain wildcard)")
+	pflag.Bool("delivery.ws.allow_all", false, "Accept WebSockets from any origin (development only)")
+	pflag.Int("delivery.ws.read_buffer_size", 4096, "WebSocket read buffer size")
+	pflag.Int("delivery.ws.write_buffer_size", 4096, "WebSocket write buffer size")
+	pflag.Int("delivery.ws.max_header_bytes", 16<<10, "Largest WebSocket upgrade request header block")
+	pflag.Duration("delivery.lp.wait", 30*time.Second, "Default long-poll wait (?wait= overrides, 1s..90s)")
+	pflag.Int("delivery.lp.limit", 16, "Default events per long-poll response (?limit= overrides, 1..100)")
+	pflag.StringSlice("delivery.receipts.kinds", []string{"MessageCreated"}, "Event kinds acknowledged on the bus once written to a client (empty disables)")
+	pflag.String("delivery.receipts.exchange", "", "Exchange for delivery receipts (empty uses the delivery exchange)")
+	pflag.Int("delivery.slow.threshold_ms", 500, "End-to-end latency that makes a delivery an exemplar candidate")
+	pflag.Int("delivery.slow.top_k", 5, "Slow-delivery exemplars kept per kind per minute")
+	pflag.Bool("delivery.e2ee.enabled", true, "Advertise end-to-end encrypted message relay")
+	pflag.Int("delivery.reconnect.capacity", 50000, "Soft user capacity for reconnect backoff widening")
+	pflag.Duration("delivery.reconnect.base_window", time.Second, "Reconnect jitter window on an idle node")
+	pflag.Duration("delivery.reconnect.max_window", 30*time.Second, "Upper bound of the reconnect jitter window")
+	pflag.StringSlice("deprecation.reject", nil, "Deprecated features to reject instead of warn about")
+	pflag.Duration("deprecation.log_interval", time.Minute, "Minimum gap between warnings for one deprecated feature")
+	pflag.String("signing.key_file", "", "Path to a JSON signing key set (hot-reloaded)")
+	pflag.String("signing.active_key", "", "Active signing key id")
 
 	defineConnectionFlags()
 }
@@ -143,6 +522,12 @@ func (c *Config) validate() error {
 		return fmt.Errorf("config: service.id is required (use --service.id or SERVICE_ID env)")
 	}
 
+	switch c.Service.Role {
+	case "", RoleDelivery, RoleObserver:
+	default:
+		return fmt.Errorf("config: service.role must be %s or %s", RoleDelivery, RoleObserver)
+	}
+
 	if c.Service.Address == "" {
 		return fmt.Errorf("config: service.addr is required")
 	}
@@ -176,6 +561,44 @@ func (c *Config) validate() error {
 		return fmt.Errorf("config: pubsub.broker_url must start with amqp:// or amqps://")
 	}
 
+	if c.Delivery.Buffer.Min > c.Delivery.Buffer.Max {
+		return fmt.Errorf("config: delivery.buffer.min must not exceed delivery.buffer.max")
+	}
+
+	for _, r := range c.Delivery.Shaping.Rules {
+		if r.DelayMs < 0 || r.JitterMs < 0 || r.ReorderMs < 0 || r.ReorderPercent < 0 || r.ReorderPercent > 100 {
+			return fmt.Errorf("config: invalid delivery.shaping rule for kind %q", r.Kind)
+		}
+	}
+
+	if c.Pubsub.Handler.MaxRetries < 0 || c.Pubsub.Handler.Multiplier < 1 {
+		return fmt.Errorf("config: pubsub.handler needs max_retries >= 0 and multiplier >= 1")
+	}
+
+	if c.Pubsub.PublishRetry.Attempts < 1 {
+		return fmt.Errorf("config: pubsub.publish_retry.attempts must be at least 1")
+	}
+
+	if c.Pubsub.Dedup.Size > 0 && c.Pubsub.Dedup.TTL <= 0 {
+		return fmt.Errorf("config: pubsub.dedup.ttl must be positive when pubsub.dedup.size is set")
+	}
+
+	if c.Pubsub.Poison.MaxReplays < 0 {
+		return fmt.Errorf("config: pubsub.poison.max_replays must not be negative")
+	}
+
+	switch c.Delivery.EventIDs {
+	case "", "random", "sequential", "counter":
+	default:
+		return fmt.Errorf("config: delivery.event_ids must be random, sequential or counter")
+	}
+
+	for _, r := range c.Delivery.Analytics.Rates {
+		if r.Rate < 0 || r.Rate > 1 {
+			return fmt.Errorf("config: delivery.analytics rate for kind %q must be within 0..1", r.Kind)
+		}
+	}
+
 	return nil
 }
 
@@ -204,3 +627,6 @@ func defineConnectionFlags() error {
 	pflag.String("service.conn.client.cert", "", "Client certificate path")
 	return nil
 }
+
+// IsObserver reports whether the node runs without the Hub and client transports.
+func (c *Config) IsObserver() bool { return c.Service.Role == RoleObserver }
@@ -11,6 +11,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	anypb "google.golang.org/protobuf/types/known/anypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -139,6 +140,177 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{1}
 }
 
+// PushOutcome is the fate of a pushed event on the serving node.
+type PushOutcome int32
+
+const (
+	// Unspecified outcome.
+	PushOutcome_PUSH_OUTCOME_UNSPECIFIED PushOutcome = 0
+	// Queued in the user's mailbox.
+	PushOutcome_PUSH_ACCEPTED PushOutcome = 1
+	// The mailbox was full; nothing was queued.
+	PushOutcome_PUSH_DROPPED PushOutcome = 2
+	// The user holds no session on this node.
+	PushOutcome_PUSH_NOT_CONNECTED PushOutcome = 3
+)
+
+// Enum value maps for PushOutcome.
+var (
+	PushOutcome_name = map[int32]string{
+		0: "PUSH_OUTCOME_UNSPECIFIED",
+		1: "PUSH_ACCEPTED",
+		2: "PUSH_DROPPED",
+		3: "PUSH_NOT_CONNECTED",
+	}
+	PushOutcome_value = map[string]int32{
+		"PUSH_OUTCOME_UNSPECIFIED": 0,
+		"PUSH_ACCEPTED":            1,
+		"PUSH_DROPPED":             2,
+		"PUSH_NOT_CONNECTED":       3,
+	}
+)
+
+func (x PushOutcome) Enum() *PushOutcome {
+	p := new(PushOutcome)
+	*p = x
+	return p
+}
+
+func (x PushOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PushOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_delivery_v1_delivery_proto_enumTypes[2].Descriptor()
+}
+
+func (PushOutcome) Type() protoreflect.EnumType {
+	return &file_api_delivery_v1_delivery_proto_enumTypes[2]
+}
+
+func (x PushOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PushOutcome.Descriptor instead.
+func (PushOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{2}
+}
+
+// DeprecationMode is the escalation level of a deprecated feature.
+type DeprecationMode int32
+
+const (
+	// Unspecified mode.
+	DeprecationMode_DEPRECATION_MODE_UNSPECIFIED DeprecationMode = 0
+	// Uses are counted and logged.
+	DeprecationMode_DEPRECATION_WARN DeprecationMode = 1
+	// Uses are counted and refused.
+	DeprecationMode_DEPRECATION_REJECT DeprecationMode = 2
+)
+
+// Enum value maps for DeprecationMode.
+var (
+	DeprecationMode_name = map[int32]string{
+		0: "DEPRECATION_MODE_UNSPECIFIED",
+		1: "DEPRECATION_WARN",
+		2: "DEPRECATION_REJECT",
+	}
+	DeprecationMode_value = map[string]int32{
+		"DEPRECATION_MODE_UNSPECIFIED": 0,
+		"DEPRECATION_WARN":             1,
+		"DEPRECATION_REJECT":           2,
+	}
+)
+
+func (x DeprecationMode) Enum() *DeprecationMode {
+	p := new(DeprecationMode)
+	*p = x
+	return p
+}
+
+func (x DeprecationMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeprecationMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_delivery_v1_delivery_proto_enumTypes[3].Descriptor()
+}
+
+func (DeprecationMode) Type() protoreflect.EnumType {
+	return &file_api_delivery_v1_delivery_proto_enumTypes[3]
+}
+
+func (x DeprecationMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeprecationMode.Descriptor instead.
+func (DeprecationMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{3}
+}
+
+// PresenceStatus is a contact's user-facing availability.
+type PresenceStatus int32
+
+const (
+	// Unspecified or unknown availability.
+	PresenceStatus_PRESENCE_STATUS_UNSPECIFIED PresenceStatus = 0
+	// The contact is online.
+	PresenceStatus_PRESENCE_ONLINE PresenceStatus = 1
+	// The contact is idle.
+	PresenceStatus_PRESENCE_AWAY PresenceStatus = 2
+	// The contact asked not to be disturbed.
+	PresenceStatus_PRESENCE_DND PresenceStatus = 3
+	// The contact is offline.
+	PresenceStatus_PRESENCE_OFFLINE PresenceStatus = 4
+)
+
+// Enum value maps for PresenceStatus.
+var (
+	PresenceStatus_name = map[int32]string{
+		0: "PRESENCE_STATUS_UNSPECIFIED",
+		1: "PRESENCE_ONLINE",
+		2: "PRESENCE_AWAY",
+		3: "PRESENCE_DND",
+		4: "PRESENCE_OFFLINE",
+	}
+	PresenceStatus_value = map[string]int32{
+		"PRESENCE_STATUS_UNSPECIFIED": 0,
+		"PRESENCE_ONLINE":             1,
+		"PRESENCE_AWAY":               2,
+		"PRESENCE_DND":                3,
+		"PRESENCE_OFFLINE":            4,
+	}
+)
+
+func (x PresenceStatus) Enum() *PresenceStatus {
+	p := new(PresenceStatus)
+	*p = x
+	return p
+}
+
+func (x PresenceStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PresenceStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_delivery_v1_delivery_proto_enumTypes[4].Descriptor()
+}
+
+func (PresenceStatus) Type() protoreflect.EnumType {
+	return &file_api_delivery_v1_delivery_proto_enumTypes[4]
+}
+
+func (x PresenceStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PresenceStatus.Descriptor instead.
+func (PresenceStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{4}
+}
+
 // EventPriority defines how urgently the client should handle the incoming event.
 type EventPriority int32
 
@@ -180,11 +352,11 @@ func (x EventPriority) String() string {
 }
 
 func (EventPriority) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_delivery_v1_delivery_proto_enumTypes[2].Descriptor()
+	return file_api_delivery_v1_delivery_proto_enumTypes[5].Descriptor()
 }
 
 func (EventPriority) Type() protoreflect.EnumType {
-	return &file_api_delivery_v1_delivery_proto_enumTypes[2]
+	return &file_api_delivery_v1_delivery_proto_enumTypes[5]
 }
 
 func (x EventPriority) Number() protoreflect.EnumNumber {
@@ -193,7 +365,81 @@ func (x EventPriority) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use EventPriority.Descriptor instead.
 func (EventPriority) EnumDescriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{2}
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{5}
+}
+
+// EventKind names the event categories a stream can limit itself to. Session signals
+// (connected, disconnected, ping, replay gap, pauses, degradation) are always sent.
+type EventKind int32
+
+const (
+	// Unspecified kind; ignored in filters.
+	EventKind_EVENT_KIND_UNSPECIFIED EventKind = 0
+	// New messages, including end-to-end encrypted ones.
+	EventKind_EVENT_KIND_MESSAGE_CREATED EventKind = 1
+	// Message edits.
+	EventKind_EVENT_KIND_MESSAGE_UPDATED EventKind = 2
+	// Message deletions.
+	EventKind_EVENT_KIND_MESSAGE_DELETED EventKind = 3
+	// Typing indicators.
+	EventKind_EVENT_KIND_TYPING EventKind = 4
+	// Read receipts.
+	EventKind_EVENT_KIND_READ_RECEIPT EventKind = 5
+	// Message reactions.
+	EventKind_EVENT_KIND_REACTION EventKind = 6
+	// Status changes of watched contacts.
+	EventKind_EVENT_KIND_PRESENCE_STATUS EventKind = 7
+)
+
+// Enum value maps for EventKind.
+var (
+	EventKind_name = map[int32]string{
+		0: "EVENT_KIND_UNSPECIFIED",
+		1: "EVENT_KIND_MESSAGE_CREATED",
+		2: "EVENT_KIND_MESSAGE_UPDATED",
+		3: "EVENT_KIND_MESSAGE_DELETED",
+		4: "EVENT_KIND_TYPING",
+		5: "EVENT_KIND_READ_RECEIPT",
+		6: "EVENT_KIND_REACTION",
+		7: "EVENT_KIND_PRESENCE_STATUS",
+	}
+	EventKind_value = map[string]int32{
+		"EVENT_KIND_UNSPECIFIED":     0,
+		"EVENT_KIND_MESSAGE_CREATED": 1,
+		"EVENT_KIND_MESSAGE_UPDATED": 2,
+		"EVENT_KIND_MESSAGE_DELETED": 3,
+		"EVENT_KIND_TYPING":          4,
+		"EVENT_KIND_READ_RECEIPT":    5,
+		"EVENT_KIND_REACTION":        6,
+		"EVENT_KIND_PRESENCE_STATUS": 7,
+	}
+)
+
+func (x EventKind) Enum() *EventKind {
+	p := new(EventKind)
+	*p = x
+	return p
+}
+
+func (x EventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_delivery_v1_delivery_proto_enumTypes[6].Descriptor()
+}
+
+func (EventKind) Type() protoreflect.EnumType {
+	return &file_api_delivery_v1_delivery_proto_enumTypes[6]
+}
+
+func (x EventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventKind.Descriptor instead.
+func (EventKind) EnumDescriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{6}
 }
 
 // StreamRequest defines the subscription parameters for the event stream.
@@ -201,6 +447,25 @@ type StreamRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// ID of the last event received before reconnecting. Retained events after it are
+	// replayed ahead of the live feed; a ReplayGapEvent is sent if it has aged out.
+	LastEventId string `protobuf:"bytes,1,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	// Timestamp of that event (Unix epoch in milliseconds). Locates the resume position
+	// when last_event_id is empty or no longer in the replay buffer.
+	LastEventTs int64 `protobuf:"varint,2,opt,name=last_event_ts,json=lastEventTs,proto3" json:"last_event_ts,omitempty"`
+	// Contact IDs whose status changes the stream receives as PresenceStatusEvents.
+	// IDs that are not UUIDs are ignored.
+	WatchPresence []string `protobuf:"bytes,3,rep,name=watch_presence,json=watchPresence,proto3" json:"watch_presence,omitempty"`
+	// Event kinds the stream receives; empty means all. Unspecified values are ignored.
+	EventKinds []EventKind `protobuf:"varint,4,rep,packed,name=event_kinds,json=eventKinds,proto3,enum=webitel.im.api.delivery.v1.EventKind" json:"event_kinds,omitempty"`
+	// Client platform (e.g. "ios", "android", "web"), shown to support tooling.
+	Platform string `protobuf:"bytes,5,opt,name=platform,proto3" json:"platform,omitempty"`
+	// Client application version.
+	AppVersion string `protobuf:"bytes,6,opt,name=app_version,json=appVersion,proto3" json:"app_version,omitempty"`
+	// Stable ID of the client device. A new stream from the same device supersedes
+	// the older one.
+	DeviceId string `protobuf:"bytes,7,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
 }
 
 func (x *StreamRequest) Reset() {
@@ -235,33 +500,67 @@ func (*StreamRequest) Descriptor() ([]byte, []int) {
 	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{0}
 }
 
-// ServerEvent is a container for all types of data sent over the delivery stream.
-type ServerEvent struct {
+func (x *StreamRequest) GetLastEventId() string {
+	if x != nil {
+		return x.LastEventId
+	}
+	return ""
+}
+
+func (x *StreamRequest) GetLastEventTs() int64 {
+	if x != nil {
+		return x.LastEventTs
+	}
+	return 0
+}
+
+func (x *StreamRequest) GetWatchPresence() []string {
+	if x != nil {
+		return x.WatchPresence
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetEventKinds() []EventKind {
+	if x != nil {
+		return x.EventKinds
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *StreamRequest) GetAppVersion() string {
+	if x != nil {
+		return x.AppVersion
+	}
+	return ""
+}
+
+func (x *StreamRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+// ListConnectionsRequest selects the user whose sessions are listed.
+type ListConnectionsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Unique identifier for the event. Used for logging, tracing, and deduplication.
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// Timestamp of event generation (Unix epoch in milliseconds).
-	CreatedAt int64 `protobuf:"varint,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	// Relative importance of the event for client-side processing priority.
-	Priority EventPriority `protobuf:"varint,3,opt,name=priority,proto3,enum=webitel.im.api.delivery.v1.EventPriority" json:"priority,omitempty"`
-	// Payload contains the specific event data.
-	//
-	// Types that are assignable to Payload:
-	//
-	//	*ServerEvent_ConnectedEvent
-	//	*ServerEvent_DisconnectedEvent
-	//	*ServerEvent_MessageEvent
-	//	*ServerEvent_AckEvent
-	//	*ServerEvent_ErrorEvent
-	//	*ServerEvent_PingEvent
-	Payload isServerEvent_Payload `protobuf_oneof:"payload"`
+	// User to describe; empty means the caller.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 }
 
-func (x *ServerEvent) Reset() {
-	*x = ServerEvent{}
+func (x *ListConnectionsRequest) Reset() {
+	*x = ListConnectionsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_delivery_v1_delivery_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -269,13 +568,13 @@ func (x *ServerEvent) Reset() {
 	}
 }
 
-func (x *ServerEvent) String() string {
+func (x *ListConnectionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServerEvent) ProtoMessage() {}
+func (*ListConnectionsRequest) ProtoMessage() {}
 
-func (x *ServerEvent) ProtoReflect() protoreflect.Message {
+func (x *ListConnectionsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_delivery_v1_delivery_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -287,158 +586,190 @@ func (x *ServerEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServerEvent.ProtoReflect.Descriptor instead.
-func (*ServerEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListConnectionsRequest.ProtoReflect.Descriptor instead.
+func (*ListConnectionsRequest) Descriptor() ([]byte, []int) {
 	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *ServerEvent) GetId() string {
+func (x *ListConnectionsRequest) GetUserId() string {
 	if x != nil {
-		return x.Id
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *ServerEvent) GetCreatedAt() int64 {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return 0
+// ListConnectionsResponse lists the user's sessions on the serving node, oldest first.
+type ListConnectionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Connections []*Connection `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+	// Node that answered; sessions on other nodes are not included.
+	NodeId string `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 }
 
-func (x *ServerEvent) GetPriority() EventPriority {
-	if x != nil {
-		return x.Priority
+func (x *ListConnectionsResponse) Reset() {
+	*x = ListConnectionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return EventPriority_PRIORITY_UNSPECIFIED
 }
 
-func (m *ServerEvent) GetPayload() isServerEvent_Payload {
-	if m != nil {
-		return m.Payload
-	}
-	return nil
+func (x *ListConnectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ServerEvent) GetConnectedEvent() *ConnectedEvent {
-	if x, ok := x.GetPayload().(*ServerEvent_ConnectedEvent); ok {
-		return x.ConnectedEvent
+func (*ListConnectionsResponse) ProtoMessage() {}
+
+func (x *ListConnectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ServerEvent) GetDisconnectedEvent() *DisconnectedEvent {
-	if x, ok := x.GetPayload().(*ServerEvent_DisconnectedEvent); ok {
-		return x.DisconnectedEvent
-	}
-	return nil
+// Deprecated: Use ListConnectionsResponse.ProtoReflect.Descriptor instead.
+func (*ListConnectionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *ServerEvent) GetMessageEvent() *NewMessageEvent {
-	if x, ok := x.GetPayload().(*ServerEvent_MessageEvent); ok {
-		return x.MessageEvent
+func (x *ListConnectionsResponse) GetConnections() []*Connection {
+	if x != nil {
+		return x.Connections
 	}
 	return nil
 }
 
-func (x *ServerEvent) GetAckEvent() *AckEvent {
-	if x, ok := x.GetPayload().(*ServerEvent_AckEvent); ok {
-		return x.AckEvent
+func (x *ListConnectionsResponse) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
 	}
-	return nil
+	return ""
 }
 
-func (x *ServerEvent) GetErrorEvent() *ErrorEvent {
-	if x, ok := x.GetPayload().(*ServerEvent_ErrorEvent); ok {
-		return x.ErrorEvent
-	}
-	return nil
+// Connection is a point-in-time description of one attached session.
+type Connection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Connection ID, as sent in ConnectedEvent.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Attach time (Unix ms).
+	CreatedAt int64 `protobuf:"varint,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Last write to the client (Unix ms); 0 when the transport does not report it.
+	LastActivity int64 `protobuf:"varint,3,opt,name=last_activity,json=lastActivity,proto3" json:"last_activity,omitempty"`
+	// Events shed on this session so far.
+	Dropped uint64 `protobuf:"varint,4,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	// Client description captured at subscribe time.
+	Metadata *ConnectionMetadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
-func (x *ServerEvent) GetPingEvent() *PingEvent {
-	if x, ok := x.GetPayload().(*ServerEvent_PingEvent); ok {
-		return x.PingEvent
+func (x *Connection) Reset() {
+	*x = Connection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-type isServerEvent_Payload interface {
-	isServerEvent_Payload()
+func (x *Connection) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type ServerEvent_ConnectedEvent struct {
-	// Confirmation of successful stream establishment.
-	ConnectedEvent *ConnectedEvent `protobuf:"bytes,4,opt,name=connected_event,json=connectedEvent,proto3,oneof"`
-}
+func (*Connection) ProtoMessage() {}
 
-type ServerEvent_DisconnectedEvent struct {
-	// Notification sent before the server closes the stream.
-	DisconnectedEvent *DisconnectedEvent `protobuf:"bytes,5,opt,name=disconnected_event,json=disconnectedEvent,proto3,oneof"`
+func (x *Connection) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type ServerEvent_MessageEvent struct {
-	// A new incoming message from a chat/thread.
-	MessageEvent *NewMessageEvent `protobuf:"bytes,6,opt,name=message_event,json=messageEvent,proto3,oneof"`
+// Deprecated: Use Connection.ProtoReflect.Descriptor instead.
+func (*Connection) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{3}
 }
 
-type ServerEvent_AckEvent struct {
-	// Acknowledgement of a previously sent message or processed action.
-	AckEvent *AckEvent `protobuf:"bytes,7,opt,name=ack_event,json=ackEvent,proto3,oneof"`
+func (x *Connection) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-type ServerEvent_ErrorEvent struct {
-	// Notification about a stream-level or business-logic error.
-	ErrorEvent *ErrorEvent `protobuf:"bytes,8,opt,name=error_event,json=errorEvent,proto3,oneof"`
+func (x *Connection) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
 }
 
-type ServerEvent_PingEvent struct {
-	// Heartbeat signal to maintain connection and detect "half-open" sockets.
-	PingEvent *PingEvent `protobuf:"bytes,9,opt,name=ping_event,json=pingEvent,proto3,oneof"`
+func (x *Connection) GetLastActivity() int64 {
+	if x != nil {
+		return x.LastActivity
+	}
+	return 0
 }
 
-func (*ServerEvent_ConnectedEvent) isServerEvent_Payload() {}
-
-func (*ServerEvent_DisconnectedEvent) isServerEvent_Payload() {}
-
-func (*ServerEvent_MessageEvent) isServerEvent_Payload() {}
-
-func (*ServerEvent_AckEvent) isServerEvent_Payload() {}
-
-func (*ServerEvent_ErrorEvent) isServerEvent_Payload() {}
+func (x *Connection) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
 
-func (*ServerEvent_PingEvent) isServerEvent_Payload() {}
+func (x *Connection) GetMetadata() *ConnectionMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
 
-// ConnectedEvent is the first message sent by the server after the stream is opened.
-type ConnectedEvent struct {
+// ConnectionMetadata describes the client behind a session.
+type ConnectionMetadata struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Status of the stream initialization.
-	Ok bool `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
-	// Unique session/connection ID assigned by the server for this stream.
-	ConnectionId string `protobuf:"bytes,2,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
-	// Current server version. Useful for feature detection and troubleshooting.
-	ServerVersion string `protobuf:"bytes,3,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`
+	Platform  string `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Version   string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	DeviceId  string `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	RemoteIp  string `protobuf:"bytes,4,opt,name=remote_ip,json=remoteIp,proto3" json:"remote_ip,omitempty"`
+	UserAgent string `protobuf:"bytes,5,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
 }
 
-func (x *ConnectedEvent) Reset() {
-	*x = ConnectedEvent{}
+func (x *ConnectionMetadata) Reset() {
+	*x = ConnectionMetadata{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[2]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ConnectedEvent) String() string {
+func (x *ConnectionMetadata) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConnectedEvent) ProtoMessage() {}
+func (*ConnectionMetadata) ProtoMessage() {}
 
-func (x *ConnectedEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[2]
+func (x *ConnectionMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -449,62 +780,74 @@ func (x *ConnectedEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConnectedEvent.ProtoReflect.Descriptor instead.
-func (*ConnectedEvent) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ConnectionMetadata.ProtoReflect.Descriptor instead.
+func (*ConnectionMetadata) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ConnectedEvent) GetOk() bool {
+func (x *ConnectionMetadata) GetPlatform() string {
 	if x != nil {
-		return x.Ok
+		return x.Platform
 	}
-	return false
+	return ""
 }
 
-func (x *ConnectedEvent) GetConnectionId() string {
+func (x *ConnectionMetadata) GetVersion() string {
 	if x != nil {
-		return x.ConnectionId
+		return x.Version
 	}
 	return ""
 }
 
-func (x *ConnectedEvent) GetServerVersion() string {
+func (x *ConnectionMetadata) GetDeviceId() string {
 	if x != nil {
-		return x.ServerVersion
+		return x.DeviceId
 	}
 	return ""
 }
 
-// DisconnectedEvent is sent by the server just before forcefully closing the stream.
-type DisconnectedEvent struct {
+func (x *ConnectionMetadata) GetRemoteIp() string {
+	if x != nil {
+		return x.RemoteIp
+	}
+	return ""
+}
+
+func (x *ConnectionMetadata) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+// GetDeliveryStatusRequest names the message to trace.
+type GetDeliveryStatusRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Human-readable explanation of why the connection is being closed.
-	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
-	// Machine-readable code to help the client decide on reconnection strategy.
-	// Examples: "SESSION_EVICTED", "SERVER_SHUTDOWN", "INACTIVITY", "AUTH_EXPIRED".
-	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	MessageId string `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Ask every delivery node, not only the serving one.
+	Cluster bool `protobuf:"varint,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
 }
 
-func (x *DisconnectedEvent) Reset() {
-	*x = DisconnectedEvent{}
+func (x *GetDeliveryStatusRequest) Reset() {
+	*x = GetDeliveryStatusRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[3]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DisconnectedEvent) String() string {
+func (x *GetDeliveryStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DisconnectedEvent) ProtoMessage() {}
+func (*GetDeliveryStatusRequest) ProtoMessage() {}
 
-func (x *DisconnectedEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[3]
+func (x *GetDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -515,52 +858,63 @@ func (x *DisconnectedEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DisconnectedEvent.ProtoReflect.Descriptor instead.
-func (*DisconnectedEvent) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use GetDeliveryStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *DisconnectedEvent) GetReason() string {
+func (x *GetDeliveryStatusRequest) GetMessageId() string {
 	if x != nil {
-		return x.Reason
+		return x.MessageId
 	}
 	return ""
 }
 
-func (x *DisconnectedEvent) GetCode() string {
+func (x *GetDeliveryStatusRequest) GetCluster() bool {
 	if x != nil {
-		return x.Code
+		return x.Cluster
 	}
-	return ""
+	return false
 }
 
-// NewMessageEvent contains a message that needs to be delivered to the client.
-type NewMessageEvent struct {
+// DeliveryStatus is the merged delivery timeline of one message.
+type DeliveryStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The actual message object with content, metadata, and sender info.
-	Message *ThreadMessage `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-}
-
-func (x *NewMessageEvent) Reset() {
-	*x = NewMessageEvent{}
+	MessageId string `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Observed steps, oldest first.
+	Timeline []*DeliveryStep `protobuf:"bytes,2,rep,name=timeline,proto3" json:"timeline,omitempty"`
+	// Some node stopped recording steps for this message after its per-message limit.
+	Truncated bool `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	// Caveats on how far the timeline can be trusted.
+	Staleness string `protobuf:"bytes,4,opt,name=staleness,proto3" json:"staleness,omitempty"`
+	// Time the status was assembled (Unix ms).
+	ObservedAt int64 `protobuf:"varint,5,opt,name=observed_at,json=observedAt,proto3" json:"observed_at,omitempty"`
+	// Nodes that answered.
+	Nodes []string `protobuf:"bytes,6,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	// Nodes that could not be asked; their steps are missing. "*" when the node list was unavailable.
+	UnreachableNodes []string `protobuf:"bytes,7,rep,name=unreachable_nodes,json=unreachableNodes,proto3" json:"unreachable_nodes,omitempty"`
+}
+
+func (x *DeliveryStatus) Reset() {
+	*x = DeliveryStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[4]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *NewMessageEvent) String() string {
+func (x *DeliveryStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewMessageEvent) ProtoMessage() {}
+func (*DeliveryStatus) ProtoMessage() {}
 
-func (x *NewMessageEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[4]
+func (x *DeliveryStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,55 +925,99 @@ func (x *NewMessageEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewMessageEvent.ProtoReflect.Descriptor instead.
-func (*NewMessageEvent) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use DeliveryStatus.ProtoReflect.Descriptor instead.
+func (*DeliveryStatus) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *NewMessageEvent) GetMessage() *ThreadMessage {
+func (x *DeliveryStatus) GetMessageId() string {
 	if x != nil {
-		return x.Message
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *DeliveryStatus) GetTimeline() []*DeliveryStep {
+	if x != nil {
+		return x.Timeline
 	}
 	return nil
 }
 
-type ThreadMessage struct {
+func (x *DeliveryStatus) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *DeliveryStatus) GetStaleness() string {
+	if x != nil {
+		return x.Staleness
+	}
+	return ""
+}
+
+func (x *DeliveryStatus) GetObservedAt() int64 {
+	if x != nil {
+		return x.ObservedAt
+	}
+	return 0
+}
+
+func (x *DeliveryStatus) GetNodes() []string {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *DeliveryStatus) GetUnreachableNodes() []string {
+	if x != nil {
+		return x.UnreachableNodes
+	}
+	return nil
+}
+
+// DeliveryStep is one observed outcome of a message on one node.
+type DeliveryStep struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id        string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ThreadId  string      `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	From      *Peer       `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
-	To        *Peer       `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
-	CreatedAt int64       `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	EditedAt  int64       `protobuf:"varint,6,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
-	Text      string      `protobuf:"bytes,7,opt,name=text,proto3" json:"text,omitempty"`
-	Type      MessageType `protobuf:"varint,8,opt,name=type,proto3,enum=webitel.im.api.delivery.v1.MessageType" json:"type,omitempty"`
-	// Types that are assignable to Content:
-	//
-	//	*ThreadMessage_Document
-	//	*ThreadMessage_Image
-	Content isThreadMessage_Content `protobuf_oneof:"content"`
-}
-
-func (x *ThreadMessage) Reset() {
-	*x = ThreadMessage{}
+	// Recipient of this leg.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Session the step concerns; empty for mailbox-level steps.
+	ConnectionId string `protobuf:"bytes,2,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	// ID of the delivered event.
+	EventId string `protobuf:"bytes,3,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Event kind, e.g. "MessageCreated".
+	Kind string `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Outcome, e.g. "Delivered", "Dropped", "Skipped".
+	Outcome string `protobuf:"bytes,5,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// Time of the step (Unix ms).
+	At int64 `protobuf:"varint,6,opt,name=at,proto3" json:"at,omitempty"`
+	// Node that observed the step.
+	NodeId string `protobuf:"bytes,7,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+}
+
+func (x *DeliveryStep) Reset() {
+	*x = DeliveryStep{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[5]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ThreadMessage) String() string {
+func (x *DeliveryStep) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ThreadMessage) ProtoMessage() {}
+func (*DeliveryStep) ProtoMessage() {}
 
-func (x *ThreadMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[5]
+func (x *DeliveryStep) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,130 +1028,153 @@ func (x *ThreadMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ThreadMessage.ProtoReflect.Descriptor instead.
-func (*ThreadMessage) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use DeliveryStep.ProtoReflect.Descriptor instead.
+func (*DeliveryStep) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ThreadMessage) GetId() string {
+func (x *DeliveryStep) GetUserId() string {
 	if x != nil {
-		return x.Id
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *ThreadMessage) GetThreadId() string {
+func (x *DeliveryStep) GetConnectionId() string {
 	if x != nil {
-		return x.ThreadId
+		return x.ConnectionId
 	}
 	return ""
 }
 
-func (x *ThreadMessage) GetFrom() *Peer {
+func (x *DeliveryStep) GetEventId() string {
 	if x != nil {
-		return x.From
+		return x.EventId
 	}
-	return nil
+	return ""
 }
 
-func (x *ThreadMessage) GetTo() *Peer {
+func (x *DeliveryStep) GetKind() string {
 	if x != nil {
-		return x.To
+		return x.Kind
 	}
-	return nil
+	return ""
 }
 
-func (x *ThreadMessage) GetCreatedAt() int64 {
+func (x *DeliveryStep) GetOutcome() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Outcome
 	}
-	return 0
+	return ""
 }
 
-func (x *ThreadMessage) GetEditedAt() int64 {
+func (x *DeliveryStep) GetAt() int64 {
 	if x != nil {
-		return x.EditedAt
+		return x.At
 	}
 	return 0
 }
 
-func (x *ThreadMessage) GetText() string {
+func (x *DeliveryStep) GetNodeId() string {
 	if x != nil {
-		return x.Text
+		return x.NodeId
 	}
 	return ""
 }
 
-func (x *ThreadMessage) GetType() MessageType {
-	if x != nil {
-		return x.Type
-	}
-	return MessageType_UNSPECIFIED_MESSAGE_TYPE
+// DisconnectRequest selects the sessions to close.
+type DisconnectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Owner of the sessions.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Session to close; empty closes every session of the user on the serving node.
+	ConnectionId string `protobuf:"bytes,2,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	// Sent to the client in DisconnectedEvent.reason; defaults to "disconnected_by_operator".
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-func (m *ThreadMessage) GetContent() isThreadMessage_Content {
-	if m != nil {
-		return m.Content
+func (x *DisconnectRequest) Reset() {
+	*x = DisconnectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *ThreadMessage) GetDocument() *Document {
-	if x, ok := x.GetContent().(*ThreadMessage_Document); ok {
-		return x.Document
-	}
-	return nil
+func (x *DisconnectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ThreadMessage) GetImage() *Image {
-	if x, ok := x.GetContent().(*ThreadMessage_Image); ok {
-		return x.Image
+func (*DisconnectRequest) ProtoMessage() {}
+
+func (x *DisconnectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type isThreadMessage_Content interface {
-	isThreadMessage_Content()
+// Deprecated: Use DisconnectRequest.ProtoReflect.Descriptor instead.
+func (*DisconnectRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{8}
 }
 
-type ThreadMessage_Document struct {
-	Document *Document `protobuf:"bytes,9,opt,name=document,proto3,oneof"`
+func (x *DisconnectRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-type ThreadMessage_Image struct {
-	Image *Image `protobuf:"bytes,10,opt,name=image,proto3,oneof"`
+func (x *DisconnectRequest) GetConnectionId() string {
+	if x != nil {
+		return x.ConnectionId
+	}
+	return ""
 }
 
-func (*ThreadMessage_Document) isThreadMessage_Content() {}
-
-func (*ThreadMessage_Image) isThreadMessage_Content() {}
+func (x *DisconnectRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
 
-type Identity struct {
+// DisconnectResponse reports how many sessions were closed.
+type DisconnectResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Issuer string `protobuf:"bytes,1,opt,name=issuer,proto3" json:"issuer,omitempty"`
-	Name   string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Closed int32 `protobuf:"varint,1,opt,name=closed,proto3" json:"closed,omitempty"`
 }
 
-func (x *Identity) Reset() {
-	*x = Identity{}
+func (x *DisconnectResponse) Reset() {
+	*x = DisconnectResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[6]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Identity) String() string {
+func (x *DisconnectResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Identity) ProtoMessage() {}
+func (*DisconnectResponse) ProtoMessage() {}
 
-func (x *Identity) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[6]
+func (x *DisconnectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -764,60 +1185,44 @@ func (x *Identity) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Identity.ProtoReflect.Descriptor instead.
-func (*Identity) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *Identity) GetIssuer() string {
-	if x != nil {
-		return x.Issuer
-	}
-	return ""
+// Deprecated: Use DisconnectResponse.ProtoReflect.Descriptor instead.
+func (*DisconnectResponse) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *Identity) GetName() string {
+func (x *DisconnectResponse) GetClosed() int32 {
 	if x != nil {
-		return x.Name
+		return x.Closed
 	}
-	return ""
+	return 0
 }
 
-type Peer struct {
+// IsOnlineRequest names the user to look up.
+type IsOnlineRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Addressing: mandatory routing identifiers
-	//
-	// Types that are assignable to Kind:
-	//
-	//	*Peer_UserId
-	//	*Peer_ChatId
-	//	*Peer_ChannelId
-	//	*Peer_BotId
-	Kind isPeer_Kind `protobuf_oneof:"kind"`
-	// Identity: enriched user data
-	Identity *Identity `protobuf:"bytes,10,opt,name=identity,proto3" json:"identity,omitempty"`
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 }
 
-func (x *Peer) Reset() {
-	*x = Peer{}
+func (x *IsOnlineRequest) Reset() {
+	*x = IsOnlineRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[7]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Peer) String() string {
+func (x *IsOnlineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Peer) ProtoMessage() {}
+func (*IsOnlineRequest) ProtoMessage() {}
 
-func (x *Peer) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[7]
+func (x *IsOnlineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -828,110 +1233,146 @@ func (x *Peer) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Peer.ProtoReflect.Descriptor instead.
-func (*Peer) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{7}
-}
-
-func (m *Peer) GetKind() isPeer_Kind {
-	if m != nil {
-		return m.Kind
-	}
-	return nil
+// Deprecated: Use IsOnlineRequest.ProtoReflect.Descriptor instead.
+func (*IsOnlineRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *Peer) GetUserId() string {
-	if x, ok := x.GetKind().(*Peer_UserId); ok {
+func (x *IsOnlineRequest) GetUserId() string {
+	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *Peer) GetChatId() string {
-	if x, ok := x.GetKind().(*Peer_ChatId); ok {
-		return x.ChatId
-	}
-	return ""
+// CheckPresenceRequest names up to 500 users to look up.
+type CheckPresenceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserIds []string `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
 }
 
-func (x *Peer) GetChannelId() string {
-	if x, ok := x.GetKind().(*Peer_ChannelId); ok {
-		return x.ChannelId
+func (x *CheckPresenceRequest) Reset() {
+	*x = CheckPresenceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *Peer) GetBotId() string {
-	if x, ok := x.GetKind().(*Peer_BotId); ok {
-		return x.BotId
-	}
-	return ""
+func (x *CheckPresenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Peer) GetIdentity() *Identity {
-	if x != nil {
-		return x.Identity
+func (*CheckPresenceRequest) ProtoMessage() {}
+
+func (x *CheckPresenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type isPeer_Kind interface {
-	isPeer_Kind()
+// Deprecated: Use CheckPresenceRequest.ProtoReflect.Descriptor instead.
+func (*CheckPresenceRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{11}
 }
 
-type Peer_UserId struct {
-	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof"`
+func (x *CheckPresenceRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
 }
 
-type Peer_ChatId struct {
-	ChatId string `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3,oneof"`
+// CheckPresenceResponse holds one Presence per requested user, in request order.
+type CheckPresenceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Presences []*Presence `protobuf:"bytes,1,rep,name=presences,proto3" json:"presences,omitempty"`
 }
 
-type Peer_ChannelId struct {
-	ChannelId string `protobuf:"bytes,3,opt,name=channel_id,json=channelId,proto3,oneof"`
+func (x *CheckPresenceResponse) Reset() {
+	*x = CheckPresenceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-type Peer_BotId struct {
-	BotId string `protobuf:"bytes,4,opt,name=bot_id,json=botId,proto3,oneof"`
+func (x *CheckPresenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Peer_UserId) isPeer_Kind() {}
+func (*CheckPresenceResponse) ProtoMessage() {}
 
-func (*Peer_ChatId) isPeer_Kind() {}
+func (x *CheckPresenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-func (*Peer_ChannelId) isPeer_Kind() {}
+// Deprecated: Use CheckPresenceResponse.ProtoReflect.Descriptor instead.
+func (*CheckPresenceResponse) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{12}
+}
 
-func (*Peer_BotId) isPeer_Kind() {}
+func (x *CheckPresenceResponse) GetPresences() []*Presence {
+	if x != nil {
+		return x.Presences
+	}
+	return nil
+}
 
-type Document struct {
+// Presence is whether a user can be reached right now.
+type Presence struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Url       string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	FileName  string `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
-	MimeType  string `protobuf:"bytes,4,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
-	SizeBytes int64  `protobuf:"varint,5,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Cluster-wide when the global presence registry is enabled, node-local otherwise.
+	Online bool `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+	// Sessions the user holds on the answering node.
+	Sessions int32 `protobuf:"varint,3,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	// Node holding the user; the answering node when sessions > 0.
+	NodeId string `protobuf:"bytes,4,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 }
 
-func (x *Document) Reset() {
-	*x = Document{}
+func (x *Presence) Reset() {
+	*x = Presence{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[8]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Document) String() string {
+func (x *Presence) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Document) ProtoMessage() {}
+func (*Presence) ProtoMessage() {}
 
-func (x *Document) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[8]
+func (x *Presence) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -942,74 +1383,68 @@ func (x *Document) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Document.ProtoReflect.Descriptor instead.
-func (*Document) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use Presence.ProtoReflect.Descriptor instead.
+func (*Presence) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *Document) GetId() string {
+func (x *Presence) GetUserId() string {
 	if x != nil {
-		return x.Id
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *Document) GetUrl() string {
+func (x *Presence) GetOnline() bool {
 	if x != nil {
-		return x.Url
+		return x.Online
 	}
-	return ""
+	return false
 }
 
-func (x *Document) GetFileName() string {
+func (x *Presence) GetSessions() int32 {
 	if x != nil {
-		return x.FileName
+		return x.Sessions
 	}
-	return ""
+	return 0
 }
 
-func (x *Document) GetMimeType() string {
+func (x *Presence) GetNodeId() string {
 	if x != nil {
-		return x.MimeType
+		return x.NodeId
 	}
 	return ""
 }
 
-func (x *Document) GetSizeBytes() int64 {
-	if x != nil {
-		return x.SizeBytes
-	}
-	return 0
-}
-
-type Image struct {
+// GetSlowDeliveriesRequest selects a one-minute window and, optionally, an event kind.
+type GetSlowDeliveriesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Url      string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	FileName string `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
-	MimeType string `protobuf:"bytes,4,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	// Any instant within the window (Unix ms).
+	Window int64 `protobuf:"varint,1,opt,name=window,proto3" json:"window,omitempty"`
+	// Event kind, e.g. "MessageCreated"; empty returns every kind.
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
 }
 
-func (x *Image) Reset() {
-	*x = Image{}
+func (x *GetSlowDeliveriesRequest) Reset() {
+	*x = GetSlowDeliveriesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[9]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Image) String() string {
+func (x *GetSlowDeliveriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Image) ProtoMessage() {}
+func (*GetSlowDeliveriesRequest) ProtoMessage() {}
 
-func (x *Image) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[9]
+func (x *GetSlowDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1020,70 +1455,53 @@ func (x *Image) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Image.ProtoReflect.Descriptor instead.
-func (*Image) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *Image) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *Image) GetUrl() string {
-	if x != nil {
-		return x.Url
-	}
-	return ""
+// Deprecated: Use GetSlowDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*GetSlowDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *Image) GetFileName() string {
+func (x *GetSlowDeliveriesRequest) GetWindow() int64 {
 	if x != nil {
-		return x.FileName
+		return x.Window
 	}
-	return ""
+	return 0
 }
 
-func (x *Image) GetMimeType() string {
+func (x *GetSlowDeliveriesRequest) GetKind() string {
 	if x != nil {
-		return x.MimeType
+		return x.Kind
 	}
 	return ""
 }
 
-// AckEvent confirms the status of a specific event or message processing on the server.
-type AckEvent struct {
+// GetSlowDeliveriesResponse lists the window's exemplars, slowest first.
+type GetSlowDeliveriesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Reference to the original event/message ID being acknowledged.
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// Result of the operation (Success, Error, etc.).
-	Status Status `protobuf:"varint,2,opt,name=status,proto3,enum=webitel.im.api.delivery.v1.Status" json:"status,omitempty"`
-	// Additional context for the result (e.g., reason for rejection, quota details).
-	Details []*anypb.Any `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`
+	// Start of the window (Unix ms).
+	WindowStart int64           `protobuf:"varint,1,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"`
+	Deliveries  []*SlowDelivery `protobuf:"bytes,2,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
 }
 
-func (x *AckEvent) Reset() {
-	*x = AckEvent{}
+func (x *GetSlowDeliveriesResponse) Reset() {
+	*x = GetSlowDeliveriesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[10]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AckEvent) String() string {
+func (x *GetSlowDeliveriesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AckEvent) ProtoMessage() {}
+func (*GetSlowDeliveriesResponse) ProtoMessage() {}
 
-func (x *AckEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[10]
+func (x *GetSlowDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1094,63 +1512,304 @@ func (x *AckEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AckEvent.ProtoReflect.Descriptor instead.
-func (*AckEvent) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetSlowDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*GetSlowDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *AckEvent) GetId() string {
+func (x *GetSlowDeliveriesResponse) GetWindowStart() int64 {
 	if x != nil {
-		return x.Id
+		return x.WindowStart
+	}
+	return 0
+}
+
+func (x *GetSlowDeliveriesResponse) GetDeliveries() []*SlowDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+// SlowDelivery is one delivery leg above the latency threshold. It carries no content;
+// the user and domain are hashed.
+type SlowDelivery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind    string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	EventId string `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Correlation ID of the originating bus message.
+	SourceId   string `protobuf:"bytes,3,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	UserHash   string `protobuf:"bytes,4,opt,name=user_hash,json=userHash,proto3" json:"user_hash,omitempty"`
+	DomainHash string `protobuf:"bytes,5,opt,name=domain_hash,json=domainHash,proto3" json:"domain_hash,omitempty"`
+	// Transport that wrote the event: "grpc", "ws", "sse" or "lp".
+	Transport string `protobuf:"bytes,6,opt,name=transport,proto3" json:"transport,omitempty"`
+	// End-to-end latency, consume to write (microseconds).
+	TotalUs int64 `protobuf:"varint,7,opt,name=total_us,json=totalUs,proto3" json:"total_us,omitempty"`
+	// Per-stage latency (microseconds).
+	EnrichUs  int64 `protobuf:"varint,8,opt,name=enrich_us,json=enrichUs,proto3" json:"enrich_us,omitempty"`
+	HubUs     int64 `protobuf:"varint,9,opt,name=hub_us,json=hubUs,proto3" json:"hub_us,omitempty"`
+	MarshalUs int64 `protobuf:"varint,10,opt,name=marshal_us,json=marshalUs,proto3" json:"marshal_us,omitempty"`
+	WriteUs   int64 `protobuf:"varint,11,opt,name=write_us,json=writeUs,proto3" json:"write_us,omitempty"`
+	// Stage that contributed most: "enrich", "hub", "marshal" or "write".
+	SlowestStage string `protobuf:"bytes,12,opt,name=slowest_stage,json=slowestStage,proto3" json:"slowest_stage,omitempty"`
+	// Session buffer depth at write time.
+	BufferPeak int32 `protobuf:"varint,13,opt,name=buffer_peak,json=bufferPeak,proto3" json:"buffer_peak,omitempty"`
+	// Time of the write (Unix ms).
+	At int64 `protobuf:"varint,14,opt,name=at,proto3" json:"at,omitempty"`
+}
+
+func (x *SlowDelivery) Reset() {
+	*x = SlowDelivery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SlowDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlowDelivery) ProtoMessage() {}
+
+func (x *SlowDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowDelivery.ProtoReflect.Descriptor instead.
+func (*SlowDelivery) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SlowDelivery) GetKind() string {
+	if x != nil {
+		return x.Kind
 	}
 	return ""
 }
 
-func (x *AckEvent) GetStatus() Status {
+func (x *SlowDelivery) GetEventId() string {
 	if x != nil {
-		return x.Status
+		return x.EventId
 	}
-	return Status_STATUS_UNSPECIFIED
+	return ""
 }
 
-func (x *AckEvent) GetDetails() []*anypb.Any {
+func (x *SlowDelivery) GetSourceId() string {
 	if x != nil {
-		return x.Details
+		return x.SourceId
+	}
+	return ""
+}
+
+func (x *SlowDelivery) GetUserHash() string {
+	if x != nil {
+		return x.UserHash
+	}
+	return ""
+}
+
+func (x *SlowDelivery) GetDomainHash() string {
+	if x != nil {
+		return x.DomainHash
+	}
+	return ""
+}
+
+func (x *SlowDelivery) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *SlowDelivery) GetTotalUs() int64 {
+	if x != nil {
+		return x.TotalUs
+	}
+	return 0
+}
+
+func (x *SlowDelivery) GetEnrichUs() int64 {
+	if x != nil {
+		return x.EnrichUs
+	}
+	return 0
+}
+
+func (x *SlowDelivery) GetHubUs() int64 {
+	if x != nil {
+		return x.HubUs
+	}
+	return 0
+}
+
+func (x *SlowDelivery) GetMarshalUs() int64 {
+	if x != nil {
+		return x.MarshalUs
+	}
+	return 0
+}
+
+func (x *SlowDelivery) GetWriteUs() int64 {
+	if x != nil {
+		return x.WriteUs
+	}
+	return 0
+}
+
+func (x *SlowDelivery) GetSlowestStage() string {
+	if x != nil {
+		return x.SlowestStage
+	}
+	return ""
+}
+
+func (x *SlowDelivery) GetBufferPeak() int32 {
+	if x != nil {
+		return x.BufferPeak
+	}
+	return 0
+}
+
+func (x *SlowDelivery) GetAt() int64 {
+	if x != nil {
+		return x.At
+	}
+	return 0
+}
+
+// GetDeprecationUsageRequest takes no parameters; every registered feature is returned.
+type GetDeprecationUsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetDeprecationUsageRequest) Reset() {
+	*x = GetDeprecationUsageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDeprecationUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeprecationUsageRequest) ProtoMessage() {}
+
+func (x *GetDeprecationUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeprecationUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetDeprecationUsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{17}
+}
+
+// GetDeprecationUsageResponse lists the registered deprecated features by name.
+type GetDeprecationUsageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Features []*DeprecationUsage `protobuf:"bytes,1,rep,name=features,proto3" json:"features,omitempty"`
+}
+
+func (x *GetDeprecationUsageResponse) Reset() {
+	*x = GetDeprecationUsageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDeprecationUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeprecationUsageResponse) ProtoMessage() {}
+
+func (x *GetDeprecationUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeprecationUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetDeprecationUsageResponse) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetDeprecationUsageResponse) GetFeatures() []*DeprecationUsage {
+	if x != nil {
+		return x.Features
 	}
 	return nil
 }
 
-// ErrorEvent communicates issues that occur during the stream's lifetime.
-type ErrorEvent struct {
+// DeprecationUsage is the use count of one deprecated feature on the serving node.
+type DeprecationUsage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Machine-readable string constant (e.g., "INTERNAL_ERROR", "AUTH_EXPIRED").
-	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
-	// Human-readable message explaining the error.
-	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	// Structured error metadata for programmatic handling.
-	Details []*anypb.Any `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`
+	Feature     string          `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
+	Description string          `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Mode        DeprecationMode `protobuf:"varint,3,opt,name=mode,proto3,enum=webitel.im.api.delivery.v1.DeprecationMode" json:"mode,omitempty"`
+	// Uses since the node started, rejected ones included.
+	Count int64 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	// Time of the last use (Unix ms); 0 when never used.
+	LastSeen int64 `protobuf:"varint,5,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
 }
 
-func (x *ErrorEvent) Reset() {
-	*x = ErrorEvent{}
+func (x *DeprecationUsage) Reset() {
+	*x = DeprecationUsage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[11]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ErrorEvent) String() string {
+func (x *DeprecationUsage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ErrorEvent) ProtoMessage() {}
+func (*DeprecationUsage) ProtoMessage() {}
 
-func (x *ErrorEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[11]
+func (x *DeprecationUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1161,349 +1820,3597 @@ func (x *ErrorEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ErrorEvent.ProtoReflect.Descriptor instead.
-func (*ErrorEvent) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use DeprecationUsage.ProtoReflect.Descriptor instead.
+func (*DeprecationUsage) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *ErrorEvent) GetCode() string {
+func (x *DeprecationUsage) GetFeature() string {
 	if x != nil {
-		return x.Code
+		return x.Feature
 	}
 	return ""
 }
 
-func (x *ErrorEvent) GetMessage() string {
+func (x *DeprecationUsage) GetDescription() string {
 	if x != nil {
-		return x.Message
+		return x.Description
 	}
 	return ""
 }
 
-func (x *ErrorEvent) GetDetails() []*anypb.Any {
+func (x *DeprecationUsage) GetMode() DeprecationMode {
 	if x != nil {
-		return x.Details
+		return x.Mode
 	}
-	return nil
+	return DeprecationMode_DEPRECATION_MODE_UNSPECIFIED
 }
 
-// PingEvent is used to verify the connection is still active.
-type PingEvent struct {
+func (x *DeprecationUsage) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DeprecationUsage) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+// ListShapingRulesRequest takes no parameters; every rule in effect is returned.
+type ListShapingRulesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+}
 
-	// Optional data that the server might include for RTT (Round Trip Time) calculation.
-	Echo string `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+func (x *ListShapingRulesRequest) Reset() {
+	*x = ListShapingRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *PingEvent) Reset() {
-	*x = PingEvent{}
+func (x *ListShapingRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListShapingRulesRequest) ProtoMessage() {}
+
+func (x *ListShapingRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListShapingRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListShapingRulesRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{20}
+}
+
+// ListShapingRulesResponse lists the delivery shaping rules in effect on the serving node.
+// It is empty in production, where shaping never applies.
+type ListShapingRulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rules []*ShapingRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *ListShapingRulesResponse) Reset() {
+	*x = ListShapingRulesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_delivery_v1_delivery_proto_msgTypes[12]
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PingEvent) String() string {
+func (x *ListShapingRulesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PingEvent) ProtoMessage() {}
+func (*ListShapingRulesResponse) ProtoMessage() {}
 
-func (x *PingEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_delivery_v1_delivery_proto_msgTypes[12]
+func (x *ListShapingRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use PingEvent.ProtoReflect.Descriptor instead.
-func (*PingEvent) Descriptor() ([]byte, []int) {
-	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *PingEvent) GetEcho() string {
-	if x != nil {
-		return x.Echo
-	}
-	return ""
-}
-
-var File_api_delivery_v1_delivery_proto protoreflect.FileDescriptor
-
-var file_api_delivery_v1_delivery_proto_rawDesc = []byte{
-	0x0a, 0x1e, 0x61, 0x70, 0x69, 0x2f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2f, 0x76,
-	0x31, 0x2f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x1a, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69,
-	0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x1a, 0x19, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e,
-	0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d,
-	0x67, 0x65, 0x6e, 0x2d, 0x6f, 0x70, 0x65, 0x6e, 0x61, 0x70, 0x69, 0x76, 0x32, 0x2f, 0x6f, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x0f, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61,
-	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x9f, 0x05, 0x0a, 0x0b, 0x53, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x42, 0x2c, 0x92, 0x41, 0x29, 0x32, 0x27, 0x55, 0x6e, 0x69, 0x71, 0x75,
-	0x65, 0x20, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x20, 0x6f, 0x66, 0x20,
-	0x74, 0x68, 0x65, 0x20, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x65, 0x64, 0x20, 0x65, 0x76, 0x65,
-	0x6e, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x45, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74,
-	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65,
-	0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
-	0x79, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69,
-	0x74, 0x79, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x55, 0x0a, 0x0f,
-	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e,
-	0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e,
-	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x48, 0x00, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x12, 0x5e, 0x0a, 0x12, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
-	0x74, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x2d, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69,
-	0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x73,
-	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
-	0x52, 0x11, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x12, 0x52, 0x0a, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x77, 0x65, 0x62,
-	0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69,
-	0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x77, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x43, 0x0a, 0x09, 0x61, 0x63, 0x6b, 0x5f, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x77, 0x65, 0x62,
-	0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69,
-	0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x48, 0x00, 0x52, 0x08, 0x61, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x49, 0x0a, 0x0b,
-	0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x26, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61,
-	0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x45,
-	0x72, 0x72, 0x6f, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x65, 0x72, 0x72,
-	0x6f, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x46, 0x0a, 0x0a, 0x70, 0x69, 0x6e, 0x67, 0x5f,
-	0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x77, 0x65,
-	0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c,
-	0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x70, 0x69, 0x6e, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42,
-	0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x9d, 0x01, 0x0a, 0x0e, 0x43,
-	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3f, 0x0a,
-	0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x42, 0x2f, 0x92, 0x41, 0x2c, 0x32, 0x2a,
-	0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x20, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73,
-	0x73, 0x66, 0x75, 0x6c, 0x20, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x20, 0x69, 0x6e, 0x69, 0x74,
-	0x69, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x02, 0x6f, 0x6b, 0x12, 0x23,
-	0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x76, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x95, 0x01, 0x0a, 0x11, 0x44,
-	0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x12, 0x44, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x42, 0x2c, 0x92, 0x41, 0x29, 0x32, 0x27, 0x48, 0x75, 0x6d, 0x61, 0x6e, 0x2d, 0x72, 0x65, 0x61,
-	0x64, 0x61, 0x62, 0x6c, 0x65, 0x20, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x20, 0x66, 0x6f, 0x72,
-	0x20, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
-	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x42, 0x26, 0x92, 0x41, 0x23, 0x32, 0x21, 0x4d, 0x61, 0x63, 0x68, 0x69,
-	0x6e, 0x65, 0x2d, 0x72, 0x65, 0x61, 0x64, 0x61, 0x62, 0x6c, 0x65, 0x20, 0x74, 0x65, 0x72, 0x6d,
-	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x20, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f,
-	0x64, 0x65, 0x22, 0x7d, 0x0a, 0x0f, 0x4e, 0x65, 0x77, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x6a, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c,
-	0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
-	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x42, 0x25, 0x92, 0x41, 0x22, 0x32, 0x20, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x20,
-	0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x20, 0x76, 0x69, 0x61, 0x20, 0x74, 0x68,
-	0x65, 0x20, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x22, 0xbb, 0x03, 0x0a, 0x0d, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x64,
-	0x12, 0x34, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
-	0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72,
-	0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x30, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e,
-	0x50, 0x65, 0x65, 0x72, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x64, 0x69, 0x74, 0x65,
-	0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x64, 0x69, 0x74,
-	0x65, 0x64, 0x41, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x3b, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c,
-	0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
-	0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x42, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
-	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65,
-	0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
-	0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52,
-	0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x39, 0x0a, 0x05, 0x69, 0x6d, 0x61,
-	0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74,
-	0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
-	0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x05, 0x69,
-	0x6d, 0x61, 0x67, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22,
-	0x36, 0x0a, 0x08, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x69,
-	0x73, 0x73, 0x75, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x73, 0x73,
-	0x75, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xc0, 0x01, 0x0a, 0x04, 0x50, 0x65, 0x65, 0x72,
-	0x12, 0x19, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x48, 0x00, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x07, 0x63,
-	0x68, 0x61, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06,
-	0x63, 0x68, 0x61, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x06, 0x62, 0x6f, 0x74, 0x5f, 0x69,
-	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x62, 0x6f, 0x74, 0x49, 0x64,
-	0x12, 0x40, 0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x0a, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x24, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e,
-	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x74, 0x79, 0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x85, 0x01, 0x0a, 0x08, 0x44,
-	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c,
-	0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69,
-	0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6d, 0x65, 0x5f, 0x74,
-	0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x69, 0x6d, 0x65, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65,
-	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74,
-	0x65, 0x73, 0x22, 0x63, 0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75,
-	0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1b, 0x0a,
-	0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69,
-	0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d,
-	0x69, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x22, 0x86, 0x01, 0x0a, 0x08, 0x41, 0x63, 0x6b, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69,
-	0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76,
-	0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x12, 0x2e, 0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
-	0x22, 0xd9, 0x01, 0x0a, 0x0a, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
-	0x34, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x20, 0x92,
-	0x41, 0x1d, 0x32, 0x1b, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x2d, 0x72, 0x65, 0x61, 0x64,
-	0x61, 0x62, 0x6c, 0x65, 0x20, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x63, 0x6f, 0x64, 0x65, 0x52,
-	0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x3b, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x21, 0x92, 0x41, 0x1e, 0x32, 0x1c, 0x48, 0x75, 0x6d,
-	0x61, 0x6e, 0x2d, 0x72, 0x65, 0x61, 0x64, 0x61, 0x62, 0x6c, 0x65, 0x20, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x20, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x12, 0x58, 0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x03, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x42, 0x28, 0x92, 0x41, 0x25, 0x32, 0x23,
-	0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x20, 0x73, 0x74, 0x72, 0x75, 0x63,
-	0x74, 0x75, 0x72, 0x65, 0x64, 0x20, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x64, 0x65, 0x74, 0x61,
-	0x69, 0x6c, 0x73, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x22, 0x3b, 0x0a, 0x09,
-	0x50, 0x69, 0x6e, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x65, 0x63, 0x68,
-	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x1a, 0x92, 0x41, 0x17, 0x32, 0x15, 0x4f, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x20, 0x70, 0x69, 0x6e, 0x67, 0x20, 0x70, 0x61, 0x79, 0x6c,
-	0x6f, 0x61, 0x64, 0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x2a, 0x4e, 0x0a, 0x0b, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x55, 0x4e, 0x53, 0x50,
-	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f,
-	0x54, 0x59, 0x50, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45, 0x58, 0x54, 0x10, 0x01,
-	0x12, 0x0c, 0x0a, 0x08, 0x44, 0x4f, 0x43, 0x55, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x09,
-	0x0a, 0x05, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x10, 0x03, 0x2a, 0x65, 0x0a, 0x06, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e,
-	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x53,
-	0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x45, 0x4d, 0x50,
-	0x4f, 0x52, 0x41, 0x52, 0x59, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x13, 0x0a,
-	0x0f, 0x50, 0x45, 0x52, 0x4d, 0x41, 0x4e, 0x45, 0x4e, 0x54, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52,
-	0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x04,
-	0x2a, 0x48, 0x0a, 0x0d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74,
-	0x79, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e,
-	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x48,
-	0x49, 0x47, 0x48, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x4e, 0x4f, 0x52, 0x4d, 0x41, 0x4c, 0x10,
-	0x02, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x4f, 0x57, 0x10, 0x03, 0x42, 0x82, 0x02, 0x0a, 0x1e, 0x63,
-	0x6f, 0x6d, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70,
-	0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x42, 0x0d, 0x44,
-	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x44,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x65, 0x62, 0x69, 0x74,
-	0x65, 0x6c, 0x2f, 0x69, 0x6d, 0x2d, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2d, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x64, 0x65,
-	0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2f, 0x76, 0x31, 0x3b, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
-	0x72, 0x79, 0x76, 0x31, 0xa2, 0x02, 0x04, 0x57, 0x49, 0x41, 0x44, 0xaa, 0x02, 0x1a, 0x57, 0x65,
-	0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x49, 0x6d, 0x2e, 0x41, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x6c,
-	0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x1a, 0x57, 0x65, 0x62, 0x69, 0x74,
-	0x65, 0x6c, 0x5c, 0x49, 0x6d, 0x5c, 0x41, 0x70, 0x69, 0x5c, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65,
-	0x72, 0x79, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x26, 0x57, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x5c,
-	0x49, 0x6d, 0x5c, 0x41, 0x70, 0x69, 0x5c, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5c,
-	0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02,
-	0x1e, 0x57, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x3a, 0x3a, 0x49, 0x6d, 0x3a, 0x3a, 0x41, 0x70,
-	0x69, 0x3a, 0x3a, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x3a, 0x3a, 0x56, 0x31, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_api_delivery_v1_delivery_proto_rawDescOnce sync.Once
-	file_api_delivery_v1_delivery_proto_rawDescData = file_api_delivery_v1_delivery_proto_rawDesc
-)
-
-func file_api_delivery_v1_delivery_proto_rawDescGZIP() []byte {
-	file_api_delivery_v1_delivery_proto_rawDescOnce.Do(func() {
-		file_api_delivery_v1_delivery_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_delivery_v1_delivery_proto_rawDescData)
-	})
-	return file_api_delivery_v1_delivery_proto_rawDescData
-}
-
-var file_api_delivery_v1_delivery_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_api_delivery_v1_delivery_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
-var file_api_delivery_v1_delivery_proto_goTypes = []interface{}{
-	(MessageType)(0),          // 0: webitel.im.api.delivery.v1.MessageType
-	(Status)(0),               // 1: webitel.im.api.delivery.v1.Status
-	(EventPriority)(0),        // 2: webitel.im.api.delivery.v1.EventPriority
-	(*StreamRequest)(nil),     // 3: webitel.im.api.delivery.v1.StreamRequest
-	(*ServerEvent)(nil),       // 4: webitel.im.api.delivery.v1.ServerEvent
-	(*ConnectedEvent)(nil),    // 5: webitel.im.api.delivery.v1.ConnectedEvent
-	(*DisconnectedEvent)(nil), // 6: webitel.im.api.delivery.v1.DisconnectedEvent
-	(*NewMessageEvent)(nil),   // 7: webitel.im.api.delivery.v1.NewMessageEvent
-	(*ThreadMessage)(nil),     // 8: webitel.im.api.delivery.v1.ThreadMessage
-	(*Identity)(nil),          // 9: webitel.im.api.delivery.v1.Identity
-	(*Peer)(nil),              // 10: webitel.im.api.delivery.v1.Peer
-	(*Document)(nil),          // 11: webitel.im.api.delivery.v1.Document
-	(*Image)(nil),             // 12: webitel.im.api.delivery.v1.Image
-	(*AckEvent)(nil),          // 13: webitel.im.api.delivery.v1.AckEvent
-	(*ErrorEvent)(nil),        // 14: webitel.im.api.delivery.v1.ErrorEvent
-	(*PingEvent)(nil),         // 15: webitel.im.api.delivery.v1.PingEvent
-	(*anypb.Any)(nil),         // 16: google.protobuf.Any
-}
-var file_api_delivery_v1_delivery_proto_depIdxs = []int32{
-	2,  // 0: webitel.im.api.delivery.v1.ServerEvent.priority:type_name -> webitel.im.api.delivery.v1.EventPriority
-	5,  // 1: webitel.im.api.delivery.v1.ServerEvent.connected_event:type_name -> webitel.im.api.delivery.v1.ConnectedEvent
-	6,  // 2: webitel.im.api.delivery.v1.ServerEvent.disconnected_event:type_name -> webitel.im.api.delivery.v1.DisconnectedEvent
-	7,  // 3: webitel.im.api.delivery.v1.ServerEvent.message_event:type_name -> webitel.im.api.delivery.v1.NewMessageEvent
-	13, // 4: webitel.im.api.delivery.v1.ServerEvent.ack_event:type_name -> webitel.im.api.delivery.v1.AckEvent
-	14, // 5: webitel.im.api.delivery.v1.ServerEvent.error_event:type_name -> webitel.im.api.delivery.v1.ErrorEvent
-	15, // 6: webitel.im.api.delivery.v1.ServerEvent.ping_event:type_name -> webitel.im.api.delivery.v1.PingEvent
-	8,  // 7: webitel.im.api.delivery.v1.NewMessageEvent.message:type_name -> webitel.im.api.delivery.v1.ThreadMessage
-	10, // 8: webitel.im.api.delivery.v1.ThreadMessage.from:type_name -> webitel.im.api.delivery.v1.Peer
-	10, // 9: webitel.im.api.delivery.v1.ThreadMessage.to:type_name -> webitel.im.api.delivery.v1.Peer
-	0,  // 10: webitel.im.api.delivery.v1.ThreadMessage.type:type_name -> webitel.im.api.delivery.v1.MessageType
-	11, // 11: webitel.im.api.delivery.v1.ThreadMessage.document:type_name -> webitel.im.api.delivery.v1.Document
-	12, // 12: webitel.im.api.delivery.v1.ThreadMessage.image:type_name -> webitel.im.api.delivery.v1.Image
-	9,  // 13: webitel.im.api.delivery.v1.Peer.identity:type_name -> webitel.im.api.delivery.v1.Identity
-	1,  // 14: webitel.im.api.delivery.v1.AckEvent.status:type_name -> webitel.im.api.delivery.v1.Status
-	16, // 15: webitel.im.api.delivery.v1.AckEvent.details:type_name -> google.protobuf.Any
-	16, // 16: webitel.im.api.delivery.v1.ErrorEvent.details:type_name -> google.protobuf.Any
-	17, // [17:17] is the sub-list for method output_type
-	17, // [17:17] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
-}
-
-func init() { file_api_delivery_v1_delivery_proto_init() }
-func file_api_delivery_v1_delivery_proto_init() {
-	if File_api_delivery_v1_delivery_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_api_delivery_v1_delivery_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StreamRequest); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListShapingRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListShapingRulesResponse) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListShapingRulesResponse) GetRules() []*ShapingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// ShapingRule delays events of one kind for one domain, for client UX testing.
+type ShapingRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Domain the rule applies to; 0 for every domain.
+	DomainId int64 `protobuf:"varint,1,opt,name=domain_id,json=domainId,proto3" json:"domain_id,omitempty"`
+	// Event kind the rule applies to; "*" for every kind.
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Fixed delay, plus up to jitter_ms of random extra delay.
+	DelayMs  int32 `protobuf:"varint,3,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`
+	JitterMs int32 `protobuf:"varint,4,opt,name=jitter_ms,json=jitterMs,proto3" json:"jitter_ms,omitempty"`
+	// Up to reorder_ms of extra hold for reorder_percent (0..100) of the events,
+	// so later events can overtake them.
+	ReorderMs      int32   `protobuf:"varint,5,opt,name=reorder_ms,json=reorderMs,proto3" json:"reorder_ms,omitempty"`
+	ReorderPercent float64 `protobuf:"fixed64,6,opt,name=reorder_percent,json=reorderPercent,proto3" json:"reorder_percent,omitempty"`
+}
+
+func (x *ShapingRule) Reset() {
+	*x = ShapingRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShapingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShapingRule) ProtoMessage() {}
+
+func (x *ShapingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShapingRule.ProtoReflect.Descriptor instead.
+func (*ShapingRule) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ShapingRule) GetDomainId() int64 {
+	if x != nil {
+		return x.DomainId
+	}
+	return 0
+}
+
+func (x *ShapingRule) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ShapingRule) GetDelayMs() int32 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *ShapingRule) GetJitterMs() int32 {
+	if x != nil {
+		return x.JitterMs
+	}
+	return 0
+}
+
+func (x *ShapingRule) GetReorderMs() int32 {
+	if x != nil {
+		return x.ReorderMs
+	}
+	return 0
+}
+
+func (x *ShapingRule) GetReorderPercent() float64 {
+	if x != nil {
+		return x.ReorderPercent
+	}
+	return 0
+}
+
+// PushEventRequest describes a one-off event injected by internal tooling.
+type PushEventRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Recipient; must hold a session on the serving node.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Event kind, one of: message_created, message_updated, message_deleted,
+	// message_read, message_reaction.
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// JSON body in the bus contract of the kind.
+	PayloadJson []byte `protobuf:"bytes,3,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
+	// Requested priority. Pushed events never outrank live traffic: HIGH is lowered to NORMAL.
+	Priority EventPriority `protobuf:"varint,4,opt,name=priority,proto3,enum=webitel.im.api.delivery.v1.EventPriority" json:"priority,omitempty"`
+}
+
+func (x *PushEventRequest) Reset() {
+	*x = PushEventRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PushEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushEventRequest) ProtoMessage() {}
+
+func (x *PushEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushEventRequest.ProtoReflect.Descriptor instead.
+func (*PushEventRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PushEventRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PushEventRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *PushEventRequest) GetPayloadJson() []byte {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return nil
+}
+
+func (x *PushEventRequest) GetPriority() EventPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return EventPriority_PRIORITY_UNSPECIFIED
+}
+
+// PushEventResponse reports what the serving node did with the pushed event.
+type PushEventResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Outcome PushOutcome `protobuf:"varint,1,opt,name=outcome,proto3,enum=webitel.im.api.delivery.v1.PushOutcome" json:"outcome,omitempty"`
+}
+
+func (x *PushEventResponse) Reset() {
+	*x = PushEventResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PushEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushEventResponse) ProtoMessage() {}
+
+func (x *PushEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushEventResponse.ProtoReflect.Descriptor instead.
+func (*PushEventResponse) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PushEventResponse) GetOutcome() PushOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return PushOutcome_PUSH_OUTCOME_UNSPECIFIED
+}
+
+// ImportEventsRequest is one batch of a history migration.
+type ImportEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Records in the versioned export format, one JSON object each:
+	// {"version": 1, "user_id": "...", "message": {...}} with From/To already enriched.
+	RecordsJson [][]byte `protobuf:"bytes,1,rep,name=records_json,json=recordsJson,proto3" json:"records_json,omitempty"`
+}
+
+func (x *ImportEventsRequest) Reset() {
+	*x = ImportEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportEventsRequest) ProtoMessage() {}
+
+func (x *ImportEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportEventsRequest.ProtoReflect.Descriptor instead.
+func (*ImportEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ImportEventsRequest) GetRecordsJson() [][]byte {
+	if x != nil {
+		return x.RecordsJson
+	}
+	return nil
+}
+
+// ImportEventsSummary reports every batch of the stream, in the order they were sent.
+type ImportEventsSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Batches []*ImportBatchSummary `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
+}
+
+func (x *ImportEventsSummary) Reset() {
+	*x = ImportEventsSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportEventsSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportEventsSummary) ProtoMessage() {}
+
+func (x *ImportEventsSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportEventsSummary.ProtoReflect.Descriptor instead.
+func (*ImportEventsSummary) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ImportEventsSummary) GetBatches() []*ImportBatchSummary {
+	if x != nil {
+		return x.Batches
+	}
+	return nil
+}
+
+// ImportBatchSummary is the outcome of one ImportEventsRequest.
+type ImportBatchSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Records taken over: delivered_live + stored.
+	Accepted int32 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	// Records handed to a session connected to the serving node.
+	DeliveredLive int32 `protobuf:"varint,2,opt,name=delivered_live,json=deliveredLive,proto3" json:"delivered_live,omitempty"`
+	// Records held for users offline on the serving node, delivered on their next subscribe.
+	Stored   int32              `protobuf:"varint,3,opt,name=stored,proto3" json:"stored,omitempty"`
+	Rejected []*ImportRejection `protobuf:"bytes,4,rep,name=rejected,proto3" json:"rejected,omitempty"`
+}
+
+func (x *ImportBatchSummary) Reset() {
+	*x = ImportBatchSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportBatchSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportBatchSummary) ProtoMessage() {}
+
+func (x *ImportBatchSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportBatchSummary.ProtoReflect.Descriptor instead.
+func (*ImportBatchSummary) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ImportBatchSummary) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *ImportBatchSummary) GetDeliveredLive() int32 {
+	if x != nil {
+		return x.DeliveredLive
+	}
+	return 0
+}
+
+func (x *ImportBatchSummary) GetStored() int32 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
+func (x *ImportBatchSummary) GetRejected() []*ImportRejection {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+// ImportRejection explains why a record was not imported.
+type ImportRejection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Position of the record in its batch.
+	Index  int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *ImportRejection) Reset() {
+	*x = ImportRejection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRejection) ProtoMessage() {}
+
+func (x *ImportRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRejection.ProtoReflect.Descriptor instead.
+func (*ImportRejection) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ImportRejection) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportRejection) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ServerEvent is a container for all types of data sent over the delivery stream.
+type ServerEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unique identifier for the event. Used for logging, tracing, and deduplication.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Timestamp of event generation (Unix epoch in milliseconds).
+	CreatedAt int64 `protobuf:"varint,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Relative importance of the event for client-side processing priority.
+	Priority EventPriority `protobuf:"varint,3,opt,name=priority,proto3,enum=webitel.im.api.delivery.v1.EventPriority" json:"priority,omitempty"`
+	// Set on events re-sent from the replay buffer on resume, ahead of the live feed.
+	Replayed bool `protobuf:"varint,21,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	// Delivery diagnostics; only set outside production, e.g. while delivery shaping is active.
+	Debug *EventDebug `protobuf:"bytes,22,opt,name=debug,proto3" json:"debug,omitempty"`
+	// Payload contains the specific event data.
+	//
+	// Types that are assignable to Payload:
+	//	*ServerEvent_ConnectedEvent
+	//	*ServerEvent_DisconnectedEvent
+	//	*ServerEvent_MessageEvent
+	//	*ServerEvent_AckEvent
+	//	*ServerEvent_ErrorEvent
+	//	*ServerEvent_PingEvent
+	//	*ServerEvent_MessageDeletedEvent
+	//	*ServerEvent_MessageUpdatedEvent
+	//	*ServerEvent_TypingEvent
+	//	*ServerEvent_MessageReadEvent
+	//	*ServerEvent_ReactionEvent
+	//	*ServerEvent_PresenceStatusEvent
+	//	*ServerEvent_ReplayGapEvent
+	//	*ServerEvent_DomainPausedEvent
+	//	*ServerEvent_DomainResumedEvent
+	//	*ServerEvent_DeliveryDegradedEvent
+	//	*ServerEvent_EncryptedEvent
+	Payload isServerEvent_Payload `protobuf_oneof:"payload"`
+}
+
+func (x *ServerEvent) Reset() {
+	*x = ServerEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerEvent) ProtoMessage() {}
+
+func (x *ServerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerEvent.ProtoReflect.Descriptor instead.
+func (*ServerEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ServerEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServerEvent) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ServerEvent) GetPriority() EventPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return EventPriority_PRIORITY_UNSPECIFIED
+}
+
+func (x *ServerEvent) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *ServerEvent) GetDebug() *EventDebug {
+	if x != nil {
+		return x.Debug
+	}
+	return nil
+}
+
+func (m *ServerEvent) GetPayload() isServerEvent_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetConnectedEvent() *ConnectedEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_ConnectedEvent); ok {
+		return x.ConnectedEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetDisconnectedEvent() *DisconnectedEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_DisconnectedEvent); ok {
+		return x.DisconnectedEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetMessageEvent() *NewMessageEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_MessageEvent); ok {
+		return x.MessageEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetAckEvent() *AckEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_AckEvent); ok {
+		return x.AckEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetErrorEvent() *ErrorEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_ErrorEvent); ok {
+		return x.ErrorEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetPingEvent() *PingEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_PingEvent); ok {
+		return x.PingEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetMessageDeletedEvent() *MessageDeletedEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_MessageDeletedEvent); ok {
+		return x.MessageDeletedEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetMessageUpdatedEvent() *MessageUpdatedEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_MessageUpdatedEvent); ok {
+		return x.MessageUpdatedEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetTypingEvent() *TypingEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_TypingEvent); ok {
+		return x.TypingEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetMessageReadEvent() *MessageReadEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_MessageReadEvent); ok {
+		return x.MessageReadEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetReactionEvent() *ReactionEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_ReactionEvent); ok {
+		return x.ReactionEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetPresenceStatusEvent() *PresenceStatusEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_PresenceStatusEvent); ok {
+		return x.PresenceStatusEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetReplayGapEvent() *ReplayGapEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_ReplayGapEvent); ok {
+		return x.ReplayGapEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetDomainPausedEvent() *DomainPausedEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_DomainPausedEvent); ok {
+		return x.DomainPausedEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetDomainResumedEvent() *DomainResumedEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_DomainResumedEvent); ok {
+		return x.DomainResumedEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetDeliveryDegradedEvent() *DeliveryDegradedEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_DeliveryDegradedEvent); ok {
+		return x.DeliveryDegradedEvent
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetEncryptedEvent() *EncryptedEvent {
+	if x, ok := x.GetPayload().(*ServerEvent_EncryptedEvent); ok {
+		return x.EncryptedEvent
+	}
+	return nil
+}
+
+type isServerEvent_Payload interface {
+	isServerEvent_Payload()
+}
+
+type ServerEvent_ConnectedEvent struct {
+	// Confirmation of successful stream establishment.
+	ConnectedEvent *ConnectedEvent `protobuf:"bytes,4,opt,name=connected_event,json=connectedEvent,proto3,oneof"`
+}
+
+type ServerEvent_DisconnectedEvent struct {
+	// Notification sent before the server closes the stream.
+	DisconnectedEvent *DisconnectedEvent `protobuf:"bytes,5,opt,name=disconnected_event,json=disconnectedEvent,proto3,oneof"`
+}
+
+type ServerEvent_MessageEvent struct {
+	// A new incoming message from a chat/thread.
+	MessageEvent *NewMessageEvent `protobuf:"bytes,6,opt,name=message_event,json=messageEvent,proto3,oneof"`
+}
+
+type ServerEvent_AckEvent struct {
+	// Acknowledgement of a previously sent message or processed action.
+	AckEvent *AckEvent `protobuf:"bytes,7,opt,name=ack_event,json=ackEvent,proto3,oneof"`
+}
+
+type ServerEvent_ErrorEvent struct {
+	// Notification about a stream-level or business-logic error.
+	ErrorEvent *ErrorEvent `protobuf:"bytes,8,opt,name=error_event,json=errorEvent,proto3,oneof"`
+}
+
+type ServerEvent_PingEvent struct {
+	// Heartbeat signal to maintain connection and detect "half-open" sockets.
+	PingEvent *PingEvent `protobuf:"bytes,9,opt,name=ping_event,json=pingEvent,proto3,oneof"`
+}
+
+type ServerEvent_MessageDeletedEvent struct {
+	// A message was deleted from a thread the client participates in.
+	MessageDeletedEvent *MessageDeletedEvent `protobuf:"bytes,10,opt,name=message_deleted_event,json=messageDeletedEvent,proto3,oneof"`
+}
+
+type ServerEvent_MessageUpdatedEvent struct {
+	// A message the client already holds was edited; carries the full new revision.
+	MessageUpdatedEvent *MessageUpdatedEvent `protobuf:"bytes,11,opt,name=message_updated_event,json=messageUpdatedEvent,proto3,oneof"`
+}
+
+type ServerEvent_TypingEvent struct {
+	// A participant is typing in a thread; hide the indicator after expires_at.
+	TypingEvent *TypingEvent `protobuf:"bytes,12,opt,name=typing_event,json=typingEvent,proto3,oneof"`
+}
+
+type ServerEvent_MessageReadEvent struct {
+	// A participant read a thread up to a message.
+	MessageReadEvent *MessageReadEvent `protobuf:"bytes,13,opt,name=message_read_event,json=messageReadEvent,proto3,oneof"`
+}
+
+type ServerEvent_ReactionEvent struct {
+	// A reaction was added to or removed from a message.
+	ReactionEvent *ReactionEvent `protobuf:"bytes,14,opt,name=reaction_event,json=reactionEvent,proto3,oneof"`
+}
+
+type ServerEvent_PresenceStatusEvent struct {
+	// A watched contact changed its availability.
+	PresenceStatusEvent *PresenceStatusEvent `protobuf:"bytes,15,opt,name=presence_status_event,json=presenceStatusEvent,proto3,oneof"`
+}
+
+type ServerEvent_ReplayGapEvent struct {
+	// The resume cursor fell out of the replay window; refetch history before trusting the stream.
+	ReplayGapEvent *ReplayGapEvent `protobuf:"bytes,16,opt,name=replay_gap_event,json=replayGapEvent,proto3,oneof"`
+}
+
+type ServerEvent_DomainPausedEvent struct {
+	// Delivery for the client's domain is paused for maintenance; events are held until resumed.
+	DomainPausedEvent *DomainPausedEvent `protobuf:"bytes,17,opt,name=domain_paused_event,json=domainPausedEvent,proto3,oneof"`
+}
+
+type ServerEvent_DomainResumedEvent struct {
+	// The maintenance window ended; held events follow.
+	DomainResumedEvent *DomainResumedEvent `protobuf:"bytes,18,opt,name=domain_resumed_event,json=domainResumedEvent,proto3,oneof"`
+}
+
+type ServerEvent_DeliveryDegradedEvent struct {
+	// Events for this stream were shed under backpressure; resync history.
+	DeliveryDegradedEvent *DeliveryDegradedEvent `protobuf:"bytes,19,opt,name=delivery_degraded_event,json=deliveryDegradedEvent,proto3,oneof"`
+}
+
+type ServerEvent_EncryptedEvent struct {
+	// An end-to-end encrypted message; the body is opaque to the server.
+	EncryptedEvent *EncryptedEvent `protobuf:"bytes,20,opt,name=encrypted_event,json=encryptedEvent,proto3,oneof"`
+}
+
+func (*ServerEvent_ConnectedEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_DisconnectedEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_MessageEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_AckEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_ErrorEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_PingEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_MessageDeletedEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_MessageUpdatedEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_TypingEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_MessageReadEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_ReactionEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_PresenceStatusEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_ReplayGapEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_DomainPausedEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_DomainResumedEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_DeliveryDegradedEvent) isServerEvent_Payload() {}
+
+func (*ServerEvent_EncryptedEvent) isServerEvent_Payload() {}
+
+// EventDebug carries diagnostics about how the serving node delivered one event.
+type EventDebug struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Synthetic delay delivery shaping held the event back by, in milliseconds.
+	ShapingDelayMs int64 `protobuf:"varint,1,opt,name=shaping_delay_ms,json=shapingDelayMs,proto3" json:"shaping_delay_ms,omitempty"`
+}
+
+func (x *EventDebug) Reset() {
+	*x = EventDebug{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventDebug) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventDebug) ProtoMessage() {}
+
+func (x *EventDebug) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventDebug.ProtoReflect.Descriptor instead.
+func (*EventDebug) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *EventDebug) GetShapingDelayMs() int64 {
+	if x != nil {
+		return x.ShapingDelayMs
+	}
+	return 0
+}
+
+// ConnectedEvent is the first message sent by the server after the stream is opened.
+type ConnectedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Status of the stream initialization.
+	Ok bool `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	// Unique session/connection ID assigned by the server for this stream.
+	ConnectionId string `protobuf:"bytes,2,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	// Current server version. Useful for feature detection and troubleshooting.
+	ServerVersion string `protobuf:"bytes,3,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`
+	// Deprecated features this connection relies on, e.g. "raw_last_event_id".
+	// Report them in client telemetry; they may be rejected in a later release.
+	Deprecations []string `protobuf:"bytes,4,rep,name=deprecations,proto3" json:"deprecations,omitempty"`
+}
+
+func (x *ConnectedEvent) Reset() {
+	*x = ConnectedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectedEvent) ProtoMessage() {}
+
+func (x *ConnectedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectedEvent.ProtoReflect.Descriptor instead.
+func (*ConnectedEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ConnectedEvent) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *ConnectedEvent) GetConnectionId() string {
+	if x != nil {
+		return x.ConnectionId
+	}
+	return ""
+}
+
+func (x *ConnectedEvent) GetServerVersion() string {
+	if x != nil {
+		return x.ServerVersion
+	}
+	return ""
+}
+
+func (x *ConnectedEvent) GetDeprecations() []string {
+	if x != nil {
+		return x.Deprecations
+	}
+	return nil
+}
+
+// DisconnectedEvent is sent by the server just before forcefully closing the stream.
+type DisconnectedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Human-readable explanation of why the connection is being closed.
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Machine-readable code to help the client decide on reconnection strategy.
+	// Examples: "SESSION_EVICTED", "SERVER_SHUTDOWN", "INACTIVITY", "AUTH_EXPIRED".
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *DisconnectedEvent) Reset() {
+	*x = DisconnectedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisconnectedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectedEvent) ProtoMessage() {}
+
+func (x *DisconnectedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectedEvent.ProtoReflect.Descriptor instead.
+func (*DisconnectedEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DisconnectedEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DisconnectedEvent) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// NewMessageEvent contains a message that needs to be delivered to the client.
+type NewMessageEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The actual message object with content, metadata, and sender info.
+	Message *ThreadMessage `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *NewMessageEvent) Reset() {
+	*x = NewMessageEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewMessageEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewMessageEvent) ProtoMessage() {}
+
+func (x *NewMessageEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewMessageEvent.ProtoReflect.Descriptor instead.
+func (*NewMessageEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *NewMessageEvent) GetMessage() *ThreadMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type ThreadMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ThreadId  string      `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	From      *Peer       `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To        *Peer       `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	CreatedAt int64       `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	EditedAt  int64       `protobuf:"varint,6,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
+	Text      string      `protobuf:"bytes,7,opt,name=text,proto3" json:"text,omitempty"`
+	Type      MessageType `protobuf:"varint,8,opt,name=type,proto3,enum=webitel.im.api.delivery.v1.MessageType" json:"type,omitempty"`
+	// Types that are assignable to Content:
+	//	*ThreadMessage_Document
+	//	*ThreadMessage_Image
+	Content isThreadMessage_Content `protobuf_oneof:"content"`
+	// Free-form sender metadata. Values that have no JSON form arrive as strings.
+	Metadata *structpb.Struct `protobuf:"bytes,11,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// The message was forwarded from another thread.
+	IsForward bool `protobuf:"varint,12,opt,name=is_forward,json=isForward,proto3" json:"is_forward,omitempty"`
+}
+
+func (x *ThreadMessage) Reset() {
+	*x = ThreadMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThreadMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadMessage) ProtoMessage() {}
+
+func (x *ThreadMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadMessage.ProtoReflect.Descriptor instead.
+func (*ThreadMessage) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ThreadMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ThreadMessage) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *ThreadMessage) GetFrom() *Peer {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ThreadMessage) GetTo() *Peer {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ThreadMessage) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ThreadMessage) GetEditedAt() int64 {
+	if x != nil {
+		return x.EditedAt
+	}
+	return 0
+}
+
+func (x *ThreadMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ThreadMessage) GetType() MessageType {
+	if x != nil {
+		return x.Type
+	}
+	return MessageType_UNSPECIFIED_MESSAGE_TYPE
+}
+
+func (m *ThreadMessage) GetContent() isThreadMessage_Content {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (x *ThreadMessage) GetDocument() *Document {
+	if x, ok := x.GetContent().(*ThreadMessage_Document); ok {
+		return x.Document
+	}
+	return nil
+}
+
+func (x *ThreadMessage) GetImage() *Image {
+	if x, ok := x.GetContent().(*ThreadMessage_Image); ok {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *ThreadMessage) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ThreadMessage) GetIsForward() bool {
+	if x != nil {
+		return x.IsForward
+	}
+	return false
+}
+
+type isThreadMessage_Content interface {
+	isThreadMessage_Content()
+}
+
+type ThreadMessage_Document struct {
+	Document *Document `protobuf:"bytes,9,opt,name=document,proto3,oneof"`
+}
+
+type ThreadMessage_Image struct {
+	Image *Image `protobuf:"bytes,10,opt,name=image,proto3,oneof"`
+}
+
+func (*ThreadMessage_Document) isThreadMessage_Content() {}
+
+func (*ThreadMessage_Image) isThreadMessage_Content() {}
+
+type Identity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Issuer string `protobuf:"bytes,1,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Identity) Reset() {
+	*x = Identity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Identity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Identity) ProtoMessage() {}
+
+func (x *Identity) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Identity.ProtoReflect.Descriptor instead.
+func (*Identity) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Identity) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *Identity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Peer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Addressing: mandatory routing identifiers
+	//
+	// Types that are assignable to Kind:
+	//	*Peer_UserId
+	//	*Peer_ChatId
+	//	*Peer_ChannelId
+	//	*Peer_BotId
+	Kind isPeer_Kind `protobuf_oneof:"kind"`
+	// Identity: enriched user data
+	Identity *Identity `protobuf:"bytes,10,opt,name=identity,proto3" json:"identity,omitempty"`
+}
+
+func (x *Peer) Reset() {
+	*x = Peer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Peer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Peer) ProtoMessage() {}
+
+func (x *Peer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Peer.ProtoReflect.Descriptor instead.
+func (*Peer) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{36}
+}
+
+func (m *Peer) GetKind() isPeer_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (x *Peer) GetUserId() string {
+	if x, ok := x.GetKind().(*Peer_UserId); ok {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Peer) GetChatId() string {
+	if x, ok := x.GetKind().(*Peer_ChatId); ok {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *Peer) GetChannelId() string {
+	if x, ok := x.GetKind().(*Peer_ChannelId); ok {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *Peer) GetBotId() string {
+	if x, ok := x.GetKind().(*Peer_BotId); ok {
+		return x.BotId
+	}
+	return ""
+}
+
+func (x *Peer) GetIdentity() *Identity {
+	if x != nil {
+		return x.Identity
+	}
+	return nil
+}
+
+type isPeer_Kind interface {
+	isPeer_Kind()
+}
+
+type Peer_UserId struct {
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof"`
+}
+
+type Peer_ChatId struct {
+	ChatId string `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3,oneof"`
+}
+
+type Peer_ChannelId struct {
+	ChannelId string `protobuf:"bytes,3,opt,name=channel_id,json=channelId,proto3,oneof"`
+}
+
+type Peer_BotId struct {
+	BotId string `protobuf:"bytes,4,opt,name=bot_id,json=botId,proto3,oneof"`
+}
+
+func (*Peer_UserId) isPeer_Kind() {}
+
+func (*Peer_ChatId) isPeer_Kind() {}
+
+func (*Peer_ChannelId) isPeer_Kind() {}
+
+func (*Peer_BotId) isPeer_Kind() {}
+
+type Document struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url       string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	FileName  string `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	MimeType  string `protobuf:"bytes,4,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	SizeBytes int64  `protobuf:"varint,5,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+}
+
+func (x *Document) Reset() {
+	*x = Document{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Document) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Document) ProtoMessage() {}
+
+func (x *Document) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Document.ProtoReflect.Descriptor instead.
+func (*Document) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *Document) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Document) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Document) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *Document) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *Document) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+type Image struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url      string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	FileName string `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	MimeType string `protobuf:"bytes,4,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+}
+
+func (x *Image) Reset() {
+	*x = Image{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Image) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Image) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Image) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *Image) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+// AckEvent confirms the status of a specific event or message processing on the server.
+type AckEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Reference to the original event/message ID being acknowledged.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Result of the operation (Success, Error, etc.).
+	Status Status `protobuf:"varint,2,opt,name=status,proto3,enum=webitel.im.api.delivery.v1.Status" json:"status,omitempty"`
+	// Additional context for the result (e.g., reason for rejection, quota details).
+	Details []*anypb.Any `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`
+}
+
+func (x *AckEvent) Reset() {
+	*x = AckEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AckEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckEvent) ProtoMessage() {}
+
+func (x *AckEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckEvent.ProtoReflect.Descriptor instead.
+func (*AckEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *AckEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AckEvent) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *AckEvent) GetDetails() []*anypb.Any {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+// ErrorEvent communicates issues that occur during the stream's lifetime.
+type ErrorEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Machine-readable string constant (e.g., "INTERNAL_ERROR", "AUTH_EXPIRED").
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Human-readable message explaining the error.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Structured error metadata for programmatic handling.
+	Details []*anypb.Any `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`
+}
+
+func (x *ErrorEvent) Reset() {
+	*x = ErrorEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorEvent) ProtoMessage() {}
+
+func (x *ErrorEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorEvent.ProtoReflect.Descriptor instead.
+func (*ErrorEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ErrorEvent) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetDetails() []*anypb.Any {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+// PingEvent is used to verify the connection is still active.
+type PingEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Optional data that the server might include for RTT (Round Trip Time) calculation.
+	Echo string `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+}
+
+func (x *PingEvent) Reset() {
+	*x = PingEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PingEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingEvent) ProtoMessage() {}
+
+func (x *PingEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingEvent.ProtoReflect.Descriptor instead.
+func (*PingEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PingEvent) GetEcho() string {
+	if x != nil {
+		return x.Echo
+	}
+	return ""
+}
+
+// EncryptedEvent carries an end-to-end encrypted message. Only the recipient's own
+// key envelope is included; envelopes addressed to other participants never leave the server.
+type EncryptedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the message.
+	MessageId string `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Thread the message belongs to.
+	ThreadId string `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// Sender of the message.
+	From *Peer `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// Creation time (Unix ms).
+	CreatedAt int64 `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// The encrypted message body.
+	Ciphertext []byte `protobuf:"bytes,5,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// Reference to the content key.
+	KeyId string `protobuf:"bytes,6,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// Cipher suite the body was sealed with.
+	Algorithm string `protobuf:"bytes,7,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	// Content key wrapped for this recipient; empty when none was addressed to it.
+	WrappedKey string `protobuf:"bytes,8,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+}
+
+func (x *EncryptedEvent) Reset() {
+	*x = EncryptedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncryptedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptedEvent) ProtoMessage() {}
+
+func (x *EncryptedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptedEvent.ProtoReflect.Descriptor instead.
+func (*EncryptedEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *EncryptedEvent) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *EncryptedEvent) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *EncryptedEvent) GetFrom() *Peer {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *EncryptedEvent) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *EncryptedEvent) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *EncryptedEvent) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *EncryptedEvent) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *EncryptedEvent) GetWrappedKey() string {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return ""
+}
+
+// DeliveryDegradedEvent warns a slow client that events were dropped on its stream.
+type DeliveryDegradedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Events shed on this stream so far.
+	Dropped uint64 `protobuf:"varint,1,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	// Drop count that triggered the warning.
+	Threshold uint64 `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+}
+
+func (x *DeliveryDegradedEvent) Reset() {
+	*x = DeliveryDegradedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeliveryDegradedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryDegradedEvent) ProtoMessage() {}
+
+func (x *DeliveryDegradedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryDegradedEvent.ProtoReflect.Descriptor instead.
+func (*DeliveryDegradedEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DeliveryDegradedEvent) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *DeliveryDegradedEvent) GetThreshold() uint64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+// DomainPausedEvent announces a maintenance window for the client's domain.
+type DomainPausedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Planned end of the window (Unix ms).
+	Until int64 `protobuf:"varint,1,opt,name=until,proto3" json:"until,omitempty"`
+}
+
+func (x *DomainPausedEvent) Reset() {
+	*x = DomainPausedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DomainPausedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainPausedEvent) ProtoMessage() {}
+
+func (x *DomainPausedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainPausedEvent.ProtoReflect.Descriptor instead.
+func (*DomainPausedEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DomainPausedEvent) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+// DomainResumedEvent announces the end of a maintenance window.
+type DomainResumedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DomainResumedEvent) Reset() {
+	*x = DomainResumedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DomainResumedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainResumedEvent) ProtoMessage() {}
+
+func (x *DomainResumedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainResumedEvent.ProtoReflect.Descriptor instead.
+func (*DomainResumedEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{45}
+}
+
+// ReplayGapEvent tells a resuming client that some events after its cursor are gone.
+type ReplayGapEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Cursor the client resumed from.
+	Requested string `protobuf:"bytes,1,opt,name=requested,proto3" json:"requested,omitempty"`
+	// First cursor replayed after the gap; empty when nothing is replayed.
+	Oldest string `protobuf:"bytes,2,opt,name=oldest,proto3" json:"oldest,omitempty"`
+}
+
+func (x *ReplayGapEvent) Reset() {
+	*x = ReplayGapEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayGapEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayGapEvent) ProtoMessage() {}
+
+func (x *ReplayGapEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayGapEvent.ProtoReflect.Descriptor instead.
+func (*ReplayGapEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ReplayGapEvent) GetRequested() string {
+	if x != nil {
+		return x.Requested
+	}
+	return ""
+}
+
+func (x *ReplayGapEvent) GetOldest() string {
+	if x != nil {
+		return x.Oldest
+	}
+	return ""
+}
+
+// PresenceStatusEvent reports the availability of a contact the client watches.
+type PresenceStatusEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Contact whose status changed.
+	ContactId string `protobuf:"bytes,1,opt,name=contact_id,json=contactId,proto3" json:"contact_id,omitempty"`
+	// The new availability.
+	Status PresenceStatus `protobuf:"varint,2,opt,name=status,proto3,enum=webitel.im.api.delivery.v1.PresenceStatus" json:"status,omitempty"`
+}
+
+func (x *PresenceStatusEvent) Reset() {
+	*x = PresenceStatusEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PresenceStatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceStatusEvent) ProtoMessage() {}
+
+func (x *PresenceStatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceStatusEvent.ProtoReflect.Descriptor instead.
+func (*PresenceStatusEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *PresenceStatusEvent) GetContactId() string {
+	if x != nil {
+		return x.ContactId
+	}
+	return ""
+}
+
+func (x *PresenceStatusEvent) GetStatus() PresenceStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PresenceStatus_PRESENCE_STATUS_UNSPECIFIED
+}
+
+// ReactionEvent reports a single reaction change on a message.
+type ReactionEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Message the reaction belongs to.
+	MessageId string `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Thread of the message.
+	ThreadId string `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// The reaction itself, e.g. a unicode emoji.
+	Emoji string `protobuf:"bytes,3,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	// True when the reaction was added, false when it was removed.
+	Added bool `protobuf:"varint,4,opt,name=added,proto3" json:"added,omitempty"`
+	// The participant who reacted. When unresolved, the kind carries its internal ID.
+	Actor *Peer `protobuf:"bytes,5,opt,name=actor,proto3" json:"actor,omitempty"`
+}
+
+func (x *ReactionEvent) Reset() {
+	*x = ReactionEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReactionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionEvent) ProtoMessage() {}
+
+func (x *ReactionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionEvent.ProtoReflect.Descriptor instead.
+func (*ReactionEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ReactionEvent) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ReactionEvent) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *ReactionEvent) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ReactionEvent) GetAdded() bool {
+	if x != nil {
+		return x.Added
+	}
+	return false
+}
+
+func (x *ReactionEvent) GetActor() *Peer {
+	if x != nil {
+		return x.Actor
+	}
+	return nil
+}
+
+// MessageReadEvent is a read receipt: everything up to last_read_message_id was seen.
+type MessageReadEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Thread that was read.
+	ThreadId string `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// The participant who read the thread. When unresolved, the kind carries its internal ID.
+	Reader *Peer `protobuf:"bytes,2,opt,name=reader,proto3" json:"reader,omitempty"`
+	// ID of the newest message the reader has seen.
+	LastReadMessageId string `protobuf:"bytes,3,opt,name=last_read_message_id,json=lastReadMessageId,proto3" json:"last_read_message_id,omitempty"`
+}
+
+func (x *MessageReadEvent) Reset() {
+	*x = MessageReadEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageReadEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageReadEvent) ProtoMessage() {}
+
+func (x *MessageReadEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageReadEvent.ProtoReflect.Descriptor instead.
+func (*MessageReadEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *MessageReadEvent) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *MessageReadEvent) GetReader() *Peer {
+	if x != nil {
+		return x.Reader
+	}
+	return nil
+}
+
+func (x *MessageReadEvent) GetLastReadMessageId() string {
+	if x != nil {
+		return x.LastReadMessageId
+	}
+	return ""
+}
+
+// TypingEvent signals that a participant is composing a message.
+type TypingEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Thread the participant is typing in.
+	ThreadId string `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// The typing participant. When the peer could not be resolved, the kind carries its internal ID.
+	From *Peer `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	// Unix ms after which the indicator is stale.
+	ExpiresAt int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+}
+
+func (x *TypingEvent) Reset() {
+	*x = TypingEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypingEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypingEvent) ProtoMessage() {}
+
+func (x *TypingEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypingEvent.ProtoReflect.Descriptor instead.
+func (*TypingEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *TypingEvent) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *TypingEvent) GetFrom() *Peer {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TypingEvent) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// MessageUpdatedEvent replaces a previously delivered message with its edited revision.
+type MessageUpdatedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The edited message; edited_at is always set.
+	Message *ThreadMessage `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *MessageUpdatedEvent) Reset() {
+	*x = MessageUpdatedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageUpdatedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageUpdatedEvent) ProtoMessage() {}
+
+func (x *MessageUpdatedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageUpdatedEvent.ProtoReflect.Descriptor instead.
+func (*MessageUpdatedEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *MessageUpdatedEvent) GetMessage() *ThreadMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// MessageDeletedEvent tells the client to drop a message from its thread view.
+type MessageDeletedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the deleted message.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Thread the message belonged to.
+	ThreadId string `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+}
+
+func (x *MessageDeletedEvent) Reset() {
+	*x = MessageDeletedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_delivery_v1_delivery_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageDeletedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageDeletedEvent) ProtoMessage() {}
+
+func (x *MessageDeletedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_delivery_v1_delivery_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageDeletedEvent.ProtoReflect.Descriptor instead.
+func (*MessageDeletedEvent) Descriptor() ([]byte, []int) {
+	return file_api_delivery_v1_delivery_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *MessageDeletedEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MessageDeletedEvent) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+var File_api_delivery_v1_delivery_proto protoreflect.FileDescriptor
+
+var file_api_delivery_v1_delivery_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x61, 0x70, 0x69, 0x2f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2f, 0x76,
+	0x31, 0x2f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x1a, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x1a, 0x19, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e,
+	0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65,
+	0x6e, 0x2d, 0x6f, 0x70, 0x65, 0x6e, 0x61, 0x70, 0x69, 0x76, 0x32, 0x2f, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa0, 0x02, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x6c, 0x61, 0x73, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x6c,
+	0x61, 0x73, 0x74, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x73, 0x12,
+	0x25, 0x0a, 0x0e, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63,
+	0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x77, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72,
+	0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x46, 0x0a, 0x0b, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f,
+	0x6b, 0x69, 0x6e, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x25, 0x2e, 0x77, 0x65,
+	0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x69,
+	0x6e, 0x64, 0x52, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x70,
+	0x70, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x61, 0x70, 0x70, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0x31, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x7c, 0x0a, 0x17, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x77, 0x65,
+	0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0xc6, 0x01, 0x0a, 0x0a, 0x43, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c,
+	0x6c, 0x61, 0x73, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x64,
+	0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x12, 0x4a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74,
+	0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x22, 0xa3, 0x01, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75,
+	0x73, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x22, 0x53, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x44,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x22, 0x95, 0x02,
+	0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12,
+	0x44, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x28, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x65, 0x70, 0x52, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x6e, 0x65, 0x73, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x6e, 0x65, 0x73,
+	0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64,
+	0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x75, 0x6e, 0x72, 0x65,
+	0x61, 0x63, 0x68, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x10, 0x75, 0x6e, 0x72, 0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x6c, 0x65,
+	0x4e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0xbe, 0x01, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x79, 0x53, 0x74, 0x65, 0x70, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12,
+	0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x0e, 0x0a,
+	0x02, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x61, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x69, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75,
+	0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x22, 0x2c, 0x0a, 0x12, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x6f, 0x73, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x22,
+	0x2a, 0x0a, 0x0f, 0x49, 0x73, 0x4f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x31, 0x0a, 0x14, 0x43,
+	0x68, 0x65, 0x63, 0x6b, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73, 0x22, 0x5b,
+	0x0a, 0x15, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x73, 0x65,
+	0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x77, 0x65, 0x62,
+	0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65,
+	0x52, 0x09, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x70, 0x0a, 0x08, 0x50,
+	0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x46, 0x0a,
+	0x18, 0x47, 0x65, 0x74, 0x53, 0x6c, 0x6f, 0x77, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x88, 0x01, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x53, 0x6c, 0x6f,
+	0x77, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x77, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x48, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x77, 0x65, 0x62,
+	0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6c, 0x6f, 0x77, 0x44, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x52, 0x0a, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73,
+	0x22, 0x95, 0x03, 0x0a, 0x0c, 0x53, 0x6c, 0x6f, 0x77, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x5f, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x55, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x6e, 0x72, 0x69, 0x63, 0x68, 0x5f, 0x75,
+	0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x6e, 0x72, 0x69, 0x63, 0x68, 0x55,
+	0x73, 0x12, 0x15, 0x0a, 0x06, 0x68, 0x75, 0x62, 0x5f, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x68, 0x75, 0x62, 0x55, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x72, 0x73,
+	0x68, 0x61, 0x6c, 0x5f, 0x75, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6d, 0x61,
+	0x72, 0x73, 0x68, 0x61, 0x6c, 0x55, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x5f, 0x75, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x55, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x5f, 0x73, 0x74,
+	0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6c, 0x6f, 0x77, 0x65,
+	0x73, 0x74, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x75, 0x66, 0x66, 0x65,
+	0x72, 0x5f, 0x70, 0x65, 0x61, 0x6b, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x62, 0x75,
+	0x66, 0x66, 0x65, 0x72, 0x50, 0x65, 0x61, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x61, 0x74, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x61, 0x74, 0x22, 0x1c, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x44,
+	0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x67, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x44, 0x65, 0x70,
+	0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65,
+	0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22,
+	0xc2, 0x01, 0x0a, 0x10, 0x44, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x3f, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2b,
+	0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x70, 0x72,
+	0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x73, 0x65, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74,
+	0x53, 0x65, 0x65, 0x6e, 0x22, 0x19, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x68, 0x61, 0x70,
+	0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x59, 0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x68, 0x61, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x75,
+	0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x05, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x77, 0x65, 0x62,
+	0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x61, 0x70, 0x69, 0x6e, 0x67, 0x52,
+	0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xbe, 0x01, 0x0a, 0x0b, 0x53,
+	0x68, 0x61, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x64,
+	0x65, 0x6c, 0x61, 0x79, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x64,
+	0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6a, 0x69, 0x74, 0x74, 0x65, 0x72,
+	0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6a, 0x69, 0x74, 0x74, 0x65,
+	0x72, 0x4d, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x6d,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x4d, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x70, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x72, 0x65, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0xa9, 0x01, 0x0a, 0x10,
+	0x50, 0x75, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4a, 0x73, 0x6f, 0x6e,
+	0x12, 0x45, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x29, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x22, 0x56, 0x0a, 0x11, 0x50, 0x75, 0x73, 0x68, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x07,
+	0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e,
+	0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x4f,
+	0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x22,
+	0x38, 0x0a, 0x13, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x73, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x4a, 0x73, 0x6f, 0x6e, 0x22, 0x5f, 0x0a, 0x13, 0x49, 0x6d, 0x70,
+	0x6f, 0x72, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x48, 0x0a, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2e, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0xb8, 0x01, 0x0a, 0x12, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x25, 0x0a,
+	0x0e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x6c, 0x69, 0x76, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64,
+	0x4c, 0x69, 0x76, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x12, 0x47, 0x0a, 0x08,
+	0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b,
+	0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x72, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x22, 0x3f, 0x0a, 0x0f, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52,
+	0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x8f, 0x0e, 0x0a, 0x0b, 0x53, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x2c, 0x92, 0x41, 0x29, 0x32, 0x27, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x20,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x20, 0x6f, 0x66, 0x20, 0x74, 0x68,
+	0x65, 0x20, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x65, 0x64, 0x20, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x45, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e,
+	0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79,
+	0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x18, 0x15, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x12, 0x3c, 0x0a, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x18,
+	0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e,
+	0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x65, 0x62, 0x75, 0x67, 0x52, 0x05, 0x64,
+	0x65, 0x62, 0x75, 0x67, 0x12, 0x55, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e,
+	0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x5e, 0x0a, 0x12, 0x64,
+	0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65,
+	0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x11, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x52, 0x0a, 0x0d, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e,
+	0x4e, 0x65, 0x77, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48,
+	0x00, 0x52, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x43, 0x0a, 0x09, 0x61, 0x63, 0x6b, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x24, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e,
+	0x41, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x08, 0x61, 0x63, 0x6b, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x49, 0x0a, 0x0b, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x77, 0x65, 0x62, 0x69,
+	0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x48, 0x00, 0x52, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x46, 0x0a, 0x0a, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x69, 0x6e, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x70, 0x69,
+	0x6e, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x65, 0x0a, 0x15, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c,
+	0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x13, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x65,
+	0x0a, 0x15, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e,
+	0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x13, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x4c, 0x0a, 0x0c, 0x74, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x5f,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x77, 0x65,
+	0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x74, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x5c, 0x0a, 0x12, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x72,
+	0x65, 0x61, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x2c, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52,
+	0x10, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x52, 0x0a, 0x0e, 0x72, 0x65, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x77, 0x65, 0x62, 0x69,
+	0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0d, 0x72, 0x65, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x65, 0x0a, 0x15, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63,
+	0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69,
+	0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x13, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x56, 0x0a, 0x10,
+	0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x67, 0x61, 0x70, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c,
+	0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x47, 0x61, 0x70, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x47, 0x61, 0x70, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x5f, 0x0a, 0x13, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2d, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x48, 0x00, 0x52, 0x11, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x62, 0x0a, 0x14, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f,
+	0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x12, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x12, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73,
+	0x75, 0x6d, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x6b, 0x0a, 0x17, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x64, 0x65, 0x67, 0x72, 0x61, 0x64, 0x65, 0x64, 0x5f, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x77, 0x65, 0x62,
+	0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x44, 0x65, 0x67, 0x72, 0x61, 0x64, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52,
+	0x15, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x44, 0x65, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x55, 0x0a, 0x0f, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70,
+	0x74, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x2a, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x65,
+	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x09, 0x0a,
+	0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x36, 0x0a, 0x0a, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x44, 0x65, 0x62, 0x75, 0x67, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x68, 0x61, 0x70, 0x69, 0x6e,
+	0x67, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0e, 0x73, 0x68, 0x61, 0x70, 0x69, 0x6e, 0x67, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73,
+	0x22, 0xc1, 0x01, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x3f, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x42,
+	0x2f, 0x92, 0x41, 0x2c, 0x32, 0x2a, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x20,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x66, 0x75, 0x6c, 0x20, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x20, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x02, 0x6f, 0x6b, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x95, 0x01, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x44, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x2c, 0x92, 0x41, 0x29, 0x32,
+	0x27, 0x48, 0x75, 0x6d, 0x61, 0x6e, 0x2d, 0x72, 0x65, 0x61, 0x64, 0x61, 0x62, 0x6c, 0x65, 0x20,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x20, 0x66, 0x6f, 0x72, 0x20, 0x64, 0x69, 0x73, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x3a, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x26,
+	0x92, 0x41, 0x23, 0x32, 0x21, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x2d, 0x72, 0x65, 0x61,
+	0x64, 0x61, 0x62, 0x6c, 0x65, 0x20, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x20, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x7d, 0x0a, 0x0f,
+	0x4e, 0x65, 0x77, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x6a, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x29, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x68,
+	0x72, 0x65, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x25, 0x92, 0x41, 0x22,
+	0x32, 0x20, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x20, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x65, 0x64, 0x20, 0x76, 0x69, 0x61, 0x20, 0x74, 0x68, 0x65, 0x20, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x8f, 0x04, 0x0a, 0x0d,
+	0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74,
+	0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d,
+	0x12, 0x30, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77,
+	0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x52, 0x02,
+	0x74, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x64, 0x69, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x64, 0x69, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x12, 0x3b, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x27, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x42, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x24, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x39, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x33,
+	0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72,
+	0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x46, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x36, 0x0a,
+	0x08, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x73, 0x73,
+	0x75, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xc0, 0x01, 0x0a, 0x04, 0x50, 0x65, 0x65, 0x72, 0x12, 0x19,
+	0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x07, 0x63, 0x68, 0x61,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x63, 0x68,
+	0x61, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x06, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x40,
+	0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x24, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x85, 0x01, 0x0a, 0x08, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x69, 0x6d, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x22, 0x63, 0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6d, 0x65,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x69, 0x6d,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x22, 0x86, 0x01, 0x0a, 0x08, 0x41, 0x63, 0x6b, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x22, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2e,
+	0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x22, 0xd9,
+	0x01, 0x0a, 0x0a, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x34, 0x0a,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x20, 0x92, 0x41, 0x1d,
+	0x32, 0x1b, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x2d, 0x72, 0x65, 0x61, 0x64, 0x61, 0x62,
+	0x6c, 0x65, 0x20, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x12, 0x3b, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x21, 0x92, 0x41, 0x1e, 0x32, 0x1c, 0x48, 0x75, 0x6d, 0x61, 0x6e,
+	0x2d, 0x72, 0x65, 0x61, 0x64, 0x61, 0x62, 0x6c, 0x65, 0x20, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x58, 0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x42, 0x28, 0x92, 0x41, 0x25, 0x32, 0x23, 0x41, 0x64,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x20, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75,
+	0x72, 0x65, 0x64, 0x20, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x22, 0x3b, 0x0a, 0x09, 0x50, 0x69,
+	0x6e, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x1a, 0x92, 0x41, 0x17, 0x32, 0x15, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x20, 0x70, 0x69, 0x6e, 0x67, 0x20, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x22, 0x97, 0x02, 0x0a, 0x0e, 0x45, 0x6e, 0x63, 0x72,
+	0x79, 0x70, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x68, 0x72,
+	0x65, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x68,
+	0x72, 0x65, 0x61, 0x64, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69,
+	0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x0a,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x63,
+	0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6b,
+	0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65, 0x79,
+	0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d,
+	0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x4b, 0x65,
+	0x79, 0x22, 0x4f, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x44, 0x65, 0x67,
+	0x72, 0x61, 0x64, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x72,
+	0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x64, 0x72, 0x6f,
+	0x70, 0x70, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x22, 0x29, 0x0a, 0x11, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0x14, 0x0a,
+	0x12, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x22, 0x46, 0x0a, 0x0e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x47, 0x61, 0x70,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x6c, 0x64, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x6c, 0x64, 0x65, 0x73, 0x74, 0x22, 0x78, 0x0a, 0x13, 0x50,
+	0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x49,
+	0x64, 0x12, 0x42, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x2a, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xaf, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x68, 0x72, 0x65, 0x61,
+	0x64, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x6f, 0x6a, 0x69, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x6f, 0x6a, 0x69, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64,
+	0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12,
+	0x36, 0x0a, 0x05, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72,
+	0x52, 0x05, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x9a, 0x01, 0x0a, 0x10, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09,
+	0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x65, 0x62, 0x69,
+	0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x52, 0x06, 0x72, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x14, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x61, 0x64,
+	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x11, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x49, 0x64, 0x22, 0x7f, 0x0a, 0x0b, 0x54, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x64,
+	0x12, 0x34, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72,
+	0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x73, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0x5a, 0x0a, 0x13, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x43, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e,
+	0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69, 0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61,
+	0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x22, 0x62, 0x0a, 0x13, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x1e, 0x92, 0x41, 0x1b, 0x32, 0x19, 0x49, 0x44, 0x20, 0x6f, 0x66,
+	0x20, 0x74, 0x68, 0x65, 0x20, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x20, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65,
+	0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x68, 0x72,
+	0x65, 0x61, 0x64, 0x49, 0x64, 0x2a, 0x4e, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45, 0x58, 0x54, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08,
+	0x44, 0x4f, 0x43, 0x55, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x4d,
+	0x41, 0x47, 0x45, 0x10, 0x03, 0x2a, 0x65, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x55, 0x43, 0x43, 0x45,
+	0x53, 0x53, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x45, 0x4d, 0x50, 0x4f, 0x52, 0x41, 0x52,
+	0x59, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x45, 0x52,
+	0x4d, 0x41, 0x4e, 0x45, 0x4e, 0x54, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x03, 0x12, 0x0c,
+	0x0a, 0x08, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x04, 0x2a, 0x68, 0x0a, 0x0b,
+	0x50, 0x75, 0x73, 0x68, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x50,
+	0x55, 0x53, 0x48, 0x5f, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x55, 0x53,
+	0x48, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c,
+	0x50, 0x55, 0x53, 0x48, 0x5f, 0x44, 0x52, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x02, 0x12, 0x16,
+	0x0a, 0x12, 0x50, 0x55, 0x53, 0x48, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45,
+	0x43, 0x54, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x61, 0x0a, 0x0f, 0x44, 0x65, 0x70, 0x72, 0x65, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x44, 0x45, 0x50,
+	0x52, 0x45, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x44,
+	0x45, 0x50, 0x52, 0x45, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x57, 0x41, 0x52, 0x4e, 0x10,
+	0x01, 0x12, 0x16, 0x0a, 0x12, 0x44, 0x45, 0x50, 0x52, 0x45, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x10, 0x02, 0x2a, 0x81, 0x01, 0x0a, 0x0e, 0x50, 0x72,
+	0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x0a, 0x1b,
+	0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a,
+	0x0f, 0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x4f, 0x4e, 0x4c, 0x49, 0x4e, 0x45,
+	0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x41,
+	0x57, 0x41, 0x59, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x43,
+	0x45, 0x5f, 0x44, 0x4e, 0x44, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x52, 0x45, 0x53, 0x45,
+	0x4e, 0x43, 0x45, 0x5f, 0x4f, 0x46, 0x46, 0x4c, 0x49, 0x4e, 0x45, 0x10, 0x04, 0x2a, 0x48, 0x0a,
+	0x0d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x18,
+	0x0a, 0x14, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x49, 0x47, 0x48,
+	0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x4e, 0x4f, 0x52, 0x4d, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x07,
+	0x0a, 0x03, 0x4c, 0x4f, 0x57, 0x10, 0x03, 0x2a, 0xf4, 0x01, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x16, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b,
+	0x49, 0x4e, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10,
+	0x03, 0x12, 0x15, 0x0a, 0x11, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x54, 0x59, 0x50, 0x49, 0x4e, 0x47, 0x10, 0x04, 0x12, 0x1b, 0x0a, 0x17, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x5f, 0x52, 0x45, 0x43, 0x45,
+	0x49, 0x50, 0x54, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b,
+	0x49, 0x4e, 0x44, 0x5f, 0x52, 0x45, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x06, 0x12, 0x1e,
+	0x0a, 0x1a, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x50, 0x52, 0x45,
+	0x53, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x10, 0x07, 0x42, 0x82,
+	0x02, 0x0a, 0x1e, 0x63, 0x6f, 0x6d, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x69,
+	0x6d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x76,
+	0x31, 0x42, 0x0d, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x50, 0x01, 0x5a, 0x44, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77,
+	0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2f, 0x69, 0x6d, 0x2d, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67,
+	0x6f, 0x2f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2f, 0x76, 0x31, 0x3b, 0x64, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x76, 0x31, 0xa2, 0x02, 0x04, 0x57, 0x49, 0x41, 0x44, 0xaa,
+	0x02, 0x1a, 0x57, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x49, 0x6d, 0x2e, 0x41, 0x70, 0x69,
+	0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x1a, 0x57,
+	0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x5c, 0x49, 0x6d, 0x5c, 0x41, 0x70, 0x69, 0x5c, 0x44, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x26, 0x57, 0x65, 0x62, 0x69,
+	0x74, 0x65, 0x6c, 0x5c, 0x49, 0x6d, 0x5c, 0x41, 0x70, 0x69, 0x5c, 0x44, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x79, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0xea, 0x02, 0x1e, 0x57, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x3a, 0x3a, 0x49, 0x6d,
+	0x3a, 0x3a, 0x41, 0x70, 0x69, 0x3a, 0x3a, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x3a,
+	0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_delivery_v1_delivery_proto_rawDescOnce sync.Once
+	file_api_delivery_v1_delivery_proto_rawDescData = file_api_delivery_v1_delivery_proto_rawDesc
+)
+
+func file_api_delivery_v1_delivery_proto_rawDescGZIP() []byte {
+	file_api_delivery_v1_delivery_proto_rawDescOnce.Do(func() {
+		file_api_delivery_v1_delivery_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_delivery_v1_delivery_proto_rawDescData)
+	})
+	return file_api_delivery_v1_delivery_proto_rawDescData
+}
+
+var file_api_delivery_v1_delivery_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_api_delivery_v1_delivery_proto_msgTypes = make([]protoimpl.MessageInfo, 53)
+var file_api_delivery_v1_delivery_proto_goTypes = []interface{}{
+	(MessageType)(0),                    // 0: webitel.im.api.delivery.v1.MessageType
+	(Status)(0),                         // 1: webitel.im.api.delivery.v1.Status
+	(PushOutcome)(0),                    // 2: webitel.im.api.delivery.v1.PushOutcome
+	(DeprecationMode)(0),                // 3: webitel.im.api.delivery.v1.DeprecationMode
+	(PresenceStatus)(0),                 // 4: webitel.im.api.delivery.v1.PresenceStatus
+	(EventPriority)(0),                  // 5: webitel.im.api.delivery.v1.EventPriority
+	(EventKind)(0),                      // 6: webitel.im.api.delivery.v1.EventKind
+	(*StreamRequest)(nil),               // 7: webitel.im.api.delivery.v1.StreamRequest
+	(*ListConnectionsRequest)(nil),      // 8: webitel.im.api.delivery.v1.ListConnectionsRequest
+	(*ListConnectionsResponse)(nil),     // 9: webitel.im.api.delivery.v1.ListConnectionsResponse
+	(*Connection)(nil),                  // 10: webitel.im.api.delivery.v1.Connection
+	(*ConnectionMetadata)(nil),          // 11: webitel.im.api.delivery.v1.ConnectionMetadata
+	(*GetDeliveryStatusRequest)(nil),    // 12: webitel.im.api.delivery.v1.GetDeliveryStatusRequest
+	(*DeliveryStatus)(nil),              // 13: webitel.im.api.delivery.v1.DeliveryStatus
+	(*DeliveryStep)(nil),                // 14: webitel.im.api.delivery.v1.DeliveryStep
+	(*DisconnectRequest)(nil),           // 15: webitel.im.api.delivery.v1.DisconnectRequest
+	(*DisconnectResponse)(nil),          // 16: webitel.im.api.delivery.v1.DisconnectResponse
+	(*IsOnlineRequest)(nil),             // 17: webitel.im.api.delivery.v1.IsOnlineRequest
+	(*CheckPresenceRequest)(nil),        // 18: webitel.im.api.delivery.v1.CheckPresenceRequest
+	(*CheckPresenceResponse)(nil),       // 19: webitel.im.api.delivery.v1.CheckPresenceResponse
+	(*Presence)(nil),                    // 20: webitel.im.api.delivery.v1.Presence
+	(*GetSlowDeliveriesRequest)(nil),    // 21: webitel.im.api.delivery.v1.GetSlowDeliveriesRequest
+	(*GetSlowDeliveriesResponse)(nil),   // 22: webitel.im.api.delivery.v1.GetSlowDeliveriesResponse
+	(*SlowDelivery)(nil),                // 23: webitel.im.api.delivery.v1.SlowDelivery
+	(*GetDeprecationUsageRequest)(nil),  // 24: webitel.im.api.delivery.v1.GetDeprecationUsageRequest
+	(*GetDeprecationUsageResponse)(nil), // 25: webitel.im.api.delivery.v1.GetDeprecationUsageResponse
+	(*DeprecationUsage)(nil),            // 26: webitel.im.api.delivery.v1.DeprecationUsage
+	(*ListShapingRulesRequest)(nil),     // 27: webitel.im.api.delivery.v1.ListShapingRulesRequest
+	(*ListShapingRulesResponse)(nil),    // 28: webitel.im.api.delivery.v1.ListShapingRulesResponse
+	(*ShapingRule)(nil),                 // 29: webitel.im.api.delivery.v1.ShapingRule
+	(*PushEventRequest)(nil),            // 30: webitel.im.api.delivery.v1.PushEventRequest
+	(*PushEventResponse)(nil),           // 31: webitel.im.api.delivery.v1.PushEventResponse
+	(*ImportEventsRequest)(nil),         // 32: webitel.im.api.delivery.v1.ImportEventsRequest
+	(*ImportEventsSummary)(nil),         // 33: webitel.im.api.delivery.v1.ImportEventsSummary
+	(*ImportBatchSummary)(nil),          // 34: webitel.im.api.delivery.v1.ImportBatchSummary
+	(*ImportRejection)(nil),             // 35: webitel.im.api.delivery.v1.ImportRejection
+	(*ServerEvent)(nil),                 // 36: webitel.im.api.delivery.v1.ServerEvent
+	(*EventDebug)(nil),                  // 37: webitel.im.api.delivery.v1.EventDebug
+	(*ConnectedEvent)(nil),              // 38: webitel.im.api.delivery.v1.ConnectedEvent
+	(*DisconnectedEvent)(nil),           // 39: webitel.im.api.delivery.v1.DisconnectedEvent
+	(*NewMessageEvent)(nil),             // 40: webitel.im.api.delivery.v1.NewMessageEvent
+	(*ThreadMessage)(nil),               // 41: webitel.im.api.delivery.v1.ThreadMessage
+	(*Identity)(nil),                    // 42: webitel.im.api.delivery.v1.Identity
+	(*Peer)(nil),                        // 43: webitel.im.api.delivery.v1.Peer
+	(*Document)(nil),                    // 44: webitel.im.api.delivery.v1.Document
+	(*Image)(nil),                       // 45: webitel.im.api.delivery.v1.Image
+	(*AckEvent)(nil),                    // 46: webitel.im.api.delivery.v1.AckEvent
+	(*ErrorEvent)(nil),                  // 47: webitel.im.api.delivery.v1.ErrorEvent
+	(*PingEvent)(nil),                   // 48: webitel.im.api.delivery.v1.PingEvent
+	(*EncryptedEvent)(nil),              // 49: webitel.im.api.delivery.v1.EncryptedEvent
+	(*DeliveryDegradedEvent)(nil),       // 50: webitel.im.api.delivery.v1.DeliveryDegradedEvent
+	(*DomainPausedEvent)(nil),           // 51: webitel.im.api.delivery.v1.DomainPausedEvent
+	(*DomainResumedEvent)(nil),          // 52: webitel.im.api.delivery.v1.DomainResumedEvent
+	(*ReplayGapEvent)(nil),              // 53: webitel.im.api.delivery.v1.ReplayGapEvent
+	(*PresenceStatusEvent)(nil),         // 54: webitel.im.api.delivery.v1.PresenceStatusEvent
+	(*ReactionEvent)(nil),               // 55: webitel.im.api.delivery.v1.ReactionEvent
+	(*MessageReadEvent)(nil),            // 56: webitel.im.api.delivery.v1.MessageReadEvent
+	(*TypingEvent)(nil),                 // 57: webitel.im.api.delivery.v1.TypingEvent
+	(*MessageUpdatedEvent)(nil),         // 58: webitel.im.api.delivery.v1.MessageUpdatedEvent
+	(*MessageDeletedEvent)(nil),         // 59: webitel.im.api.delivery.v1.MessageDeletedEvent
+	(*structpb.Struct)(nil),             // 60: google.protobuf.Struct
+	(*anypb.Any)(nil),                   // 61: google.protobuf.Any
+}
+var file_api_delivery_v1_delivery_proto_depIdxs = []int32{
+	6,  // 0: webitel.im.api.delivery.v1.StreamRequest.event_kinds:type_name -> webitel.im.api.delivery.v1.EventKind
+	10, // 1: webitel.im.api.delivery.v1.ListConnectionsResponse.connections:type_name -> webitel.im.api.delivery.v1.Connection
+	11, // 2: webitel.im.api.delivery.v1.Connection.metadata:type_name -> webitel.im.api.delivery.v1.ConnectionMetadata
+	14, // 3: webitel.im.api.delivery.v1.DeliveryStatus.timeline:type_name -> webitel.im.api.delivery.v1.DeliveryStep
+	20, // 4: webitel.im.api.delivery.v1.CheckPresenceResponse.presences:type_name -> webitel.im.api.delivery.v1.Presence
+	23, // 5: webitel.im.api.delivery.v1.GetSlowDeliveriesResponse.deliveries:type_name -> webitel.im.api.delivery.v1.SlowDelivery
+	26, // 6: webitel.im.api.delivery.v1.GetDeprecationUsageResponse.features:type_name -> webitel.im.api.delivery.v1.DeprecationUsage
+	3,  // 7: webitel.im.api.delivery.v1.DeprecationUsage.mode:type_name -> webitel.im.api.delivery.v1.DeprecationMode
+	29, // 8: webitel.im.api.delivery.v1.ListShapingRulesResponse.rules:type_name -> webitel.im.api.delivery.v1.ShapingRule
+	5,  // 9: webitel.im.api.delivery.v1.PushEventRequest.priority:type_name -> webitel.im.api.delivery.v1.EventPriority
+	2,  // 10: webitel.im.api.delivery.v1.PushEventResponse.outcome:type_name -> webitel.im.api.delivery.v1.PushOutcome
+	34, // 11: webitel.im.api.delivery.v1.ImportEventsSummary.batches:type_name -> webitel.im.api.delivery.v1.ImportBatchSummary
+	35, // 12: webitel.im.api.delivery.v1.ImportBatchSummary.rejected:type_name -> webitel.im.api.delivery.v1.ImportRejection
+	5,  // 13: webitel.im.api.delivery.v1.ServerEvent.priority:type_name -> webitel.im.api.delivery.v1.EventPriority
+	37, // 14: webitel.im.api.delivery.v1.ServerEvent.debug:type_name -> webitel.im.api.delivery.v1.EventDebug
+	38, // 15: webitel.im.api.delivery.v1.ServerEvent.connected_event:type_name -> webitel.im.api.delivery.v1.ConnectedEvent
+	39, // 16: webitel.im.api.delivery.v1.ServerEvent.disconnected_event:type_name -> webitel.im.api.delivery.v1.DisconnectedEvent
+	40, // 17: webitel.im.api.delivery.v1.ServerEvent.message_event:type_name -> webitel.im.api.delivery.v1.NewMessageEvent
+	46, // 18: webitel.im.api.delivery.v1.ServerEvent.ack_event:type_name -> webitel.im.api.delivery.v1.AckEvent
+	47, // 19: webitel.im.api.delivery.v1.ServerEvent.error_event:type_name -> webitel.im.api.delivery.v1.ErrorEvent
+	48, // 20: webitel.im.api.delivery.v1.ServerEvent.ping_event:type_name -> webitel.im.api.delivery.v1.PingEvent
+	59, // 21: webitel.im.api.delivery.v1.ServerEvent.message_deleted_event:type_name -> webitel.im.api.delivery.v1.MessageDeletedEvent
+	58, // 22: webitel.im.api.delivery.v1.ServerEvent.message_updated_event:type_name -> webitel.im.api.delivery.v1.MessageUpdatedEvent
+	57, // 23: webitel.im.api.delivery.v1.ServerEvent.typing_event:type_name -> webitel.im.api.delivery.v1.TypingEvent
+	56, // 24: webitel.im.api.delivery.v1.ServerEvent.message_read_event:type_name -> webitel.im.api.delivery.v1.MessageReadEvent
+	55, // 25: webitel.im.api.delivery.v1.ServerEvent.reaction_event:type_name -> webitel.im.api.delivery.v1.ReactionEvent
+	54, // 26: webitel.im.api.delivery.v1.ServerEvent.presence_status_event:type_name -> webitel.im.api.delivery.v1.PresenceStatusEvent
+	53, // 27: webitel.im.api.delivery.v1.ServerEvent.replay_gap_event:type_name -> webitel.im.api.delivery.v1.ReplayGapEvent
+	51, // 28: webitel.im.api.delivery.v1.ServerEvent.domain_paused_event:type_name -> webitel.im.api.delivery.v1.DomainPausedEvent
+	52, // 29: webitel.im.api.delivery.v1.ServerEvent.domain_resumed_event:type_name -> webitel.im.api.delivery.v1.DomainResumedEvent
+	50, // 30: webitel.im.api.delivery.v1.ServerEvent.delivery_degraded_event:type_name -> webitel.im.api.delivery.v1.DeliveryDegradedEvent
+	49, // 31: webitel.im.api.delivery.v1.ServerEvent.encrypted_event:type_name -> webitel.im.api.delivery.v1.EncryptedEvent
+	41, // 32: webitel.im.api.delivery.v1.NewMessageEvent.message:type_name -> webitel.im.api.delivery.v1.ThreadMessage
+	43, // 33: webitel.im.api.delivery.v1.ThreadMessage.from:type_name -> webitel.im.api.delivery.v1.Peer
+	43, // 34: webitel.im.api.delivery.v1.ThreadMessage.to:type_name -> webitel.im.api.delivery.v1.Peer
+	0,  // 35: webitel.im.api.delivery.v1.ThreadMessage.type:type_name -> webitel.im.api.delivery.v1.MessageType
+	44, // 36: webitel.im.api.delivery.v1.ThreadMessage.document:type_name -> webitel.im.api.delivery.v1.Document
+	45, // 37: webitel.im.api.delivery.v1.ThreadMessage.image:type_name -> webitel.im.api.delivery.v1.Image
+	60, // 38: webitel.im.api.delivery.v1.ThreadMessage.metadata:type_name -> google.protobuf.Struct
+	42, // 39: webitel.im.api.delivery.v1.Peer.identity:type_name -> webitel.im.api.delivery.v1.Identity
+	1,  // 40: webitel.im.api.delivery.v1.AckEvent.status:type_name -> webitel.im.api.delivery.v1.Status
+	61, // 41: webitel.im.api.delivery.v1.AckEvent.details:type_name -> google.protobuf.Any
+	61, // 42: webitel.im.api.delivery.v1.ErrorEvent.details:type_name -> google.protobuf.Any
+	43, // 43: webitel.im.api.delivery.v1.EncryptedEvent.from:type_name -> webitel.im.api.delivery.v1.Peer
+	4,  // 44: webitel.im.api.delivery.v1.PresenceStatusEvent.status:type_name -> webitel.im.api.delivery.v1.PresenceStatus
+	43, // 45: webitel.im.api.delivery.v1.ReactionEvent.actor:type_name -> webitel.im.api.delivery.v1.Peer
+	43, // 46: webitel.im.api.delivery.v1.MessageReadEvent.reader:type_name -> webitel.im.api.delivery.v1.Peer
+	43, // 47: webitel.im.api.delivery.v1.TypingEvent.from:type_name -> webitel.im.api.delivery.v1.Peer
+	41, // 48: webitel.im.api.delivery.v1.MessageUpdatedEvent.message:type_name -> webitel.im.api.delivery.v1.ThreadMessage
+	49, // [49:49] is the sub-list for method output_type
+	49, // [49:49] is the sub-list for method input_type
+	49, // [49:49] is the sub-list for extension type_name
+	49, // [49:49] is the sub-list for extension extendee
+	0,  // [0:49] is the sub-list for field type_name
+}
+
+func init() { file_api_delivery_v1_delivery_proto_init() }
+func file_api_delivery_v1_delivery_proto_init() {
+	if File_api_delivery_v1_delivery_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_delivery_v1_delivery_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListConnectionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListConnectionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Connection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectionMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDeliveryStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeliveryStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeliveryStep); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisconnectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisconnectResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IsOnlineRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckPresenceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckPresenceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Presence); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSlowDeliveriesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSlowDeliveriesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlowDelivery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDeprecationUsageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDeprecationUsageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeprecationUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListShapingRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListShapingRulesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShapingRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushEventRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1514,7 +5421,67 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushEventResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportEventsSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportBatchSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportRejection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ServerEvent); i {
 			case 0:
 				return &v.state
@@ -1526,7 +5493,19 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventDebug); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ConnectedEvent); i {
 			case 0:
 				return &v.state
@@ -1538,7 +5517,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DisconnectedEvent); i {
 			case 0:
 				return &v.state
@@ -1550,7 +5529,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NewMessageEvent); i {
 			case 0:
 				return &v.state
@@ -1562,7 +5541,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ThreadMessage); i {
 			case 0:
 				return &v.state
@@ -1574,7 +5553,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Identity); i {
 			case 0:
 				return &v.state
@@ -1586,7 +5565,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Peer); i {
 			case 0:
 				return &v.state
@@ -1598,7 +5577,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Document); i {
 			case 0:
 				return &v.state
@@ -1610,7 +5589,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Image); i {
 			case 0:
 				return &v.state
@@ -1622,7 +5601,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AckEvent); i {
 			case 0:
 				return &v.state
@@ -1634,7 +5613,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ErrorEvent); i {
 			case 0:
 				return &v.state
@@ -1646,7 +5625,7 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
-		file_api_delivery_v1_delivery_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_api_delivery_v1_delivery_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PingEvent); i {
 			case 0:
 				return &v.state
@@ -1658,20 +5637,163 @@ func file_api_delivery_v1_delivery_proto_init() {
 				return nil
 			}
 		}
+		file_api_delivery_v1_delivery_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncryptedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeliveryDegradedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DomainPausedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DomainResumedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayGapEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresenceStatusEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReactionEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageReadEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypingEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageUpdatedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_delivery_v1_delivery_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageDeletedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_api_delivery_v1_delivery_proto_msgTypes[1].OneofWrappers = []interface{}{
+	file_api_delivery_v1_delivery_proto_msgTypes[29].OneofWrappers = []interface{}{
 		(*ServerEvent_ConnectedEvent)(nil),
 		(*ServerEvent_DisconnectedEvent)(nil),
 		(*ServerEvent_MessageEvent)(nil),
 		(*ServerEvent_AckEvent)(nil),
 		(*ServerEvent_ErrorEvent)(nil),
 		(*ServerEvent_PingEvent)(nil),
+		(*ServerEvent_MessageDeletedEvent)(nil),
+		(*ServerEvent_MessageUpdatedEvent)(nil),
+		(*ServerEvent_TypingEvent)(nil),
+		(*ServerEvent_MessageReadEvent)(nil),
+		(*ServerEvent_ReactionEvent)(nil),
+		(*ServerEvent_PresenceStatusEvent)(nil),
+		(*ServerEvent_ReplayGapEvent)(nil),
+		(*ServerEvent_DomainPausedEvent)(nil),
+		(*ServerEvent_DomainResumedEvent)(nil),
+		(*ServerEvent_DeliveryDegradedEvent)(nil),
+		(*ServerEvent_EncryptedEvent)(nil),
 	}
-	file_api_delivery_v1_delivery_proto_msgTypes[5].OneofWrappers = []interface{}{
+	file_api_delivery_v1_delivery_proto_msgTypes[34].OneofWrappers = []interface{}{
 		(*ThreadMessage_Document)(nil),
 		(*ThreadMessage_Image)(nil),
 	}
-	file_api_delivery_v1_delivery_proto_msgTypes[7].OneofWrappers = []interface{}{
+	file_api_delivery_v1_delivery_proto_msgTypes[36].OneofWrappers = []interface{}{
 		(*Peer_UserId)(nil),
 		(*Peer_ChatId)(nil),
 		(*Peer_ChannelId)(nil),
@@ -1682,8 +5804,8 @@ func file_api_delivery_v1_delivery_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_delivery_v1_delivery_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   13,
+			NumEnums:      7,
+			NumMessages:   53,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
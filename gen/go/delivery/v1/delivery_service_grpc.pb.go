@@ -19,7 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Delivery_Stream_FullMethodName = "/webitel.im.api.delivery.v1.Delivery/Stream"
+	Delivery_Stream_FullMethodName            = "/webitel.im.api.delivery.v1.Delivery/Stream"
+	Delivery_ListConnections_FullMethodName   = "/webitel.im.api.delivery.v1.Delivery/ListConnections"
+	Delivery_GetDeliveryStatus_FullMethodName = "/webitel.im.api.delivery.v1.Delivery/GetDeliveryStatus"
 )
 
 // DeliveryClient is the client API for Delivery service.
@@ -34,6 +36,13 @@ type DeliveryClient interface {
 	// The client sends a StreamRequest to subscribe, and the server responds with
 	// a continuous stream of ServerEvents until the connection is closed.
 	Stream(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ServerEvent], error)
+	// ListConnections describes the sessions a user holds on the serving node.
+	// Callers may list their own sessions; listing another user's requires service scope.
+	ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ListConnectionsResponse, error)
+	// GetDeliveryStatus reports what happened to a message: mailbox and per-session outcomes,
+	// never its content. Recipients see only their own leg; service scope sees every leg.
+	// With cluster set, the serving node asks every other delivery node and merges the answers.
+	GetDeliveryStatus(ctx context.Context, in *GetDeliveryStatusRequest, opts ...grpc.CallOption) (*DeliveryStatus, error)
 }
 
 type deliveryClient struct {
@@ -63,6 +72,26 @@ func (c *deliveryClient) Stream(ctx context.Context, in *StreamRequest, opts ...
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Delivery_StreamClient = grpc.ServerStreamingClient[ServerEvent]
 
+func (c *deliveryClient) ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ListConnectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConnectionsResponse)
+	err := c.cc.Invoke(ctx, Delivery_ListConnections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryClient) GetDeliveryStatus(ctx context.Context, in *GetDeliveryStatusRequest, opts ...grpc.CallOption) (*DeliveryStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryStatus)
+	err := c.cc.Invoke(ctx, Delivery_GetDeliveryStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DeliveryServer is the server API for Delivery service.
 // All implementations must embed UnimplementedDeliveryServer
 // for forward compatibility.
@@ -75,6 +104,13 @@ type DeliveryServer interface {
 	// The client sends a StreamRequest to subscribe, and the server responds with
 	// a continuous stream of ServerEvents until the connection is closed.
 	Stream(*StreamRequest, grpc.ServerStreamingServer[ServerEvent]) error
+	// ListConnections describes the sessions a user holds on the serving node.
+	// Callers may list their own sessions; listing another user's requires service scope.
+	ListConnections(context.Context, *ListConnectionsRequest) (*ListConnectionsResponse, error)
+	// GetDeliveryStatus reports what happened to a message: mailbox and per-session outcomes,
+	// never its content. Recipients see only their own leg; service scope sees every leg.
+	// With cluster set, the serving node asks every other delivery node and merges the answers.
+	GetDeliveryStatus(context.Context, *GetDeliveryStatusRequest) (*DeliveryStatus, error)
 	mustEmbedUnimplementedDeliveryServer()
 }
 
@@ -88,6 +124,12 @@ type UnimplementedDeliveryServer struct{}
 func (UnimplementedDeliveryServer) Stream(*StreamRequest, grpc.ServerStreamingServer[ServerEvent]) error {
 	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
 }
+func (UnimplementedDeliveryServer) ListConnections(context.Context, *ListConnectionsRequest) (*ListConnectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConnections not implemented")
+}
+func (UnimplementedDeliveryServer) GetDeliveryStatus(context.Context, *GetDeliveryStatusRequest) (*DeliveryStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryStatus not implemented")
+}
 func (UnimplementedDeliveryServer) mustEmbedUnimplementedDeliveryServer() {}
 func (UnimplementedDeliveryServer) testEmbeddedByValue()                  {}
 
@@ -120,13 +162,58 @@ func _Delivery_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Delivery_StreamServer = grpc.ServerStreamingServer[ServerEvent]
 
+func _Delivery_ListConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConnectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServer).ListConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Delivery_ListConnections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServer).ListConnections(ctx, req.(*ListConnectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Delivery_GetDeliveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeliveryStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServer).GetDeliveryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Delivery_GetDeliveryStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServer).GetDeliveryStatus(ctx, req.(*GetDeliveryStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Delivery_ServiceDesc is the grpc.ServiceDesc for Delivery service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var Delivery_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "webitel.im.api.delivery.v1.Delivery",
 	HandlerType: (*DeliveryServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListConnections",
+			Handler:    _Delivery_ListConnections_Handler,
+		},
+		{
+			MethodName: "GetDeliveryStatus",
+			Handler:    _Delivery_GetDeliveryStatus_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Stream",
@@ -136,3 +223,405 @@ var Delivery_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "api/delivery/v1/delivery_service.proto",
 }
+
+const (
+	DeliveryAdmin_PushEvent_FullMethodName           = "/webitel.im.api.delivery.v1.DeliveryAdmin/PushEvent"
+	DeliveryAdmin_Disconnect_FullMethodName          = "/webitel.im.api.delivery.v1.DeliveryAdmin/Disconnect"
+	DeliveryAdmin_IsOnline_FullMethodName            = "/webitel.im.api.delivery.v1.DeliveryAdmin/IsOnline"
+	DeliveryAdmin_CheckPresence_FullMethodName       = "/webitel.im.api.delivery.v1.DeliveryAdmin/CheckPresence"
+	DeliveryAdmin_GetSlowDeliveries_FullMethodName   = "/webitel.im.api.delivery.v1.DeliveryAdmin/GetSlowDeliveries"
+	DeliveryAdmin_GetDeprecationUsage_FullMethodName = "/webitel.im.api.delivery.v1.DeliveryAdmin/GetDeprecationUsage"
+	DeliveryAdmin_ListShapingRules_FullMethodName    = "/webitel.im.api.delivery.v1.DeliveryAdmin/ListShapingRules"
+	DeliveryAdmin_ImportEvents_FullMethodName        = "/webitel.im.api.delivery.v1.DeliveryAdmin/ImportEvents"
+)
+
+// DeliveryAdminClient is the client API for DeliveryAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ------------------------------------------------------------------------------
+// DELIVERY ADMIN SERVICE
+// ------------------------------------------------------------------------------
+// DeliveryAdmin exposes service-to-service operations. Every method requires a
+// caller authenticated with service scope.
+type DeliveryAdminClient interface {
+	// PushEvent injects a one-off event for a user connected to the serving node,
+	// bypassing the message bus. The event is neither enriched nor re-published.
+	PushEvent(ctx context.Context, in *PushEventRequest, opts ...grpc.CallOption) (*PushEventResponse, error)
+	// Disconnect force-closes one session, or every session of the user on the serving node.
+	// Closed streams receive a final DisconnectedEvent carrying the reason.
+	Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*DisconnectResponse, error)
+	// IsOnline reports whether a user is reachable, e.g. before sending a push notification.
+	IsOnline(ctx context.Context, in *IsOnlineRequest, opts ...grpc.CallOption) (*Presence, error)
+	// CheckPresence is the batched IsOnline for up to 500 users; results follow the request order.
+	CheckPresence(ctx context.Context, in *CheckPresenceRequest, opts ...grpc.CallOption) (*CheckPresenceResponse, error)
+	// GetSlowDeliveries returns the slowest deliveries of one closed minute on the serving node,
+	// with their per-stage latency. NotFound for open, unknown or expired windows.
+	GetSlowDeliveries(ctx context.Context, in *GetSlowDeliveriesRequest, opts ...grpc.CallOption) (*GetSlowDeliveriesResponse, error)
+	// GetDeprecationUsage reports how often the serving node saw each deprecated feature
+	// since it started, so legacy surfaces can be removed on data.
+	GetDeprecationUsage(ctx context.Context, in *GetDeprecationUsageRequest, opts ...grpc.CallOption) (*GetDeprecationUsageResponse, error)
+	// ListShapingRules returns the delivery shaping rules in effect on the serving node,
+	// so testers can tell shaped latency from real latency.
+	ListShapingRules(ctx context.Context, in *ListShapingRulesRequest, opts ...grpc.CallOption) (*ListShapingRulesResponse, error)
+	// ImportEvents injects migrated history, streamed as batches of pre-enriched records.
+	// Imported events are neither enriched nor re-published; the reply summarises each batch.
+	ImportEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportEventsRequest, ImportEventsSummary], error)
+}
+
+type deliveryAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDeliveryAdminClient(cc grpc.ClientConnInterface) DeliveryAdminClient {
+	return &deliveryAdminClient{cc}
+}
+
+func (c *deliveryAdminClient) PushEvent(ctx context.Context, in *PushEventRequest, opts ...grpc.CallOption) (*PushEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushEventResponse)
+	err := c.cc.Invoke(ctx, DeliveryAdmin_PushEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryAdminClient) Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*DisconnectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisconnectResponse)
+	err := c.cc.Invoke(ctx, DeliveryAdmin_Disconnect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryAdminClient) IsOnline(ctx context.Context, in *IsOnlineRequest, opts ...grpc.CallOption) (*Presence, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Presence)
+	err := c.cc.Invoke(ctx, DeliveryAdmin_IsOnline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryAdminClient) CheckPresence(ctx context.Context, in *CheckPresenceRequest, opts ...grpc.CallOption) (*CheckPresenceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckPresenceResponse)
+	err := c.cc.Invoke(ctx, DeliveryAdmin_CheckPresence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryAdminClient) GetSlowDeliveries(ctx context.Context, in *GetSlowDeliveriesRequest, opts ...grpc.CallOption) (*GetSlowDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSlowDeliveriesResponse)
+	err := c.cc.Invoke(ctx, DeliveryAdmin_GetSlowDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryAdminClient) GetDeprecationUsage(ctx context.Context, in *GetDeprecationUsageRequest, opts ...grpc.CallOption) (*GetDeprecationUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeprecationUsageResponse)
+	err := c.cc.Invoke(ctx, DeliveryAdmin_GetDeprecationUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryAdminClient) ListShapingRules(ctx context.Context, in *ListShapingRulesRequest, opts ...grpc.CallOption) (*ListShapingRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListShapingRulesResponse)
+	err := c.cc.Invoke(ctx, DeliveryAdmin_ListShapingRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryAdminClient) ImportEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportEventsRequest, ImportEventsSummary], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DeliveryAdmin_ServiceDesc.Streams[0], DeliveryAdmin_ImportEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportEventsRequest, ImportEventsSummary]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryAdmin_ImportEventsClient = grpc.ClientStreamingClient[ImportEventsRequest, ImportEventsSummary]
+
+// DeliveryAdminServer is the server API for DeliveryAdmin service.
+// All implementations must embed UnimplementedDeliveryAdminServer
+// for forward compatibility.
+//
+// ------------------------------------------------------------------------------
+// DELIVERY ADMIN SERVICE
+// ------------------------------------------------------------------------------
+// DeliveryAdmin exposes service-to-service operations. Every method requires a
+// caller authenticated with service scope.
+type DeliveryAdminServer interface {
+	// PushEvent injects a one-off event for a user connected to the serving node,
+	// bypassing the message bus. The event is neither enriched nor re-published.
+	PushEvent(context.Context, *PushEventRequest) (*PushEventResponse, error)
+	// Disconnect force-closes one session, or every session of the user on the serving node.
+	// Closed streams receive a final DisconnectedEvent carrying the reason.
+	Disconnect(context.Context, *DisconnectRequest) (*DisconnectResponse, error)
+	// IsOnline reports whether a user is reachable, e.g. before sending a push notification.
+	IsOnline(context.Context, *IsOnlineRequest) (*Presence, error)
+	// CheckPresence is the batched IsOnline for up to 500 users; results follow the request order.
+	CheckPresence(context.Context, *CheckPresenceRequest) (*CheckPresenceResponse, error)
+	// GetSlowDeliveries returns the slowest deliveries of one closed minute on the serving node,
+	// with their per-stage latency. NotFound for open, unknown or expired windows.
+	GetSlowDeliveries(context.Context, *GetSlowDeliveriesRequest) (*GetSlowDeliveriesResponse, error)
+	// GetDeprecationUsage reports how often the serving node saw each deprecated feature
+	// since it started, so legacy surfaces can be removed on data.
+	GetDeprecationUsage(context.Context, *GetDeprecationUsageRequest) (*GetDeprecationUsageResponse, error)
+	// ListShapingRules returns the delivery shaping rules in effect on the serving node,
+	// so testers can tell shaped latency from real latency.
+	ListShapingRules(context.Context, *ListShapingRulesRequest) (*ListShapingRulesResponse, error)
+	// ImportEvents injects migrated history, streamed as batches of pre-enriched records.
+	// Imported events are neither enriched nor re-published; the reply summarises each batch.
+	ImportEvents(grpc.ClientStreamingServer[ImportEventsRequest, ImportEventsSummary]) error
+	mustEmbedUnimplementedDeliveryAdminServer()
+}
+
+// UnimplementedDeliveryAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDeliveryAdminServer struct{}
+
+func (UnimplementedDeliveryAdminServer) PushEvent(context.Context, *PushEventRequest) (*PushEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushEvent not implemented")
+}
+func (UnimplementedDeliveryAdminServer) Disconnect(context.Context, *DisconnectRequest) (*DisconnectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Disconnect not implemented")
+}
+func (UnimplementedDeliveryAdminServer) IsOnline(context.Context, *IsOnlineRequest) (*Presence, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsOnline not implemented")
+}
+func (UnimplementedDeliveryAdminServer) CheckPresence(context.Context, *CheckPresenceRequest) (*CheckPresenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPresence not implemented")
+}
+func (UnimplementedDeliveryAdminServer) GetSlowDeliveries(context.Context, *GetSlowDeliveriesRequest) (*GetSlowDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSlowDeliveries not implemented")
+}
+func (UnimplementedDeliveryAdminServer) GetDeprecationUsage(context.Context, *GetDeprecationUsageRequest) (*GetDeprecationUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeprecationUsage not implemented")
+}
+func (UnimplementedDeliveryAdminServer) ListShapingRules(context.Context, *ListShapingRulesRequest) (*ListShapingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShapingRules not implemented")
+}
+func (UnimplementedDeliveryAdminServer) ImportEvents(grpc.ClientStreamingServer[ImportEventsRequest, ImportEventsSummary]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportEvents not implemented")
+}
+func (UnimplementedDeliveryAdminServer) mustEmbedUnimplementedDeliveryAdminServer() {}
+func (UnimplementedDeliveryAdminServer) testEmbeddedByValue()                       {}
+
+// UnsafeDeliveryAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DeliveryAdminServer will
+// result in compilation errors.
+type UnsafeDeliveryAdminServer interface {
+	mustEmbedUnimplementedDeliveryAdminServer()
+}
+
+func RegisterDeliveryAdminServer(s grpc.ServiceRegistrar, srv DeliveryAdminServer) {
+	// If the following call pancis, it indicates UnimplementedDeliveryAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DeliveryAdmin_ServiceDesc, srv)
+}
+
+func _DeliveryAdmin_PushEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryAdminServer).PushEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryAdmin_PushEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryAdminServer).PushEvent(ctx, req.(*PushEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryAdmin_Disconnect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryAdminServer).Disconnect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryAdmin_Disconnect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryAdminServer).Disconnect(ctx, req.(*DisconnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryAdmin_IsOnline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsOnlineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryAdminServer).IsOnline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryAdmin_IsOnline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryAdminServer).IsOnline(ctx, req.(*IsOnlineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryAdmin_CheckPresence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPresenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryAdminServer).CheckPresence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryAdmin_CheckPresence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryAdminServer).CheckPresence(ctx, req.(*CheckPresenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryAdmin_GetSlowDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSlowDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryAdminServer).GetSlowDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryAdmin_GetSlowDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryAdminServer).GetSlowDeliveries(ctx, req.(*GetSlowDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryAdmin_GetDeprecationUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeprecationUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryAdminServer).GetDeprecationUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryAdmin_GetDeprecationUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryAdminServer).GetDeprecationUsage(ctx, req.(*GetDeprecationUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryAdmin_ListShapingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListShapingRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryAdminServer).ListShapingRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryAdmin_ListShapingRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryAdminServer).ListShapingRules(ctx, req.(*ListShapingRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryAdmin_ImportEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliveryAdminServer).ImportEvents(&grpc.GenericServerStream[ImportEventsRequest, ImportEventsSummary]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryAdmin_ImportEventsServer = grpc.ClientStreamingServer[ImportEventsRequest, ImportEventsSummary]
+
+// DeliveryAdmin_ServiceDesc is the grpc.ServiceDesc for DeliveryAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DeliveryAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "webitel.im.api.delivery.v1.DeliveryAdmin",
+	HandlerType: (*DeliveryAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PushEvent",
+			Handler:    _DeliveryAdmin_PushEvent_Handler,
+		},
+		{
+			MethodName: "Disconnect",
+			Handler:    _DeliveryAdmin_Disconnect_Handler,
+		},
+		{
+			MethodName: "IsOnline",
+			Handler:    _DeliveryAdmin_IsOnline_Handler,
+		},
+		{
+			MethodName: "CheckPresence",
+			Handler:    _DeliveryAdmin_CheckPresence_Handler,
+		},
+		{
+			MethodName: "GetSlowDeliveries",
+			Handler:    _DeliveryAdmin_GetSlowDeliveries_Handler,
+		},
+		{
+			MethodName: "GetDeprecationUsage",
+			Handler:    _DeliveryAdmin_GetDeprecationUsage_Handler,
+		},
+		{
+			MethodName: "ListShapingRules",
+			Handler:    _DeliveryAdmin_ListShapingRules_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportEvents",
+			Handler:       _DeliveryAdmin_ImportEvents_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/delivery/v1/delivery_service.proto",
+}
@@ -5,6 +5,9 @@ import (
 
 	imauth "github.com/webitel/im-delivery-service/infra/client/im-auth"
 	imcontact "github.com/webitel/im-delivery-service/infra/client/im-contact"
+	imdelivery "github.com/webitel/im-delivery-service/infra/client/im-delivery"
+	imgroup "github.com/webitel/im-delivery-service/infra/client/im-group"
+	"github.com/webitel/im-delivery-service/internal/service"
 	"go.uber.org/fx"
 )
 
@@ -14,6 +17,12 @@ var Module = fx.Module(
 	// [CONSTRUCTOR] Provides the resilient contact client
 	fx.Provide(imcontact.New),
 	fx.Provide(imauth.New),
+	// [CLUSTER_QUERY] Direct per-node clients of the other delivery instances
+	fx.Provide(fx.Annotate(
+		imdelivery.New,
+		fx.As(fx.Self()),
+		fx.As(new(service.ClusterQuerier)),
+	)),
 
 	// [LIFECYCLE] Ensures the gRPC connection pool is closed gracefully on app shutdown
 	fx.Invoke(func(lc fx.Lifecycle, client *imcontact.Client) {
@@ -31,4 +40,35 @@ var Module = fx.Module(
 			},
 		})
 	}),
+
+	fx.Invoke(func(lc fx.Lifecycle, peers *imdelivery.Peers) {
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				return peers.Close()
+			},
+		})
+	}),
+)
+
+// GroupsModule provides the group directory client. It is opt-in: without it the
+// enricher's GroupClient and ChannelClient stay unset and those peers pass through,
+// and group-addressed messages without a recipients list cannot be expanded.
+var GroupsModule = fx.Module(
+	"webitel_clients_groups",
+
+	fx.Provide(fx.Annotate(
+		imgroup.New,
+		fx.As(fx.Self()),
+		fx.As(new(service.GroupClient)),
+		fx.As(new(service.ChannelClient)),
+		fx.As(new(service.GroupMembers)),
+	)),
+
+	fx.Invoke(func(lc fx.Lifecycle, client *imgroup.Client) {
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				return client.Close()
+			},
+		})
+	}),
 )
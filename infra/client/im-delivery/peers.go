@@ -0,0 +1,190 @@
+package imdelivery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/webitel-go-kit/infra/discovery"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Interface guard
+var _ service.ClusterQuerier = (*Peers)(nil)
+
+// peerQueryTimeout bounds each node's answer; slower nodes are reported unreachable.
+const peerQueryTimeout = 3 * time.Second
+
+// Peers asks the other delivery nodes directly, one connection per instance.
+//
+// [NODE_QUERY]
+// Unlike the load-balanced clients in this package, a cluster query must reach every
+// instance, so Peers lists them from discovery on each call and dials their endpoints.
+// Connections to instances that left are closed on the next call.
+type Peers struct {
+	logger    *slog.Logger
+	discovery discovery.DiscoveryProvider
+	selfID    string
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn // Instance ID -> connection
+}
+
+func New(logger *slog.Logger, dp discovery.DiscoveryProvider, cfg *config.Config) *Peers {
+	return &Peers{
+		logger:    logger,
+		discovery: dp,
+		selfID:    cfg.Service.ID,
+		conns:     make(map[string]*grpc.ClientConn),
+	}
+}
+
+// QueryDeliveryStatus asks every other node, concurrently, for its view of the message.
+// [AUTH_FORWARDING] The caller's metadata is forwarded as-is, like AuthService.Inspect,
+// so each node filters legs for the same viewer.
+func (p *Peers) QueryDeliveryStatus(ctx context.Context, messageID uuid.UUID) ([]service.PeerDeliveryStatus, error) {
+	instances, err := p.discovery.GetService(ctx, model.ServiceName)
+	if err != nil {
+		return nil, fmt.Errorf("[im-delivery-peers] list instances: %w", err)
+	}
+	clients := p.clientsFor(instances)
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	res := make([]service.PeerDeliveryStatus, len(clients))
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Go(func() {
+			res[i] = p.query(ctx, c, messageID)
+		})
+	}
+	wg.Wait()
+	return res, nil
+}
+
+type peerClient struct {
+	nodeID string
+	api    impb.DeliveryClient
+	err    error // Dial failure
+}
+
+func (p *Peers) query(ctx context.Context, c peerClient, messageID uuid.UUID) service.PeerDeliveryStatus {
+	res := service.PeerDeliveryStatus{NodeID: c.nodeID}
+	if c.err != nil {
+		res.Err = c.err
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, peerQueryTimeout)
+	defer cancel()
+
+	st, err := c.api.GetDeliveryStatus(ctx, &impb.GetDeliveryStatusRequest{MessageId: messageID.String()})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		// Answered, but holds nothing visible to the viewer.
+	case err != nil:
+		p.logger.Warn("PEER_QUERY_FAILED", slog.String("node_id", c.nodeID), slog.Any("err", err))
+		res.Err = err
+	default:
+		res.Status = unmapDeliveryStatus(st)
+	}
+	return res
+}
+
+// clientsFor returns a client per instance other than this node, dialing new ones and
+// closing those of instances that are gone.
+func (p *Peers) clientsFor(instances []*discovery.ServiceInstance) []peerClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(instances))
+	res := make([]peerClient, 0, len(instances))
+	for _, inst := range instances {
+		if inst.Id == p.selfID {
+			continue
+		}
+		seen[inst.Id] = struct{}{}
+
+		conn, ok := p.conns[inst.Id]
+		if !ok {
+			var err error
+			if conn, err = p.dial(inst); err != nil {
+				res = append(res, peerClient{nodeID: inst.Id, err: err})
+				continue
+			}
+			p.conns[inst.Id] = conn
+		}
+		res = append(res, peerClient{nodeID: inst.Id, api: impb.NewDeliveryClient(conn)})
+	}
+
+	for id, conn := range p.conns {
+		if _, ok := seen[id]; !ok {
+			_ = conn.Close()
+			delete(p.conns, id)
+		}
+	}
+	return res
+}
+
+func (p *Peers) dial(inst *discovery.ServiceInstance) (*grpc.ClientConn, error) {
+	if len(inst.Endpoints) == 0 {
+		return nil, fmt.Errorf("instance %s advertises no endpoint", inst.Id)
+	}
+	u, err := url.Parse(inst.Endpoints[0])
+	if err != nil {
+		return nil, fmt.Errorf("instance %s endpoint: %w", inst.Id, err)
+	}
+	// [PLAINTEXT] The delivery gRPC server listens without TLS (see grpcsrv.New), so its
+	// own nodes must dial it the same way; the client TLS config targets other services.
+	return grpc.NewClient(u.Host,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+}
+
+// Close shuts every peer connection down.
+func (p *Peers) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, conn := range p.conns {
+		_ = conn.Close()
+		delete(p.conns, id)
+	}
+	return nil
+}
+
+func unmapDeliveryStatus(st *impb.DeliveryStatus) *service.DeliveryStatus {
+	res := &service.DeliveryStatus{
+		Truncated: st.GetTruncated(),
+		Timeline:  make([]service.DeliveryStep, len(st.GetTimeline())),
+	}
+	for i, s := range st.GetTimeline() {
+		connID, _ := uuid.Parse(s.GetConnectionId())
+		userID, _ := uuid.Parse(s.GetUserId())
+		res.Timeline[i] = service.DeliveryStep{
+			UserID:  userID,
+			ConnID:  connID,
+			EventID: s.GetEventId(),
+			Kind:    s.GetKind(),
+			Outcome: s.GetOutcome(),
+			At:      time.UnixMilli(s.GetAt()),
+			NodeID:  s.GetNodeId(),
+		}
+	}
+	return res
+}
@@ -0,0 +1,142 @@
+package imgroup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	groupv1 "github.com/webitel/im-delivery-service/gen/go/group/v1"
+	webitel "github.com/webitel/im-delivery-service/infra/client"
+	infratls "github.com/webitel/im-delivery-service/infra/tls"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/webitel-go-kit/infra/discovery"
+	rpc "github.com/webitel/webitel-go-kit/infra/transport/gRPC"
+	"google.golang.org/grpc"
+)
+
+const ServiceName string = "im-group-service"
+
+// Interface guards
+var (
+	_ service.GroupClient   = (*Client)(nil)
+	_ service.ChannelClient = (*Client)(nil)
+	_ service.GroupMembers  = (*Client)(nil)
+)
+
+// membersPageSize is the page requested from ListMembers; the server caps it as well.
+const membersPageSize = 500
+
+type Client struct {
+	logger *slog.Logger
+	// [GENERIC_RPC] Holds the go-kit RPC client for the group service
+	rpc *rpc.Client[groupv1.GroupsClient]
+}
+
+func New(logger *slog.Logger, discovery discovery.DiscoveryProvider, tls *infratls.Config) (*Client, error) {
+	// [FACTORY] Required by go-kit to instantiate the gRPC stub
+	factory := func(conn *grpc.ClientConn) groupv1.GroupsClient {
+		return groupv1.NewGroupsClient(conn)
+	}
+
+	// [INIT] Initialize the shared RPC client wrapper
+	c, err := webitel.New(logger, discovery, ServiceName, tls, factory)
+	if err != nil {
+		return nil, fmt.Errorf("[im-group-client] initialization failed: %w", err)
+	}
+
+	return &Client{
+		logger: logger,
+		rpc:    c,
+	}, nil
+}
+
+// LookupGroup returns the display profile of a group thread.
+func (c *Client) LookupGroup(ctx context.Context, id uuid.UUID, domainID int32) (service.GroupProfile, error) {
+	var resp *groupv1.Group
+
+	err := c.rpc.Execute(ctx, func(api groupv1.GroupsClient) error {
+		c.logger.Debug("GROUPS.LOCATE_GROUP", slog.String("id", id.String()), slog.Int("domain_id", int(domainID)))
+
+		var err error
+		resp, err = api.LocateGroup(ctx, &groupv1.LocateGroupRequest{Id: id.String(), DomainId: domainID})
+		return err
+	})
+	if err != nil {
+		return service.GroupProfile{}, err
+	}
+
+	return service.GroupProfile{
+		Title:       resp.GetTitle(),
+		MemberCount: int(resp.GetMemberCount()),
+		AvatarURL:   resp.GetAvatarUrl(),
+	}, nil
+}
+
+// LookupChannel returns the identity of a broadcast channel through the same directory.
+func (c *Client) LookupChannel(ctx context.Context, id uuid.UUID, domainID int32) (name, sub, issuer string, err error) {
+	var resp *groupv1.Channel
+
+	err = c.rpc.Execute(ctx, func(api groupv1.GroupsClient) error {
+		c.logger.Debug("GROUPS.LOCATE_CHANNEL", slog.String("id", id.String()), slog.Int("domain_id", int(domainID)))
+
+		var err error
+		resp, err = api.LocateChannel(ctx, &groupv1.LocateChannelRequest{Id: id.String(), DomainId: domainID})
+		return err
+	})
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// [WIRE_IDENTITY] Like groups, a channel without an external subject is addressed by its ID.
+	sub = resp.GetSubject()
+	if sub == "" {
+		sub = id.String()
+	}
+	return resp.GetTitle(), sub, resp.GetIssId(), nil
+}
+
+// ListGroupMembers pages through every member of a group. Malformed IDs are skipped.
+func (c *Client) ListGroupMembers(ctx context.Context, groupID uuid.UUID, domainID int32) ([]uuid.UUID, error) {
+	var (
+		members []uuid.UUID
+		token   string
+	)
+	for {
+		var resp *groupv1.MemberList
+
+		err := c.rpc.Execute(ctx, func(api groupv1.GroupsClient) error {
+			c.logger.Debug("GROUPS.LIST_MEMBERS", slog.String("group_id", groupID.String()), slog.Int("domain_id", int(domainID)))
+
+			var err error
+			resp, err = api.ListMembers(ctx, &groupv1.ListMembersRequest{
+				GroupId:   groupID.String(),
+				DomainId:  domainID,
+				Size:      membersPageSize,
+				PageToken: token,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range resp.GetUserIds() {
+			if uid, err := uuid.Parse(id); err == nil {
+				members = append(members, uid)
+			}
+		}
+
+		if token = resp.GetNextPageToken(); token == "" {
+			return members, nil
+		}
+	}
+}
+
+// Close gracefully shuts down the underlying gRPC connection pool
+func (c *Client) Close() error {
+	if c.rpc != nil {
+		return c.rpc.Close()
+	}
+	return nil
+}
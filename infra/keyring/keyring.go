@@ -0,0 +1,235 @@
+package keyring
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	ErrNoActiveKey      = errors.New("keyring: no active signing key")
+	ErrUnknownKey       = errors.New("keyring: unknown key id")
+	ErrMalformedToken   = errors.New("keyring: malformed token")
+	ErrInvalidSignature = errors.New("keyring: signature mismatch")
+)
+
+var verifications, _ = otel.Meter("github.com/webitel/im-delivery-service/infra/keyring").Int64Counter(
+	"im_delivery_signature_verifications_total",
+	metric.WithDescription("Signature verifications per key id and outcome"),
+)
+
+// Snapshot is the serialized form of a key set (config or mounted secret file).
+//
+// [ROTATION]
+//  1. add the new key to Keys (accepted for verification only);
+//  2. switch Active to it (new signatures use it, old tokens still verify);
+//  3. remove the old key once its verification counter goes quiet.
+type Snapshot struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"` // key id -> base64 secret
+}
+
+// KeyRing holds [HMAC] keys: one active signing key plus the set still accepted for verification.
+type KeyRing struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string][]byte
+	logger *slog.Logger
+}
+
+// New builds a key ring from a snapshot.
+func New(snap Snapshot, logger *slog.Logger) (*KeyRing, error) {
+	kr := &KeyRing{logger: logger}
+	if err := kr.Load(snap); err != nil {
+		return nil, err
+	}
+	return kr, nil
+}
+
+// Load atomically replaces the key set. Invalid snapshots leave the ring untouched.
+func (kr *KeyRing) Load(snap Snapshot) error {
+	keys := make(map[string][]byte, len(snap.Keys))
+	for id, secret := range snap.Keys {
+		if id == "" || strings.ContainsAny(id, ".,=") {
+			return fmt.Errorf("keyring: invalid key id %q", id)
+		}
+		raw, err := base64.StdEncoding.DecodeString(secret)
+		if err != nil || len(raw) == 0 {
+			return fmt.Errorf("keyring: key %q is not valid base64", id)
+		}
+		keys[id] = raw
+	}
+
+	// [DISABLED] An empty key set is allowed; Sign then reports ErrNoActiveKey.
+	if _, ok := keys[snap.Active]; !ok && (len(keys) > 0 || snap.Active != "") {
+		return fmt.Errorf("%w: %q is not in the key set", ErrNoActiveKey, snap.Active)
+	}
+
+	kr.mu.Lock()
+	kr.active, kr.keys = snap.Active, keys
+	kr.mu.Unlock()
+	return nil
+}
+
+// Sign computes an HMAC-SHA256 over data with the active key.
+func (kr *KeyRing) Sign(data []byte) (keyID string, sig []byte, err error) {
+	kr.mu.RLock()
+	keyID, key := kr.active, kr.keys[kr.active]
+	kr.mu.RUnlock()
+
+	if key == nil {
+		return "", nil, ErrNoActiveKey
+	}
+	return keyID, mac(key, data), nil
+}
+
+// Verify checks a signature produced by the key identified by keyID.
+func (kr *KeyRing) Verify(keyID string, data, sig []byte) error {
+	kr.mu.RLock()
+	key, ok := kr.keys[keyID]
+	kr.mu.RUnlock()
+
+	outcome := "ok"
+	defer func() {
+		verifications.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("key_id", keyID),
+			attribute.String("outcome", outcome),
+		))
+	}()
+
+	if !ok {
+		outcome = "unknown_key"
+		return ErrUnknownKey
+	}
+	if !hmac.Equal(mac(key, data), sig) {
+		outcome = "mismatch"
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// SignToken produces a compact self-describing token: "<kid>.<payload_b64>.<sig_b64>".
+// Embedding the key id lets verification pick the right key after rotation.
+func (kr *KeyRing) SignToken(payload []byte) (string, error) {
+	keyID, sig, err := kr.Sign(payload)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return keyID + "." + enc.EncodeToString(payload) + "." + enc.EncodeToString(sig), nil
+}
+
+// VerifyToken validates a token produced by SignToken and returns its payload.
+func (kr *KeyRing) VerifyToken(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return nil, ErrMalformedToken
+	}
+
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if err := kr.Verify(parts[0], payload, sig); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// SignatureHeader renders the webhook header value understood by pkg/sigverify.
+func (kr *KeyRing) SignatureHeader(body []byte) (string, error) {
+	keyID, sig, err := kr.Sign(body)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("v1,kid=%s,sig=%s", keyID, base64.StdEncoding.EncodeToString(sig)), nil
+}
+
+// LoadFile reads a JSON snapshot from disk.
+func LoadFile(path string) (Snapshot, error) {
+	var snap Snapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snap, fmt.Errorf("keyring: read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snap, fmt.Errorf("keyring: decode %s: %w", path, err)
+	}
+	return snap, nil
+}
+
+// Watch reloads the ring whenever the secret file changes until ctx is done.
+// [K8S_FRIENDLY] The parent directory is watched because mounted secrets are swapped via symlinks.
+func (kr *KeyRing) Watch(ctx context.Context, path string) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	go func() {
+		defer w.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				kr.reload(path)
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				kr.logger.Warn("KEYRING_WATCH_ERROR", "err", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (kr *KeyRing) reload(path string) {
+	snap, err := LoadFile(path)
+	if err == nil {
+		err = kr.Load(snap)
+	}
+	if err != nil {
+		kr.logger.Error("KEYRING_RELOAD_FAILED", "path", path, "err", err)
+		return
+	}
+	kr.logger.Info("KEYRING_RELOADED", "active", snap.Active, "keys", len(snap.Keys))
+}
+
+func mac(key, data []byte) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write(data)
+	return m.Sum(nil)
+}
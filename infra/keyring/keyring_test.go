@@ -0,0 +1,248 @@
+package keyring
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var quiet = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func secret(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+func TestRotation(t *testing.T) {
+	v1 := Snapshot{Active: "k1", Keys: map[string]string{"k1": secret("one")}}
+	v2 := Snapshot{Active: "k1", Keys: map[string]string{"k1": secret("one"), "k2": secret("two")}}
+	v3 := Snapshot{Active: "k2", Keys: map[string]string{"k1": secret("one"), "k2": secret("two")}}
+	v4 := Snapshot{Active: "k2", Keys: map[string]string{"k2": secret("two")}}
+
+	tests := []struct {
+		name       string
+		steps      []Snapshot // Applied after the token was signed under v1
+		wantErr    error
+		wantSigner string // Key id of a token signed after the steps
+	}{
+		{name: "no rotation", wantSigner: "k1"},
+		{name: "new key accepted", steps: []Snapshot{v2}, wantSigner: "k1"},
+		{name: "new key active, old token still verifies", steps: []Snapshot{v2, v3}, wantSigner: "k2"},
+		{name: "old key removed", steps: []Snapshot{v2, v3, v4}, wantErr: ErrUnknownKey, wantSigner: "k2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kr, err := New(v1, quiet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := kr.SignToken([]byte("resume:42"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, snap := range tt.steps {
+				if err := kr.Load(snap); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			payload, err := kr.VerifyToken(token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verify in-flight token: got %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && string(payload) != "resume:42" {
+				t.Fatalf("payload = %q", payload)
+			}
+
+			fresh, err := kr.SignToken([]byte("x"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kid, _, _ := strings.Cut(fresh, "."); kid != tt.wantSigner {
+				t.Fatalf("signed with %q, want %q", kid, tt.wantSigner)
+			}
+		})
+	}
+}
+
+func TestVerifyTokenRejects(t *testing.T) {
+	kr, err := New(Snapshot{Active: "k1", Keys: map[string]string{"k1": secret("one")}}, quiet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	good, err := kr.SignToken([]byte("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, body, _ := strings.Cut(good, ".")
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid", token: good},
+		{name: "empty", token: "", wantErr: ErrMalformedToken},
+		{name: "missing key id", token: "." + body, wantErr: ErrMalformedToken},
+		{name: "too few parts", token: "k1.abc", wantErr: ErrMalformedToken},
+		{name: "too many parts", token: good + ".x", wantErr: ErrMalformedToken},
+		{name: "payload not base64", token: "k1.!!!." + strings.Split(good, ".")[2], wantErr: ErrMalformedToken},
+		{name: "unknown key id", token: "k9." + body, wantErr: ErrUnknownKey},
+		{name: "tampered payload", token: "k1." + base64.RawURLEncoding.EncodeToString([]byte("other")) + "." + strings.Split(good, ".")[2], wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := kr.VerifyToken(tt.token); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidSnapshots(t *testing.T) {
+	tests := []struct {
+		name    string
+		snap    Snapshot
+		wantErr bool
+	}{
+		{name: "empty ring", snap: Snapshot{}},
+		{name: "active key missing", snap: Snapshot{Active: "k2", Keys: map[string]string{"k1": secret("one")}}, wantErr: true},
+		{name: "key id with a separator", snap: Snapshot{Active: "k.1", Keys: map[string]string{"k.1": secret("one")}}, wantErr: true},
+		{name: "secret not base64", snap: Snapshot{Active: "k1", Keys: map[string]string{"k1": "%%%"}}, wantErr: true},
+		{name: "empty secret", snap: Snapshot{Active: "k1", Keys: map[string]string{"k1": ""}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kr, err := New(Snapshot{Active: "k0", Keys: map[string]string{"k0": secret("zero")}}, quiet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := kr.Load(tt.snap); (err != nil) != tt.wantErr {
+				t.Fatalf("Load: got %v, want error %v", err, tt.wantErr)
+			}
+			// A rejected snapshot leaves the previous keys in place.
+			if tt.wantErr {
+				if kid, _, err := kr.Sign([]byte("x")); err != nil || kid != "k0" {
+					t.Fatalf("ring changed after a rejected load: %q, %v", kid, err)
+				}
+			}
+		})
+	}
+}
+
+// TestConcurrentReload signs and verifies while the key set is swapped; run with -race.
+func TestConcurrentReload(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		rounds  int
+	}{
+		{name: "few workers", workers: 2, rounds: 500},
+		{name: "many workers", workers: 8, rounds: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			both := map[string]string{"k1": secret("one"), "k2": secret("two")}
+			kr, err := New(Snapshot{Active: "k1", Keys: both}, quiet)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var wg sync.WaitGroup
+			wg.Go(func() {
+				for i := range tt.rounds {
+					active := []string{"k1", "k2"}[i%2]
+					if err := kr.Load(Snapshot{Active: active, Keys: both}); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			})
+			for range tt.workers {
+				wg.Go(func() {
+					for range tt.rounds {
+						token, err := kr.SignToken([]byte("p"))
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						// Both keys stay accepted, so no token may fail mid-rotation.
+						if _, err := kr.VerifyToken(token); err != nil {
+							t.Error(err)
+							return
+						}
+					}
+				})
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestWatchReloadsSecretFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		next       string // New file content
+		wantSigner string
+	}{
+		{name: "rotated file", next: `{"active":"k2","keys":{"k1":"` + secret("one") + `","k2":"` + secret("two") + `"}}`, wantSigner: "k2"},
+		{name: "broken file keeps the ring", next: `{not json`, wantSigner: "k1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.json")
+			writeSnapshot(t, path, Snapshot{Active: "k1", Keys: map[string]string{"k1": secret("one")}})
+
+			snap, err := LoadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kr, err := New(snap, quiet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			if err := kr.Watch(ctx, path); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(path, []byte(tt.next), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			// A rejected file produces no change to wait for, so it gets a fixed window.
+			deadline := time.Now().Add(2 * time.Second)
+			if tt.wantSigner == "k1" {
+				time.Sleep(200 * time.Millisecond)
+			}
+			for {
+				kid, _, _ := kr.Sign([]byte("x"))
+				if kid == tt.wantSigner {
+					return
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("signer = %q, want %q", kid, tt.wantSigner)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func writeSnapshot(t *testing.T, path string, snap Snapshot) {
+	t.Helper()
+	b, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
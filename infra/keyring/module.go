@@ -0,0 +1,45 @@
+package keyring
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/webitel/im-delivery-service/config"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("keyring",
+	fx.Provide(ProvideKeyRing),
+)
+
+// ProvideKeyRing builds the ring from a mounted secret file when configured,
+// falling back to inline config keys, and watches the file for rotation.
+func ProvideKeyRing(cfg *config.Config, logger *slog.Logger, lc fx.Lifecycle) (*KeyRing, error) {
+	signing := cfg.Signing
+
+	snap := Snapshot{Active: signing.ActiveKey, Keys: signing.Keys}
+	if signing.KeyFile != "" {
+		var err error
+		if snap, err = LoadFile(signing.KeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	kr, err := New(snap, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if signing.KeyFile != "" {
+		ctx, cancel := context.WithCancel(context.Background())
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error { return kr.Watch(ctx, signing.KeyFile) },
+			OnStop: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+	}
+
+	return kr, nil
+}
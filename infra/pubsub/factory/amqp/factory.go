@@ -6,6 +6,7 @@ import (
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-amqp/v3/pkg/amqp"
 	"github.com/ThreeDotsLabs/watermill/message"
+	amqp091 "github.com/rabbitmq/amqp091-go"
 	"github.com/webitel/im-delivery-service/infra/pubsub/factory"
 )
 
@@ -75,7 +76,9 @@ func (f *Factory) BuildPublisher(pubConfig *factory.PublisherConfig) (message.Pu
 		Connection: amqp.ConnectionConfig{
 			AmqpURI: f.url,
 		},
-		Marshaler: amqp.DefaultMarshaler{},
+		Marshaler: observedMarshaler{amqp.DefaultMarshaler{
+			PostprocessPublishing: withContentType,
+		}},
 		Exchange: amqp.ExchangeConfig{
 			GenerateName: func(s string) string {
 				return pubConfig.Exchange.Name
@@ -88,7 +91,30 @@ func (f *Factory) BuildPublisher(pubConfig *factory.PublisherConfig) (message.Pu
 			GenerateRoutingKey: func(s string) string {
 				return s
 			},
+			ConfirmDelivery: pubConfig.ConfirmDelivery,
 		},
 	}
 	return amqp.NewPublisher(conf, f.logger)
 }
+
+// withContentType lifts the content-type metadata header into the AMQP property,
+// where consumers that ignore headers still see it.
+func withContentType(p amqp091.Publishing) amqp091.Publishing {
+	if ct, ok := p.Headers[factory.ContentTypeHeader].(string); ok {
+		p.ContentType = ct
+	}
+	return p
+}
+
+// observedMarshaler reports each message to its factory.PublishObserver. The publisher
+// marshals a message right before sending it, after the previous one went out.
+type observedMarshaler struct {
+	amqp.Marshaler
+}
+
+func (m observedMarshaler) Marshal(msg *message.Message) (amqp091.Publishing, error) {
+	if o, ok := factory.PublishObserverFrom(msg.Context()); ok {
+		o.Started(msg)
+	}
+	return m.Marshaler.Marshal(msg)
+}
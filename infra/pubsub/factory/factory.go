@@ -1,6 +1,8 @@
 package factory
 
 import (
+	"context"
+
 	"github.com/ThreeDotsLabs/watermill/message"
 )
 
@@ -40,7 +42,31 @@ type SubscriberConfig struct {
 	ExclusiveConsumer bool // Consumer has exclusive access to the queue
 }
 
+// ContentTypeHeader is the message metadata key publishers copy into the AMQP content type.
+const ContentTypeHeader = "content-type"
+
 // PublisherConfig holds publication topology details
 type PublisherConfig struct {
-	Exchange ExchangeConfig
+	Exchange        ExchangeConfig
+	ConfirmDelivery bool // Publish returns only once the broker confirmed (or rejected) the message
+}
+
+// PublishObserver learns which message of a multi-message Publish is being sent.
+// Publishers send in order and stop at the first failure, so once Started(m) is
+// called every message before m has gone out; a caller retries from m onwards.
+type PublishObserver interface {
+	Started(msg *message.Message)
+}
+
+type publishObserverKey struct{}
+
+// WithPublishObserver attaches o to a message context; see PublishObserver.
+func WithPublishObserver(ctx context.Context, o PublishObserver) context.Context {
+	return context.WithValue(ctx, publishObserverKey{}, o)
+}
+
+// PublishObserverFrom returns the observer attached to a message context, if any.
+func PublishObserverFrom(ctx context.Context) (PublishObserver, bool) {
+	o, ok := ctx.Value(publishObserverKey{}).(PublishObserver)
+	return o, ok
 }
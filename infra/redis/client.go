@@ -0,0 +1,23 @@
+package redis
+
+import (
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/webitel/im-delivery-service/config"
+)
+
+const defaultTimeout = time.Second
+
+// NewClient builds a pooled go-redis client. It does not dial; connections are
+// opened on demand and replaced after I/O errors.
+func NewClient(cfg config.RedisConfig) *goredis.Client {
+	return goredis.NewClient(&goredis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  defaultTimeout,
+		ReadTimeout:  defaultTimeout,
+		WriteTimeout: defaultTimeout,
+	})
+}
@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"context"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"go.uber.org/fx"
+)
+
+// Module backs the Hub's global presence with Redis. Wired only when
+// delivery.presence.global is on (see cmd).
+var Module = fx.Module("redis",
+	fx.Provide(
+		ProvideClient,
+		fx.Annotate(
+			ProvidePresenceOption,
+			fx.ResultTags(`group:"hub_options"`),
+		),
+	),
+)
+
+func ProvideClient(cfg *config.Config, lc fx.Lifecycle) *goredis.Client {
+	c := NewClient(cfg.Redis)
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error { return c.Close() },
+	})
+	return c
+}
+
+// ProvidePresenceOption registers this node's users under cfg.Service.ID.
+func ProvidePresenceOption(c *goredis.Client, cfg *config.Config) registry.Option {
+	return registry.WithPresenceStore(NewPresenceStore(c), cfg.Service.ID, cfg.Delivery.Presence.TTL)
+}
@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/webitel/im-delivery-service/internal/domain/presence"
+)
+
+// Interface guard
+var _ presence.Store = (*PresenceStore)(nil)
+
+const presenceKeyPrefix = "presence:"
+
+// deleteIfOwner removes the key only while it still names the calling node.
+// EvalSha runs it from the script cache, falling back to EVAL on a cold server.
+var deleteIfOwner = goredis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`)
+
+// PresenceStore keeps presence:{userID} -> nodeID keys with a TTL.
+type PresenceStore struct {
+	client goredis.Cmdable
+}
+
+func NewPresenceStore(client goredis.Cmdable) *PresenceStore {
+	return &PresenceStore{client: client}
+}
+
+func presenceKey(userID uuid.UUID) string { return presenceKeyPrefix + userID.String() }
+
+func (s *PresenceStore) Set(ctx context.Context, userID uuid.UUID, nodeID string, ttl time.Duration) error {
+	return s.client.Set(ctx, presenceKey(userID), nodeID, ttl).Err()
+}
+
+// Refresh re-arms every key in one pipelined round-trip.
+func (s *PresenceStore) Refresh(ctx context.Context, userIDs []uuid.UUID, nodeID string, ttl time.Duration) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err := s.client.Pipelined(ctx, func(p goredis.Pipeliner) error {
+		for _, id := range userIDs {
+			p.Set(ctx, presenceKey(id), nodeID, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *PresenceStore) Delete(ctx context.Context, userID uuid.UUID, nodeID string) error {
+	return deleteIfOwner.Run(ctx, s.client, []string{presenceKey(userID)}, nodeID).Err()
+}
+
+func (s *PresenceStore) Lookup(ctx context.Context, userID uuid.UUID) (string, bool, error) {
+	nodeID, err := s.client.Get(ctx, presenceKey(userID)).Result()
+	if errors.Is(err, goredis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return nodeID, nodeID != "", nil
+}
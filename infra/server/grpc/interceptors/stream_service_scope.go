@@ -0,0 +1,30 @@
+package grpcinterceptors
+
+import (
+	"strings"
+
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewStreamServiceScopeInterceptor rejects streams to AdminServiceName whose caller lacks
+// model.ScopeService. It must run after NewStreamAuthInterceptor, which resolves the caller.
+func NewStreamServiceScopeInterceptor() grpc.StreamServerInterceptor {
+	prefix := "/" + AdminServiceName + "/"
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(srv, ss)
+		}
+
+		auth, ok := GetAuthContact(ss.Context())
+		if !ok {
+			return status.Error(codes.Unauthenticated, "authentication required")
+		}
+		if !auth.HasScope(model.ScopeService) {
+			return status.Error(codes.PermissionDenied, "service scope required")
+		}
+		return handler(srv, ss)
+	}
+}
@@ -2,13 +2,33 @@ package grpcinterceptors
 
 import (
 	"context"
+	"strings"
 
+	"github.com/webitel/im-delivery-service/internal/service"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// UnaryAuthInterceptor is a gRPC interceptor for handling authentication.
-func NewUnaryAuthInterceptor() grpc.UnaryServerInterceptor {
+// DeliveryServiceName is the client-facing Delivery service from delivery_service.proto.
+const DeliveryServiceName = "webitel.im.api.delivery.v1.Delivery"
+
+// NewUnaryAuthInterceptor authenticates unary calls to DeliveryServiceName the way
+// streams are authenticated. Handlers decide what the caller may see; other services
+// pass through untouched (AdminServiceName has its own interceptor).
+func NewUnaryAuthInterceptor(auther service.Auther) grpc.UnaryServerInterceptor {
+	prefix := "/" + DeliveryServiceName + "/"
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		return handler(ctx, req)
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+
+		auth, err := auther.Inspect(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+		}
+
+		// [ENRICHMENT] Same context key as streams, so handlers use GetAuthContact.
+		return handler(context.WithValue(ctx, AuthContextKey, auth), req)
 	}
 }
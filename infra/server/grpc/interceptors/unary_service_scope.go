@@ -0,0 +1,38 @@
+package grpcinterceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdminServiceName is the DeliveryAdmin service from delivery_service.proto; only service
+// callers may invoke its methods.
+const AdminServiceName = "webitel.im.api.delivery.v1.DeliveryAdmin"
+
+// NewUnaryServiceScopeInterceptor authenticates calls to AdminServiceName and rejects
+// callers whose token lacks model.ScopeService. Other methods pass through untouched.
+func NewUnaryServiceScopeInterceptor(auther service.Auther) grpc.UnaryServerInterceptor {
+	prefix := "/" + AdminServiceName + "/"
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+
+		auth, err := auther.Inspect(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+		}
+		if !auth.HasScope(model.ScopeService) {
+			return nil, status.Error(codes.PermissionDenied, "service scope required")
+		}
+
+		// [ENRICHMENT] Same context key as streams, so handlers use GetAuthContact.
+		return handler(context.WithValue(ctx, AuthContextKey, auth), req)
+	}
+}
@@ -129,15 +129,19 @@ func New(addr string, log *slog.Logger, auther service.Auther, deliverer service
 		grpc.KeepaliveParams(kasp),
 
 		// [PIPELINE] UNARY_INTERCEPTORS
-		// Sequence: Error Handling -> Authentication -> Validation.
+		// Sequence: Error Handling -> Authentication -> Service Scope -> Validation.
 		grpc.ChainUnaryInterceptor(
 			intrcp.UnaryServerErrorInterceptor(),
-			grpcinterceptors.NewUnaryAuthInterceptor(),
+			grpcinterceptors.NewUnaryAuthInterceptor(auther),
+			grpcinterceptors.NewUnaryServiceScopeInterceptor(auther),
 			validatemiddleware.UnaryServerInterceptor(validator),
 		),
 
+		// [PIPELINE] STREAM_INTERCEPTORS
+		// Sequence: Authentication -> Service Scope.
 		grpc.ChainStreamInterceptor(
 			grpcinterceptors.NewStreamAuthInterceptor(auther),
+			grpcinterceptors.NewStreamServiceScopeInterceptor(),
 		),
 	)
 
@@ -183,6 +187,7 @@ func (s *Server) Shutdown() error {
 	// This triggers a cascade: Hub -> Cell -> Connector.Close(), which closes the
 	// internal receive channels. Handlers detect the closed channel, send a final
 	// 'DisconnectedEvent' to the client, and exit the event loop gracefully.
+	// On a delivery node amqp.ShutdownSequence has already drained the Hub by now.
 	if s.deliverer != nil {
 		s.deliverer.Close()
 	}
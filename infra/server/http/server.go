@@ -0,0 +1,120 @@
+package httpsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"go.uber.org/fx"
+)
+
+// readHeaderTimeout bounds the request line and headers; bodies and streams are not
+// limited here, since LP, SSE and WS responses are long-lived by design.
+const readHeaderTimeout = 10 * time.Second
+
+var Module = fx.Module("http_server",
+	fx.Provide(func(conf *config.Config, logger *slog.Logger, lc fx.Lifecycle) (*Server, error) {
+		srv, err := New(conf.Service.HTTPAddress, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				go func() {
+					// [LIFECYCLE] NON-BLOCKING START
+					logger.Info(fmt.Sprintf("listen http %s", srv.Addr()))
+					if err := srv.Listen(); err != nil {
+						logger.Error("http server error", "err", err)
+					}
+				}()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				// [GRACEFUL_EXIT] On a delivery node the Hub has drained by now, so open
+				// polls and streams have already received their final event.
+				if err := srv.Shutdown(ctx); err != nil {
+					logger.Error("error stopping http server", "err", err.Error())
+					return err
+				}
+				return nil
+			},
+		})
+
+		return srv, nil
+	}),
+)
+
+// Server serves the HTTP transports (WS, LP, SSE) on one chi router.
+type Server struct {
+	router   chi.Router
+	http     *http.Server
+	listener net.Listener
+	log      *slog.Logger
+}
+
+func New(addr string, log *slog.Logger) (*Server, error) {
+	router := chi.NewRouter()
+	// [PANIC_ISOLATION] One faulty request must not take the listener down.
+	router.Use(middleware.Recoverer)
+
+	// [TRANSPORT_BINDING] TCP_SOCKET_INITIALIZATION
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		router:   router,
+		http:     &http.Server{Handler: router, ReadHeaderTimeout: readHeaderTimeout},
+		listener: l,
+		log:      log,
+	}, nil
+}
+
+// Public mounts routes that authenticate themselves (the WS upgrade accepts tokens
+// the shared middleware cannot read, see ws.accessToken).
+func (s *Server) Public(fn func(r chi.Router)) {
+	s.router.Group(fn)
+}
+
+// Authenticated mounts routes behind auth: every request reaching them carries an
+// httpauth.Identity.
+func (s *Server) Authenticated(auth httpauth.Middleware, fn func(r chi.Router)) {
+	s.router.Group(func(r chi.Router) {
+		r.Use(auth)
+		fn(r)
+	})
+}
+
+// Handler exposes the router, e.g. for httptest.
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *Server) Listen() error {
+	// [ACCEPT_LOOP] BLOCKING_SERVE
+	if err := s.http.Serve(s.listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting requests and waits for in-flight ones until ctx ends.
+// Hijacked WS connections are not tracked here; the Hub drain closes them.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.log.Debug("initiating graceful shutdown of http server")
+	return s.http.Shutdown(ctx)
+}
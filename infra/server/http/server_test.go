@@ -0,0 +1,79 @@
+package httpsrv_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	httpsrv "github.com/webitel/im-delivery-service/infra/server/http"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"github.com/webitel/im-delivery-service/internal/handler/lp"
+	"github.com/webitel/im-delivery-service/internal/handler/sse"
+	"github.com/webitel/im-delivery-service/internal/handler/ws"
+	"google.golang.org/grpc/metadata"
+)
+
+// tokenAuther accepts the tokens it knows, mapped to their contact IDs.
+type tokenAuther map[string]string
+
+func (a tokenAuther) Inspect(ctx context.Context) (*model.AuthContact, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get(httpauth.AccessTokenHeader) {
+		if contact, ok := a[token]; ok {
+			return &model.AuthContact{ContactID: contact}, nil
+		}
+	}
+	return nil, errors.New("token rejected")
+}
+
+func TestTransportRoutes(t *testing.T) {
+	user := uuid.New()
+	auther := tokenAuther{"user": user.String(), "bot": "bot:42"}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv, err := httpsrv.New("127.0.0.1:0", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := httpauth.NewMiddleware(auther)
+	ws.Register(srv, ws.NewWSHandler(logger, nil, auther, nil, nil))
+	lp.Register(srv, auth, lp.NewLPHandler(nil))
+	sse.Register(srv, auth, sse.NewSSEHandler(logger, nil))
+
+	other := uuid.NewString()
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{"ws without token", "/ws", "", http.StatusUnauthorized},
+		{"ws with unknown token", "/ws", "forged", http.StatusUnauthorized},
+		{"lp without token", "/lp/" + user.String(), "", http.StatusUnauthorized},
+		{"lp with unknown token", "/lp/" + user.String(), "forged", http.StatusUnauthorized},
+		{"lp for another user", "/lp/" + other, "user", http.StatusForbidden},
+		{"lp as a non-user contact", "/lp/" + user.String(), "bot", http.StatusForbidden},
+		{"sse without token", "/sse/" + user.String(), "", http.StatusUnauthorized},
+		{"sse for another user", "/sse/" + other, "user", http.StatusForbidden},
+		{"unknown route", "/poll/" + user.String(), "user", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
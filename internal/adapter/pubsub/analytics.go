@@ -0,0 +1,113 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// AnalyticsTopic is the routing key of sampled analytics batches.
+const AnalyticsTopic = "im_delivery.analytics.v1"
+
+const (
+	analyticsQueueSize = 4096
+	analyticsBatchSize = 256
+	analyticsFlushTick = time.Second
+)
+
+var analyticsDropped, _ = meter.Int64Counter(
+	"im_delivery_analytics_dropped_total",
+	metric.WithDescription("Sampled analytics records shed because the export queue was full"),
+)
+
+// AnalyticsPublisher batches analytics records into JSON-array messages.
+// [BEST_EFFORT] Export never blocks the Cell loop; a full queue drops the record.
+type AnalyticsPublisher struct {
+	publisher message.Publisher
+	logger    *slog.Logger
+	queue     chan model.AnalyticsRecord
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewAnalyticsPublisher(pub message.Publisher, logger *slog.Logger) *AnalyticsPublisher {
+	return &AnalyticsPublisher{
+		publisher: pub,
+		logger:    logger,
+		queue:     make(chan model.AnalyticsRecord, analyticsQueueSize),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Export enqueues a record without blocking.
+func (p *AnalyticsPublisher) Export(rec model.AnalyticsRecord) {
+	select {
+	case p.queue <- rec:
+	default:
+		analyticsDropped.Add(context.Background(), 1)
+	}
+}
+
+// Start runs the batching loop until Stop.
+func (p *AnalyticsPublisher) Start() {
+	go p.run()
+}
+
+// Stop flushes what is queued and waits for the loop to exit.
+func (p *AnalyticsPublisher) Stop() {
+	close(p.stop)
+	<-p.done
+}
+
+func (p *AnalyticsPublisher) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(analyticsFlushTick)
+	defer ticker.Stop()
+
+	batch := make([]model.AnalyticsRecord, 0, analyticsBatchSize)
+	for {
+		select {
+		case rec := <-p.queue:
+			batch = append(batch, rec)
+			if len(batch) >= analyticsBatchSize {
+				batch = p.flush(batch)
+			}
+		case <-ticker.C:
+			batch = p.flush(batch)
+		case <-p.stop:
+			for {
+				select {
+				case rec := <-p.queue:
+					batch = append(batch, rec)
+				default:
+					p.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush publishes the batch and returns it emptied for reuse.
+func (p *AnalyticsPublisher) flush(batch []model.AnalyticsRecord) []model.AnalyticsRecord {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	data, err := json.Marshal(batch)
+	if err == nil {
+		err = p.publisher.Publish(AnalyticsTopic, message.NewMessage(watermill.NewUUID(), data))
+	}
+	if err != nil {
+		p.logger.Warn("ANALYTICS_EXPORT_FAILED", "err", err, "records", len(batch))
+	}
+	return batch[:0]
+}
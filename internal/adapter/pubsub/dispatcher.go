@@ -3,54 +3,443 @@ package pubsub
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/webitel/im-delivery-service/infra/pubsub/factory"
 	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrPublishAbandoned is returned when the caller stops waiting for a publish that is
+// still running. The dispatcher keeps the event: it is published or parked in the outbox
+// in the background, so the caller must not publish it again.
+var ErrPublishAbandoned = errors.New("dispatcher: publish abandoned")
+
+// ErrPublishTimeout is returned when the broker does not confirm a publish in time.
+// It wraps ErrPublishAbandoned.
+var ErrPublishTimeout = fmt.Errorf("dispatcher: publish timed out: %w", ErrPublishAbandoned)
+
+var meter = otel.Meter("github.com/webitel/im-delivery-service/internal/adapter/pubsub")
+
+var publishTimeouts, _ = meter.Int64Counter(
+	"im_delivery_publisher_timeout_total",
+	metric.WithDescription("Publishes abandoned because the broker exceeded the deadline"),
+)
+
+var publishRetries, _ = meter.Int64Counter(
+	"im_delivery_publisher_retries_total",
+	metric.WithDescription("Publish attempts repeated after a broker error"),
+)
+
+var publishDropped, _ = meter.Int64Counter(
+	"im_delivery_publisher_dropped_total",
+	metric.WithDescription("Events given up on after every publish attempt failed"),
+)
+
+const defaultPublishTimeout = 5 * time.Second
+
+// [ENVELOPE] Metadata set on every published message; AMQP carries it as headers.
+const (
+	HeaderEventID       = "event_id"
+	HeaderEventKind     = "event_kind"
+	HeaderTraceID       = "trace_id"
+	HeaderSchemaVersion = "schema_version"
+
+	// PayloadSchemaVersion identifies the JSON shape of exported events.
+	// Bump it with any breaking change so consumers can branch on the header.
+	PayloadSchemaVersion = 1
+)
+
+// traceIDKey carries the correlation ID of a consumed message; see WithTraceID.
+type traceIDKey struct{}
+
+// WithTraceID attaches the trace_id of the message being handled, so events it
+// re-publishes keep it when no span is active.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// traceIDFrom prefers the active span's trace ID over the consumed message's.
+func traceIDFrom(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
 type EventDispatcher interface {
 	Publish(ctx context.Context, ev event.Eventer) error
+	// PublishWithTimeout bounds the broker round-trip; a non-positive timeout
+	// uses the dispatcher's configured default (see WithPublisherTimeout).
+	PublishWithTimeout(ctx context.Context, ev event.Eventer, timeout time.Duration) error
+	// PublishBatch publishes events grouped by routing key; see BatchError for partial failures.
+	PublishBatch(ctx context.Context, events []event.Eventer) error
 	Publisher() message.Publisher
 }
 
 type eventDispatcher struct {
 	publisher message.Publisher
+	timeout   time.Duration
+	logger    *slog.Logger
+
+	// [RETRY] Bounded exponential backoff between attempts; attempts <= 1 disables retries.
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	// [OUTBOX] Parks what the retries could not publish; nil drops it instead.
+	outbox *Outbox
+
+	// [EXCHANGE_SELECTION] Resolves publishers for events naming their own exchange.
+	exchanges ExchangePublishers
+}
+
+// ExchangePublishers returns a publisher bound to an exchange; see PublisherProvider.Get.
+type ExchangePublishers interface {
+	Get(exchange string) (message.Publisher, error)
+}
+
+// DispatcherOption defines a functional configuration type for the dispatcher.
+type DispatcherOption func(*eventDispatcher)
+
+// WithPublisherTimeout sets the default publish deadline.
+func WithPublisherTimeout(d time.Duration) DispatcherOption {
+	return func(d2 *eventDispatcher) {
+		d2.timeout = d
+	}
+}
+
+// WithPublishRetry retries a failed publish up to attempts times in total, doubling
+// the pause from backoff up to maxBackoff.
+func WithPublishRetry(attempts int, backoff, maxBackoff time.Duration) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.attempts = attempts
+		d.backoff = backoff
+		d.maxBackoff = maxBackoff
+	}
+}
+
+// WithOutbox parks failed publishes in o until the broker recovers.
+func WithOutbox(o *Outbox) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.outbox = o
+	}
 }
 
-func NewEventDispatcher(pub message.Publisher) EventDispatcher {
-	return &eventDispatcher{publisher: pub}
+// WithExchangePublishers routes events implementing event.ExchangeProvider through p.
+// Without it every event goes to the dispatcher's own publisher.
+func WithExchangePublishers(p ExchangePublishers) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.exchanges = p
+	}
+}
+
+// WithDispatcherLogger reports dropped publishes through logger.
+func WithDispatcherLogger(logger *slog.Logger) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.logger = logger
+	}
+}
+
+func NewEventDispatcher(pub message.Publisher, opts ...DispatcherOption) EventDispatcher {
+	d := &eventDispatcher{publisher: pub, timeout: defaultPublishTimeout, attempts: 1, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// PublishWithTimeout runs Publish under a deadline.
+// [NON_BLOCKING_CALLER] Watermill publishers take no context, so the publish runs in its
+// own goroutine; on timeout the caller is released while the broker call finishes in the background.
+// [HANDOVER] The abandoned publish stops retrying and parks what is left in the outbox
+// (or drops it without one), so the returned error wraps ErrPublishAbandoned: a caller
+// that published again would export the event twice.
+func (d *eventDispatcher) PublishWithTimeout(ctx context.Context, ev event.Eventer, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = d.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- d.Publish(ctx, ev) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			publishTimeouts.Add(context.Background(), 1)
+			return ErrPublishTimeout
+		}
+		return fmt.Errorf("%w: %w", ErrPublishAbandoned, ctx.Err())
+	}
 }
 
 func (d *eventDispatcher) Publish(ctx context.Context, ev event.Eventer) error {
-	if ev == nil {
+	entry, ok, err := d.envelope(ctx, ev)
+	if err != nil || !ok {
+		return err
+	}
+	if failed, err := d.send(ctx, []outboxEntry{entry}); len(failed) > 0 {
+		return fmt.Errorf("dispatcher: publish failed: %w", err)
+	}
+	return nil
+}
+
+// PublishBatch groups events by exchange and routing key and publishes each group in one call.
+// Events that are not exportable are skipped. A partial failure is a *BatchError
+// naming the events that were neither published nor parked in the outbox.
+// [AT_LEAST_ONCE] A failed group is retried from the message that failed, so the ones
+// already out are not published twice; only that message may be.
+func (d *eventDispatcher) PublishBatch(ctx context.Context, events []event.Eventer) error {
+	type groupKey struct{ exchange, routingKey string }
+	var (
+		keys   []groupKey
+		groups = make(map[groupKey][]outboxEntry)
+		batch  BatchError
+	)
+	for _, ev := range events {
+		entry, ok, err := d.envelope(ctx, ev)
+		if err != nil {
+			batch.add([]string{ev.GetID()}, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		key := groupKey{entry.exchange, entry.routingKey}
+		if _, seen := groups[key]; !seen {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], entry)
+	}
+
+	for _, key := range keys {
+		if failed, err := d.send(ctx, groups[key]); len(failed) > 0 {
+			batch.add(failed, err)
+		}
+	}
+
+	if len(batch.Failed) == 0 {
 		return nil
 	}
+	return &batch
+}
+
+// BatchError reports the events of a PublishBatch that were not published.
+type BatchError struct {
+	Failed []string // Event IDs, in batch order per routing key
+	Err    error    // Last underlying error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("dispatcher: %d event(s) not published: %v", len(e.Failed), e.Err)
+}
+
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// FailedEventIDs lets callers outside the adapter read the failures through an interface.
+func (e *BatchError) FailedEventIDs() []string { return e.Failed }
+
+func (e *BatchError) add(ids []string, err error) {
+	e.Failed = append(e.Failed, ids...)
+	e.Err = err
+}
+
+// envelope builds the broker message for ev; ok is false when ev is not for export.
+func (d *eventDispatcher) envelope(ctx context.Context, ev event.Eventer) (outboxEntry, bool, error) {
+	if ev == nil {
+		return outboxEntry{}, false, nil
+	}
 
 	// [CONTRACT] Check if the event is meant for external AMQP delivery
 	exportable, ok := ev.(event.Exportable)
 	if !ok {
-		return nil
+		return outboxEntry{}, false, nil
+	}
+
+	// [CONTRACT] An empty key means "not ready for export" (e.g. imported history).
+	routingKey := exportable.GetRoutingKey()
+	if routingKey == "" {
+		return outboxEntry{}, false, nil
+	}
+
+	// [EXCHANGE_SELECTION] Events may name their own exchange; the rest use the default.
+	pub, exchange := d.publisher, ""
+	if p, ok := ev.(event.ExchangeProvider); ok && p.GetExchange() != "" && d.exchanges != nil {
+		exchange = p.GetExchange()
+		var err error
+		if pub, err = d.exchanges.Get(exchange); err != nil {
+			return outboxEntry{}, false, fmt.Errorf("dispatcher: publisher for exchange %q: %w", exchange, err)
+		}
 	}
 
 	payload, err := json.Marshal(ev)
 	if err != nil {
-		return fmt.Errorf("dispatcher: marshal error: %w", err)
+		return outboxEntry{}, false, fmt.Errorf("dispatcher: marshal error: %w", err)
 	}
 
 	// [ENVELOPE] Create a clean message without Watermill infrastructure noise
 	msg := message.NewMessage(watermill.NewUUID(), payload)
 	msg.SetContext(ctx)
+	msg.Metadata.Set(factory.ContentTypeHeader, "application/json")
+	msg.Metadata.Set(HeaderEventID, ev.GetID())
+	msg.Metadata.Set(HeaderEventKind, ev.GetKind().String())
+	msg.Metadata.Set(HeaderSchemaVersion, strconv.Itoa(PayloadSchemaVersion))
+	// [TRACING] Consumers correlate the message with the span or message that produced it.
+	if traceID := traceIDFrom(ctx); traceID != "" {
+		msg.Metadata.Set(HeaderTraceID, traceID)
+	}
+
+	return outboxEntry{
+		publisher:  pub,
+		exchange:   exchange,
+		routingKey: routingKey,
+		msg:        msg,
+		eventID:    ev.GetID(),
+		kind:       ev.GetKind().String(),
+		priority:   ev.GetPriority(),
+	}, true, nil
+}
+
+// send publishes entries sharing an exchange and routing key in one call, parking them
+// in the outbox when the retries fail. It returns the IDs of the entries that were
+// neither published nor parked.
+func (d *eventDispatcher) send(ctx context.Context, entries []outboxEntry) ([]string, error) {
+	pub, routingKey := entries[0].publisher, entries[0].routingKey
+
+	// [ORDERING] While the outbox drains, newer events queue behind the parked ones.
+	if d.outbox != nil && d.outbox.Pending() {
+		if entries = d.park(ctx, entries); len(entries) == 0 {
+			return nil, nil
+		}
+	}
+
+	msgs := make([]*message.Message, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.msg
+	}
 
 	// [ROUTING] The first argument is the Routing Key.
 	// In your Factory, GenerateRoutingKey: func(s string) string { return s }
 	// so the routing key will be exactly what 'exportable.GetRoutingKey()' returns.
-	if err := d.publisher.Publish(exportable.GetRoutingKey(), msg); err != nil {
-		return fmt.Errorf("dispatcher: publish failed: %w", err)
+	// With publisher confirms enabled in the factory, a nil error means the broker has the message.
+	attempt, sent, err := d.publishWithRetry(ctx, pub, routingKey, msgs...)
+	if err == nil {
+		return nil, nil
 	}
+	// [AT_LEAST_ONCE] Only the unsent remainder is parked or reported.
+	entries = entries[sent:]
 
-	return nil
+	if d.outbox != nil {
+		d.logger.Warn("PUBLISH_DEFERRED",
+			slog.String("routing_key", routingKey),
+			slog.Int("events", len(entries)),
+			slog.Any("err", err),
+		)
+		entries = d.park(ctx, entries)
+	}
+
+	failed := make([]string, 0, len(entries))
+	for _, e := range entries {
+		publishDropped.Add(context.Background(), 1, metric.WithAttributes(attribute.String("reason", "retries_exhausted")))
+		d.logger.Error("PUBLISH_DROPPED",
+			slog.String("routing_key", routingKey),
+			slog.String("event_id", e.eventID),
+			slog.String("kind", e.kind),
+			slog.Int("attempts", attempt),
+			slog.Any("err", err),
+		)
+		failed = append(failed, e.eventID)
+	}
+	return failed, err
+}
+
+// park hands the messages to the outbox, detached from the caller's deadline,
+// and returns the ones it refused (the outbox is closed).
+func (d *eventDispatcher) park(ctx context.Context, entries []outboxEntry) []outboxEntry {
+	var refused []outboxEntry
+	for _, e := range entries {
+		e.msg.SetContext(context.WithoutCancel(ctx))
+		if !d.outbox.Enqueue(e) {
+			refused = append(refused, e)
+		}
+	}
+	return refused
+}
+
+// publishWithRetry returns how many attempts were made, how many leading msgs went out
+// and the last error. Each retry resends only the messages from the one that failed,
+// as reported through factory.PublishObserver; publishers that do not report are
+// retried whole. It stops early once ctx is done: the caller has already given up on the event.
+func (d *eventDispatcher) publishWithRetry(ctx context.Context, pub message.Publisher, routingKey string, msgs ...*message.Message) (int, int, error) {
+	progress := newPublishProgress(msgs)
+	pause := d.backoff
+	for attempt := 1; ; attempt++ {
+		err := pub.Publish(routingKey, msgs[progress.sent():]...)
+		if err == nil {
+			return attempt, len(msgs), nil
+		}
+		if attempt >= d.attempts {
+			return attempt, progress.sent(), err
+		}
+
+		publishRetries.Add(context.Background(), 1)
+		select {
+		case <-ctx.Done():
+			return attempt, progress.sent(), errors.Join(err, ctx.Err())
+		case <-time.After(pause):
+		}
+		pause *= 2
+		if d.maxBackoff > 0 {
+			pause = min(pause, d.maxBackoff)
+		}
+	}
+}
+
+// publishProgress tracks the furthest message of a group the publisher started on.
+type publishProgress struct {
+	index   map[*message.Message]int
+	started atomic.Int64 // Index of that message; every earlier one went out
 }
 
+var _ factory.PublishObserver = (*publishProgress)(nil)
+
+// newPublishProgress attaches itself to every message of a multi-message group.
+func newPublishProgress(msgs []*message.Message) *publishProgress {
+	p := &publishProgress{}
+	if len(msgs) < 2 {
+		return p
+	}
+	p.index = make(map[*message.Message]int, len(msgs))
+	for i, m := range msgs {
+		p.index[m] = i
+		m.SetContext(factory.WithPublishObserver(m.Context(), p))
+	}
+	return p
+}
+
+func (p *publishProgress) Started(msg *message.Message) {
+	if i, ok := p.index[msg]; ok && int64(i) > p.started.Load() {
+		p.started.Store(int64(i))
+	}
+}
+
+// sent is how many leading messages are known to have gone out.
+func (p *publishProgress) sent() int { return int(p.started.Load()) }
+
 func (d *eventDispatcher) Publisher() message.Publisher { return d.publisher }
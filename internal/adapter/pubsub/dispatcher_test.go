@@ -0,0 +1,263 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/infra/pubsub/factory"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errBroker = errors.New("broker unavailable")
+
+// flakyPublisher fails the first failures calls, then records what it publishes.
+type flakyPublisher struct {
+	mu        sync.Mutex
+	failures  int
+	calls     int
+	published []string // Event IDs
+}
+
+func (p *flakyPublisher) Publish(_ string, msgs ...*message.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.calls <= p.failures {
+		return errBroker
+	}
+	for _, m := range msgs {
+		p.published = append(p.published, m.Metadata.Get(HeaderEventID))
+	}
+	return nil
+}
+
+func (*flakyPublisher) Close() error { return nil }
+
+func quietLogger() *slog.Logger { return slog.New(slog.NewTextHandler(io.Discard, nil)) }
+
+// exportable builds a message.created event with a routing key.
+func exportable() event.Eventer {
+	peer := model.Peer{ID: uuid.New(), Type: model.PeerUser, Sub: "user"}
+	msg := &model.Message{ID: uuid.New(), ThreadID: uuid.New(), DomainID: 1}
+	return event.NewMessageV1Event(msg, uuid.New(), peer, peer)
+}
+
+func TestPublishOutboxScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		prePark    bool // An earlier publish is still parked
+		wantParked bool
+	}{
+		{name: "published", failures: 0},
+		{name: "failure is parked", failures: 1, wantParked: true},
+		{name: "parked head keeps order", prePark: true, wantParked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &flakyPublisher{failures: tt.failures}
+			outbox := NewOutbox(10, time.Millisecond, time.Millisecond, quietLogger())
+			d := NewEventDispatcher(pub, WithOutbox(outbox), WithDispatcherLogger(quietLogger()))
+
+			if tt.prePark {
+				entry, _, err := d.(*eventDispatcher).envelope(context.Background(), exportable())
+				if err != nil {
+					t.Fatal(err)
+				}
+				outbox.Enqueue(entry)
+			}
+			parkedBefore := len(outbox.entries)
+
+			ev := exportable()
+			if err := d.Publish(context.Background(), ev); err != nil {
+				t.Fatalf("err: got %v, want nil", err)
+			}
+
+			parked := len(outbox.entries) > parkedBefore
+			if parked != tt.wantParked {
+				t.Fatalf("parked: got %v, want %v", parked, tt.wantParked)
+			}
+			published := len(pub.published) == 1 && pub.published[0] == ev.GetID()
+			if published == tt.wantParked {
+				t.Fatalf("published: got %v (%v), want %v", published, pub.published, !tt.wantParked)
+			}
+		})
+	}
+}
+
+// sequentialPublisher sends one message at a time like the AMQP publisher, reporting
+// each to its observer, and fails on the message numbers in failAt (1-based, across calls).
+type sequentialPublisher struct {
+	failAt    map[int]bool
+	report    bool
+	n         int
+	published []string
+}
+
+func (p *sequentialPublisher) Publish(_ string, msgs ...*message.Message) error {
+	for _, m := range msgs {
+		if p.report {
+			if o, ok := factory.PublishObserverFrom(m.Context()); ok {
+				o.Started(m)
+			}
+		}
+		p.n++
+		if p.failAt[p.n] {
+			return errBroker
+		}
+		p.published = append(p.published, m.Metadata.Get(HeaderEventID))
+	}
+	return nil
+}
+
+func (*sequentialPublisher) Close() error { return nil }
+
+func TestPublishBatchRetriesRemainder(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		failAt   []int
+		report   bool
+		attempts int
+		wantSent int // Messages that reached the broker, duplicates included
+		wantErr  bool
+	}{
+		{name: "no failure", size: 4, report: true, attempts: 3, wantSent: 4},
+		{name: "mid-group failure resumes at the failed message", size: 4, failAt: []int{3}, report: true, attempts: 3, wantSent: 4},
+		{name: "first message fails", size: 4, failAt: []int{1}, report: true, attempts: 3, wantSent: 4},
+		{name: "repeated failures keep moving forward", size: 5, failAt: []int{2, 5}, report: true, attempts: 3, wantSent: 5},
+		{name: "unreported progress retries the group whole", size: 4, failAt: []int{3}, attempts: 3, wantSent: 6},
+		{name: "exhausted retries report only the remainder", size: 4, failAt: []int{3, 4}, report: true, attempts: 2, wantSent: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &sequentialPublisher{failAt: make(map[int]bool), report: tt.report}
+			for _, n := range tt.failAt {
+				pub.failAt[n] = true
+			}
+			d := NewEventDispatcher(pub,
+				WithPublishRetry(tt.attempts, time.Millisecond, time.Millisecond),
+				WithDispatcherLogger(quietLogger()),
+			)
+
+			// Events from the same sender share a routing key, so they form one group.
+			events := make([]event.Eventer, tt.size)
+			for i := range events {
+				events[i] = exportable()
+			}
+
+			err := d.PublishBatch(context.Background(), events)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err: got %v, want error %v", err, tt.wantErr)
+			}
+			if len(pub.published) != tt.wantSent {
+				t.Fatalf("sent %d messages, want %d", len(pub.published), tt.wantSent)
+			}
+
+			var batch *BatchError
+			if errors.As(err, &batch) {
+				if want := tt.size - len(pub.published); len(batch.Failed) != want {
+					t.Fatalf("failed: got %d events, want %d", len(batch.Failed), want)
+				}
+				for _, id := range batch.Failed {
+					if slices.Contains(pub.published, id) {
+						t.Fatalf("event %s reported failed but was published", id)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestPublishSkipsImported checks that migrated history, which carries no routing key,
+// never reaches the broker, so it raises no receipts or watermarks downstream.
+func TestPublishSkipsImported(t *testing.T) {
+	imported := func() event.Eventer {
+		ev := exportable().(*event.MessageV1Event)
+		ev.Imported = true
+		return ev
+	}
+	tests := []struct {
+		name     string
+		events   []event.Eventer
+		wantSent int
+	}{
+		{name: "live event", events: []event.Eventer{exportable()}, wantSent: 1},
+		{name: "imported event", events: []event.Eventer{imported()}},
+		{name: "mixed batch", events: []event.Eventer{exportable(), imported(), exportable()}, wantSent: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &flakyPublisher{}
+			d := NewEventDispatcher(pub, WithDispatcherLogger(quietLogger()))
+
+			var err error
+			if len(tt.events) == 1 {
+				err = d.Publish(context.Background(), tt.events[0])
+			} else {
+				err = d.PublishBatch(context.Background(), tt.events)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pub.published) != tt.wantSent {
+				t.Fatalf("sent %d messages, want %d", len(pub.published), tt.wantSent)
+			}
+		})
+	}
+}
+
+// metadataPublisher keeps the metadata of every published message.
+type metadataPublisher struct{ metadata []message.Metadata }
+
+func (p *metadataPublisher) Publish(_ string, msgs ...*message.Message) error {
+	for _, m := range msgs {
+		p.metadata = append(p.metadata, m.Metadata)
+	}
+	return nil
+}
+
+func (*metadataPublisher) Close() error { return nil }
+
+func TestPublishTraceID(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "span in context", ctx: trace.ContextWithSpanContext(context.Background(), sc), want: sc.TraceID().String()},
+		{name: "consumed message", ctx: WithTraceID(context.Background(), "corr-1"), want: "corr-1"},
+		{name: "span wins over consumed message", ctx: trace.ContextWithSpanContext(WithTraceID(context.Background(), "corr-1"), sc), want: sc.TraceID().String()},
+		{name: "neither", ctx: context.Background()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &metadataPublisher{}
+			d := NewEventDispatcher(pub, WithDispatcherLogger(quietLogger()))
+			if err := d.Publish(tt.ctx, exportable()); err != nil {
+				t.Fatal(err)
+			}
+			if len(pub.metadata) != 1 {
+				t.Fatalf("published %d messages, want 1", len(pub.metadata))
+			}
+			if got := pub.metadata[0].Get(HeaderTraceID); got != tt.want {
+				t.Fatalf("%s: got %q, want %q", HeaderTraceID, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package pubsub
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var outboxDepth, _ = meter.Int64UpDownCounter(
+	"im_delivery_publisher_outbox_depth",
+	metric.WithDescription("Events waiting in the outbox for the broker to recover"),
+)
+
+var outboxFlushed, _ = meter.Int64Counter(
+	"im_delivery_publisher_outbox_flushed_total",
+	metric.WithDescription("Outbox events published after the broker recovered"),
+)
+
+// outboxEntry is one fully built message waiting for the broker.
+type outboxEntry struct {
+	publisher  message.Publisher // Bound to the event's exchange
+	exchange   string            // "" for the dispatcher's default
+	routingKey string
+	msg        *message.Message
+	eventID    string
+	kind       string
+	priority   event.EventPriority
+}
+
+// Outbox holds re-publishes that failed while the broker was unavailable.
+// Each entry keeps the publisher of its exchange.
+//
+// [STRATEGY]
+// The dispatcher parks a message here once its own retries are exhausted, and routes
+// every later message here too while anything is parked, so the bus keeps the
+// original order. A single flusher replays the head with exponential backoff.
+// [SCOPE] Re-publishes of consumed messages are parked too and their source is ACKed:
+// the recipient already has the event, so a redelivery would only duplicate it.
+// The outbox is memory only; what a crash leaves in it is lost.
+// [OVERFLOW] A full outbox sheds its oldest entry of the lowest priority first, never
+// the head being published; with nothing else to shed the new entry is dropped.
+type Outbox struct {
+	logger     *slog.Logger
+	size       int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	mu        sync.Mutex
+	entries   []outboxEntry
+	closed    bool
+	flushing  *message.Message // The head being published by the flusher, if any
+	abandoned bool             // Stop gave up waiting; the flusher owns only its in-flight head
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewOutbox buffers up to size messages; the flusher retries from backoff up to maxBackoff.
+func NewOutbox(size int, backoff, maxBackoff time.Duration, logger *slog.Logger) *Outbox {
+	return &Outbox{
+		logger:     logger,
+		size:       size,
+		backoff:    max(backoff, 10*time.Millisecond),
+		maxBackoff: max(maxBackoff, backoff),
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Pending reports whether messages are waiting; new publishes queue behind them.
+func (o *Outbox) Pending() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries) > 0
+}
+
+// Enqueue parks a message. It reports false once the outbox is closed.
+func (o *Outbox) Enqueue(e outboxEntry) bool {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return false
+	}
+	var shed *outboxEntry
+	if len(o.entries) >= o.size {
+		i := o.victim()
+		if i < 0 {
+			// [IN_FLIGHT] Only the head the flusher is publishing is left; shed e instead.
+			o.mu.Unlock()
+			o.drop(e, "outbox_overflow")
+			return true
+		}
+		victim := o.entries[i]
+		shed = &victim
+		o.entries = append(o.entries[:i], o.entries[i+1:]...)
+		outboxDepth.Add(context.Background(), -1)
+	}
+	o.entries = append(o.entries, e)
+	o.mu.Unlock()
+	outboxDepth.Add(context.Background(), 1)
+
+	if shed != nil {
+		o.drop(*shed, "outbox_overflow")
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// victim picks the oldest entry of the lowest priority, or -1 when the only one is
+// the head the flusher is publishing: shedding it would count a delivery as dropped. [LOCKED]
+func (o *Outbox) victim() int {
+	idx := -1
+	for i, e := range o.entries {
+		if o.flushing != nil && e.msg == o.flushing {
+			continue
+		}
+		if idx < 0 || e.priority < o.entries[idx].priority {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// Start runs the flusher until Stop.
+func (o *Outbox) Start() {
+	go o.run()
+}
+
+// Stop refuses new entries and keeps flushing until the outbox is empty or ctx ends;
+// whatever is left then is logged as lost. A head still being published is left to the
+// flusher, which logs it only if that publish fails.
+func (o *Outbox) Stop(ctx context.Context) {
+	o.mu.Lock()
+	o.closed = true
+	o.mu.Unlock()
+
+	close(o.stop)
+	select {
+	case <-o.done:
+	case <-ctx.Done():
+	}
+
+	o.mu.Lock()
+	left := o.entries
+	o.entries = nil
+	o.abandoned = true
+	flushing := o.flushing
+	o.mu.Unlock()
+	outboxDepth.Add(context.Background(), -int64(len(left)))
+	for _, e := range left {
+		if e.msg == flushing {
+			continue
+		}
+		o.drop(e, "shutdown")
+	}
+}
+
+func (o *Outbox) run() {
+	defer close(o.done)
+
+	pause := o.backoff
+	stopping := false
+	for {
+		head, ok := o.head()
+		if !ok {
+			if stopping {
+				return
+			}
+			select {
+			case <-o.wake:
+				continue
+			case <-o.stop:
+				stopping = true
+				continue
+			}
+		}
+
+		err := head.publisher.Publish(head.routingKey, head.msg)
+		if o.settle() {
+			// [SHUTDOWN] Stop gave up meanwhile and skipped this entry; report it only if lost.
+			if err != nil {
+				o.drop(head, "shutdown")
+			}
+			return
+		}
+		if err != nil {
+			o.logger.Debug("OUTBOX_FLUSH_FAILED", slog.String("event_id", head.eventID), slog.Any("err", err))
+			select {
+			case <-time.After(pause):
+			case <-o.stop:
+				stopping = true
+				// [SHUTDOWN] Keep trying until Stop's deadline instead of waiting out a long pause.
+				time.Sleep(o.backoff)
+			}
+			pause = min(pause*2, o.maxBackoff)
+			continue
+		}
+
+		pause = o.backoff
+		o.pop(head.msg)
+		outboxFlushed.Add(context.Background(), 1)
+	}
+}
+
+// head returns the first entry and marks it in flight.
+func (o *Outbox) head() (outboxEntry, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.entries) == 0 || o.abandoned {
+		return outboxEntry{}, false
+	}
+	o.flushing = o.entries[0].msg
+	return o.entries[0], true
+}
+
+// settle clears the in-flight mark and reports whether Stop has abandoned the outbox.
+func (o *Outbox) settle() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.flushing = nil
+	return o.abandoned
+}
+
+// pop removes msg if it is still the head; overflow may have shed it meanwhile.
+func (o *Outbox) pop(msg *message.Message) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.entries) > 0 && o.entries[0].msg == msg {
+		o.entries = o.entries[1:]
+		outboxDepth.Add(context.Background(), -1)
+	}
+}
+
+func (o *Outbox) drop(e outboxEntry, reason string) {
+	publishDropped.Add(context.Background(), 1, metric.WithAttributes(attribute.String("reason", reason)))
+	o.logger.Error("PUBLISH_DROPPED",
+		slog.String("exchange", e.exchange),
+		slog.String("routing_key", e.routingKey),
+		slog.String("event_id", e.eventID),
+		slog.String("kind", e.kind),
+		slog.String("reason", reason),
+	)
+}
@@ -0,0 +1,179 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// TestOutboxFlushAfterRecovery publishes through a broker that is down for a while and
+// checks that every event below the outbox limit reaches it, in publish order.
+func TestOutboxFlushAfterRecovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   int
+		failures int // Broker calls that fail before it recovers
+	}{
+		{name: "short outage", events: 3, failures: 1},
+		{name: "flusher retries the head", events: 5, failures: 4},
+		{name: "outbox filled to the limit", events: 8, failures: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &flakyPublisher{failures: tt.failures}
+			outbox := NewOutbox(8, time.Millisecond, 20*time.Millisecond, quietLogger())
+			d := NewEventDispatcher(pub, WithOutbox(outbox), WithDispatcherLogger(quietLogger()))
+
+			var want []string
+			for range tt.events {
+				ev := exportable()
+				want = append(want, ev.GetID())
+				if err := d.Publish(context.Background(), ev); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			outbox.Start()
+			deadline := time.Now().Add(2 * time.Second)
+			for outbox.Pending() && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+			outbox.Stop(context.Background())
+
+			pub.mu.Lock()
+			defer pub.mu.Unlock()
+			if !slices.Equal(pub.published, want) {
+				t.Fatalf("published %v, want %v", pub.published, want)
+			}
+		})
+	}
+}
+
+func TestOutboxOverflowSheds(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int                   // 3 when zero
+		inFlight   bool                  // The flusher is publishing the first entry
+		priorities []event.EventPriority // Enqueued in order
+		wantKept   []int                 // Indexes into priorities
+	}{
+		{name: "below the limit", priorities: []event.EventPriority{event.PriorityLow, event.PriorityHigh}, wantKept: []int{0, 1}},
+		{name: "lowest priority goes first", priorities: []event.EventPriority{event.PriorityHigh, event.PriorityLow, event.PriorityNormal, event.PriorityHigh}, wantKept: []int{0, 2, 3}},
+		{name: "oldest of the lowest", priorities: []event.EventPriority{event.PriorityNormal, event.PriorityLow, event.PriorityLow, event.PriorityHigh}, wantKept: []int{0, 2, 3}},
+		{name: "equal priorities shed the head", priorities: []event.EventPriority{event.PriorityNormal, event.PriorityNormal, event.PriorityNormal, event.PriorityNormal}, wantKept: []int{1, 2, 3}},
+		{name: "new entry may be shed next", priorities: []event.EventPriority{event.PriorityHigh, event.PriorityHigh, event.PriorityHigh, event.PriorityLow, event.PriorityHigh}, wantKept: []int{1, 2, 4}},
+		{name: "in-flight head is not shed", inFlight: true, priorities: []event.EventPriority{event.PriorityLow, event.PriorityNormal, event.PriorityHigh, event.PriorityHigh}, wantKept: []int{0, 2, 3}},
+		{name: "in-flight head alone sheds the new entry", size: 1, inFlight: true, priorities: []event.EventPriority{event.PriorityLow, event.PriorityHigh}, wantKept: []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := tt.size
+			if size == 0 {
+				size = 3
+			}
+			outbox := NewOutbox(size, time.Millisecond, time.Millisecond, quietLogger())
+			var ids, want []string
+			for i, p := range tt.priorities {
+				id := watermill.NewUUID()
+				ids = append(ids, id)
+				outbox.Enqueue(outboxEntry{msg: message.NewMessage(id, nil), eventID: id, priority: p})
+				if i == 0 && tt.inFlight {
+					outbox.head()
+				}
+			}
+			for _, i := range tt.wantKept {
+				want = append(want, ids[i])
+			}
+
+			var kept []string
+			for _, e := range outbox.entries {
+				kept = append(kept, e.eventID)
+			}
+			if !slices.Equal(kept, want) {
+				t.Fatalf("kept %v, want %v", kept, want)
+			}
+		})
+	}
+}
+
+// gatedPublisher blocks every publish until release delivers its result.
+type gatedPublisher struct {
+	entered chan string
+	release chan error
+}
+
+func (p *gatedPublisher) Publish(_ string, msgs ...*message.Message) error {
+	p.entered <- msgs[0].UUID
+	return <-p.release
+}
+
+func (*gatedPublisher) Close() error { return nil }
+
+// TestOutboxStopInFlightHead checks that Stop, giving up while the flusher is still
+// publishing the head, reports only that head's fate from the flusher itself.
+func TestOutboxStopInFlightHead(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      error // Outcome of the in-flight publish after Stop returns
+		wantHeadLog bool
+	}{
+		{name: "in-flight publish succeeds", result: nil},
+		{name: "in-flight publish fails", result: errBroker, wantHeadLog: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs syncBuffer
+			outbox := NewOutbox(4, time.Millisecond, time.Millisecond, slog.New(slog.NewTextHandler(&logs, nil)))
+			pub := &gatedPublisher{entered: make(chan string, 1), release: make(chan error, 1)}
+
+			head, tail := watermill.NewUUID(), watermill.NewUUID()
+			for _, id := range []string{head, tail} {
+				outbox.Enqueue(outboxEntry{publisher: pub, msg: message.NewMessage(id, nil), eventID: id})
+			}
+			outbox.Start()
+			<-pub.entered
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			outbox.Stop(ctx)
+
+			pub.release <- tt.result
+			<-outbox.done
+
+			out := logs.String()
+			if !strings.Contains(out, "event_id="+tail) {
+				t.Fatalf("queued entry not reported lost:\n%s", out)
+			}
+			if got := strings.Contains(out, "event_id="+head); got != tt.wantHeadLog {
+				t.Fatalf("head reported lost: got %v, want %v\n%s", got, tt.wantHeadLog, out)
+			}
+		})
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the flusher and the test to share.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
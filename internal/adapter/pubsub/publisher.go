@@ -1,6 +1,9 @@
 package pubsub
 
 import (
+	"errors"
+	"sync"
+
 	"github.com/ThreeDotsLabs/watermill/message"
 	infrapubsub "github.com/webitel/im-delivery-service/infra/pubsub"
 	"github.com/webitel/im-delivery-service/infra/pubsub/factory"
@@ -8,18 +11,93 @@ import (
 
 type PublisherProvider struct {
 	factory factory.Factory
+	confirm bool
+
+	// [OWNERSHIP] Publishers built here are closed by Close, once nothing publishes anymore.
+	mu     sync.Mutex
+	built  []message.Publisher
+	shared map[string]message.Publisher // Get's per-exchange cache
 }
 
-func NewPublisherProvider(p infrapubsub.Provider) *PublisherProvider {
-	return &PublisherProvider{factory: p.GetFactory()}
+// ProviderOption defines a functional configuration type for the PublisherProvider.
+type ProviderOption func(*PublisherProvider)
+
+// WithPublisherConfirms makes every publisher built afterwards wait for broker confirms.
+func WithPublisherConfirms(enabled bool) ProviderOption {
+	return func(pp *PublisherProvider) {
+		pp.confirm = enabled
+	}
+}
+
+func NewPublisherProvider(p infrapubsub.Provider, opts ...ProviderOption) *PublisherProvider {
+	pp := &PublisherProvider{factory: p.GetFactory()}
+	for _, opt := range opts {
+		opt(pp)
+	}
+	return pp
 }
 
 func (pp *PublisherProvider) Build(exchange string) (message.Publisher, error) {
-	return pp.factory.BuildPublisher(&factory.PublisherConfig{
+	pub, err := pp.factory.BuildPublisher(&factory.PublisherConfig{
 		Exchange: factory.ExchangeConfig{
 			Name:    exchange,
 			Type:    "topic",
 			Durable: true,
 		},
+		ConfirmDelivery: pp.confirm,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	pp.mu.Lock()
+	pp.built = append(pp.built, pub)
+	pp.mu.Unlock()
+	return pub, nil
+}
+
+// Get returns the shared publisher bound to exchange, building it on first use.
+// Unlike Build, every caller asking for the same exchange gets the same publisher.
+func (pp *PublisherProvider) Get(exchange string) (message.Publisher, error) {
+	pp.mu.Lock()
+	pub, ok := pp.shared[exchange]
+	pp.mu.Unlock()
+	if ok {
+		return pub, nil
+	}
+
+	pub, err := pp.Build(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	// [RACE] Another caller may have built one meanwhile; the spare stays in built and is closed with it.
+	if existing, ok := pp.shared[exchange]; ok {
+		return existing, nil
+	}
+	if pp.shared == nil {
+		pp.shared = make(map[string]message.Publisher)
+	}
+	pp.shared[exchange] = pub
+	return pub, nil
+}
+
+// Close closes every publisher built so far.
+// [STOP_ORDER] Must run after the router and every other publisher user has stopped.
+func (pp *PublisherProvider) Close() error {
+	pp.mu.Lock()
+	built := pp.built
+	pp.built = nil
+	pp.shared = nil
+	pp.mu.Unlock()
+
+	var errs []error
+	for _, pub := range built {
+		if err := pub.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
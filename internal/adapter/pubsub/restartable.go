@@ -0,0 +1,226 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var subscriberRestarts, _ = meter.Int64Counter(
+	"im_delivery_subscriber_restarts_total",
+	metric.WithDescription("Subscribers rebuilt after their AMQP channel failed"),
+)
+
+const (
+	defaultRestartMinBackoff = 500 * time.Millisecond
+	defaultRestartMaxBackoff = 60 * time.Second
+)
+
+// errSubscriberClosed stops the restart loop once Close was called.
+var errSubscriberClosed = errors.New("pubsub: subscriber closed")
+
+// SubscriberBuildFunc creates a fresh subscriber with fixed parameters.
+type SubscriberBuildFunc func() (message.Subscriber, error)
+
+// subscriberBox lets the active subscriber (an interface) live in an atomic.Pointer.
+type subscriberBox struct {
+	sub message.Subscriber
+}
+
+// RestartableSubscriber keeps a consumer alive across AMQP channel failures.
+//
+// [CHANNEL_LOSS] When the broker closes the channel, the watermill subscriber ends its
+// output stream and never consumes again. The wrapper owns the stream handed to the
+// router instead: on an unexpected end it backs off, rebuilds the subscriber with the
+// same parameters, swaps it in atomically and keeps forwarding, so the router and its
+// handler middleware never notice.
+type RestartableSubscriber struct {
+	handler string
+	build   SubscriberBuildFunc
+	logger  *slog.Logger
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	active    atomic.Pointer[subscriberBox]
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// Interface guard
+var _ message.Subscriber = (*RestartableSubscriber)(nil)
+
+// RestartOption defines a functional configuration type for the RestartableSubscriber.
+type RestartOption func(*RestartableSubscriber)
+
+// WithRestartBackoff sets the first and the maximum delay between rebuild attempts.
+func WithRestartBackoff(min, max time.Duration) RestartOption {
+	return func(s *RestartableSubscriber) {
+		s.minBackoff, s.maxBackoff = min, max
+	}
+}
+
+// NewRestartableSubscriber builds the initial subscriber; handler labels logs and metrics.
+func NewRestartableSubscriber(handler string, build SubscriberBuildFunc, logger *slog.Logger, opts ...RestartOption) (*RestartableSubscriber, error) {
+	s := &RestartableSubscriber{
+		handler:    handler,
+		build:      build,
+		logger:     logger,
+		minBackoff: defaultRestartMinBackoff,
+		maxBackoff: defaultRestartMaxBackoff,
+		closing:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	sub, err := build()
+	if err != nil {
+		return nil, err
+	}
+	s.active.Store(&subscriberBox{sub: sub})
+	return s, nil
+}
+
+// Subscribe starts consuming and returns a stream that survives subscriber rebuilds.
+func (s *RestartableSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	in, err := s.active.Load().sub.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *message.Message)
+	go s.pump(ctx, topic, in, out)
+	return out, nil
+}
+
+// Close stops the restart loop and closes the active subscriber.
+func (s *RestartableSubscriber) Close() error {
+	s.closeOnce.Do(func() { close(s.closing) })
+	return s.active.Load().sub.Close()
+}
+
+// pump forwards messages and resubscribes whenever the upstream stream ends on its own.
+func (s *RestartableSubscriber) pump(ctx context.Context, topic string, in <-chan *message.Message, out chan<- *message.Message) {
+	defer close(out)
+
+	for {
+		if !s.forward(ctx, in, out) {
+			return
+		}
+
+		var err error
+		if in, err = s.restart(ctx, topic); err != nil {
+			return
+		}
+	}
+}
+
+// forward drains in into out; it reports true only when in ended unexpectedly.
+func (s *RestartableSubscriber) forward(ctx context.Context, in <-chan *message.Message, out chan<- *message.Message) bool {
+	for {
+		select {
+		case msg, ok := <-in:
+			if !ok {
+				return ctx.Err() == nil && !s.closed()
+			}
+			// [ACK_PASSTHROUGH] The same *Message travels on, so Ack/Nack reach the broker channel that delivered it.
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return false
+			case <-s.closing:
+				return false
+			}
+		case <-ctx.Done():
+			return false
+		case <-s.closing:
+			return false
+		}
+	}
+}
+
+// restart rebuilds and resubscribes with exponential backoff until it succeeds or is stopped.
+func (s *RestartableSubscriber) restart(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	s.logger.Warn("SUBSCRIBER_CHANNEL_LOST", slog.String("handler", s.handler), slog.String("topic", topic))
+
+	for attempt := 0; ; attempt++ {
+		delay := s.backoff(attempt)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-s.closing:
+			timer.Stop()
+			return nil, errSubscriberClosed
+		}
+
+		sub, err := s.build()
+		if err != nil {
+			s.logger.Error("SUBSCRIBER_REBUILD_FAILED",
+				slog.String("handler", s.handler),
+				slog.Int("attempt", attempt+1),
+				slog.Duration("backoff", delay),
+				slog.Any("err", err),
+			)
+			continue
+		}
+
+		in, err := sub.Subscribe(ctx, topic)
+		if err != nil {
+			_ = sub.Close()
+			s.logger.Error("SUBSCRIBER_RESUBSCRIBE_FAILED",
+				slog.String("handler", s.handler),
+				slog.Int("attempt", attempt+1),
+				slog.Any("err", err),
+			)
+			continue
+		}
+
+		// [ATOMIC_SWAP] Close sees either the old or the new subscriber, never neither.
+		old := s.active.Swap(&subscriberBox{sub: sub})
+		_ = old.sub.Close()
+
+		// A Close racing the swap may have closed the old subscriber only.
+		if s.closed() {
+			_ = sub.Close()
+			return nil, errSubscriberClosed
+		}
+
+		subscriberRestarts.Add(ctx, 1, metric.WithAttributes(attribute.String("handler", s.handler)))
+		s.logger.Info("SUBSCRIBER_RESTARTED", slog.String("handler", s.handler), slog.Int("attempts", attempt+1))
+		return in, nil
+	}
+}
+
+// backoff doubles from minBackoff up to maxBackoff, with up to 50% jitter so nodes that
+// lost the broker together do not reconnect in lockstep.
+func (s *RestartableSubscriber) backoff(attempt int) time.Duration {
+	d := s.maxBackoff
+	if attempt < 32 {
+		d = min(s.minBackoff<<attempt, s.maxBackoff)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return min(d/2+rand.N(d/2+1), s.maxBackoff)
+}
+
+func (s *RestartableSubscriber) closed() bool {
+	select {
+	case <-s.closing:
+		return true
+	default:
+		return false
+	}
+}
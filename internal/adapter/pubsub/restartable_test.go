@@ -0,0 +1,198 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// chanSubscriber hands out streams the test feeds and closes; closing a stream
+// models the broker dropping the AMQP channel.
+type chanSubscriber struct {
+	mu     sync.Mutex
+	stream chan *message.Message
+	closed bool
+}
+
+func (s *chanSubscriber) Subscribe(context.Context, string) (<-chan *message.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stream = make(chan *message.Message)
+	return s.stream, nil
+}
+
+func (s *chanSubscriber) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *chanSubscriber) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// subscriberFactory fails its first failures builds, then reports every subscriber it builds on built.
+type subscriberFactory struct {
+	mu       sync.Mutex
+	failures int
+	builds   int
+	built    chan *chanSubscriber
+}
+
+func (f *subscriberFactory) build() (message.Subscriber, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.builds++
+	if f.failures > 0 {
+		f.failures--
+		return nil, errBroker
+	}
+	sub := &chanSubscriber{}
+	f.built <- sub
+	return sub, nil
+}
+
+func (f *subscriberFactory) buildCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.builds
+}
+
+func TestRestartableSubscriber(t *testing.T) {
+	const minBackoff = 500 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		failures     int                                                       // Rebuilds that fail before one succeeds
+		stop         func(s *RestartableSubscriber, cancel context.CancelFunc) // Runs right after the channel is lost; nil lets it restart
+		wantBuilds   int                                                       // Including the initial build
+		wantRestarts int64
+		wantMin      time.Duration // Bounds of the loss-to-restart delay
+		wantMax      time.Duration
+	}{
+		{
+			name:         "channel lost",
+			wantBuilds:   2,
+			wantRestarts: 1,
+			wantMin:      minBackoff / 2,
+			wantMax:      minBackoff,
+		},
+		{
+			name:         "rebuild fails twice",
+			failures:     2,
+			wantBuilds:   4,
+			wantRestarts: 1,
+			wantMin:      (minBackoff + 2*minBackoff + 4*minBackoff) / 2,
+			wantMax:      minBackoff + 2*minBackoff + 4*minBackoff,
+		},
+		{
+			name:       "closed while backing off",
+			stop:       func(s *RestartableSubscriber, _ context.CancelFunc) { _ = s.Close() },
+			wantBuilds: 1,
+		},
+		{
+			name:       "consumer context cancelled",
+			stop:       func(_ *RestartableSubscriber, cancel context.CancelFunc) { cancel() },
+			wantBuilds: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := counterTotal(t, "im_delivery_subscriber_restarts_total")
+			synctest.Test(t, func(t *testing.T) {
+				f := &subscriberFactory{built: make(chan *chanSubscriber, 8)}
+				s, err := NewRestartableSubscriber("ON_MSG_CREATED", f.build, quietLogger(), WithRestartBackoff(minBackoff, time.Minute))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer s.Close()
+				first := <-f.built
+				f.failures = tt.failures
+
+				ctx, cancel := context.WithCancel(t.Context())
+				defer cancel()
+				out, err := s.Subscribe(ctx, "im_message")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// [ACK_PASSTHROUGH] The router receives the broker's own *Message.
+				msg := message.NewMessage("before", nil)
+				first.stream <- msg
+				if got := <-out; got != msg {
+					t.Fatalf("forwarded %v, want the upstream message", got.UUID)
+				}
+
+				lostAt := time.Now()
+				close(first.stream)
+				if tt.stop != nil {
+					time.Sleep(minBackoff / 4)
+					tt.stop(s, cancel)
+					if _, ok := <-out; ok {
+						t.Fatal("stream delivered after the consumer stopped")
+					}
+					time.Sleep(time.Minute)
+				} else {
+					next := <-f.built
+					synctest.Wait()
+					if delay := time.Since(lostAt); delay < tt.wantMin || delay > tt.wantMax {
+						t.Fatalf("restarted after %v, want between %v and %v", delay, tt.wantMin, tt.wantMax)
+					}
+					if !first.isClosed() {
+						t.Fatal("lost subscriber was not closed")
+					}
+
+					msg := message.NewMessage("after", nil)
+					next.stream <- msg
+					if got := <-out; got != msg {
+						t.Fatalf("forwarded %v after the restart, want the new subscriber's message", got.UUID)
+					}
+
+					_ = s.Close()
+					if !next.isClosed() {
+						t.Fatal("Close did not reach the rebuilt subscriber")
+					}
+				}
+
+				if got := f.buildCount(); got != tt.wantBuilds {
+					t.Fatalf("built %d subscribers, want %d", got, tt.wantBuilds)
+				}
+			})
+			if got := counterTotal(t, "im_delivery_subscriber_restarts_total") - before; got != tt.wantRestarts {
+				t.Fatalf("im_delivery_subscriber_restarts_total grew by %d, want %d", got, tt.wantRestarts)
+			}
+		})
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max time.Duration
+		attempt  int
+		want     time.Duration // Upper bound; draws fall in [want/2, want]
+	}{
+		{name: "first attempt", min: 500 * time.Millisecond, max: time.Minute, attempt: 0, want: 500 * time.Millisecond},
+		{name: "doubles", min: 500 * time.Millisecond, max: time.Minute, attempt: 3, want: 4 * time.Second},
+		{name: "capped", min: 500 * time.Millisecond, max: time.Minute, attempt: 10, want: time.Minute},
+		{name: "shift overflow stays capped", min: 500 * time.Millisecond, max: time.Minute, attempt: 100, want: time.Minute},
+		{name: "disabled", attempt: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &RestartableSubscriber{minBackoff: tt.min, maxBackoff: tt.max}
+			for range 1000 {
+				if got := s.backoff(tt.attempt); got < tt.want/2 || got > tt.want {
+					t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.want/2, tt.want)
+				}
+			}
+		})
+	}
+}
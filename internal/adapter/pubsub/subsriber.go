@@ -1,6 +1,8 @@
 package pubsub
 
 import (
+	"log/slog"
+
 	"github.com/ThreeDotsLabs/watermill/message"
 	infrapubsub "github.com/webitel/im-delivery-service/infra/pubsub"
 	"github.com/webitel/im-delivery-service/infra/pubsub/factory"
@@ -34,3 +36,26 @@ func (sp *SubscriberProvider) Build(queue, exchange, routingKey string) (message
 		ExclusiveConsumer: true,  // Single consumer per channel
 	})
 }
+
+// BuildShared creates a subscriber on a durable queue that every node attaches to,
+// so each message is handled once cluster-wide (competing consumers).
+func (sp *SubscriberProvider) BuildShared(queue, exchange, routingKey string) (message.Subscriber, error) {
+	return sp.factory.BuildSubscriber("im-delivery-service", &factory.SubscriberConfig{
+		Exchange: factory.ExchangeConfig{
+			Name:    exchange,
+			Type:    "topic",
+			Durable: true,
+		},
+		Queue:        queue,
+		RoutingKey:   routingKey,
+		DurableQueue: true, // Survives broker restarts and node churn
+	})
+}
+
+// BuildRestartable wraps Build in a RestartableSubscriber, so a lost AMQP channel
+// is rebuilt with the same queue and binding instead of silently ending consumption.
+func (sp *SubscriberProvider) BuildRestartable(handler, queue, exchange, routingKey string, logger *slog.Logger) (message.Subscriber, error) {
+	return NewRestartableSubscriber(handler, func() (message.Subscriber, error) {
+		return sp.Build(queue, exchange, routingKey)
+	}, logger)
+}
@@ -0,0 +1,134 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// blockingPublisher holds every publish until release is closed, like a broker
+// that never confirms, then returns err.
+type blockingPublisher struct {
+	release chan struct{}
+	err     error
+}
+
+func (p *blockingPublisher) Publish(string, ...*message.Message) error {
+	<-p.release
+	return p.err
+}
+
+func (*blockingPublisher) Close() error { return nil }
+
+func TestPublishWithTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		blocks       bool
+		timeout      time.Duration // Passed per call; 0 uses the dispatcher default
+		cancelAfter  time.Duration // Caller cancels its own context; 0 never
+		wantErr      error
+		wantElapsed  time.Duration
+		wantTimeouts int64
+	}{
+		{name: "broker confirms", timeout: time.Second},
+		{name: "explicit timeout", blocks: true, timeout: 200 * time.Millisecond, wantErr: ErrPublishTimeout, wantElapsed: 200 * time.Millisecond, wantTimeouts: 1},
+		{name: "default timeout", blocks: true, wantErr: ErrPublishTimeout, wantElapsed: time.Second, wantTimeouts: 1},
+		{name: "caller gives up first", blocks: true, timeout: time.Second, cancelAfter: 50 * time.Millisecond, wantErr: context.Canceled, wantElapsed: 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := counterTotal(t, "im_delivery_publisher_timeout_total")
+			synctest.Test(t, func(t *testing.T) {
+				var pub message.Publisher = &flakyPublisher{}
+				if tt.blocks {
+					blocking := &blockingPublisher{release: make(chan struct{})}
+					// [CLEANUP] The abandoned publish finishes in the background once released.
+					defer synctest.Wait()
+					defer close(blocking.release)
+					pub = blocking
+				}
+				d := NewEventDispatcher(pub, WithPublisherTimeout(time.Second), WithDispatcherLogger(quietLogger()))
+
+				ctx, cancel := context.WithCancel(t.Context())
+				defer cancel()
+				if tt.cancelAfter > 0 {
+					time.AfterFunc(tt.cancelAfter, cancel)
+				}
+
+				start := time.Now()
+				err := d.PublishWithTimeout(ctx, exportable(), tt.timeout)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("PublishWithTimeout = %v, want %v", err, tt.wantErr)
+				}
+				if abandoned := errors.Is(err, ErrPublishAbandoned); abandoned != tt.blocks {
+					t.Fatalf("abandoned: got %v, want %v", abandoned, tt.blocks)
+				}
+				if elapsed := time.Since(start); elapsed != tt.wantElapsed {
+					t.Fatalf("caller released after %v, want %v", elapsed, tt.wantElapsed)
+				}
+			})
+			if got := counterTotal(t, "im_delivery_publisher_timeout_total") - before; got != tt.wantTimeouts {
+				t.Fatalf("im_delivery_publisher_timeout_total grew by %d, want %d", got, tt.wantTimeouts)
+			}
+		})
+	}
+}
+
+// TestAbandonedPublishIsParked checks that a publish the caller stopped waiting for
+// stays with the dispatcher: it lands in the outbox once the broker refuses it.
+func TestAbandonedPublishIsParked(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		pub := &blockingPublisher{release: make(chan struct{}), err: errBroker}
+		outbox := NewOutbox(10, time.Second, time.Second, quietLogger())
+		d := NewEventDispatcher(pub, WithOutbox(outbox), WithPublishRetry(3, time.Second, time.Second), WithDispatcherLogger(quietLogger()))
+
+		ev := exportable()
+		if err := d.PublishWithTimeout(t.Context(), ev, 100*time.Millisecond); !errors.Is(err, ErrPublishTimeout) {
+			t.Fatalf("PublishWithTimeout = %v, want %v", err, ErrPublishTimeout)
+		}
+		close(pub.release)
+		synctest.Wait()
+
+		if len(outbox.entries) != 1 || outbox.entries[0].eventID != ev.GetID() {
+			t.Fatalf("outbox holds %d entries, want the abandoned event", len(outbox.entries))
+		}
+	})
+}
+
+// testMetrics installs one SDK provider for the package: the global delegate forwards
+// package-level instruments only to the first provider ever set.
+var testMetrics = sync.OnceValue(func() *sdkmetric.ManualReader {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return reader
+})
+
+// counterTotal sums an int64 counter across its attribute sets.
+func counterTotal(t *testing.T, name string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := testMetrics().Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			var total int64
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				total += dp.Value
+			}
+			return total
+		}
+	}
+	return 0
+}
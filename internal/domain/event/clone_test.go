@@ -0,0 +1,118 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+func TestClone(t *testing.T) {
+	msg := func() *model.Message { return &model.Message{ID: uuid.New(), Text: "hi"} }
+	tests := []struct {
+		name      string
+		ev        Eventer
+		readdress bool // Topic events are not user-addressed
+	}{
+		{name: "message v1", ev: NewMessageV1Event(msg(), uuid.New(), model.Peer{}, model.Peer{}), readdress: true},
+		{name: "message v2", ev: NewMessageV2Event(msg(), uuid.New(), model.Peer{}, model.Peer{}), readdress: true},
+		{name: "system", ev: NewSystemEvent(uuid.New(), Ping, PriorityNormal, &model.ConnectedPayload{}), readdress: true},
+		{name: "topic", ev: NewTopicEvent("call.1", []byte(`{}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := tt.ev.GetUserID()
+			tt.ev.Encoded().StoreBytes("ws.json", []byte("cached for the original recipient"))
+
+			recipient := uuid.New()
+			c := tt.ev.Clone(recipient)
+
+			wantUser := owner
+			if tt.readdress {
+				wantUser = recipient
+			}
+			if c.GetUserID() != wantUser {
+				t.Fatalf("clone user = %s, want %s", c.GetUserID(), wantUser)
+			}
+			if tt.ev.GetUserID() != owner {
+				t.Fatal("Clone re-addressed the original")
+			}
+			if c.GetPayload() != tt.ev.GetPayload() {
+				t.Fatal("clone copied the payload instead of sharing it")
+			}
+			if c.GetID() != tt.ev.GetID() || c.GetKind() != tt.ev.GetKind() {
+				t.Fatalf("clone identity changed: %s/%v", c.GetID(), c.GetKind())
+			}
+			if _, ok := c.Encoded().Bytes("ws.json"); ok {
+				t.Fatal("clone inherited the original's wire cache")
+			}
+		})
+	}
+}
+
+func TestCloneSharedKeepsWireCache(t *testing.T) {
+	tests := []struct {
+		name      string
+		encrypted bool
+		wantShare bool
+	}{
+		{name: "plain message shares the cache", wantShare: true},
+		{name: "encrypted message falls back to Clone", encrypted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &model.Message{ID: uuid.New(), Text: "hi"}
+			if tt.encrypted {
+				msg.Encrypted = &model.EncryptedContent{}
+			}
+			ev := NewMessageV1Event(msg, uuid.New(), model.Peer{}, model.Peer{})
+			ev.Encoded().StoreBytes("ws.json", []byte("frame"))
+
+			recipient := uuid.New()
+			c := ev.CloneShared(recipient)
+			if c.GetUserID() != recipient {
+				t.Fatalf("clone user = %s, want %s", c.GetUserID(), recipient)
+			}
+			if _, ok := c.Encoded().Bytes("ws.json"); ok != tt.wantShare {
+				t.Fatalf("cache shared = %v, want %v", ok, tt.wantShare)
+			}
+		})
+	}
+}
+
+// BenchmarkFanOut compares building one event per recipient with cloning one
+// template, for a 100-member thread.
+//
+// Baseline (amd64, median of 3): construct 49035 ns/op, 54400 B/op, 500 allocs/op;
+// clone 15813 ns/op, 16192 B/op, 204 allocs/op.
+func BenchmarkFanOut(b *testing.B) {
+	const recipients = 100
+	ids := make([]uuid.UUID, recipients)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	msg := &model.Message{ID: uuid.New(), ThreadID: uuid.New(), Text: "hello", Metadata: map[string]any{"k": "v"}}
+	from := model.Peer{ID: uuid.New(), Type: model.PeerUser, Name: "Alice"}
+	to := model.Peer{ID: uuid.New(), Type: model.PeerGroup, Name: "Team"}
+
+	sink := make([]Eventer, recipients) // Keeps legs alive, as a real fan-out does
+
+	b.Run("construct", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for i, id := range ids {
+				m := *msg // Each construction needs its own message copy.
+				sink[i] = NewMessageV1Event(&m, id, from, to)
+			}
+		}
+	})
+	b.Run("clone", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			tmpl := NewMessageV1Event(msg, uuid.Nil, from, to)
+			for i, id := range ids {
+				sink[i] = tmpl.Clone(id)
+			}
+		}
+	})
+}
@@ -1,14 +1,31 @@
 package event
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
 
 type EventKind int16
 
 //go:generate stringer -type=EventKind
 const (
-	Connected      EventKind = iota + 1 // [SYSTEM]
-	Disconnected                        // [SYSTEM]
-	MessageCreated                      // [BUSINESS]
+	Connected        EventKind = iota + 1 // [SYSTEM]
+	Disconnected                          // [SYSTEM]
+	MessageCreated                        // [BUSINESS]
+	TopicMessage                          // [EPHEMERAL]
+	ReplayGap                             // [SYSTEM] Resume cursor fell out of the replay buffer
+	DomainPaused                          // [SYSTEM] Tenant maintenance window started
+	DomainResumed                         // [SYSTEM] Tenant maintenance window ended; held events follow
+	DeliveryDegraded                      // [SYSTEM] Session shed enough events that the client should resync
+	PresenceChanged                       // [BUS] First session attached / last one detached on this node
+	MessageDeleted                        // [BUSINESS]
+	MessageUpdated                        // [BUSINESS] Edited body; EditedAt is set
+	Typing                                // [TRANSIENT] Peer is composing; shed first, never re-published
+	ReadReceipt                           // [BUSINESS] Addressed to the sender of the messages read
+	MessageReaction                       // [BUSINESS] Emoji added or removed; addressed to the message author
+	PresenceStatus                        // [SYSTEM] A watched contact's status changed
+	Ping                                  // [SYSTEM] Idle-stream heartbeat; written by the transport, never routed
+	MessageDelivered                      // [BUS] An event reached a client's wire; never delivered to sessions
 )
 
 type EventPriority int32
@@ -27,8 +44,19 @@ type Eventer interface {
 	GetPriority() EventPriority
 	GetOccurredAt() int64
 	GetPayload() any
-	GetCached() any
-	SetCached(any)
+
+	// Encoded is the shared wire cache of this event; wrappers forward to the wrapped event's.
+	// [CONCURRENCY] The holder is set before the event is published and never replaced,
+	// so sessions may marshal one event from any number of goroutines.
+	Encoded() *model.EncodedEvent
+
+	// [E2EE] Encrypted events carry only ciphertext; the pipeline must not read or enrich them.
+	IsEncrypted() bool
+	GetEncryptedPayload() *model.EncryptedPayload
+
+	// Clone returns a shallow copy addressed to another recipient.
+	// The payload pointer is shared (treated as immutable); the transport cache is reset.
+	Clone(userID uuid.UUID) Eventer
 }
 
 // Exportable defines an event that should be re-published to the message bus.
@@ -37,3 +65,15 @@ type Exportable interface {
 	// If it returns an empty string, the binder will skip publishing.
 	GetRoutingKey() string
 }
+
+// ExchangeProvider is implemented by Exportable events that belong on an exchange
+// other than the dispatcher's default. An empty name keeps the default.
+type ExchangeProvider interface {
+	GetExchange() string
+}
+
+// Ephemeral marks an event that is delivered only to live topic subscribers.
+// Such events are never replayed, receipted or re-published to the bus.
+type Ephemeral interface {
+	IsEphemeral() bool
+}
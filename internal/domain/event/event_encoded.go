@@ -0,0 +1,35 @@
+package event
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EncodingKey names the cached encoding of ev for one transport and frame version.
+// The cursor is part of the key because JSON frames carry it. ok is false for
+// per-session wrappers (replayed, shaped) whose frames must not be shared.
+func EncodingKey(transport string, version int, ev Eventer) (key string, ok bool) {
+	if IsReplayed(ev) {
+		return "", false
+	}
+	for e := ev; e != nil; {
+		if _, shaped := e.(Shaped); shaped {
+			return "", false
+		}
+		w, isWrapper := e.(Wrapper)
+		if !isWrapper {
+			break
+		}
+		e = w.Unwrap()
+	}
+
+	var b strings.Builder
+	b.WriteString(transport)
+	b.WriteByte('/')
+	b.WriteString(strconv.Itoa(version))
+	if cur, ok := CursorOf(ev); ok {
+		b.WriteByte('@')
+		b.WriteString(cur.String())
+	}
+	return b.String(), true
+}
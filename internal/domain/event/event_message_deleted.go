@@ -0,0 +1,65 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+var (
+	_ Eventer    = (*MessageDeletedEvent)(nil)
+	_ Exportable = (*MessageDeletedEvent)(nil)
+)
+
+// MessageDeletedEvent tells one recipient that a message was removed from a thread.
+// Like MessageV1Event, UserID is the physical recipient of this instance.
+type MessageDeletedEvent struct {
+	ID        uuid.UUID
+	Deleted   *model.MessageDeleted `json:"message"`
+	UserID    uuid.UUID             `json:"user_id"`
+	Encodings *model.EncodedEvent   `json:"-"` // [INTERNAL] Per-transport wire cache, shared by the recipient's sessions
+}
+
+func NewMessageDeletedEvent(deleted *model.MessageDeleted, userID uuid.UUID) *MessageDeletedEvent {
+	return &MessageDeletedEvent{
+		ID:        newUUID(),
+		Deleted:   deleted,
+		UserID:    userID,
+		Encodings: new(model.EncodedEvent),
+	}
+}
+
+func (e *MessageDeletedEvent) GetID() string                { return e.ID.String() }
+func (e *MessageDeletedEvent) GetPayload() any              { return e.Deleted }
+func (e *MessageDeletedEvent) GetUserID() uuid.UUID         { return e.UserID }
+func (e *MessageDeletedEvent) GetOccurredAt() int64         { return e.Deleted.DeletedAt }
+func (e *MessageDeletedEvent) GetKind() EventKind           { return MessageDeleted }
+func (e *MessageDeletedEvent) GetPriority() EventPriority   { return PriorityHigh }
+func (e *MessageDeletedEvent) Encoded() *model.EncodedEvent { return e.Encodings }
+
+func (e *MessageDeletedEvent) IsEncrypted() bool                            { return false }
+func (e *MessageDeletedEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+// Clone re-addresses the event for [FAN_OUT].
+func (e *MessageDeletedEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.UserID = userID
+	c.Encodings = new(model.EncodedEvent)
+	return &c
+}
+
+// MarshalJSON keeps the top-level domain_id that bus consumers read.
+func (e *MessageDeletedEvent) MarshalJSON() ([]byte, error) {
+	type wire MessageDeletedEvent
+	return json.Marshal(struct {
+		*wire
+		DomainID int64 `json:"domain_id"`
+	}{(*wire)(e), e.Deleted.DomainID})
+}
+
+// GetRoutingKey pattern: im_delivery.v1.{domain_id}.message.deleted
+func (e *MessageDeletedEvent) GetRoutingKey() string {
+	return fmt.Sprintf("im_delivery.v1.%d.message.deleted", e.Deleted.DomainID)
+}
@@ -0,0 +1,41 @@
+package event
+
+import (
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+var (
+	_ Eventer    = (*MessageUpdatedEvent)(nil)
+	_ Exportable = (*MessageUpdatedEvent)(nil)
+
+	_ SamplingCarrier = (*MessageUpdatedEvent)(nil)
+	_ Timed           = (*MessageUpdatedEvent)(nil)
+)
+
+// MessageUpdatedEvent carries an edited message. It shares the MessageV1Event wire
+// shape and fan-out semantics; only the kind, timestamp and routing key differ.
+type MessageUpdatedEvent struct {
+	MessageV1Event
+}
+
+// NewMessageUpdatedEvent binds the bare peers like NewMessageV1Event; msg.EditedAt must be set.
+func NewMessageUpdatedEvent(msg *model.Message, userID uuid.UUID, from, to model.Peer) *MessageUpdatedEvent {
+	return &MessageUpdatedEvent{MessageV1Event: *NewMessageV1Event(msg, userID, from, to)}
+}
+
+func (e *MessageUpdatedEvent) GetKind() EventKind   { return MessageUpdated }
+func (e *MessageUpdatedEvent) GetOccurredAt() int64 { return e.Message.EditedAt }
+
+// Clone re-addresses the event for [FAN_OUT] without rebuilding the message.
+func (e *MessageUpdatedEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.UserID = userID
+	c.Encodings = new(model.EncodedEvent)
+	return &c
+}
+
+// GetRoutingKey pattern: im_delivery.v1.{domain_id}.{peer_type}.{subject}.message.updated
+func (e *MessageUpdatedEvent) GetRoutingKey() string {
+	return messageRoutingKey(e.Message, "updated")
+}
@@ -1,6 +1,7 @@
 package event
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -11,6 +12,9 @@ import (
 var (
 	_ Eventer    = (*MessageV1Event)(nil)
 	_ Exportable = (*MessageV1Event)(nil)
+
+	_ SamplingCarrier = (*MessageV1Event)(nil)
+	_ Timed           = (*MessageV1Event)(nil)
 )
 
 // MessageV1Event is a domain event wrapper that facilitates the "Fan-out" delivery pattern.
@@ -23,11 +27,13 @@ var (
 // This allows "Stateless Horizontal Scaling" where every node can check
 // hub.IsConnected(UserID) to decide if it should handle the delivery.
 type MessageV1Event struct {
-	ID       uuid.UUID
-	Message  *model.Message `json:"message"`
-	UserID   uuid.UUID      `json:"user_id"` // [PHYSICAL_RECIPIENT] Target user ID
-	DomainID int64          `json:"domain_id"`
-	Cached   any            `json:"-"` // [INTERNAL] Not for serialization
+	ID        uuid.UUID
+	Message   *model.Message      `json:"message"`
+	UserID    uuid.UUID           `json:"user_id"`            // [PHYSICAL_RECIPIENT] Target user ID
+	Imported  bool                `json:"imported,omitempty"` // [MIGRATION] Historical record; never re-published
+	Encodings *model.EncodedEvent `json:"-"`                  // [INTERNAL] Per-transport wire cache, shared by the recipient's sessions
+	Sampling  *SamplingMark       `json:"-"`                  // [ANALYTICS] Shared by all fan-out legs
+	Stages    StageTimes          `json:"-"`                  // [STAGE_TIMING] Stamped before fan-out
 }
 
 // NewMessageV1Event initializes the event and binds enriched peers.
@@ -40,30 +46,87 @@ func NewMessageV1Event(msg *model.Message, userID uuid.UUID, from, to model.Peer
 	msg.To = to
 
 	return &MessageV1Event{
-		ID:       uuid.New(),
-		Message:  msg,
-		UserID:   userID, // Used by the Hub to find the local WebSocket connection
-		DomainID: msg.DomainID,
+		ID:        newUUID(),
+		Message:   msg,
+		UserID:    userID, // Used by the Hub to find the local WebSocket connection
+		Sampling:  new(SamplingMark),
+		Encodings: new(model.EncodedEvent),
+	}
+}
+
+func (e *MessageV1Event) GetID() string        { return e.ID.String() }
+func (e *MessageV1Event) GetPayload() any      { return e.Message }
+func (e *MessageV1Event) GetUserID() uuid.UUID { return e.UserID }
+func (e *MessageV1Event) GetOccurredAt() int64 { return e.Message.CreatedAt }
+func (e *MessageV1Event) GetKind() EventKind   { return MessageCreated }
+func (e *MessageV1Event) GetPriority() EventPriority {
+	// [MIGRATION] Imported history yields to live traffic.
+	if e.Imported {
+		return PriorityLow
+	}
+	return PriorityHigh
+}
+func (e *MessageV1Event) Encoded() *model.EncodedEvent { return e.Encodings }
+
+func (e *MessageV1Event) IsEncrypted() bool { return e.Message.IsEncrypted() }
+func (e *MessageV1Event) GetEncryptedPayload() *model.EncryptedPayload {
+	if !e.IsEncrypted() {
+		return nil
+	}
+	return e.Message.Encrypted.Payload()
+}
+
+func (e *MessageV1Event) GetStageTimes() *StageTimes { return &e.Stages }
+
+func (e *MessageV1Event) GetSamplingMark() *SamplingMark { return e.Sampling }
+
+// Clone re-addresses the event for [FAN_OUT] without rebuilding the message.
+func (e *MessageV1Event) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.UserID = userID
+	c.Encodings = new(model.EncodedEvent)
+	return &c
+}
+
+// CloneShared re-addresses the event like Clone but keeps the wire cache, so every
+// leg of a group fan-out reuses one encoding. Encrypted bodies are wrapped per
+// recipient and fall back to Clone.
+func (e *MessageV1Event) CloneShared(userID uuid.UUID) Eventer {
+	if e.IsEncrypted() {
+		return e.Clone(userID)
 	}
+	c := *e
+	c.UserID = userID
+	return &c
 }
 
-func (e *MessageV1Event) GetID() string              { return e.ID.String() }
-func (e *MessageV1Event) GetPayload() any            { return e.Message }
-func (e *MessageV1Event) GetUserID() uuid.UUID       { return e.UserID }
-func (e *MessageV1Event) GetOccurredAt() int64       { return e.Message.CreatedAt }
-func (e *MessageV1Event) GetKind() EventKind         { return MessageCreated }
-func (e *MessageV1Event) GetPriority() EventPriority { return PriorityHigh }
-func (e *MessageV1Event) GetCached() any             { return e.Cached }
-func (e *MessageV1Event) SetCached(v any)            { e.Cached = v }
+// MarshalJSON keeps the top-level domain_id that bus consumers read; the
+// tenant itself lives on the message (single source of truth).
+func (e *MessageV1Event) MarshalJSON() ([]byte, error) {
+	type wire MessageV1Event
+	return json.Marshal(struct {
+		*wire
+		DomainID int64 `json:"domain_id"`
+	}{(*wire)(e), e.Message.DomainID})
+}
 
 // GetRoutingKey generates RabbitMQ routing topic based on domain requirements.
 // Pattern: im_delivery.v1.{domain_id}.{peer_type}.{subject}.message.created
 func (e *MessageV1Event) GetRoutingKey() string {
+	// [SUPPRESSION] Imported records already happened on the legacy platform.
+	if e.Imported {
+		return ""
+	}
+	return messageRoutingKey(e.Message, "created")
+}
+
+// messageRoutingKey builds im_delivery.v1.{domain_id}.{peer_type}.{subject}.message.{action}.
+func messageRoutingKey(msg *model.Message, action string) string {
 	// Default peer type is contact
 	peerType := "contact"
 
 	// Normalize issuer to lowercase for reliable comparison
-	issuer := strings.ToLower(e.Message.To.Issuer)
+	issuer := strings.ToLower(msg.To.Issuer)
 
 	// [STRATEGY] If issuer contains 'bot' or 'schema', classify as bot routing
 	if strings.Contains(issuer, "bot") || strings.Contains(issuer, "schema") {
@@ -71,9 +134,10 @@ func (e *MessageV1Event) GetRoutingKey() string {
 	}
 
 	// [ROUTING] Build the final RabbitMQ topic string
-	return fmt.Sprintf("im_delivery.v1.%d.%s.%s.message.created",
-		e.Message.DomainID,
+	return fmt.Sprintf("im_delivery.v1.%d.%s.%s.message.%s",
+		msg.DomainID,
 		peerType,
-		e.Message.To.Sub,
+		msg.To.Sub,
+		action,
 	)
 }
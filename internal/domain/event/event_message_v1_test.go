@@ -0,0 +1,52 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+func TestMessageV1RoutingKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		domainID int64
+		to       model.Peer
+		imported bool
+		want     string
+	}{
+		{name: "contact", domainID: 7, to: model.Peer{Sub: "alice", Issuer: "webitel"}, want: "im_delivery.v1.7.contact.alice.message.created"},
+		{name: "bot issuer", domainID: 7, to: model.Peer{Sub: "b1", Issuer: "Bot-Gateway"}, want: "im_delivery.v1.7.bot.b1.message.created"},
+		{name: "schema issuer", domainID: 3, to: model.Peer{Sub: "s1", Issuer: "schema"}, want: "im_delivery.v1.3.bot.s1.message.created"},
+		{name: "wide domain id", domainID: 1 << 40, to: model.Peer{Sub: "alice"}, want: "im_delivery.v1.1099511627776.contact.alice.message.created"},
+		{name: "imported history is not exported", domainID: 7, to: model.Peer{Sub: "alice"}, imported: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := NewMessageV1Event(&model.Message{ID: uuid.New(), DomainID: tt.domainID}, uuid.New(), model.Peer{}, tt.to)
+			ev.Imported = tt.imported
+			if got := ev.GetRoutingKey(); got != tt.want {
+				t.Fatalf("GetRoutingKey = %q, want %q", got, tt.want)
+			}
+
+			// [WIRE_COMPAT] Bus consumers still read the tenant at the top level.
+			b, err := json.Marshal(ev)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var wire struct {
+				DomainID int64 `json:"domain_id"`
+				Message  struct {
+					DomainID int64 `json:"domain_id"`
+				} `json:"message"`
+			}
+			if err := json.Unmarshal(b, &wire); err != nil {
+				t.Fatal(err)
+			}
+			if wire.DomainID != tt.domainID || wire.Message.DomainID != tt.domainID {
+				t.Fatalf("domain_id on the wire: top level %d, message %d, want %d", wire.DomainID, wire.Message.DomainID, tt.domainID)
+			}
+		})
+	}
+}
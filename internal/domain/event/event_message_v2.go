@@ -19,7 +19,7 @@ type MessageV2Event struct {
 	ID      uuid.UUID
 	message *model.Message
 	userID  uuid.UUID
-	cached  any
+	encoded *model.EncodedEvent
 }
 
 // NewMessageV2Event initializes the event with pre-resolved peers and domain entity
@@ -27,20 +27,35 @@ func NewMessageV2Event(msg *model.Message, userID uuid.UUID, from, to model.Peer
 	msg.From = from
 	msg.To = to
 	return &MessageV2Event{
-		ID:      uuid.New(),
+		ID:      newUUID(),
 		message: msg,
 		userID:  userID,
+		encoded: new(model.EncodedEvent),
 	}
 }
 
-func (e *MessageV2Event) GetID() string              { return e.ID.String() }
-func (e *MessageV2Event) GetPayload() any            { return e.message }
-func (e *MessageV2Event) GetUserID() uuid.UUID       { return e.userID }
-func (e *MessageV2Event) GetOccurredAt() int64       { return e.message.CreatedAt }
-func (e *MessageV2Event) GetKind() EventKind         { return MessageCreated }
-func (e *MessageV2Event) GetPriority() EventPriority { return PriorityHigh }
-func (e *MessageV2Event) GetCached() any             { return e.cached }
-func (e *MessageV2Event) SetCached(v any)            { e.cached = v }
+func (e *MessageV2Event) GetID() string                { return e.ID.String() }
+func (e *MessageV2Event) GetPayload() any              { return e.message }
+func (e *MessageV2Event) GetUserID() uuid.UUID         { return e.userID }
+func (e *MessageV2Event) GetOccurredAt() int64         { return e.message.CreatedAt }
+func (e *MessageV2Event) GetKind() EventKind           { return MessageCreated }
+func (e *MessageV2Event) GetPriority() EventPriority   { return PriorityHigh }
+func (e *MessageV2Event) Encoded() *model.EncodedEvent { return e.encoded }
+func (e *MessageV2Event) IsEncrypted() bool            { return e.message.IsEncrypted() }
+func (e *MessageV2Event) GetEncryptedPayload() *model.EncryptedPayload {
+	if !e.IsEncrypted() {
+		return nil
+	}
+	return e.message.Encrypted.Payload()
+}
+
+// Clone re-addresses the event for [FAN_OUT] without rebuilding the message.
+func (e *MessageV2Event) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.userID = userID
+	c.encoded = new(model.EncodedEvent)
+	return &c
+}
 
 // GetRoutingKey for V2: im_delivery.message.v2.{sub}.{issuer}.{domain}.processed
 func (e *MessageV2Event) GetRoutingKey() string {
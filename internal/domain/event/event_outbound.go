@@ -0,0 +1,61 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+var (
+	_ Eventer          = (*OutboundEvent)(nil)
+	_ Exportable       = (*OutboundEvent)(nil)
+	_ ExchangeProvider = (*OutboundEvent)(nil)
+)
+
+// OutboundEvent is a client-originated payload relayed to the bus verbatim.
+// It never reaches the Hub: the body is already in the consumer's wire format.
+type OutboundEvent struct {
+	ID         uuid.UUID
+	Kind       EventKind
+	UserID     uuid.UUID // The client that sent it
+	RoutingKey string
+	Exchange   string // "" publishes to the dispatcher's default exchange
+	Body       json.RawMessage
+	OccurredAt int64
+}
+
+func NewOutboundEvent(kind EventKind, userID uuid.UUID, routingKey string, body json.RawMessage) *OutboundEvent {
+	return &OutboundEvent{
+		ID:         newUUID(),
+		Kind:       kind,
+		UserID:     userID,
+		RoutingKey: routingKey,
+		Body:       body,
+		OccurredAt: time.Now().UnixMilli(),
+	}
+}
+
+func (e *OutboundEvent) GetID() string                { return e.ID.String() }
+func (e *OutboundEvent) GetPayload() any              { return e.Body }
+func (e *OutboundEvent) GetUserID() uuid.UUID         { return e.UserID }
+func (e *OutboundEvent) GetOccurredAt() int64         { return e.OccurredAt }
+func (e *OutboundEvent) GetKind() EventKind           { return e.Kind }
+func (e *OutboundEvent) GetPriority() EventPriority   { return PriorityLow }
+func (e *OutboundEvent) Encoded() *model.EncodedEvent { return nil } // Never delivered to sessions
+
+func (e *OutboundEvent) IsEncrypted() bool                            { return false }
+func (e *OutboundEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+func (e *OutboundEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.UserID = userID
+	return &c
+}
+
+// MarshalJSON emits the body alone: consumers decode it as the producer's contract.
+func (e *OutboundEvent) MarshalJSON() ([]byte, error) { return e.Body, nil }
+
+func (e *OutboundEvent) GetRoutingKey() string { return e.RoutingKey }
+func (e *OutboundEvent) GetExchange() string   { return e.Exchange }
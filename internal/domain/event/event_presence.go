@@ -0,0 +1,72 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// [GUARD] Ensure compliance with the Eventer and Exportable interfaces.
+var (
+	_ Eventer    = (*PresenceEvent)(nil)
+	_ Exportable = (*PresenceEvent)(nil)
+)
+
+// PresenceEvent is an outbound-only notice of a node-local presence transition.
+// It is published to the bus and never delivered to client sessions.
+type PresenceEvent struct {
+	id      string
+	userID  uuid.UUID
+	payload *model.PresencePayload
+	encoded *model.EncodedEvent
+}
+
+// NewPresenceEvent builds the notice; at is Unix ms.
+func NewPresenceEvent(userID uuid.UUID, domainID int64, online bool, nodeID string, at int64) *PresenceEvent {
+	return &PresenceEvent{
+		id:     newID(),
+		userID: userID,
+		payload: &model.PresencePayload{
+			UserID:   userID.String(),
+			DomainID: domainID,
+			Online:   online,
+			NodeID:   nodeID,
+			At:       at,
+		},
+		encoded: new(model.EncodedEvent),
+	}
+}
+
+func (e *PresenceEvent) GetID() string                { return e.id }
+func (e *PresenceEvent) GetKind() EventKind           { return PresenceChanged }
+func (e *PresenceEvent) GetUserID() uuid.UUID         { return e.userID }
+func (e *PresenceEvent) GetPriority() EventPriority   { return PriorityNormal }
+func (e *PresenceEvent) GetOccurredAt() int64         { return e.payload.At }
+func (e *PresenceEvent) GetPayload() any              { return e.payload }
+func (e *PresenceEvent) Encoded() *model.EncodedEvent { return e.encoded }
+func (e *PresenceEvent) IsEncrypted() bool            { return false }
+
+func (e *PresenceEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+// Clone re-addresses the event; the payload keeps naming the original user.
+func (e *PresenceEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.userID = userID
+	c.encoded = new(model.EncodedEvent)
+	return &c
+}
+
+// GetRoutingKey pattern: im_delivery.v1.{domain_id}.presence.changed
+func (e *PresenceEvent) GetRoutingKey() string {
+	return fmt.Sprintf("im_delivery.v1.%d.presence.changed", e.payload.DomainID)
+}
+
+// MarshalJSON publishes the flat payload bus consumers read, tagged with the event ID.
+func (e *PresenceEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID string `json:"id"`
+		*model.PresencePayload
+	}{e.id, e.payload})
+}
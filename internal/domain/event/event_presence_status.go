@@ -0,0 +1,47 @@
+package event
+
+import (
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// [GUARD] Ensure compliance with the Eventer interface.
+// Not Exportable: it is a node-local fan-out of a bus event, not a new fact.
+var _ Eventer = (*PresenceStatusEvent)(nil)
+
+// PresenceStatusEvent tells one watcher that a contact's status changed.
+type PresenceStatusEvent struct {
+	id      string
+	userID  uuid.UUID
+	payload *model.PresenceStatusPayload
+	encoded *model.EncodedEvent
+}
+
+// NewPresenceStatusEvent addresses the change to watcherID; the payload is shared.
+func NewPresenceStatusEvent(payload *model.PresenceStatusPayload, watcherID uuid.UUID) *PresenceStatusEvent {
+	return &PresenceStatusEvent{
+		id:      newID(),
+		userID:  watcherID,
+		payload: payload,
+		encoded: new(model.EncodedEvent),
+	}
+}
+
+func (e *PresenceStatusEvent) GetID() string                { return e.id }
+func (e *PresenceStatusEvent) GetKind() EventKind           { return PresenceStatus }
+func (e *PresenceStatusEvent) GetUserID() uuid.UUID         { return e.userID }
+func (e *PresenceStatusEvent) GetPriority() EventPriority   { return PriorityNormal }
+func (e *PresenceStatusEvent) GetOccurredAt() int64         { return e.payload.At }
+func (e *PresenceStatusEvent) GetPayload() any              { return e.payload }
+func (e *PresenceStatusEvent) Encoded() *model.EncodedEvent { return e.encoded }
+func (e *PresenceStatusEvent) IsEncrypted() bool            { return false }
+
+func (e *PresenceStatusEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+// Clone re-addresses the event for [FAN_OUT].
+func (e *PresenceStatusEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.userID = userID
+	c.encoded = new(model.EncodedEvent)
+	return &c
+}
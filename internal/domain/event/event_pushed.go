@@ -0,0 +1,22 @@
+package event
+
+// [GUARD] Ensure compliance with the Eventer and Wrapper interfaces.
+var (
+	_ Eventer = (*PushedEvent)(nil)
+	_ Wrapper = (*PushedEvent)(nil)
+)
+
+// PushedEvent tags an event injected by internal tooling and pins its priority.
+// [PRIORITY_CAP] A pushed event never outranks live traffic, whatever its kind defaults to.
+type PushedEvent struct {
+	Eventer
+	Priority EventPriority
+}
+
+// NewPushedEvent wraps ev; priority is clamped to [PriorityLow, PriorityNormal].
+func NewPushedEvent(ev Eventer, priority EventPriority) *PushedEvent {
+	return &PushedEvent{Eventer: ev, Priority: min(max(priority, PriorityLow), PriorityNormal)}
+}
+
+func (e *PushedEvent) GetPriority() EventPriority { return e.Priority }
+func (e *PushedEvent) Unwrap() Eventer            { return e.Eventer }
@@ -0,0 +1,65 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+var (
+	_ Eventer    = (*ReactionEvent)(nil)
+	_ Exportable = (*ReactionEvent)(nil)
+)
+
+// ReactionEvent tells a message author that a peer added or removed a reaction.
+// UserID is the author (the recipient of this instance), not the actor.
+type ReactionEvent struct {
+	ID        uuid.UUID
+	Reaction  *model.Reaction     `json:"reaction"`
+	UserID    uuid.UUID           `json:"user_id"`
+	Encodings *model.EncodedEvent `json:"-"` // [INTERNAL] Per-transport wire cache, shared by the recipient's sessions
+}
+
+func NewReactionEvent(reaction *model.Reaction, userID uuid.UUID) *ReactionEvent {
+	return &ReactionEvent{
+		ID:        newUUID(),
+		Reaction:  reaction,
+		UserID:    userID,
+		Encodings: new(model.EncodedEvent),
+	}
+}
+
+func (e *ReactionEvent) GetID() string                { return e.ID.String() }
+func (e *ReactionEvent) GetPayload() any              { return e.Reaction }
+func (e *ReactionEvent) GetUserID() uuid.UUID         { return e.UserID }
+func (e *ReactionEvent) GetOccurredAt() int64         { return e.Reaction.At }
+func (e *ReactionEvent) GetKind() EventKind           { return MessageReaction }
+func (e *ReactionEvent) GetPriority() EventPriority   { return PriorityLow }
+func (e *ReactionEvent) Encoded() *model.EncodedEvent { return e.Encodings }
+
+func (e *ReactionEvent) IsEncrypted() bool                            { return false }
+func (e *ReactionEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+// Clone re-addresses the event for [FAN_OUT].
+func (e *ReactionEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.UserID = userID
+	c.Encodings = new(model.EncodedEvent)
+	return &c
+}
+
+// MarshalJSON keeps the top-level domain_id that bus consumers read.
+func (e *ReactionEvent) MarshalJSON() ([]byte, error) {
+	type wire ReactionEvent
+	return json.Marshal(struct {
+		*wire
+		DomainID int64 `json:"domain_id"`
+	}{(*wire)(e), e.Reaction.DomainID})
+}
+
+// GetRoutingKey pattern: im_delivery.v1.{domain_id}.message.reaction
+func (e *ReactionEvent) GetRoutingKey() string {
+	return fmt.Sprintf("im_delivery.v1.%d.message.reaction", e.Reaction.DomainID)
+}
@@ -0,0 +1,65 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+var (
+	_ Eventer    = (*ReadReceiptEvent)(nil)
+	_ Exportable = (*ReadReceiptEvent)(nil)
+)
+
+// ReadReceiptEvent tells a sender that a reader caught up to a message in a thread.
+// UserID is the sender (the recipient of the receipt), not the reader.
+type ReadReceiptEvent struct {
+	ID        uuid.UUID
+	Read      *model.MessageRead  `json:"read"`
+	UserID    uuid.UUID           `json:"user_id"`
+	Encodings *model.EncodedEvent `json:"-"` // [INTERNAL] Per-transport wire cache, shared by the recipient's sessions
+}
+
+func NewReadReceiptEvent(read *model.MessageRead, userID uuid.UUID) *ReadReceiptEvent {
+	return &ReadReceiptEvent{
+		ID:        newUUID(),
+		Read:      read,
+		UserID:    userID,
+		Encodings: new(model.EncodedEvent),
+	}
+}
+
+func (e *ReadReceiptEvent) GetID() string                { return e.ID.String() }
+func (e *ReadReceiptEvent) GetPayload() any              { return e.Read }
+func (e *ReadReceiptEvent) GetUserID() uuid.UUID         { return e.UserID }
+func (e *ReadReceiptEvent) GetOccurredAt() int64         { return e.Read.ReadAt }
+func (e *ReadReceiptEvent) GetKind() EventKind           { return ReadReceipt }
+func (e *ReadReceiptEvent) GetPriority() EventPriority   { return PriorityNormal }
+func (e *ReadReceiptEvent) Encoded() *model.EncodedEvent { return e.Encodings }
+
+func (e *ReadReceiptEvent) IsEncrypted() bool                            { return false }
+func (e *ReadReceiptEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+// Clone re-addresses the event for [FAN_OUT].
+func (e *ReadReceiptEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.UserID = userID
+	c.Encodings = new(model.EncodedEvent)
+	return &c
+}
+
+// MarshalJSON keeps the top-level domain_id that bus consumers read.
+func (e *ReadReceiptEvent) MarshalJSON() ([]byte, error) {
+	type wire ReadReceiptEvent
+	return json.Marshal(struct {
+		*wire
+		DomainID int64 `json:"domain_id"`
+	}{(*wire)(e), e.Read.DomainID})
+}
+
+// GetRoutingKey pattern: im_delivery.v1.{domain_id}.message.read
+func (e *ReadReceiptEvent) GetRoutingKey() string {
+	return fmt.Sprintf("im_delivery.v1.%d.message.read", e.Read.DomainID)
+}
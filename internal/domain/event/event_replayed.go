@@ -0,0 +1,41 @@
+package event
+
+// Replayed is implemented by events re-sent from the replay buffer on resume.
+type Replayed interface {
+	IsReplayed() bool
+}
+
+// [GUARD] Ensure compliance with the Eventer, Replayed and Wrapper interfaces.
+var (
+	_ Eventer  = (*ReplayedEvent)(nil)
+	_ Replayed = (*ReplayedEvent)(nil)
+	_ Wrapper  = (*ReplayedEvent)(nil)
+)
+
+// ReplayedEvent tags a retained event sent ahead of the live feed on resume, so clients
+// can tell catch-up from new traffic. The ring entry itself is shared and left untouched.
+type ReplayedEvent struct {
+	Eventer
+}
+
+func NewReplayedEvent(ev Eventer) *ReplayedEvent {
+	return &ReplayedEvent{Eventer: ev}
+}
+
+func (e *ReplayedEvent) IsReplayed() bool { return true }
+func (e *ReplayedEvent) Unwrap() Eventer  { return e.Eventer }
+
+// IsReplayed reports whether ev, or any event it wraps, came from the replay buffer.
+func IsReplayed(ev Eventer) bool {
+	for ev != nil {
+		if r, ok := ev.(Replayed); ok {
+			return r.IsReplayed()
+		}
+		w, ok := ev.(Wrapper)
+		if !ok {
+			break
+		}
+		ev = w.Unwrap()
+	}
+	return false
+}
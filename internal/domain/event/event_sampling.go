@@ -0,0 +1,22 @@
+package event
+
+import "sync"
+
+// SamplingMark caches the analytics sampling decision for one source message.
+// [FAN_OUT_CONSISTENCY] Clones share the pointer, so every recipient leg sees the
+// decision taken by whichever leg asked first.
+type SamplingMark struct {
+	once    sync.Once
+	sampled bool
+}
+
+// Decide evaluates fn once and returns the cached result afterwards.
+func (m *SamplingMark) Decide(fn func() bool) bool {
+	m.once.Do(func() { m.sampled = fn() })
+	return m.sampled
+}
+
+// SamplingCarrier is implemented by events that carry a shared SamplingMark.
+type SamplingCarrier interface {
+	GetSamplingMark() *SamplingMark
+}
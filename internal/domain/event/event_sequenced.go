@@ -0,0 +1,96 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a client-supplied resume cursor cannot be parsed.
+var ErrInvalidCursor = errors.New("event: invalid resume cursor")
+
+// Cursor is a position in a user's delivery stream.
+//
+// [EPOCH] Sequences restart when a user's Cell is recreated (eviction, restart, another
+// node), so every cursor is qualified by the Cell incarnation that issued it.
+type Cursor struct {
+	Epoch uint32
+	Seq   uint64
+}
+
+// String renders the wire form "<epoch_hex>.<seq>".
+func (c Cursor) String() string {
+	return fmt.Sprintf("%x.%d", c.Epoch, c.Seq)
+}
+
+// IsZero reports whether the cursor is unset (no resume requested).
+func (c Cursor) IsZero() bool { return c.Epoch == 0 && c.Seq == 0 }
+
+// ParseCursor parses the wire form produced by Cursor.String.
+func ParseCursor(s string) (Cursor, error) {
+	epochStr, seqStr, ok := strings.Cut(s, ".")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	epoch, err := strconv.ParseUint(epochStr, 16, 32)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{Epoch: uint32(epoch), Seq: seq}, nil
+}
+
+// Sequenced is implemented by events stamped with a per-user delivery cursor.
+type Sequenced interface {
+	GetCursor() Cursor
+}
+
+// Wrapper is implemented by envelopes that decorate another event.
+type Wrapper interface {
+	Unwrap() Eventer
+}
+
+// [GUARD] Ensure compliance with the Eventer, Sequenced and Wrapper interfaces.
+var (
+	_ Eventer   = (*SequencedEvent)(nil)
+	_ Sequenced = (*SequencedEvent)(nil)
+	_ Wrapper   = (*SequencedEvent)(nil)
+)
+
+// SequencedEvent stamps an event with the user's monotonically increasing sequence.
+//
+// [SINGLE_WRITER] Sequences are assigned only by the user's Cell loop, so they are
+// gap-free and strictly ordered per user; resume cursors are expressed in this space.
+type SequencedEvent struct {
+	Eventer
+	Cursor     Cursor
+	DequeuedAt int64 // [STAGE_TIMING] Monotime when the Cell loop took it from the mailbox
+}
+
+func NewSequencedEvent(ev Eventer, cursor Cursor) *SequencedEvent {
+	return &SequencedEvent{Eventer: ev, Cursor: cursor, DequeuedAt: Monotime()}
+}
+
+func (e *SequencedEvent) GetCursor() Cursor { return e.Cursor }
+
+func (e *SequencedEvent) Unwrap() Eventer { return e.Eventer }
+
+// CursorOf returns the delivery cursor of an event, looking through wrappers.
+// ok is false when the event was not sequenced (e.g. handshake or topic traffic).
+func CursorOf(ev Eventer) (Cursor, bool) {
+	for ev != nil {
+		if s, ok := ev.(Sequenced); ok {
+			return s.GetCursor(), true
+		}
+		w, ok := ev.(Wrapper)
+		if !ok {
+			break
+		}
+		ev = w.Unwrap()
+	}
+	return Cursor{}, false
+}
@@ -0,0 +1,29 @@
+package event
+
+import "time"
+
+// Shaped is implemented by events held back by synthetic delivery shaping.
+type Shaped interface {
+	GetShapingDelay() time.Duration
+}
+
+// [GUARD] Ensure compliance with the Eventer and Shaped interfaces.
+var (
+	_ Eventer = (*ShapedEvent)(nil)
+	_ Shaped  = (*ShapedEvent)(nil)
+	_ Wrapper = (*ShapedEvent)(nil)
+)
+
+// ShapedEvent tags a delivered event with the synthetic delay applied to it.
+// [DEBUG_ONLY] Transports surface the delay so test clients can assert shaping is active.
+type ShapedEvent struct {
+	Eventer
+	Delay time.Duration
+}
+
+func NewShapedEvent(ev Eventer, delay time.Duration) *ShapedEvent {
+	return &ShapedEvent{Eventer: ev, Delay: delay}
+}
+
+func (e *ShapedEvent) GetShapingDelay() time.Duration { return e.Delay }
+func (e *ShapedEvent) Unwrap() Eventer                { return e.Eventer }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
 )
 
 // [GUARD] Ensure compliance with the Eventer interface.
@@ -18,29 +19,40 @@ type SystemEvent struct {
 	priority   EventPriority
 	occurredAt int64
 	payload    any
-	cached     any // Atomic/Sync.Pool optimization for transport-specific serialization
+	encoded    *model.EncodedEvent
 }
 
 // [INTERFACE_IMPLEMENTATION]
-func (e *SystemEvent) GetID() string              { return e.id }
-func (e *SystemEvent) GetTraceID() string         { return e.traceID }
-func (e *SystemEvent) GetKind() EventKind         { return e.kind }
-func (e *SystemEvent) GetUserID() uuid.UUID       { return e.userID }
-func (e *SystemEvent) GetPriority() EventPriority { return e.priority }
-func (e *SystemEvent) GetOccurredAt() int64       { return e.occurredAt }
-func (e *SystemEvent) GetPayload() any            { return e.payload }
-func (e *SystemEvent) GetCached() any             { return e.cached }
-func (e *SystemEvent) SetCached(v any)            { e.cached = v }
+func (e *SystemEvent) GetID() string                { return e.id }
+func (e *SystemEvent) GetTraceID() string           { return e.traceID }
+func (e *SystemEvent) GetKind() EventKind           { return e.kind }
+func (e *SystemEvent) GetUserID() uuid.UUID         { return e.userID }
+func (e *SystemEvent) GetPriority() EventPriority   { return e.priority }
+func (e *SystemEvent) GetOccurredAt() int64         { return e.occurredAt }
+func (e *SystemEvent) GetPayload() any              { return e.payload }
+func (e *SystemEvent) Encoded() *model.EncodedEvent { return e.encoded }
+func (e *SystemEvent) IsEncrypted() bool            { return false }
+
+func (e *SystemEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+// Clone re-addresses the signal for [FAN_OUT]; the payload is shared.
+func (e *SystemEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.userID = userID
+	c.encoded = new(model.EncodedEvent)
+	return &c
+}
 
 // NewSystemEvent is a universal factory for creating any signal.
 func NewSystemEvent(userID uuid.UUID, kind EventKind, priority EventPriority, payload any) *SystemEvent {
 	return &SystemEvent{
-		id:         uuid.NewString(),
+		id:         newID(),
 		traceID:    uuid.NewString(),
 		userID:     userID,
 		kind:       kind,
 		priority:   priority,
 		occurredAt: time.Now().UnixMilli(),
 		payload:    payload,
+		encoded:    new(model.EncodedEvent),
 	}
 }
@@ -0,0 +1,45 @@
+package event
+
+import "time"
+
+// clockBase anchors Monotime; time.Since uses the monotonic clock reading.
+var clockBase = time.Now()
+
+// Monotime returns monotonic nanoseconds since process start.
+// [CHEAP] One clock read, no allocation; stage stamps are plain int64 stores.
+func Monotime() int64 { return int64(time.Since(clockBase)) }
+
+// StageTimes holds coarse pipeline boundaries stamped before fan-out (Monotime values).
+// Zero means the stage was not stamped.
+type StageTimes struct {
+	Consume   int64 // Broker message picked up
+	Enrich    int64 // Enrichment finished
+	Broadcast int64 // Handed to the Hub
+}
+
+// Timed is implemented by events that carry StageTimes.
+type Timed interface {
+	GetStageTimes() *StageTimes
+}
+
+// StagesOf returns the stage stamps of an event and the per-leg dequeue stamp,
+// looking through wrappers. ok is false when the event carries no stamps.
+func StagesOf(ev Eventer) (stages StageTimes, dequeued int64, ok bool) {
+	for ev != nil {
+		if s, isSeq := ev.(*SequencedEvent); isSeq && dequeued == 0 {
+			dequeued = s.DequeuedAt
+		}
+		if t, isTimed := ev.(Timed); isTimed {
+			if st := t.GetStageTimes(); st != nil && st.Consume != 0 {
+				return *st, dequeued, true
+			}
+			return StageTimes{}, 0, false
+		}
+		w, isWrapper := ev.(Wrapper)
+		if !isWrapper {
+			break
+		}
+		ev = w.Unwrap()
+	}
+	return StageTimes{}, 0, false
+}
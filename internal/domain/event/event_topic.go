@@ -0,0 +1,58 @@
+package event
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// [GUARD] Ensure compliance with the Eventer and Ephemeral interfaces.
+var (
+	_ Eventer   = (*TopicEvent)(nil)
+	_ Ephemeral = (*TopicEvent)(nil)
+)
+
+// TopicEvent is an [EPHEMERAL] envelope delivered to connections subscribed to a topic key.
+//
+// [STRATEGY]
+// Unlike user-addressed events it bypasses the user Cells entirely: the Hub resolves
+// recipients through its topic index, so GetUserID returns uuid.Nil.
+type TopicEvent struct {
+	id         string
+	occurredAt int64
+	payload    *model.TopicPayload
+	encoded    *model.EncodedEvent
+}
+
+// NewTopicEvent wraps a raw topic update into a deliverable event.
+func NewTopicEvent(key string, data []byte) *TopicEvent {
+	return &TopicEvent{
+		id:         newID(),
+		occurredAt: time.Now().UnixMilli(),
+		payload:    &model.TopicPayload{Key: key, Data: data},
+		encoded:    new(model.EncodedEvent),
+	}
+}
+
+func (e *TopicEvent) GetID() string                { return e.id }
+func (e *TopicEvent) GetKind() EventKind           { return TopicMessage }
+func (e *TopicEvent) GetUserID() uuid.UUID         { return uuid.Nil }
+func (e *TopicEvent) GetPriority() EventPriority   { return PriorityLow }
+func (e *TopicEvent) GetOccurredAt() int64         { return e.occurredAt }
+func (e *TopicEvent) GetPayload() any              { return e.payload }
+func (e *TopicEvent) Encoded() *model.EncodedEvent { return e.encoded }
+func (e *TopicEvent) IsEphemeral() bool            { return true }
+func (e *TopicEvent) IsEncrypted() bool            { return false }
+
+func (e *TopicEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+// Clone copies the event; topic events are not user-addressed, so userID is ignored.
+func (e *TopicEvent) Clone(uuid.UUID) Eventer {
+	c := *e
+	c.encoded = new(model.EncodedEvent)
+	return &c
+}
+
+// GetTopicKey returns the entity key this event is scoped to.
+func (e *TopicEvent) GetTopicKey() string { return e.payload.Key }
@@ -0,0 +1,50 @@
+package event
+
+import (
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// [GUARD] Ensure compliance with the Eventer interface.
+// TypingEvent is deliberately not Exportable: indicators are never re-published to the bus.
+var _ Eventer = (*TypingEvent)(nil)
+
+// TypingEvent tells one recipient that a peer is composing in a thread.
+// [TRANSIENT] It is the lowest priority a Cell carries, so backpressure sheds it first.
+type TypingEvent struct {
+	id         string
+	userID     uuid.UUID
+	occurredAt int64
+	payload    *model.TypingPayload
+	encoded    *model.EncodedEvent
+}
+
+// NewTypingEvent addresses the indicator to userID; at is Unix ms.
+func NewTypingEvent(payload *model.TypingPayload, userID uuid.UUID, at int64) *TypingEvent {
+	return &TypingEvent{
+		id:         newID(),
+		userID:     userID,
+		occurredAt: at,
+		payload:    payload,
+		encoded:    new(model.EncodedEvent),
+	}
+}
+
+func (e *TypingEvent) GetID() string                { return e.id }
+func (e *TypingEvent) GetKind() EventKind           { return Typing }
+func (e *TypingEvent) GetUserID() uuid.UUID         { return e.userID }
+func (e *TypingEvent) GetPriority() EventPriority   { return PriorityLow }
+func (e *TypingEvent) GetOccurredAt() int64         { return e.occurredAt }
+func (e *TypingEvent) GetPayload() any              { return e.payload }
+func (e *TypingEvent) Encoded() *model.EncodedEvent { return e.encoded }
+func (e *TypingEvent) IsEncrypted() bool            { return false }
+
+func (e *TypingEvent) GetEncryptedPayload() *model.EncryptedPayload { return nil }
+
+// Clone re-addresses the event for [FAN_OUT].
+func (e *TypingEvent) Clone(userID uuid.UUID) Eventer {
+	c := *e
+	c.userID = userID
+	c.encoded = new(model.EncodedEvent)
+	return &c
+}
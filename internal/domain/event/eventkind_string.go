@@ -11,11 +11,25 @@ func _() {
 	_ = x[Connected-1]
 	_ = x[Disconnected-2]
 	_ = x[MessageCreated-3]
+	_ = x[TopicMessage-4]
+	_ = x[ReplayGap-5]
+	_ = x[DomainPaused-6]
+	_ = x[DomainResumed-7]
+	_ = x[DeliveryDegraded-8]
+	_ = x[PresenceChanged-9]
+	_ = x[MessageDeleted-10]
+	_ = x[MessageUpdated-11]
+	_ = x[Typing-12]
+	_ = x[ReadReceipt-13]
+	_ = x[MessageReaction-14]
+	_ = x[PresenceStatus-15]
+	_ = x[Ping-16]
+	_ = x[MessageDelivered-17]
 }
 
-const _EventKind_name = "ConnectedDisconnectedMessageCreated"
+const _EventKind_name = "ConnectedDisconnectedMessageCreatedTopicMessageReplayGapDomainPausedDomainResumedDeliveryDegradedPresenceChangedMessageDeletedMessageUpdatedTypingReadReceiptMessageReactionPresenceStatusPingMessageDelivered"
 
-var _EventKind_index = [...]uint8{0, 9, 21, 35}
+var _EventKind_index = [...]uint8{0, 9, 21, 35, 47, 56, 68, 81, 97, 112, 126, 140, 146, 157, 172, 186, 190, 206}
 
 func (i EventKind) String() string {
 	i -= 1
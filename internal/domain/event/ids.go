@@ -0,0 +1,112 @@
+package event
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+// UUIDGenerator produces event identifiers.
+type UUIDGenerator interface {
+	NewID() string
+	NewUUID() uuid.UUID
+}
+
+// Interface guards
+var (
+	_ UUIDGenerator = RandomUUIDGenerator{}
+	_ UUIDGenerator = SequentialUUIDGenerator{}
+	_ UUIDGenerator = (*CounterIDGenerator)(nil)
+)
+
+// Generator names accepted by GeneratorByName.
+const (
+	IDStrategyRandom     = "random"
+	IDStrategySequential = "sequential"
+	IDStrategyCounter    = "counter"
+)
+
+// RandomUUIDGenerator issues UUIDv4 (the historical behavior).
+type RandomUUIDGenerator struct{}
+
+func (RandomUUIDGenerator) NewID() string      { return uuid.NewString() }
+func (RandomUUIDGenerator) NewUUID() uuid.UUID { return uuid.New() }
+
+// SequentialUUIDGenerator issues time-ordered UUIDv7, which index well in databases.
+type SequentialUUIDGenerator struct{}
+
+func (g SequentialUUIDGenerator) NewID() string { return g.NewUUID().String() }
+
+func (SequentialUUIDGenerator) NewUUID() uuid.UUID {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.New()
+	}
+	return id
+}
+
+// CounterIDGenerator issues compact base-36 counters without touching the entropy pool.
+//
+// [NODE_LOCAL] String IDs are unique only within the process; UUIDs combine a random
+// per-process prefix with the counter, so they stay unique across nodes in practice.
+type CounterIDGenerator struct {
+	prefix  uint64
+	counter atomic.Int64
+}
+
+func NewCounterIDGenerator() *CounterIDGenerator {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return &CounterIDGenerator{prefix: binary.BigEndian.Uint64(b[:])}
+}
+
+func (g *CounterIDGenerator) NewID() string {
+	return strconv.FormatInt(g.counter.Add(1), 36)
+}
+
+func (g *CounterIDGenerator) NewUUID() uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[:8], g.prefix)
+	binary.BigEndian.PutUint64(id[8:], uint64(g.counter.Add(1)))
+	return id
+}
+
+// GeneratorByName maps a configured strategy to a generator; unknown names fall back to random.
+func GeneratorByName(name string) UUIDGenerator {
+	switch name {
+	case IDStrategySequential:
+		return SequentialUUIDGenerator{}
+	case IDStrategyCounter:
+		return NewCounterIDGenerator()
+	default:
+		return RandomUUIDGenerator{}
+	}
+}
+
+type generatorBox struct{ gen UUIDGenerator }
+
+var idGenerator atomic.Pointer[generatorBox]
+
+func init() {
+	SetUUIDGenerator(RandomUUIDGenerator{})
+}
+
+// SetUUIDGenerator replaces the process-wide generator used by event constructors.
+func SetUUIDGenerator(gen UUIDGenerator) {
+	if gen == nil {
+		gen = RandomUUIDGenerator{}
+	}
+	idGenerator.Store(&generatorBox{gen: gen})
+}
+
+// WithUUIDGenerator installs gen when the Fx app starts building.
+func WithUUIDGenerator(gen UUIDGenerator) fx.Option {
+	return fx.Invoke(func() { SetUUIDGenerator(gen) })
+}
+
+func newID() string      { return idGenerator.Load().gen.NewID() }
+func newUUID() uuid.UUID { return idGenerator.Load().gen.NewUUID() }
@@ -0,0 +1,46 @@
+package event
+
+import "strings"
+
+// KindFilter is the set of event kinds a session asked for; the zero value admits all.
+//
+// [CONTROL_SIGNALS] Session-protocol kinds (handshake, gaps, degradation, pauses) always
+// pass: a client that filtered them out could not tell when to resync.
+type KindFilter uint64
+
+// NewKindFilter admits exactly kinds (plus the control signals); no kinds admits all.
+func NewKindFilter(kinds ...EventKind) KindFilter {
+	var f KindFilter
+	for _, k := range kinds {
+		if k > 0 && k < 64 {
+			f |= 1 << k
+		}
+	}
+	return f
+}
+
+// Allows reports whether an event of kind k reaches the session.
+func (f KindFilter) Allows(k EventKind) bool {
+	if f == 0 || isControl(k) {
+		return true
+	}
+	return k > 0 && k < 64 && f&(1<<k) != 0
+}
+
+func isControl(k EventKind) bool {
+	switch k {
+	case Connected, Disconnected, ReplayGap, DomainPaused, DomainResumed, DeliveryDegraded, Ping:
+		return true
+	}
+	return false
+}
+
+// ParseEventKind resolves the String form of a kind (e.g. "MessageCreated"), case-insensitively.
+func ParseEventKind(name string) (EventKind, bool) {
+	for k := EventKind(1); k < EventKind(len(_EventKind_index)); k++ {
+		if strings.EqualFold(k.String(), name) {
+			return k, true
+		}
+	}
+	return 0, false
+}
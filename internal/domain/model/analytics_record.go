@@ -0,0 +1,15 @@
+package model
+
+// AnalyticsRecord is the compact, content-free export of one sampled delivery leg.
+// Legs of the same source share SourceID; summing Recipients by it yields the fan-out width.
+type AnalyticsRecord struct {
+	Kind       string `json:"kind"`
+	DomainID   int64  `json:"domain_id"`
+	ThreadID   string `json:"thread_id,omitempty"`
+	SourceID   string `json:"source_id"`
+	Recipients int    `json:"recipients"`
+	Transport  string `json:"transport"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Bytes      int    `json:"bytes"`
+	At         int64  `json:"at"` // Unix milliseconds
+}
@@ -1,9 +1,20 @@
 package model
 
+import "slices"
+
 type AuthContact struct {
 	DC        int64
 	ContactID string
 	Sub       string
 	Iss       string
 	Name      string
+	Scope     []string // Permissions granted to the access token
+}
+
+// ScopeService marks service-to-service callers (internal tools, other backends).
+const ScopeService = "service"
+
+// HasScope reports whether the token was granted scope.
+func (a *AuthContact) HasScope(scope string) bool {
+	return slices.Contains(a.Scope, scope)
 }
@@ -6,4 +6,10 @@ type ConnectedPayload struct {
 	Ok            bool   `json:"ok"`
 	ConnectionID  string `json:"connection_id"`
 	ServerVersion string `json:"server_version"`
+	// BufferSize is the connection's delivery buffer capacity (client headroom).
+	BufferSize int `json:"buffer_size,omitempty"`
+	// Capabilities lists optional server features active for this session.
+	Capabilities []string `json:"capabilities,omitempty"`
+	// Deprecations names legacy features this connection relies on; surface them in client telemetry.
+	Deprecations []string `json:"deprecations,omitempty"`
 }
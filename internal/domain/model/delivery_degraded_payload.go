@@ -0,0 +1,8 @@
+package model
+
+// DeliveryDegradedPayload warns a client that its session has shed events under
+// backpressure, so the UI can show a "you may have missed messages" banner and resync.
+type DeliveryDegradedPayload struct {
+	Dropped   uint64 `json:"dropped"`   // Events shed on this connection so far
+	Threshold uint64 `json:"threshold"` // Drop count that triggered the warning
+}
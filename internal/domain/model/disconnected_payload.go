@@ -4,4 +4,8 @@ package model
 type DisconnectedPayload struct {
 	Reason string `json:"reason"`
 	Code   string `json:"code,omitempty"` // Optional: "SHUTDOWN", "EVICTED", "TIMEOUT"
+
+	// [RECONNECT_GUIDANCE] Server-chosen backoff; clients wait this long before redialing.
+	RetryAfterMs  int64  `json:"retry_after_ms,omitempty"`
+	AlternateNode string `json:"alternate_node,omitempty"`
 }
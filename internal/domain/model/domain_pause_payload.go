@@ -0,0 +1,9 @@
+package model
+
+// DomainPausePayload announces the start or end of a tenant maintenance window,
+// so UIs can show (and later hide) a maintenance banner.
+type DomainPausePayload struct {
+	DomainID int64  `json:"domain_id"`
+	UntilMs  int64  `json:"until_ms,omitempty"` // Planned end of the window (unix ms); empty on resume
+	Policy   string `json:"policy,omitempty"`
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"sync"
+	"sync/atomic"
+
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+)
+
+// EncodedEvent caches the wire encodings of one event so every session of the
+// recipient reuses bytes computed once per node instead of re-marshalling per tab.
+//
+// [CONCURRENCY] Sessions marshal the same event from their own goroutines. Every
+// encoding is published atomically; builders that race produce identical output
+// and the first stored value wins. A nil *EncodedEvent caches nothing.
+type EncodedEvent struct {
+	pb   atomic.Pointer[encodedProto]
+	wire sync.Map // encoding key -> []byte
+}
+
+type encodedProto struct {
+	version uint64
+	msg     *impb.ServerEvent
+}
+
+// Proto returns the cached ServerEvent if it was built by schema version.
+func (e *EncodedEvent) Proto(version uint64) (*impb.ServerEvent, bool) {
+	if e == nil {
+		return nil, false
+	}
+	p := e.pb.Load()
+	if p == nil || p.version != version {
+		return nil, false
+	}
+	return p.msg, true
+}
+
+// StoreProto caches msg as built by schema version and returns the message every
+// caller should send: a concurrent builder's if it was published first, so all
+// sessions share one ServerEvent (and its memoized size) instead of racing copies.
+func (e *EncodedEvent) StoreProto(version uint64, msg *impb.ServerEvent) *impb.ServerEvent {
+	if e == nil {
+		return msg
+	}
+	next := &encodedProto{version: version, msg: msg}
+	for {
+		cur := e.pb.Load()
+		if cur != nil && cur.version == version {
+			return cur.msg
+		}
+		if e.pb.CompareAndSwap(cur, next) {
+			return msg
+		}
+	}
+}
+
+// PurgeProtoBefore drops a ServerEvent built by a schema older than version.
+// It reports whether anything was dropped.
+func (e *EncodedEvent) PurgeProtoBefore(version uint64) bool {
+	if e == nil {
+		return false
+	}
+	p := e.pb.Load()
+	return p != nil && p.version < version && e.pb.CompareAndSwap(p, nil)
+}
+
+// Bytes returns the encoding cached under key (see event.EncodingKey).
+func (e *EncodedEvent) Bytes(key string) ([]byte, bool) {
+	if e == nil {
+		return nil, false
+	}
+	v, ok := e.wire.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return v.([]byte), true
+}
+
+// StoreBytes caches b under key and returns the bytes every caller should use,
+// which are the earlier ones if another session got there first.
+func (e *EncodedEvent) StoreBytes(key string, b []byte) []byte {
+	if e == nil {
+		return b
+	}
+	v, _ := e.wire.LoadOrStore(key, b)
+	return v.([]byte)
+}
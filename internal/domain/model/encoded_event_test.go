@@ -0,0 +1,146 @@
+package model_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	grpcmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/gprc"
+	lpmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/lp"
+	wsmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/ws"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestConcurrentMarshal encodes one cold event from 100 goroutines on every transport,
+// through the template itself, CloneShared legs taken meanwhile and one Clone leg, and
+// checks that each cache publishes a single encoding. Run it with -race.
+func TestConcurrentMarshal(t *testing.T) {
+	const goroutines = 100
+	msg := &model.Message{ID: uuid.New(), ThreadID: uuid.New(), Text: "hi", Metadata: map[string]any{"k": "v"}}
+	ev := event.NewMessageV1Event(msg, uuid.New(), model.Peer{ID: uuid.New()}, model.Peer{ID: uuid.New()})
+	own := ev.Clone(uuid.New()) // A leg with its own cache, shared by a third of the goroutines
+
+	type result struct {
+		shared bool // Marshalled through ev's cache
+		ws, lp []byte
+		pb     *impb.ServerEvent
+	}
+	results := make([]result, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Go(func() {
+			var leg event.Eventer
+			switch i % 3 {
+			case 0:
+				leg = ev
+			case 1:
+				leg = ev.CloneShared(uuid.New())
+			default:
+				leg = own
+			}
+			ws, err := wsmarshaller.MarshallVersioned(leg, 1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			lp, err := lpmarshaller.MarshallEvents([]event.Eventer{leg})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			results[i] = result{shared: leg != own, ws: ws, lp: lp, pb: grpcmarshaller.MarshallDeliveryEvent(leg)}
+		})
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	first := map[bool]result{}
+	for i, r := range results {
+		f, seen := first[r.shared]
+		if !seen {
+			first[r.shared] = r
+			continue
+		}
+		if r.pb != f.pb {
+			t.Fatalf("goroutine %d (shared %v) got its own ServerEvent", i, r.shared)
+		}
+		if !bytes.Equal(r.ws, f.ws) || !bytes.Equal(r.lp, f.lp) {
+			t.Fatalf("goroutine %d (shared %v) got a different encoding", i, r.shared)
+		}
+	}
+	if first[true].pb == first[false].pb {
+		t.Fatal("Clone leg shares the template's ServerEvent")
+	}
+	if !proto.Equal(first[true].pb, first[false].pb) || !bytes.Equal(first[true].ws, first[false].ws) {
+		t.Fatal("Clone leg encodes differently from the template")
+	}
+}
+
+// BenchmarkSessionFanOut encodes one event for the 10 sessions of a recipient on each
+// transport, with the shared encoding cache and without it (a nil EncodedEvent).
+// Every iteration is a fresh event, so the cached figures include one build.
+//
+// Baseline (amd64, median of 3): ws cached 6684 ns/op, 1704 B/op, 43 allocs/op;
+// uncached 48470 ns/op, 11761 B/op, 200 allocs/op. lp cached 27508 ns/op, 9416 B/op,
+// 82 allocs/op; uncached 84664 ns/op, 20482 B/op, 230 allocs/op. grpc cached 1453 ns/op,
+// 1305 B/op, 22 allocs/op; uncached 14786 ns/op, 12250 B/op, 200 allocs/op.
+func BenchmarkSessionFanOut(b *testing.B) {
+	const sessions = 10
+	msg := &model.Message{
+		ID:        uuid.New(),
+		ThreadID:  uuid.New(),
+		DomainID:  1,
+		Text:      "Hello, see you at the standup in ten minutes",
+		CreatedAt: 1700000000000,
+		Metadata:  map[string]any{"client": "web", "draft": false},
+	}
+	from := model.Peer{ID: uuid.New(), Type: model.PeerUser, Name: "Alice", Sub: "alice", Issuer: "iss"}
+	to := model.Peer{ID: uuid.New(), Type: model.PeerUser, Name: "Bob", Sub: "bob", Issuer: "iss"}
+	ev := event.NewMessageV1Event(msg, uuid.New(), from, to)
+
+	transports := []struct {
+		name   string
+		encode func(event.Eventer) error
+	}{
+		{name: "ws", encode: func(ev event.Eventer) error {
+			_, err := wsmarshaller.MarshallVersioned(ev, 1)
+			return err
+		}},
+		{name: "lp", encode: func(ev event.Eventer) error {
+			_, err := lpmarshaller.MarshallEvents([]event.Eventer{ev})
+			return err
+		}},
+		{name: "grpc", encode: func(ev event.Eventer) error {
+			_ = grpcmarshaller.MarshallDeliveryEvent(ev)
+			return nil
+		}},
+	}
+	for _, tr := range transports {
+		for _, cached := range []bool{true, false} {
+			name := tr.name + "/uncached"
+			if cached {
+				name = tr.name + "/cached"
+			}
+			b.Run(name, func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					ev.Encodings = nil
+					if cached {
+						ev.Encodings = new(model.EncodedEvent)
+					}
+					for range sessions {
+						if err := tr.encode(ev); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			})
+		}
+	}
+}
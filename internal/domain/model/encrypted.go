@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/base64"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// EncryptedContent is an [E2EE] message body: opaque ciphertext plus key metadata.
+	// The delivery pipeline never inspects, transforms or logs it.
+	EncryptedContent struct {
+		Ciphertext []byte        `json:"ciphertext"` // base64 on the JSON wire
+		Algorithm  string        `json:"algorithm"`
+		KeyID      string        `json:"key_id"`
+		Envelopes  []KeyEnvelope `json:"envelopes,omitempty"`
+	}
+
+	// KeyEnvelope is the content key wrapped for a single recipient.
+	KeyEnvelope struct {
+		RecipientID uuid.UUID `json:"recipient_id"`
+		WrappedKey  string    `json:"wrapped_key"`
+	}
+)
+
+// EncryptedPayload is the event-level [E2EE] view: ciphertext and key reference only,
+// never any plaintext field.
+type EncryptedPayload struct {
+	CiphertextBase64 string `json:"ciphertext"`
+	KeyID            string `json:"key_id"`
+	Algorithm        string `json:"algorithm"`
+}
+
+// Payload renders the content as an event-level envelope.
+func (c *EncryptedContent) Payload() *EncryptedPayload {
+	if c == nil {
+		return nil
+	}
+	return &EncryptedPayload{
+		CiphertextBase64: base64.StdEncoding.EncodeToString(c.Ciphertext),
+		KeyID:            c.KeyID,
+		Algorithm:        c.Algorithm,
+	}
+}
+
+// ForRecipient returns a copy carrying only the envelope addressed to userID.
+// [PRIVACY] Envelopes of other recipients never leave the node.
+func (c *EncryptedContent) ForRecipient(userID uuid.UUID) *EncryptedContent {
+	if c == nil {
+		return nil
+	}
+
+	res := &EncryptedContent{
+		Ciphertext: c.Ciphertext,
+		Algorithm:  c.Algorithm,
+		KeyID:      c.KeyID,
+	}
+
+	for _, env := range c.Envelopes {
+		if env.RecipientID == userID {
+			res.Envelopes = []KeyEnvelope{env}
+			break
+		}
+	}
+
+	return res
+}
+
+// IsEncrypted reports whether the message body is an E2EE ciphertext.
+func (m *Message) IsEncrypted() bool {
+	return m != nil && m.Encrypted != nil
+}
+
+// ForRecipient returns a shallow copy of an encrypted message scoped to one recipient.
+// Plain messages are returned as-is.
+func (m *Message) ForRecipient(userID uuid.UUID) *Message {
+	if !m.IsEncrypted() {
+		return m
+	}
+
+	cp := *m
+	cp.Encrypted = m.Encrypted.ForRecipient(userID)
+	return &cp
+}
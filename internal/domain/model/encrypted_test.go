@@ -0,0 +1,68 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEncryptedForRecipient(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	content := &EncryptedContent{
+		Ciphertext: []byte{0xde, 0xad},
+		Algorithm:  "aes-256-gcm",
+		KeyID:      "k1",
+		Envelopes: []KeyEnvelope{
+			{RecipientID: alice, WrappedKey: "for-alice"},
+			{RecipientID: bob, WrappedKey: "for-bob"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		recipient uuid.UUID
+		want      []string // Wrapped keys left in the copy
+	}{
+		{name: "first recipient", recipient: alice, want: []string{"for-alice"}},
+		{name: "second recipient", recipient: bob, want: []string{"for-bob"}},
+		{name: "recipient without an envelope", recipient: carol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &Message{Encrypted: content}
+			got := msg.ForRecipient(tt.recipient).Encrypted
+
+			if len(got.Envelopes) != len(tt.want) {
+				t.Fatalf("envelopes: got %v, want %v", got.Envelopes, tt.want)
+			}
+			for i, env := range got.Envelopes {
+				if env.WrappedKey != tt.want[i] || env.RecipientID != tt.recipient {
+					t.Fatalf("envelope %d: got %+v", i, env)
+				}
+			}
+			if got.KeyID != content.KeyID || got.Algorithm != content.Algorithm || string(got.Ciphertext) != string(content.Ciphertext) {
+				t.Fatalf("ciphertext or key metadata changed: %+v", got)
+			}
+			if len(content.Envelopes) != 2 {
+				t.Fatal("the shared content lost envelopes")
+			}
+		})
+	}
+}
+
+func TestPlainMessageForRecipient(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *Message
+	}{
+		{name: "plain", msg: &Message{Text: "hi"}},
+		{name: "nil", msg: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.ForRecipient(uuid.New()); got != tt.msg {
+				t.Fatalf("plain message copied: %+v", got)
+			}
+		})
+	}
+}
@@ -7,6 +7,17 @@ type HubStats struct {
 	TotalConnections int           `json:"total_connections"`
 	Uptime           time.Duration `json:"uptime"`
 	Shards           []ShardStats  `json:"shards,omitempty"`
+
+	// Mailbox occupancy aggregated over all Cells at snapshot time.
+	MailboxDepthTotal int `json:"mailbox_depth_total"`
+	MailboxDepthMax   int `json:"mailbox_depth_max"`
+	MailboxesNonEmpty int `json:"mailboxes_non_empty"`
+
+	// Cumulative since start.
+	EventsBroadcast int64 `json:"events_broadcast"`
+	EventsDropped   int64 `json:"events_dropped"`  // MailboxDropped + SessionDropped
+	MailboxDropped  int64 `json:"mailbox_dropped"` // Rejected by a full Cell mailbox
+	SessionDropped  int64 `json:"session_dropped"` // Shed by a saturated session buffer
 }
 
 type ShardStats struct {
@@ -15,8 +15,12 @@ type (
 		CreatedAt int64          `json:"created_at"`
 		EditedAt  int64          `json:"updated_at,omitempty"`
 		Metadata  map[string]any `json:"metadata,omitempty"`
+		IsForward bool           `json:"is_forward,omitempty"`
 		Documents []*Document    `json:"documents,omitempty"`
 		Images    []*Image       `json:"images,omitempty"`
+
+		// [E2EE] When set, Text is empty and the body is opaque to this service.
+		Encrypted *EncryptedContent `json:"encrypted,omitempty"`
 	}
 
 	Document struct {
@@ -0,0 +1,12 @@
+package model
+
+import "github.com/google/uuid"
+
+// MessageDeleted tells clients to drop a message from the thread view.
+type MessageDeleted struct {
+	ID        uuid.UUID `json:"id"`
+	ThreadID  uuid.UUID `json:"thread_id"`
+	DomainID  int64     `json:"domain_id"`
+	DeletedBy uuid.UUID `json:"deleted_by"`
+	DeletedAt int64     `json:"deleted_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "github.com/google/uuid"
+
+// Reaction tells a message author that a peer added or removed an emoji reaction.
+type Reaction struct {
+	MessageID uuid.UUID `json:"message_id"`
+	ThreadID  uuid.UUID `json:"thread_id"`
+	DomainID  int64     `json:"domain_id"`
+	Emoji     string    `json:"emoji"`
+	Actor     Peer      `json:"actor"`
+	Added     bool      `json:"added"` // false: the reaction was removed
+	At        int64     `json:"at"`
+}
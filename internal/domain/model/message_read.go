@@ -0,0 +1,12 @@
+package model
+
+import "github.com/google/uuid"
+
+// MessageRead tells a sender how far a reader has read a thread.
+type MessageRead struct {
+	ThreadID          uuid.UUID `json:"thread_id"`
+	DomainID          int64     `json:"domain_id"`
+	Reader            Peer      `json:"reader"`
+	LastReadMessageID uuid.UUID `json:"last_read_message_id"`
+	ReadAt            int64     `json:"read_at"`
+}
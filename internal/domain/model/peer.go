@@ -13,14 +13,21 @@ const (
 	PeerUser PeerType = iota + 1
 	PeerGroup
 	PeerChannel
+	PeerBot
 )
 
+// Peer is the single peer model shared by DTO mapping, enrichment, routing keys and
+// every transport marshaller. ID and Type come from the bus; the rest is enrichment.
 type Peer struct {
-	ID     uuid.UUID `json:"id"`
-	Type   PeerType  `json:"type"`
-	Sub    string    `json:"sub,omitempty"`
-	Issuer string    `json:"issuer,omitempty"`
-	Name   string    `json:"name,omitempty"`
+	ID        uuid.UUID `json:"id"`
+	Type      PeerType  `json:"type"`
+	Sub       string    `json:"sub,omitempty"`
+	Issuer    string    `json:"issuer,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
+
+	// MemberCount is set for enriched groups only.
+	MemberCount int `json:"member_count,omitempty"`
 }
 
 type PeerOption func(*Peer)
@@ -34,6 +41,13 @@ func WithIdentity(sub, issuer, name string) PeerOption {
 	}
 }
 
+// WithAvatar applies the profile picture resolved during enrichment
+func WithAvatar(url string) PeerOption {
+	return func(p *Peer) {
+		p.AvatarURL = url
+	}
+}
+
 func NewPeer(id uuid.UUID, pType PeerType, opts ...PeerOption) Peer {
 	p := Peer{ID: id, Type: pType}
 	for _, opt := range opts {
@@ -11,11 +11,12 @@ func _() {
 	_ = x[PeerUser-1]
 	_ = x[PeerGroup-2]
 	_ = x[PeerChannel-3]
+	_ = x[PeerBot-4]
 }
 
-const _PeerType_name = "PeerUserPeerGroupPeerChannel"
+const _PeerType_name = "PeerUserPeerGroupPeerChannelPeerBot"
 
-var _PeerType_index = [...]uint8{0, 8, 17, 28}
+var _PeerType_index = [...]uint8{0, 8, 17, 28, 35}
 
 func (i PeerType) String() string {
 	i -= 1
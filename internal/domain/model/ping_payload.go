@@ -0,0 +1,7 @@
+package model
+
+// PingPayload is the server heartbeat written to an idle stream so proxies and
+// mobile networks see traffic and a dead path surfaces on the server's write.
+type PingPayload struct {
+	ServerTime int64 `json:"server_time"` // Unix ms at send
+}
@@ -0,0 +1,10 @@
+package model
+
+// PresencePayload announces that a user came online on a node or left it.
+type PresencePayload struct {
+	UserID   string `json:"user_id"`
+	DomainID int64  `json:"domain_id"`
+	Online   bool   `json:"online"`
+	NodeID   string `json:"node_id"`   // Node whose session count changed
+	At       int64  `json:"timestamp"` // Unix ms of the transition (offline: when the last session left)
+}
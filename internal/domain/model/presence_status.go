@@ -0,0 +1,30 @@
+package model
+
+import "github.com/google/uuid"
+
+// PresenceStatus is a contact's user-facing availability.
+type PresenceStatus string
+
+const (
+	StatusOnline  PresenceStatus = "online"
+	StatusAway    PresenceStatus = "away"
+	StatusDND     PresenceStatus = "dnd"
+	StatusOffline PresenceStatus = "offline"
+)
+
+// Valid reports whether s is one of the known statuses.
+func (s PresenceStatus) Valid() bool {
+	switch s {
+	case StatusOnline, StatusAway, StatusDND, StatusOffline:
+		return true
+	}
+	return false
+}
+
+// PresenceStatusPayload tells a watcher that a contact's status changed.
+type PresenceStatusPayload struct {
+	ContactID uuid.UUID      `json:"contact_id"`
+	DomainID  int64          `json:"domain_id"`
+	Status    PresenceStatus `json:"status"`
+	At        int64          `json:"at"` // Unix ms of the change
+}
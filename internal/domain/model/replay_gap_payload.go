@@ -0,0 +1,8 @@
+package model
+
+// ReplayGapPayload tells a resuming client that events were lost between its cursor
+// and the oldest replayed one; it must refetch history before trusting the stream.
+type ReplayGapPayload struct {
+	Requested string `json:"requested"`        // Cursor sent by the client
+	Oldest    string `json:"oldest,omitempty"` // First cursor that follows (empty if nothing is replayed)
+}
@@ -0,0 +1,10 @@
+package model
+
+import "encoding/json"
+
+// TopicPayload carries an opaque, high-frequency update scoped to an arbitrary entity key
+// (e.g. live transcription of a call) rather than to a specific user.
+type TopicPayload struct {
+	Key  string          `json:"key"`
+	Data json.RawMessage `json:"data"`
+}
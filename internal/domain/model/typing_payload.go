@@ -0,0 +1,11 @@
+package model
+
+import "github.com/google/uuid"
+
+// TypingPayload tells a thread participant that a peer is composing a message.
+type TypingPayload struct {
+	ThreadID  uuid.UUID `json:"thread_id"`
+	DomainID  int64     `json:"domain_id"`
+	From      Peer      `json:"from"`
+	ExpiresAt int64     `json:"expires_at"` // Unix ms; clients hide the indicator afterwards
+}
@@ -0,0 +1,27 @@
+// Package presence defines the cluster-wide presence registry: which node, if
+// any, holds a user's sessions right now.
+package presence
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store maps a user to the node holding their sessions, with a TTL so a crashed
+// node's entries expire on their own.
+//
+// [LAST_WRITER_WINS] A user connected to several nodes is recorded under one of
+// them; Delete only removes the entry while it still names the caller's node, so
+// one node leaving never hides the user's sessions on another.
+type Store interface {
+	// Set records userID on nodeID for ttl.
+	Set(ctx context.Context, userID uuid.UUID, nodeID string, ttl time.Duration) error
+	// Refresh re-records every user in one round-trip; it is the node heartbeat.
+	Refresh(ctx context.Context, userIDs []uuid.UUID, nodeID string, ttl time.Duration) error
+	// Delete removes userID's entry if it still names nodeID.
+	Delete(ctx context.Context, userID uuid.UUID, nodeID string) error
+	// Lookup returns the node holding userID; ok is false when the user is offline everywhere.
+	Lookup(ctx context.Context, userID uuid.UUID) (nodeID string, ok bool, err error)
+}
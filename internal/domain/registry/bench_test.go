@@ -0,0 +1,269 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// Hub benchmarks for regression CI (see .github/workflows/registry-bench.yml).
+//
+// [BASELINE] go test -run '^$' -bench . -benchmem -count 5 ./internal/domain/registry
+// Medians of 5 runs on linux/amd64, 1 vCPU, go1.27. Absolute numbers move with the
+// machine; compare runs on the same host, where a sustained drift beyond ~20% is worth a look:
+//
+//	BenchmarkHub_Broadcast_1K_Users                     2956 ns/op      429 B/op     5 allocs/op
+//	BenchmarkHub_Broadcast_100_Users_10_Sessions       13580 ns/op     2100 B/op    27 allocs/op
+//	BenchmarkHub_Register_Parallel                     35474 ns/op    23901 B/op    34 allocs/op
+//	BenchmarkHub_Eviction                           25841127 ns/op   406896 B/op   879 allocs/op
+//	BenchmarkCell_Deliver_10_Sessions                  23771 ns/op     3840 B/op    50 allocs/op
+//	BenchmarkHub_ReconnectStorm/pooled_class        18389817 ns/op 12081203 B/op 140009 allocs/op
+//	BenchmarkHub_ReconnectStorm/odd_size            60070349 ns/op 177041115 B/op 160000 allocs/op
+//	BenchmarkHub_Stats_200K_Cells                   47173527 ns/op     9816 B/op    87 allocs/op
+//	BenchmarkHub_Broadcast_1K_Users_StatsPolling        3703 ns/op      424 B/op     5 allocs/op
+
+// benchBufferSize keeps session buffers deep enough that drainers, not drops, set the pace.
+const benchBufferSize = 1024
+
+// quietHub silences the Hub's lifecycle logs, which would otherwise interleave with results.
+func quietHub(b *testing.B) {
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	b.Cleanup(func() { slog.SetDefault(prev) })
+}
+
+// drainConnectors reads every connector until the benchmark ends, as a transport would.
+func drainConnectors(b *testing.B, conns []Connector) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, conn := range conns {
+		wg.Go(func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-conn.Recv():
+				}
+			}
+		})
+	}
+	b.Cleanup(func() {
+		cancel()
+		wg.Wait()
+		for _, conn := range conns {
+			conn.Release()
+		}
+	})
+}
+
+// benchHub registers users with sessions each and returns the user IDs.
+func benchHub(b *testing.B, users, sessions int) (*Hub, []uuid.UUID) {
+	quietHub(b)
+	hub := NewHub(WithMailboxSize(benchBufferSize), WithEvictionInterval(time.Hour))
+	b.Cleanup(hub.Shutdown)
+
+	ids := make([]uuid.UUID, users)
+	conns := make([]Connector, 0, users*sessions)
+	for i := range ids {
+		ids[i] = uuid.New()
+		for range sessions {
+			conn := NewConnector(context.Background(), ids[i], benchBufferSize, ConnectMetadata{})
+			hub.Register(conn)
+			conns = append(conns, conn)
+		}
+	}
+	drainConnectors(b, conns)
+	return hub, ids
+}
+
+// BenchmarkHub_Broadcast_1K_Users routes events round-robin over 1000 single-session users.
+func BenchmarkHub_Broadcast_1K_Users(b *testing.B) {
+	hub, ids := benchHub(b, 1000, 1)
+	evs := make([]event.Eventer, len(ids))
+	for i, id := range ids {
+		evs[i] = event.NewSystemEvent(id, event.Ping, event.PriorityNormal, nil)
+	}
+
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		hub.Broadcast(evs[i%len(evs)])
+		i++
+	}
+}
+
+// BenchmarkHub_Broadcast_100_Users_10_Sessions measures per-user fan-out to 10 sessions.
+func BenchmarkHub_Broadcast_100_Users_10_Sessions(b *testing.B) {
+	hub, ids := benchHub(b, 100, 10)
+	evs := make([]event.Eventer, len(ids))
+	for i, id := range ids {
+		evs[i] = event.NewSystemEvent(id, event.Ping, event.PriorityNormal, nil)
+	}
+
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		hub.Broadcast(evs[i%len(evs)])
+		i++
+	}
+}
+
+// BenchmarkHub_Register_Parallel registers new users from GOMAXPROCS goroutines at once.
+func BenchmarkHub_Register_Parallel(b *testing.B) {
+	quietHub(b)
+	hub := NewHub(WithEvictionInterval(time.Hour))
+	b.Cleanup(hub.Shutdown)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			conn := NewConnector(context.Background(), uuid.New(), 16, ConnectMetadata{})
+			hub.Register(conn)
+			hub.Unregister(conn.GetUserID(), conn.GetID())
+			conn.Release()
+		}
+	})
+}
+
+// BenchmarkHub_Eviction reclaims 10K idle Cells in one pass.
+func BenchmarkHub_Eviction(b *testing.B) {
+	const cells = 10_000
+	quietHub(b)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		b.StopTimer()
+		// A negative idle timeout makes every Cell without sessions eligible at once.
+		hub := NewHub(WithIdleTimeout(-time.Hour), WithEvictionInterval(time.Hour))
+		for range cells {
+			conn := NewConnector(context.Background(), uuid.New(), 1, ConnectMetadata{})
+			hub.Register(conn)
+			hub.Unregister(conn.GetUserID(), conn.GetID())
+			conn.Release()
+		}
+		b.StartTimer()
+
+		hub.performEviction()
+
+		b.StopTimer()
+		if n := hub.ConnectedUsers(); n != 0 {
+			b.Fatalf("%d cells survived eviction", n)
+		}
+		hub.Shutdown()
+		b.StartTimer()
+	}
+}
+
+// BenchmarkCell_Deliver_10_Sessions measures one Cell fanning an event out to 10 real connectors.
+func BenchmarkCell_Deliver_10_Sessions(b *testing.B) {
+	userID := uuid.New()
+	cell := NewCell(userID, benchBufferSize, 0, nil, nil, nil)
+	b.Cleanup(cell.Stop)
+
+	conns := make([]Connector, 10)
+	for i := range conns {
+		conns[i] = NewConnector(context.Background(), userID, benchBufferSize, ConnectMetadata{})
+		cell.Attach(conns[i])
+	}
+	drainConnectors(b, conns)
+	ev := event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil)
+
+	b.ReportAllocs()
+	// [LOOP_ONLY] deliver is called directly; the Cell loop is idle since nothing is pushed.
+	for b.Loop() {
+		cell.deliver(ev)
+	}
+}
+
+// BenchmarkHub_ReconnectStorm runs stormCycles Subscribe/Unsubscribe cycles per op.
+// A pooled size class reuses its buffered channel, so B/op stays near the struct
+// cost; an odd size allocates a fresh channel every cycle.
+func BenchmarkHub_ReconnectStorm(b *testing.B) {
+	const stormCycles = 10_000
+	for _, bc := range []struct {
+		name string
+		size int
+	}{
+		{"pooled_class", benchBufferSize},
+		{"odd_size", benchBufferSize - 1},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			quietHub(b)
+			hub := NewHub(WithEvictionInterval(time.Hour))
+			b.Cleanup(hub.Shutdown)
+			userID := uuid.New()
+
+			b.ReportAllocs()
+			for b.Loop() {
+				for range stormCycles {
+					conn := NewConnector(context.Background(), userID, bc.size, ConnectMetadata{})
+					hub.Register(conn)
+					hub.Unregister(userID, conn.GetID())
+					conn.Release()
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkHub_Stats_200K_Cells polls Stats on a node holding 200K single-session
+// users, the debug endpoint's worst case. The walk takes one shard RLock at a time.
+func BenchmarkHub_Stats_200K_Cells(b *testing.B) {
+	const cells = 200_000
+	quietHub(b)
+	hub := NewHub(WithMailboxSize(16), WithEvictionInterval(time.Hour))
+	b.Cleanup(hub.Shutdown)
+
+	conns := make([]Connector, cells)
+	for i := range conns {
+		conns[i] = NewConnector(context.Background(), uuid.New(), 1, ConnectMetadata{})
+		hub.Register(conns[i])
+	}
+	b.Cleanup(func() {
+		for _, conn := range conns {
+			conn.Release()
+		}
+	})
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if st := hub.Stats(); st.TotalUsers != cells {
+			b.Fatalf("Stats saw %d cells, want %d", st.TotalUsers, cells)
+		}
+	}
+}
+
+// BenchmarkHub_Broadcast_1K_Users_StatsPolling repeats BenchmarkHub_Broadcast_1K_Users
+// while another goroutine calls Stats back to back, far more often than a debug
+// endpoint would; the gap between the two is the latency cost of polling.
+func BenchmarkHub_Broadcast_1K_Users_StatsPolling(b *testing.B) {
+	hub, ids := benchHub(b, 1000, 1)
+	evs := make([]event.Eventer, len(ids))
+	for i, id := range ids {
+		evs[i] = event.NewSystemEvent(id, event.Ping, event.PriorityNormal, nil)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for ctx.Err() == nil {
+			hub.Stats()
+		}
+	})
+	b.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		hub.Broadcast(evs[i%len(evs)])
+		i++
+	}
+}
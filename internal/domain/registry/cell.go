@@ -22,10 +22,13 @@ import (
 	"github.com/webitel/im-delivery-service/internal/domain/event"
 )
 
+// Interface guard
+var _ Celler = (*Cell)(nil)
+
 // Celler defines the internal API for user-specific delivery units.
 type Celler interface {
 	Push(ev event.Eventer) bool
-	Attach(conn Connector)
+	Attach(conn Connector) bool
 	Detach(connID uuid.UUID) bool
 	IsIdle(timeout time.Duration) bool
 	Stop()
@@ -48,6 +51,19 @@ type Cell struct {
 	// Allows multiplexing a single event to multiple devices (mobile, web, desktop).
 	sessions map[uuid.UUID]Connector
 
+	// [LIVENESS] Set under mu by Stop; a stopped Cell refuses new sessions.
+	stopped bool
+
+	// [SESSION_LIMIT] Concurrent sessions allowed before the LRU one is kicked (0: unlimited).
+	// Set by the Hub before the Cell is published (see session_limit.go).
+	maxSessions int
+
+	// [PRESENCE] Reports 0->1 and 1->0 session transitions (see presence.go); set like maxSessions.
+	presence func(conn Connector, online bool)
+
+	// [ORPHAN_DETECTION] Closed sessions seen attached by the previous eviction cycle (see orphans.go).
+	suspects map[uuid.UUID]struct{}
+
 	// [CONCURRENCY_CONTROL]
 	// Fine-grained lock for managing the sessions map.
 	// RWMutex is chosen because read-heavy delivery operations outnumber
@@ -59,19 +75,53 @@ type Cell struct {
 	// Ensures no goroutine leaks occur after the user goes offline.
 	doneCh chan struct{}
 
+	// [GRACEFUL_DRAIN] Set before doneCh closes; drained closes once the loop flushed the mailbox (see cell_drain.go).
+	drainBy time.Time
+	drained chan struct{}
+
 	// [OPTIMIZATION] Atomic timestamp to avoid mutex contention during activity checks
 	lastActivityUnix int64
+
+	// [DIAGNOSTICS] Per-session outcome reporter supplied by the Hub (may be nil).
+	observe func(ev event.Eventer, connID uuid.UUID, outcome DeliveryOutcome)
+
+	// [TEARDOWN] Hub bookkeeping run once per retired session (may be nil; see retire.go).
+	retired func(conn Connector, reason RetireReason)
+
+	// [STATS] Hub-wide counters (may be nil for standalone Cells).
+	stats *hubCounters
+
+	// [RESUME] Cursor state and replay history; owned by the loop goroutine (see replay.go).
+	epoch  uint32
+	seq    uint64
+	replay *replayRing
+
+	// [LOOP_OWNED] Reused fan-out snapshot, so deliver does not allocate per event.
+	fanout []Connector
+
+	// [CONTROL_PATH] Operations that must be serialized with delivery run on the loop.
+	control chan func()
 }
 
-func NewCell(userID uuid.UUID, bufferSize int) *Cell {
+func NewCell(userID uuid.UUID, bufferSize, replaySize int, observe func(event.Eventer, uuid.UUID, DeliveryOutcome), retired func(Connector, RetireReason), stats *hubCounters) *Cell {
+	if stats == nil {
+		stats = newHubCounters()
+	}
 	c := &Cell{
 		userID:           userID,
+		observe:          observe,
+		retired:          retired,
+		stats:            stats,
+		epoch:            newEpoch(),
+		replay:           newReplayRing(replaySize),
+		control:          make(chan func()),
 		mailbox:          make(chan event.Eventer, bufferSize),
 		sessions:         make(map[uuid.UUID]Connector),
 		doneCh:           make(chan struct{}),
+		drained:          make(chan struct{}),
 		lastActivityUnix: time.Now().Unix(),
 	}
-	go c.loop()
+	spawn(c.loop)
 	return c
 }
 
@@ -94,6 +144,23 @@ func (c *Cell) IsIdle(timeout time.Duration) bool {
 	return time.Since(lastActivity) > timeout
 }
 
+// claimIdle marks an idle Cell stopped and reports whether it did.
+// [EVICTION_CLAIM] The check and the mark share one critical section with Attach, so
+// once it returns true no session can join; the caller runs Stop later, off any shard lock.
+func (c *Cell) claimIdle(timeout time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped || len(c.sessions) > 0 {
+		return false
+	}
+	if time.Since(time.Unix(atomic.LoadInt64(&c.lastActivityUnix), 0)) <= timeout {
+		return false
+	}
+	c.stopped = true
+	return true
+}
+
 func (c *Cell) Push(ev event.Eventer) bool {
 	c.touch()
 	select {
@@ -101,43 +168,99 @@ func (c *Cell) Push(ev event.Eventer) bool {
 		return true
 	default:
 		// [BACKPRESSURE] Drop event if mailbox is full to protect system stability
+		c.stats.droppedMailbox.Add(1)
 		return false
 	}
 }
 
-func (c *Cell) Attach(conn Connector) {
+// Attach adds a session and reports whether it did; false means the Cell has stopped
+// and the caller must obtain a fresh one. A live session from the same device is
+// superseded; otherwise, at the session limit, the least-recently-active one is kicked.
+func (c *Cell) Attach(conn Connector) bool {
+	var kicked, superseded Connector
+
 	c.mu.Lock()
+	if c.stopped {
+		c.mu.Unlock()
+		return false
+	}
+	first := len(c.sessions) == 0
+	if _, dup := c.sessions[conn.GetID()]; !dup {
+		c.stats.sessions.Add(1)
+		// [RETIRE_WINNER] Both replacements remove the old entry under c.mu, in the same
+		// critical section as the insert, so racing reconnects never see two sessions.
+		if superseded = c.deviceTwin(conn); superseded != nil {
+			delete(c.sessions, superseded.GetID())
+			delete(c.suspects, superseded.GetID())
+		} else if c.maxSessions > 0 && len(c.sessions) >= c.maxSessions {
+			if kicked = c.lruVictim(conn.GetID()); kicked != nil {
+				delete(c.sessions, kicked.GetID())
+				delete(c.suspects, kicked.GetID())
+			}
+		}
+	}
 	c.sessions[conn.GetID()] = conn
 	c.mu.Unlock()
 	c.touch()
+
+	if superseded != nil {
+		c.supersede(superseded)
+	}
+	if kicked != nil {
+		c.kick(kicked)
+	}
+	if first {
+		c.notifyPresence(conn, true)
+	}
+	return true
 }
 
+// isStopped reports whether Stop has run.
+func (c *Cell) isStopped() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stopped
+}
+
+// sessionCount is the number of sessions attached right now.
+func (c *Cell) sessionCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.sessions)
+}
+
+// Detach retires the session on behalf of its transport and reports whether the Cell is now empty.
 func (c *Cell) Detach(connID uuid.UUID) bool {
-	c.mu.Lock()
-	delete(c.sessions, connID)
-	isEmpty := len(c.sessions) == 0
-	c.mu.Unlock()
+	c.retire(connID, RetireUnregister)
 	c.touch()
-	return isEmpty
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.sessions) == 0
 }
 
 func (c *Cell) loop() {
+	defer c.recoverLoop()
+
 	for {
 		select {
 		case <-c.doneCh:
+			c.drainMailbox()
 			return
+		case op := <-c.control:
+			c.safeControl(op)
 		case ev := <-c.mailbox:
 			// [STRATEGY: BATCH_DRAINING]
 			// Once awakened, don't return to the expensive 'select' immediately.
 			// Tight loop to drain pending events reduces scheduler overhead.
-			c.deliver(ev)
+			c.safeDeliver(ev)
 
 			// Attempt to drain up to 64 events in one go to smooth out bursts.
 			// This number is a sweet spot between latency and CPU fairness.
 			for range 64 {
 				select {
 				case nextEv := <-c.mailbox:
-					c.deliver(nextEv)
+					c.safeDeliver(nextEv)
 				default:
 					// Mailbox empty, go back to wait
 					goto wait
@@ -149,27 +272,62 @@ func (c *Cell) loop() {
 }
 
 // deliver broadcasts events to all active sessions of the user.
+//
+// [SNAPSHOT_FANOUT] The session set is copied under the read lock and the lock is
+// released before any Send, so a slow session never blocks Attach/Detach for the
+// same user. Sessions are served one after another on the loop goroutine: no
+// goroutine is started per event, and a panic in one session is recovered by
+// deliverTo without costing its siblings the event.
+// [LOOP_ONLY]
 func (c *Cell) deliver(ev event.Eventer) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.fanout = c.fanout[:0]
+	for _, conn := range c.sessions {
+		c.fanout = append(c.fanout, conn)
+	}
+	c.mu.RUnlock()
+
+	for _, conn := range c.fanout {
+		c.deliverTo(conn, ev)
+	}
+	clear(c.fanout) // Do not pin released connectors until the next event.
+}
 
-	if len(c.sessions) == 0 {
+// deliverTo sends one event to one session and reports the outcome.
+func (c *Cell) deliverTo(conn Connector, ev event.Eventer) {
+	defer c.recoverSession(conn, ev)
+
+	// [KIND_FILTER] Skipped before Send, so excluded kinds never occupy the buffer
+	// or count as drops against the session.
+	if !conn.KindFilter().Allows(ev.GetKind()) {
+		c.report(ev, conn.GetID(), OutcomeFiltered)
 		return
 	}
 
-	for _, conn := range c.sessions {
-		// Strict 250ms window. If a connection is slow, it won't kill the Actor loop.
-		conn.Send(ev, time.Millisecond*250)
+	// [TEARDOWN_SHORTCUT] A disconnecting session would refuse low and normal events
+	// anyway; keep just the high-priority signals for it and report the rest as skipped
+	// rather than as drops against a healthy buffer.
+	if conn.IsClosing() && ev.GetPriority() < event.PriorityHigh {
+		c.report(ev, conn.GetID(), OutcomeSkipped)
+		return
+	}
+
+	// Strict 250ms window. If a connection is slow, it won't kill the Actor loop.
+	if conn.Send(ev, time.Millisecond*250) {
+		c.report(ev, conn.GetID(), OutcomeDelivered)
+	} else {
+		c.stats.droppedSessions.Add(1)
+		c.report(ev, conn.GetID(), OutcomeDropped)
+	}
+}
+
+func (c *Cell) report(ev event.Eventer, connID uuid.UUID, outcome DeliveryOutcome) {
+	if c.observe != nil {
+		c.observe(ev, connID, outcome)
 	}
 }
 
 func (c *Cell) Stop() {
 	close(c.doneCh)
-
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	for id, conn := range c.sessions {
-		conn.Close()
-		delete(c.sessions, id)
-	}
+	c.retireAll(RetireCellStop)
 }
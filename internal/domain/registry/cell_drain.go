@@ -0,0 +1,54 @@
+package registry
+
+import "time"
+
+// # Draining stop
+//
+// Stop (used by the evictor on idle Cells) ends the loop and retires the sessions at once.
+// On Hub shutdown the mailbox may still hold events for connected users, so the stop
+// is split in two:
+//
+//  1. beginStop records the drain deadline and closes doneCh; the loop notices, delivers
+//     what is left in the mailbox until the deadline, then closes drained and exits;
+//  2. finishStop waits for drained (bounded by the same deadline) and only then retires
+//     the sessions, so transports flush the final events before their connectors close.
+//
+// Shutdown runs phase 1 for every Cell before phase 2 for any, so all Cells drain in
+// parallel under one shared deadline.
+
+// drainGrace covers a delivery already in flight when the deadline passes.
+const drainGrace = 250 * time.Millisecond
+
+// beginStop signals the loop to drain until deadline and exit.
+func (c *Cell) beginStop(deadline time.Time) {
+	c.drainBy = deadline // Published to the loop by the close below.
+	close(c.doneCh)
+}
+
+// finishStop waits for the drain and retires every session.
+// A loop that is down (panic restart pending) never drains; the deadline bounds the wait.
+func (c *Cell) finishStop(deadline time.Time) {
+	timer := time.NewTimer(time.Until(deadline) + drainGrace)
+	defer timer.Stop()
+
+	select {
+	case <-c.drained:
+	case <-timer.C:
+	}
+	c.retireAll(RetireCellStop)
+}
+
+// drainMailbox delivers what is left in the mailbox until the drain deadline.
+// [LOOP_ONLY] Runs on the loop's exit path; a zero deadline (plain Stop) skips it.
+func (c *Cell) drainMailbox() {
+	defer close(c.drained)
+
+	for time.Now().Before(c.drainBy) {
+		select {
+		case ev := <-c.mailbox:
+			c.safeDeliver(ev)
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// cellRestartDelay throttles loop restarts so a poisoned Cell cannot spin.
+const cellRestartDelay = time.Second
+
+var cellPanics, _ = registryMeter.Int64Counter(
+	"im_delivery_cell_panics_total",
+	metric.WithDescription("Panics recovered in Cell loops"),
+)
+
+// recoverLoop is deferred at the top of Cell.loop.
+// [SELF_HEALING] The panic is logged and counted, then the loop is restarted after a
+// short delay unless the Cell was stopped meanwhile.
+func (c *Cell) recoverLoop() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	slog.Error("CELL_LOOP_PANIC",
+		"user_id", c.userID,
+		"err", r,
+		"stack", string(debug.Stack()),
+	)
+	// [CARDINALITY] Only the first byte of the user ID is used as a label (256 values).
+	cellPanics.Add(context.Background(), 1,
+		metric.WithAttributes(attribute.String("user_id_prefix", c.userID.String()[:2])),
+	)
+
+	select {
+	case <-c.doneCh:
+		return
+	case <-time.After(cellRestartDelay):
+	}
+	spawn(c.loop)
+}
+
+// safeDeliver isolates a single malformed event so it cannot take the loop down.
+func (c *Cell) safeDeliver(ev event.Eventer) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("CELL_DELIVER_PANIC",
+				"user_id", c.userID,
+				"event_id", ev.GetID(),
+				"kind", ev.GetKind().String(),
+				"err", r,
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+	if ev == nil {
+		return
+	}
+	c.deliver(c.sequence(ev))
+}
+
+// safeControl runs a control operation so a panic in it (a connector's Send during a
+// replay) cannot take the loop down. Every operation answers its caller from a defer,
+// so the caller gets a failure result instead of waiting forever.
+func (c *Cell) safeControl(op func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("CELL_CONTROL_PANIC",
+				"user_id", c.userID,
+				"err", r,
+				"stack", string(debug.Stack()),
+			)
+			cellPanics.Add(context.Background(), 1,
+				metric.WithAttributes(attribute.String("user_id_prefix", c.userID.String()[:2])),
+			)
+		}
+	}()
+	op()
+}
+
+// recoverSession is deferred by deliverTo: a session that panics loses this event,
+// the remaining sessions of the snapshot still get it.
+func (c *Cell) recoverSession(conn Connector, ev event.Eventer) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	slog.Error("CELL_SESSION_PANIC",
+		"user_id", c.userID,
+		"conn_id", conn.GetID(),
+		"event_id", ev.GetID(),
+		"err", r,
+		"stack", string(debug.Stack()),
+	)
+	c.stats.droppedSessions.Add(1)
+	c.report(ev, conn.GetID(), OutcomeDropped)
+}
@@ -0,0 +1,188 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// panicConn is a session whose transport blows up on every Send.
+type panicConn struct{ Connector }
+
+func (panicConn) Send(event.Eventer, time.Duration) bool { panic("send exploded") }
+
+// slowConn is a session whose Send stalls for the whole window.
+type slowConn struct {
+	Connector
+	delay time.Duration
+}
+
+func (s slowConn) Send(event.Eventer, time.Duration) bool {
+	time.Sleep(s.delay)
+	return false
+}
+
+func recvIDs(t *testing.T, conn Connector, n int) []string {
+	t.Helper()
+	ids := make([]string, 0, n)
+	timeout := time.After(2 * time.Second)
+	for len(ids) < n {
+		select {
+		case ev := <-conn.Recv():
+			ids = append(ids, ev.GetID())
+		case <-timeout:
+			t.Fatalf("received %d of %d events", len(ids), n)
+		}
+	}
+	return ids
+}
+
+func TestCellDeliverIsolatesPanickingSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		panicky int // Panicking sessions attached next to the healthy one
+		events  int
+	}{
+		{name: "one panicking sibling", panicky: 1, events: 1},
+		{name: "panics on every event of a burst", panicky: 1, events: 32},
+		{name: "several panicking siblings", panicky: 3, events: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			cell := NewCell(userID, 128, 0, nil, nil, nil)
+			t.Cleanup(cell.Stop)
+
+			healthy := NewConnector(context.Background(), userID, 64, ConnectMetadata{})
+			t.Cleanup(healthy.Release)
+			for range tt.panicky {
+				cell.Attach(panicConn{NewConnector(context.Background(), userID, 64, ConnectMetadata{})})
+			}
+			cell.Attach(healthy)
+
+			want := make([]string, 0, tt.events)
+			for range tt.events {
+				ev := event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil)
+				want = append(want, ev.GetID())
+				if !cell.Push(ev) {
+					t.Fatal("mailbox refused the event")
+				}
+			}
+
+			got := recvIDs(t, healthy, tt.events)
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("event %d: got %s, want %s", i, got[i], want[i])
+				}
+			}
+			if dropped := cell.stats.droppedSessions.Load(); dropped != int64(tt.panicky*tt.events) {
+				t.Fatalf("dropped sessions: got %d, want %d", dropped, tt.panicky*tt.events)
+			}
+		})
+	}
+}
+
+func TestCellAttachNotBlockedBySlowSession(t *testing.T) {
+	userID := uuid.New()
+	cell := NewCell(userID, 16, 0, nil, nil, nil)
+	t.Cleanup(cell.Stop)
+
+	cell.Attach(slowConn{NewConnector(context.Background(), userID, 64, ConnectMetadata{}), 200 * time.Millisecond})
+	cell.Push(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil))
+	time.Sleep(20 * time.Millisecond) // Let the loop enter the stalled Send
+
+	start := time.Now()
+	cell.Attach(NewConnector(context.Background(), userID, 64, ConnectMetadata{}))
+	if took := time.Since(start); took > 10*time.Millisecond {
+		t.Fatalf("Attach took %s while a session was stalled", took)
+	}
+}
+
+// TestCellDeliverToClosingSession checks that a session being torn down still gets
+// high-priority signals while low and normal events are skipped without a send.
+func TestCellDeliverToClosingSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority event.EventPriority
+		want     DeliveryOutcome
+	}{
+		{name: "low skipped", priority: event.PriorityLow, want: OutcomeSkipped},
+		{name: "normal skipped", priority: event.PriorityNormal, want: OutcomeSkipped},
+		{name: "high still sent", priority: event.PriorityHigh, want: OutcomeDelivered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			var got []DeliveryOutcome
+			cell := NewCell(userID, 16, 0, func(_ event.Eventer, _ uuid.UUID, o DeliveryOutcome) {
+				got = append(got, o)
+			}, nil, nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			conn := NewConnector(ctx, userID, 64, ConnectMetadata{})
+			t.Cleanup(conn.Release)
+			cancel() // The client is disconnecting; the transport has not unregistered yet
+			if !conn.IsClosing() {
+				t.Fatal("connector is not closing after its context ended")
+			}
+
+			start := time.Now()
+			cell.deliverTo(conn, event.NewSystemEvent(userID, event.Disconnected, tt.priority, nil))
+			if took := time.Since(start); took > 100*time.Millisecond {
+				t.Fatalf("deliverTo waited %s on a closing session", took)
+			}
+			if len(got) != 1 || got[0] != tt.want {
+				t.Fatalf("outcomes: got %v, want [%v]", got, tt.want)
+			}
+			if queued := len(conn.Recv()); queued != map[bool]int{true: 1}[tt.want == OutcomeDelivered] {
+				t.Fatalf("buffered events: got %d", queued)
+			}
+		})
+	}
+}
+
+// TestCellResumeIsolatesPanickingSession resumes a connector whose Send panics during
+// the replay: the resume must fail rather than hang, and the loop must keep serving.
+func TestCellResumeIsolatesPanickingSession(t *testing.T) {
+	userID := uuid.New()
+	cell := NewCell(userID, 16, 16, nil, nil, nil)
+	t.Cleanup(cell.Stop)
+
+	healthy := NewConnector(context.Background(), userID, 64, ConnectMetadata{})
+	t.Cleanup(healthy.Release)
+	cell.Attach(healthy)
+	for range 3 {
+		cell.Push(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil))
+	}
+	recvIDs(t, healthy, 3)
+
+	resumed := make(chan bool, 1)
+	go func() {
+		// The zero cursor forces a gap notice, so the replay sends at least once.
+		resumed <- cell.AttachResume(panicConn{NewConnector(context.Background(), userID, 64, ConnectMetadata{})}, event.Cursor{})
+	}()
+	select {
+	case ok := <-resumed:
+		if ok {
+			t.Fatal("resume of a panicking session reported attached")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("resume blocked after the replay panicked")
+	}
+	if n := cell.sessionCount(); n != 1 {
+		t.Fatalf("sessions = %d, want only the healthy one", n)
+	}
+
+	// Later control operations and deliveries still run on the same loop.
+	if !cell.CanResume(event.Cursor{Epoch: cell.epoch, Seq: 3}) {
+		t.Fatal("CanResume failed after the panic")
+	}
+	ev := event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil)
+	cell.Push(ev)
+	if got := recvIDs(t, healthy, 1); got[0] != ev.GetID() {
+		t.Fatalf("received %s, want %s", got[0], ev.GetID())
+	}
+}
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"log/slog"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -20,156 +21,353 @@ type Connector interface {
 	GetUserID() uuid.UUID
 	Send(ev event.Eventer, timeout time.Duration) bool // Thread-safe send with backpressure handling
 	Recv() <-chan event.Eventer
-	Close() // Terminate connection and release resources
+	Done() <-chan struct{}        // Closed when the connector is terminated (replaces the closed-Recv signal)
+	PeakDepth() int               // Highest observed buffer occupancy, for right-sizing telemetry
+	IsClosing() bool              // Non-blocking check whether the transport is being torn down
+	ShedIDs() []string            // IDs of the most recently shed events, oldest first (diagnostics)
+	Dropped() uint64              // Events shed on this connection so far
+	Metadata() ConnectMetadata    // Client description captured at subscribe time
+	KindFilter() event.KindFilter // Kinds the session asked for; the Cell skips the rest before Send
+	CreatedAt() time.Time         // When the session was opened
+	Close()                       // Terminate the connection; safe from any goroutine, any number of times
+	CloseWithReason(string)       // Close, recording why; the first recorded reason wins
+	CloseReason() string          // Reason given to CloseWithReason, or "" (for termination notices)
+	Release()                     // Recycle buffers; only the owning transport may call it, after it stopped reading
 }
 
 // [METADATA] EXPORTED FOR TRANSPORT AND ANALYTICS LAYERS
 type ConnectMetadata struct {
-	Platform  string
-	Version   string
-	RemoteIP  string
-	UserAgent string
+	Platform  string `json:"platform,omitempty"`
+	Version   string `json:"version,omitempty"`
+	DeviceID  string `json:"device_id,omitempty"`
+	RemoteIP  string `json:"remote_ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
 }
 
+// LogValue renders the non-empty fields as a group for session-scoped loggers.
+func (m ConnectMetadata) LogValue() slog.Value {
+	var attrs []slog.Attr
+	for _, f := range []struct{ key, val string }{
+		{"platform", m.Platform},
+		{"version", m.Version},
+		{"device_id", m.DeviceID},
+		{"remote_ip", m.RemoteIP},
+		{"user_agent", m.UserAgent},
+	} {
+		if f.val != "" {
+			attrs = append(attrs, slog.String(f.key, f.val))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// connState is the lifecycle of a connector.
+//
+// [LIFECYCLE]
+//
+//	active  -> closing : Close (any goroutine); Send takes only PriorityHigh events, without waiting
+//	closing -> closed  : Release (owning transport); Send is refused from here on
+//	closed  -> (pool)  : the buffer only, by whichever of Release or the last in-flight Send leaves last
+//
+// The struct itself is never reused, so the state never returns to active and a
+// stale holder of the connector is refused for good. A Send that passed the gate
+// before Close may still complete; after Close returns, low and normal priority
+// Sends never do. Send registers itself in inflight
+// before it reads state, and Release publishes closed before it reads inflight, so
+// at least one side always observes the other: the buffer is never handed to
+// another session under a Send that got past the gate.
+type connState int32
+
+const (
+	stateActive connState = iota
+	stateClosing
+	stateClosed
+)
+
 // [CONNECT] CONCRETE IMPLEMENTATION (UNEXPORTED TO FORCE INTERFACE USAGE)
 type connect struct {
 	id             uuid.UUID
 	userID         uuid.UUID
 	metadata       ConnectMetadata
+	kinds          event.KindFilter
 	createdAt      time.Time
 	ctx            context.Context
 	cancelFn       context.CancelFunc
 	sendCh         chan event.Eventer
-	closeOnce      sync.Once // [PROTECTION]
-	lastActivityAt int64     // [ATOMIC_FIELD]
-	droppedCount   uint64    // [ATOMIC_FIELD]
-}
+	writeSem       chan struct{} // [SINGLE_WRITER] Held by the Send that writes sendCh (see connect_evict.go)
+	closeOnce      sync.Once     // [PROTECTION]
+	state          atomic.Int32  // [LIFECYCLE] connState
+	inflight       atomic.Int32  // Sends past the lifecycle gate
+	recycled       atomic.Bool   // Exactly-once guard for the buffer's pool return
+	closeReason    atomic.Pointer[string]
+	lastActivityAt int64   // [ATOMIC_FIELD]
+	droppedCount   uint64  // [ATOMIC_FIELD]
+	peakDepth      int64   // [ATOMIC_FIELD]
+	droppedSince   int64   // [ATOMIC_FIELD] UnixNano of the first drop in the current streak; 0 when healthy
+	shed           shedLog // [DIAGNOSTICS] IDs of recently shed events (see connect_evict.go)
 
-// [POOL] SYNC.POOL FOR OBJECT REUSE (REDUCES GC PRESSURE)
-var connectPool = sync.Pool{
-	New: func() any {
-		return &connect{}
-	},
-}
+	// [SELF_HEALING] Saturation streak after which the session is force-closed (0 disables).
+	forceCloseAfter time.Duration
 
-// [NEW_CONNECTOR] FACTORY FUNCTION USING POOLING
-func NewConnector(ctx context.Context, userID uuid.UUID, bufferSize int) Connector {
-	c := connectPool.Get().(*connect)
+	// [DEGRADATION_NOTICE] Drop count that triggers one DeliveryDegraded event (0 disables).
+	degradedThreshold uint64
+	degradedSent      atomic.Bool
+}
 
-	// [INITIALIZATION]
-	// Delegate state setup to the reset method to ensure a clean slate.
-	c.reset(ctx, userID, bufferSize)
+// ConnectOption configures a connector at creation, before it is registered.
+type ConnectOption func(*connect)
 
-	return c
+// WithKindFilter limits the session to the given event kinds (see event.KindFilter).
+func WithKindFilter(f event.KindFilter) ConnectOption {
+	return func(c *connect) { c.kinds = f }
 }
 
-// reset re-initializes the connector's internal state using a struct literal.
-// This is the cleanest way to wipe 'stale' data from pooled objects and reset the sync.Once guard.
-func (c *connect) reset(ctx context.Context, userID uuid.UUID, bufferSize int) {
+// [NEW_CONNECTOR] FACTORY FUNCTION
+// The buffered channel (the expensive part) comes from the size-class pool when possible;
+// the struct is allocated fresh so no Send can ever observe it re-initialized for another session.
+func NewConnector(ctx context.Context, userID uuid.UUID, bufferSize int, md ConnectMetadata, opts ...ConnectOption) Connector {
 	childCtx, cancel := context.WithCancel(ctx)
-
-	// [BLANK_SLATE_ASSIGNMENT]
-	// By reassigning the pointer's value to a new literal, we ensure all fields,
-	// including metadata and counters, are reset to their zero-values or defaults.
-	*c = connect{
+	c := &connect{
 		id:             uuid.New(),
 		userID:         userID,
+		metadata:       md,
 		createdAt:      time.Now(),
 		ctx:            childCtx,
 		cancelFn:       cancel,
-		sendCh:         make(chan event.Eventer, bufferSize),
+		sendCh:         acquireChan(bufferSize),
+		writeSem:       make(chan struct{}, 1),
 		lastActivityAt: time.Now().UnixNano(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // --- IMPLEMENTATION OF CONNECTOR INTERFACE ---
 
-func (c *connect) GetID() uuid.UUID     { return c.id }
-func (c *connect) GetUserID() uuid.UUID { return c.userID }
+func (c *connect) GetID() uuid.UUID             { return c.id }
+func (c *connect) GetUserID() uuid.UUID         { return c.userID }
+func (c *connect) Metadata() ConnectMetadata    { return c.metadata }
+func (c *connect) KindFilter() event.KindFilter { return c.kinds }
+func (c *connect) CreatedAt() time.Time         { return c.createdAt }
 
 // Send attempts to push an event into the channel.
 // If the channel is full, it tries to evict lower priority events to make room.
 func (c *connect) Send(ev event.Eventer, timeout time.Duration) bool {
+	// [LIFECYCLE_GATE] Register first, then check: see connState.
+	c.inflight.Add(1)
+	defer c.leave()
+	if connState(c.state.Load()) == stateClosed {
+		return false
+	}
+
+	// [TENANT_GUARD] An event addressed to another user never enters this buffer.
+	if uid := ev.GetUserID(); uid != uuid.Nil && uid != c.userID {
+		return false
+	}
+
+	// [TEARDOWN_SIGNALS] A closing session still takes high-priority signals (kicks,
+	// notices), but only into a free slot: its reader is leaving, so waiting is pointless.
+	if c.IsClosing() {
+		return ev.GetPriority() >= event.PriorityHigh && c.offerWhileClosing(ev)
+	}
+
 	// [RESOURCE_MANAGEMENT] Create a localized context to enforce a strict delivery window.
 	// This ensures that the User Cell is not held hostage by a single stalled session.
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	// [SINGLE_WRITER] Producers take turns, so eviction never competes with another
+	// writer for the slots it frees. Waiting for the turn counts against the window.
+	// [FAST_PATH] A free turn is taken without a select on the timer: once the window has
+	// elapsed both cases are ready and Go would pick one at random, shedding with room left.
 	select {
-	// 1. [LIFECYCLE_GATE] Immediately abort if the underlying transport is already dead.
+	case c.writeSem <- struct{}{}:
+	default:
+		select {
+		case c.writeSem <- struct{}{}:
+		case <-c.ctx.Done():
+			return false
+		case <-ctx.Done():
+			c.shedEvent(ev)
+			return false
+		}
+	}
+	defer func() { <-c.writeSem }()
+	if c.ctx.Err() != nil {
+		return false
+	}
+
+	// [PRIMARY_DELIVERY] A free slot is taken at once, whatever is left of the window.
+	select {
+	case c.sendCh <- ev:
+		c.accepted()
+		return true
+	default:
+	}
+	if ctx.Err() != nil {
+		return c.handleBackpressure(ev)
+	}
+
+	select {
+	// 1. [LIFECYCLE_GATE] Abort if the transport dies while we wait for room.
 	case <-c.ctx.Done():
 		return false
 
-	// 2. [PRIMARY_DELIVERY] Attempt to enqueue the event into the session's mailbox.
-	// Unlike a 'default' block, this will wait up to 'timeout' for space to become available,
+	// 2. [PRIMARY_DELIVERY] Wait up to 'timeout' for space to become available,
 	// which smooths out transient network jitter.
 	case c.sendCh <- ev:
+		c.accepted()
 		return true
 
 	// 3. [BACKPRESSURE_THRESHOLD] Triggered if the buffer remains saturated for the entire duration.
 	// This indicates a persistent slow consumer or network congestion.
 	case <-ctx.Done():
 		// Initiate smart eviction or shedding logic to preserve system throughput.
-		return c.handleBackpressure(ev, timeout)
+		return c.handleBackpressure(ev)
 	}
 }
 
-// handleBackpressure manages full buffers by dropping low-priority events.
-func (c *connect) handleBackpressure(ev event.Eventer, timeout time.Duration) bool {
-	// If the incoming event is low priority, drop it immediately to save buffer for high priority
-	if ev.GetPriority() <= event.PriorityLow {
-		atomic.AddUint64(&c.droppedCount, 1)
+// accepted records an event that entered the buffer.
+func (c *connect) accepted() {
+	c.trackDepth()
+	atomic.StoreInt64(&c.droppedSince, 0)
+	atomic.StoreInt64(&c.lastActivityAt, time.Now().UnixNano())
+}
+
+// offerWhileClosing enqueues ev only if the writer turn and a buffer slot are free
+// right now. Unlike offer it ignores the ended context, which is what closing means.
+func (c *connect) offerWhileClosing(ev event.Eventer) bool {
+	select {
+	case c.writeSem <- struct{}{}:
+		defer func() { <-c.writeSem }()
+	default:
 		return false
 	}
-
-	// Try to evict one existing low-priority event from the channel to make room
-	// This is a simplified LIFO eviction for high-priority messages
 	select {
-	case oldEv := <-c.sendCh:
-		if oldEv.GetPriority() < ev.GetPriority() {
-			// Successfully replaced lower priority event with a higher one
-			c.sendCh <- ev
-			return true
-		}
-		// If the existing event was also high priority, put it back (best effort)
-		select {
-		case c.sendCh <- oldEv:
-		default:
-			// If we can't even put it back, it's lost
-		}
-	case <-time.After(timeout):
-		// Hard timeout reached
+	case c.sendCh <- ev:
+		c.trackDepth()
+		return true
+	default:
+		return false
 	}
+}
 
+// markDropped counts a drop and opens the saturation streak if none is running.
+func (c *connect) markDropped() {
 	atomic.AddUint64(&c.droppedCount, 1)
-	return false
+	atomic.CompareAndSwapInt64(&c.droppedSince, 0, time.Now().UnixNano())
 }
 
+// saturatedTooLong reports whether drops have persisted beyond forceCloseAfter.
+func (c *connect) saturatedTooLong() bool {
+	if c.forceCloseAfter <= 0 {
+		return false
+	}
+	since := atomic.LoadInt64(&c.droppedSince)
+	return since != 0 && time.Since(time.Unix(0, since)) > c.forceCloseAfter
+}
+
+// setForceCloseAfter is applied by the Hub at registration, before any Send.
+func (c *connect) setForceCloseAfter(d time.Duration) { c.forceCloseAfter = d }
+
+// setDegradedThreshold is applied by the Hub at registration, before any Send.
+func (c *connect) setDegradedThreshold(n uint64) { c.degradedThreshold = n }
+
+// lastActive is the UnixNano of the last event accepted into the buffer.
+// A stalled consumer stops accepting, so its activity ages.
+func (c *connect) lastActive() int64 { return atomic.LoadInt64(&c.lastActivityAt) }
+
+func (c *connect) Dropped() uint64 { return atomic.LoadUint64(&c.droppedCount) }
+
 func (c *connect) Recv() <-chan event.Eventer { return c.sendCh }
 
-// Close terminates the session, triggers cleanup, and recycles the object.
+func (c *connect) Done() <-chan struct{} { return c.ctx.Done() }
+
+func (c *connect) PeakDepth() int { return int(atomic.LoadInt64(&c.peakDepth)) }
+
+// IsClosing reports whether the connector was closed or its context cancelled.
+// [NON_BLOCKING] Reads the done channel without waiting.
+func (c *connect) IsClosing() bool {
+	if connState(c.state.Load()) != stateActive {
+		return true
+	}
+	// The parent context (stream/request) may end before anyone calls Close.
+	select {
+	case <-c.ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// trackDepth records the buffer high-water mark with a lock-free CAS loop.
+func (c *connect) trackDepth() {
+	depth := int64(len(c.sendCh))
+	for {
+		peak := atomic.LoadInt64(&c.peakDepth)
+		if depth <= peak || atomic.CompareAndSwapInt64(&c.peakDepth, peak, depth) {
+			return
+		}
+	}
+}
+
+// Close terminates the session. It only signals; buffers are recycled by Release.
 func (c *connect) Close() {
 	// [IDEMPOTENCY_SHIELD]
-	// Ensures the teardown logic runs exactly once. This prevents "panic: close of closed channel"
-	// and double-entry corruption of the sync.Pool when called concurrently
-	// by the Hub (shutdown), Cell (eviction), or gRPC handler (defer).
+	// Ensures the teardown logic runs exactly once when called concurrently
+	// by the Hub (shutdown), Cell (eviction), or transport handler (defer).
 	c.closeOnce.Do(func() {
-		// 1. [SIGNAL_ABORT] Immediately cancel the context to stop any pending Send operations.
+		c.state.CompareAndSwap(int32(stateActive), int32(stateClosing))
+		// [SIGNAL_ABORT] Cancel the context to stop pending Send operations and
+		// wake the transport loop via Done(). The channel stays open so it can be reused.
 		c.cancelFn()
+	})
+}
 
-		// 2. [UPSTREAM_NOTIFY] Closing the channel signals the gRPC stream handler (via !ok)
-		// to send a final 'Disconnected' event and exit the loop gracefully.
-		if c.sendCh != nil {
-			close(c.sendCh)
-		}
+// CloseWithReason records the reason (unless one is already set) and closes.
+// The reason is stored before the context is cancelled, so a transport woken by
+// Done always observes it.
+func (c *connect) CloseWithReason(reason string) {
+	c.closeReason.CompareAndSwap(nil, &reason)
+	c.Close()
+}
 
-		// 3. [MEMORY_SANITIZATION]
-		// Zero out references to prevent memory leaks while the object is idle in the pool.
-		// This ensures the next user of this pooled object starts with a clean slate.
-		c.sendCh = nil
-		c.metadata = ConnectMetadata{}
+func (c *connect) CloseReason() string {
+	if r := c.closeReason.Load(); r != nil {
+		return *r
+	}
+	return ""
+}
 
-		// 4. [RESOURCE_RECYCLING] Return the sanitized structure to reduce GC allocation pressure.
-		connectPool.Put(c)
-	})
+// Release terminates the session (if not yet closed) and returns the channel to
+// its pool once no Send is in flight.
+//
+// [OWNERSHIP] Must be called exactly once by the transport that owns the connector,
+// after its read loop has exited; otherwise a stale reader could observe the next tenant's events.
+func (c *connect) Release() {
+	c.Close()
+	c.state.Store(int32(stateClosed))
+	c.tryRecycle()
+}
+
+// leave ends a Send; the last one out after Release performs the deferred recycle.
+func (c *connect) leave() {
+	if c.inflight.Add(-1) == 0 && connState(c.state.Load()) == stateClosed {
+		c.tryRecycle()
+	}
+}
+
+// tryRecycle returns the buffer to its pool if the connector is closed and idle.
+// Both Release and leave may race here; the CAS lets exactly one through.
+func (c *connect) tryRecycle() {
+	if c.inflight.Load() != 0 || !c.recycled.CompareAndSwap(false, true) {
+		return
+	}
+
+	// [MEMORY_SANITIZATION]
+	// Stale events are drained (or the channel discarded) before it re-enters the pool.
+	releaseChan(c.sendCh)
 }
@@ -0,0 +1,172 @@
+package registry
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// shedLogSize bounds how many shed event IDs a connector remembers.
+const shedLogSize = 16
+
+// shedLog is a small ring of the IDs of events a connector gave up on.
+type shedLog struct {
+	mu   sync.Mutex
+	ids  [shedLogSize]string
+	next int
+	size int
+}
+
+func (l *shedLog) add(id string) {
+	l.mu.Lock()
+	l.ids[l.next] = id
+	l.next = (l.next + 1) % shedLogSize
+	l.size = min(l.size+1, shedLogSize)
+	l.mu.Unlock()
+}
+
+func (l *shedLog) snapshot() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	res := make([]string, 0, l.size)
+	for i := range l.size {
+		res = append(res, l.ids[(l.next-l.size+i+shedLogSize)%shedLogSize])
+	}
+	return res
+}
+
+// ShedIDs returns the IDs of the most recently shed events, oldest first.
+func (c *connect) ShedIDs() []string { return c.shed.snapshot() }
+
+// handleBackpressure runs once the buffer stayed full for the whole send window.
+//
+// [PRIORITY_EVICTION]
+// The buffered events are taken out without blocking, the oldest event of the lowest
+// priority below the incoming one is shed, and the survivors are put back in their
+// original order followed by the incoming event. Equal priorities therefore keep FIFO
+// order, and nothing ever overtakes an older event of its own priority.
+//
+// [SERIALIZED] It runs inside Send while writeSem is held, so no other producer can
+// take the slots it frees: the transport only ever removes events, which leaves at
+// least as much room for the survivors as was taken out, and they go back in order.
+// [GUARDED_WRITES] Every write back into the channel is a select on c.ctx.Done()
+// with a default branch: a closed connector sheds the event instead of blocking
+// the Cell loop.
+func (c *connect) handleBackpressure(ev event.Eventer) bool {
+	// [ESCALATION] A buffer that has stayed saturated past the window belongs to a dead
+	// consumer; closing it stops the session from starving its siblings in the Cell.
+	if c.saturatedTooLong() {
+		c.Close()
+		c.shedEvent(ev)
+		return false
+	}
+
+	// Low priority never displaces anything; shed it up front.
+	if ev.GetPriority() <= event.PriorityLow {
+		c.shedEvent(ev)
+		c.checkDegraded()
+		return false
+	}
+
+	ok := c.evictFor(ev)
+	c.checkDegraded()
+	return ok
+}
+
+// evictFor makes room for ev by shedding a lower-priority buffered event.
+// [WRITE_SEM_REQUIRED]
+func (c *connect) evictFor(ev event.Eventer) bool {
+	pending := c.takeBuffered()
+	victim := -1
+	for i, old := range pending {
+		if old.GetPriority() < ev.GetPriority() && (victim < 0 || old.GetPriority() < pending[victim].GetPriority()) {
+			victim = i
+		}
+	}
+
+	if victim >= 0 {
+		c.shed.add(pending[victim].GetID())
+		atomic.AddUint64(&c.droppedCount, 1)
+		pending = append(pending[:victim], pending[victim+1:]...)
+	}
+
+	for _, old := range pending {
+		if !c.offer(old) {
+			c.shed.add(old.GetID())
+			atomic.AddUint64(&c.droppedCount, 1)
+		}
+	}
+
+	if victim >= 0 && c.offer(ev) {
+		c.trackDepth()
+		atomic.StoreInt64(&c.droppedSince, 0)
+		return true
+	}
+
+	c.shedEvent(ev)
+	return false
+}
+
+// checkDegraded pushes a single DeliveryDegraded notice once drops reach the threshold.
+// [ONCE_PER_CONNECTION] The CAS is never reset for the life of the connector.
+// [WRITE_SEM_REQUIRED]
+func (c *connect) checkDegraded() {
+	dropped := c.Dropped()
+	if c.degradedThreshold == 0 || dropped < c.degradedThreshold || c.ctx.Err() != nil {
+		return
+	}
+	if !c.degradedSent.CompareAndSwap(false, true) {
+		return
+	}
+
+	notice := event.NewSystemEvent(c.userID, event.DeliveryDegraded, event.PriorityHigh, &model.DeliveryDegradedPayload{
+		Dropped:   dropped,
+		Threshold: c.degradedThreshold,
+	})
+	// The buffer is full by definition here; the notice takes a low-priority slot.
+	if !c.offer(notice) && !c.evictFor(notice) {
+		slog.Debug("DELIVERY_DEGRADED_NOTICE_SHED", slog.String("user_id", c.userID.String()), slog.String("conn_id", c.id.String()))
+	}
+}
+
+// takeBuffered removes whatever is currently buffered without waiting.
+// The transport may read concurrently; it only ever takes events older than ours.
+func (c *connect) takeBuffered() []event.Eventer {
+	pending := make([]event.Eventer, 0, len(c.sendCh))
+	for range cap(c.sendCh) {
+		select {
+		case old := <-c.sendCh:
+			pending = append(pending, old)
+		default:
+			return pending
+		}
+	}
+	return pending
+}
+
+// offer is the only write path outside Send's primary select on an active
+// connector (see offerWhileClosing for a closing one); it never blocks.
+// [WRITE_SEM_REQUIRED]
+func (c *connect) offer(ev event.Eventer) bool {
+	// Checked first: select picks randomly among ready cases.
+	if c.ctx.Err() != nil {
+		return false
+	}
+	select {
+	case <-c.ctx.Done():
+		return false
+	case c.sendCh <- ev:
+		return true
+	default:
+		return false
+	}
+}
+
+// shedEvent records the incoming event as dropped.
+func (c *connect) shedEvent(ev event.Eventer) {
+	c.shed.add(ev.GetID())
+	c.markDropped()
+}
@@ -0,0 +1,171 @@
+package registry
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+func drainIDs(conn Connector) []string {
+	var ids []string
+	for {
+		select {
+		case ev := <-conn.Recv():
+			ids = append(ids, ev.GetID())
+		default:
+			return ids
+		}
+	}
+}
+
+func TestEvictFor(t *testing.T) {
+	const (
+		low    = event.PriorityLow
+		normal = event.PriorityNormal
+		high   = event.PriorityHigh
+	)
+	tests := []struct {
+		name     string
+		buffered []event.EventPriority
+		incoming event.EventPriority
+		closed   bool
+		accepted bool
+		shed     int   // Index into buffered of the shed event; -1 sheds the incoming one
+		want     []int // Remaining buffer as indexes into buffered; -1 is the incoming event
+	}{
+		{name: "low incoming dropped", buffered: []event.EventPriority{low, low}, incoming: low, shed: -1, want: []int{0, 1}},
+		{name: "high evicts low", buffered: []event.EventPriority{normal, low}, incoming: high, accepted: true, shed: 1, want: []int{0, -1}},
+		{name: "oldest of the lowest goes", buffered: []event.EventPriority{low, normal, low}, incoming: high, accepted: true, shed: 0, want: []int{1, 2, -1}},
+		{name: "equal priority keeps fifo", buffered: []event.EventPriority{high, high}, incoming: high, shed: -1, want: []int{0, 1}},
+		{name: "closed mid-eviction", buffered: []event.EventPriority{low, low}, incoming: high, closed: true, shed: -1, want: nil}, // A closed buffer is discarded
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			conn := NewConnector(context.Background(), userID, len(tt.buffered), ConnectMetadata{}).(*connect)
+			ids := make([]string, len(tt.buffered))
+			for i, p := range tt.buffered {
+				ev := event.NewSystemEvent(userID, event.Ping, p, nil)
+				ids[i] = ev.GetID()
+				if !conn.Send(ev, time.Millisecond) {
+					t.Fatalf("could not fill slot %d", i)
+				}
+			}
+			in := event.NewSystemEvent(userID, event.Ping, tt.incoming, nil)
+			if tt.closed {
+				conn.Close()
+			}
+
+			// Evict as Send does, holding the writer turn.
+			var got bool
+			if tt.incoming <= event.PriorityLow {
+				got = conn.Send(in, time.Millisecond)
+			} else {
+				conn.writeSem <- struct{}{}
+				got = conn.evictFor(in)
+				<-conn.writeSem
+			}
+			if got != tt.accepted {
+				t.Fatalf("accepted: got %v, want %v", got, tt.accepted)
+			}
+
+			shedID := in.GetID()
+			if tt.shed >= 0 {
+				shedID = ids[tt.shed]
+			}
+			if !tt.closed && !slices.Contains(conn.ShedIDs(), shedID) {
+				t.Fatalf("shed IDs %v do not record %s", conn.ShedIDs(), shedID)
+			}
+
+			want := make([]string, 0, len(tt.want))
+			for _, i := range tt.want {
+				if i < 0 {
+					want = append(want, in.GetID())
+				} else {
+					want = append(want, ids[i])
+				}
+			}
+			if got := drainIDs(conn); !slices.Equal(got, want) {
+				t.Fatalf("buffer: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestEvictConcurrentProducers checks that racing producers never reorder events of
+// the same priority and that every event is either received or counted as dropped.
+func TestEvictConcurrentProducers(t *testing.T) {
+	tests := []struct {
+		name      string
+		producers int
+		events    int
+		size      int
+	}{
+		{name: "two racing producers", producers: 2, events: 500, size: 4},
+		{name: "many producers, tiny buffer", producers: 8, events: 200, size: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			conn := NewConnector(context.Background(), userID, tt.size, ConnectMetadata{})
+
+			type tag struct{ producer, seq int }
+			var wg sync.WaitGroup
+			for p := range tt.producers {
+				wg.Go(func() {
+					for i := range tt.events {
+						prio := event.PriorityNormal
+						if i%3 == 0 {
+							prio = event.PriorityHigh
+						}
+						conn.Send(event.NewSystemEvent(userID, event.Ping, prio, tag{p, i}), 50*time.Microsecond)
+					}
+				})
+			}
+
+			done := make(chan struct{})
+			go func() { wg.Wait(); close(done) }()
+
+			received := 0
+			last := map[[2]int]int{} // {producer, priority} -> last seq
+			check := func(ev event.Eventer) {
+				received++
+				tg := ev.GetPayload().(tag)
+				k := [2]int{tg.producer, int(ev.GetPriority())}
+				if prev, ok := last[k]; ok && tg.seq <= prev {
+					t.Fatalf("producer %d priority %d: seq %d after %d", tg.producer, k[1], tg.seq, prev)
+				}
+				last[k] = tg.seq
+			}
+		consume:
+			for {
+				select {
+				case ev := <-conn.Recv():
+					check(ev)
+					time.Sleep(10 * time.Microsecond) // A consumer slower than the producers
+				case <-done:
+					break consume
+				}
+			}
+			for {
+				select {
+				case ev := <-conn.Recv():
+					check(ev)
+					continue
+				default:
+				}
+				break
+			}
+
+			if total := uint64(tt.producers * tt.events); uint64(received)+conn.Dropped() != total {
+				t.Fatalf("received %d + dropped %d != sent %d", received, conn.Dropped(), total)
+			}
+			conn.Release()
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package registry
+
+import (
+	"math/bits"
+	"sync"
+
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// [SIZE_CLASSES] Power-of-two buffer capacities whose channels are retained across reuse.
+const (
+	minChanClassShift = 6  // 64
+	maxChanClassShift = 12 // 4096
+)
+
+// chanPools holds one pool per size class.
+//
+// [STRATEGY]
+// A channel's capacity is fixed at creation, so a single pool of connectors never
+// reuses the buffered channel when sizes differ per transport. Pooling channels by
+// exact capacity keeps the expensive allocation out of reconnect storms; sizes
+// outside the classes are allocated fresh and simply dropped on release.
+var chanPools [maxChanClassShift - minChanClassShift + 1]sync.Pool
+
+// chanClass maps a capacity to its pool index, or -1 if it is not a pooled class.
+func chanClass(size int) int {
+	if size <= 0 || size&(size-1) != 0 {
+		return -1
+	}
+	shift := bits.TrailingZeros(uint(size))
+	if shift < minChanClassShift || shift > maxChanClassShift {
+		return -1
+	}
+	return shift - minChanClassShift
+}
+
+// acquireChan returns an empty channel with exactly the requested capacity.
+func acquireChan(size int) chan event.Eventer {
+	if idx := chanClass(size); idx >= 0 {
+		if ch, ok := chanPools[idx].Get().(chan event.Eventer); ok {
+			// [DEFENSIVE_DRAIN] A late Send may have slipped in after release.
+			if drainChan(ch) {
+				return ch
+			}
+		}
+	}
+	return make(chan event.Eventer, size)
+}
+
+// releaseChan returns a channel to its class pool once it is verifiably empty.
+func releaseChan(ch chan event.Eventer) {
+	if ch == nil {
+		return
+	}
+	idx := chanClass(cap(ch))
+	if idx < 0 || !drainChan(ch) {
+		// [DISCARD] Odd size or a writer is still racing in: let the GC take it.
+		return
+	}
+	chanPools[idx].Put(ch)
+}
+
+// drainChan empties the channel, reading at most cap(ch) events.
+// It reports false if the channel could not be emptied within that bound.
+func drainChan(ch chan event.Eventer) bool {
+	for range cap(ch) {
+		select {
+		case <-ch:
+		default:
+			return true
+		}
+	}
+	return len(ch) == 0
+}
@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+func TestAcquireChanCapacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		released int // Capacity returned to the pools first (0: none)
+		size     int
+	}{
+		{name: "smallest class", size: 64},
+		{name: "largest class", size: 4096},
+		{name: "odd size", size: 100},
+		{name: "below the classes", size: 32},
+		{name: "above the classes", size: 8192},
+		{name: "same class reused", released: 256, size: 256},
+		{name: "neighbouring class not borrowed", released: 256, size: 512},
+		{name: "odd size not served from a class", released: 128, size: 127},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.released > 0 {
+				releaseChan(make(chan event.Eventer, tt.released))
+			}
+			if got := cap(acquireChan(tt.size)); got != tt.size {
+				t.Fatalf("cap = %d, want %d", got, tt.size)
+			}
+		})
+	}
+}
+
+// TestReusedChannelStartsEmpty fills a connector as its tenant leaves it and checks
+// that the next session on a recycled channel starts with nothing queued.
+func TestReusedChannelStartsEmpty(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		queued int
+	}{
+		{name: "partly filled", size: 64, queued: 10},
+		{name: "full", size: 64, queued: 64},
+		{name: "unpooled size", size: 100, queued: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev, next := uuid.New(), uuid.New()
+			old := NewConnector(context.Background(), prev, tt.size, ConnectMetadata{})
+			// [NO_TIMING] Filled straight into the buffer: only the pooling is under test.
+			for range tt.queued {
+				old.(*connect).sendCh <- event.NewSystemEvent(prev, event.Ping, event.PriorityHigh, nil)
+			}
+			old.Release()
+
+			fresh := NewConnector(context.Background(), next, tt.size, ConnectMetadata{})
+			t.Cleanup(fresh.Release)
+			if n := len(fresh.Recv()); n != 0 {
+				t.Fatalf("new session starts with %d queued events of the previous tenant", n)
+			}
+			if got := cap(fresh.Recv()); got != tt.size {
+				t.Fatalf("cap = %d, want %d", got, tt.size)
+			}
+		})
+	}
+}
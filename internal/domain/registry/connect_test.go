@@ -0,0 +1,132 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+func TestConnectSendAfterRelease(t *testing.T) {
+	tests := []struct {
+		name     string
+		prepare  func(Connector)
+		priority event.EventPriority
+		want     bool
+	}{
+		{name: "active", prepare: func(Connector) {}, priority: event.PriorityNormal, want: true},
+		{name: "closed, high", prepare: func(c Connector) { c.Close() }, priority: event.PriorityHigh, want: true},
+		{name: "closed, normal", prepare: func(c Connector) { c.Close() }, priority: event.PriorityNormal, want: false},
+		{name: "closed, low", prepare: func(c Connector) { c.Close() }, priority: event.PriorityLow, want: false},
+		{name: "closed with reason, high", prepare: func(c Connector) { c.CloseWithReason("kicked") }, priority: event.PriorityHigh, want: true},
+		{name: "closed with reason, normal", prepare: func(c Connector) { c.CloseWithReason("kicked") }, priority: event.PriorityNormal, want: false},
+		{name: "released", prepare: func(c Connector) { c.Release() }, priority: event.PriorityHigh, want: false},
+		{name: "released twice", prepare: func(c Connector) { c.Release(); c.Release() }, priority: event.PriorityHigh, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			conn := NewConnector(context.Background(), userID, 64, ConnectMetadata{})
+			tt.prepare(conn)
+
+			ev := event.NewSystemEvent(userID, event.Ping, tt.priority, nil)
+			if got := conn.Send(ev, time.Millisecond); got != tt.want {
+				t.Fatalf("Send: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestConnectSendWithRoomNeverSheds sends with a window that has already elapsed:
+// a free writer turn and a free slot must still take the event every time.
+func TestConnectSendWithRoomNeverSheds(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		timeout time.Duration
+	}{
+		{name: "zero window", size: 64, timeout: 0},
+		{name: "negative window", size: 64, timeout: -time.Second},
+		{name: "unpooled size", size: 100, timeout: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			conn := NewConnector(context.Background(), userID, tt.size, ConnectMetadata{})
+			t.Cleanup(conn.Release)
+
+			for i := range tt.size {
+				if !conn.Send(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil), tt.timeout) {
+					t.Fatalf("send %d of %d shed with room in the buffer", i+1, tt.size)
+				}
+			}
+			if got := conn.Dropped(); got != 0 {
+				t.Fatalf("dropped = %d, want 0", got)
+			}
+		})
+	}
+}
+
+// TestConnectNoCrossUserDelivery hammers a released connector from stale holders while
+// the next session reuses its pooled buffer; run it with -race.
+func TestConnectNoCrossUserDelivery(t *testing.T) {
+	tests := []struct {
+		name    string
+		senders int
+		rounds  int
+		size    int
+	}{
+		{name: "pooled class", senders: 8, rounds: 40, size: 64},
+		{name: "many stale senders", senders: 16, rounds: 10, size: 64},
+		{name: "unpooled size", senders: 8, rounds: 10, size: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var leaked atomic.Int64
+			for range tt.rounds {
+				stale, next := uuid.New(), uuid.New()
+				old := NewConnector(context.Background(), stale, tt.size, ConnectMetadata{})
+
+				stop := make(chan struct{})
+				var wg sync.WaitGroup
+				for range tt.senders {
+					wg.Go(func() {
+						for {
+							select {
+							case <-stop:
+								return
+							default:
+								old.Send(event.NewSystemEvent(stale, event.Ping, event.PriorityHigh, nil), time.Microsecond)
+							}
+						}
+					})
+				}
+
+				old.Release()
+				fresh := NewConnector(context.Background(), next, tt.size, ConnectMetadata{})
+				deadline := time.After(time.Millisecond)
+			read:
+				for {
+					select {
+					case ev := <-fresh.Recv():
+						if ev.GetUserID() != next {
+							leaked.Add(1)
+						}
+					case <-deadline:
+						break read
+					}
+				}
+				close(stop)
+				wg.Wait()
+				fresh.Release()
+			}
+			if n := leaked.Load(); n != 0 {
+				t.Fatalf("%d events of a released session reached the next one", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package registry
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrUserNotOnNode is returned when the user holds no Cell on this node.
+var ErrUserNotOnNode = errors.New("registry: user has no sessions on this node")
+
+// Disconnect force-closes one session of the user (connID set) or all of them (uuid.Nil)
+// and returns how many were closed. The reason reaches each transport through
+// Connector.CloseReason, for its termination notice. The Cell itself is left to the
+// idle evictor, so a quick reconnect keeps its replay history.
+func (h *Hub) Disconnect(userID, connID uuid.UUID, reason string) (int, error) {
+	s := h.getShard(userID)
+
+	s.RLock()
+	cell, ok := s.cells[userID]
+	s.RUnlock()
+	if !ok {
+		return 0, ErrUserNotOnNode
+	}
+	return cell.disconnect(connID, reason), nil
+}
+
+// disconnect retires the selected sessions with RetireAdmin.
+// [RETIRE_WINNER] Entries are removed under c.mu, exactly as in retire.
+func (c *Cell) disconnect(connID uuid.UUID, reason string) int {
+	c.mu.Lock()
+	var conns []Connector
+	for id, conn := range c.sessions {
+		if connID != uuid.Nil && id != connID {
+			continue
+		}
+		conns = append(conns, conn)
+		delete(c.sessions, id)
+		delete(c.suspects, id)
+	}
+	last := len(conns) > 0 && len(c.sessions) == 0
+	c.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.CloseWithReason(reason)
+		c.finishRetire(conn, RetireAdmin)
+	}
+	if last {
+		c.notifyPresence(conns[0], false)
+	}
+	c.touch()
+	return len(conns)
+}
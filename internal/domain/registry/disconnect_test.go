@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestHubDisconnect(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions int
+		target   int // Index of the session to close; -1 closes all
+		unknown  bool
+		want     int
+		wantErr  error
+	}{
+		{name: "one session of two", sessions: 2, target: 0, want: 1},
+		{name: "every session", sessions: 3, target: -1, want: 3},
+		{name: "unknown connection", sessions: 1, target: 5, want: 0},
+		{name: "user not on this node", unknown: true, target: -1, wantErr: ErrUserNotOnNode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub()
+			t.Cleanup(hub.Shutdown)
+
+			userID := uuid.New()
+			conns := make([]Connector, tt.sessions)
+			for i := range conns {
+				conns[i] = NewConnector(context.Background(), userID, 8, ConnectMetadata{})
+				hub.Register(conns[i])
+			}
+			if tt.unknown {
+				userID = uuid.New()
+			}
+
+			connID := uuid.Nil
+			if tt.target >= len(conns) {
+				connID = uuid.New()
+			} else if tt.target >= 0 {
+				connID = conns[tt.target].GetID()
+			}
+
+			got, err := hub.Disconnect(userID, connID, "kicked")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("closed: got %d, want %d", got, tt.want)
+			}
+			closed := 0
+			for _, c := range conns {
+				if c.IsClosing() {
+					closed++
+					if c.CloseReason() != "kicked" {
+						t.Fatalf("close reason: got %q, want %q", c.CloseReason(), "kicked")
+					}
+				}
+			}
+			if closed != tt.want {
+				t.Fatalf("closing connectors: got %d, want %d", closed, tt.want)
+			}
+			if left := hub.ConnectedCount(userID); !tt.unknown && left != tt.sessions-tt.want {
+				t.Fatalf("sessions left: got %d, want %d", left, tt.sessions-tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package registry
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// ReasonServerDraining is the DisconnectedPayload reason sent to every session when the node drains.
+const ReasonServerDraining = "server_draining"
+
+// drainPoll is how often BeginDrain re-checks the mailboxes.
+const drainPoll = 10 * time.Millisecond
+
+// DrainNotice builds the farewell for one user; nil falls back to a bare SHUTDOWN notice.
+// The Reason is always overwritten with ReasonServerDraining.
+type DrainNotice func(userID uuid.UUID) *model.DisconnectedPayload
+
+// BeginDrain puts the Hub into the draining state ahead of Shutdown.
+//
+// [SEQUENCE]
+//  1. Draining reports true, so the service layer refuses new subscriptions;
+//  2. every user with a live session gets a Disconnected notice, queued behind what
+//     is already in the mailbox so nothing in flight is overtaken;
+//  3. it waits, until deadline at most, for every mailbox to empty.
+//
+// Sessions stay attached: Shutdown still closes them. Calling it again is a no-op.
+func (h *Hub) BeginDrain(deadline time.Time, notice DrainNotice) {
+	if !h.draining.CompareAndSwap(false, true) {
+		return
+	}
+
+	var cells []*Cell
+	for _, s := range h.shards {
+		s.RLock()
+		for _, cell := range s.cells {
+			if cell.sessionCount() > 0 {
+				cells = append(cells, cell)
+			}
+		}
+		s.RUnlock()
+	}
+
+	unsent := 0
+	for _, cell := range cells {
+		payload := &model.DisconnectedPayload{Code: "SHUTDOWN"}
+		if notice != nil {
+			payload = notice(cell.userID)
+		}
+		payload.Reason = ReasonServerDraining
+		// [BEST_EFFORT] A saturated mailbox loses the notice; the transport's own
+		// termination notice still follows when Shutdown closes the connector.
+		if !cell.Push(event.NewSystemEvent(cell.userID, event.Disconnected, event.PriorityHigh, payload)) {
+			unsent++
+		}
+	}
+
+	for time.Now().Before(deadline) && pendingMail(cells) > 0 {
+		time.Sleep(drainPoll)
+	}
+
+	slog.Info("HUB_DRAINED",
+		slog.Int("users", len(cells)),
+		slog.Int("notices_unsent", unsent),
+		slog.Int("mail_left", pendingMail(cells)),
+	)
+}
+
+// Draining reports whether BeginDrain has been called.
+func (h *Hub) Draining() bool {
+	return h.draining.Load()
+}
+
+// pendingMail counts events still queued across cells.
+func pendingMail(cells []*Cell) int {
+	n := 0
+	for _, cell := range cells {
+		n += len(cell.mailbox)
+	}
+	return n
+}
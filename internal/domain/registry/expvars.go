@@ -0,0 +1,47 @@
+package registry
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+)
+
+// hubExpvars holds the [LIGHTWEIGHT_METRICS] published through the standard expvar registry.
+type hubExpvars struct {
+	activeUsers    *expvar.Int
+	shardCells     *expvar.Map
+	totalBroadcast *expvar.Int
+}
+
+var (
+	expvarsOnce sync.Once
+	expvarsInst *hubExpvars
+)
+
+// publishedExpvars registers the variables exactly once per process.
+// [SAFETY] expvar.Publish panics on duplicate names, so multiple Hubs share one set.
+func publishedExpvars() *hubExpvars {
+	expvarsOnce.Do(func() {
+		expvarsInst = &hubExpvars{
+			activeUsers:    expvar.NewInt("im_delivery.hub.active_users"),
+			shardCells:     expvar.NewMap("im_delivery.hub.shard_cells"),
+			totalBroadcast: expvar.NewInt("im_delivery.hub.total_events_broadcast"),
+		}
+	})
+	return expvarsInst
+}
+
+// ExposeExpvars publishes Hub metrics under the "im_delivery" prefix.
+// They are served by the standard GET /debug/vars handler (importing expvar
+// registers it on http.DefaultServeMux) and need no external dependencies.
+// Values refresh on every eviction cycle and Broadcast call.
+func (h *Hub) ExposeExpvars() {
+	h.vars.Store(publishedExpvars())
+}
+
+// recordShardCells publishes the cell count of one shard (called under the shard lock).
+func (v *hubExpvars) recordShardCells(idx, cells int) {
+	gauge := new(expvar.Int)
+	gauge.Set(int64(cells))
+	v.shardCells.Set(strconv.Itoa(idx), gauge)
+}
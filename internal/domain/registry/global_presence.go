@@ -0,0 +1,173 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/presence"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	globalPresenceQueue   = 4096
+	globalPresenceTimeout = time.Second
+	globalLookupTimeout   = 250 * time.Millisecond
+	// globalPresenceFlush bounds how long Shutdown waits for queued offline writes.
+	globalPresenceFlush = 2 * time.Second
+)
+
+var globalPresenceDropped, _ = registryMeter.Int64Counter(
+	"im_delivery_global_presence_dropped_total",
+	metric.WithDescription("Presence writes shed because the store queue was full"),
+)
+
+// globalOp is one queued write; a nil refresh means a single-user transition.
+type globalOp struct {
+	userID  uuid.UUID
+	online  bool
+	refresh []uuid.UUID
+}
+
+// globalPresence mirrors this node's 0->1 / 1->0 session transitions into a
+// cluster-wide presence.Store.
+//
+// [DEGRADED_MODE]
+// Store I/O runs on one worker goroutine fed by a bounded queue, so the Hub never
+// waits on the network. When the store is unreachable writes are logged once and
+// shed; the heartbeat rewrites every local user on recovery, and IsConnectedGlobal
+// falls back to local-only answers in the meantime.
+type globalPresence struct {
+	store  presence.Store
+	nodeID string
+	ttl    time.Duration
+
+	ops       chan globalOp
+	quit      chan struct{}
+	done      chan struct{}
+	unhealthy atomic.Bool
+}
+
+func newGlobalPresence(store presence.Store, nodeID string, ttl time.Duration) *globalPresence {
+	return &globalPresence{
+		store:  store,
+		nodeID: nodeID,
+		ttl:    ttl,
+		ops:    make(chan globalOp, globalPresenceQueue),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// enqueue never blocks; a shed transition is repaired by the next heartbeat
+// (online) or by the key's TTL (offline).
+func (g *globalPresence) enqueue(op globalOp) {
+	select {
+	case g.ops <- op:
+	default:
+		globalPresenceDropped.Add(context.Background(), 1)
+	}
+}
+
+// run applies queued writes until close, then flushes what is left.
+func (g *globalPresence) run() {
+	defer close(g.done)
+	for {
+		select {
+		case op := <-g.ops:
+			g.apply(op)
+		case <-g.quit:
+			g.flush(time.Now().Add(globalPresenceFlush))
+			return
+		}
+	}
+}
+
+// flush applies what is queued until the deadline. An unreachable store is not
+// waited on: its keys expire by TTL anyway.
+func (g *globalPresence) flush(deadline time.Time) {
+	for !g.unhealthy.Load() && time.Now().Before(deadline) {
+		select {
+		case op := <-g.ops:
+			g.apply(op)
+		default:
+			return
+		}
+	}
+}
+
+// close stops the worker after it flushed the queue (bounded by globalPresenceFlush).
+func (g *globalPresence) close() {
+	close(g.quit)
+	<-g.done
+}
+
+func (g *globalPresence) apply(op globalOp) {
+	ctx, cancel := context.WithTimeout(context.Background(), globalPresenceTimeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case op.refresh != nil:
+		err = g.store.Refresh(ctx, op.refresh, g.nodeID, g.ttl)
+	case op.online:
+		err = g.store.Set(ctx, op.userID, g.nodeID, g.ttl)
+	default:
+		err = g.store.Delete(ctx, op.userID, g.nodeID)
+	}
+	g.report(err)
+}
+
+// report logs only health transitions, so an outage does not flood the log.
+func (g *globalPresence) report(err error) {
+	if err != nil {
+		if !g.unhealthy.Swap(true) {
+			slog.Warn("GLOBAL_PRESENCE_UNAVAILABLE: falling back to local-only presence", slog.Any("err", err))
+		}
+		return
+	}
+	if g.unhealthy.Swap(false) {
+		slog.Info("GLOBAL_PRESENCE_RECOVERED")
+	}
+}
+
+// heartbeat re-records every user holding a session on this node.
+// [EVICTOR_TICK] Called from runEvictor; only collects IDs, the I/O is queued.
+func (h *Hub) heartbeat() {
+	var users []uuid.UUID
+	for _, s := range h.shards {
+		s.RLock()
+		for id, cell := range s.cells {
+			if cell.sessionCount() > 0 {
+				users = append(users, id)
+			}
+		}
+		s.RUnlock()
+	}
+	if len(users) > 0 {
+		h.global.enqueue(globalOp{refresh: users})
+	}
+}
+
+// IsConnectedGlobal reports the node holding the user's sessions anywhere in the
+// cluster. Local sessions answer without a round-trip; without a store, or while it
+// is unreachable, the answer is local-only.
+func (h *Hub) IsConnectedGlobal(userID uuid.UUID) (nodeID string, ok bool) {
+	if h.ConnectedCount(userID) > 0 {
+		return h.config.nodeID, true
+	}
+	if h.global == nil {
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), globalLookupTimeout)
+	defer cancel()
+	nodeID, ok, err := h.global.store.Lookup(ctx, userID)
+	h.global.report(err)
+	if err != nil {
+		return "", false
+	}
+	return nodeID, ok
+}
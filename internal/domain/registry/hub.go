@@ -1,12 +1,16 @@
 package registry
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
 	"golang.org/x/sys/cpu"
 )
 
@@ -17,14 +21,44 @@ var _ Hubber = (*Hub)(nil)
 // It acts as the entry point for both incoming events (Broadcast) and
 // transport lifecycle management (Register/Unregister).
 type Hubber interface {
-	Broadcast(ev event.Eventer) bool
+	Broadcast(ev event.Eventer) BroadcastResult
+	// [ATOMIC_LOCALITY] Lookup and push under one shard read lock.
+	BroadcastIfConnected(ev event.Eventer) BroadcastResult
 	Register(conn Connector)
+	// [RESUME] Attach with an atomic replay of everything after the cursor (see replay.go).
+	RegisterResume(conn Connector, from event.Cursor)
+	// [LEGACY_RESUME] Like RegisterResume, but from the raw ID (or timestamp) of the last event received.
+	RegisterResumeID(conn Connector, lastEventID string, lastEventTS int64)
+	// [RESUME] Whether a resume from the cursor would be gap-free; false without a Cell.
+	CanResume(userID uuid.UUID, from event.Cursor) bool
 	Unregister(userID, connID uuid.UUID)
 	IsConnected(userID uuid.UUID) bool
+	// ConnectedCount is the number of sessions the user has attached to this node.
+	ConnectedCount(userID uuid.UUID) int
+	// ConnectedUsers counts users holding a Cell on this node.
+	ConnectedUsers() int
+	// Stats is a cheap operational snapshot; safe to poll every few seconds.
+	Stats() model.HubStats
+	// Sessions describes the user's connections attached to this node (see sessions.go).
+	Sessions(userID uuid.UUID) []SessionInfo
+	// Disconnect force-closes one (connID) or all (uuid.Nil) of the user's sessions.
+	Disconnect(userID, connID uuid.UUID, reason string) (int, error)
+	// [GLOBAL_PRESENCE] Node holding the user's sessions cluster-wide; local-only without a store.
+	IsConnectedGlobal(userID uuid.UUID) (nodeID string, ok bool)
+	// [GRACEFUL_DRAIN] Notify sessions and flush mailboxes ahead of Shutdown.
+	BeginDrain(deadline time.Time, notice DrainNotice)
+	Draining() bool
 	Shutdown()
+
+	// [EPHEMERAL_TOPICS] Connection-scoped subscriptions to arbitrary entity keys.
+	SubscribeTopic(conn Connector, key string, ttl time.Duration) error
+	UnsubscribeTopic(connID uuid.UUID, key string)
+	HasTopicSubscribers(key string) bool
+	BroadcastTopic(key string, ev event.Eventer) int
 }
 
-const shardCount = 256
+// defaultShardCount is used unless overridden by WithShardCount; must be a power of two.
+const defaultShardCount = 256
 
 // Hub implements [Hubber] using a SHARDED_ACTOR architecture.
 // This design eliminates global lock contention by partitioning the workload.
@@ -32,20 +66,40 @@ type Hub struct {
 	// [CONCURRENCY_STRATEGY] Array of independent shards.
 	// Each shard handles a subset of users based on their UUID.
 	shards    []*shard
+	shardMask uint32 // len(shards)-1; valid because the count is a power of two
+	topics    *topicIndex
 	config    hubConfig
+	vars      atomic.Pointer[hubExpvars]       // [OPTIONAL] Set by ExposeExpvars
+	stats     *hubCounters                     // [STATS] See stats.go
+	observer  atomic.Pointer[observerBox]      // [OPTIONAL] Set by SetDeliveryObserver
+	presence  atomic.Pointer[PresenceNotifier] // [OPTIONAL] Set by SetPresenceNotifier
+	global    *globalPresence                  // [OPTIONAL] Set by WithPresenceStore (see global_presence.go)
+	draining  atomic.Bool                      // [GRACEFUL_DRAIN] Set by BeginDrain (see drain.go)
 	stopCh    chan struct{}
 	closeOnce sync.Once
 }
 
 type hubConfig struct {
-	evictionInterval time.Duration
-	idleTimeout      time.Duration
-	mailboxSize      int
+	evictionInterval  time.Duration
+	idleTimeout       time.Duration
+	mailboxSize       int
+	maxTopicsPerConn  int
+	topicDefaultTTL   time.Duration
+	topicMaxTTL       time.Duration
+	forceCloseAfter   time.Duration
+	degradedThreshold uint64
+	shutdownDrain     time.Duration
+	maxSessions       int
+	replaySize        int
+	shardCount        int
+	lockProfiling     bool
+	contentionAlert   time.Duration
+	nodeID            string
 }
 
 // shard represents a logical partition of the user registry.
 type shard struct {
-	sync.RWMutex
+	shardMutex
 	// [REGISTRY] Map of UserID to their dedicated delivery Cell (Actor).
 	cells map[uuid.UUID]*Cell
 	// Modern CPUs load data into L1/L2 caches in fixed-size blocks (Cache Lines),
@@ -66,33 +120,84 @@ type shard struct {
 // NewHub initializes the registry with [SHARDED_LOCKING] and starts the evictor.
 func NewHub(opts ...Option) *Hub {
 	h := &Hub{
-		shards: make([]*shard, shardCount),
+		topics: newTopicIndex(),
 		config: hubConfig{
-			evictionInterval: 1 * time.Minute,
-			idleTimeout:      10 * time.Minute,
-			mailboxSize:      1024,
+			evictionInterval:  1 * time.Minute,
+			idleTimeout:       10 * time.Minute,
+			mailboxSize:       1024,
+			maxTopicsPerConn:  32,
+			topicDefaultTTL:   5 * time.Minute,
+			topicMaxTTL:       30 * time.Minute,
+			replaySize:        256,
+			degradedThreshold: 100,
+			shutdownDrain:     2 * time.Second,
+			shardCount:        defaultShardCount,
+			contentionAlert:   time.Millisecond,
 		},
+		stats:  newHubCounters(),
 		stopCh: make(chan struct{}),
 	}
 
-	// [MEMORY_ALLOCATION] Pre-allocate all shards to prevent runtime pointer nil-checks.
-	for i := range shardCount {
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	// [FAIL_FAST] A non power-of-two count would break the routing mask.
+	n := h.config.shardCount
+	if !isPowerOfTwo(n) {
+		panic(fmt.Sprintf("registry: shard count must be a positive power of two, got %d", n))
+	}
+
+	// [MEMORY_ALLOCATION] Pre-allocate all shards (sized by options) to prevent runtime pointer nil-checks.
+	h.shards = make([]*shard, n)
+	h.shardMask = uint32(n - 1)
+	for i := range h.shards {
 		h.shards[i] = &shard{cells: make(map[uuid.UUID]*Cell)}
 	}
 
-	for _, opt := range opts {
-		opt(h)
+	// [PROFILING] Configured after options so the whole registry shares one setting.
+	if h.config.lockProfiling && !lockProfilingBuilt {
+		slog.Warn("HUB_LOCK_PROFILING_UNAVAILABLE", "hint", "rebuild with -tags lockprof")
+	}
+	for i, s := range h.shards {
+		s.shardID = i
+		s.profiled = h.config.lockProfiling
+		s.threshold = h.config.contentionAlert
+	}
+
+	if h.global != nil {
+		spawn(h.global.run)
 	}
 
 	// [BACKGROUND_PROCESS] Start the resource reclamation routine.
-	go h.runEvictor()
+	spawn(h.runEvictor)
 	return h
 }
 
-// getShard maps a UserID to a specific shard using the first byte of the UUID.
+// getShard maps a UserID to a specific shard by hashing all 16 bytes of the UUID.
+// The leading bytes of a UUIDv7 are a timestamp, so routing on them alone would
+// crowd users created around the same time into a few shards.
 // [LOCK_FREE_ROUTING] This operation requires no locks.
 func (h *Hub) getShard(userID uuid.UUID) *shard {
-	return h.shards[userID[0]]
+	return h.shards[shardHash(userID)&h.shardMask]
+}
+
+// shardHash is FNV-1a (32-bit) over the UUID, inlined to stay allocation-free.
+func shardHash(id uuid.UUID) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for _, b := range id {
+		hash ^= uint32(b)
+		hash *= prime32
+	}
+	return hash
+}
+
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
 }
 
 // IsConnected checks if a user has an active [CELL] in the registry.
@@ -104,8 +209,33 @@ func (h *Hub) IsConnected(userID uuid.UUID) bool {
 	return ok
 }
 
+// ConnectedCount reports how many sessions the user has attached to this node.
+// Unlike IsConnected it is zero for a Cell that lingers, session-less, until eviction.
+func (h *Hub) ConnectedCount(userID uuid.UUID) int {
+	s := h.getShard(userID)
+	s.RLock()
+	cell, ok := s.cells[userID]
+	s.RUnlock()
+	if !ok {
+		return 0
+	}
+	return cell.sessionCount()
+}
+
+// ConnectedUsers counts Cells across all shards.
+// [COLD_PATH] Takes every shard RLock in turn; meant for disconnect-time decisions, not per event.
+func (h *Hub) ConnectedUsers() int {
+	total := 0
+	for _, s := range h.shards {
+		s.RLock()
+		total += len(s.cells)
+		s.RUnlock()
+	}
+	return total
+}
+
 // Broadcast dispatches an event to the specific user's [MAILBOX].
-func (h *Hub) Broadcast(ev event.Eventer) bool {
+func (h *Hub) Broadcast(ev event.Eventer) BroadcastResult {
 	userID := ev.GetUserID()
 	s := h.getShard(userID)
 
@@ -114,29 +244,171 @@ func (h *Hub) Broadcast(ev event.Eventer) bool {
 	cell, ok := s.cells[userID]
 	s.RUnlock()
 
+	h.stats.broadcast.Add(1)
+	if vars := h.vars.Load(); vars != nil {
+		vars.totalBroadcast.Add(1)
+	}
+
+	if !ok {
+		h.observe(ev, uuid.Nil, OutcomeNotConnected)
+		return BroadcastResult{Reason: ReasonNotRegistered}
+	}
+
+	sessions := cell.sessionCount()
+	if !cell.Push(ev) {
+		h.observe(ev, uuid.Nil, OutcomeMailboxFull)
+		return BroadcastResult{UserWasConnected: true, Reason: ReasonMailboxFull, Sessions: sessions}
+	}
+	h.observe(ev, uuid.Nil, OutcomeQueued)
+	return BroadcastResult{UserWasConnected: true, Queued: true, Reason: ReasonAccepted, Sessions: sessions}
+}
+
+// ErrMailboxFull is returned by callers that surface a ReasonMailboxFull drop as an error.
+var ErrMailboxFull = errors.New("registry: user mailbox is full")
+
+// DeliveryReason says why the Hub did or did not accept an event.
+type DeliveryReason int8
+
+//go:generate stringer -type=DeliveryReason -trimprefix=Reason
+const (
+	ReasonNotRegistered DeliveryReason = iota + 1 // No Cell on this node for the user
+	ReasonMailboxFull                             // Cell exists but its mailbox is saturated
+	ReasonAccepted                                // Queued into the Cell's mailbox
+)
+
+// BroadcastResult reports what Broadcast/BroadcastIfConnected did with an event.
+type BroadcastResult struct {
+	// UserWasConnected is false when no Cell existed for the recipient.
+	UserWasConnected bool
+	// Queued is true when the event was accepted into the mailbox.
+	Queued bool
+	// Reason distinguishes a miss from a drop; see DeliveryReason.
+	Reason DeliveryReason
+	// Sessions attached to the Cell at push time (0 when not registered).
+	Sessions int
+}
+
+// Saturated reports a drop caused by [BACKPRESSURE], as opposed to a miss.
+func (r BroadcastResult) Saturated() bool {
+	return r.Reason == ReasonMailboxFull
+}
+
+// BroadcastIfConnected pushes an event only if the recipient has a Cell on this node.
+// [TOCTOU_FREE] The lookup and the non-blocking push share one shard RLock, so the
+// Cell cannot be evicted between the locality check and the push.
+func (h *Hub) BroadcastIfConnected(ev event.Eventer) BroadcastResult {
+	userID := ev.GetUserID()
+	s := h.getShard(userID)
+
+	h.stats.broadcast.Add(1)
+	if vars := h.vars.Load(); vars != nil {
+		vars.totalBroadcast.Add(1)
+	}
+
+	res := BroadcastResult{Reason: ReasonNotRegistered}
+	s.RLock()
+	cell, ok := s.cells[userID]
 	if ok {
-		return cell.Push(ev)
+		res.UserWasConnected = true
+		res.Sessions = cell.sessionCount()
+		res.Queued = cell.Push(ev)
+	}
+	s.RUnlock()
+
+	switch {
+	case !ok:
+		h.observe(ev, uuid.Nil, OutcomeNotConnected)
+	case !res.Queued:
+		res.Reason = ReasonMailboxFull
+		h.observe(ev, uuid.Nil, OutcomeMailboxFull)
+	default:
+		res.Reason = ReasonAccepted
+		h.observe(ev, uuid.Nil, OutcomeQueued)
 	}
-	return false
+	return res
 }
 
 // Register performs an [IDEMPOTENT] registration of a new connection.
 // It creates a new Cell (Actor) if the user is connecting for the first time.
 func (h *Hub) Register(conn Connector) {
+	// [SESSION_ATTACH] Delegate session management to the Cell.
+	h.attach(conn, (*Cell).Attach)
+}
+
+// RegisterResume registers a connection that continues a previous stream.
+// The replay and the switch to live delivery happen atomically on the Cell loop.
+func (h *Hub) RegisterResume(conn Connector, from event.Cursor) {
+	h.attach(conn, func(cell *Cell, conn Connector) bool {
+		return cell.AttachResume(conn, from)
+	})
+}
+
+// RegisterResumeID resumes from a raw event ID, resolved against the Cell's replay ring,
+// falling back to the event's timestamp when the ID is empty or no longer retained.
+// A position that cannot be resolved replays everything behind a ReplayGap.
+func (h *Hub) RegisterResumeID(conn Connector, lastEventID string, lastEventTS int64) {
+	h.attach(conn, func(cell *Cell, conn Connector) bool {
+		return cell.AttachResumeID(conn, lastEventID, lastEventTS)
+	})
+}
+
+// CanResume reports whether the user's Cell still holds everything after the cursor.
+// A missing Cell means a new epoch on the next attach, so nothing can be resumed.
+func (h *Hub) CanResume(userID uuid.UUID, from event.Cursor) bool {
+	s := h.getShard(userID)
+
+	s.RLock()
+	cell, ok := s.cells[userID]
+	s.RUnlock()
+	return ok && cell.CanResume(from)
+}
+
+// attach binds conn to a live Cell.
+//
+// [EVICTION_RACE] prepare releases the shard lock before the attach, so the evictor
+// may stop that very Cell in between. A stopped Cell refuses the attach, and the
+// next prepare (under the shard write lock) finds the map entry gone and creates a
+// fresh Cell, which is not idle and therefore cannot be evicted again right away.
+func (h *Hub) attach(conn Connector, fn func(*Cell, Connector) bool) {
+	for {
+		if fn(h.prepare(conn), conn) {
+			return
+		}
+		slog.Debug("REGISTER_RETRY: cell_stopped", "user_id", conn.GetUserID())
+	}
+}
+
+// prepare configures the connector and returns the user's Cell, creating it if needed.
+func (h *Hub) prepare(conn Connector) *Cell {
+	// [SELF_HEALING] Propagate the escalation window to our own connector implementation.
+	if c, ok := conn.(interface{ setForceCloseAfter(time.Duration) }); ok {
+		c.setForceCloseAfter(h.config.forceCloseAfter)
+	}
+	if c, ok := conn.(interface{ setDegradedThreshold(uint64) }); ok {
+		c.setDegradedThreshold(h.config.degradedThreshold)
+	}
+
 	userID := conn.GetUserID()
 	s := h.getShard(userID)
 
 	s.Lock()
+	defer s.Unlock()
+
 	cell, ok := s.cells[userID]
+	if ok && cell.isStopped() {
+		// [LIVENESS] Never hand out a dead actor, even if it is still indexed.
+		ok = false
+		h.stats.cells.Add(-1)
+	}
 	if !ok {
 		// [ACTOR_CREATION] Initialize a new isolated delivery unit for the user.
-		cell = NewCell(userID, h.config.mailboxSize)
+		cell = NewCell(userID, h.config.mailboxSize, h.config.replaySize, h.observe, h.retired, h.stats)
+		cell.maxSessions = h.config.maxSessions
+		cell.presence = h.presenceChanged
 		s.cells[userID] = cell
+		h.stats.cells.Add(1)
 	}
-	s.Unlock()
-
-	// [SESSION_ATTACH] Delegate session management to the Cell.
-	cell.Attach(conn)
+	return cell
 }
 
 // Unregister removes a specific connection from the user's [CELL].
@@ -146,8 +418,13 @@ func (h *Hub) Unregister(userID, connID uuid.UUID) {
 	cell, ok := s.cells[userID]
 	s.RUnlock()
 
+	// [TEARDOWN] The retirement hook drops topic subscriptions; without a Cell (already
+	// evicted) there is nothing to retire, but stray subscriptions are still purged.
+	if !ok || !cell.retire(connID, RetireUnregister) {
+		h.topics.dropConn(connID)
+	}
 	if ok {
-		cell.Detach(connID)
+		cell.touch()
 	}
 }
 
@@ -156,39 +433,101 @@ func (h *Hub) runEvictor() {
 	ticker := time.NewTicker(h.config.evictionInterval)
 	defer ticker.Stop()
 
+	// [HEARTBEAT] Global presence keys are refreshed well inside their TTL; nil blocks forever.
+	var heartbeat <-chan time.Time
+	if h.global != nil {
+		hb := time.NewTicker(h.global.ttl / 3)
+		defer hb.Stop()
+		heartbeat = hb.C
+	}
+
 	for {
 		select {
 		case <-h.stopCh:
 			return
 		case <-ticker.C:
 			h.performEviction()
+		case <-heartbeat:
+			h.heartbeat()
 		}
 	}
 }
 
 // performEviction executes the [RECLAMATION] logic shard-by-shard.
 func (h *Hub) performEviction() {
-	reaped := 0
-	for i := range shardCount {
-		s := h.shards[i]
+	vars := h.vars.Load()
+	active := 0
+	var orphans []Connector
+	var victims []*Cell
+	for i, s := range h.shards {
 
 		// [GRANULAR_LOCKING] Lock only one shard at a time to keep others responsive.
 		s.Lock()
 		for id, cell := range s.cells {
-			if cell.IsIdle(h.config.idleTimeout) {
-				cell.Stop() // Terminate Actor goroutine
+			// [ORPHAN_RECLAMATION] Dead sessions that never unregistered would keep the Cell alive forever.
+			orphans = append(orphans, cell.reapOrphans()...)
+
+			// [DEFERRED_STOP] Claimed Cells refuse Attach from here on, so they can be
+			// unlinked now and stopped after the shard lock is released.
+			if cell.claimIdle(h.config.idleTimeout) {
 				delete(s.cells, id)
-				reaped++
+				h.stats.cells.Add(-1)
+				victims = append(victims, cell)
 			}
 		}
+		active += len(s.cells)
+		if vars != nil {
+			vars.recordShardCells(i, len(s.cells))
+		}
 		s.Unlock()
 	}
 
+	for _, cell := range victims {
+		cell.Stop() // Terminate Actor goroutine
+	}
+	reaped := len(victims)
+
+	if vars != nil {
+		vars.activeUsers.Set(int64(active))
+	}
+
+	for _, conn := range orphans {
+		h.reportOrphan(conn)
+	}
+
 	if reaped > 0 {
-		slog.Info("RESOURCE_RECLAIMED", "count", reaped, "shard_total", shardCount)
+		slog.Info("RESOURCE_RECLAIMED", "count", reaped, "shard_total", len(h.shards))
+	}
+
+	if expired := h.topics.purgeExpired(); expired > 0 {
+		slog.Debug("TOPIC_SUBSCRIPTIONS_EXPIRED", "count", expired)
 	}
 }
 
+// PurgeCachedBefore invalidates replay-buffer caches built by a marshaller schema older
+// than version and returns how many events were purged.
+// [SNAPSHOT] Cells are collected per shard under a read lock and purged outside it,
+// so a busy Cell loop never stalls registration on its shard.
+func (h *Hub) PurgeCachedBefore(version uint64) int {
+	total := 0
+	for _, s := range h.shards {
+
+		s.RLock()
+		cells := make([]*Cell, 0, len(s.cells))
+		for _, cell := range s.cells {
+			cells = append(cells, cell)
+		}
+		s.RUnlock()
+
+		for _, cell := range cells {
+			if n := cell.purgeCachedBefore(version); n > 0 {
+				total += n
+			}
+		}
+	}
+	return total
+}
+
 // Shutdown ensures a [GRACEFUL_EXIT] by stopping all background actors exactly once.
 func (h *Hub) Shutdown() {
 	// [IDEMPOTENCY_GUARANTEE]
@@ -200,18 +539,20 @@ func (h *Hub) Shutdown() {
 		close(h.stopCh)
 
 		// 2. [SHARD_DRAINING]
-		// Iterate through all shards to stop individual User Cells.
-		for i := range shardCount {
-			s := h.shards[i]
+		// Iterate through all shards to signal individual User Cells. Each loop delivers
+		// what is left in its mailbox, in parallel, until the shared deadline.
+		deadline := time.Now().Add(h.config.shutdownDrain)
+		var stopping []*Cell
+		for _, s := range h.shards {
 
 			s.Lock()
 			for _, cell := range s.cells {
-				// [CASCADE_STOP]
-				// Each Cell will stop its event loop and close its connectors,
-				// triggering final delivery events to the clients.
-				cell.Stop()
+				cell.beginStop(deadline)
+				stopping = append(stopping, cell)
 			}
 
+			h.stats.cells.Add(-int64(len(s.cells)))
+
 			// 3. [MEMORY_MANAGEMENT]
 			// Explicitly clear the map to release references and assist the
 			// Garbage Collector in reclaiming memory for high-density sessions.
@@ -219,8 +560,22 @@ func (h *Hub) Shutdown() {
 			s.Unlock()
 		}
 
+		// [CASCADE_STOP]
+		// Only after the drain are connectors closed, so transports can flush the
+		// final events (and their termination notices) to the clients.
+		for _, cell := range stopping {
+			cell.finishStop(deadline)
+		}
+
+		h.topics.reset()
+
+		// [GLOBAL_PRESENCE] Flush the offline writes queued by the cascade above.
+		if h.global != nil {
+			h.global.close()
+		}
+
 		slog.Info("HUB_SHUTDOWN_COMPLETE",
-			slog.Int("shards_processed", shardCount),
+			slog.Int("shards_processed", len(h.shards)),
 			slog.String("status", "graceful_drain_finished"),
 		)
 	})
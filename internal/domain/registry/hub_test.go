@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// TestRegisterRacingEviction interleaves Register with forced eviction and checks that
+// every registered connection still receives a broadcast afterwards.
+func TestRegisterRacingEviction(t *testing.T) {
+	tests := []struct {
+		name       string
+		iterations int
+		shards     int
+		evictors   int
+	}{
+		{name: "single shard", iterations: 2000, shards: 1, evictors: 1},
+		{name: "competing evictors", iterations: 1000, shards: 4, evictors: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every Cell without sessions is idle, so each eviction pass races the attach.
+			hub := NewHub(WithShardCount(tt.shards), WithIdleTimeout(-time.Hour), WithEvictionInterval(time.Hour))
+			t.Cleanup(hub.Shutdown)
+
+			for range tt.iterations {
+				userID := uuid.New()
+				conn := NewConnector(context.Background(), userID, 64, ConnectMetadata{})
+
+				var wg sync.WaitGroup
+				wg.Go(func() { hub.Register(conn) })
+				for range tt.evictors {
+					wg.Go(hub.performEviction)
+				}
+				wg.Wait()
+
+				ev := event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil)
+				if res := hub.Broadcast(ev); !res.Queued {
+					t.Fatalf("broadcast not queued: %+v", res)
+				}
+				select {
+				case got := <-conn.Recv():
+					if got.GetID() != ev.GetID() {
+						t.Fatalf("got event %s, want %s", got.GetID(), ev.GetID())
+					}
+				case <-time.After(time.Second):
+					t.Fatal("registered connection never received the broadcast")
+				}
+				hub.Unregister(userID, conn.GetID())
+				conn.Release()
+			}
+		})
+	}
+}
+
+func TestPerformEvictionSkipsBusyCells(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions int
+		want     int // Cells left after eviction
+	}{
+		{name: "idle cell reclaimed", sessions: 0, want: 0},
+		{name: "cell with a session kept", sessions: 1, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(WithShardCount(1), WithIdleTimeout(-time.Hour), WithEvictionInterval(time.Hour))
+			t.Cleanup(hub.Shutdown)
+
+			userID := uuid.New()
+			conn := NewConnector(context.Background(), userID, 64, ConnectMetadata{})
+			hub.Register(conn)
+			if tt.sessions == 0 {
+				hub.Unregister(userID, conn.GetID())
+			}
+
+			hub.performEviction()
+			if got := hub.ConnectedUsers(); got != tt.want {
+				t.Fatalf("cells: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
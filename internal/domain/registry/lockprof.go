@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var registryMeter = otel.Meter("github.com/webitel/im-delivery-service/internal/domain/registry")
+
+var shardLockContention, _ = registryMeter.Int64Counter(
+	"im_delivery_hub_shard_lock_contention_total",
+	metric.WithDescription("Shard lock acquisitions that waited longer than the contention threshold"),
+)
+
+// shardMutex is the shard's RWMutex with optional [CONTENTION_PROFILING].
+//
+// [ZERO_COST] It is a concrete type (no interface indirection), and profiling is
+// compiled in only with `-tags lockprof` (see lockprof_on.go). In default builds
+// lockProfilingBuilt is a false constant, the profiled branches are dead code and
+// every call is the plain RWMutex call. In profiling builds WithLockProfiling still
+// selects which Hubs record, and the uncontended path is a successful TryLock that
+// never reads the clock.
+type shardMutex struct {
+	mu        sync.RWMutex
+	profiled  bool
+	shardID   int
+	threshold time.Duration
+}
+
+func (m *shardMutex) Lock() {
+	if lockProfilingBuilt && m.profiled {
+		m.lockProfiled()
+		return
+	}
+	m.mu.Lock()
+}
+
+func (m *shardMutex) lockProfiled() {
+	if m.mu.TryLock() {
+		return
+	}
+	start := time.Now()
+	m.mu.Lock()
+	m.record("write", time.Since(start))
+}
+
+func (m *shardMutex) Unlock() { m.mu.Unlock() }
+
+func (m *shardMutex) RLock() {
+	if lockProfilingBuilt && m.profiled {
+		m.rlockProfiled()
+		return
+	}
+	m.mu.RLock()
+}
+
+func (m *shardMutex) rlockProfiled() {
+	if m.mu.TryRLock() {
+		return
+	}
+	start := time.Now()
+	m.mu.RLock()
+	m.record("read", time.Since(start))
+}
+
+func (m *shardMutex) RUnlock() { m.mu.RUnlock() }
+
+// record reports a wait that crossed the alert threshold.
+func (m *shardMutex) record(mode string, wait time.Duration) {
+	if wait < m.threshold {
+		return
+	}
+	shardLockContention.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.Int("shard_id", m.shardID),
+		attribute.String("mode", mode),
+	))
+	slog.Warn("HUB_SHARD_LOCK_CONTENTION",
+		"shard_id", m.shardID,
+		"mode", mode,
+		"wait", wait,
+	)
+}
@@ -0,0 +1,7 @@
+//go:build !lockprof
+
+package registry
+
+// lockProfilingBuilt is false in default builds: shard locks are plain RWMutex calls
+// and WithLockProfiling has no effect. Build with `-tags lockprof` to profile.
+const lockProfilingBuilt = false
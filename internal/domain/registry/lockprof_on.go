@@ -0,0 +1,7 @@
+//go:build lockprof
+
+package registry
+
+// lockProfilingBuilt compiles the [CONTENTION_PROFILING] paths in; WithLockProfiling
+// then turns them on per Hub.
+const lockProfilingBuilt = true
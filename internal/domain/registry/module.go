@@ -7,15 +7,28 @@ import (
 	"go.uber.org/fx"
 )
 
+// hubParams collects options contributed by infrastructure modules (e.g. a presence store).
+type hubParams struct {
+	fx.In
+
+	Options []Option `group:"hub_options"`
+}
+
 var Module = fx.Module("registry",
 	fx.Provide(
 		// [CLEAN_INJECTION] Configure Hub using Functional Options
-		func() *Hub {
-			return NewHub(
-				WithEvictionInterval(15*time.Minute),
-				WithIdleTimeout(30*time.Minute),
+		func(p hubParams) *Hub {
+			h := NewHub(append([]Option{
+				WithEvictionInterval(15 * time.Minute),
+				WithIdleTimeout(30 * time.Minute),
 				WithMailboxSize(2048),
-			)
+				WithReplayBufferSize(256),
+				WithMaxSessionsPerUser(8),
+				WithForceCloseAfter(30 * time.Second),
+			}, p.Options...)...)
+			// [OBSERVABILITY] Zero-dependency metrics for deployments without Prometheus.
+			h.ExposeExpvars()
+			return h
 		},
 		fx.Annotate(
 			func(h *Hub) Hubber { return h },
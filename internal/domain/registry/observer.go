@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// DeliveryOutcome classifies a single step of an event's path through the Hub.
+type DeliveryOutcome int8
+
+//go:generate stringer -type=DeliveryOutcome -trimprefix=Outcome
+const (
+	OutcomeQueued       DeliveryOutcome = iota + 1 // Accepted into the user's mailbox
+	OutcomeNotConnected                            // No Cell on this node for the user
+	OutcomeMailboxFull                             // Rejected by mailbox backpressure
+	OutcomeDelivered                               // Enqueued into a session buffer
+	OutcomeDropped                                 // Session buffer saturated; event shed
+	OutcomeSkipped                                 // Session was closing; low or normal priority event skipped
+	OutcomeFiltered                                // Session's kind filter excludes the event
+)
+
+// DeliveryObserver receives delivery outcomes for diagnostics.
+// connID is uuid.Nil for Hub-level steps that precede session fan-out.
+//
+// [HOT_PATH] Implementations are called from Cell loops and must not block.
+type DeliveryObserver interface {
+	ObserveDelivery(ev event.Eventer, connID uuid.UUID, outcome DeliveryOutcome)
+}
+
+// observerBox lets the observer be swapped atomically after the Hub is built.
+type observerBox struct{ obs DeliveryObserver }
+
+// SetDeliveryObserver installs (or, with nil, removes) the delivery observer.
+func (h *Hub) SetDeliveryObserver(obs DeliveryObserver) {
+	if obs == nil {
+		h.observer.Store(nil)
+		return
+	}
+	h.observer.Store(&observerBox{obs: obs})
+}
+
+// multiObserver fans outcomes out to several observers in registration order.
+type multiObserver []DeliveryObserver
+
+func (m multiObserver) ObserveDelivery(ev event.Eventer, connID uuid.UUID, outcome DeliveryOutcome) {
+	for _, obs := range m {
+		obs.ObserveDelivery(ev, connID, outcome)
+	}
+}
+
+// AddDeliveryObserver installs obs alongside any observer already present.
+// [COPY_ON_WRITE] The hot path keeps a single atomic load; only registration allocates.
+func (h *Hub) AddDeliveryObserver(obs DeliveryObserver) {
+	for {
+		cur := h.observer.Load()
+		next := &observerBox{obs: obs}
+		if cur != nil {
+			chain := multiObserver{cur.obs, obs}
+			if m, ok := cur.obs.(multiObserver); ok {
+				chain = append(append(multiObserver{}, m...), obs)
+			}
+			next.obs = chain
+		}
+		if h.observer.CompareAndSwap(cur, next) {
+			return
+		}
+	}
+}
+
+// observe reports an outcome if an observer is installed.
+func (h *Hub) observe(ev event.Eventer, connID uuid.UUID, outcome DeliveryOutcome) {
+	if box := h.observer.Load(); box != nil {
+		box.obs.ObserveDelivery(ev, connID, outcome)
+	}
+}
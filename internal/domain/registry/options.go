@@ -1,6 +1,10 @@
 package registry
 
-import "time"
+import (
+	"time"
+
+	"github.com/webitel/im-delivery-service/internal/domain/presence"
+)
 
 // Option defines a functional configuration type for the Hub.
 type Option func(*Hub)
@@ -28,3 +32,95 @@ func WithMailboxSize(size int) Option {
 		h.config.mailboxSize = size
 	}
 }
+
+// WithShardCount sets how many [SHARDED_LOCKING] partitions the registry uses.
+// n must be a positive power of two; NewHub panics otherwise. Default is 256.
+func WithShardCount(n int) Option {
+	return func(h *Hub) {
+		h.config.shardCount = n
+	}
+}
+
+// WithReplayBufferSize sets how many recent events each Cell retains for [RESUME].
+// Zero disables replay; resuming clients then always receive a ReplayGap.
+func WithReplayBufferSize(n int) Option {
+	return func(h *Hub) {
+		h.config.replaySize = n
+	}
+}
+
+// WithMaxSessionsPerUser caps concurrent sessions per user. Attaching beyond the cap
+// kicks the least-recently-active session with a session_limit_exceeded notice.
+// Zero (the default) means unlimited.
+func WithMaxSessionsPerUser(n int) Option {
+	return func(h *Hub) {
+		h.config.maxSessions = n
+	}
+}
+
+// WithForceCloseAfter enables [SELF_HEALING] of stalled sessions: a connection whose
+// buffer keeps dropping events for longer than d is closed. Zero disables it.
+func WithForceCloseAfter(d time.Duration) Option {
+	return func(h *Hub) {
+		h.config.forceCloseAfter = d
+	}
+}
+
+// WithDegradedThreshold sets how many shed events a connection tolerates before the
+// client is sent a single DeliveryDegraded notice asking it to resync. Zero disables it.
+func WithDegradedThreshold(n uint64) Option {
+	return func(h *Hub) {
+		h.config.degradedThreshold = n
+	}
+}
+
+// WithShutdownDrain bounds how long Shutdown lets Cells deliver events still queued
+// in their mailboxes before the connectors are closed. Zero skips the drain.
+func WithShutdownDrain(d time.Duration) Option {
+	return func(h *Hub) {
+		h.config.shutdownDrain = d
+	}
+}
+
+// WithLockProfiling enables shard lock [CONTENTION_PROFILING]. Off by default, and
+// only honoured by binaries built with `-tags lockprof`; other builds compile it out.
+func WithLockProfiling(enabled bool) Option {
+	return func(h *Hub) {
+		h.config.lockProfiling = enabled
+	}
+}
+
+// WithContentionAlertThreshold sets the lock wait above which contention is reported.
+func WithContentionAlertThreshold(d time.Duration) Option {
+	return func(h *Hub) {
+		h.config.contentionAlert = d
+	}
+}
+
+// WithMaxTopicsPerConnection bounds how many [EPHEMERAL_TOPICS] a single
+// connection may subscribe to at once. Zero disables the bound.
+func WithMaxTopicsPerConnection(n int) Option {
+	return func(h *Hub) {
+		h.config.maxTopicsPerConn = n
+	}
+}
+
+// WithTopicTTL sets the default and maximum lifetime of a topic subscription.
+func WithTopicTTL(def, maxTTL time.Duration) Option {
+	return func(h *Hub) {
+		h.config.topicDefaultTTL = def
+		h.config.topicMaxTTL = maxTTL
+	}
+}
+
+// WithPresenceStore mirrors this node's sessions into a cluster-wide [GLOBAL_PRESENCE]
+// registry under nodeID. Keys live for ttl and are refreshed every ttl/3.
+func WithPresenceStore(store presence.Store, nodeID string, ttl time.Duration) Option {
+	return func(h *Hub) {
+		if ttl <= 0 {
+			ttl = 90 * time.Second
+		}
+		h.config.nodeID = nodeID
+		h.global = newGlobalPresence(store, nodeID, ttl)
+	}
+}
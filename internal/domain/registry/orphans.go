@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var orphanSessions, _ = registryMeter.Int64Counter(
+	"im_delivery_orphan_sessions_total",
+	metric.WithDescription("Sessions reclaimed by the evictor because their transport never unregistered them"),
+)
+
+// describer exposes connector metadata for orphan reports without widening Connector.
+type describer interface {
+	describe() (createdAt time.Time, md ConnectMetadata)
+}
+
+func (c *connect) describe() (time.Time, ConnectMetadata) { return c.createdAt, c.metadata }
+
+// reapOrphans detaches sessions whose connector is closed although Unregister never ran.
+//
+// [TWO_STRIKES] A closing session is reaped only if it is still attached on the next
+// eviction cycle, so a handler that is merely mid-cleanup is never counted as an orphan.
+// Healthy sessions are never touched: only a cancelled connector context qualifies.
+func (c *Cell) reapOrphans() []Connector {
+	c.mu.Lock()
+	var candidates []Connector
+	for id, conn := range c.sessions {
+		if !conn.IsClosing() {
+			delete(c.suspects, id)
+			continue
+		}
+		if _, seen := c.suspects[id]; !seen {
+			if c.suspects == nil {
+				c.suspects = make(map[uuid.UUID]struct{})
+			}
+			c.suspects[id] = struct{}{}
+			continue
+		}
+
+		candidates = append(candidates, conn)
+	}
+	c.mu.Unlock()
+
+	// [TEARDOWN] Retired through the common protocol; a racing Unregister may win instead.
+	// [NO_TOUCH] The idle clock keeps running from the last real activity.
+	var reaped []Connector
+	for _, conn := range candidates {
+		if c.retire(conn.GetID(), RetireOrphan) {
+			reaped = append(reaped, conn)
+		}
+	}
+	return reaped
+}
+
+// reportOrphan records a reaped connector for follow-up.
+// [OWNERSHIP] Release stays with the (possibly dead) transport; an unreleased
+// connector is simply garbage collected.
+func (h *Hub) reportOrphan(conn Connector) {
+	orphanSessions.Add(context.Background(), 1)
+
+	attrs := []any{
+		slog.String("user_id", conn.GetUserID().String()),
+		slog.String("conn_id", conn.GetID().String()),
+	}
+	if d, ok := conn.(describer); ok {
+		createdAt, md := d.describe()
+		attrs = append(attrs,
+			slog.Duration("age", time.Since(createdAt)),
+			slog.String("platform", md.Platform),
+			slog.String("client_version", md.Version),
+			slog.String("device_id", md.DeviceID),
+			slog.String("remote_ip", md.RemoteIP),
+		)
+	}
+	slog.Warn("ORPHAN_SESSION_REAPED", attrs...)
+}
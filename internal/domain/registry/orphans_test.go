@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// TestOrphanReclamation simulates transports that abandon their connector without
+// calling Unregister, and a healthy long-lived session next to them.
+func TestOrphanReclamation(t *testing.T) {
+	tests := []struct {
+		name        string
+		abandon     func(conn Connector, cancel context.CancelFunc) // Before the first cycle; nil keeps the session healthy
+		between     func(hub *Hub, conn Connector)                  // Between the first and second cycle
+		cycles      int
+		wantOrphans int64
+		wantCells   int // Cells left once the cycles ran
+	}{
+		{
+			name:        "stream context cancelled, defer never ran",
+			abandon:     func(_ Connector, cancel context.CancelFunc) { cancel() },
+			cycles:      2,
+			wantOrphans: 1,
+		},
+		{
+			name:        "connector closed, never unregistered",
+			abandon:     func(conn Connector, _ context.CancelFunc) { conn.Close() },
+			cycles:      2,
+			wantOrphans: 1,
+		},
+		{
+			name:    "handler unregisters while the evictor watches",
+			abandon: func(_ Connector, cancel context.CancelFunc) { cancel() },
+			between: func(hub *Hub, conn Connector) { hub.Unregister(conn.GetUserID(), conn.GetID()) },
+			cycles:  2,
+		},
+		{
+			name:      "healthy long-lived session",
+			cycles:    50,
+			wantCells: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := counterTotal(t, "im_delivery_orphan_sessions_total")
+			synctest.Test(t, func(t *testing.T) {
+				hub := NewHub(WithShardCount(1), WithEvictionInterval(time.Hour), WithIdleTimeout(time.Second))
+				defer hub.Shutdown()
+
+				ctx, cancel := context.WithCancel(t.Context())
+				defer cancel()
+				userID := uuid.New()
+				conn := NewConnector(ctx, userID, 16, ConnectMetadata{Platform: "ios"})
+				defer conn.Release()
+				hub.Register(conn)
+
+				if tt.abandon != nil {
+					tt.abandon(conn, cancel)
+				}
+				for cycle := 1; cycle <= tt.cycles; cycle++ {
+					time.Sleep(2 * time.Second) // Past the idle timeout
+					hub.performEviction()
+
+					// [TWO_STRIKES] A closing session survives the cycle that first sees it.
+					if cycle == 1 {
+						if got := hub.ConnectedCount(userID); got != 1 {
+							t.Fatalf("first cycle left %d sessions, want 1", got)
+						}
+						if tt.between != nil {
+							tt.between(hub, conn)
+						}
+					}
+				}
+
+				wantSessions := 0
+				if tt.abandon == nil {
+					wantSessions = 1
+				}
+				if got := hub.ConnectedCount(userID); got != wantSessions {
+					t.Fatalf("sessions after %d cycles: got %d, want %d", tt.cycles, got, wantSessions)
+				}
+				// [FAST_RECLAMATION] A Cell emptied by the reaper goes in the same pass.
+				if got := hub.Stats().TotalUsers; got != tt.wantCells {
+					t.Fatalf("cells after %d cycles: got %d, want %d", tt.cycles, got, tt.wantCells)
+				}
+			})
+			if got := counterTotal(t, "im_delivery_orphan_sessions_total") - before; got != tt.wantOrphans {
+				t.Fatalf("im_delivery_orphan_sessions_total grew by %d, want %d", got, tt.wantOrphans)
+			}
+		})
+	}
+}
+
+// testMetrics installs one SDK provider for the package: the global delegate forwards
+// package-level instruments only to the first provider ever set.
+var testMetrics = sync.OnceValue(func() *sdkmetric.ManualReader {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return reader
+})
+
+// counterTotal sums an int64 counter across its attribute sets.
+func counterTotal(t *testing.T, name string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := testMetrics().Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			var total int64
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				total += dp.Value
+			}
+			return total
+		}
+	}
+	return 0
+}
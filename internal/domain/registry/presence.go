@@ -0,0 +1,37 @@
+package registry
+
+// PresenceNotifier is told when a user's first session attaches to this node
+// (online) and when the last one leaves it (offline). conn is the session that
+// caused the transition.
+//
+// [HOT_PATH] Called synchronously from Attach and the retire paths, outside the Cell
+// lock, and must not block. Transitions racing on one user may arrive out of order;
+// implementations that care should re-check ConnectedCount.
+type PresenceNotifier func(conn Connector, online bool)
+
+// SetPresenceNotifier installs (or, with nil, removes) the presence notifier.
+func (h *Hub) SetPresenceNotifier(fn PresenceNotifier) {
+	if fn == nil {
+		h.presence.Store(nil)
+		return
+	}
+	h.presence.Store(&fn)
+}
+
+// presenceChanged is the Cell-side hook: it mirrors the transition into the global
+// store (if configured) and reports it to the notifier (if installed).
+func (h *Hub) presenceChanged(conn Connector, online bool) {
+	if h.global != nil {
+		h.global.enqueue(globalOp{userID: conn.GetUserID(), online: online})
+	}
+	if fn := h.presence.Load(); fn != nil {
+		(*fn)(conn, online)
+	}
+}
+
+// notifyPresence reports a session-count transition through the Hub hook, if any.
+func (c *Cell) notifyPresence(conn Connector, online bool) {
+	if c.presence != nil {
+		c.presence(conn, online)
+	}
+}
@@ -0,0 +1,266 @@
+package registry
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// # Resume protocol
+//
+// Every event that passes through a user's Cell is stamped, inside the Cell loop, with
+// a Cursor{Epoch, Seq}. The loop is the only writer, so Seq is gap-free and strictly
+// increasing within an Epoch; the Epoch changes whenever the Cell is recreated.
+//
+// A client that stores the cursor of the last event it processed may reconnect with it.
+// The attach is executed as a control operation on the same loop, which makes the
+// handoff atomic with respect to live delivery:
+//
+//  1. the replay range (cursor.Seq, head] is snapshotted from the ring buffer;
+//  2. the range is enqueued into the new connector, in order;
+//  3. the connector joins the live session set.
+//
+// Because no event is sequenced or delivered while the operation runs, every event
+// with a higher Seq is delivered only by the live path and none is delivered twice.
+// If the range cannot be replayed in full (different Epoch, evicted from the ring,
+// or larger than the connector buffer) a ReplayGap event precedes whatever is replayed,
+// and the client must refetch history before trusting the stream.
+
+// replaySendTimeout bounds each enqueue during replay; the connector is sized to fit.
+const replaySendTimeout = 250 * time.Millisecond
+
+// replayRing is a fixed-capacity FIFO of the user's most recent sequenced events.
+// [LOOP_OWNED] Accessed only from the Cell loop; no locking.
+type replayRing struct {
+	buf  []*event.SequencedEvent
+	head int // Index of the oldest element
+	size int
+}
+
+func newReplayRing(capacity int) *replayRing {
+	return &replayRing{buf: make([]*event.SequencedEvent, max(capacity, 0))}
+}
+
+func (r *replayRing) add(ev *event.SequencedEvent) {
+	if len(r.buf) == 0 {
+		return
+	}
+	if r.size < len(r.buf) {
+		r.buf[(r.head+r.size)%len(r.buf)] = ev
+		r.size++
+		return
+	}
+	// [OVERWRITE] Full: drop the oldest.
+	r.buf[r.head] = ev
+	r.head = (r.head + 1) % len(r.buf)
+}
+
+// oldestSeq returns the sequence of the oldest retained event (0 when empty).
+func (r *replayRing) oldestSeq() uint64 {
+	if r.size == 0 {
+		return 0
+	}
+	return r.buf[r.head].Cursor.Seq
+}
+
+// seqOf returns the sequence of the retained event with the given ID.
+func (r *replayRing) seqOf(id string) (uint64, bool) {
+	for i := range r.size {
+		if ev := r.buf[(r.head+i)%len(r.buf)]; ev.GetID() == id {
+			return ev.Cursor.Seq, true
+		}
+	}
+	return 0, false
+}
+
+// seqAt returns the sequence of the last retained event that occurred at or before ts
+// (Unix ms). ok is false when the oldest retained event is already newer, since the
+// events between ts and it may have been evicted.
+func (r *replayRing) seqAt(ts int64) (uint64, bool) {
+	var seq uint64
+	for i := range r.size {
+		ev := r.buf[(r.head+i)%len(r.buf)]
+		if ev.GetOccurredAt() > ts {
+			break
+		}
+		seq = ev.Cursor.Seq
+	}
+	return seq, seq != 0
+}
+
+// after returns retained events with Seq > seq, oldest first.
+func (r *replayRing) after(seq uint64) []*event.SequencedEvent {
+	var res []*event.SequencedEvent
+	for i := range r.size {
+		ev := r.buf[(r.head+i)%len(r.buf)]
+		if ev.Cursor.Seq > seq {
+			res = append(res, ev)
+		}
+	}
+	return res
+}
+
+// newEpoch picks a non-zero Cell incarnation identifier.
+func newEpoch() uint32 {
+	for {
+		if e := rand.Uint32(); e != 0 {
+			return e
+		}
+	}
+}
+
+// sequence stamps the next cursor on an event and records it for replay.
+// [LOOP_ONLY]
+func (c *Cell) sequence(ev event.Eventer) *event.SequencedEvent {
+	c.seq++
+	sev := event.NewSequencedEvent(ev, event.Cursor{Epoch: c.epoch, Seq: c.seq})
+	c.replay.add(sev)
+	return sev
+}
+
+// AttachResume attaches a connector and replays everything after the cursor before it
+// receives live events. It returns false if the Cell has already stopped (nothing is
+// replayed or attached in that case).
+func (c *Cell) AttachResume(conn Connector, from event.Cursor) bool {
+	return c.attachResume(conn, func() (event.Cursor, string) { return from, from.String() })
+}
+
+// AttachResumeID is AttachResume keyed by the ID of the last event the client received.
+// When the ID is empty or no longer retained, lastEventTS (Unix ms of that event, 0 if
+// unknown) locates the position instead. Both are resolved inside the loop, so they
+// see exactly the ring the replay reads.
+func (c *Cell) AttachResumeID(conn Connector, lastEventID string, lastEventTS int64) bool {
+	return c.attachResume(conn, func() (event.Cursor, string) {
+		requested := lastEventID
+		if lastEventID != "" {
+			if seq, ok := c.replay.seqOf(lastEventID); ok {
+				return event.Cursor{Epoch: c.epoch, Seq: seq}, requested
+			}
+		} else {
+			requested = strconv.FormatInt(lastEventTS, 10)
+		}
+		if lastEventTS > 0 {
+			if seq, ok := c.replay.seqAt(lastEventTS); ok {
+				return event.Cursor{Epoch: c.epoch, Seq: seq}, requested
+			}
+		}
+		// Unknown position: the zero cursor never matches the epoch, forcing a gap.
+		return event.Cursor{}, requested
+	})
+}
+
+// attachResume runs resolve, the replay and the attach as one control operation.
+func (c *Cell) attachResume(conn Connector, resolve func() (event.Cursor, string)) bool {
+	attached := make(chan bool, 1)
+	op := func() {
+		ok := false
+		defer func() { attached <- ok }() // [PANIC] A failed replay still answers.
+		// [LIVENESS] Stop may have run between the send and now; replay nothing then.
+		if c.isStopped() {
+			return
+		}
+		from, requested := resolve()
+		c.replayInto(conn, from, requested)
+		ok = c.Attach(conn)
+	}
+
+	select {
+	case c.control <- op:
+	case <-c.doneCh:
+		return false
+	}
+
+	// [HANDOFF] The loop always runs an operation it has received.
+	return <-attached
+}
+
+// replayInto enqueues the replay range into the connector, signalling any gap first.
+// requested is echoed in the gap notice as the client sent it.
+// [LOOP_ONLY]
+func (c *Cell) replayInto(conn Connector, from event.Cursor, requested string) {
+	pending, gap := c.resumeRange(from)
+
+	// [BUFFER_BOUND] The transport is not reading yet; never overflow the connector.
+	// One slot is reserved for the gap notice.
+	if limit := cap(conn.Recv()) - 1; len(pending) > limit {
+		pending = pending[len(pending)-max(limit, 0):]
+		gap = true
+	}
+
+	if gap {
+		payload := &model.ReplayGapPayload{Requested: requested}
+		if len(pending) > 0 {
+			payload.Oldest = pending[0].Cursor.String()
+		}
+		conn.Send(event.NewSystemEvent(c.userID, event.ReplayGap, event.PriorityHigh, payload), replaySendTimeout)
+	}
+
+	for _, ev := range pending {
+		if conn.KindFilter().Allows(ev.GetKind()) {
+			conn.Send(event.NewReplayedEvent(ev), replaySendTimeout)
+		}
+	}
+}
+
+// resumeRange returns the retained events after from and whether some were lost.
+// [LOOP_ONLY]
+func (c *Cell) resumeRange(from event.Cursor) ([]*event.SequencedEvent, bool) {
+	// Foreign epoch or a cursor from the future: everything retained is replayed behind a gap.
+	if from.Epoch != c.epoch || from.Seq > c.seq {
+		return c.replay.after(0), true
+	}
+	pending := c.replay.after(from.Seq)
+	// Contiguous when the client is up to date, or the ring still holds the next sequence.
+	gap := from.Seq < c.seq && (len(pending) == 0 || pending[0].Cursor.Seq != from.Seq+1)
+	return pending, gap
+}
+
+// CanResume reports whether everything after the cursor is still retained, so a resume
+// from it would replay without a gap. It counts as activity: polling transports, which
+// hold no session between requests, keep their history alive by asking.
+// It returns false if the Cell has already stopped.
+func (c *Cell) CanResume(from event.Cursor) bool {
+	c.touch()
+	ok := make(chan bool, 1)
+	op := func() {
+		resumable := false
+		defer func() { ok <- resumable }()
+		_, gap := c.resumeRange(from)
+		resumable = !gap
+	}
+
+	select {
+	case c.control <- op:
+	case <-c.doneCh:
+		return false
+	}
+	return <-ok
+}
+
+// purgeCachedBefore drops transport caches built by a marshaller schema older than version.
+// [LOOP_ONLY] The ring is loop-owned, so the purge runs as a control operation.
+// It returns the number of purged events, or -1 if the Cell has already stopped or the purge failed.
+func (c *Cell) purgeCachedBefore(version uint64) int {
+	purged := make(chan int, 1)
+	op := func() {
+		n := -1
+		defer func() { purged <- n }()
+		count := 0
+		for _, ev := range c.replay.after(0) {
+			if ev.Encoded().PurgeProtoBefore(version) {
+				count++
+			}
+		}
+		n = count
+	}
+
+	select {
+	case c.control <- op:
+	case <-c.doneCh:
+		return -1
+	}
+	return <-purged
+}
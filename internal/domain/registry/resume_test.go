@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"context"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"testing"
+	"testing/synctest"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// TestResumeDuringBroadcast runs randomized resume-during-broadcast interleavings and
+// checks the handoff invariant: a resumed connection receives every sequence after its
+// cursor exactly once, in order, and without a gap notice.
+func TestResumeDuringBroadcast(t *testing.T) {
+	const runs = 2000
+	for seed := range uint64(runs) {
+		synctest.Test(t, func(t *testing.T) {
+			r := rand.New(rand.NewPCG(seed, 0))
+			hub := NewHub(WithShardCount(1), WithMailboxSize(128))
+			defer hub.Shutdown()
+
+			user := uuid.New()
+			ping := func() event.Eventer { return event.NewSystemEvent(user, event.Ping, event.PriorityNormal, nil) }
+
+			// The previous session saw some history and stored the cursor of one event.
+			prev := NewConnector(context.Background(), user, 128, ConnectMetadata{})
+			hub.Register(prev)
+			before, during := 1+r.IntN(20), r.IntN(40)
+			for range before {
+				hub.Broadcast(ping())
+			}
+			synctest.Wait()
+			seen := drain(prev)
+			if len(seen) != before {
+				t.Fatalf("seed %d: previous session received %d events, want %d", seed, len(seen), before)
+			}
+			from, _ := event.CursorOf(seen[r.IntN(before)])
+
+			// [INTERLEAVING] The broadcaster yields at seeded points, so the resume lands
+			// anywhere in the concurrent burst.
+			yields := rand.New(rand.NewPCG(seed, 1))
+			var wg sync.WaitGroup
+			wg.Go(func() {
+				for range during {
+					if yields.IntN(3) == 0 {
+						runtime.Gosched()
+					}
+					hub.Broadcast(ping())
+				}
+			})
+			for range r.IntN(during + 1) {
+				runtime.Gosched()
+			}
+			next := NewConnector(context.Background(), user, 128, ConnectMetadata{})
+			hub.RegisterResume(next, from)
+			wg.Wait()
+			synctest.Wait()
+
+			want := from.Seq + 1
+			for _, ev := range drain(next) {
+				if ev.GetKind() == event.ReplayGap {
+					t.Fatalf("seed %d: unexpected gap resuming from %s", seed, from)
+				}
+				c, ok := event.CursorOf(ev)
+				if !ok || c.Epoch != from.Epoch {
+					t.Fatalf("seed %d: event without a cursor of this epoch: %v", seed, c)
+				}
+				if c.Seq != want {
+					t.Fatalf("seed %d: received seq %d, want %d (resumed from %s)", seed, c.Seq, want, from)
+				}
+				want++
+			}
+			if last := uint64(before + during); want != last+1 {
+				t.Fatalf("seed %d: stream ended before seq %d, want through %d", seed, want, last)
+			}
+		})
+	}
+}
+
+// drain returns everything buffered on the connector without blocking.
+func drain(conn Connector) []event.Eventer {
+	var res []event.Eventer
+	for {
+		select {
+		case ev := <-conn.Recv():
+			res = append(res, ev)
+		default:
+			return res
+		}
+	}
+}
+
+// TestResumeGap checks when a resume is announced as partial.
+func TestResumeGap(t *testing.T) {
+	tests := []struct {
+		name     string
+		ring     int
+		buffer   int // Resumed connector buffer
+		from     func(epoch uint32) event.Cursor
+		wantGap  bool
+		wantSeqs int // Replayed events after the notice, if any
+	}{
+		{name: "up to date", ring: 16, buffer: 32, from: func(e uint32) event.Cursor { return event.Cursor{Epoch: e, Seq: 10} }},
+		{name: "contiguous", ring: 16, buffer: 32, from: func(e uint32) event.Cursor { return event.Cursor{Epoch: e, Seq: 4} }, wantSeqs: 6},
+		{name: "foreign epoch", ring: 16, buffer: 32, from: func(e uint32) event.Cursor { return event.Cursor{Epoch: e + 1, Seq: 4} }, wantGap: true, wantSeqs: 10},
+		{name: "cursor from the future", ring: 16, buffer: 32, from: func(e uint32) event.Cursor { return event.Cursor{Epoch: e, Seq: 99} }, wantGap: true, wantSeqs: 10},
+		{name: "evicted from the ring", ring: 4, buffer: 32, from: func(e uint32) event.Cursor { return event.Cursor{Epoch: e, Seq: 2} }, wantGap: true, wantSeqs: 4},
+		{name: "larger than the buffer", ring: 16, buffer: 4, from: func(e uint32) event.Cursor { return event.Cursor{Epoch: e, Seq: 2} }, wantGap: true, wantSeqs: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				hub := NewHub(WithShardCount(1), WithReplayBufferSize(tt.ring))
+				defer hub.Shutdown()
+
+				user := uuid.New()
+				prev := NewConnector(context.Background(), user, 32, ConnectMetadata{})
+				hub.Register(prev)
+				for range 10 {
+					hub.Broadcast(event.NewSystemEvent(user, event.Ping, event.PriorityNormal, nil))
+				}
+				synctest.Wait()
+				first, _ := event.CursorOf(drain(prev)[0])
+
+				next := NewConnector(context.Background(), user, tt.buffer, ConnectMetadata{})
+				hub.RegisterResume(next, tt.from(first.Epoch))
+				synctest.Wait()
+
+				got := drain(next)
+				gap := len(got) > 0 && got[0].GetKind() == event.ReplayGap
+				if gap {
+					got = got[1:]
+				}
+				if gap != tt.wantGap || len(got) != tt.wantSeqs {
+					t.Fatalf("gap = %v with %d replayed, want %v with %d", gap, len(got), tt.wantGap, tt.wantSeqs)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RetireReason records which teardown path won the race for a session.
+type RetireReason string
+
+const (
+	RetireUnregister   RetireReason = "unregister"    // Transport detached its own session
+	RetireOrphan       RetireReason = "orphan"        // Evictor reclaimed a dead, never-unregistered session
+	RetireCellStop     RetireReason = "cell_stopped"  // Cell evicted or Hub shut down
+	RetireSessionLimit RetireReason = "session_limit" // Oldest session kicked for a new one
+	RetireAdmin        RetireReason = "admin"         // Force-closed by an operator (see Hub.Disconnect)
+	RetireSuperseded   RetireReason = "superseded"    // Replaced by a newer session from the same device
+)
+
+var sessionsRetired, _ = registryMeter.Int64Counter(
+	"im_delivery_sessions_retired_total",
+	metric.WithDescription("Sessions torn down, by the teardown path that won"),
+)
+
+// # Teardown protocol
+//
+// Every path that ends a session (transport Unregister, orphan reaping, eviction,
+// shutdown) funnels through Cell.retire. The session map is the single source of
+// truth: whoever removes the entry under c.mu wins, and only the winner
+//
+//  1. drops the orphan-suspect mark,
+//  2. runs the Hub's retirement hook (topic index, metrics, log) with its reason,
+//  3. closes the connector.
+//
+// Losers see the entry already gone and do nothing, so bookkeeping is applied
+// exactly once no matter how many initiators race. deliver fans out from a snapshot
+// taken under the read lock and sends after releasing it, so a just-retired
+// connector may still be in the snapshot. That is harmless: the winner closes it,
+// and a closed connector takes only high-priority signals, and nothing once its
+// transport releases it (see connState).
+// Notices meant for the retired session (kick, supersede) are sent before the close.
+// External callers only request retirement; they never touch Cell state directly.
+
+// retire tears one session down and reports whether this call won.
+func (c *Cell) retire(connID uuid.UUID, reason RetireReason) bool {
+	c.mu.Lock()
+	conn, ok := c.sessions[connID]
+	if ok {
+		delete(c.sessions, connID)
+		delete(c.suspects, connID)
+	}
+	last := ok && len(c.sessions) == 0
+	c.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	c.finishRetire(conn, reason)
+	if last {
+		c.notifyPresence(conn, false)
+	}
+	return true
+}
+
+// retireAll tears every session down; used by Stop.
+func (c *Cell) retireAll(reason RetireReason) {
+	c.mu.Lock()
+	c.stopped = true
+	conns := make([]Connector, 0, len(c.sessions))
+	for id, conn := range c.sessions {
+		conns = append(conns, conn)
+		delete(c.sessions, id)
+	}
+	c.suspects = nil
+	c.mu.Unlock()
+
+	for _, conn := range conns {
+		c.finishRetire(conn, reason)
+	}
+	if len(conns) > 0 {
+		c.notifyPresence(conns[0], false)
+	}
+}
+
+// finishRetire runs the winner's side effects outside the Cell lock.
+func (c *Cell) finishRetire(conn Connector, reason RetireReason) {
+	c.stats.sessions.Add(-1)
+	if c.retired != nil {
+		c.retired(conn, reason)
+	}
+	conn.Close()
+}
+
+// retired is the Hub-side half of the protocol, run once per session by the winner.
+func (h *Hub) retired(conn Connector, reason RetireReason) {
+	// [TOPIC_CLEANUP] Ephemeral subscriptions never outlive their connection.
+	h.topics.dropConn(conn.GetID())
+
+	sessionsRetired.Add(context.Background(), 1, metric.WithAttributes(attribute.String("reason", string(reason))))
+	slog.Debug("SESSION_RETIRED",
+		slog.String("user_id", conn.GetUserID().String()),
+		slog.String("conn_id", conn.GetID().String()),
+		slog.String("reason", string(reason)),
+	)
+}
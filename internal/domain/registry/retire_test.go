@@ -0,0 +1,78 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// teardownPaths are the initiators that may race to end one session.
+var teardownPaths = map[string]func(c *Cell, conn Connector){
+	"unregister": func(c *Cell, conn Connector) { c.Detach(conn.GetID()) },
+	"orphan":     func(c *Cell, conn Connector) { c.retire(conn.GetID(), RetireOrphan) },
+	"admin":      func(c *Cell, conn Connector) { c.disconnect(conn.GetID(), "kicked") },
+	"stop":       func(c *Cell, _ Connector) { c.Stop() },
+}
+
+// TestRetireRacingInitiators races every pair of teardown paths on the same session.
+// Exactly one reason must be recorded, the counters must net to zero, and the
+// connector must refuse low and normal events sent after it was retired.
+func TestRetireRacingInitiators(t *testing.T) {
+	type pair struct{ a, b string }
+	var tests []pair
+	for a := range teardownPaths {
+		for b := range teardownPaths {
+			if a == "stop" && b == "stop" {
+				continue // Stop is called once per Cell by the Hub
+			}
+			tests = append(tests, pair{a, b})
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			for range 20 {
+				var (
+					mu      sync.Mutex
+					reasons []RetireReason
+				)
+				stats := newHubCounters()
+				userID := uuid.New()
+				cell := NewCell(userID, 16, 0, nil, func(_ Connector, r RetireReason) {
+					mu.Lock()
+					reasons = append(reasons, r)
+					mu.Unlock()
+				}, stats)
+
+				conn := NewConnector(context.Background(), userID, 64, ConnectMetadata{})
+				cell.Attach(conn)
+
+				var wg sync.WaitGroup
+				wg.Go(func() { teardownPaths[tt.a](cell, conn) })
+				wg.Go(func() { teardownPaths[tt.b](cell, conn) })
+				wg.Wait()
+
+				if len(reasons) != 1 {
+					t.Fatalf("retire reasons: got %v, want exactly one", reasons)
+				}
+				if n := stats.sessions.Load(); n != 0 {
+					t.Fatalf("session counter: got %d, want 0", n)
+				}
+				if n := cell.sessionCount(); n != 0 {
+					t.Fatalf("sessions left attached: %d", n)
+				}
+				if conn.Send(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil), time.Millisecond) {
+					t.Fatal("a retired connector accepted a normal-priority Send")
+				}
+				if !cell.isStopped() {
+					cell.Stop()
+				}
+				conn.Release()
+			}
+		})
+	}
+}
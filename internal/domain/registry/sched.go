@@ -0,0 +1,10 @@
+package registry
+
+// [SCHEDULER_SEAM]
+// Every goroutine the registry starts goes through spawn, so the caller's goroutine
+// context is the only thing that decides where an actor runs. The simulation tests
+// (sim_test.go) rely on this: a Hub built inside a testing/synctest bubble keeps
+// every Cell loop, evictor and timer in that bubble.
+
+// spawn starts f on a new goroutine.
+func spawn(f func()) { go f() }
@@ -0,0 +1,51 @@
+package registry
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// ReasonSessionLimit is the DisconnectedPayload reason sent to a kicked session.
+const ReasonSessionLimit = "session_limit_exceeded"
+
+// kickNoticeTimeout bounds the farewell enqueue; a stalled victim just gets closed.
+const kickNoticeTimeout = 50 * time.Millisecond
+
+// activityReporter is implemented by our own connector; foreign Connectors count as oldest.
+type activityReporter interface {
+	lastActive() int64
+}
+
+// lruVictim picks the least-recently-active session other than keep.
+// [LOCK_REQUIRED] c.mu must be held.
+func (c *Cell) lruVictim(keep uuid.UUID) Connector {
+	var (
+		victim Connector
+		oldest int64
+	)
+	for id, conn := range c.sessions {
+		if id == keep {
+			continue
+		}
+		var at int64
+		if r, ok := conn.(activityReporter); ok {
+			at = r.lastActive()
+		}
+		if victim == nil || at < oldest {
+			victim, oldest = conn, at
+		}
+	}
+	return victim
+}
+
+// kick tells the victim why it is being dropped, then retires it through the usual path.
+func (c *Cell) kick(victim Connector) {
+	victim.Send(event.NewSystemEvent(c.userID, event.Disconnected, event.PriorityHigh, &model.DisconnectedPayload{
+		Reason: ReasonSessionLimit,
+		Code:   "SESSION_LIMIT",
+	}), kickNoticeTimeout)
+	c.finishRetire(victim, RetireSessionLimit)
+}
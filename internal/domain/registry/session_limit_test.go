@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+func TestSessionLimitKicksLeastRecentlyActive(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		sessions   int   // Attached before the newcomer, one second apart
+		touch      []int // Sessions that accept an event afterwards, in order
+		wantKicked int   // Index of the kicked session; -1 expects none
+	}{
+		{name: "oldest session is kicked", limit: 2, sessions: 2, wantKicked: 0},
+		{name: "recent activity spares an older session", limit: 2, sessions: 2, touch: []int{0}, wantKicked: 1},
+		{name: "least recent of several", limit: 3, sessions: 3, touch: []int{1, 0}, wantKicked: 2},
+		{name: "below the limit", limit: 3, sessions: 1, wantKicked: -1},
+		{name: "unlimited", limit: 0, sessions: 4, wantKicked: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				hub := NewHub(WithShardCount(1), WithEvictionInterval(time.Hour), WithMaxSessionsPerUser(tt.limit))
+				defer hub.Shutdown()
+
+				userID := uuid.New()
+				ping := func() event.Eventer { return event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil) }
+				conns := make([]Connector, tt.sessions)
+				for i := range conns {
+					time.Sleep(time.Second)
+					conns[i] = NewConnector(context.Background(), userID, 16, ConnectMetadata{})
+					defer conns[i].Release()
+					hub.Register(conns[i])
+				}
+				for _, i := range tt.touch {
+					time.Sleep(time.Second)
+					if !conns[i].Send(ping(), time.Second) {
+						t.Fatalf("session %d refused the touch", i)
+					}
+				}
+
+				time.Sleep(time.Second)
+				newcomer := NewConnector(context.Background(), userID, 16, ConnectMetadata{})
+				defer newcomer.Release()
+				hub.Register(newcomer)
+				synctest.Wait()
+
+				for i, conn := range conns {
+					if got := conn.IsClosing(); got != (i == tt.wantKicked) {
+						t.Fatalf("session %d closing = %v, kicked session is %d", i, got, tt.wantKicked)
+					}
+				}
+				if tt.wantKicked >= 0 {
+					if reason := disconnectReason(conns[tt.wantKicked]); reason != ReasonSessionLimit {
+						t.Fatalf("kicked session notice: got %q, want %q", reason, ReasonSessionLimit)
+					}
+				}
+				wantSessions := tt.sessions + 1
+				if tt.wantKicked >= 0 {
+					wantSessions--
+				}
+				if got := hub.ConnectedCount(userID); got != wantSessions {
+					t.Fatalf("sessions: got %d, want %d", got, wantSessions)
+				}
+
+				if res := hub.Broadcast(ping()); !res.Queued {
+					t.Fatalf("broadcast after the kick not queued: %+v", res)
+				}
+				synctest.Wait()
+				select {
+				case ev := <-newcomer.Recv():
+					if ev.GetKind() != event.Ping {
+						t.Fatalf("newcomer received %v, want %v", ev.GetKind(), event.Ping)
+					}
+				default:
+					t.Fatal("newcomer did not receive the broadcast")
+				}
+			})
+		})
+	}
+}
+
+// disconnectReason returns the reason of the first Disconnected event buffered for conn.
+func disconnectReason(conn Connector) string {
+	for {
+		select {
+		case ev := <-conn.Recv():
+			if p, ok := ev.GetPayload().(*model.DisconnectedPayload); ok && ev.GetKind() == event.Disconnected {
+				return p.Reason
+			}
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package registry
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionInfo is a point-in-time description of one attached connection.
+type SessionInfo struct {
+	ConnID       uuid.UUID       `json:"conn_id"`
+	CreatedAt    time.Time       `json:"created_at"`
+	LastActivity time.Time       `json:"last_activity"` // Zero when the Connector does not report it
+	Dropped      uint64          `json:"dropped"`
+	Metadata     ConnectMetadata `json:"metadata"`
+}
+
+// Sessions lists the connections the user has attached to this node, oldest first.
+// Returns nil when the user holds no Cell here.
+func (h *Hub) Sessions(userID uuid.UUID) []SessionInfo {
+	s := h.getShard(userID)
+
+	s.RLock()
+	cell, ok := s.cells[userID]
+	s.RUnlock()
+	if !ok {
+		return nil
+	}
+	return cell.describeSessions()
+}
+
+// describeSessions snapshots the session set under the read lock.
+func (c *Cell) describeSessions() []SessionInfo {
+	c.mu.RLock()
+	res := make([]SessionInfo, 0, len(c.sessions))
+	for id, conn := range c.sessions {
+		info := SessionInfo{
+			ConnID:    id,
+			CreatedAt: conn.CreatedAt(),
+			Dropped:   conn.Dropped(),
+			Metadata:  conn.Metadata(),
+		}
+		if r, ok := conn.(activityReporter); ok {
+			info.LastActivity = time.Unix(0, r.lastActive())
+		}
+		res = append(res, info)
+	}
+	c.mu.RUnlock()
+
+	slices.SortFunc(res, func(a, b SessionInfo) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return res
+}
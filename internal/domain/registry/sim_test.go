@@ -0,0 +1,439 @@
+package registry
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// # Simulation harness
+//
+// Each scenario runs a Hub inside a testing/synctest bubble. The driver applies one
+// operation per step, then settles: it advances the fake clock past the send window
+// and waits until every registry goroutine is durably blocked and every mailbox is
+// empty. Steps therefore never overlap, the run is a pure function of the seed, and
+// the invariants below are checked at well-defined points:
+//
+//   - no session receives an event twice;
+//   - no session receives a low or normal priority broadcast issued after it was
+//     closed (high-priority signals may still reach a closing session);
+//   - events of one priority reach a session in broadcast order;
+//   - every queued event is accounted for once per session it was fanned out to;
+//   - mailboxes drain within a bounded number of send windows (no lost wakeup);
+//   - every event a session accepted is read by it unless the session was released;
+//   - after shutdown and release, the Hub's cell and session counters are zero.
+//
+// A failure names the scenario and seed; rerun it with
+//
+//	go test ./internal/domain/registry -run 'TestSimulation/<scenario>' -sim.seed=<seed>
+//
+// and -sim.seeds=N explores N further seeds per scenario (the nightly job does this).
+
+var (
+	simSeed  = flag.Uint64("sim.seed", 0, "run every simulation scenario with this seed only")
+	simSeeds = flag.Int("sim.seeds", 0, "additional random seeds to explore per simulation scenario")
+)
+
+type simOp int
+
+const (
+	opRegister   simOp = iota // Open a session (optionally from a shared device)
+	opBroadcast               // Broadcast to a user
+	opRead                    // The transport drains its buffer
+	opClose                   // The transport dies without unregistering
+	opUnregister              // The transport unregisters and releases its connector
+	opAdmin                   // An operator disconnects a session
+	opEvict                   // The evictor runs after the idle timeout
+	simOpCount
+)
+
+var simOpNames = [simOpCount]string{"register", "broadcast", "read", "close", "unregister", "admin", "evict"}
+
+// simSpec describes one scenario; the seed picks the concrete operation sequence.
+type simSpec struct {
+	name        string
+	seed        uint64
+	steps       int
+	users       int
+	devices     int // Device IDs to draw from on register (0: none, never superseded)
+	buffer      int // Session buffer; odd sizes keep the channels out of the shared pools
+	maxSessions int
+	priorities  []event.EventPriority
+	weights     [simOpCount]int
+}
+
+var simSpecs = []simSpec{
+	{name: "attach-evict", seed: 1, steps: 120, users: 1, buffer: 5,
+		weights: [simOpCount]int{opRegister: 3, opBroadcast: 2, opRead: 2, opUnregister: 3, opEvict: 4}},
+	{name: "broadcast-after-close", seed: 2, steps: 120, users: 2, buffer: 5,
+		weights: [simOpCount]int{opRegister: 2, opBroadcast: 5, opRead: 2, opClose: 3, opUnregister: 1}},
+	{name: "orphan-vs-unregister", seed: 3, steps: 150, users: 2, buffer: 5,
+		weights: [simOpCount]int{opRegister: 2, opBroadcast: 1, opClose: 4, opUnregister: 3, opEvict: 3}},
+	{name: "admin-vs-unregister", seed: 4, steps: 120, users: 2, buffer: 5,
+		weights: [simOpCount]int{opRegister: 3, opBroadcast: 2, opRead: 1, opUnregister: 4, opAdmin: 4}},
+	{name: "device-supersede", seed: 5, steps: 120, users: 1, devices: 2, buffer: 5,
+		weights: [simOpCount]int{opRegister: 6, opBroadcast: 2, opRead: 2, opUnregister: 1}},
+	{name: "session-limit-kick", seed: 6, steps: 120, users: 1, buffer: 5, maxSessions: 2,
+		weights: [simOpCount]int{opRegister: 6, opBroadcast: 2, opRead: 2, opUnregister: 1}},
+	{name: "saturated-consumer", seed: 7, steps: 200, users: 1, buffer: 3,
+		weights: [simOpCount]int{opRegister: 1, opBroadcast: 8, opRead: 1}},
+	{name: "priority-tiers", seed: 8, steps: 200, users: 1, buffer: 3,
+		priorities: []event.EventPriority{event.PriorityLow, event.PriorityNormal, event.PriorityHigh},
+		weights:    [simOpCount]int{opRegister: 1, opBroadcast: 6, opRead: 2}},
+	{name: "shutdown-with-backlog", seed: 9, steps: 60, users: 3, buffer: 3,
+		weights: [simOpCount]int{opRegister: 3, opBroadcast: 8}},
+	{name: "reconnect-churn", seed: 10, steps: 250, users: 3, devices: 3, buffer: 5, maxSessions: 3,
+		priorities: []event.EventPriority{event.PriorityLow, event.PriorityNormal, event.PriorityHigh},
+		weights:    [simOpCount]int{1, 1, 1, 1, 1, 1, 1}},
+}
+
+func TestSimulation(t *testing.T) {
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	for _, spec := range simSpecs {
+		t.Run(spec.name, func(t *testing.T) {
+			seeds := []uint64{spec.seed}
+			if *simSeed != 0 {
+				seeds = []uint64{*simSeed}
+			}
+			for range *simSeeds {
+				seeds = append(seeds, rand.Uint64())
+			}
+			for _, seed := range seeds {
+				synctest.Test(t, func(t *testing.T) {
+					newSimWorld(t, spec, seed).run()
+				})
+			}
+		})
+	}
+}
+
+// simConn is the harness's view of one session.
+type simConn struct {
+	conn      Connector
+	user      uuid.UUID
+	closedAt  int // Step at which the connector was first seen closing; -1 while open
+	released  bool
+	received  map[string]bool
+	lastSeq   map[event.EventPriority]int
+	delivered map[string]bool // Reported OutcomeDelivered by the Cell
+	refused   uint64          // Reported OutcomeDropped by the Cell
+}
+
+// simBroadcast remembers when and how an event was issued.
+type simBroadcast struct {
+	step     int
+	seq      int
+	sessions int // Sessions attached when it was queued
+	queued   bool
+}
+
+type simWorld struct {
+	t    *testing.T
+	spec simSpec
+	seed uint64
+	rng  *rand.Rand
+	hub  *Hub
+	step int
+
+	users      []uuid.UUID
+	conns      []*simConn
+	byID       map[uuid.UUID]*simConn
+	broadcasts map[string]*simBroadcast
+
+	mu       sync.Mutex // Guards outcomes; the observer runs on Cell loops
+	outcomes map[string]int
+}
+
+func newSimWorld(t *testing.T, spec simSpec, seed uint64) *simWorld {
+	w := &simWorld{
+		t:          t,
+		spec:       spec,
+		seed:       seed,
+		rng:        rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		byID:       make(map[uuid.UUID]*simConn),
+		broadcasts: make(map[string]*simBroadcast),
+		outcomes:   make(map[string]int),
+	}
+	for range spec.users {
+		w.users = append(w.users, uuid.New())
+	}
+	opts := []Option{
+		WithShardCount(2),
+		WithMailboxSize(64),
+		WithIdleTimeout(time.Minute),
+		WithEvictionInterval(24 * time.Hour), // Eviction is an explicit step
+	}
+	if spec.maxSessions > 0 {
+		opts = append(opts, WithMaxSessionsPerUser(spec.maxSessions))
+	}
+	w.hub = NewHub(opts...)
+	w.hub.SetDeliveryObserver(w)
+	return w
+}
+
+// ObserveDelivery counts per-session outcomes of broadcast events.
+func (w *simWorld) ObserveDelivery(ev event.Eventer, connID uuid.UUID, outcome DeliveryOutcome) {
+	if connID == uuid.Nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.outcomes[ev.GetID()]++
+	sc := w.byID[connID]
+	switch {
+	case sc == nil:
+	case outcome == OutcomeDelivered:
+		sc.delivered[ev.GetID()] = true
+	case outcome == OutcomeDropped:
+		sc.refused++
+	}
+}
+
+func (w *simWorld) failf(format string, args ...any) {
+	w.t.Helper()
+	w.t.Fatalf("scenario %s seed %d step %d: %s", w.spec.name, w.seed, w.step, fmt.Sprintf(format, args...))
+}
+
+func (w *simWorld) run() {
+	for w.step = 0; w.step < w.spec.steps; w.step++ {
+		w.apply(w.pick())
+		w.settle()
+		w.checkStep()
+	}
+
+	// [LIVENESS] Whatever a live session accepted is read by it, unless a later,
+	// higher-priority event evicted it; evictions are the drops the Cell never saw.
+	for _, sc := range w.live() {
+		w.read(sc)
+	}
+	for _, sc := range w.live() {
+		w.mu.Lock()
+		missing := 0
+		for id := range sc.delivered {
+			if !sc.received[id] {
+				missing++
+			}
+		}
+		evicted := sc.conn.Dropped() - sc.refused
+		w.mu.Unlock()
+		if uint64(missing) > evicted {
+			w.failf("conn %s lost %d accepted events, only %d were evicted", sc.conn.GetID(), missing, evicted)
+		}
+	}
+
+	w.hub.Shutdown()
+	synctest.Wait()
+	for _, sc := range w.live() {
+		w.unregister(sc)
+	}
+	synctest.Wait()
+
+	if n := w.hub.stats.sessions.Load(); n != 0 {
+		w.failf("session counter did not return to zero: %d", n)
+	}
+	if n := w.hub.stats.cells.Load(); n != 0 {
+		w.failf("cell counter did not return to zero: %d", n)
+	}
+}
+
+func (w *simWorld) pick() simOp {
+	total := 0
+	for _, wt := range w.spec.weights {
+		total += wt
+	}
+	n := w.rng.IntN(total)
+	for op, wt := range w.spec.weights {
+		if n < wt {
+			return simOp(op)
+		}
+		n -= wt
+	}
+	return opBroadcast
+}
+
+func (w *simWorld) live() []*simConn {
+	var res []*simConn
+	for _, sc := range w.conns {
+		if !sc.released {
+			res = append(res, sc)
+		}
+	}
+	return res
+}
+
+func (w *simWorld) anyLive() *simConn {
+	live := w.live()
+	if len(live) == 0 {
+		return nil
+	}
+	return live[w.rng.IntN(len(live))]
+}
+
+func (w *simWorld) apply(op simOp) {
+	user := w.users[w.rng.IntN(len(w.users))]
+	switch op {
+	case opRegister:
+		var md ConnectMetadata
+		if w.spec.devices > 0 {
+			md.DeviceID = fmt.Sprintf("device-%d", w.rng.IntN(w.spec.devices))
+		}
+		sc := &simConn{
+			conn:      NewConnector(context.Background(), user, w.spec.buffer, md),
+			user:      user,
+			closedAt:  -1,
+			received:  make(map[string]bool),
+			lastSeq:   make(map[event.EventPriority]int),
+			delivered: make(map[string]bool),
+		}
+		w.mu.Lock()
+		w.conns = append(w.conns, sc)
+		w.byID[sc.conn.GetID()] = sc
+		w.mu.Unlock()
+		w.hub.Register(sc.conn)
+
+	case opBroadcast:
+		prio := event.PriorityNormal
+		if len(w.spec.priorities) > 0 {
+			prio = w.spec.priorities[w.rng.IntN(len(w.spec.priorities))]
+		}
+		ev := event.NewSystemEvent(user, event.Ping, prio, nil)
+		b := &simBroadcast{step: w.step, seq: len(w.broadcasts), sessions: w.hub.ConnectedCount(user)}
+		w.broadcasts[ev.GetID()] = b
+		b.queued = w.hub.Broadcast(ev).Queued
+
+	case opRead:
+		if sc := w.anyLive(); sc != nil {
+			w.read(sc)
+		}
+
+	case opClose:
+		if sc := w.anyLive(); sc != nil {
+			sc.conn.Close()
+		}
+
+	case opUnregister:
+		if sc := w.anyLive(); sc != nil {
+			w.unregister(sc)
+		}
+
+	case opAdmin:
+		if sc := w.anyLive(); sc != nil {
+			_, _ = w.hub.Disconnect(sc.user, sc.conn.GetID(), "admin")
+		}
+
+	case opEvict:
+		time.Sleep(2 * time.Minute)
+		w.hub.performEviction()
+	}
+}
+
+// unregister is the transport's exit path: detach, then release the connector.
+func (w *simWorld) unregister(sc *simConn) {
+	w.read(sc)
+	w.hub.Unregister(sc.user, sc.conn.GetID())
+	sc.conn.Release()
+	sc.released = true
+}
+
+// read drains the session buffer and checks the delivery-side invariants.
+func (w *simWorld) read(sc *simConn) {
+	for {
+		select {
+		case ev := <-sc.conn.Recv():
+			id := ev.GetID()
+			if sc.received[id] {
+				w.failf("conn %s received %s twice", sc.conn.GetID(), id)
+			}
+			sc.received[id] = true
+
+			b, ok := w.broadcasts[id]
+			if !ok {
+				continue // Kick, supersede and replay notices
+			}
+			if sc.closedAt >= 0 && b.step > sc.closedAt && ev.GetPriority() < event.PriorityHigh {
+				w.failf("conn %s closed at step %d received %s broadcast at step %d", sc.conn.GetID(), sc.closedAt, id, b.step)
+			}
+			if last, seen := sc.lastSeq[ev.GetPriority()]; seen && b.seq < last {
+				w.failf("conn %s received priority %d out of order", sc.conn.GetID(), ev.GetPriority())
+			}
+			sc.lastSeq[ev.GetPriority()] = b.seq
+		default:
+			return
+		}
+	}
+}
+
+// settle advances the fake clock one send window at a time until every mailbox is
+// empty and a whole window passed without a new outcome, so no Send is in flight.
+func (w *simWorld) settle() {
+	const windows = 256
+	synctest.Wait()
+	last := -1
+	for range windows {
+		time.Sleep(300 * time.Millisecond)
+		synctest.Wait()
+		total := w.outcomeTotal()
+		if total == last && w.mailboxesEmpty() {
+			return
+		}
+		last = total
+	}
+	w.failf("mailboxes did not drain within %d send windows", windows)
+}
+
+func (w *simWorld) outcomeTotal() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	total := 0
+	for _, n := range w.outcomes {
+		total += n
+	}
+	return total
+}
+
+func (w *simWorld) mailboxesEmpty() bool {
+	for _, s := range w.hub.shards {
+		s.RLock()
+		for _, cell := range s.cells {
+			if len(cell.mailbox) > 0 {
+				s.RUnlock()
+				return false
+			}
+		}
+		s.RUnlock()
+	}
+	return true
+}
+
+func (w *simWorld) checkStep() {
+	for _, sc := range w.conns {
+		if sc.closedAt < 0 && sc.conn.IsClosing() {
+			sc.closedAt = w.step
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, b := range w.broadcasts {
+		if b.step != w.step || !b.queued {
+			continue
+		}
+		if got := w.outcomes[id]; got != b.sessions {
+			w.failf("event %s fanned out to %d sessions, %d outcomes reported", id, b.sessions, got)
+		}
+	}
+}
+
+func (op simOp) String() string { return simOpNames[op] }
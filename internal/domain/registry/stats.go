@@ -0,0 +1,55 @@
+package registry
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// hubCounters are the [CHEAP_STATS] maintained on the hot path with single atomic adds.
+type hubCounters struct {
+	startedAt time.Time
+
+	cells           atomic.Int64 // Live Cells
+	sessions        atomic.Int64 // Attached sessions across all Cells
+	broadcast       atomic.Int64 // Events offered to Broadcast/BroadcastIfConnected
+	droppedMailbox  atomic.Int64 // Rejected by a full Cell mailbox
+	droppedSessions atomic.Int64 // Shed by a saturated session buffer
+}
+
+func newHubCounters() *hubCounters {
+	return &hubCounters{startedAt: time.Now()}
+}
+
+// Stats returns a snapshot of the Hub.
+//
+// Counters are read with atomic loads; only the mailbox aggregates walk the Cells,
+// one shard RLock at a time (like performEviction), so no global lock is taken and
+// registration on other shards proceeds while the walk runs. Values from different
+// shards are not a single point-in-time cut.
+func (h *Hub) Stats() model.HubStats {
+	st := model.HubStats{
+		TotalUsers:       int(h.stats.cells.Load()),
+		TotalConnections: int(h.stats.sessions.Load()),
+		Uptime:           time.Since(h.stats.startedAt),
+		EventsBroadcast:  h.stats.broadcast.Load(),
+		EventsDropped:    h.stats.droppedMailbox.Load() + h.stats.droppedSessions.Load(),
+		MailboxDropped:   h.stats.droppedMailbox.Load(),
+		SessionDropped:   h.stats.droppedSessions.Load(),
+	}
+
+	for _, s := range h.shards {
+		s.RLock()
+		for _, cell := range s.cells {
+			depth := len(cell.mailbox)
+			st.MailboxDepthTotal += depth
+			st.MailboxDepthMax = max(st.MailboxDepthMax, depth)
+			if depth > 0 {
+				st.MailboxesNonEmpty++
+			}
+		}
+		s.RUnlock()
+	}
+	return st
+}
@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+func TestHubStats(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(hub *Hub) // Session buffers are never read
+		want model.HubStats
+	}{
+		{
+			name: "cells and sessions",
+			run: func(hub *Hub) {
+				for range 2 {
+					userID := uuid.New()
+					for range 2 {
+						hub.Register(NewConnector(context.Background(), userID, 16, ConnectMetadata{}))
+					}
+				}
+			},
+			want: model.HubStats{TotalUsers: 2, TotalConnections: 4},
+		},
+		{
+			name: "unregister keeps the cell until eviction",
+			run: func(hub *Hub) {
+				conn := NewConnector(context.Background(), uuid.New(), 16, ConnectMetadata{})
+				hub.Register(conn)
+				hub.Unregister(conn.GetUserID(), conn.GetID())
+			},
+			want: model.HubStats{TotalUsers: 1},
+		},
+		{
+			name: "a miss still counts as broadcast",
+			run: func(hub *Hub) {
+				hub.Broadcast(event.NewSystemEvent(uuid.New(), event.Ping, event.PriorityNormal, nil))
+				hub.BroadcastIfConnected(event.NewSystemEvent(uuid.New(), event.Ping, event.PriorityNormal, nil))
+			},
+			want: model.HubStats{EventsBroadcast: 2},
+		},
+		{
+			// The first event fills the session buffer, the second holds the loop in
+			// Send, the next two wait in the mailbox and the fifth is rejected.
+			name: "stalled session backs up the mailbox",
+			run: func(hub *Hub) {
+				userID := uuid.New()
+				hub.Register(NewConnector(context.Background(), userID, 1, ConnectMetadata{}))
+				for range 5 {
+					hub.Broadcast(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil))
+					synctest.Wait()
+				}
+			},
+			want: model.HubStats{
+				TotalUsers: 1, TotalConnections: 1,
+				MailboxDepthTotal: 2, MailboxDepthMax: 2, MailboxesNonEmpty: 1,
+				EventsBroadcast: 5, EventsDropped: 1, MailboxDropped: 1,
+			},
+		},
+		{
+			name: "send window expiry counts a session drop",
+			run: func(hub *Hub) {
+				userID := uuid.New()
+				hub.Register(NewConnector(context.Background(), userID, 1, ConnectMetadata{}))
+				for range 3 {
+					hub.Broadcast(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil))
+					synctest.Wait()
+				}
+				// Past the 250ms send window: the second event is shed, the third takes its place.
+				time.Sleep(300 * time.Millisecond)
+				synctest.Wait()
+			},
+			want: model.HubStats{
+				TotalUsers: 1, TotalConnections: 1,
+				EventsBroadcast: 3, EventsDropped: 1, SessionDropped: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				hub := NewHub(WithShardCount(4), WithMailboxSize(2), WithEvictionInterval(time.Hour))
+				defer hub.Shutdown()
+
+				tt.run(hub)
+				synctest.Wait()
+
+				got := hub.Stats()
+				got.Uptime = 0
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("Stats:\n got %+v\nwant %+v", got, tt.want)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// ReasonSuperseded is the DisconnectedPayload reason sent to a session replaced by a
+// newer one from the same device.
+const ReasonSuperseded = "superseded"
+
+// deviceTwin finds another session opened from conn's device, if conn declares one.
+// [DEVICE_REPLACEMENT] A restarted app must not leave its dead session attached until
+// keepalive notices; sessions without a device ID are never matched.
+// [LOCK_REQUIRED] c.mu must be held.
+func (c *Cell) deviceTwin(conn Connector) Connector {
+	deviceID := conn.Metadata().DeviceID
+	if deviceID == "" {
+		return nil
+	}
+	for id, other := range c.sessions {
+		if id != conn.GetID() && other.Metadata().DeviceID == deviceID {
+			return other
+		}
+	}
+	return nil
+}
+
+// supersede tells the replaced session why it is being dropped, then retires it.
+func (c *Cell) supersede(old Connector) {
+	old.Send(event.NewSystemEvent(c.userID, event.Disconnected, event.PriorityHigh, &model.DisconnectedPayload{
+		Reason: ReasonSuperseded,
+		Code:   "SUPERSEDED",
+	}), kickNoticeTimeout)
+	c.finishRetire(old, RetireSuperseded)
+}
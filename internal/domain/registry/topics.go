@@ -0,0 +1,218 @@
+package registry
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+var (
+	// ErrTopicKeyRequired is returned when a subscription is requested for an empty key.
+	ErrTopicKeyRequired = errors.New("registry: topic key is required")
+	// ErrTopicLimitExceeded is returned when a connection already holds the maximum number of topics.
+	ErrTopicLimitExceeded = errors.New("registry: topic subscription limit exceeded")
+)
+
+// topicSub is a single [EPHEMERAL_SUBSCRIPTION] of a connector to a topic key.
+type topicSub struct {
+	conn      Connector
+	expiresAt int64 // [UNIX_NANO] Subscription is ignored and reaped after this moment.
+}
+
+// topicIndex is the Hub's [SECONDARY_INDEX] of topic key -> subscribed connectors.
+//
+// [STRATEGY]
+// Topic traffic is high-frequency and short-lived, so it bypasses user Cells and is
+// delivered straight into each connector's buffer. The reverse index (byConn) keeps
+// the per-connection bound cheap to enforce and makes detach cleanup O(topics).
+type topicIndex struct {
+	mu     sync.RWMutex
+	byKey  map[string]map[uuid.UUID]*topicSub
+	byConn map[uuid.UUID]map[string]struct{}
+}
+
+func newTopicIndex() *topicIndex {
+	return &topicIndex{
+		byKey:  make(map[string]map[uuid.UUID]*topicSub),
+		byConn: make(map[uuid.UUID]map[string]struct{}),
+	}
+}
+
+// subscribe adds or refreshes a subscription, enforcing the per-connection bound.
+func (t *topicIndex) subscribe(conn Connector, key string, ttl time.Duration, limit int) error {
+	connID := conn.GetID()
+	expiresAt := time.Now().Add(ttl).UnixNano()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// [REFRESH] Re-subscribing extends the TTL and never counts against the bound.
+	if sub, ok := t.byKey[key][connID]; ok {
+		sub.expiresAt = expiresAt
+		return nil
+	}
+
+	keys := t.byConn[connID]
+	if limit > 0 && len(keys) >= limit {
+		return ErrTopicLimitExceeded
+	}
+
+	if keys == nil {
+		keys = make(map[string]struct{})
+		t.byConn[connID] = keys
+	}
+	keys[key] = struct{}{}
+
+	subs := t.byKey[key]
+	if subs == nil {
+		subs = make(map[uuid.UUID]*topicSub)
+		t.byKey[key] = subs
+	}
+	subs[connID] = &topicSub{conn: conn, expiresAt: expiresAt}
+
+	return nil
+}
+
+// unsubscribe removes a single subscription.
+func (t *topicIndex) unsubscribe(connID uuid.UUID, key string) {
+	t.mu.Lock()
+	t.removeLocked(connID, key)
+	t.mu.Unlock()
+}
+
+// dropConn removes every subscription held by a connection (called on detach).
+func (t *topicIndex) dropConn(connID uuid.UUID) {
+	t.mu.Lock()
+	for key := range t.byConn[connID] {
+		t.removeLocked(connID, key)
+	}
+	t.mu.Unlock()
+}
+
+func (t *topicIndex) removeLocked(connID uuid.UUID, key string) {
+	if subs, ok := t.byKey[key]; ok {
+		delete(subs, connID)
+		if len(subs) == 0 {
+			delete(t.byKey, key)
+		}
+	}
+	if keys, ok := t.byConn[connID]; ok {
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(t.byConn, connID)
+		}
+	}
+}
+
+// subscribers returns a snapshot of live connectors for the key.
+func (t *topicIndex) subscribers(key string) []Connector {
+	now := time.Now().UnixNano()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	subs := t.byKey[key]
+	if len(subs) == 0 {
+		return nil
+	}
+
+	res := make([]Connector, 0, len(subs))
+	for _, sub := range subs {
+		if sub.expiresAt > now {
+			res = append(res, sub.conn)
+		}
+	}
+	return res
+}
+
+// hasSubscribers reports whether at least one live subscription exists for the key.
+func (t *topicIndex) hasSubscribers(key string) bool {
+	now := time.Now().UnixNano()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, sub := range t.byKey[key] {
+		if sub.expiresAt > now {
+			return true
+		}
+	}
+	return false
+}
+
+// purgeExpired reaps subscriptions whose TTL has elapsed and returns their count.
+func (t *topicIndex) purgeExpired() int {
+	now := time.Now().UnixNano()
+	reaped := 0
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, subs := range t.byKey {
+		for connID, sub := range subs {
+			if sub.expiresAt <= now {
+				t.removeLocked(connID, key)
+				reaped++
+			}
+		}
+	}
+	return reaped
+}
+
+// reset drops the whole index (used by Shutdown).
+func (t *topicIndex) reset() {
+	t.mu.Lock()
+	t.byKey = make(map[string]map[uuid.UUID]*topicSub)
+	t.byConn = make(map[uuid.UUID]map[string]struct{})
+	t.mu.Unlock()
+}
+
+// DomainTopicKey is the index key of a topic within a tenant. Subscribers and the bus
+// side both go through it, so equal raw keys from different domains never meet.
+func DomainTopicKey(domainID int64, key string) string {
+	return strconv.FormatInt(domainID, 10) + "/" + key
+}
+
+// SubscribeTopic binds a connector to an [EPHEMERAL] topic key for the given TTL.
+// A non-positive TTL falls back to the default; TTLs above the maximum are clamped.
+func (h *Hub) SubscribeTopic(conn Connector, key string, ttl time.Duration) error {
+	if key == "" {
+		return ErrTopicKeyRequired
+	}
+
+	if ttl <= 0 {
+		ttl = h.config.topicDefaultTTL
+	}
+	if ttl > h.config.topicMaxTTL {
+		ttl = h.config.topicMaxTTL
+	}
+
+	return h.topics.subscribe(conn, key, ttl, h.config.maxTopicsPerConn)
+}
+
+// UnsubscribeTopic removes a connector's subscription to a topic key.
+func (h *Hub) UnsubscribeTopic(connID uuid.UUID, key string) {
+	h.topics.unsubscribe(connID, key)
+}
+
+// HasTopicSubscribers is the [LOCALITY_FILTER] for topic traffic.
+func (h *Hub) HasTopicSubscribers(key string) bool {
+	return h.topics.hasSubscribers(key)
+}
+
+// BroadcastTopic delivers an event directly to every connector subscribed to the key,
+// bypassing user Cells. It returns the number of connectors that accepted the event.
+func (h *Hub) BroadcastTopic(key string, ev event.Eventer) int {
+	delivered := 0
+	for _, conn := range h.topics.subscribers(key) {
+		// [BACKPRESSURE] Same strict window as Cell delivery; low priority is shed first.
+		if conn.Send(ev, time.Millisecond*250) {
+			delivered++
+		}
+	}
+	return delivered
+}
@@ -0,0 +1,194 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+func TestTopicSubscriptions(t *testing.T) {
+	const key = "call.transcript.42"
+
+	tests := []struct {
+		name    string
+		conns   int
+		ttl     time.Duration
+		prepare func(t *testing.T, hub *Hub, conns []Connector)
+		want    int // Connectors that receive the topic event
+		wantSub bool
+	}{
+		{
+			name:    "fan-out to every subscriber",
+			conns:   3,
+			ttl:     time.Minute,
+			want:    3,
+			wantSub: true,
+		},
+		{
+			name:  "expired subscriptions are skipped and reaped",
+			conns: 2,
+			ttl:   time.Millisecond,
+			prepare: func(t *testing.T, hub *Hub, _ []Connector) {
+				time.Sleep(5 * time.Millisecond)
+				if got := hub.topics.purgeExpired(); got != 2 {
+					t.Fatalf("reaped %d subscriptions, want 2", got)
+				}
+			},
+		},
+		{
+			name:  "explicit unsubscribe",
+			conns: 2,
+			ttl:   time.Minute,
+			prepare: func(_ *testing.T, hub *Hub, conns []Connector) {
+				hub.UnsubscribeTopic(conns[0].GetID(), key)
+			},
+			want:    1,
+			wantSub: true,
+		},
+		{
+			name:  "detach drops the subscription",
+			conns: 2,
+			ttl:   time.Minute,
+			prepare: func(_ *testing.T, hub *Hub, conns []Connector) {
+				for _, c := range conns {
+					hub.Unregister(c.GetUserID(), c.GetID())
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(WithShardCount(1), WithEvictionInterval(time.Hour), WithTopicTTL(tt.ttl, time.Hour))
+			t.Cleanup(hub.Shutdown)
+
+			conns := make([]Connector, tt.conns)
+			for i := range conns {
+				conns[i] = NewConnector(context.Background(), uuid.New(), 64, ConnectMetadata{})
+				hub.Register(conns[i])
+				if err := hub.SubscribeTopic(conns[i], key, 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.prepare != nil {
+				tt.prepare(t, hub, conns)
+			}
+
+			if got := hub.HasTopicSubscribers(key); got != tt.wantSub {
+				t.Fatalf("HasTopicSubscribers = %v, want %v", got, tt.wantSub)
+			}
+			if got := hub.BroadcastTopic(key, event.NewTopicEvent(key, []byte(`{}`))); got != tt.want {
+				t.Fatalf("BroadcastTopic delivered to %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopicSubscriptionBound(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		keys    []string
+		wantErr []error
+	}{
+		{
+			name:    "within the bound",
+			limit:   2,
+			keys:    []string{"a", "b"},
+			wantErr: []error{nil, nil},
+		},
+		{
+			name:    "over the bound",
+			limit:   2,
+			keys:    []string{"a", "b", "c"},
+			wantErr: []error{nil, nil, ErrTopicLimitExceeded},
+		},
+		{
+			name:    "refresh does not count",
+			limit:   1,
+			keys:    []string{"a", "a"},
+			wantErr: []error{nil, nil},
+		},
+		{
+			name:    "empty key",
+			limit:   1,
+			keys:    []string{""},
+			wantErr: []error{ErrTopicKeyRequired},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(WithShardCount(1), WithEvictionInterval(time.Hour), WithMaxTopicsPerConnection(tt.limit))
+			t.Cleanup(hub.Shutdown)
+
+			conn := NewConnector(context.Background(), uuid.New(), 64, ConnectMetadata{})
+			hub.Register(conn)
+			for i, key := range tt.keys {
+				if err := hub.SubscribeTopic(conn, key, time.Minute); !errors.Is(err, tt.wantErr[i]) {
+					t.Fatalf("subscribe %q: got %v, want %v", key, err, tt.wantErr[i])
+				}
+			}
+		})
+	}
+}
+
+// TestTopicDeliveryLeavesUserDeliveryAlone checks that topic traffic goes only to
+// subscribers and user-addressed events still reach every session.
+func TestTopicDeliveryLeavesUserDeliveryAlone(t *testing.T) {
+	tests := []struct {
+		name       string
+		subscribed bool
+	}{
+		{name: "subscribed session", subscribed: true},
+		{name: "unsubscribed session", subscribed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(WithShardCount(1), WithEvictionInterval(time.Hour))
+			t.Cleanup(hub.Shutdown)
+
+			userID := uuid.New()
+			conn := NewConnector(context.Background(), userID, 64, ConnectMetadata{})
+			hub.Register(conn)
+			if tt.subscribed {
+				if err := hub.SubscribeTopic(conn, "queue.7", time.Minute); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			hub.BroadcastTopic("queue.7", event.NewTopicEvent("queue.7", []byte(`{}`)))
+			user := event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil)
+			if res := hub.Broadcast(user); !res.Queued {
+				t.Fatalf("user broadcast not queued: %+v", res)
+			}
+
+			var kinds []event.EventKind
+			timeout := time.After(time.Second)
+			for len(kinds) == 0 || kinds[len(kinds)-1] != event.Ping {
+				select {
+				case ev := <-conn.Recv():
+					kinds = append(kinds, ev.GetKind())
+				case <-timeout:
+					t.Fatalf("user event never arrived, got %v", kinds)
+				}
+			}
+
+			wantTopic := 0
+			if tt.subscribed {
+				wantTopic = 1
+			}
+			gotTopic := 0
+			for _, k := range kinds {
+				if k == event.TopicMessage {
+					gotTopic++
+				}
+			}
+			if gotTopic != wantTopic {
+				t.Fatalf("received %d topic events, want %d (%v)", gotTopic, wantTopic, kinds)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package watch tracks which local connections asked to follow which contacts'
+// presence, so status changes reach only the users who care.
+package watch
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Registry is a node-local, in-memory index of presence watches.
+//
+// [CONNECTION_SCOPED] Watches belong to a connection, not to a user: they are added
+// when a session subscribes and evicted with Unwatch when it unsubscribes, so a user
+// with several sessions keeps watching until the last one that asked goes away.
+type Registry struct {
+	mu        sync.RWMutex
+	byContact map[uuid.UUID]map[uuid.UUID]uuid.UUID // contactID -> connID -> watcher userID
+	byConn    map[uuid.UUID][]uuid.UUID             // connID -> contactIDs, for eviction
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		byContact: make(map[uuid.UUID]map[uuid.UUID]uuid.UUID),
+		byConn:    make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+// Watch makes connection connID of userID follow contacts, replacing any earlier set.
+func (r *Registry) Watch(userID, connID uuid.UUID, contacts []uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.unwatchLocked(connID)
+	if len(contacts) == 0 {
+		return
+	}
+
+	kept := make([]uuid.UUID, 0, len(contacts))
+	for _, contactID := range contacts {
+		conns, ok := r.byContact[contactID]
+		if !ok {
+			conns = make(map[uuid.UUID]uuid.UUID)
+			r.byContact[contactID] = conns
+		}
+		if _, dup := conns[connID]; dup {
+			continue
+		}
+		conns[connID] = userID
+		kept = append(kept, contactID)
+	}
+	r.byConn[connID] = kept
+}
+
+// Unwatch evicts every watch held by connID.
+func (r *Registry) Unwatch(connID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unwatchLocked(connID)
+}
+
+func (r *Registry) unwatchLocked(connID uuid.UUID) {
+	for _, contactID := range r.byConn[connID] {
+		conns := r.byContact[contactID]
+		delete(conns, connID)
+		if len(conns) == 0 {
+			delete(r.byContact, contactID)
+		}
+	}
+	delete(r.byConn, connID)
+}
+
+// WatchersOf returns the distinct users with a connection following contactID.
+func (r *Registry) WatchersOf(contactID uuid.UUID) []uuid.UUID {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conns := r.byContact[contactID]
+	if len(conns) == 0 {
+		return nil
+	}
+	seen := make(map[uuid.UUID]struct{}, len(conns))
+	res := make([]uuid.UUID, 0, len(conns))
+	for _, userID := range conns {
+		if _, ok := seen[userID]; ok {
+			continue
+		}
+		seen[userID] = struct{}{}
+		res = append(res, userID)
+	}
+	return res
+}
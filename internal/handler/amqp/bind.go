@@ -1,24 +1,61 @@
 package amqp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/adapter/pubsub"
 	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
 )
 
 // DomainHandler defines the functional signature for business logic.
 type DomainHandler[T any] func(ctx context.Context, userID uuid.UUID, payload *T) (event.Eventer, error)
 
+// BindOption adjusts how Bind routes a message.
+type BindOption func(*bindConfig)
+
+type bindConfig struct {
+	recipientSegment int  // Routing key segment holding the recipient; -1 takes the first UUID
+	noDedup          bool // Repeats are meaningful; see WithoutDedup
+}
+
+// WithRecipientSegment reads the recipient from a fixed, 0-based routing key segment,
+// for topics whose key carries more than one user UUID.
+func WithRecipientSegment(pos int) BindOption {
+	return func(c *bindConfig) { c.recipientSegment = pos }
+}
+
+// WithoutDedup delivers every copy, for ephemeral kinds (typing) whose identical
+// repeats are refreshes rather than redeliveries.
+func WithoutDedup() BindOption {
+	return func(c *bindConfig) { c.noDedup = true }
+}
+
 // [INFRASTRUCTURE_BRIDGE]
 // Bind connects Watermill to Domain logic, handling Panic Recovery, Locality, and Fan-out.
-func Bind[T any](h *MessageHandler, fn DomainHandler[T]) message.NoPublishHandlerFunc {
+func Bind[T any](h *MessageHandler, fn DomainHandler[T], opts ...BindOption) message.NoPublishHandlerFunc {
+	cfg := bindConfig{recipientSegment: -1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	scope := reflect.TypeFor[T]().String()
+
 	return func(msg *message.Message) error {
+		consumedAt := event.Monotime()
+
 		// [PANIC_RECOVERY]
 		// Safely handle runtime panics to keep the consumer alive.
 		defer func() {
@@ -32,7 +69,7 @@ func Bind[T any](h *MessageHandler, fn DomainHandler[T]) message.NoPublishHandle
 
 		// [IDENTIFICATION]
 		// Extract recipient UUID from metadata for routing decisions.
-		userID, ok := resolveUserID(msg)
+		userID, ok := resolveUserID(msg, cfg.recipientSegment)
 		if !ok {
 			h.logger.Warn("ROUTING_FAILED: recipient_missing", "msg_id", msg.UUID)
 			return nil // ACK: Invalid routing is a terminal state.
@@ -40,7 +77,9 @@ func Bind[T any](h *MessageHandler, fn DomainHandler[T]) message.NoPublishHandle
 
 		// [LOCALITY_FILTER]
 		// Distributed scaling: process only if the target user is connected to THIS node.
-		if !h.hub.IsConnected(userID) {
+		// Advisory only: it saves the decode; delivery itself re-checks atomically below.
+		// Observer nodes plug in AlwaysProcess here.
+		if !h.locality.IsConnected(userID) {
 			return nil // ACK: Handled by another instance.
 		}
 
@@ -51,37 +90,540 @@ func Bind[T any](h *MessageHandler, fn DomainHandler[T]) message.NoPublishHandle
 			return nil // ACK: Poison Pill protection.
 		}
 
+		// [DATA_RESIDENCY]
+		// Every node consumes its own queue copy, so the allowed region's nodes still
+		// process this event; here it is a hard block. A local session for a restricted
+		// tenant should have been rejected at Subscribe, so reaching this point is a misconfig.
+		if scoped, ok := any(payload).(domainScoped); ok {
+			if err := h.residency.Check(scoped.GetDomainID()); err != nil {
+				h.residency.ReportViolation(msg.Context(), scoped.GetDomainID())
+				h.logger.Error("RESIDENCY_VIOLATION_BLOCKED", "err", err, "msg_id", msg.UUID, "user_id", userID)
+				return nil // ACK: This node must never deliver it.
+			}
+		}
+
+		// [IDEMPOTENCY]
+		// A broker redelivery of a message this recipient already got is ACKed before enrichment,
+		// unless only its re-publish failed: then the sessions are skipped and just the export retried.
+		var ref dedupRef
+		exportOnly := false
+		if !cfg.noDedup {
+			ref = newDedupRef(scope, msg, payload)
+			switch h.dedup.claim(ref, userID) {
+			case claimDuplicate:
+				h.logger.Debug("DUPLICATE_SUPPRESSED", "msg_id", msg.UUID, "dedup_id", ref.id, "user_id", userID)
+				return nil // ACK: Already delivered.
+			case claimExport:
+				exportOnly = true
+			}
+		}
+
 		// [EXECUTION]
 		// Domain logic execution with enriched context (TraceID).
 		ev, err := fn(msg.Context(), userID, payload)
 		if err != nil {
+			if exportOnly {
+				h.dedup.oweExport(ref, userID)
+			} else {
+				h.dedup.release(ref, userID)
+			}
 			return err // NACK: Business failure triggers Retry policy.
 		}
-
 		if ev == nil {
 			return nil
 		}
+		stampStages(ev, consumedAt)
+
+		// [FAN_OUT_DISPATCH] Local sessions first, then the re-publish.
+		if !exportOnly {
+			if err := h.deliverLocal(msg, domainOf(payload), userID, ev); err != nil {
+				h.dedup.release(ref, userID)
+				return err // NACK: Nothing reached the recipient.
+			}
+		}
+		if err := h.export(msg, userID, ev); err != nil {
+			h.dedup.oweExport(ref, userID)
+			return err // NACK: The recipient has it; the redelivery retries just the export.
+		}
+		return nil
+	}
+}
+
+// [STAGE_TIMING] Coarse boundaries for slow-delivery exemplars (see service.SlowDeliveryTracker).
+func stampStages(ev event.Eventer, consumedAt int64) {
+	if timed, ok := ev.(event.Timed); ok {
+		now := event.Monotime() // Enrichment ends where the Hub handoff begins.
+		st := timed.GetStageTimes()
+		st.Consume, st.Enrich, st.Broadcast = consumedAt, now, now
+	}
+}
+
+// [GLOBAL_DISPATCH]
+// export re-publishes ev for multi-node synchronization (RabbitMQ).
+// [OUTBOX] A publish the broker refuses is parked by the dispatcher, so msg is ACKed.
+func (h *MessageHandler) export(msg *message.Message, userID uuid.UUID, ev event.Eventer) error {
+	if _, ok := ev.(event.Exportable); !ok {
+		return nil
+	}
+	err := h.dispatcher.PublishWithTimeout(msg.Context(), ev, 0)
+	if errors.Is(err, pubsub.ErrPublishAbandoned) {
+		// ACK: the dispatcher still owns the publish; a redelivery would export it twice.
+		h.logger.Warn("GLOBAL_DISPATCH_UNCONFIRMED", "err", err, "msg_id", msg.UUID, "user_id", userID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("GLOBAL_DISPATCH_FAILED: %w", err)
+	}
+	return nil
+}
+
+// deliverLocal pushes ev to the local sessions (WebSockets/gRPC) of userID,
+// unless the tenant is in a maintenance pause.
+func (h *MessageHandler) deliverLocal(msg *message.Message, domainID int64, userID uuid.UUID, ev event.Eventer) error {
+	if h.gate.Hold(domainID, ev) {
+		h.logger.Debug("LOCAL_DELIVERY_HELD: domain_paused", "msg_id", msg.UUID, "user_id", userID)
+	} else {
+		switch res := h.local.BroadcastIfConnected(ev); res.Reason {
+		case registry.ReasonNotRegistered:
+			// ACK: the recipient left between the locality check and the push.
+			h.logger.Debug("LOCAL_DELIVERY_SKIPPED: recipient_gone", "msg_id", msg.UUID, "user_id", userID)
+		case registry.ReasonMailboxFull:
+			// NACK: [BACKPRESSURE] let the Retry policy redeliver once the Cell drains.
+			return fmt.Errorf("LOCAL_DELIVERY_FAILED: %w (user_id=%s, sessions=%d)", registry.ErrMailboxFull, userID, res.Sessions)
+		}
+	}
+	return nil
+}
+
+// domainScoped is implemented by payloads that carry their tenant.
+type domainScoped interface {
+	GetDomainID() int64
+}
+
+// domainOf returns the payload's tenant, or 0 when it carries none.
+func domainOf(payload any) int64 {
+	if scoped, ok := payload.(domainScoped); ok {
+		return scoped.GetDomainID()
+	}
+	return 0
+}
+
+// domainPolicy is the payload of im_system.domain.policy.v1.
+type domainPolicy struct {
+	DomainID int64    `json:"domain_id"`
+	Regions  []string `json:"regions"` // Empty lifts the restriction
+}
+
+// [POLICY_BRIDGE]
+// BindDomainPolicy applies runtime residency policy updates on every node.
+func BindDomainPolicy(h *MessageHandler) message.NoPublishHandlerFunc {
+	return func(msg *message.Message) error {
+		var p domainPolicy
+		if err := json.Unmarshal(msg.Payload, &p); err != nil || p.DomainID == 0 {
+			h.logger.Error("DECODE_FAILED", "err", err, "msg_id", msg.UUID)
+			return nil // ACK: Poison Pill protection.
+		}
+
+		h.residency.SetDomainRegions(p.DomainID, p.Regions)
+		h.logger.Info("DOMAIN_POLICY_APPLIED", "domain_id", p.DomainID, "regions", p.Regions)
+		return nil
+	}
+}
+
+// domainPause is the payload of im_system.domain.pause.v1.
+type domainPause struct {
+	DomainID int64     `json:"domain_id"`
+	Until    time.Time `json:"until"`
+	Policy   string    `json:"policy"` // buffer | defer
+	Lift     bool      `json:"lift"`   // Ends the pause early
+}
+
+// pauseController is implemented by gates that support maintenance pauses.
+type pauseController interface {
+	Pause(domainID int64, until time.Time, policy service.PausePolicy) error
+	Resume(domainID int64) bool
+}
+
+// [MAINTENANCE_BRIDGE]
+// BindDomainPause starts or lifts a tenant maintenance pause on every node.
+func BindDomainPause(h *MessageHandler) message.NoPublishHandlerFunc {
+	return func(msg *message.Message) error {
+		var p domainPause
+		if err := json.Unmarshal(msg.Payload, &p); err != nil || p.DomainID == 0 {
+			h.logger.Error("DECODE_FAILED", "err", err, "msg_id", msg.UUID)
+			return nil // ACK: Poison Pill protection.
+		}
+
+		ctl, ok := h.gate.(pauseController)
+		if !ok {
+			return nil // ACK: This node holds nothing back.
+		}
+
+		if p.Lift {
+			ctl.Resume(p.DomainID)
+			return nil
+		}
+		if err := ctl.Pause(p.DomainID, p.Until, service.PausePolicy(p.Policy)); err != nil {
+			h.logger.Error("DOMAIN_PAUSE_REJECTED", "err", err, "domain_id", p.DomainID, "msg_id", msg.UUID)
+		}
+		return nil // ACK: A rejected pause is a terminal state.
+	}
+}
+
+// userStatusScope and topicScope name the node-level dedup claims of those consumers.
+const (
+	userStatusScope = "user.status"
+	topicScope      = "topic"
+)
+
+// [PRESENCE_BRIDGE]
+// BindUserStatus delivers a contact's status change to its local watchers.
+// The routing key names the contact, not a recipient, so Bind's locality filter does
+// not apply; the watch index only ever holds sessions on THIS node.
+func BindUserStatus(h *MessageHandler) message.NoPublishHandlerFunc {
+	return func(msg *message.Message) error {
+		var raw dto.UserStatusV1
+		if err := json.Unmarshal(msg.Payload, &raw); err != nil || raw.ContactID == "" {
+			h.logger.Error("DECODE_FAILED", "err", err, "msg_id", msg.UUID)
+			return nil // ACK: Poison Pill protection.
+		}
+
+		if err := h.residency.Check(raw.GetDomainID()); err != nil {
+			h.residency.ReportViolation(msg.Context(), raw.GetDomainID())
+			h.logger.Error("RESIDENCY_VIOLATION_BLOCKED", "err", err, "msg_id", msg.UUID)
+			return nil // ACK: This node must never deliver it.
+		}
+
+		// [IDEMPOTENCY] The watchers are resolved here, so the node claims the status once.
+		ref := newDedupRef(userStatusScope, msg, &raw)
+		if h.dedup.claim(ref, uuid.Nil) == claimDuplicate {
+			h.logger.Debug("DUPLICATE_SUPPRESSED", "msg_id", msg.UUID, "dedup_id", ref.id)
+			return nil // ACK: Already delivered.
+		}
+
+		// [BEST_EFFORT] A newer status supersedes this one, so a full mailbox is not retried.
+		for _, ev := range h.OnStatusChangedV1(msg.Context(), &raw) {
+			if h.gate.Hold(raw.GetDomainID(), ev) {
+				continue
+			}
+			h.local.BroadcastIfConnected(ev)
+		}
+		return nil
+	}
+}
+
+// recipientsKey marks thread-level message.created payloads; see BindMessageCreated.
+var recipientsKey = []byte(`"recipients"`)
+
+// messageCreatedScope matches Bind's scope for dto.MessageV1, so a per-recipient copy
+// and a thread-level copy of one message dedupe against each other.
+var messageCreatedScope = reflect.TypeFor[dto.MessageV1]().String()
+
+// [GROUP_FAN_OUT]
+// BindMessageCreated routes per-recipient message.created events through Bind and
+// expands thread-level ones into one event per local member. A thread-level event
+// either carries the member list or is addressed to the group itself, in which case
+// the members come from the group directory (see groupAddressed).
+// The sender and target peers are enriched once; every leg shares the wire cache,
+// so each transport marshals the message once per node.
+func BindMessageCreated(h *MessageHandler) message.NoPublishHandlerFunc {
+	single := Bind(h, h.OnMessageCreatedV1)
+
+	return func(msg *message.Message) error {
+		// [FAST_PATH] Per-recipient events keep Bind's locality check before the decode.
+		lookup := false
+		if !bytes.Contains(msg.Payload, recipientsKey) {
+			if !h.groupAddressed(msg) {
+				return single(msg)
+			}
+			lookup = true
+		}
+
+		consumedAt := event.Monotime()
+
+		defer func() {
+			if r := recover(); r != nil {
+				h.logger.Error("PANIC_RECOVERED",
+					"err", r,
+					"stack", string(debug.Stack()),
+					"msg_id", msg.UUID)
+			}
+		}()
+
+		var raw dto.MessageV1
+		if err := json.Unmarshal(msg.Payload, &raw); err != nil {
+			h.logger.Error("DECODE_FAILED", "err", err, "msg_id", msg.UUID)
+			return nil // ACK: Poison Pill protection.
+		}
+		if len(raw.Recipients) == 0 && !lookup {
+			return single(msg)
+		}
+
+		if err := h.residency.Check(raw.GetDomainID()); err != nil {
+			h.residency.ReportViolation(msg.Context(), raw.GetDomainID())
+			h.logger.Error("RESIDENCY_VIOLATION_BLOCKED", "err", err, "msg_id", msg.UUID)
+			return nil // ACK: This node must never deliver it.
+		}
+
+		members, err := h.threadMembers(msg.Context(), &raw)
+		if err != nil {
+			h.logger.Error("GROUP_MEMBERS_LOOKUP_FAILED", "err", err, "msg_id", msg.UUID, "group_id", raw.To.ID)
+			return err // NACK: The directory may be back on retry.
+		}
+
+		// [LOCALITY_FILTER] Every node sees the whole member list; each keeps its own members.
+		local := h.localRecipients(members)
+		if len(local) == 0 {
+			return nil // ACK: Handled by other instances.
+		}
+
+		// [IDEMPOTENCY] Members that already got this message are dropped from a redelivery;
+		// those whose leg was pushed but not re-published are only exported.
+		ref := newDedupRef(messageCreatedScope, msg, &raw)
+		fresh, exportOnly := h.dedup.claimAll(ref, local)
+		if len(fresh) == 0 && len(exportOnly) == 0 {
+			h.logger.Debug("DUPLICATE_SUPPRESSED", "msg_id", msg.UUID, "dedup_id", ref.id)
+			return nil // ACK: Already delivered.
+		}
+		return h.fanOut(msg, &raw, len(members), ref, fresh, exportOnly, consumedAt)
+	}
+}
+
+// groupAddressed reports whether a message.created without a member list is routed
+// to a group thread instead of a user, so its members must be looked up.
+// A connected user is never a group, which keeps the common case free of a decode;
+// observer nodes report everyone connected and rely on the recipients list.
+func (h *MessageHandler) groupAddressed(msg *message.Message) bool {
+	if h.members == nil {
+		return false
+	}
+	id, ok := resolveUserID(msg, -1)
+	if !ok || h.locality.IsConnected(id) || !bytes.Contains(msg.Payload, []byte(id.String())) {
+		return false
+	}
+
+	var peek struct {
+		To dto.PeerDTO `json:"to"`
+	}
+	if err := json.Unmarshal(msg.Payload, &peek); err != nil {
+		return false // Bind reports the decode failure.
+	}
+	return model.PeerType(peek.To.Type) == model.PeerGroup && peek.To.ID == id.String()
+}
+
+// threadMembers returns the carried member list or, without one, asks the group directory.
+func (h *MessageHandler) threadMembers(ctx context.Context, raw *dto.MessageV1) ([]uuid.UUID, error) {
+	if len(raw.Recipients) > 0 {
+		members := make([]uuid.UUID, 0, len(raw.Recipients))
+		for _, id := range raw.Recipients {
+			if uid, err := uuid.Parse(id); err == nil {
+				members = append(members, uid)
+			}
+		}
+		return members, nil
+	}
+
+	groupID, err := uuid.Parse(raw.To.ID)
+	if err != nil {
+		return nil, nil // Not a group we can look up; nothing to deliver.
+	}
+	return h.members.ListGroupMembers(ctx, groupID, raw.DomainID)
+}
+
+// fanOut enriches a thread-level message once and delivers one leg per fresh member,
+// then re-publishes the legs of every member that has one, exportOnly included.
+// It settles the dedup claims: undelivered members are released, delivered ones keep
+// their claim and, if the re-publish fails, owe just the export.
+func (h *MessageHandler) fanOut(msg *message.Message, raw *dto.MessageV1, members int, ref dedupRef, fresh, exportOnly []uuid.UUID, consumedAt int64) error {
+	recipients := append(fresh[:len(fresh):len(fresh)], exportOnly...)
+	ev, err := h.OnMessageCreatedV1(msg.Context(), recipients[0], raw)
+	if err != nil {
+		h.dedup.release(ref, fresh...)
+		h.dedup.oweExport(ref, exportOnly...)
+		return err // NACK: Business failure triggers Retry policy.
+	}
+	if ev == nil {
+		return nil
+	}
+	stampStages(ev, consumedAt)
+
+	legs := make([]event.Eventer, len(recipients))
+	for i, userID := range recipients {
+		legs[i] = ev
+		if i > 0 {
+			legs[i] = readdress(ev, userID)
+		}
+	}
+
+	// [BACKPRESSURE] A full mailbox stops the fan-out: the members from there on are
+	// released for the redelivery, the ones before it are exported now.
+	var errLocal error
+	delivered := len(fresh)
+	for i, userID := range fresh {
+		if errLocal = h.deliverLocal(msg, raw.GetDomainID(), userID, legs[i]); errLocal != nil {
+			h.dedup.release(ref, fresh[i:]...)
+			delivered = i
+			break
+		}
+	}
+	pushed := append(recipients[:delivered:delivered], exportOnly...)
+	legs = append(legs[:delivered:delivered], legs[len(fresh):]...)
+
+	exports := make([]event.Eventer, 0, len(legs))
+	for _, leg := range legs {
+		if _, ok := leg.(event.Exportable); ok {
+			exports = append(exports, leg)
+		}
+	}
+
+	// [BATCH_EXPORT] Legs share a routing key, so the re-publish is one broker call.
+	// A failed batch owes every pushed leg's export; the redelivery retries only those.
+	if err := h.dispatcher.PublishBatch(msg.Context(), exports); err != nil {
+		var batch *pubsub.BatchError
+		if errors.As(err, &batch) {
+			h.logger.Error("GLOBAL_DISPATCH_PARTIAL", "msg_id", msg.UUID, "failed", batch.Failed)
+		}
+		h.dedup.oweExport(ref, pushed...)
+		return errors.Join(errLocal, fmt.Errorf("GLOBAL_DISPATCH_FAILED: %w", err))
+	}
+	if errLocal != nil {
+		return errLocal
+	}
+
+	h.logger.Debug("GROUP_FAN_OUT", "msg_id", msg.UUID, "members", members, "local", len(recipients))
+	return nil
+}
+
+// localRecipients returns the distinct, non-nil member IDs connected to THIS node.
+func (h *MessageHandler) localRecipients(ids []uuid.UUID) []uuid.UUID {
+	res := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, uid := range ids {
+		if uid == uuid.Nil {
+			continue
+		}
+		if _, dup := seen[uid]; dup {
+			continue
+		}
+		seen[uid] = struct{}{}
+		if h.locality.IsConnected(uid) {
+			res = append(res, uid)
+		}
+	}
+	return res
+}
+
+// sharedCloner is implemented by events whose encodings do not depend on the recipient.
+type sharedCloner interface {
+	CloneShared(userID uuid.UUID) event.Eventer
+}
+
+// readdress re-targets a fan-out leg, keeping the wire cache when the event allows it.
+func readdress(ev event.Eventer, userID uuid.UUID) event.Eventer {
+	if c, ok := ev.(sharedCloner); ok {
+		return c.CloneShared(userID)
+	}
+	return ev.Clone(userID)
+}
+
+// peerPrimer is implemented by enrichers that can store a profile pushed by the bus.
+type peerPrimer interface {
+	Prime(peer model.Peer, domainID int32)
+}
+
+// BindContactChanged keeps this node's peer cache coherent with the contact directory.
+// Updates that carry a full profile replace the cached entry; anything else evicts it.
+func BindContactChanged(h *MessageHandler, deleted bool) message.NoPublishHandlerFunc {
+	return func(msg *message.Message) error {
+		var raw dto.ContactChangedV1
+		if err := json.Unmarshal(msg.Payload, &raw); err != nil || raw.ContactID == "" {
+			h.logger.Error("DECODE_FAILED", "err", err, "msg_id", msg.UUID)
+			return nil // ACK: Poison Pill protection.
+		}
+
+		peer := raw.ToPeer()
+		if peer.ID == uuid.Nil {
+			h.logger.Error("DECODE_FAILED", "err", "invalid contact_id", "msg_id", msg.UUID)
+			return nil
+		}
 
-		// [FAN_OUT_DISPATCH]
-		// 1. Local delivery (WebSockets/gRPC).
-		h.hub.Broadcast(ev)
+		if p, ok := h.enricher.(peerPrimer); ok && !deleted {
+			p.Prime(peer, raw.DomainID)
+			return nil
+		}
+		h.enricher.Invalidate(peer.ID, raw.DomainID)
+		return nil
+	}
+}
 
-		// 2. Global delivery (RabbitMQ) for multi-node synchronization.
-		if _, ok := ev.(event.Exportable); ok {
-			if err := h.dispatcher.Publish(msg.Context(), ev); err != nil {
-				return fmt.Errorf("GLOBAL_DISPATCH_FAILED: %w", err)
+// [EPHEMERAL_BRIDGE]
+// BindTopic connects an ephemeral-topic exchange to the Hub's topic index.
+// The routing key is the topic key, so nodes without a local subscriber skip the message untouched.
+// [TENANT_ISOLATION] The key is looked up under the domain it names, as sessions subscribe it.
+func BindTopic(h *MessageHandler) message.NoPublishHandlerFunc {
+	return func(msg *message.Message) error {
+		defer func() {
+			if r := recover(); r != nil {
+				h.logger.Error("PANIC_RECOVERED",
+					"err", r,
+					"stack", string(debug.Stack()),
+					"msg_id", msg.UUID)
 			}
+		}()
+
+		key := routingKey(msg)
+		if key == "" {
+			h.logger.Warn("ROUTING_FAILED: topic_key_missing", "msg_id", msg.UUID)
+			return nil // ACK: Invalid routing is a terminal state.
 		}
 
+		domainID, err := service.TopicDomain(key)
+		if err != nil {
+			h.logger.Warn("ROUTING_FAILED: topic_domain_missing", "msg_id", msg.UUID, "topic", key)
+			return nil // ACK: A key outside every domain has no subscribers.
+		}
+		scoped := registry.DomainTopicKey(domainID, key)
+
+		// [LOCALITY_FILTER]
+		// Process only if at least one connection on THIS node subscribed to the key.
+		if !h.locality.HasTopicSubscribers(scoped) {
+			return nil
+		}
+
+		// [IDEMPOTENCY] A redelivered chunk would reach every subscriber again.
+		ref := newDedupRef(topicScope, msg, nil)
+		if h.dedup.claim(ref, uuid.Nil) == claimDuplicate {
+			h.logger.Debug("DUPLICATE_SUPPRESSED", "msg_id", msg.UUID, "dedup_id", ref.id, "topic", key)
+			return nil // ACK: Already delivered.
+		}
+
+		// [DIRECT_DELIVERY] Ephemeral: never re-published, never retried.
+		h.local.BroadcastTopic(scoped, event.NewTopicEvent(key, msg.Payload))
 		return nil
 	}
 }
 
-func resolveUserID(msg *message.Message) (uuid.UUID, bool) {
+func routingKey(msg *message.Message) string {
 	rk := msg.Metadata.Get("x-routing-key")
 	if rk == "" {
 		rk = msg.Metadata.Get("routing_key")
 	}
+	return rk
+}
+
+// resolveUserID reads the recipient from segment pos of the routing key,
+// or from its first UUID segment when pos is negative.
+func resolveUserID(msg *message.Message, pos int) (uuid.UUID, bool) {
+	rk := routingKey(msg)
+
+	if pos >= 0 {
+		parts := strings.Split(rk, ".")
+		if pos >= len(parts) {
+			return uuid.Nil, false
+		}
+		uid, err := uuid.Parse(parts[pos])
+		return uid, err == nil
+	}
 
 	for part := range strings.SplitSeq(rk, ".") {
 		if uid, err := uuid.Parse(part); err == nil {
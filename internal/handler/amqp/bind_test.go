@@ -0,0 +1,509 @@
+package amqp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/adapter/pubsub"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
+)
+
+// fakeLocality reports the given users as connected to this node.
+type fakeLocality map[uuid.UUID]bool
+
+func (f fakeLocality) IsConnected(id uuid.UUID) bool { return f[id] }
+func (fakeLocality) HasTopicSubscribers(string) bool { return false }
+
+// recordingDelivery accepts every event and remembers it.
+type recordingDelivery struct {
+	mu     sync.Mutex
+	events []event.Eventer
+}
+
+func (d *recordingDelivery) BroadcastIfConnected(ev event.Eventer) registry.BroadcastResult {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = append(d.events, ev)
+	return registry.BroadcastResult{UserWasConnected: true, Queued: true, Reason: registry.ReasonAccepted, Sessions: 1}
+}
+
+func (*recordingDelivery) BroadcastTopic(string, event.Eventer) int { return 0 }
+
+// recordingDispatcher remembers every exported event, or fails with err.
+type recordingDispatcher struct {
+	mu      sync.Mutex
+	err     error
+	batches [][]event.Eventer
+	singles []event.Eventer
+}
+
+func (d *recordingDispatcher) Publish(ctx context.Context, ev event.Eventer) error {
+	return d.PublishWithTimeout(ctx, ev, 0)
+}
+
+func (d *recordingDispatcher) PublishWithTimeout(_ context.Context, ev event.Eventer, _ time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	d.singles = append(d.singles, ev)
+	return nil
+}
+
+func (d *recordingDispatcher) PublishBatch(_ context.Context, events []event.Eventer) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	d.batches = append(d.batches, events)
+	return nil
+}
+
+func (*recordingDispatcher) Publisher() message.Publisher { return nil }
+
+// fakeMembers is a group directory holding one group.
+type fakeMembers struct {
+	groupID uuid.UUID
+	members []uuid.UUID
+	err     error
+	calls   int
+}
+
+func (f *fakeMembers) ListGroupMembers(_ context.Context, groupID uuid.UUID, _ int32) ([]uuid.UUID, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if groupID != f.groupID {
+		return nil, nil
+	}
+	return f.members, nil
+}
+
+// fanOutFixture is a handler over fakes with a counting enrichment step.
+type fanOutFixture struct {
+	h         *MessageHandler
+	delivered *recordingDelivery
+	exported  *recordingDispatcher
+	enriched  int
+}
+
+func newFanOutFixture(connected fakeLocality, members service.GroupMembers) *fanOutFixture {
+	f := &fanOutFixture{delivered: new(recordingDelivery), exported: new(recordingDispatcher)}
+
+	pipeline := service.NewEnrichmentPipeline()
+	pipeline.AddStep(event.MessageCreated, func(_ context.Context, ev event.Eventer, _ int32) (event.Eventer, error) {
+		f.enriched++
+		return ev, nil
+	})
+
+	f.h = NewMessageHandler(
+		connected, f.delivered, OpenGate{},
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		pipeline, f.exported,
+		service.NewResidencyPolicy(&config.Config{}),
+		NoWatchers{}, nil, nil, members,
+	)
+	return f
+}
+
+func newUUIDs(n int) []uuid.UUID {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	return ids
+}
+
+func createdMessage(t *testing.T, routingKey string, raw dto.MessageV1) *message.Message {
+	t.Helper()
+	payload, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := message.NewMessage(uuid.NewString(), payload)
+	msg.Metadata.Set("x-routing-key", routingKey)
+	return msg
+}
+
+func TestBindMessageCreatedFanOut(t *testing.T) {
+	sender := uuid.New()
+	groupID := uuid.New()
+	members := newUUIDs(50)
+	local := members[10:13]
+
+	connected := fakeLocality{}
+	for _, id := range local {
+		connected[id] = true
+	}
+
+	recipients := make([]string, len(members))
+	for i, id := range members {
+		recipients[i] = id.String()
+	}
+
+	base := dto.MessageV1{
+		MessageID:  uuid.NewString(),
+		ThreadID:   groupID.String(),
+		DomainID:   1,
+		From:       dto.PeerDTO{ID: sender.String(), Type: int(model.PeerUser)},
+		To:         dto.PeerDTO{ID: groupID.String(), Type: int(model.PeerGroup)},
+		Body:       "hello",
+		OccurredAt: "2026-01-02T03:04:05Z",
+	}
+	groupKey := "im_message.1." + groupID.String() + ".message.created.v1"
+
+	tests := []struct {
+		name        string
+		recipients  []string
+		directory   *fakeMembers
+		wantLocal   []uuid.UUID
+		wantLookups int
+		wantErr     bool
+	}{
+		{
+			name:       "carried recipients",
+			recipients: recipients,
+			wantLocal:  local,
+		},
+		{
+			name:        "carried recipients skip the directory",
+			recipients:  recipients,
+			directory:   &fakeMembers{groupID: groupID, members: members},
+			wantLocal:   local,
+			wantLookups: 0,
+		},
+		{
+			name:        "members looked up for a group-addressed message",
+			directory:   &fakeMembers{groupID: groupID, members: members},
+			wantLocal:   local,
+			wantLookups: 1,
+		},
+		{
+			name:        "directory failure is retried",
+			directory:   &fakeMembers{groupID: groupID, err: errors.New("unavailable")},
+			wantLookups: 1,
+			wantErr:     true,
+		},
+		{
+			name: "without a directory the group is not a recipient",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dir service.GroupMembers
+			if tt.directory != nil {
+				dir = tt.directory
+			}
+			f := newFanOutFixture(connected, dir)
+
+			raw := base
+			raw.Recipients = tt.recipients
+			err := BindMessageCreated(f.h)(createdMessage(t, groupKey, raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.directory != nil && tt.directory.calls != tt.wantLookups {
+				t.Errorf("lookups = %d, want %d", tt.directory.calls, tt.wantLookups)
+			}
+
+			if len(f.delivered.events) != len(tt.wantLocal) {
+				t.Fatalf("delivered %d events, want %d", len(f.delivered.events), len(tt.wantLocal))
+			}
+			if len(tt.wantLocal) == 0 {
+				if f.enriched != 0 {
+					t.Errorf("enriched %d times without local members", f.enriched)
+				}
+				return
+			}
+
+			// The sender is enriched once for the whole thread.
+			if f.enriched != 1 {
+				t.Errorf("enriched %d times, want 1", f.enriched)
+			}
+
+			// One leg per local member, all sharing one wire cache.
+			shared := f.delivered.events[0].Encoded()
+			got := make(map[uuid.UUID]bool, len(f.delivered.events))
+			for _, ev := range f.delivered.events {
+				got[ev.GetUserID()] = true
+				if ev.Encoded() != shared {
+					t.Errorf("leg for %s has its own wire cache", ev.GetUserID())
+				}
+			}
+			for _, id := range tt.wantLocal {
+				if !got[id] {
+					t.Errorf("member %s not delivered", id)
+				}
+			}
+
+			// The re-publish is one batch with every leg.
+			if len(f.exported.batches) != 1 || len(f.exported.batches[0]) != len(tt.wantLocal) {
+				t.Errorf("exports = %v, want one batch of %d", f.exported.batches, len(tt.wantLocal))
+			}
+		})
+	}
+}
+
+func TestBindMessageCreatedPerRecipient(t *testing.T) {
+	user := uuid.New()
+	groupID := uuid.New()
+	raw := dto.MessageV1{
+		MessageID:  uuid.NewString(),
+		ThreadID:   groupID.String(),
+		DomainID:   1,
+		From:       dto.PeerDTO{ID: uuid.NewString(), Type: int(model.PeerUser)},
+		To:         dto.PeerDTO{ID: groupID.String(), Type: int(model.PeerGroup)},
+		OccurredAt: "2026-01-02T03:04:05Z",
+	}
+	key := "im_message.1." + user.String() + ".message.created.v1"
+
+	tests := []struct {
+		name      string
+		connected fakeLocality
+		want      int
+	}{
+		{"local recipient", fakeLocality{user: true}, 1},
+		{"remote recipient", fakeLocality{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := &fakeMembers{groupID: groupID, members: newUUIDs(5)}
+			f := newFanOutFixture(tt.connected, dir)
+
+			if err := BindMessageCreated(f.h)(createdMessage(t, key, raw)); err != nil {
+				t.Fatal(err)
+			}
+			if dir.calls != 0 {
+				t.Errorf("per-recipient event looked members up %d times", dir.calls)
+			}
+			if len(f.delivered.events) != tt.want {
+				t.Fatalf("delivered %d events, want %d", len(f.delivered.events), tt.want)
+			}
+			if tt.want > 0 && f.delivered.events[0].GetUserID() != user {
+				t.Errorf("delivered to %s, want %s", f.delivered.events[0].GetUserID(), user)
+			}
+		})
+	}
+}
+
+// TestBindExportFailure sends a message whose re-publish fails with err, then
+// redelivers it once the broker is back.
+func TestBindExportFailure(t *testing.T) {
+	user := uuid.New()
+	key := "im_message.1." + user.String() + ".message.deleted.v1"
+	payload, err := json.Marshal(dto.MessageDeletedV1{MessageID: uuid.NewString(), ThreadID: uuid.NewString(), DomainID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		err          error
+		wantNACK     bool
+		wantExported int // Across both deliveries
+	}{
+		{name: "exported, redelivery suppressed", err: nil, wantExported: 1},
+		{name: "abandoned publish is left to the dispatcher", err: pubsub.ErrPublishTimeout, wantExported: 0},
+		{name: "refused publish is retried alone", err: errors.New("broker unavailable"), wantNACK: true, wantExported: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFanOutFixture(fakeLocality{user: true}, nil)
+			f.h.dedup = newTestDeduplicator()
+			f.exported.err = tt.err
+			handle := Bind(f.h, f.h.OnMessageDeletedV1)
+
+			msg := message.NewMessage(uuid.NewString(), payload)
+			msg.Metadata.Set("x-routing-key", key)
+			if err := handle(msg); (err != nil) != tt.wantNACK {
+				t.Fatalf("err: got %v, want NACK %v", err, tt.wantNACK)
+			}
+
+			f.exported.err = nil
+			if err := handle(msg.Copy()); err != nil {
+				t.Fatalf("redelivery: %v", err)
+			}
+			if len(f.delivered.events) != 1 {
+				t.Fatalf("delivered %d events, want 1", len(f.delivered.events))
+			}
+			if len(f.exported.singles) != tt.wantExported {
+				t.Fatalf("exported %d events, want %d", len(f.exported.singles), tt.wantExported)
+			}
+		})
+	}
+}
+
+// TestExportRoutingKeyCarriesDomain is the end-to-end regression for the tenant in the
+// export routing key: domain_id travels from the inbound payload through ToDomain and
+// the event into the key, on both the per-recipient and the thread-level path.
+func TestExportRoutingKeyCarriesDomain(t *testing.T) {
+	user := uuid.New()
+	peer := dto.PeerDTO{ID: uuid.NewString(), Type: 1}
+	tests := []struct {
+		name       string
+		domainID   int32
+		recipients []string
+	}{
+		{name: "per-recipient", domainID: 7},
+		{name: "largest domain id", domainID: math.MaxInt32},
+		{name: "thread-level", domainID: 42, recipients: []string{user.String()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFanOutFixture(fakeLocality{user: true}, nil)
+			key := fmt.Sprintf("im_message.%d.%s.message.created.v1", tt.domainID, user)
+			if tt.recipients != nil {
+				key = fmt.Sprintf("im_message.%d.message.created.v1", tt.domainID)
+			}
+			msg := createdMessage(t, key, dto.MessageV1{
+				MessageID: uuid.NewString(), ThreadID: uuid.NewString(), DomainID: tt.domainID,
+				From: peer, To: peer, OccurredAt: "2026-01-02T03:04:05Z", Recipients: tt.recipients,
+			})
+			if err := BindMessageCreated(f.h)(msg); err != nil {
+				t.Fatal(err)
+			}
+
+			exported := f.exported.singles
+			for _, batch := range f.exported.batches {
+				exported = append(exported, batch...)
+			}
+			if len(exported) != 1 {
+				t.Fatalf("exported %d events, want 1", len(exported))
+			}
+			want := fmt.Sprintf("im_delivery.v1.%d.", tt.domainID)
+			if got := exported[0].(event.Exportable).GetRoutingKey(); !strings.HasPrefix(got, want) {
+				t.Fatalf("routing key %q, want prefix %q", got, want)
+			}
+		})
+	}
+}
+
+// TestBindMessageRead consumes a read receipt through the ON_MSG_READ binding and
+// checks that it reaches the sender's sessions, not the reader's, and is ACKed.
+func TestBindMessageRead(t *testing.T) {
+	reader, sender := uuid.New(), uuid.New()
+	payload, err := json.Marshal(dto.MessageReadV1{
+		ThreadID:          uuid.NewString(),
+		DomainID:          1,
+		Reader:            dto.PeerDTO{ID: reader.String(), Type: 1},
+		LastReadMessageID: uuid.NewString(),
+		OccurredAt:        "2026-01-02T03:04:05Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		key       string
+		connected fakeLocality
+		want      int // Receipts delivered to the sender
+	}{
+		{name: "sender connected", key: "im_message.1." + reader.String() + "." + sender.String() + ".message.read.v1", connected: fakeLocality{sender: true}, want: 1},
+		{name: "only the reader connected", key: "im_message.1." + reader.String() + "." + sender.String() + ".message.read.v1", connected: fakeLocality{reader: true}},
+		{name: "sender segment missing", key: "im_message.1." + reader.String() + ".message.read.v1", connected: fakeLocality{reader: true, sender: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFanOutFixture(tt.connected, nil)
+			handle := Bind(f.h, f.h.OnMessageReadV1, WithRecipientSegment(MessageReadSenderSegment))
+
+			msg := message.NewMessage(uuid.NewString(), payload)
+			msg.Metadata.Set("x-routing-key", tt.key)
+			if err := handle(msg); err != nil {
+				t.Fatalf("NACKed: %v", err)
+			}
+
+			if len(f.delivered.events) != tt.want {
+				t.Fatalf("delivered %d events, want %d", len(f.delivered.events), tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+			ev := f.delivered.events[0]
+			if ev.GetUserID() != sender || ev.GetKind() != event.ReadReceipt {
+				t.Fatalf("delivered %v to %s, want a read receipt to %s", ev.GetKind(), ev.GetUserID(), sender)
+			}
+			read, ok := ev.GetPayload().(*model.MessageRead)
+			if !ok || read.Reader.ID != reader {
+				t.Fatalf("payload = %#v, want the reader %s", ev.GetPayload(), reader)
+			}
+			if len(f.exported.singles) != 1 {
+				t.Fatalf("exported %d events, want 1", len(f.exported.singles))
+			}
+		})
+	}
+}
+
+// TestBindTopicTenantIsolation checks that a transcript chunk only reaches sessions that
+// subscribed its key under the domain named in the routing key.
+func TestBindTopicTenantIsolation(t *testing.T) {
+	const subscribed = "im_call.transcript.1.call-1"
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{name: "subscribed key", key: subscribed, want: true},
+		{name: "same call id in another domain", key: "im_call.transcript.2.call-1"},
+		{name: "key without a domain", key: "im_call.transcript.call-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := registry.NewHub()
+			t.Cleanup(hub.Shutdown)
+			conn := registry.NewConnector(t.Context(), uuid.New(), 8, registry.ConnectMetadata{})
+			if err := hub.SubscribeTopic(conn, registry.DomainTopicKey(1, subscribed), 0); err != nil {
+				t.Fatal(err)
+			}
+			// An unscoped subscription to the bare key must never match a chunk.
+			if err := hub.SubscribeTopic(conn, "im_call.transcript.call-1", 0); err != nil {
+				t.Fatal(err)
+			}
+
+			f := newFanOutFixture(fakeLocality{}, nil)
+			f.h.locality, f.h.local = hub, hub
+			f.h.dedup = newTestDeduplicator()
+			msg := message.NewMessage(uuid.NewString(), []byte(`{"seq":1}`))
+			msg.Metadata.Set("x-routing-key", tt.key)
+			if err := BindTopic(f.h)(msg); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case ev := <-conn.Recv():
+				if !tt.want {
+					t.Fatalf("chunk for %s delivered", tt.key)
+				}
+				if ev.GetPayload().(*model.TopicPayload).Key != tt.key {
+					t.Fatalf("delivered %+v", ev.GetPayload())
+				}
+			default:
+				if tt.want {
+					t.Fatal("chunk not delivered to the subscriber")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package amqp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/webitel/im-delivery-service/config"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var duplicatesSuppressed, _ = meter.Int64Counter(
+	"im_delivery_duplicates_suppressed_total",
+	metric.WithDescription("Redelivered messages ACKed without delivery because the recipient already got them"),
+)
+
+// idempotent is implemented by payloads with a business identity that survives an
+// upstream re-publish (a new envelope for the same message).
+type idempotent interface {
+	DedupKey() string
+}
+
+// dedupRef names one delivery: the payload kind and its identity within that kind.
+// The kind keeps an edit or a reaction from colliding with the message it refers to.
+type dedupRef struct {
+	scope string
+	id    string
+}
+
+// newDedupRef identifies msg by the payload's DedupKey, else by the broker envelope's
+// UUID (kept on redelivery), else by a digest of the payload.
+func newDedupRef(scope string, msg *message.Message, payload any) dedupRef {
+	if k, ok := payload.(idempotent); ok {
+		if id := k.DedupKey(); id != "" {
+			return dedupRef{scope, id}
+		}
+	}
+	if msg.UUID != "" {
+		return dedupRef{scope, msg.UUID}
+	}
+	sum := sha256.Sum256(msg.Payload)
+	return dedupRef{scope, hex.EncodeToString(sum[:16])}
+}
+
+type dedupKey struct {
+	ref    dedupRef
+	userID uuid.UUID
+}
+
+// claimResult is what a claim found for one {delivery, user_id} pair.
+type claimResult uint8
+
+const (
+	claimFresh     claimResult = iota // New pair: deliver and export
+	claimDuplicate                    // Already handled: ACK
+	claimExport                       // Delivered, but the re-publish failed: export only
+)
+
+// Deduplicator remembers recently delivered {delivery, user_id} pairs.
+//
+// [STRATEGY]
+// RabbitMQ redelivers whatever was unacked when a channel drops, so a message the Hub
+// already broadcast can arrive again. A pair is claimed before enrichment and released
+// only if the local push fails, so only the retry policy's redeliveries get through.
+// A pair whose push was accepted but whose re-publish failed stays claimed with the
+// export owed: its redelivery skips the sessions and retries just the export.
+// Events fanned out by the node itself (presence, topics) are claimed for uuid.Nil.
+// [SCOPE] Every client-bound kind is covered except typing, which is ephemeral and
+// legitimately repeats; policy, pause and contact events set state and are idempotent.
+// [BOUNDED] The LRU holds at most pubsub.dedup.size pairs, each for pubsub.dedup.ttl.
+type Deduplicator struct {
+	mu   sync.Mutex                     // Makes the check-and-claim atomic across the router's handlers
+	seen *expirable.LRU[dedupKey, bool] // Value: the export is still owed
+}
+
+// NewDeduplicator returns nil when pubsub.dedup.size is 0; a nil Deduplicator claims everything.
+func NewDeduplicator(cfg *config.Config) *Deduplicator {
+	c := cfg.Pubsub.Dedup
+	if c.Size <= 0 {
+		return nil
+	}
+	return &Deduplicator{
+		seen: expirable.NewLRU[dedupKey, bool](c.Size, nil, c.TTL),
+	}
+}
+
+// claim remembers ref for userID and reports what was there before. An owed export
+// is handed to this caller, so concurrent redeliveries do not both retry it.
+func (d *Deduplicator) claim(ref dedupRef, userID uuid.UUID) claimResult {
+	if d == nil || ref.id == "" {
+		return claimFresh
+	}
+	k := dedupKey{ref, userID}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	owed, ok := d.seen.Get(k)
+	switch {
+	case !ok:
+		d.seen.Add(k, false)
+		return claimFresh
+	case owed:
+		d.seen.Add(k, false)
+		return claimExport
+	}
+	duplicatesSuppressed.Add(context.Background(), 1)
+	return claimDuplicate
+}
+
+// claimAll splits the recipients into those for which ref is new and those that
+// only owe the export; the rest already got it.
+func (d *Deduplicator) claimAll(ref dedupRef, userIDs []uuid.UUID) (fresh, export []uuid.UUID) {
+	if d == nil || ref.id == "" {
+		return userIDs, nil
+	}
+	for _, id := range userIDs {
+		switch d.claim(ref, id) {
+		case claimFresh:
+			fresh = append(fresh, id)
+		case claimExport:
+			export = append(export, id)
+		}
+	}
+	return fresh, export
+}
+
+// release forgets claims whose local push failed, so the retry is not mistaken for a duplicate.
+func (d *Deduplicator) release(ref dedupRef, userIDs ...uuid.UUID) {
+	if d == nil || ref.id == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, id := range userIDs {
+		d.seen.Remove(dedupKey{ref, id})
+	}
+}
+
+// oweExport keeps claims whose local push was accepted but whose re-publish failed,
+// so the retry exports without pushing to the sessions again.
+func (d *Deduplicator) oweExport(ref dedupRef, userIDs ...uuid.UUID) {
+	if d == nil || ref.id == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, id := range userIDs {
+		d.seen.Add(dedupKey{ref, id}, true)
+	}
+}
@@ -0,0 +1,347 @@
+package amqp
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
+)
+
+func newTestDeduplicator() *Deduplicator {
+	cfg := &config.Config{}
+	cfg.Pubsub.Dedup = config.DedupConfig{Size: 1000, TTL: time.Minute}
+	return NewDeduplicator(cfg)
+}
+
+// watchersOf makes every contact watched by the given local users.
+type watchersOf []uuid.UUID
+
+func (w watchersOf) WatchersOf(uuid.UUID) []uuid.UUID { return w }
+
+// subscribedLocality reports every topic as subscribed on this node.
+type subscribedLocality struct{ fakeLocality }
+
+func (subscribedLocality) HasTopicSubscribers(string) bool { return true }
+
+func TestNewDedupRef(t *testing.T) {
+	edit := func(at string) *dto.MessageUpdatedV1 {
+		return &dto.MessageUpdatedV1{MessageV1: dto.MessageV1{MessageID: "m1"}, EditedAt: at}
+	}
+	tests := []struct {
+		name    string
+		uuid    string
+		payload any
+		want    string // Empty expects a payload digest
+	}{
+		{name: "message id wins over the envelope", uuid: "env", payload: &dto.MessageV1{MessageID: "m1"}, want: "m1"},
+		{name: "edits are told apart by edited_at", uuid: "env", payload: edit("2026-01-01T00:00:00Z"), want: "m1@2026-01-01T00:00:00Z"},
+		{name: "edit without edited_at uses the envelope", uuid: "env", payload: edit(""), want: "env"},
+		{name: "other kinds use the envelope", uuid: "env", payload: &dto.MessageDeletedV1{MessageID: "m1"}, want: "env"},
+		{name: "without an envelope id the payload is digested", payload: &dto.MessageDeletedV1{MessageID: "m1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := message.NewMessage(tt.uuid, []byte(`{"message_id":"m1"}`))
+			ref := newDedupRef("scope", msg, tt.payload)
+			if ref.scope != "scope" {
+				t.Fatalf("scope: got %q", ref.scope)
+			}
+			if tt.want != "" {
+				if ref.id != tt.want {
+					t.Fatalf("id: got %q, want %q", ref.id, tt.want)
+				}
+				return
+			}
+			same := newDedupRef("scope", message.NewMessage("", []byte(`{"message_id":"m1"}`)), tt.payload)
+			other := newDedupRef("scope", message.NewMessage("", []byte(`{"message_id":"m2"}`)), tt.payload)
+			if ref.id == "" || ref.id != same.id || ref.id == other.id {
+				t.Fatalf("digest: got %q, same payload %q, other payload %q", ref.id, same.id, other.id)
+			}
+		})
+	}
+}
+
+func TestBindSuppressesRedeliveries(t *testing.T) {
+	user := uuid.New()
+	key := "im_message.1." + user.String() + ".message.x.v1"
+	peer := dto.PeerDTO{ID: uuid.NewString(), Type: 1}
+	created := dto.MessageV1{
+		MessageID: uuid.NewString(), ThreadID: uuid.NewString(), DomainID: 1,
+		From: peer, To: peer, OccurredAt: "2026-01-02T03:04:05Z",
+	}
+	edited := func(at string) dto.MessageUpdatedV1 { return dto.MessageUpdatedV1{MessageV1: created, EditedAt: at} }
+
+	handlers := map[string]func(h *MessageHandler) message.NoPublishHandlerFunc{
+		"created": BindMessageCreated,
+		"updated": func(h *MessageHandler) message.NoPublishHandlerFunc { return Bind(h, h.OnMessageUpdatedV1) },
+		"deleted": func(h *MessageHandler) message.NoPublishHandlerFunc { return Bind(h, h.OnMessageDeletedV1) },
+		"deleted, no dedup": func(h *MessageHandler) message.NoPublishHandlerFunc {
+			return Bind(h, h.OnMessageDeletedV1, WithoutDedup())
+		},
+		"reaction": func(h *MessageHandler) message.NoPublishHandlerFunc { return Bind(h, h.OnReactionV1) },
+	}
+
+	type delivery struct {
+		handler string
+		uuid    string // Broker envelope ID; equal IDs model a redelivery
+		payload any
+	}
+	tests := []struct {
+		name       string
+		deliveries []delivery
+		want       int
+	}{
+		{
+			name:       "created redelivered",
+			deliveries: []delivery{{"created", "a", created}, {"created", "a", created}},
+			want:       1,
+		},
+		{
+			name:       "created re-published upstream",
+			deliveries: []delivery{{"created", "a", created}, {"created", "b", created}},
+			want:       1,
+		},
+		{
+			name:       "edit after creation is delivered",
+			deliveries: []delivery{{"created", "a", created}, {"updated", "b", edited("2026-01-02T03:05:00Z")}},
+			want:       2,
+		},
+		{
+			name: "successive edits are delivered, a redelivered one is not",
+			deliveries: []delivery{
+				{"updated", "a", edited("2026-01-02T03:05:00Z")},
+				{"updated", "b", edited("2026-01-02T03:06:00Z")},
+				{"updated", "b", edited("2026-01-02T03:06:00Z")},
+			},
+			want: 2,
+		},
+		{
+			name: "deletion redelivered",
+			deliveries: []delivery{
+				{"deleted", "a", dto.MessageDeletedV1{MessageID: created.MessageID, ThreadID: created.ThreadID, DomainID: 1}},
+				{"deleted", "a", dto.MessageDeletedV1{MessageID: created.MessageID, ThreadID: created.ThreadID, DomainID: 1}},
+			},
+			want: 1,
+		},
+		{
+			name: "reaction redelivered",
+			deliveries: []delivery{
+				{"reaction", "a", dto.ReactionV1{MessageID: created.MessageID, ThreadID: created.ThreadID, DomainID: 1, Emoji: "+1", Actor: peer, Added: true}},
+				{"reaction", "a", dto.ReactionV1{MessageID: created.MessageID, ThreadID: created.ThreadID, DomainID: 1, Emoji: "+1", Actor: peer, Added: true}},
+			},
+			want: 1,
+		},
+		{
+			name: "opted-out kinds deliver every copy",
+			deliveries: []delivery{
+				{"deleted, no dedup", "a", dto.MessageDeletedV1{MessageID: created.MessageID, ThreadID: created.ThreadID, DomainID: 1}},
+				{"deleted, no dedup", "a", dto.MessageDeletedV1{MessageID: created.MessageID, ThreadID: created.ThreadID, DomainID: 1}},
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFanOutFixture(fakeLocality{user: true}, nil)
+			f.h.dedup = newTestDeduplicator()
+			bound := make(map[string]message.NoPublishHandlerFunc, len(handlers))
+			for name, bind := range handlers {
+				bound[name] = bind(f.h)
+			}
+
+			for _, d := range tt.deliveries {
+				payload, err := json.Marshal(d.payload)
+				if err != nil {
+					t.Fatal(err)
+				}
+				msg := message.NewMessage(d.uuid, payload)
+				msg.Metadata.Set("x-routing-key", key)
+				if err := bound[d.handler](msg); err != nil {
+					t.Fatalf("%s: %v", d.handler, err)
+				}
+			}
+			if got := len(f.delivered.events); got != tt.want {
+				t.Fatalf("delivered %d events, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// fullMailboxes refuses pushes to the listed users while they are marked full.
+type fullMailboxes struct {
+	*recordingDelivery
+	full map[uuid.UUID]bool
+}
+
+func (d *fullMailboxes) BroadcastIfConnected(ev event.Eventer) registry.BroadcastResult {
+	if d.full[ev.GetUserID()] {
+		return registry.BroadcastResult{UserWasConnected: true, Reason: registry.ReasonMailboxFull, Sessions: 1}
+	}
+	return d.recordingDelivery.BroadcastIfConnected(ev)
+}
+
+// TestFanOutRedeliveryAfterFailure fails the first delivery of a thread-level message,
+// then redelivers it once the mailbox drained and the broker is back: every member
+// gets the message exactly once and only the missing exports are retried.
+func TestFanOutRedeliveryAfterFailure(t *testing.T) {
+	local := newUUIDs(3)
+	connected := fakeLocality{}
+	recipients := make([]string, len(local))
+	for i, id := range local {
+		connected[id] = true
+		recipients[i] = id.String()
+	}
+	groupID := uuid.New()
+	raw := dto.MessageV1{
+		MessageID:  uuid.NewString(),
+		ThreadID:   groupID.String(),
+		DomainID:   1,
+		From:       dto.PeerDTO{ID: uuid.NewString(), Type: int(model.PeerUser)},
+		To:         dto.PeerDTO{ID: groupID.String(), Type: int(model.PeerGroup)},
+		Recipients: recipients,
+		OccurredAt: "2026-01-02T03:04:05Z",
+	}
+	key := "im_message.1." + groupID.String() + ".message.created.v1"
+
+	tests := []struct {
+		name        string
+		full        uuid.UUID // Member whose mailbox is full on the first delivery
+		exportErr   error
+		wantExports []int // Legs per export batch, across both deliveries
+	}{
+		{name: "full mailbox", full: local[1], wantExports: []int{1, 2}},
+		{name: "refused export", exportErr: errors.New("broker unavailable"), wantExports: []int{3}},
+		{name: "both", full: local[1], exportErr: errors.New("broker unavailable"), wantExports: []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFanOutFixture(connected, nil)
+			mailboxes := &fullMailboxes{recordingDelivery: f.delivered, full: map[uuid.UUID]bool{tt.full: true}}
+			f.h.local = mailboxes
+			f.h.dedup = newTestDeduplicator()
+			f.exported.err = tt.exportErr
+			handle := BindMessageCreated(f.h)
+
+			msg := createdMessage(t, key, raw)
+			if err := handle(msg); err == nil {
+				t.Fatal("first delivery: want NACK")
+			}
+
+			mailboxes.full, f.exported.err = nil, nil
+			if err := handle(msg.Copy()); err != nil {
+				t.Fatalf("redelivery: %v", err)
+			}
+
+			got := make(map[uuid.UUID]int, len(local))
+			for _, ev := range f.delivered.events {
+				got[ev.GetUserID()]++
+			}
+			for _, id := range local {
+				if got[id] != 1 {
+					t.Errorf("member %s got %d copies, want 1", id, got[id])
+				}
+			}
+			exports := make([]int, len(f.exported.batches))
+			for i, b := range f.exported.batches {
+				exports[i] = len(b)
+			}
+			if !slices.Equal(exports, tt.wantExports) {
+				t.Fatalf("export batches: got %v legs, want %v", exports, tt.wantExports)
+			}
+		})
+	}
+}
+
+// envelope is one broker delivery; equal IDs model a redelivery.
+type envelope struct {
+	uuid    string
+	payload []byte
+}
+
+func TestNodeLevelConsumersSuppressRedeliveries(t *testing.T) {
+	watchers := watchersOf{uuid.New(), uuid.New()}
+	status := func(s string) []byte {
+		b, _ := json.Marshal(dto.UserStatusV1{ContactID: uuid.NewString(), DomainID: 1, Status: s})
+		return b
+	}
+	online := status("online")
+
+	tests := []struct {
+		name   string
+		bind   func(h *MessageHandler) message.NoPublishHandlerFunc
+		key    string
+		copies []envelope
+		want   int // Events delivered across all copies
+	}{
+		{
+			name:   "status redelivered",
+			bind:   BindUserStatus,
+			key:    "im_system.1.user.status.v1",
+			copies: []envelope{{"a", online}, {"a", online}},
+			want:   len(watchers),
+		},
+		{
+			name:   "distinct statuses",
+			bind:   BindUserStatus,
+			key:    "im_system.1.user.status.v1",
+			copies: []envelope{{"a", online}, {"b", status("away")}},
+			want:   2 * len(watchers),
+		},
+		{
+			name:   "transcript chunk redelivered",
+			bind:   BindTopic,
+			key:    "im_call.transcript.1.call-1",
+			copies: []envelope{{"a", []byte(`{"seq":1}`)}, {"a", []byte(`{"seq":1}`)}},
+			want:   1,
+		},
+		{
+			name:   "transcript chunks without envelope ids",
+			bind:   BindTopic,
+			key:    "im_call.transcript.1.call-1",
+			copies: []envelope{{"", []byte(`{"seq":1}`)}, {"", []byte(`{"seq":2}`)}, {"", []byte(`{"seq":2}`)}},
+			want:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFanOutFixture(fakeLocality{}, nil)
+			f.h.locality = subscribedLocality{}
+			f.h.watchers = watchers
+			f.h.dedup = newTestDeduplicator()
+			topics := &topicRecorder{recordingDelivery: f.delivered}
+			f.h.local = topics
+
+			handle := tt.bind(f.h)
+			for _, c := range tt.copies {
+				msg := message.NewMessage(c.uuid, c.payload)
+				msg.Metadata.Set("x-routing-key", tt.key)
+				if err := handle(msg); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := len(f.delivered.events) + topics.broadcasts; got != tt.want {
+				t.Fatalf("delivered %d events, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// topicRecorder counts topic broadcasts on top of per-user deliveries.
+type topicRecorder struct {
+	*recordingDelivery
+	broadcasts int
+}
+
+func (r *topicRecorder) BroadcastTopic(string, event.Eventer) int {
+	r.broadcasts++
+	return 1
+}
@@ -2,6 +2,7 @@ package amqp
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/webitel/im-delivery-service/internal/domain/event"
@@ -11,26 +12,93 @@ import (
 // [ON_MESSAGE_CREATED]
 // Handles message enrichment and prepares it for distribution.
 func (h *MessageHandler) OnMessageCreatedV1(ctx context.Context, userID uuid.UUID, raw *dto.MessageV1) (event.Eventer, error) {
+	// [EVENT_TRANSFORMATION]
+	// Convert DTO to a domain event carrying the bare peers.
+	ev := event.NewMessageV1Event(raw.ToDomain(), userID, raw.From.ToDomain(), raw.To.ToDomain())
+
 	// [ENRICHMENT]
-	// Fetch profile details for From/To entities from external services.
-	from, to, err := h.enricher.ResolvePeers(ctx, raw.From.ToDomain(), raw.To.ToDomain(), raw.DomainID)
+	// Registered steps (peer profiles, ...) prepare it for WebSocket/gRPC broadcast.
+	enriched, err := h.enrichment.Run(ctx, ev, raw.DomainID)
 	if err != nil {
-		h.logger.Error("PEER_ENRICHMENT_FAILED", "err", err, "msg_id", raw.MessageID)
+		h.logger.Error("ENRICHMENT_FAILED", "err", err, "msg_id", raw.MessageID)
 		return nil, err // Returns err to trigger retry
 	}
+	return enriched, nil
+}
 
-	// [EVENT_TRANSFORMATION]
-	// Convert DTO to enriched domain event ready for WebSocket/gRPC broadcast.
-	return event.NewMessageV1Event(raw.ToDomain(), userID, from, to), nil
+// [ON_MESSAGE_UPDATED]
+// Edits replace the client's copy wholesale, so peers are enriched exactly as on creation.
+func (h *MessageHandler) OnMessageUpdatedV1(ctx context.Context, userID uuid.UUID, raw *dto.MessageUpdatedV1) (event.Eventer, error) {
+	ev := event.NewMessageUpdatedEvent(raw.ToDomain(), userID, raw.From.ToDomain(), raw.To.ToDomain())
+
+	enriched, err := h.enrichment.Run(ctx, ev, raw.DomainID)
+	if err != nil {
+		h.logger.Error("ENRICHMENT_FAILED", "err", err, "msg_id", raw.MessageID)
+		return nil, err // Returns err to trigger retry
+	}
+	return enriched, nil
 }
 
 // [ON_MESSAGE_DELETED]
-func (h *MessageHandler) OnMessageDeletedV1(ctx context.Context, uid uuid.UUID, raw *any) (event.Eventer, error) {
-	h.logger.Debug("MOCK_DELETE_HANDLED", "user_id", uid)
-	return nil, nil
+// Tombstones carry no peers, so there is nothing to enrich; Bind handles locality,
+// local broadcast and the re-publish exactly as for created messages.
+func (h *MessageHandler) OnMessageDeletedV1(ctx context.Context, userID uuid.UUID, raw *dto.MessageDeletedV1) (event.Eventer, error) {
+	return event.NewMessageDeletedEvent(raw.ToDomain(), userID), nil
+}
+
+// [ON_MESSAGE_REACTION]
+// The actor is enriched so clients can name who reacted; bursts on one message need
+// no batching here, as the Cell drains them together.
+func (h *MessageHandler) OnReactionV1(ctx context.Context, userID uuid.UUID, raw *dto.ReactionV1) (event.Eventer, error) {
+	ev := event.NewReactionEvent(raw.ToDomain(), userID)
+
+	enriched, err := h.enrichment.Run(ctx, ev, raw.DomainID)
+	if err != nil {
+		h.logger.Error("ENRICHMENT_FAILED", "err", err, "msg_id", raw.MessageID)
+		return nil, err // Returns err to trigger retry
+	}
+	return enriched, nil
+}
+
+// [ON_MESSAGE_READ]
+// userID is the sender (see MessageReadSenderSegment); unknown or remote senders were
+// already ACKed by Bind's locality filter.
+func (h *MessageHandler) OnMessageReadV1(ctx context.Context, userID uuid.UUID, raw *dto.MessageReadV1) (event.Eventer, error) {
+	return event.NewReadReceiptEvent(raw.ToDomain(), userID), nil
+}
+
+// [ON_THREAD_TYPING]
+// Indicators are shed rather than retried: expired or over-rate ones are ACKed without
+// delivery, and the event is not Exportable, so Bind never re-publishes it.
+func (h *MessageHandler) OnTypingV1(ctx context.Context, userID uuid.UUID, raw *dto.TypingV1) (event.Eventer, error) {
+	p := raw.ToDomain()
+	now := time.Now()
+	if p.ExpiresAt == 0 {
+		p.ExpiresAt = now.Add(typingDefaultTTL).UnixMilli()
+	}
+	// [STALENESS] Also covers NACK redeliveries that outlived the indicator.
+	if p.ExpiresAt <= now.UnixMilli() {
+		return nil, nil
+	}
+	if !h.typing.Allow(userID, p.ThreadID) {
+		return nil, nil
+	}
+	return event.NewTypingEvent(p, userID, now.UnixMilli()), nil
 }
 
 // [ON_STATUS_CHANGED]
-func (h *MessageHandler) OnStatusChangedV1(ctx context.Context, uid uuid.UUID, raw *any) (event.Eventer, error) {
-	return nil, nil
+// One change fans out to every local user watching the contact; see BindUserStatus.
+func (h *MessageHandler) OnStatusChangedV1(ctx context.Context, raw *dto.UserStatusV1) []event.Eventer {
+	p := raw.ToDomain()
+	if !p.Status.Valid() {
+		h.logger.Warn("USER_STATUS_UNKNOWN", "status", raw.Status, "contact_id", raw.ContactID)
+		return nil
+	}
+
+	watchers := h.watchers.WatchersOf(p.ContactID)
+	res := make([]event.Eventer, 0, len(watchers))
+	for _, w := range watchers {
+		res = append(res, event.NewPresenceStatusEvent(p, w))
+	}
+	return res
 }
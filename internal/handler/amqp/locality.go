@@ -0,0 +1,67 @@
+package amqp
+
+import (
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/domain/watch"
+)
+
+// LocalityChecker decides whether this node must process an event.
+type LocalityChecker interface {
+	IsConnected(userID uuid.UUID) bool
+	HasTopicSubscribers(key string) bool
+}
+
+// LocalDelivery hands processed events to sessions held by this node.
+type LocalDelivery interface {
+	BroadcastIfConnected(ev event.Eventer) registry.BroadcastResult
+	BroadcastTopic(key string, ev event.Eventer) int
+}
+
+// DeliveryGate may hold an event back from local delivery (domain maintenance pauses).
+type DeliveryGate interface {
+	Hold(domainID int64, ev event.Eventer) bool
+}
+
+// PresenceWatchers resolves the local users following a contact's status.
+type PresenceWatchers interface {
+	WatchersOf(contactID uuid.UUID) []uuid.UUID
+}
+
+// Interface guards
+var (
+	_ LocalityChecker  = (registry.Hubber)(nil)
+	_ LocalDelivery    = (registry.Hubber)(nil)
+	_ LocalityChecker  = AlwaysProcess{}
+	_ LocalDelivery    = NoDelivery{}
+	_ DeliveryGate     = OpenGate{}
+	_ PresenceWatchers = (*watch.Registry)(nil)
+	_ PresenceWatchers = NoWatchers{}
+)
+
+// OpenGate never holds anything; observer nodes deliver nothing to pause.
+type OpenGate struct{}
+
+func (OpenGate) Hold(int64, event.Eventer) bool { return false }
+
+// AlwaysProcess is the observer-node locality: every user event is processed here.
+// Ephemeral topics have no subscribers without client sessions.
+type AlwaysProcess struct{}
+
+func (AlwaysProcess) IsConnected(uuid.UUID) bool      { return true }
+func (AlwaysProcess) HasTopicSubscribers(string) bool { return false }
+
+// NoDelivery is the observer-node delivery: events only reach the export sinks.
+type NoDelivery struct{}
+
+func (NoDelivery) BroadcastIfConnected(event.Eventer) registry.BroadcastResult {
+	return registry.BroadcastResult{Reason: registry.ReasonNotRegistered}
+}
+
+func (NoDelivery) BroadcastTopic(string, event.Eventer) int { return 0 }
+
+// NoWatchers is the observer-node index: no sessions, so nobody watches.
+type NoWatchers struct{}
+
+func (NoWatchers) WatchersOf(uuid.UUID) []uuid.UUID { return nil }
@@ -3,11 +3,35 @@ package amqp
 import (
 	"context"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	pubsubadapter "github.com/webitel/im-delivery-service/internal/adapter/pubsub"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	meter = otel.Meter("github.com/webitel/im-delivery-service/internal/handler/amqp")
+
+	handlerDuration, _ = meter.Float64Histogram(
+		"im_delivery_amqp_handler_duration_seconds",
+		metric.WithDescription("Total handler time per invocation, including retries"),
+		metric.WithUnit("s"),
+	)
+	handlerErrors, _ = meter.Int64Counter(
+		"im_delivery_amqp_handler_errors_total",
+		metric.WithDescription("Handler invocations that ended with an error"),
+	)
+	handlerRetries, _ = meter.Int64Counter(
+		"im_delivery_amqp_handler_retries_total",
+		metric.WithDescription("Retry attempts performed by the retry middleware"),
+	)
 )
 
 // [TRACE_ID_MIDDLEWARE]
@@ -20,8 +44,7 @@ func TraceIDMiddleware(h message.HandlerFunc) message.HandlerFunc {
 			msg.Metadata.Set("trace_id", traceID)
 		}
 
-		ctx := context.WithValue(msg.Context(), "trace_id", traceID)
-		msg.SetContext(ctx)
+		msg.SetContext(pubsubadapter.WithTraceID(msg.Context(), traceID))
 
 		return h(msg)
 	}
@@ -46,12 +69,62 @@ func LoggingMiddleware(logger *slog.Logger) message.HandlerMiddleware {
 	}
 }
 
+// [TELEMETRY_MIDDLEWARE]
+// Measures time-to-complete per handler. Must be the outermost middleware so the
+// recorded duration covers retries and backoff delays.
+func TelemetryMiddleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		start := time.Now()
+		msgs, err := h(msg)
+
+		ctx := msg.Context()
+		attrs := metric.WithAttributes(attribute.String("handler_name", message.HandlerNameFromCtx(ctx)))
+
+		handlerDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+		if err != nil {
+			handlerErrors.Add(ctx, 1, attrs)
+		}
+		return msgs, err
+	}
+}
+
 // [RETRY_MIDDLEWARE]
-func NewRetryMiddleware() middleware.Retry {
+// handlerName labels the retry counter; Watermill's retry hook carries no message context.
+func NewRetryMiddleware(handlerName string, policy config.HandlerPolicy) middleware.Retry {
+	attrs := metric.WithAttributes(attribute.String("handler_name", handlerName))
+
 	return middleware.Retry{
-		MaxRetries:      3,
-		InitialInterval: time.Second * 2,
-		MaxInterval:     time.Second * 15,
-		Multiplier:      2.0,
+		MaxRetries:      policy.MaxRetries,
+		InitialInterval: policy.InitialInterval,
+		MaxInterval:     policy.MaxInterval,
+		Multiplier:      policy.Multiplier,
+		OnRetryHook: func(int, time.Duration) {
+			handlerRetries.Add(context.Background(), 1, attrs)
+		},
+	}
+}
+
+// Metadata recorded on messages that reach the poison topic.
+const (
+	RetryCountHeader = "retry_count" // Retries made after the first attempt
+	LastErrorHeader  = "last_error"  // Error of the final attempt
+)
+
+// [ATTEMPT_MIDDLEWARE]
+// AttemptMetadataMiddleware sits inside the retry middleware and stamps each attempt,
+// so the poison queue outside it publishes the retry count and the last error.
+func AttemptMetadataMiddleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		if n, err := strconv.Atoi(msg.Metadata.Get(RetryCountHeader)); err == nil {
+			msg.Metadata.Set(RetryCountHeader, strconv.Itoa(n+1))
+		} else {
+			msg.Metadata.Set(RetryCountHeader, "0")
+		}
+
+		msgs, err := h(msg)
+		if err != nil {
+			msg.Metadata.Set(LastErrorHeader, err.Error())
+		}
+		return msgs, err
 	}
 }
@@ -0,0 +1,109 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/webitel/im-delivery-service/config"
+)
+
+// chanSubscriber hands the test's messages to the router as they are, so their
+// Acked and Nacked channels show what the router decided.
+type chanSubscriber struct {
+	ch   chan *message.Message
+	once sync.Once
+}
+
+func (s *chanSubscriber) Subscribe(context.Context, string) (<-chan *message.Message, error) {
+	return s.ch, nil
+}
+
+func (s *chanSubscriber) Close() error {
+	s.once.Do(func() { close(s.ch) })
+	return nil
+}
+
+// TestRetryMiddleware fails a handler a number of times and checks that the router
+// ACKs the message once a later attempt succeeds, and NACKs it once retries run out.
+func TestRetryMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		failures   int // Attempts that fail before one succeeds
+		wantCalls  int64
+		wantAck    bool
+	}{
+		{name: "first attempt succeeds", maxRetries: 3, wantCalls: 1, wantAck: true},
+		{name: "succeeds on the last retry", maxRetries: 3, failures: 3, wantCalls: 4, wantAck: true},
+		{name: "succeeds midway", maxRetries: 5, failures: 2, wantCalls: 3, wantAck: true},
+		{name: "retries exhausted", maxRetries: 2, failures: 10, wantCalls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := config.HandlerPolicy{
+				MaxRetries:      tt.maxRetries,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     time.Millisecond,
+				Multiplier:      1,
+			}
+			var calls atomic.Int64
+			handler := func(*message.Message) error {
+				if calls.Add(1) <= int64(tt.failures) {
+					return errors.New("transient")
+				}
+				return nil
+			}
+
+			router, err := message.NewRouter(message.RouterConfig{CloseTimeout: time.Second}, watermill.NopLogger{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sub := &chanSubscriber{ch: make(chan *message.Message, 1)}
+			router.AddConsumerHandler("ON_TEST", "test", sub, handler).AddMiddleware(
+				NewRetryMiddleware("ON_TEST", policy).Middleware,
+				AttemptMetadataMiddleware,
+			)
+			go func() { _ = router.Run(context.Background()) }()
+			t.Cleanup(func() { _ = router.Close() })
+			<-router.Running()
+
+			msg := message.NewMessage(watermill.NewUUID(), nil)
+			sub.ch <- msg
+
+			select {
+			case <-msg.Acked():
+				if !tt.wantAck {
+					t.Fatal("message ACKed after its retries ran out")
+				}
+			case <-msg.Nacked():
+				if tt.wantAck {
+					t.Fatal("message NACKed")
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("message neither ACKed nor NACKed")
+			}
+
+			// An ACKed message is settled for good; a NACK may not also ACK it.
+			select {
+			case <-msg.Acked():
+				if !tt.wantAck {
+					t.Fatal("message both NACKed and ACKed")
+				}
+			default:
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("handler calls = %d, want %d", got, tt.wantCalls)
+			}
+			if got, want := msg.Metadata.Get(RetryCountHeader), strconv.FormatInt(tt.wantCalls-1, 10); got != want {
+				t.Fatalf("%s = %q, want %q", RetryCountHeader, got, want)
+			}
+		})
+	}
+}
@@ -6,32 +6,76 @@ import (
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/webitel/im-delivery-service/config"
+	infrapubsub "github.com/webitel/im-delivery-service/infra/pubsub"
 	pubsubadapter "github.com/webitel/im-delivery-service/internal/adapter/pubsub"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/domain/watch"
+	"github.com/webitel/im-delivery-service/internal/service"
 	"go.uber.org/fx"
 )
 
 const DeliveryExchange = "im_delivery.broadcast"
 
-var Module = fx.Module("amqp-handler",
+// ObserverExchange receives the enriched events exported by observer nodes.
+// Kept apart from DeliveryExchange so warehouse feeds never reach delivery nodes.
+const ObserverExchange = "im_delivery.observed"
+
+// pipeline is the consumption core shared by both node roles.
+var pipeline = fx.Options(
 	fx.Provide(
 		pubsubadapter.NewSubscriberProvider,
-		pubsubadapter.NewPublisherProvider,
 
-		// [FIX] Building the publisher.
-		// If your pp.Build only takes a string, we pass the Exchange name.
-		func(pp *pubsubadapter.PublisherProvider) (message.Publisher, error) {
-			return pp.Build(DeliveryExchange)
+		// [STOP_ORDER] Every publisher user depends on the provider, so their hooks are
+		// appended after this one and stop before it: publishers close last.
+		func(p infrapubsub.Provider, cfg *config.Config, lc fx.Lifecycle) *pubsubadapter.PublisherProvider {
+			pp := pubsubadapter.NewPublisherProvider(p, pubsubadapter.WithPublisherConfirms(cfg.Pubsub.Confirm))
+			lc.Append(fx.Hook{
+				OnStop: func(context.Context) error { return pp.Close() },
+			})
+			return pp
 		},
 
 		// [DISPATCHER] Domain-aware wrapper for the publisher
-		func(pub message.Publisher) pubsubadapter.EventDispatcher {
-			return pubsubadapter.NewEventDispatcher(pub)
+		func(pub message.Publisher, pp *pubsubadapter.PublisherProvider, cfg *config.Config, logger *slog.Logger, lc fx.Lifecycle) pubsubadapter.EventDispatcher {
+			retry := cfg.Pubsub.PublishRetry
+			opts := []pubsubadapter.DispatcherOption{
+				pubsubadapter.WithPublisherTimeout(cfg.Pubsub.PublishTimeout),
+				pubsubadapter.WithPublishRetry(retry.Attempts, retry.Backoff, retry.MaxBackoff),
+				pubsubadapter.WithDispatcherLogger(logger),
+				// [EXCHANGE_SELECTION] Events naming another exchange get a cached publisher for it.
+				pubsubadapter.WithExchangePublishers(pp),
+			}
+
+			// [OUTBOX] Appended after the publisher provider, so it drains before publishers close.
+			if size := cfg.Pubsub.Outbox.Size; size > 0 {
+				o := pubsubadapter.NewOutbox(size, retry.Backoff, retry.MaxBackoff, logger)
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error { o.Start(); return nil },
+					OnStop: func(ctx context.Context) error {
+						ctx, cancel := context.WithTimeout(ctx, cfg.Pubsub.Outbox.FlushTimeout)
+						defer cancel()
+						o.Stop(ctx)
+						return nil
+					},
+				})
+				opts = append(opts, pubsubadapter.WithOutbox(o))
+			}
+			return pubsubadapter.NewEventDispatcher(pub, opts...)
 		},
 
-		NewMessageHandler,
+		NewDeduplicator,
+		// [OPTIONAL] The group directory exists only when enrichment.groups is on.
+		fx.Annotate(
+			NewMessageHandler,
+			fx.ParamTags(``, ``, ``, ``, ``, ``, ``, ``, ``, ``, `optional:"true"`),
+		),
 
-		func(logger *slog.Logger) (*message.Router, error) {
-			return message.NewRouter(message.RouterConfig{}, watermill.NewSlogLogger(logger))
+		func(cfg *config.Config, logger *slog.Logger) (*message.Router, error) {
+			return message.NewRouter(message.RouterConfig{
+				// [DRAIN] How long Close waits for handlers mid-enrichment before giving up.
+				CloseTimeout: cfg.Pubsub.RouterCloseTimeout,
+			}, watermill.NewSlogLogger(logger))
 		},
 	),
 
@@ -40,26 +84,104 @@ var Module = fx.Module("amqp-handler",
 		h *MessageHandler,
 		router *message.Router,
 		subProvider *pubsubadapter.SubscriberProvider,
+		cfg *config.Config,
 		logger *slog.Logger,
 	) error {
-		// [WIRING] Register all defined consumers
-		if err := h.RegisterHandlers(router, subProvider); err != nil {
+		// [WIRING] Register all consumers defined for this node's role
+		if err := h.RegisterHandlers(router, subProvider, cfg.Service.Role, cfg.Pubsub); err != nil {
 			return err
 		}
 
+		// [LIFECYCLE_CONTEXT] Run is bound to the app's lifetime instead of context.Background.
+		runCtx, cancel := context.WithCancel(context.Background())
+		runDone := make(chan struct{})
+
 		lc.Append(fx.Hook{
 			OnStart: func(ctx context.Context) error {
 				go func() {
-					if err := router.Run(context.Background()); err != nil {
+					defer close(runDone)
+					if err := router.Run(runCtx); err != nil {
 						logger.Error("router runtime error", "err", err)
 					}
 				}()
 				return nil
 			},
 			OnStop: func(ctx context.Context) error {
-				return router.Close()
+				// 1. Stop consuming and wait (up to RouterCloseTimeout) for in-flight handlers,
+				//    so none of them publishes after the publishers are closed.
+				err := router.Close()
+				// 2. Release the subscribers, then wait for Run itself to return.
+				cancel()
+				select {
+				case <-runDone:
+				case <-ctx.Done():
+					logger.Warn("router did not stop before the shutdown deadline")
+				}
+				return err
 			},
 		})
 		return nil
 	}),
 )
+
+var Module = fx.Module("amqp-handler",
+	pipeline,
+	fx.Provide(
+		// [FIX] Building the publisher.
+		// If your pp.Build only takes a string, we pass the Exchange name.
+		func(pp *pubsubadapter.PublisherProvider) (message.Publisher, error) {
+			return pp.Build(DeliveryExchange)
+		},
+
+		// [LOCALITY] The Hub answers both "is the user here" and "deliver it".
+		func(hub registry.Hubber) LocalityChecker { return hub },
+		func(hub registry.Hubber) LocalDelivery { return hub },
+		// [DOMAIN_PAUSE] Tenant maintenance windows hold local delivery.
+		func(p *service.DomainPauser) DeliveryGate { return p },
+		// [PRESENCE_WATCH] Status changes fan out to the sessions that asked for them.
+		func(r *watch.Registry) PresenceWatchers { return r },
+
+		// [PRESENCE] Presence changes leave through the same domain-aware dispatcher.
+		func(d pubsubadapter.EventDispatcher) service.PresencePublisher { return d },
+		// [RECEIPTS] Wire-level delivery acknowledgements use the same dispatcher.
+		func(d pubsubadapter.EventDispatcher) service.ReceiptPublisher { return d },
+
+		// [CLIENT_EVENTS] Client-originated events join their producers on the system exchange.
+		// Typing is superseded within seconds, so it is never retried.
+		func(pp *pubsubadapter.PublisherProvider, cfg *config.Config, logger *slog.Logger) (service.ClientEventPublisher, error) {
+			pub, err := pp.Build(SystemEventsExchange)
+			if err != nil {
+				return nil, err
+			}
+			return pubsubadapter.NewEventDispatcher(pub,
+				pubsubadapter.WithPublisherTimeout(cfg.Pubsub.PublishTimeout),
+				pubsubadapter.WithDispatcherLogger(logger),
+			), nil
+		},
+
+		// [ANALYTICS_EXPORT] Batching sink for sampled deliveries; routed via the broadcast exchange.
+		func(pub message.Publisher, logger *slog.Logger, lc fx.Lifecycle) service.AnalyticsSink {
+			p := pubsubadapter.NewAnalyticsPublisher(pub, logger)
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error { p.Start(); return nil },
+				OnStop:  func(context.Context) error { p.Stop(); return nil },
+			})
+			return p
+		},
+	),
+)
+
+// ObserverModule runs the same pipeline without a Hub: every event is decoded,
+// enriched and exported to ObserverExchange, and nothing is delivered to clients.
+var ObserverModule = fx.Module("amqp-observer",
+	pipeline,
+	fx.Provide(
+		func(pp *pubsubadapter.PublisherProvider) (message.Publisher, error) {
+			return pp.Build(ObserverExchange)
+		},
+		func() LocalityChecker { return AlwaysProcess{} },
+		func() LocalDelivery { return NoDelivery{} },
+		func() DeliveryGate { return OpenGate{} },
+		func() PresenceWatchers { return NoWatchers{} },
+	),
+)
@@ -0,0 +1,136 @@
+package amqp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	infrapubsub "github.com/webitel/im-delivery-service/infra/pubsub"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/service"
+	servicedi "github.com/webitel/im-delivery-service/internal/service/di"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// stallingEnricher holds every enrichment until release is closed, like a directory
+// call that is slow to answer; it ignores ctx on purpose.
+type stallingEnricher struct {
+	service.Enricher
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (e stallingEnricher) ResolvePeers(_ context.Context, from, to model.Peer, _ int32) (model.Peer, model.Peer, error) {
+	e.entered <- struct{}{}
+	<-e.release
+	from.Name, to.Name = "Enriched Sender", "Enriched Recipient"
+	return from, to, nil
+}
+
+// TestPipelineStopOrder checks that Stop waits for a handler caught mid-enrichment
+// and that nothing is published once the publishers are closed.
+func TestPipelineStopOrder(t *testing.T) {
+	user := uuid.New()
+	peer := dto.PeerDTO{ID: uuid.NewString(), Type: 1}
+	created, _ := json.Marshal(dto.MessageV1{
+		MessageID: uuid.NewString(), ThreadID: uuid.NewString(), DomainID: 1,
+		From: peer, To: peer, Body: "hi", OccurredAt: "2026-01-02T03:04:05Z",
+	})
+
+	tests := []struct {
+		name     string
+		send     bool          // Consume one message before stopping
+		inFlight bool          // Stop while the handler is inside the enricher
+		hold     time.Duration // How long the enricher stalls after Stop begins
+	}{
+		{name: "idle router"},
+		{name: "handler finishes before stop", send: true},
+		{name: "handler mid-enrichment at stop", send: true, inFlight: true, hold: 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Service.Role = config.RoleObserver
+			cfg.Pubsub.PublishTimeout = time.Second
+			cfg.Pubsub.RouterCloseTimeout = 2 * time.Second
+
+			bus := newMemBus()
+			t.Cleanup(func() { _ = bus.ch.Close() })
+			enricher := stallingEnricher{entered: make(chan struct{}, 1), release: make(chan struct{})}
+			var router *message.Router
+			app := fxtest.New(t,
+				fx.Supply(cfg),
+				fx.Provide(
+					func() *slog.Logger { return slog.New(slog.NewTextHandler(io.Discard, nil)) },
+					func() infrapubsub.Provider { return bus },
+				),
+				servicedi.ObserverModule,
+				ObserverModule,
+				fx.Replace(fx.Annotate(enricher, fx.As(new(service.Enricher)))),
+				fx.Populate(&router),
+			)
+			app.RequireStart()
+			<-router.Running()
+
+			exports, err := bus.ch.Subscribe(t.Context(), ObserverExchange)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.send {
+				key := "im_message.1." + user.String() + ".message.created.v1"
+				if err := bus.publisher(MessageEventsExchange).Publish(key, message.NewMessage(watermill.NewUUID(), created)); err != nil {
+					t.Fatal(err)
+				}
+				<-enricher.entered
+			}
+			if tt.send && !tt.inFlight {
+				close(enricher.release)
+				select {
+				case msg := <-exports:
+					msg.Ack()
+				case <-time.After(time.Second):
+					t.Fatal("nothing exported before stop")
+				}
+			}
+
+			stopped := make(chan error, 1)
+			go func() { stopped <- app.Stop(context.Background()) }()
+			if tt.inFlight {
+				select {
+				case err := <-stopped:
+					t.Fatalf("Stop returned while a handler was mid-enrichment: %v", err)
+				case <-time.After(tt.hold):
+				}
+				close(enricher.release)
+			}
+			if err := <-stopped; err != nil {
+				t.Fatalf("Stop: %v", err)
+			}
+
+			if tt.inFlight {
+				select {
+				case msg := <-exports:
+					msg.Ack()
+					if !bytes.Contains(msg.Payload, []byte("Enriched Sender")) {
+						t.Fatalf("export is not enriched: %s", msg.Payload)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("the in-flight handler's export was lost")
+				}
+			}
+			if n := bus.late.Load(); n != 0 {
+				t.Fatalf("%d publishes reached a closed publisher", n)
+			}
+		})
+	}
+}
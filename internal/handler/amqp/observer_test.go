@@ -0,0 +1,265 @@
+package amqp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	infrapubsub "github.com/webitel/im-delivery-service/infra/pubsub"
+	"github.com/webitel/im-delivery-service/infra/pubsub/factory"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/service"
+	servicedi "github.com/webitel/im-delivery-service/internal/service/di"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// memBus is an in-memory stand-in for the broker: one gochannel topic per exchange,
+// with AMQP topic matching applied on the subscriber side.
+type memBus struct {
+	ch   *gochannel.GoChannel
+	late atomic.Int64 // Publishes attempted on a closed publisher
+}
+
+func newMemBus() *memBus {
+	return &memBus{ch: gochannel.NewGoChannel(gochannel.Config{OutputChannelBuffer: 64}, watermill.NopLogger{})}
+}
+
+func (b *memBus) GetRouter() *message.Router  { return nil }
+func (b *memBus) GetFactory() factory.Factory { return b }
+func (b *memBus) publisher(exchange string) *memPublisher {
+	return &memPublisher{bus: b, exchange: exchange}
+}
+
+func (b *memBus) BuildPublisher(cfg *factory.PublisherConfig) (message.Publisher, error) {
+	return b.publisher(cfg.Exchange.Name), nil
+}
+
+func (b *memBus) BuildSubscriber(_ string, cfg *factory.SubscriberConfig) (message.Subscriber, error) {
+	return &memSubscriber{bus: b, exchange: cfg.Exchange.Name, pattern: cfg.RoutingKey}, nil
+}
+
+type memPublisher struct {
+	bus      *memBus
+	exchange string
+	closed   atomic.Bool
+}
+
+// Publish stamps the routing key the way the AMQP marshaller does on the consumer side.
+func (p *memPublisher) Publish(routingKey string, msgs ...*message.Message) error {
+	if p.closed.Load() {
+		p.bus.late.Add(1)
+		return errors.New("publisher closed")
+	}
+	for _, m := range msgs {
+		out := m.Copy()
+		out.Metadata.Set("x-routing-key", routingKey)
+		if err := p.bus.ch.Publish(p.exchange, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *memPublisher) Close() error {
+	p.closed.Store(true)
+	return nil
+}
+
+type memSubscriber struct {
+	bus      *memBus
+	exchange string
+	pattern  string
+}
+
+func (s *memSubscriber) Subscribe(ctx context.Context, _ string) (<-chan *message.Message, error) {
+	in, err := s.bus.ch.Subscribe(ctx, s.exchange)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan *message.Message)
+	go func() {
+		defer close(out)
+		for msg := range in {
+			if !topicMatches(s.pattern, routingKey(msg)) {
+				msg.Ack() // Not bound to this queue
+				continue
+			}
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (*memSubscriber) Close() error { return nil }
+
+// topicMatches applies AMQP topic binding rules: * is one word, # is zero or more.
+func topicMatches(pattern, key string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(key, "."))
+}
+
+func matchWords(pattern, key []string) bool {
+	if len(pattern) == 0 {
+		return len(key) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(key); i++ {
+			if matchWords(pattern[1:], key[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(key) > 0 && matchWords(pattern[1:], key[1:])
+	default:
+		return len(key) > 0 && key[0] == pattern[0] && matchWords(pattern[1:], key[1:])
+	}
+}
+
+// namingEnricher stands in for the directory-backed enricher.
+type namingEnricher struct{ service.Enricher }
+
+func (namingEnricher) ResolvePeers(_ context.Context, from, to model.Peer, _ int32) (model.Peer, model.Peer, error) {
+	from.Name, to.Name = "Enriched Sender", "Enriched Recipient"
+	return from, to, nil
+}
+
+// TestObserverGraph boots the observer role's Fx graph on an in-memory bus and checks
+// that it consumes, enriches and exports without any delivery-side component.
+func TestObserverGraph(t *testing.T) {
+	user := uuid.New()
+	peer := dto.PeerDTO{ID: uuid.NewString(), Type: 1}
+	created, _ := json.Marshal(dto.MessageV1{
+		MessageID: uuid.NewString(), ThreadID: uuid.NewString(), DomainID: 1,
+		From: peer, To: peer, Body: "hi", OccurredAt: "2026-01-02T03:04:05Z",
+	})
+	typing, _ := json.Marshal(map[string]any{"thread_id": uuid.NewString(), "domain_id": 1, "user_id": user})
+
+	tests := []struct {
+		name       string
+		exchange   string
+		routingKey string
+		payload    []byte
+		wantExport bool
+	}{
+		{
+			name:       "message is enriched and exported",
+			exchange:   MessageEventsExchange,
+			routingKey: "im_message.1." + user.String() + ".message.created.v1",
+			payload:    created,
+			wantExport: true,
+		},
+		{
+			name:       "delivery-only handlers are not registered",
+			exchange:   SystemEventsExchange,
+			routingKey: "im_system.1." + user.String() + ".thread.typing.v1",
+			payload:    typing,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := runtime.NumGoroutine()
+
+			cfg := &config.Config{}
+			cfg.Service.Role = config.RoleObserver
+			cfg.Pubsub.PublishTimeout = time.Second
+			cfg.Pubsub.RouterCloseTimeout = time.Second
+
+			bus := newMemBus()
+			var (
+				router   *message.Router
+				graph    fx.DotGraph
+				locality LocalityChecker
+				local    LocalDelivery
+			)
+			app := fxtest.New(t,
+				fx.Supply(cfg),
+				fx.Provide(
+					func() *slog.Logger { return slog.New(slog.NewTextHandler(io.Discard, nil)) },
+					func() infrapubsub.Provider { return bus },
+				),
+				servicedi.ObserverModule,
+				ObserverModule,
+				fx.Replace(fx.Annotate(namingEnricher{}, fx.As(new(service.Enricher)))),
+				fx.Populate(&router, &graph, &locality, &local),
+			)
+
+			// [GRAPH_INSPECTION] The observer graph holds the pipeline and nothing that serves clients.
+			if !strings.Contains(string(graph), "amqp.MessageHandler") {
+				t.Fatalf("graph lacks the message handler:\n%s", graph)
+			}
+			for _, absent := range []string{"registry.", "service.Deliverer", "watch.Registry", "DomainPauser", "grpcsrv.", "httpsrv."} {
+				if strings.Contains(string(graph), absent) {
+					t.Fatalf("observer graph provides %s", absent)
+				}
+			}
+
+			if _, ok := locality.(AlwaysProcess); !ok {
+				t.Fatalf("locality is %T, want AlwaysProcess", locality)
+			}
+			if _, ok := local.(NoDelivery); !ok {
+				t.Fatalf("local delivery is %T, want NoDelivery", local)
+			}
+
+			app.RequireStart()
+			<-router.Running()
+
+			exports, err := bus.ch.Subscribe(t.Context(), ObserverExchange)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := bus.publisher(tt.exchange).Publish(tt.routingKey, message.NewMessage(watermill.NewUUID(), tt.payload)); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case msg := <-exports:
+				msg.Ack()
+				if !tt.wantExport {
+					t.Fatalf("exported %s", msg.Payload)
+				}
+				if !bytes.Contains(msg.Payload, []byte("Enriched Sender")) {
+					t.Fatalf("export is not enriched: %s", msg.Payload)
+				}
+			case <-time.After(500 * time.Millisecond):
+				if tt.wantExport {
+					t.Fatal("nothing exported")
+				}
+			}
+
+			app.RequireStop()
+			if err := bus.ch.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// [LEAK_CHECK] Every goroutine the graph started must be gone after Stop.
+			deadline := time.Now().Add(2 * time.Second)
+			for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if n := runtime.NumGoroutine(); n > baseline {
+				buf := make([]byte, 1<<20)
+				t.Fatalf("%d goroutines left running after Stop, baseline %d:\n%s", n, baseline, buf[:runtime.Stack(buf, true)])
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// PoisonInspectQueue feeds the recorder; shared, so each message is recorded once.
+	PoisonInspectQueue = "im-delivery.poison.inspect"
+	// PoisonParkingQueue keeps poisoned messages until `poison replay` drains it.
+	PoisonParkingQueue = "im-delivery.poison.parked"
+
+	// Metadata stamped before the poison queue, so a replay knows where a message came from.
+	SourceExchangeHeader   = "x-source-exchange"
+	SourceRoutingKeyHeader = "x-source-routing-key"
+	// ReplayAttemptHeader counts replays; it survives re-poisoning and caps the loop.
+	ReplayAttemptHeader = "x-replay-attempt"
+
+	poisonSnippetBytes = 256
+)
+
+var poisonedMessages, _ = meter.Int64Counter(
+	"im_delivery_poison_messages_total",
+	metric.WithDescription("Messages that exhausted their retries, by source topic"),
+)
+
+// [SOURCE_MIDDLEWARE]
+// SourceMetadataMiddleware records the consumed exchange and routing key ahead of the poison queue.
+func SourceMetadataMiddleware(exchange string) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+			msg.Metadata.Set(SourceExchangeHeader, exchange)
+			if rk := routingKey(msg); rk != "" {
+				msg.Metadata.Set(SourceRoutingKeyHeader, rk)
+			}
+			return h(msg)
+		}
+	}
+}
+
+// [POISON_RECORDER]
+// BindPoison logs a structured entry per poisoned message and counts it by source topic.
+// It runs without retry or poison middleware: a recorder failure must not loop.
+func BindPoison(h *MessageHandler) message.NoPublishHandlerFunc {
+	return func(msg *message.Message) error {
+		topic := msg.Metadata.Get(middleware.PoisonedTopicKey)
+		poisonedMessages.Add(context.Background(), 1, metric.WithAttributes(attribute.String("source_topic", topic)))
+
+		snippet := msg.Payload
+		if len(snippet) > poisonSnippetBytes {
+			snippet = snippet[:poisonSnippetBytes]
+		}
+		h.logger.Warn("POISON_RECORDED",
+			slog.String("msg_id", msg.UUID),
+			slog.String("source_topic", topic),
+			slog.String("source_exchange", msg.Metadata.Get(SourceExchangeHeader)),
+			slog.String("routing_key", msg.Metadata.Get(SourceRoutingKeyHeader)),
+			slog.String("handler", msg.Metadata.Get(middleware.PoisonedHandlerKey)),
+			slog.String("error", msg.Metadata.Get(middleware.ReasonForPoisonedKey)),
+			slog.String("retry_count", msg.Metadata.Get(RetryCountHeader)),
+			slog.String("replay_attempt", msg.Metadata.Get(ReplayAttemptHeader)),
+			slog.String("payload", string(snippet)),
+			slog.Time("recorded_at", time.Now()),
+		)
+		return nil
+	}
+}
+
+// DeclarePoisonParking makes sure the parking queue exists and is bound, without consuming,
+// so poisoned messages are kept even before the replay tool first runs.
+func DeclarePoisonParking(sub message.Subscriber) error {
+	init, ok := sub.(message.SubscribeInitializer)
+	if !ok {
+		return nil
+	}
+	return init.SubscribeInitialize(DeliveryPoisonTopic)
+}
+
+// ReplayOptions selects what `poison replay` republishes.
+type ReplayOptions struct {
+	Topic      string        // Source topic to replay ("" = any)
+	Limit      int           // Most messages republished in one run
+	MaxReplays int           // Messages replayed this often stay parked
+	Idle       time.Duration // The run ends after this long without a message
+}
+
+// ReplayReport summarises a replay run.
+type ReplayReport struct {
+	Replayed int
+	Skipped  int // Other topics, returned to the queue
+	Capped   int // Reached MaxReplays; returned to the queue
+}
+
+// ReplayPoisoned drains parked messages from sub and republishes them to their source
+// exchange and routing key with ReplayAttemptHeader incremented.
+// [NO_LOOP] Messages at the replay cap are returned to the queue, never republished; the
+// run stops when a returned message comes back, so it cannot spin on them either.
+func ReplayPoisoned(ctx context.Context, sub message.Subscriber, publisherFor func(exchange string) (message.Publisher, error), opts ReplayOptions) (ReplayReport, error) {
+	var report ReplayReport
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	msgs, err := sub.Subscribe(ctx, DeliveryPoisonTopic)
+	if err != nil {
+		return report, fmt.Errorf("poison subscribe: %w", err)
+	}
+
+	returned := make(map[string]struct{})
+	for report.Replayed < opts.Limit {
+		var msg *message.Message
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		case <-time.After(opts.Idle):
+			return report, nil
+		case m, ok := <-msgs:
+			if !ok {
+				return report, nil
+			}
+			msg = m
+		}
+
+		if _, seen := returned[msg.UUID]; seen {
+			msg.Nack()
+			return report, nil
+		}
+
+		if opts.Topic != "" && msg.Metadata.Get(middleware.PoisonedTopicKey) != opts.Topic {
+			returned[msg.UUID] = struct{}{}
+			report.Skipped++
+			msg.Nack()
+			continue
+		}
+
+		attempt, _ := strconv.Atoi(msg.Metadata.Get(ReplayAttemptHeader))
+		if attempt >= opts.MaxReplays {
+			returned[msg.UUID] = struct{}{}
+			report.Capped++
+			msg.Nack()
+			continue
+		}
+
+		if err := replayOne(msg, attempt+1, publisherFor); err != nil {
+			msg.Nack()
+			return report, err
+		}
+		msg.Ack()
+		report.Replayed++
+	}
+	return report, nil
+}
+
+func replayOne(msg *message.Message, attempt int, publisherFor func(string) (message.Publisher, error)) error {
+	exchange := msg.Metadata.Get(SourceExchangeHeader)
+	rk := msg.Metadata.Get(SourceRoutingKeyHeader)
+	if exchange == "" || rk == "" {
+		return errors.New("poison replay: message carries no source exchange or routing key")
+	}
+
+	pub, err := publisherFor(exchange)
+	if err != nil {
+		return fmt.Errorf("poison replay: publisher for %q: %w", exchange, err)
+	}
+
+	out := message.NewMessage(msg.UUID, msg.Payload)
+	for k, v := range msg.Metadata {
+		out.Metadata.Set(k, v)
+	}
+	// The poison bookkeeping of the previous failure does not apply to the new attempt.
+	for _, k := range []string{
+		middleware.ReasonForPoisonedKey, middleware.PoisonedTopicKey,
+		middleware.PoisonedHandlerKey, middleware.PoisonedSubscriberKey,
+		RetryCountHeader, LastErrorHeader,
+	} {
+		delete(out.Metadata, k)
+	}
+	out.Metadata.Set(ReplayAttemptHeader, strconv.Itoa(attempt))
+
+	return pub.Publish(rk, out)
+}
@@ -0,0 +1,71 @@
+package amqp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
+)
+
+// TestBindResidency simulates one node per region consuming its own copy of the same
+// message, with the recipient connected to both (a misconfigured session on the wrong
+// region). Only the allowed region delivers; the other ACKs without delivering, since
+// its queue copy is its own and a requeue would only loop.
+func TestBindResidency(t *testing.T) {
+	user := uuid.New()
+	key := "im_message.7." + user.String() + ".message.created.v1"
+	peer := dto.PeerDTO{ID: uuid.NewString(), Type: 1}
+	created := func(domain int32) *message.Message {
+		raw := dto.MessageV1{
+			MessageID: uuid.NewString(), ThreadID: uuid.NewString(), DomainID: domain,
+			From: peer, To: peer, OccurredAt: "2026-01-02T03:04:05Z",
+		}
+		return createdMessage(t, key, raw)
+	}
+	policy := func(regions ...string) *message.Message {
+		b, _ := json.Marshal(domainPolicy{DomainID: 7, Regions: regions})
+		return message.NewMessage(uuid.NewString(), b)
+	}
+
+	tests := []struct {
+		name   string
+		policy *message.Message // Consumed by both nodes first, if set
+		domain int32
+		want   map[string]int // Region -> delivered events
+	}{
+		{name: "restricted tenant", domain: 7, want: map[string]int{"eu": 1, "us": 0}},
+		{name: "unrestricted tenant", domain: 1, want: map[string]int{"eu": 1, "us": 1}},
+		{name: "policy event moves the tenant", policy: policy("us"), domain: 7, want: map[string]int{"eu": 0, "us": 1}},
+		{name: "policy event lifts the restriction", policy: policy(), domain: 7, want: map[string]int{"eu": 1, "us": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := created(tt.domain)
+			for region, want := range tt.want {
+				cfg := &config.Config{}
+				cfg.Service.Region = region
+				cfg.Delivery.Residency.Domains = []config.DomainResidency{{DomainID: 7, Regions: []string{"eu"}}}
+
+				f := newFanOutFixture(fakeLocality{user: true}, nil)
+				f.h.residency = service.NewResidencyPolicy(cfg)
+				if tt.policy != nil {
+					if err := BindDomainPolicy(f.h)(tt.policy); err != nil {
+						t.Fatalf("%s: policy: %v", region, err)
+					}
+				}
+
+				// A nil error ACKs: neither node may requeue its copy.
+				if err := BindMessageCreated(f.h)(msg.Copy()); err != nil {
+					t.Fatalf("%s: %v", region, err)
+				}
+				if got := len(f.delivered.events); got != want {
+					t.Fatalf("%s delivered %d events, want %d", region, got, want)
+				}
+			}
+		})
+	}
+}
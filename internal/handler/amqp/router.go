@@ -8,8 +8,8 @@ import (
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
 	"github.com/webitel/im-delivery-service/internal/adapter/pubsub"
-	"github.com/webitel/im-delivery-service/internal/domain/registry"
 	"github.com/webitel/im-delivery-service/internal/service"
 )
 
@@ -17,11 +17,31 @@ const (
 	// ------------------- EXCHANGES (SOURCES) -------------------
 	MessageEventsExchange = "im_message.events"
 	SystemEventsExchange  = "im_system.events"
+	CallEventsExchange    = "im_call.events"
+	ContactEventsExchange = "im_contact.events"
 
 	// ------------------- TOPICS (ROUTING KEYS) -----------------
-	TopicMessageCreated = "im_message.#.message.created.v1"
-	TopicMessageDeleted = "im_message.#.message.deleted.v1"
-	TopicUserStatus     = "im_system.#.user.status.v1"
+	TopicMessageCreated  = "im_message.#.message.created.v1"
+	TopicMessageDeleted  = "im_message.#.message.deleted.v1"
+	TopicMessageUpdated  = "im_message.#.message.updated.v1"
+	TopicMessageRead     = "im_message.#.message.read.v1"
+	TopicMessageReaction = "im_message.#.message.reaction.v1"
+	TopicUserStatus      = "im_system.#.user.status.v1"
+	TopicDomainPolicy    = "im_system.#.domain.policy.v1"
+	TopicDomainPause     = "im_system.#.domain.pause.v1"
+	TopicTyping          = "im_system.#.thread.typing.v1"
+	TopicContactUpdated  = "im_contact.#.contact.updated.v1"
+	TopicContactDeleted  = "im_contact.#.contact.deleted.v1"
+
+	// ------------------- EPHEMERAL TOPICS ----------------------
+	// Routing keys map 1:1 to topic keys requested by connections via SubscribeTopic:
+	// im_call.transcript.{domain_id}.{call_id}.
+	TopicCallTranscript = "im_call.transcript.#"
+
+	// ------------------- RECIPIENT SEGMENTS --------------------
+	// Read receipts are keyed im_message.{domain_id}.{reader_id}.{sender_id}.message.read.v1;
+	// the receipt goes to the sender, not to the first UUID in the key.
+	MessageReadSenderSegment = 3
 
 	// ------------------- QUEUES (CONSUMERS) --------------------
 	DeliveryProcessorQueue = "im-delivery.incoming-processor.v1"
@@ -29,18 +49,61 @@ const (
 )
 
 type MessageHandler struct {
-	hub        registry.Hubber
+	locality   LocalityChecker
+	local      LocalDelivery
+	gate       DeliveryGate
 	logger     *slog.Logger
-	enricher   service.Enricher
+	enrichment *service.EnrichmentPipeline
 	dispatcher pubsub.EventDispatcher
+	residency  *service.ResidencyPolicy
+	watchers   PresenceWatchers
+	enricher   service.Enricher
+	typing     *typingLimiter
+	dedup      *Deduplicator
+	members    service.GroupMembers // Optional: expands group-addressed messages (see groupAddressed)
 }
 
-func NewMessageHandler(hub registry.Hubber, logger *slog.Logger, enricher service.Enricher, dispatcher pubsub.EventDispatcher) *MessageHandler {
-	return &MessageHandler{hub, logger, enricher, dispatcher}
+// NewMessageHandler takes locality and local delivery as narrow interfaces so observer
+// nodes wire it without a Hub (see AlwaysProcess, NoDelivery and NoWatchers).
+func NewMessageHandler(locality LocalityChecker, local LocalDelivery, gate DeliveryGate, logger *slog.Logger, enrichment *service.EnrichmentPipeline, dispatcher pubsub.EventDispatcher, residency *service.ResidencyPolicy, watchers PresenceWatchers, enricher service.Enricher, dedup *Deduplicator, members service.GroupMembers) *MessageHandler {
+	return &MessageHandler{locality, local, gate, logger, enrichment, dispatcher, residency, watchers, enricher, newTypingLimiter(), dedup, members}
+}
+
+// handlerRoles tags a registration with the node roles that consume it.
+type handlerRoles uint8
+
+const (
+	forDelivery handlerRoles = 1 << iota
+	forObserver
+
+	forAll = forDelivery | forObserver
+)
+
+func rolesOf(role string) handlerRoles {
+	if role == config.RoleObserver {
+		return forObserver
+	}
+	return forDelivery
+}
+
+// handlerTuning adjusts the configured default policy for consumers whose traffic
+// differs from message events; pubsub.handlers overrides still apply on top.
+var handlerTuning = map[string]config.HandlerPolicy{
+	// A typing indicator is stale before a retry could land, and it arrives in bursts.
+	"ON_THREAD_TYPING": {MaxRetries: -1, Throttle: 1000, Timeout: 2 * time.Second},
+}
+
+// policyFor resolves a consumer's policy: default, then built-in tuning, then config override.
+func policyFor(name string, cfg config.PubsubConfig) config.HandlerPolicy {
+	p := cfg.Handler.Merge(handlerTuning[name])
+	if o, ok := cfg.HandlerOverride(name); ok {
+		p = p.Merge(o)
+	}
+	return p
 }
 
 // [REGISTRATION_PIPELINE]
-func (h *MessageHandler) RegisterHandlers(router *message.Router, subProvider *pubsub.SubscriberProvider) error {
+func (h *MessageHandler) RegisterHandlers(router *message.Router, subProvider *pubsub.SubscriberProvider, role string, policies config.PubsubConfig) error {
 	poison, err := middleware.PoisonQueue(h.dispatcher.Publisher(), DeliveryPoisonTopic)
 	if err != nil {
 		return fmt.Errorf("POISON_SETUP_FAILED: %w", err)
@@ -51,38 +114,118 @@ func (h *MessageHandler) RegisterHandlers(router *message.Router, subProvider *p
 		exchange string
 		topic    string
 		handler  message.NoPublishHandlerFunc
+		roles    handlerRoles
 	}{
-		{"ON_MSG_CREATED", MessageEventsExchange, TopicMessageCreated, Bind(h, h.OnMessageCreatedV1)},
+		{"ON_MSG_CREATED", MessageEventsExchange, TopicMessageCreated, BindMessageCreated(h), forAll},
+		{"ON_MSG_UPDATED", MessageEventsExchange, TopicMessageUpdated, Bind(h, h.OnMessageUpdatedV1), forAll},
+		{"ON_MSG_DELETED", MessageEventsExchange, TopicMessageDeleted, Bind(h, h.OnMessageDeletedV1), forAll},
+		{"ON_MSG_READ", MessageEventsExchange, TopicMessageRead, Bind(h, h.OnMessageReadV1, WithRecipientSegment(MessageReadSenderSegment)), forAll},
+		{"ON_MSG_REACTION", MessageEventsExchange, TopicMessageReaction, Bind(h, h.OnReactionV1), forAll},
+
+		// [DELIVERY_SEMANTICS] Presence only matters to connected clients.
+		// Recipients are the contact's watchers, not a routing key segment.
+		{"ON_USR_STATUS", SystemEventsExchange, TopicUserStatus, BindUserStatus(h), forDelivery},
+		{"ON_DOMAIN_POLICY", SystemEventsExchange, TopicDomainPolicy, BindDomainPolicy(h), forAll},
+		{"ON_DOMAIN_PAUSE", SystemEventsExchange, TopicDomainPause, BindDomainPause(h), forDelivery},
+		{"ON_THREAD_TYPING", SystemEventsExchange, TopicTyping, Bind(h, h.OnTypingV1, WithoutDedup()), forDelivery},
+
+		// [CACHE_COHERENCE] The peer cache is node-local, so every node consumes these
+		// and the locality filter does not apply.
+		{"ON_CONTACT_UPDATED", ContactEventsExchange, TopicContactUpdated, BindContactChanged(h, false), forAll},
+		{"ON_CONTACT_DELETED", ContactEventsExchange, TopicContactDeleted, BindContactChanged(h, true), forAll},
 
-		// [ARCHITECTURAL_PLACEHOLDERS]
-		// The following handlers serve as blueprints for scaling the system.
-		// Add new domain listeners here by following this table-driven pattern.
-		{"ON_MSG_DELETED", MessageEventsExchange, TopicMessageDeleted, Bind(h, h.OnMessageDeletedV1)},
-		{"ON_USR_STATUS", SystemEventsExchange, TopicUserStatus, Bind(h, h.OnStatusChangedV1)},
+		// [EPHEMERAL_TOPICS]
+		// Delivered only to connections subscribed to the routing key; bypasses user Cells.
+		{"ON_CALL_TRANSCRIPT", CallEventsExchange, TopicCallTranscript, BindTopic(h), forDelivery},
 	}
 
+	active := rolesOf(role)
 	for _, c := range configs {
+		// [ROLE_FILTER] Observer nodes skip handlers that exist only for client delivery.
+		if c.roles&active == 0 {
+			continue
+		}
+
 		instanceID := uuid.NewString()[:8]
 		// [UNIQUE_HANDLER_QUEUE]
 		// We create a unique queue for EACH handler on THIS node.
 		// Format: im-delivery.node.b23a8f12.ON_MSG_CREATED
 		handlerQueue := fmt.Sprintf("%s.%s.%s", DeliveryProcessorQueue, instanceID, c.name)
 
-		sub, err := subProvider.Build(handlerQueue, c.exchange, c.topic)
+		sub, err := subProvider.BuildRestartable(c.name, handlerQueue, c.exchange, c.topic, h.logger)
 		if err != nil {
 			return err
 		}
 
-		router.AddConsumerHandler(c.name, c.topic, sub, c.handler).AddMiddleware(
+		// [ORDER] Telemetry is outermost so its latency includes retries;
+		// the poison queue wraps retry so only exhausted messages reach it, tagged with their source;
+		// the timeout bounds each attempt; panic recovery lives inside Bind, closest to the handler.
+		policy := policyFor(c.name, policies)
+		mws := []message.HandlerMiddleware{
+			TelemetryMiddleware,
 			TraceIDMiddleware,
 			LoggingMiddleware(h.logger),
-			NewRetryMiddleware().Middleware,
+		}
+		if policy.Throttle > 0 {
+			mws = append(mws, middleware.NewThrottle(policy.Throttle, time.Second).Middleware)
+		}
+		mws = append(mws,
+			SourceMetadataMiddleware(c.exchange),
 			poison,
-			middleware.NewThrottle(100, time.Second).Middleware,
-			middleware.Timeout(time.Second*30),
+			NewRetryMiddleware(c.name, policy).Middleware,
+			AttemptMetadataMiddleware,
 		)
+		if policy.Timeout > 0 {
+			mws = append(mws, middleware.Timeout(policy.Timeout))
+		}
+		router.AddConsumerHandler(c.name, c.topic, sub, c.handler).AddMiddleware(mws...)
+	}
+
+	if err := h.registerPoison(router, subProvider, role); err != nil {
+		return err
+	}
+
+	h.logger.Info("AMQP_PIPELINE_READY", "queue", DeliveryProcessorQueue, "role", role)
+	return nil
+}
+
+// poisonExchange is where this role's poison middleware publishes: the dispatcher's exchange.
+func poisonExchange(role string) string {
+	if role == config.RoleObserver {
+		return ObserverExchange
+	}
+	return DeliveryExchange
+}
+
+// [POISON_QUEUES]
+// Poisoned messages are bound to two durable queues shared by all nodes:
+// the inspect queue is consumed here and recorded once cluster-wide,
+// the parking queue is only declared and keeps them for `poison replay`.
+func (h *MessageHandler) registerPoison(router *message.Router, subProvider *pubsub.SubscriberProvider, role string) error {
+	exchange := poisonExchange(role)
+
+	parking, err := subProvider.BuildShared(PoisonParkingQueue, exchange, DeliveryPoisonTopic)
+	if err != nil {
+		return fmt.Errorf("POISON_PARKING_FAILED: %w", err)
+	}
+	err = DeclarePoisonParking(parking)
+	_ = parking.Close()
+	if err != nil {
+		return fmt.Errorf("POISON_PARKING_FAILED: %w", err)
+	}
+
+	sub, err := pubsub.NewRestartableSubscriber("ON_POISON", func() (message.Subscriber, error) {
+		return subProvider.BuildShared(PoisonInspectQueue, exchange, DeliveryPoisonTopic)
+	}, h.logger)
+	if err != nil {
+		return err
 	}
 
-	h.logger.Info("AMQP_PIPELINE_READY", "queue", DeliveryProcessorQueue)
+	// [NO_RETRY] The recorder only logs and counts; neither poison nor retry wraps it.
+	router.AddConsumerHandler("ON_POISON", DeliveryPoisonTopic, sub, BindPoison(h)).AddMiddleware(
+		TelemetryMiddleware,
+		TraceIDMiddleware,
+		LoggingMiddleware(h.logger),
+	)
 	return nil
 }
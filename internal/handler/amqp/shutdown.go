@@ -0,0 +1,44 @@
+package amqp
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"go.uber.org/fx"
+)
+
+// ShutdownSequence orders the delivery node's stop: consumption ends first, then
+// the Hub drains, and only then does the gRPC server's own hook close what is left.
+//
+// [ROOT_ONLY] fx runs OnStop hooks in reverse registration order and module invokes
+// before root ones, so this must be wired at the application root (see cmd) to be
+// registered last and therefore stopped first.
+var ShutdownSequence = fx.Invoke(func(
+	lc fx.Lifecycle,
+	router *message.Router,
+	deliverer service.Deliverer,
+	cfg *config.Config,
+	logger *slog.Logger,
+) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			// 1. [STOP_CONSUMING] No new events enter a Hub that is about to drain.
+			// Router.Close is idempotent, so the pipeline's own hook stays harmless.
+			if err := router.Close(); err != nil {
+				logger.Error("router close error", "err", err)
+			}
+
+			// 2. [GRACEFUL_DRAIN] Bounded by both the configured window and fx's stop timeout.
+			deadline := time.Now().Add(cfg.Delivery.DrainTimeout)
+			if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+				deadline = d
+			}
+			deliverer.Drain(deadline)
+			return nil
+		},
+	})
+})
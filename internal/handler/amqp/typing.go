@@ -0,0 +1,86 @@
+package amqp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// typingRate and typingBurst bound indicators per recipient and thread.
+	typingRate  = 2.0 // tokens per second
+	typingBurst = 2.0
+
+	// typingDefaultTTL applies when the producer sent no expires_at.
+	typingDefaultTTL = 5 * time.Second
+
+	// typingIdleAfter is how long a full bucket lingers before it is pruned.
+	typingIdleAfter = 30 * time.Second
+)
+
+type typingKey struct {
+	recipient uuid.UUID
+	thread    uuid.UUID
+}
+
+type typingBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// typingLimiter is a per-(recipient, thread) token bucket.
+//
+// [STRATEGY] A client that re-sends "typing" on every keystroke would otherwise fill
+// the recipient's mailbox with indicators; beyond the rate they are dropped at the
+// consumer, before the Hub is ever involved.
+type typingLimiter struct {
+	mu        sync.Mutex
+	buckets   map[typingKey]*typingBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newTypingLimiter() *typingLimiter {
+	return &typingLimiter{
+		buckets: make(map[typingKey]*typingBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow reports whether one more indicator may go to recipient for thread.
+func (l *typingLimiter) Allow(recipient, thread uuid.UUID) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	k := typingKey{recipient, thread}
+	b, ok := l.buckets[k]
+	if !ok {
+		b = &typingBucket{tokens: typingBurst, last: now}
+		l.buckets[k] = b
+	}
+
+	b.tokens = min(typingBurst, b.tokens+now.Sub(b.last).Seconds()*typingRate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep drops buckets idle long enough to have refilled, at most once per idle window.
+func (l *typingLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < typingIdleAfter {
+		return
+	}
+	l.lastSweep = now
+	for k, b := range l.buckets {
+		if now.Sub(b.last) >= typingIdleAfter {
+			delete(l.buckets, k)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	grpcinterceptors "github.com/webitel/im-delivery-service/infra/server/grpc/interceptors"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListConnections describes the sessions a user holds on this node.
+func (d *DeliveryService) ListConnections(ctx context.Context, req *impb.ListConnectionsRequest) (*impb.ListConnectionsResponse, error) {
+	viewer, err := viewerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := targetUser(req.GetUserId(), viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := d.deliverer.ListConnections(ctx, userID, viewer)
+	if err != nil {
+		if errors.Is(err, service.ErrListConnectionsForbidden) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &impb.ListConnectionsResponse{
+		Connections: make([]*impb.Connection, len(sessions)),
+		NodeId:      d.cfg.Service.ID,
+	}
+	for i, s := range sessions {
+		res.Connections[i] = mapConnection(s)
+	}
+	return res, nil
+}
+
+// viewerFromContext identifies the caller; the auth interceptor has already run.
+func viewerFromContext(ctx context.Context) (service.DeliveryViewer, error) {
+	auth, ok := grpcinterceptors.GetAuthContact(ctx)
+	if !ok {
+		return service.DeliveryViewer{}, status.Error(codes.Unauthenticated, "authentication context missing")
+	}
+	userID, err := uuid.Parse(auth.ContactID)
+	if err != nil && !auth.HasScope(model.ScopeService) {
+		return service.DeliveryViewer{}, status.Error(codes.InvalidArgument, "invalid user id format")
+	}
+	return service.DeliveryViewer{UserID: userID, ServiceScope: auth.HasScope(model.ScopeService)}, nil
+}
+
+// targetUser parses the requested user ID; empty means the caller.
+func targetUser(raw string, viewer service.DeliveryViewer) (uuid.UUID, error) {
+	if raw == "" {
+		if viewer.UserID == uuid.Nil {
+			return uuid.Nil, status.Error(codes.InvalidArgument, "user_id is required")
+		}
+		return viewer.UserID, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	return id, nil
+}
+
+func mapConnection(s registry.SessionInfo) *impb.Connection {
+	res := &impb.Connection{
+		Id:        s.ConnID.String(),
+		CreatedAt: s.CreatedAt.UnixMilli(),
+		Dropped:   s.Dropped,
+		Metadata: &impb.ConnectionMetadata{
+			Platform:  s.Metadata.Platform,
+			Version:   s.Metadata.Version,
+			DeviceId:  s.Metadata.DeviceID,
+			RemoteIp:  s.Metadata.RemoteIP,
+			UserAgent: s.Metadata.UserAgent,
+		},
+	}
+	if !s.LastActivity.IsZero() {
+		res.LastActivity = s.LastActivity.UnixMilli()
+	}
+	return res
+}
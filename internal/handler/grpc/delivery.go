@@ -1,31 +1,87 @@
 package grpc
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
 	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
 	grpcinterceptors "github.com/webitel/im-delivery-service/infra/server/grpc/interceptors"
 	"github.com/webitel/im-delivery-service/internal/domain/event"
 	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
 	grpcmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/gprc"
 	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 var _ impb.DeliveryServer = (*DeliveryService)(nil)
 
+// LastEventIDMetadataKey and LastEventTSMetadataKey are the [LEGACY_RESUME] fallback for
+// clients built before StreamRequest.last_event_id/last_event_ts; they are read only
+// when the request carries neither field.
+const (
+	LastEventIDMetadataKey = "last-event-id"
+	LastEventTSMetadataKey = "last-event-ts"
+)
+
+// WatchPresenceMetadataKey is the fallback for clients built before
+// StreamRequest.watch_presence, read only when the request lists no contacts.
+// Values may repeat or be comma-separated.
+const WatchPresenceMetadataKey = "watch-presence"
+
+// Client description keys: the fallback for clients built before StreamRequest.platform,
+// app_version and device_id, read per field when the request leaves it empty.
+// The user agent is always the standard header.
+const (
+	PlatformMetadataKey   = "platform"
+	AppVersionMetadataKey = "app-version"
+	DeviceIDMetadataKey   = "device-id"
+)
+
+// EventKindsMetadataKey is the fallback for clients built before StreamRequest.event_kinds,
+// read only when the request lists no kinds. It names domain kinds (e.g. "MessageCreated");
+// unknown names are ignored. Parsed like WatchPresenceMetadataKey.
+const EventKindsMetadataKey = "event-kinds"
+
+// streamKinds maps the filterable StreamRequest kinds to domain event kinds.
+var streamKinds = map[impb.EventKind]event.EventKind{
+	impb.EventKind_EVENT_KIND_MESSAGE_CREATED: event.MessageCreated,
+	impb.EventKind_EVENT_KIND_MESSAGE_UPDATED: event.MessageUpdated,
+	impb.EventKind_EVENT_KIND_MESSAGE_DELETED: event.MessageDeleted,
+	impb.EventKind_EVENT_KIND_TYPING:          event.Typing,
+	impb.EventKind_EVENT_KIND_READ_RECEIPT:    event.ReadReceipt,
+	impb.EventKind_EVENT_KIND_REACTION:        event.MessageReaction,
+	impb.EventKind_EVENT_KIND_PRESENCE_STATUS: event.PresenceStatus,
+}
+
 type DeliveryService struct {
 	logger    *slog.Logger
 	deliverer service.Deliverer
+	inspector *service.DeliveryInspector
+	cfg       *config.Config
 	impb.UnimplementedDeliveryServer
 }
 
-func NewDeliveryService(logger *slog.Logger, deliverer service.Deliverer) *DeliveryService {
+func NewDeliveryService(logger *slog.Logger, deliverer service.Deliverer, inspector *service.DeliveryInspector, cfg *config.Config) *DeliveryService {
 	return &DeliveryService{
 		logger:    logger,
 		deliverer: deliverer,
+		inspector: inspector,
+		cfg:       cfg,
 	}
 }
 
@@ -47,9 +103,11 @@ func (d *DeliveryService) Stream(req *impb.StreamRequest, stream impb.Delivery_S
 	}
 
 	// Create a stream-scoped logger to track this specific connection
+	md := connectMetadata(stream.Context(), req)
 	l := d.logger.With(
 		slog.String("user_id", userID.String()),
 		slog.String("session_id", uuid.NewString()),
+		slog.Any("client", md),
 	)
 
 	l.Info("[STREAM] incoming connection request", slog.String("version", model.ServerVersion))
@@ -57,8 +115,35 @@ func (d *DeliveryService) Stream(req *impb.StreamRequest, stream impb.Delivery_S
 	// [ACTOR_ATTACHMENT]
 	// Subscribe links this specific gRPC stream to the User's Virtual Cell (Actor).
 	// This ensures all events routed to the Hub for this UserID will reach this stream.
-	conn, err := d.deliverer.Subscribe(stream.Context(), userID)
+	resumeID, resumeTS := resumePosition(stream.Context(), req)
+	conn, err := d.deliverer.Subscribe(stream.Context(), userID, service.SubscribeOptions{
+		Transport:     service.TransportGRPC,
+		DomainID:      auth.DC,
+		LastEventID:   resumeID,
+		LastEventTS:   resumeTS,
+		Platform:      md.Platform,
+		Metadata:      md,
+		WatchPresence: watchPresence(stream.Context(), req),
+		Kinds:         eventKinds(stream.Context(), req),
+	})
 	if err != nil {
+		if errors.Is(err, deprecation.ErrRejected) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+
+		// [GRACEFUL_DRAIN] Unavailable + RetryInfo makes generic clients back off and redial.
+		var drainErr *service.DrainingError
+		if errors.As(err, &drainErr) {
+			return retryStatus(registry.ReasonServerDraining, drainErr.Hint.RetryAfter)
+		}
+
+		// [DATA_RESIDENCY] Tell the client where to redial instead of a generic failure.
+		var resErr *service.ResidencyError
+		if errors.As(err, &resErr) {
+			l.Warn("[HUB] subscription rejected by residency policy", slog.Any("allowed_regions", resErr.AllowedRegions))
+			return status.Error(codes.FailedPrecondition, resErr.Error())
+		}
+
 		l.Error("[HUB] subscription rejected", slog.Any("err", err))
 		return status.Error(codes.Internal, "failed to establish connection session")
 	}
@@ -67,27 +152,47 @@ func (d *DeliveryService) Stream(req *impb.StreamRequest, stream impb.Delivery_S
 	// Ensure the connector is detached from the Hub when the function returns.
 	// This prevents memory leaks and ensures the Hub doesn't try to send to a dead stream.
 	defer func() {
-		d.deliverer.Unsubscribe(userID, conn.GetID())
+		// Read before Unsubscribe: the stats are final once the session is retired.
+		connID, dropped := conn.GetID(), conn.Dropped()
+		d.deliverer.Unsubscribe(userID, connID)
 		l.Info("[STREAM] connection closed and resources reclaimed",
-			slog.String("conn_id", conn.GetID().String()),
+			slog.String("conn_id", connID.String()),
+			slog.Uint64("dropped", dropped),
 		)
 	}()
 
 	l.Info("[STREAM] session established", slog.String("conn_id", conn.GetID().String()))
 
+	// [DELIVERY_SHAPING] Deferred after Unsubscribe so it stops first.
+	events, stopShaping := d.deliverer.Shape(conn, auth.DC)
+	defer stopShaping()
+
 	// [HANDSHAKE_LOGIC]
 	// Create the payload from model package.
 	welcomeEv := event.NewSystemEvent(userID, event.Connected, event.PriorityNormal, &model.ConnectedPayload{
 		Ok:            true,
 		ConnectionID:  conn.GetID().String(),
 		ServerVersion: model.ServerVersion,
+		BufferSize:    cap(conn.Recv()),
+		Capabilities:  d.deliverer.Capabilities(auth.DC),
+		Deprecations:  streamDeprecations(resumeID),
 	})
 
-	if err := stream.Send(grpcmarshaller.MarshallDeliveryEvent(welcomeEv)); err != nil {
+	// [SEND_DEADLINE] A peer that stops reading must not hold this goroutine or its Cell slot.
+	sender := newBoundedSender(stream.Context(), stream, d.cfg.Delivery.SendTimeout)
+
+	if err := sender.Send(grpcmarshaller.MarshallDeliveryEvent(welcomeEv)); err != nil {
 		l.Error("[STREAM] handshake delivery failed", slog.Any("err", err))
+		if errors.Is(err, errSendTimeout) {
+			return sendTimedOut()
+		}
 		return err
 	}
 
+	// [HEARTBEAT] Keeps idle streams visible to proxies; every successful write re-arms it.
+	idle := newHeartbeat(d.cfg.Delivery.Heartbeat)
+	defer idle.Stop()
+
 	// [EVENT_LOOP]
 	// Main delivery loop that bridges the internal Actor mailbox with the gRPC stream.
 	for {
@@ -98,36 +203,201 @@ func (d *DeliveryService) Stream(req *impb.StreamRequest, stream impb.Delivery_S
 			l.Info("[STREAM] client terminated connection", slog.Any("reason", stream.Context().Err()))
 			return nil
 
-		case ev, ok := <-conn.Recv():
-			if !ok {
-				// [TERMINATION_SENTINEL]
-				// Before returning the gRPC error, we push a final System Event to the wire.
-				l.Warn("[HUB] mailbox closed, sending termination event")
+		case <-conn.Done():
+			// The connector inherits the stream context; a client-side hang-up is not a server termination.
+			if stream.Context().Err() != nil {
+				return nil
+			}
+
+			// [TERMINATION_SENTINEL]
+			// Before returning the gRPC error, we push a final System Event to the wire.
+			l.Warn("[HUB] connector closed, sending termination event")
 
-				terminationEv := event.NewSystemEvent(userID, event.Disconnected, event.PriorityHigh, &model.DisconnectedPayload{
-					Reason: "session_closed_by_server",
-				})
+			hint := d.deliverer.DisconnectHint(reconnect.ReasonSessionClosed)
+			terminationEv := event.NewSystemEvent(userID, event.Disconnected, event.PriorityHigh, &model.DisconnectedPayload{
+				Reason:        terminationReason(conn),
+				Code:          string(hint.Reason),
+				RetryAfterMs:  hint.RetryAfterMs(),
+				AlternateNode: hint.AlternateNode,
+			})
 
-				// Send the "goodbye" message. We ignore the error here because if the
-				// transport is already failing, we just proceed to return the status.
-				_ = stream.Send(grpcmarshaller.MarshallDeliveryEvent(terminationEv))
+			// Send the "goodbye" message. We ignore the error here because if the
+			// transport is already failing, we just proceed to return the status.
+			_ = sender.Send(grpcmarshaller.MarshallDeliveryEvent(terminationEv))
 
-				return status.Error(codes.Unavailable, "session_terminated_by_server")
+			return retryStatus("session_terminated_by_server", hint.RetryAfter)
+
+		case <-idle.C():
+			pingEv := event.NewSystemEvent(userID, event.Ping, event.PriorityLow, &model.PingPayload{
+				ServerTime: time.Now().UnixMilli(),
+			})
+			if err := sender.Send(grpcmarshaller.MarshallDeliveryEvent(pingEv)); err != nil {
+				l.Warn("[STREAM] heartbeat write failed", slog.Any("err", err))
+				if errors.Is(err, errSendTimeout) {
+					return sendTimedOut()
+				}
+				return status.Error(codes.DataLoss, "stream_transmission_failed")
 			}
+			idle.Reset()
+
+		case ev := <-events:
 
 			// [TRANSMIT_OVER_HTTP2]
 			// Serialize and push the event into the gRPC transmit buffer.
 			// gRPC handles internal flow control and HTTP/2 framing.
-			if err := stream.Send(grpcmarshaller.MarshallDeliveryEvent(ev)); err != nil {
+			marshalStart := time.Now()
+			pb := grpcmarshaller.MarshallDeliveryEvent(ev)
+			writeStart := time.Now()
+
+			if err := sender.Send(pb); err != nil {
 				l.Error("[STREAM] transmission error",
 					slog.Any("err", err),
 					slog.String("event_id", ev.GetID()),
 				)
+				if errors.Is(err, errSendTimeout) {
+					return sendTimedOut()
+				}
 				// Returning error here triggers a gRPC status code (DataLoss) to the client.
 				return status.Error(codes.DataLoss, "stream_transmission_failed")
 			}
+			idle.Reset()
 
+			d.deliverer.RecordDelivery(conn, ev, writeStart.Sub(marshalStart), time.Since(writeStart))
 			l.Debug("[STREAM] event pushed to wire", slog.String("event_type", ev.GetKind().String()))
 		}
 	}
 }
+
+// resumePosition returns the resume ID and timestamp from the request, or from the
+// [LEGACY_RESUME] metadata keys when the request sets neither.
+func resumePosition(ctx context.Context, req *impb.StreamRequest) (string, int64) {
+	if req.GetLastEventId() != "" || req.GetLastEventTs() > 0 {
+		return req.GetLastEventId(), max(req.GetLastEventTs(), 0)
+	}
+	return lastEventID(ctx), lastEventTS(ctx)
+}
+
+// lastEventID returns the [LEGACY_RESUME] event ID sent by the client, if any.
+func lastEventID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(LastEventIDMetadataKey); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// lastEventTS parses LastEventTSMetadataKey; 0 when absent or malformed.
+func lastEventTS(ctx context.Context) int64 {
+	for _, v := range metadataList(ctx, LastEventTSMetadataKey) {
+		if ts, err := strconv.ParseInt(v, 10, 64); err == nil && ts > 0 {
+			return ts
+		}
+	}
+	return 0
+}
+
+// watchPresence parses the request's watch list, or WatchPresenceMetadataKey when it
+// is empty, skipping IDs that are not UUIDs.
+func watchPresence(ctx context.Context, req *impb.StreamRequest) []uuid.UUID {
+	ids := req.GetWatchPresence()
+	if len(ids) == 0 {
+		ids = metadataList(ctx, WatchPresenceMetadataKey)
+	}
+	var res []uuid.UUID
+	for _, part := range ids {
+		if id, err := uuid.Parse(part); err == nil {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// eventKinds maps the request's kind filter, or parses EventKindsMetadataKey when the
+// request sets none, skipping unknown kinds.
+func eventKinds(ctx context.Context, req *impb.StreamRequest) []event.EventKind {
+	var res []event.EventKind
+	if kinds := req.GetEventKinds(); len(kinds) > 0 {
+		for _, k := range kinds {
+			if kind, ok := streamKinds[k]; ok {
+				res = append(res, kind)
+			}
+		}
+		return res
+	}
+	for _, part := range metadataList(ctx, EventKindsMetadataKey) {
+		if k, ok := event.ParseEventKind(part); ok {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+// metadataList flattens a metadata key whose values may repeat or be comma-separated.
+func metadataList(ctx context.Context, key string) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	var res []string
+	for _, v := range md.Get(key) {
+		for part := range strings.SplitSeq(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				res = append(res, part)
+			}
+		}
+	}
+	return res
+}
+
+// connectMetadata describes the calling client for support tooling.
+func connectMetadata(ctx context.Context, req *impb.StreamRequest) registry.ConnectMetadata {
+	var md registry.ConnectMetadata
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		md.RemoteIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(md.RemoteIP); err == nil {
+			md.RemoteIP = host
+		}
+	}
+	in, _ := metadata.FromIncomingContext(ctx)
+	first := func(field, key string) string {
+		if field = strings.TrimSpace(field); field != "" {
+			return field
+		}
+		if v := in.Get(key); len(v) > 0 {
+			return strings.TrimSpace(v[0])
+		}
+		return ""
+	}
+	md.Platform = first(req.GetPlatform(), PlatformMetadataKey)
+	md.Version = first(req.GetAppVersion(), AppVersionMetadataKey)
+	md.DeviceID = first(req.GetDeviceId(), DeviceIDMetadataKey)
+	md.UserAgent = first("", "user-agent")
+	return md
+}
+
+// sendTimedOut records a slow-send disconnect and closes the stream with DeadlineExceeded.
+func sendTimedOut() error {
+	service.RecordStuckWrite(context.Background(), service.TransportGRPC, service.StuckWriteDeadline)
+	return status.Error(codes.DeadlineExceeded, "stream_send_timeout")
+}
+
+// terminationReason is the operator-supplied close reason, or the generic one.
+func terminationReason(conn registry.Connector) string {
+	if r := conn.CloseReason(); r != "" {
+		return r
+	}
+	return "session_closed_by_server"
+}
+
+// retryStatus is an Unavailable status carrying the backoff as a RetryInfo detail.
+// [RETRY_INFO] Standard detail so generic gRPC clients honor the same backoff.
+func retryStatus(msg string, retryAfter time.Duration) error {
+	st := status.New(codes.Unavailable, msg)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
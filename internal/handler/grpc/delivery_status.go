@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetDeliveryStatus returns the delivery timeline of a message, from this node or the cluster.
+func (d *DeliveryService) GetDeliveryStatus(ctx context.Context, req *impb.GetDeliveryStatusRequest) (*impb.DeliveryStatus, error) {
+	viewer, err := viewerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	messageID, err := uuid.Parse(req.GetMessageId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid message_id")
+	}
+
+	get := d.inspector.GetDeliveryStatus
+	if req.GetCluster() {
+		get = d.inspector.GetClusterDeliveryStatus
+	}
+	st, err := get(ctx, messageID, viewer)
+	if err != nil {
+		if errors.Is(err, service.ErrDeliveryStatusNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return mapDeliveryStatus(st), nil
+}
+
+func mapDeliveryStatus(st *service.DeliveryStatus) *impb.DeliveryStatus {
+	res := &impb.DeliveryStatus{
+		MessageId:        st.MessageID.String(),
+		Timeline:         make([]*impb.DeliveryStep, len(st.Timeline)),
+		Truncated:        st.Truncated,
+		Staleness:        st.Staleness,
+		ObservedAt:       st.ObservedAt.UnixMilli(),
+		Nodes:            st.Nodes,
+		UnreachableNodes: st.Unreachable,
+	}
+	for i, s := range st.Timeline {
+		step := &impb.DeliveryStep{
+			UserId:  s.UserID.String(),
+			EventId: s.EventID,
+			Kind:    s.Kind,
+			Outcome: s.Outcome,
+			At:      s.At.UnixMilli(),
+			NodeId:  s.NodeID,
+		}
+		if s.ConnID != uuid.Nil {
+			step.ConnectionId = s.ConnID.String()
+		}
+		res.Timeline[i] = step
+	}
+	return res
+}
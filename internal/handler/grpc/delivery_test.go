@@ -0,0 +1,187 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter time.Duration
+	}{
+		{name: "sub-second", retryAfter: 250 * time.Millisecond},
+		{name: "seconds", retryAfter: 12 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(retryStatus("session_terminated_by_server", tt.retryAfter))
+			if !ok || st.Code() != codes.Unavailable {
+				t.Fatalf("status = %v, want Unavailable", st)
+			}
+			for _, d := range st.Details() {
+				if info, ok := d.(*errdetails.RetryInfo); ok {
+					if got := info.GetRetryDelay().AsDuration(); got != tt.retryAfter {
+						t.Fatalf("RetryInfo delay = %v, want %v", got, tt.retryAfter)
+					}
+					return
+				}
+			}
+			t.Fatalf("no RetryInfo detail in %v", st.Details())
+		})
+	}
+}
+
+func TestResumePosition(t *testing.T) {
+	legacy := metadata.Pairs(LastEventIDMetadataKey, "evt-md", LastEventTSMetadataKey, "1700000000000")
+
+	tests := []struct {
+		name   string
+		req    *impb.StreamRequest
+		md     metadata.MD
+		wantID string
+		wantTS int64
+	}{
+		{name: "fresh stream", req: &impb.StreamRequest{}},
+		{name: "request fields", req: &impb.StreamRequest{LastEventId: "evt-1", LastEventTs: 1700000000123}, wantID: "evt-1", wantTS: 1700000000123},
+		{name: "timestamp only", req: &impb.StreamRequest{LastEventTs: 1700000000123}, wantTS: 1700000000123},
+		{name: "request wins over metadata", req: &impb.StreamRequest{LastEventId: "evt-1"}, md: legacy, wantID: "evt-1"},
+		{name: "metadata fallback", req: &impb.StreamRequest{}, md: legacy, wantID: "evt-md", wantTS: 1700000000000},
+		{name: "negative timestamp ignored", req: &impb.StreamRequest{LastEventId: "evt-1", LastEventTs: -5}, wantID: "evt-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			id, ts := resumePosition(ctx, tt.req)
+			if id != tt.wantID || ts != tt.wantTS {
+				t.Fatalf("got (%q, %d), want (%q, %d)", id, ts, tt.wantID, tt.wantTS)
+			}
+		})
+	}
+}
+
+func TestWatchPresence(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	legacy := metadata.Pairs(WatchPresenceMetadataKey, b.String()+", not-a-uuid")
+
+	tests := []struct {
+		name string
+		req  *impb.StreamRequest
+		md   metadata.MD
+		want []uuid.UUID
+	}{
+		{name: "none", req: &impb.StreamRequest{}},
+		{name: "request field", req: &impb.StreamRequest{WatchPresence: []string{a.String(), "bogus", b.String()}}, want: []uuid.UUID{a, b}},
+		{name: "request wins over metadata", req: &impb.StreamRequest{WatchPresence: []string{a.String()}}, md: legacy, want: []uuid.UUID{a}},
+		{name: "metadata fallback", req: &impb.StreamRequest{}, md: legacy, want: []uuid.UUID{b}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			if got := watchPresence(ctx, tt.req); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventKinds(t *testing.T) {
+	legacy := metadata.Pairs(EventKindsMetadataKey, "typing,Nope")
+
+	tests := []struct {
+		name string
+		req  *impb.StreamRequest
+		md   metadata.MD
+		want []event.EventKind
+	}{
+		{name: "all kinds", req: &impb.StreamRequest{}},
+		{
+			name: "request filter",
+			req: &impb.StreamRequest{EventKinds: []impb.EventKind{
+				impb.EventKind_EVENT_KIND_MESSAGE_CREATED, impb.EventKind_EVENT_KIND_UNSPECIFIED, impb.EventKind_EVENT_KIND_PRESENCE_STATUS,
+			}},
+			want: []event.EventKind{event.MessageCreated, event.PresenceStatus},
+		},
+		{
+			name: "request wins over metadata",
+			req:  &impb.StreamRequest{EventKinds: []impb.EventKind{impb.EventKind_EVENT_KIND_REACTION}},
+			md:   legacy,
+			want: []event.EventKind{event.MessageReaction},
+		},
+		{name: "metadata fallback", req: &impb.StreamRequest{}, md: legacy, want: []event.EventKind{event.Typing}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			if got := eventKinds(ctx, tt.req); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStreamKindsCoverProtoEnum fails when a filterable kind is added to the proto
+// without a domain mapping.
+func TestStreamKindsCoverProtoEnum(t *testing.T) {
+	for v := range impb.EventKind_name {
+		k := impb.EventKind(v)
+		if k == impb.EventKind_EVENT_KIND_UNSPECIFIED {
+			continue
+		}
+		if _, ok := streamKinds[k]; !ok {
+			t.Errorf("%v has no domain kind", k)
+		}
+	}
+}
+
+func TestConnectMetadata(t *testing.T) {
+	legacy := metadata.Pairs(
+		PlatformMetadataKey, "android", AppVersionMetadataKey, "1.0.0", DeviceIDMetadataKey, "dev-md",
+		"user-agent", "grpc-go/1.78",
+	)
+
+	tests := []struct {
+		name string
+		req  *impb.StreamRequest
+		md   metadata.MD
+		want registry.ConnectMetadata
+	}{
+		{name: "nothing sent", req: &impb.StreamRequest{}},
+		{
+			name: "request fields",
+			req:  &impb.StreamRequest{Platform: "ios", AppVersion: "2.1.0", DeviceId: "dev-1"},
+			want: registry.ConnectMetadata{Platform: "ios", Version: "2.1.0", DeviceID: "dev-1"},
+		},
+		{
+			name: "request wins per field",
+			req:  &impb.StreamRequest{Platform: "ios", DeviceId: " "},
+			md:   legacy,
+			want: registry.ConnectMetadata{Platform: "ios", Version: "1.0.0", DeviceID: "dev-md", UserAgent: "grpc-go/1.78"},
+		},
+		{
+			name: "metadata fallback",
+			req:  &impb.StreamRequest{},
+			md:   legacy,
+			want: registry.ConnectMetadata{Platform: "android", Version: "1.0.0", DeviceID: "dev-md", UserAgent: "grpc-go/1.78"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			if got := connectMetadata(ctx, tt.req); got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+)
+
+// streamDeprecations names the deprecated features a stream relies on, for its handshake.
+// [NO_CURSOR] StreamRequest resumes by event ID only, so any last_event_id is a raw one;
+// Subscribe has already counted the use, or rejected the stream.
+func streamDeprecations(lastEventID string) []string {
+	if lastEventID == "" {
+		return nil
+	}
+	return deprecation.Names(service.DeprecatedRawLastEventID)
+}
+
+// GetDeprecationUsage reports the use counts of every deprecated feature on this node.
+// The service-scope interceptor has already authenticated the caller.
+func (a *AdminService) GetDeprecationUsage(context.Context, *impb.GetDeprecationUsageRequest) (*impb.GetDeprecationUsageResponse, error) {
+	return mapDeprecationUsage(deprecation.GetDeprecationUsage()), nil
+}
+
+func mapDeprecationUsage(usage []deprecation.Usage) *impb.GetDeprecationUsageResponse {
+	res := &impb.GetDeprecationUsageResponse{Features: make([]*impb.DeprecationUsage, len(usage))}
+	for i, u := range usage {
+		f := &impb.DeprecationUsage{
+			Feature:     u.Feature,
+			Description: u.Description,
+			Mode:        mapDeprecationMode(u.Mode),
+			Count:       u.Count,
+		}
+		if !u.LastSeen.IsZero() {
+			f.LastSeen = u.LastSeen.UnixMilli()
+		}
+		res.Features[i] = f
+	}
+	return res
+}
+
+func mapDeprecationMode(m deprecation.Mode) impb.DeprecationMode {
+	switch m {
+	case deprecation.ModeWarn:
+		return impb.DeprecationMode_DEPRECATION_WARN
+	case deprecation.ModeReject:
+		return impb.DeprecationMode_DEPRECATION_REJECT
+	default:
+		return impb.DeprecationMode_DEPRECATION_MODE_UNSPECIFIED
+	}
+}
@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStreamDeprecations(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string
+	}{
+		{name: "fresh stream", want: nil},
+		{name: "raw resume", lastEventID: "evt-1", want: []string{service.DeprecatedRawLastEventID.Name()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := streamDeprecations(tt.lastEventID); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapDeprecationUsage(t *testing.T) {
+	seen := time.UnixMilli(1700000000123)
+	tests := []struct {
+		name  string
+		usage deprecation.Usage
+		want  *impb.DeprecationUsage
+	}{
+		{
+			name:  "never used",
+			usage: deprecation.Usage{Feature: "api_v1", Description: "d", Mode: deprecation.ModeWarn},
+			want:  &impb.DeprecationUsage{Feature: "api_v1", Description: "d", Mode: impb.DeprecationMode_DEPRECATION_WARN},
+		},
+		{
+			name:  "rejected after use",
+			usage: deprecation.Usage{Feature: "raw_last_event_id", Mode: deprecation.ModeReject, Count: 7, LastSeen: seen},
+			want:  &impb.DeprecationUsage{Feature: "raw_last_event_id", Mode: impb.DeprecationMode_DEPRECATION_REJECT, Count: 7, LastSeen: seen.UnixMilli()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapDeprecationUsage([]deprecation.Usage{tt.usage})
+			if len(got.Features) != 1 || !proto.Equal(got.Features[0], tt.want) {
+				t.Fatalf("got %v, want %v", got.Features, tt.want)
+			}
+		})
+	}
+}
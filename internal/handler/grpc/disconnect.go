@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Disconnect force-closes one or all of a user's sessions on this node.
+// The service-scope interceptor has already authenticated the caller.
+func (a *AdminService) Disconnect(ctx context.Context, req *impb.DisconnectRequest) (*impb.DisconnectResponse, error) {
+	viewer, err := viewerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	connID := uuid.Nil
+	if raw := req.GetConnectionId(); raw != "" {
+		if connID, err = uuid.Parse(raw); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid connection_id")
+		}
+	}
+
+	closed, err := a.deliverer.Disconnect(ctx, userID, connID, req.GetReason(), viewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDisconnectForbidden):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, registry.ErrUserNotOnNode):
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &impb.DisconnectResponse{Closed: int32(closed)}, nil
+}
@@ -0,0 +1,39 @@
+package grpc
+
+import "time"
+
+// heartbeat fires after a stream has been idle for interval.
+// [CHEAP_IDLE] One timer per stream, re-armed on every write; no ticker wakes busy streams.
+type heartbeat struct {
+	timer    *time.Timer
+	interval time.Duration
+}
+
+// newHeartbeat returns a disarmed heartbeat (nil channel) when interval is not positive.
+func newHeartbeat(interval time.Duration) *heartbeat {
+	if interval <= 0 {
+		return &heartbeat{}
+	}
+	return &heartbeat{timer: time.NewTimer(interval), interval: interval}
+}
+
+// C fires once the stream has been idle for the interval.
+func (h *heartbeat) C() <-chan time.Time {
+	if h.timer == nil {
+		return nil
+	}
+	return h.timer.C
+}
+
+// Reset re-arms the timer after a successful write.
+func (h *heartbeat) Reset() {
+	if h.timer != nil {
+		h.timer.Reset(h.interval)
+	}
+}
+
+func (h *heartbeat) Stop() {
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+}
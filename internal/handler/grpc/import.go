@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ImportEvents feeds each streamed batch of export records to the importer and answers,
+// once the client closes its side, with one summary per batch.
+// The service-scope interceptor has already authenticated the caller.
+func (a *AdminService) ImportEvents(stream grpc.ClientStreamingServer[impb.ImportEventsRequest, impb.ImportEventsSummary]) error {
+	ctx := stream.Context()
+	res := &impb.ImportEventsSummary{}
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+
+		records, positions, rejected := decodeImportRecords(req.GetRecordsJson())
+		summary, err := a.importer.Import(ctx, records)
+		if err != nil {
+			// [PARTIAL] Batches already imported stay imported; the caller sees the stream fail.
+			return status.FromContextError(err).Err()
+		}
+		res.Batches = append(res.Batches, mapImportSummary(summary, positions, rejected))
+	}
+}
+
+// decodeImportRecords parses a batch. positions maps each decoded record back to its
+// index in the batch; records that are not valid JSON are rejected up front.
+func decodeImportRecords(raw [][]byte) (records []service.ImportRecord, positions []int, rejected []service.ImportRejection) {
+	for idx, data := range raw {
+		var rec service.ImportRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			rejected = append(rejected, service.ImportRejection{Index: idx, Reason: "malformed record: " + err.Error()})
+			continue
+		}
+		records = append(records, rec)
+		positions = append(positions, idx)
+	}
+	return records, positions, rejected
+}
+
+// mapImportSummary reports a batch against the request's indices, rejections in batch order.
+func mapImportSummary(s *service.ImportSummary, positions []int, rejected []service.ImportRejection) *impb.ImportBatchSummary {
+	for _, r := range s.Rejected {
+		rejected = append(rejected, service.ImportRejection{Index: positions[r.Index], Reason: r.Reason})
+	}
+	slices.SortFunc(rejected, func(a, b service.ImportRejection) int { return a.Index - b.Index })
+
+	res := &impb.ImportBatchSummary{
+		Accepted:      int32(s.Accepted),
+		DeliveredLive: int32(s.DeliveredLive),
+		Stored:        int32(s.Stored),
+		Rejected:      make([]*impb.ImportRejection, len(rejected)),
+	}
+	for i, r := range rejected {
+		res.Rejected[i] = &impb.ImportRejection{Index: int32(r.Index), Reason: r.Reason}
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// importStream replays prepared batches and keeps the summary the handler closes with.
+type importStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	batches []*impb.ImportEventsRequest
+	summary *impb.ImportEventsSummary
+}
+
+func (s *importStream) Context() context.Context { return s.ctx }
+
+func (s *importStream) Recv() (*impb.ImportEventsRequest, error) {
+	if len(s.batches) == 0 {
+		return nil, io.EOF
+	}
+	req := s.batches[0]
+	s.batches = s.batches[1:]
+	return req, nil
+}
+
+func (s *importStream) SendAndClose(res *impb.ImportEventsSummary) error {
+	s.summary = res
+	return nil
+}
+
+// TestImportEvents streams two batches through the handler and checks that each gets its
+// own summary, with rejections reported against the record's position in its batch.
+func TestImportEvents(t *testing.T) {
+	hub := registry.NewHub()
+	defer hub.Shutdown()
+	cfg := &config.Config{}
+	cfg.Delivery.Import.SpillPerUser = 1
+	admin := NewAdminService(nil, nil, service.NewEventImporter(hub, cfg), nil)
+
+	online, offline := uuid.New(), uuid.New()
+	conn := registry.NewConnector(t.Context(), online, 8, registry.ConnectMetadata{})
+	hub.Register(conn)
+
+	record := func(version int, userID uuid.UUID) []byte {
+		data, err := json.Marshal(service.ImportRecord{Version: version, UserID: userID, Message: &model.Message{ID: uuid.New(), Text: "legacy"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	stream := &importStream{ctx: t.Context(), batches: []*impb.ImportEventsRequest{
+		{RecordsJson: [][]byte{record(service.ImportFormatV1, online), []byte("{"), record(service.ImportFormatV1, offline)}},
+		{RecordsJson: [][]byte{record(2, online), record(service.ImportFormatV1, offline), record(service.ImportFormatV1, online)}},
+	}}
+	if err := admin.ImportEvents(stream); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &impb.ImportEventsSummary{Batches: []*impb.ImportBatchSummary{
+		{
+			Accepted: 2, DeliveredLive: 1, Stored: 1,
+			Rejected: []*impb.ImportRejection{{Index: 1, Reason: "malformed record: unexpected end of JSON input"}},
+		},
+		{
+			Accepted: 1, DeliveredLive: 1,
+			Rejected: []*impb.ImportRejection{
+				{Index: 0, Reason: "unsupported export format version: 2"},
+				{Index: 1, Reason: service.ErrImportSpillFull.Error()},
+			},
+		},
+	}}
+	if !proto.Equal(stream.summary, want) {
+		t.Fatalf("summary = %v, want %v", stream.summary, want)
+	}
+}
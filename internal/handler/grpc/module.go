@@ -11,13 +11,19 @@ import (
 var Module = fx.Module("delivery-grpc",
 	fx.Provide(
 		NewDeliveryService,
+		NewAdminService,
+		NewSchemaCacheInvalidator,
 	),
 	fx.Invoke(RegisterDeliveryServices),
+	// [SCHEMA_CHANGE] Cached encodings built before a deprecation mode change must not reach clients.
+	fx.Invoke(RegisterSchemaCacheInvalidator),
 )
 
 func RegisterDeliveryServices(
 	server *grpcsrv.Server,
 	service *DeliveryService,
+	admin *AdminService,
 ) {
 	impb.RegisterDeliveryServer(server.Server, service)
+	impb.RegisterDeliveryAdminServer(server.Server, admin)
 }
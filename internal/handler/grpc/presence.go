@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsOnline reports whether a user is reachable.
+// The service-scope interceptor has already authenticated the caller.
+func (a *AdminService) IsOnline(_ context.Context, req *impb.IsOnlineRequest) (*impb.Presence, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	return mapPresence(a.deliverer.IsOnline(userID)), nil
+}
+
+// CheckPresence is the batched IsOnline.
+func (a *AdminService) CheckPresence(_ context.Context, req *impb.CheckPresenceRequest) (*impb.CheckPresenceResponse, error) {
+	ids := make([]uuid.UUID, len(req.GetUserIds()))
+	for i, raw := range req.GetUserIds() {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid user_ids[%d]", i)
+		}
+		ids[i] = id
+	}
+
+	presences, err := a.deliverer.CheckPresence(ids)
+	if err != nil {
+		if errors.Is(err, service.ErrPresenceBatchTooLarge) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &impb.CheckPresenceResponse{Presences: make([]*impb.Presence, len(presences))}
+	for i, p := range presences {
+		res.Presences[i] = mapPresence(p)
+	}
+	return res, nil
+}
+
+func mapPresence(p service.Presence) *impb.Presence {
+	return &impb.Presence{
+		UserId:   p.UserID.String(),
+		Online:   p.Online,
+		Sessions: int32(p.Sessions),
+		NodeId:   p.NodeID,
+	}
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ impb.DeliveryAdminServer = (*AdminService)(nil)
+
+// AdminService serves the service-scoped DeliveryAdmin RPCs.
+type AdminService struct {
+	deliverer service.Deliverer
+	slow      *service.SlowDeliveryTracker
+	importer  *service.EventImporter
+	shaper    *service.DeliveryShaper
+	impb.UnimplementedDeliveryAdminServer
+}
+
+func NewAdminService(deliverer service.Deliverer, slow *service.SlowDeliveryTracker, importer *service.EventImporter, shaper *service.DeliveryShaper) *AdminService {
+	return &AdminService{deliverer: deliverer, slow: slow, importer: importer, shaper: shaper}
+}
+
+// PushEvent injects a one-off event for a user connected to this node.
+// The service-scope interceptor has already authenticated the caller.
+func (a *AdminService) PushEvent(ctx context.Context, req *impb.PushEventRequest) (*impb.PushEventResponse, error) {
+	viewer, err := viewerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	outcome, err := a.deliverer.PushEvent(ctx, service.PushRequest{
+		UserID:   userID,
+		Kind:     req.GetKind(),
+		Payload:  req.GetPayloadJson(),
+		Priority: unmapPriority(req.GetPriority()),
+	}, viewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPushForbidden):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, service.ErrPushDisabled):
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		case errors.Is(err, service.ErrPushTooLarge):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, service.ErrPushInvalid):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &impb.PushEventResponse{Outcome: mapPushOutcome(outcome)}, nil
+}
+
+// unmapPriority converts the requested Protobuf priority; unspecified leaves the default to the service.
+func unmapPriority(p impb.EventPriority) event.EventPriority {
+	switch p {
+	case impb.EventPriority_LOW:
+		return event.PriorityLow
+	case impb.EventPriority_NORMAL:
+		return event.PriorityNormal
+	case impb.EventPriority_HIGH:
+		return event.PriorityHigh
+	default:
+		return 0
+	}
+}
+
+func mapPushOutcome(o service.PushOutcome) impb.PushOutcome {
+	switch o {
+	case service.PushAccepted:
+		return impb.PushOutcome_PUSH_ACCEPTED
+	case service.PushDropped:
+		return impb.PushOutcome_PUSH_DROPPED
+	case service.PushNotConnected:
+		return impb.PushOutcome_PUSH_NOT_CONNECTED
+	default:
+		return impb.PushOutcome_PUSH_OUTCOME_UNSPECIFIED
+	}
+}
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	grpcmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/gprc"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"go.uber.org/fx"
+)
+
+// SchemaCacheInvalidator drops cached ServerEvent encodings built by an older
+// marshaller schema, so replayed events are re-marshalled with the current one.
+//
+// [RUNTIME_SCHEMA] The mapping itself only changes with a deploy, and a fresh process
+// starts with empty replay buffers. What changes underneath live Cells is a deprecation
+// mode (see grpcmarshaller.SchemaVersion), so the purge follows mode changes.
+type SchemaCacheInvalidator struct {
+	hub    *registry.Hub
+	logger *slog.Logger
+	active atomic.Bool // The Hub's Cells accept control operations only while it runs
+}
+
+func NewSchemaCacheInvalidator(hub *registry.Hub, logger *slog.Logger) *SchemaCacheInvalidator {
+	return &SchemaCacheInvalidator{hub: hub, logger: logger}
+}
+
+// Invalidate purges every replay buffer and returns the number of events touched.
+func (i *SchemaCacheInvalidator) Invalidate() int {
+	if !i.active.Load() {
+		return 0
+	}
+	version := grpcmarshaller.SchemaVersion()
+	n := i.hub.PurgeCachedBefore(version)
+	if n > 0 {
+		i.logger.Info("SCHEMA_CACHE_PURGED",
+			slog.Int("events", n),
+			slog.Int("schema_version", grpcmarshaller.MarshallerSchemaVersion),
+			slog.Uint64("mode_revision", version&0xffffffff),
+		)
+	}
+	return n
+}
+
+// RegisterSchemaCacheInvalidator purges on every deprecation mode change (e.g. a
+// config reload escalating a feature) while the Hub is running.
+func RegisterSchemaCacheInvalidator(lc fx.Lifecycle, inv *SchemaCacheInvalidator) {
+	deprecation.OnModeChange(func() { inv.Invalidate() })
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			inv.active.Store(true)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			inv.active.Store(false)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+)
+
+// errSendTimeout marks a Send still blocked in HTTP/2 flow control past its deadline.
+var errSendTimeout = errors.New("grpc: stream send exceeded deadline")
+
+// boundedSender bounds every stream.Send with a deadline.
+//
+// [WRITE_DISCIPLINE] grpc-go has no per-message write deadline, so Sends run on one
+// worker goroutine per stream while the handler waits with a reusable timer. After a
+// timeout the handler returns, the stream context is cancelled, the blocked Send
+// fails and the worker exits on ctx; the buffered result keeps it from blocking.
+type boundedSender struct {
+	ctx     context.Context
+	stream  impb.Delivery_StreamServer
+	timeout time.Duration
+	timer   *time.Timer
+	reqs    chan *impb.ServerEvent
+	res     chan error
+}
+
+// newBoundedSender starts the worker; a non-positive timeout sends inline, unbounded.
+func newBoundedSender(ctx context.Context, stream impb.Delivery_StreamServer, timeout time.Duration) *boundedSender {
+	s := &boundedSender{ctx: ctx, stream: stream, timeout: timeout}
+	if timeout <= 0 {
+		return s
+	}
+	s.timer = time.NewTimer(timeout)
+	s.timer.Stop()
+	s.reqs = make(chan *impb.ServerEvent)
+	s.res = make(chan error, 1)
+	go s.work(ctx)
+	return s
+}
+
+func (s *boundedSender) work(ctx context.Context) {
+	for {
+		select {
+		case pb := <-s.reqs:
+			s.res <- s.stream.Send(pb)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Send writes pb or gives up with errSendTimeout, or with the context error once the
+// stream has ended. Not safe for concurrent use,
+// and no further Send may follow a timeout.
+func (s *boundedSender) Send(pb *impb.ServerEvent) error {
+	if s.timer == nil {
+		return s.stream.Send(pb)
+	}
+
+	// The worker is idle: every previous request was answered before returning.
+	// It exits once the stream context ends, so hand off only while the stream lives.
+	select {
+	case s.reqs <- pb:
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+	s.timer.Reset(s.timeout)
+	select {
+	case err := <-s.res:
+		s.timer.Stop()
+		return err
+	case <-s.timer.C:
+		return errSendTimeout
+	}
+}
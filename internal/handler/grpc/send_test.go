@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"google.golang.org/grpc"
+)
+
+// blockingStream is a server stream whose Send blocks until its context ends,
+// like a transport stuck in HTTP/2 flow control.
+type blockingStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *blockingStream) Context() context.Context { return s.ctx }
+
+func (s *blockingStream) Send(*impb.ServerEvent) error {
+	<-s.ctx.Done()
+	return s.ctx.Err()
+}
+
+func TestBoundedSenderAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sender := newBoundedSender(ctx, &blockingStream{ctx: ctx}, time.Minute)
+	cancel()
+	// Let the worker observe the cancellation and exit before the handler sends.
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() { done <- sender.Send(&impb.ServerEvent{}) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Send = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Send blocked after the stream context was cancelled")
+	}
+}
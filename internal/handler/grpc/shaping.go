@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/webitel/im-delivery-service/config"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+)
+
+// ListShapingRules reports the delivery shaping rules in effect on this node.
+// The service-scope interceptor has already authenticated the caller.
+func (a *AdminService) ListShapingRules(context.Context, *impb.ListShapingRulesRequest) (*impb.ListShapingRulesResponse, error) {
+	return mapShapingRules(a.shaper.Rules()), nil
+}
+
+func mapShapingRules(rules []config.ShapingRule) *impb.ListShapingRulesResponse {
+	res := &impb.ListShapingRulesResponse{Rules: make([]*impb.ShapingRule, len(rules))}
+	for i, r := range rules {
+		res.Rules[i] = &impb.ShapingRule{
+			DomainId:       r.DomainID,
+			Kind:           r.Kind,
+			DelayMs:        int32(r.DelayMs),
+			JitterMs:       int32(r.JitterMs),
+			ReorderMs:      int32(r.ReorderMs),
+			ReorderPercent: r.ReorderPercent,
+		}
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/webitel/im-delivery-service/config"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestListShapingRules checks the mapping of configured rules and that production
+// reports none, since shaping never applies there.
+func TestListShapingRules(t *testing.T) {
+	rules := []config.ShapingRule{
+		{Kind: "*", DelayMs: 200},
+		{DomainID: 7, Kind: "message_created", DelayMs: 100, JitterMs: 50, ReorderMs: 300, ReorderPercent: 12.5},
+	}
+	tests := []struct {
+		name string
+		env  string
+		want *impb.ListShapingRulesResponse
+	}{
+		{
+			name: "staging",
+			env:  "staging",
+			want: &impb.ListShapingRulesResponse{Rules: []*impb.ShapingRule{
+				{Kind: "*", DelayMs: 200},
+				{DomainId: 7, Kind: "message_created", DelayMs: 100, JitterMs: 50, ReorderMs: 300, ReorderPercent: 12.5},
+			}},
+		},
+		{name: "production", env: config.EnvProduction, want: &impb.ListShapingRulesResponse{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Service.Environment = tt.env
+			cfg.Delivery.Shaping.Rules = rules
+			admin := NewAdminService(nil, nil, nil, service.NewDeliveryShaper(cfg))
+
+			got, err := admin.ListShapingRules(t.Context(), &impb.ListShapingRulesRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetSlowDeliveries returns the slow-delivery exemplars of one closed window on this node.
+// The service-scope interceptor has already authenticated the caller.
+func (a *AdminService) GetSlowDeliveries(_ context.Context, req *impb.GetSlowDeliveriesRequest) (*impb.GetSlowDeliveriesResponse, error) {
+	if req.GetWindow() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "window is required")
+	}
+	window := time.UnixMilli(req.GetWindow())
+
+	exemplars, err := a.slow.GetSlowDeliveries(window, req.GetKind())
+	if err != nil {
+		if errors.Is(err, service.ErrSlowWindowNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &impb.GetSlowDeliveriesResponse{
+		WindowStart: service.SlowWindowStart(window).UnixMilli(),
+		Deliveries:  make([]*impb.SlowDelivery, len(exemplars)),
+	}
+	for i, d := range exemplars {
+		res.Deliveries[i] = mapSlowDelivery(d)
+	}
+	return res, nil
+}
+
+func mapSlowDelivery(d service.SlowDelivery) *impb.SlowDelivery {
+	return &impb.SlowDelivery{
+		Kind:         d.Kind,
+		EventId:      d.EventID,
+		SourceId:     d.SourceID,
+		UserHash:     d.UserHash,
+		DomainHash:   d.DomainHash,
+		Transport:    d.Transport,
+		TotalUs:      d.Total.Microseconds(),
+		EnrichUs:     d.Stages.Enrich.Microseconds(),
+		HubUs:        d.Stages.Hub.Microseconds(),
+		MarshalUs:    d.Stages.Marshal.Microseconds(),
+		WriteUs:      d.Stages.Write.Microseconds(),
+		SlowestStage: d.SlowestStage,
+		BufferPeak:   int32(d.BufferPeak),
+		At:           d.At.UnixMilli(),
+	}
+}
@@ -0,0 +1,134 @@
+// Package httpauth authenticates HTTP transports (LP, SSE, WS) through the same
+// service.Auther the gRPC interceptors use.
+package httpauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// AccessTokenHeader is the header the auth service reads the token from.
+	AccessTokenHeader = "X-Webitel-Access"
+	// AccessTokenCookie carries the token for browser clients that cannot set headers.
+	AccessTokenCookie = "access_token"
+)
+
+var (
+	ErrNoToken         = errors.New("httpauth: access token missing")
+	ErrInvalidIdentity = errors.New("httpauth: contact id is not a user id")
+)
+
+// Token reads the bearer token from, in order: the Authorization header, the
+// X-Webitel-Access header, the access_token cookie. Empty when none is present.
+func Token(r *http.Request) string {
+	if v := r.Header.Get("Authorization"); v != "" {
+		if t, ok := strings.CutPrefix(v, "Bearer "); ok {
+			return strings.TrimSpace(t)
+		}
+		return strings.TrimSpace(v)
+	}
+	if v := r.Header.Get(AccessTokenHeader); v != "" {
+		return v
+	}
+	if c, err := r.Cookie(AccessTokenCookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// Authenticate resolves the caller behind token.
+// [METADATA_BRIDGE] Inspect forwards incoming gRPC metadata, so the request headers are
+// mapped onto it and the token is normalized into the access header. Credentials the
+// auth service does not expect (cookies, WS handshake keys) are not forwarded.
+func Authenticate(r *http.Request, auther service.Auther, token string) (*model.AuthContact, uuid.UUID, error) {
+	if token == "" {
+		return nil, uuid.Nil, ErrNoToken
+	}
+
+	md := metadata.MD{}
+	for k, v := range r.Header {
+		switch k {
+		case "Authorization", "Cookie", "Sec-Websocket-Protocol", "Sec-Websocket-Key":
+			continue
+		}
+		md.Append(k, v...)
+	}
+	md.Set(AccessTokenHeader, token)
+
+	auth, err := auther.Inspect(metadata.NewIncomingContext(r.Context(), md))
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(auth.ContactID)
+	if err != nil {
+		return auth, uuid.Nil, ErrInvalidIdentity
+	}
+	return auth, userID, nil
+}
+
+type contextKey struct{}
+
+// Identity is the authenticated caller of an HTTP request.
+type Identity struct {
+	Contact *model.AuthContact
+	UserID  uuid.UUID
+}
+
+// WithIdentity stores the caller in ctx.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the caller stored by Middleware.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	return id, ok
+}
+
+// Middleware rejects unauthenticated requests with 401 and requests whose contact is
+// not a user with 403; otherwise the Identity is available through FromContext.
+type Middleware func(http.Handler) http.Handler
+
+// NewMiddleware builds the chi-compatible [PRE_AUTH] middleware.
+func NewMiddleware(auther service.Auther) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth, userID, err := Authenticate(r, auther, Token(r))
+			switch {
+			case errors.Is(err, ErrInvalidIdentity):
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			case err != nil:
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "authentication failed", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), Identity{Contact: auth, UserID: userID})))
+		})
+	}
+}
+
+// RequireUser resolves the user a request acts for: the authenticated caller, which
+// must match the {userID} path segment when the route has one. It writes 401/403
+// and reports false otherwise.
+func RequireUser(w http.ResponseWriter, r *http.Request, pathUserID string) (Identity, bool) {
+	id, ok := FromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return Identity{}, false
+	}
+	if pathUserID != "" && !strings.EqualFold(pathUserID, id.UserID.String()) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return Identity{}, false
+	}
+	return id, true
+}
@@ -0,0 +1,132 @@
+package httpauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenAuther resolves the forwarded access header against a fixed token table.
+type tokenAuther map[string]*model.AuthContact
+
+func (a tokenAuther) Inspect(ctx context.Context) (*model.AuthContact, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("authorization")) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "authorization header forwarded")
+	}
+	tokens := md.Get(AccessTokenHeader)
+	if len(tokens) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "token missing")
+	}
+	switch tokens[0] {
+	case "expired":
+		return nil, status.Error(codes.Unauthenticated, "token expired")
+	case "malformed":
+		return nil, status.Error(codes.InvalidArgument, "token malformed")
+	}
+	if c, ok := a[tokens[0]]; ok {
+		return c, nil
+	}
+	return nil, status.Error(codes.Unauthenticated, "token unknown")
+}
+
+// TestMiddleware checks the status each kind of token gets: 401 for missing, expired,
+// malformed or unknown tokens, 403 for a valid token whose contact is not a user.
+func TestMiddleware(t *testing.T) {
+	userID := uuid.New()
+	auther := tokenAuther{
+		"user":    {ContactID: userID.String(), Scope: []string{"chat"}},
+		"service": {ContactID: "backend", Scope: []string{model.ScopeService}},
+		"bot":     {ContactID: "bot:42"},
+	}
+	bearer := func(token string) func(*http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+
+	tests := []struct {
+		name       string
+		setup      func(*http.Request)
+		wantStatus int
+	}{
+		{name: "no token", setup: func(*http.Request) {}, wantStatus: http.StatusUnauthorized},
+		{name: "expired token", setup: bearer("expired"), wantStatus: http.StatusUnauthorized},
+		{name: "malformed token", setup: bearer("malformed"), wantStatus: http.StatusUnauthorized},
+		{name: "unknown token", setup: bearer("forged"), wantStatus: http.StatusUnauthorized},
+		{name: "service scope is not a user", setup: bearer("service"), wantStatus: http.StatusForbidden},
+		{name: "contact without a user id", setup: bearer("bot"), wantStatus: http.StatusForbidden},
+		{name: "bearer header", setup: bearer("user"), wantStatus: http.StatusOK},
+		{name: "access header", setup: func(r *http.Request) { r.Header.Set(AccessTokenHeader, "user") }, wantStatus: http.StatusOK},
+		{name: "cookie", setup: func(r *http.Request) { r.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: "user"}) }, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Identity
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = FromContext(r.Context())
+			})
+			r := httptest.NewRequest(http.MethodGet, "/lp/poll", nil)
+			tt.setup(r)
+			w := httptest.NewRecorder()
+			NewMiddleware(auther)(next).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			switch tt.wantStatus {
+			case http.StatusOK:
+				if got.UserID != userID {
+					t.Fatalf("identity user = %s, want %s", got.UserID, userID)
+				}
+			case http.StatusUnauthorized:
+				if w.Header().Get("WWW-Authenticate") != "Bearer" {
+					t.Fatal("401 without a Bearer challenge")
+				}
+			}
+		})
+	}
+}
+
+// TestRequireUser checks the {userID} path guard of LP and SSE.
+func TestRequireUser(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name       string
+		identity   bool
+		pathUserID string
+		wantStatus int
+	}{
+		{name: "no identity", pathUserID: userID.String(), wantStatus: http.StatusUnauthorized},
+		{name: "no path segment", identity: true, wantStatus: http.StatusOK},
+		{name: "own user", identity: true, pathUserID: userID.String(), wantStatus: http.StatusOK},
+		{name: "own user, upper case", identity: true, pathUserID: strings.ToUpper(userID.String()), wantStatus: http.StatusOK},
+		{name: "another user", identity: true, pathUserID: uuid.NewString(), wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/lp/"+tt.pathUserID, nil)
+			if tt.identity {
+				r = r.WithContext(WithIdentity(r.Context(), Identity{Contact: &model.AuthContact{}, UserID: userID}))
+			}
+			w := httptest.NewRecorder()
+			id, ok := RequireUser(w, r, tt.pathUserID)
+
+			if ok != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("ok = %v, want status %d", ok, tt.wantStatus)
+			}
+			if !ok && w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if ok && id.UserID != userID {
+				t.Fatalf("user = %s, want %s", id.UserID, userID)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package httpauth
+
+import "go.uber.org/fx"
+
+// Module provides the Middleware for whichever HTTP router mounts the LP, SSE and WS handlers.
+var Module = fx.Module("http-auth",
+	fx.Provide(NewMiddleware),
+)
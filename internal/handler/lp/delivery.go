@@ -1,40 +1,148 @@
 package lp
 
 import (
+	"errors"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/google/uuid"
 	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
 	lpmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/lp"
 	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+)
+
+const (
+	// defaultWait is how long a poll waits for the first event.
+	defaultWait = 30 * time.Second
+	// defaultLimit caps the events returned by a single poll.
+	defaultLimit = 16
+	// defaultBatchWindow is how long a poll lingers after the first event for more to arrive.
+	defaultBatchWindow = 50 * time.Millisecond
+	// defaultWriteTimeout bounds writing the poll response.
+	defaultWriteTimeout = 10 * time.Second
+)
+
+// Client description headers, shared with the WS transport; the query parameters
+// "platform", "client_version" and "device_id" are accepted as fallbacks.
+const (
+	PlatformHeader      = "X-Client-Platform"
+	ClientVersionHeader = "X-Client-Version"
+	DeviceIDHeader      = "X-Device-Id"
 )
 
 type LPHandler struct {
-	deliverer service.Deliverer
+	deliverer    service.Deliverer
+	batchWindow  time.Duration
+	writeTimeout time.Duration
+	wait         time.Duration
+	limit        int
+}
+
+// Option defines a functional configuration type for the LPHandler.
+type Option func(*LPHandler)
+
+// WithLPBatchWindow sets the [BATCHING] window opened by the first event of a poll.
+// Zero restores the immediate (non-blocking) drain.
+func WithLPBatchWindow(d time.Duration) Option {
+	return func(h *LPHandler) {
+		h.batchWindow = d
+	}
 }
 
-func NewLPHandler(deliverer service.Deliverer) *LPHandler {
-	return &LPHandler{
-		deliverer: deliverer,
+// WithLPWriteTimeout sets the deadline for writing a poll response.
+func WithLPWriteTimeout(d time.Duration) Option {
+	return func(h *LPHandler) {
+		h.writeTimeout = d
 	}
 }
 
+func NewLPHandler(deliverer service.Deliverer, opts ...Option) *LPHandler {
+	h := &LPHandler{
+		deliverer:    deliverer,
+		batchWindow:  defaultBatchWindow,
+		writeTimeout: defaultWriteTimeout,
+		wait:         defaultWait,
+		limit:        defaultLimit,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
 // Poll handles the long-polling request.
 // It holds the connection until an event arrives or timeout occurs.
 func (h *LPHandler) Poll(w http.ResponseWriter, r *http.Request) {
-	// 1. Extract Identity (UserID should be validated via middleware in production).
-	userIDStr := chi.URLParam(r, "userID")
-	userID, err := uuid.Parse(userIDStr)
+	// 1. Identity comes from httpauth.Middleware; the path only has to agree with it.
+	id, ok := httpauth.RequireUser(w, r, chi.URLParam(r, "userID"))
+	if !ok {
+		return
+	}
+	userID := id.UserID
+
+	params, err := h.parsePollParams(r)
 	if err != nil {
-		http.Error(w, "invalid user id", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, "invalid_poll_params", err.Error())
+		return
+	}
+	w.Header().Set(PollTimeoutHeader, strconv.Itoa(int(params.wait/time.Second)))
+
+	// [RESUME] Each poll passes the cursor of the last event it received, so events
+	// arriving between polls are replayed instead of lost.
+	var resume event.Cursor
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		if resume, err = event.ParseCursor(raw); err != nil {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+	// [LEGACY_RESUME] Older clients only know the ID of the last event they received.
+	lastEventID := r.URL.Query().Get("last_event_id")
+	lastEventTS, _ := strconv.ParseInt(r.URL.Query().Get("last_event_ts"), 10, 64)
+
+	// [RESUME] The Cell outlives the poll, so a fresh cursor replays whatever arrived
+	// between polls. One that fell out of the replay buffer cannot, and the client
+	// must resync history instead of trusting a gapped stream.
+	if !resume.IsZero() && !h.deliverer.CanResume(userID, resume) {
+		http.Error(w, "cursor expired, resync history", http.StatusGone)
 		return
 	}
 
 	// 2. Temporary Subscription.
+	md := connectMetadata(r)
 	// We create a connector that will live only for the duration of this HTTP request.
-	conn, err := h.deliverer.Subscribe(r.Context(), userID)
+	conn, err := h.deliverer.Subscribe(r.Context(), userID, service.SubscribeOptions{
+		Transport:   service.TransportLP,
+		DomainID:    id.Contact.DC,
+		Resume:      resume,
+		LastEventID: lastEventID,
+		LastEventTS: lastEventTS,
+		Platform:    md.Platform,
+		Metadata:    md,
+	})
+	if errors.Is(err, deprecation.ErrRejected) {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
+	var drainErr *service.DrainingError
+	if errors.As(err, &drainErr) {
+		w.Header().Set("Retry-After", drainErr.Hint.RetryAfterHeader())
+		http.Error(w, registry.ReasonServerDraining, http.StatusServiceUnavailable)
+		return
+	}
+	// [DATA_RESIDENCY] This node may not serve the tenant; the body names the allowed regions.
+	var resErr *service.ResidencyError
+	if errors.As(err, &resErr) {
+		http.Error(w, resErr.Error(), http.StatusMisdirectedRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "failed to subscribe", http.StatusInternalServerError)
 		return
@@ -42,9 +150,11 @@ func (h *LPHandler) Poll(w http.ResponseWriter, r *http.Request) {
 
 	// Ensure cleanup: remove from registry and return to pool when request finishes.
 	defer h.deliverer.Unsubscribe(userID, conn.GetID())
-	defer conn.Close()
 
-	var events []event.Eventer
+	events, stopShaping := h.deliverer.Shape(conn, id.Contact.DC)
+	defer stopShaping()
+
+	var batch []event.Eventer
 
 	// 3. Wait for data or timeout.
 	select {
@@ -52,38 +162,113 @@ func (h *LPHandler) Poll(w http.ResponseWriter, r *http.Request) {
 		// Client disconnected.
 		return
 
-	case <-time.After(30 * time.Second):
+	case <-time.After(params.wait):
 		// Standard Long-Polling timeout to prevent hanging connections.
+		w.Header().Set(PollCountHeader, "0")
 		w.WriteHeader(http.StatusNoContent)
 		return
 
-	case ev, ok := <-conn.Recv():
-		if !ok {
-			return
-		}
-		events = append(events, ev)
+	case <-conn.Done():
+		// Session terminated by the server.
+		// [RECONNECT_GUIDANCE] Tell the poller how long to back off before the next request.
+		hint := h.deliverer.DisconnectHint(reconnect.ReasonSessionClosed)
+		w.Header().Set("Retry-After", hint.RetryAfterHeader())
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
 
-		// [OPTIONAL] Drain remaining events from buffer to provide batching.
-		// This minimizes the number of subsequent HTTP requests.
-	drainLoop:
-		for range 15 {
-			select {
-			case nextEv := <-conn.Recv():
-				events = append(events, nextEv)
-			default:
-				break drainLoop
-			}
-		}
+	case ev := <-events:
+		batch = append(batch, ev)
+
+		// [BATCHING] Linger up to batchWindow for more events so chatty streams
+		// are not answered one event per round-trip. A full batch is sent at once.
+		batch = h.collectBatch(r, events, batch, params.limit)
 	}
 
 	// 4. Final transmission.
-	data, err := lpmarshaller.MarshallEvents(events)
+	marshalStart := time.Now()
+	data, err := lpmarshaller.MarshallEvents(batch)
 	if err != nil {
 		http.Error(w, "marshal error", http.StatusInternalServerError)
 		return
 	}
+	writeStart := time.Now()
+
+	// [WRITE_DISCIPLINE] A peer with a closed TCP window must not pin this handler.
+	// The events are already consumed; a breach is counted so wedged peers stay visible.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(h.writeTimeout))
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(PollCountHeader, strconv.Itoa(len(batch)))
 	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(data)
+	if _, err := w.Write(data); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			service.RecordStuckWrite(r.Context(), service.TransportLP, service.StuckWriteDeadline)
+		}
+		return
+	}
+
+	// [STAGE_TIMING] The whole batch shares one marshal and one write.
+	marshalDur, writeDur := writeStart.Sub(marshalStart), time.Since(writeStart)
+	for _, ev := range batch {
+		h.deliverer.RecordDelivery(conn, ev, marshalDur, writeDur)
+	}
+}
+
+// collectBatch tops up the batch until it is full, the window closes, or the client leaves.
+func (h *LPHandler) collectBatch(r *http.Request, events <-chan event.Eventer, batch []event.Eventer, limit int) []event.Eventer {
+	var window <-chan time.Time
+	if h.batchWindow > 0 {
+		timer := time.NewTimer(h.batchWindow)
+		defer timer.Stop()
+		window = timer.C
+	}
+
+	for len(batch) < limit {
+		if window == nil {
+			// [NON_BLOCKING] No window: take only what is already buffered.
+			select {
+			case ev := <-events:
+				batch = append(batch, ev)
+				continue
+			default:
+				return batch
+			}
+		}
+
+		select {
+		case ev := <-events:
+			batch = append(batch, ev)
+		case <-window:
+			return batch
+		case <-r.Context().Done():
+			return batch
+		}
+	}
+	return batch
+}
+
+// connectMetadata describes the client from its headers (or query fallbacks).
+func connectMetadata(r *http.Request) registry.ConnectMetadata {
+	return registry.ConnectMetadata{
+		Platform:  headerOrQuery(r, PlatformHeader, "platform"),
+		Version:   headerOrQuery(r, ClientVersionHeader, "client_version"),
+		DeviceID:  headerOrQuery(r, DeviceIDHeader, "device_id"),
+		RemoteIP:  remoteIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+func headerOrQuery(r *http.Request, header, param string) string {
+	if v := r.Header.Get(header); v != "" {
+		return v
+	}
+	return r.URL.Query().Get(param)
+}
+
+// remoteIP strips the port from the peer address.
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
 }
@@ -0,0 +1,150 @@
+package lp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+)
+
+// fakeDeliverer serves one poll from a prepared connector; everything it does not
+// override panics through the nil embedded interface.
+type fakeDeliverer struct {
+	service.Deliverer
+	conn         registry.Connector
+	subscribeErr error
+	hint         reconnect.Hint
+	residency    *service.ResidencyPolicy // Checked against the subscribed domain, if set
+
+	subscribed  service.SubscribeOptions
+	shapeDomain int64
+}
+
+func (f *fakeDeliverer) Subscribe(_ context.Context, _ uuid.UUID, opts service.SubscribeOptions) (registry.Connector, error) {
+	f.subscribed = opts
+	if f.residency != nil {
+		if err := f.residency.Check(opts.DomainID); err != nil {
+			return nil, err
+		}
+	}
+	return f.conn, f.subscribeErr
+}
+
+func (f *fakeDeliverer) Unsubscribe(uuid.UUID, uuid.UUID) {}
+
+func (f *fakeDeliverer) Shape(conn registry.Connector, domainID int64) (<-chan event.Eventer, func()) {
+	f.shapeDomain = domainID
+	return conn.Recv(), func() {}
+}
+
+func (*fakeDeliverer) RecordDelivery(registry.Connector, event.Eventer, time.Duration, time.Duration) {
+}
+
+func (f *fakeDeliverer) DisconnectHint(reason reconnect.Reason) reconnect.Hint {
+	h := f.hint
+	h.Reason = reason
+	return h
+}
+
+// TestPollRetryAfter checks that server-initiated, retryable endings of a poll carry
+// the reconnect guidance as a Retry-After header.
+func TestPollRetryAfter(t *testing.T) {
+	userID := uuid.New()
+	closed := registry.NewConnector(context.Background(), userID, 1, registry.ConnectMetadata{})
+	closed.Close()
+
+	tests := []struct {
+		name       string
+		deliverer  *fakeDeliverer
+		wantStatus int
+		wantRetry  string
+	}{
+		{
+			name:       "session closed by the server",
+			deliverer:  &fakeDeliverer{conn: closed, hint: reconnect.Hint{RetryAfter: 2500 * time.Millisecond}},
+			wantStatus: http.StatusServiceUnavailable,
+			wantRetry:  "3",
+		},
+		{
+			name: "node draining",
+			deliverer: &fakeDeliverer{subscribeErr: &service.DrainingError{
+				Hint: reconnect.Hint{Reason: reconnect.ReasonShutdown, RetryAfter: 7 * time.Second},
+			}},
+			wantStatus: http.StatusServiceUnavailable,
+			wantRetry:  "7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Get("/lp/{userID}", NewLPHandler(tt.deliverer).Poll)
+
+			req := httptest.NewRequest(http.MethodGet, "/lp/"+userID.String(), nil)
+			req = req.WithContext(httpauth.WithIdentity(req.Context(), httpauth.Identity{Contact: &model.AuthContact{}, UserID: userID}))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.wantRetry)
+			}
+		})
+	}
+}
+
+// TestPollDomain checks that a poll subscribes and shapes under the caller's domain,
+// so a residency-restricted tenant is turned away from a node outside its regions.
+func TestPollDomain(t *testing.T) {
+	const domainID = 7
+	tests := []struct {
+		name       string
+		region     string // This node's region; the domain is restricted to "eu"
+		wantStatus int
+	}{
+		{name: "allowed region", region: "eu", wantStatus: http.StatusOK},
+		{name: "restricted domain rejected", region: "us", wantStatus: http.StatusMisdirectedRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Service.Region = tt.region
+			cfg.Delivery.Residency.Domains = []config.DomainResidency{{DomainID: domainID, Regions: []string{"eu"}}}
+
+			userID := uuid.New()
+			conn := registry.NewConnector(context.Background(), userID, 1, registry.ConnectMetadata{})
+			conn.Send(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil), 0)
+			d := &fakeDeliverer{conn: conn, residency: service.NewResidencyPolicy(cfg)}
+
+			router := chi.NewRouter()
+			router.Get("/lp/{userID}", NewLPHandler(d, WithLPBatchWindow(0)).Poll)
+
+			req := httptest.NewRequest(http.MethodGet, "/lp/"+userID.String(), nil)
+			req = req.WithContext(httpauth.WithIdentity(req.Context(), httpauth.Identity{Contact: &model.AuthContact{DC: domainID}, UserID: userID}))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if d.subscribed.DomainID != domainID {
+				t.Fatalf("subscribed under domain %d, want %d", d.subscribed.DomainID, domainID)
+			}
+			if tt.wantStatus == http.StatusOK && d.shapeDomain != domainID {
+				t.Fatalf("shaped under domain %d, want %d", d.shapeDomain, domainID)
+			}
+		})
+	}
+}
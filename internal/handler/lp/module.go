@@ -0,0 +1,27 @@
+package lp
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/webitel/im-delivery-service/config"
+	httpsrv "github.com/webitel/im-delivery-service/infra/server/http"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"go.uber.org/fx"
+)
+
+// Route is the long-poll endpoint; {userID} must name the authenticated caller.
+const Route = "/lp/{userID}"
+
+var Module = fx.Module("delivery-lp",
+	fx.Provide(func(cfg *config.Config, deliverer service.Deliverer) *LPHandler {
+		return NewLPHandler(deliverer, WithLPConfig(cfg.Delivery.LP))
+	}),
+	fx.Invoke(Register),
+)
+
+// Register mounts Poll behind auth, which Poll relies on for the caller's identity.
+func Register(server *httpsrv.Server, auth httpauth.Middleware, h *LPHandler) {
+	server.Authenticated(auth, func(r chi.Router) {
+		r.Get(Route, h.Poll)
+	})
+}
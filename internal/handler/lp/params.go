@@ -0,0 +1,85 @@
+package lp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/webitel/im-delivery-service/config"
+)
+
+// Per-request bounds for ?wait= and ?limit=; config defaults are clamped to them too.
+const (
+	minPollWait  = time.Second
+	maxPollWait  = 90 * time.Second
+	minPollLimit = 1
+	maxPollLimit = 100
+)
+
+// Response headers that let clients tune their next poll.
+const (
+	PollTimeoutHeader = "X-Poll-Timeout" // Effective wait in whole seconds
+	PollCountHeader   = "X-Poll-Count"   // Events in this response
+)
+
+// WithLPConfig applies the config defaults for wait and limit.
+func WithLPConfig(c config.LPConfig) Option {
+	return func(h *LPHandler) {
+		if c.Wait > 0 {
+			h.wait = min(max(c.Wait, minPollWait), maxPollWait)
+		}
+		if c.Limit > 0 {
+			h.limit = min(max(c.Limit, minPollLimit), maxPollLimit)
+		}
+	}
+}
+
+// pollParams are the effective wait and batch limit of one poll.
+type pollParams struct {
+	wait  time.Duration
+	limit int
+}
+
+// parsePollParams reads ?wait= (a duration like "25s" or whole seconds) and ?limit=.
+// Out-of-range or malformed values are rejected rather than silently clamped.
+func (h *LPHandler) parsePollParams(r *http.Request) (pollParams, error) {
+	p := pollParams{wait: h.wait, limit: h.limit}
+	q := r.URL.Query()
+
+	if raw := q.Get("wait"); raw != "" {
+		d, err := parseWait(raw)
+		if err != nil || d < minPollWait || d > maxPollWait {
+			return p, fmt.Errorf("wait must be between %s and %s", minPollWait, maxPollWait)
+		}
+		p.wait = d
+	}
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < minPollLimit || n > maxPollLimit {
+			return p, fmt.Errorf("limit must be between %d and %d", minPollLimit, maxPollLimit)
+		}
+		p.limit = n
+	}
+	return p, nil
+}
+
+func parseWait(raw string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(raw); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(raw)
+}
+
+// errorBody is the JSON shape of a rejected poll.
+type errorBody struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+func writeJSONError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorBody{Error: code, Message: msg})
+}
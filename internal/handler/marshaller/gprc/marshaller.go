@@ -4,16 +4,47 @@ import (
 	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
 	"github.com/webitel/im-delivery-service/internal/domain/event"
 	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"google.golang.org/protobuf/proto"
 )
 
+// MarshallerSchemaVersion identifies the ServerEvent mapping produced by this package.
+// Bump it with any breaking change to the mapping so cached encodings are rebuilt.
+const MarshallerSchemaVersion = 13
+
+// SchemaVersion is the version cached encodings are tagged with: the mapping version
+// in the high bits and the deprecation mode revision in the low ones, since a rejected
+// feature changes what the mapping emits. It only grows, so older entries compare below it.
+func SchemaVersion() uint64 {
+	return MarshallerSchemaVersion<<32 | uint64(uint32(deprecation.Default.Revision()))
+}
+
 // MarshallDeliveryEvent transforms domain Eventer to Protobuf ServerEvent.
 // It acts as a gateway and uses type-specific marshallers.
 func MarshallDeliveryEvent(ev event.Eventer) *impb.ServerEvent {
-	// 1. [PERFORMANCE] Check cache first.
-	if cached := ev.GetCached(); cached != nil {
-		if pb, ok := cached.(*impb.ServerEvent); ok {
-			return pb
-		}
+	pb := marshalShared(ev)
+	shaped, isShaped := ev.(event.Shaped)
+	if !event.IsReplayed(ev) && !isShaped {
+		return pb
+	}
+
+	// [REPLAY] The cached message is shared with live sessions; tag a copy.
+	res := proto.CloneOf(pb)
+	res.Replayed = event.IsReplayed(ev)
+	if isShaped {
+		// [SHAPING] The delay is drawn per session, so it never reaches the shared encoding.
+		res.Debug = &impb.EventDebug{ShapingDelayMs: shaped.GetShapingDelay().Milliseconds()}
+	}
+	return res
+}
+
+// marshalShared returns the ServerEvent every session of the recipient shares.
+func marshalShared(ev event.Eventer) *impb.ServerEvent {
+	// 1. [PERFORMANCE] Check cache first; entries built by another schema version are ignored.
+	enc := ev.Encoded()
+	version := SchemaVersion()
+	if pb, ok := enc.Proto(version); ok {
+		return pb
 	}
 
 	// 2. Base event mapping.
@@ -26,14 +57,45 @@ func MarshallDeliveryEvent(ev event.Eventer) *impb.ServerEvent {
 	// 3. [STRATEGY] Route to specific logic based on payload type.
 	switch p := ev.GetPayload().(type) {
 	case *model.Message:
-		res.Payload = marshalMessagePayload(p)
+		// [E2EE] Encrypted bodies get their own case, scoped to the addressed user.
+		// [EDITS] MessageUpdated shares the payload type; the kind picks the oneof case.
+		switch {
+		case ev.IsEncrypted():
+			res.Payload = marshalEncryptedPayload(p, ev.GetUserID())
+		case ev.GetKind() == event.MessageUpdated:
+			res.Payload = marshalMessageUpdatedPayload(p)
+		default:
+			res.Payload = marshalMessagePayload(p)
+		}
 	case *model.ConnectedPayload:
 		res.Payload = marshalConnectedPayload(p)
 	case *model.DisconnectedPayload:
 		res.Payload = marshalDisconnectedPayload(p)
+	case *model.ReplayGapPayload:
+		res.Payload = marshalReplayGapPayload(p)
+	case *model.DomainPausePayload:
+		// Pause and resume share the payload type; the kind picks the oneof case.
+		if ev.GetKind() == event.DomainResumed {
+			res.Payload = marshalDomainResumedPayload(p)
+		} else {
+			res.Payload = marshalDomainPausedPayload(p)
+		}
+	case *model.DeliveryDegradedPayload:
+		res.Payload = marshalDeliveryDegradedPayload(p)
+	case *model.MessageDeleted:
+		res.Payload = marshalMessageDeletedPayload(p)
+	case *model.TypingPayload:
+		res.Payload = marshalTypingPayload(p)
+	case *model.MessageRead:
+		res.Payload = marshalMessageReadPayload(p)
+	case *model.Reaction:
+		res.Payload = marshalReactionPayload(p)
+	case *model.PresenceStatusPayload:
+		res.Payload = marshalPresenceStatusPayload(p)
+	case *model.PingPayload:
+		res.Payload = marshalPingPayload(p)
 	}
 
-	// 4. [CACHE] Save the result back.
-	ev.SetCached(res)
-	return res
+	// 4. [CACHE] Save the result back, tagged with the schema that built it.
+	return enc.StoreProto(version, res)
 }
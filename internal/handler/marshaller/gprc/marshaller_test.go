@@ -0,0 +1,330 @@
+package grpcmarshaller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMarshallDeliveryEventPayload(t *testing.T) {
+	userID := uuid.New()
+	msgID, threadID := uuid.New(), uuid.New()
+	from := model.Peer{ID: uuid.New(), Type: model.PeerUser, Sub: "user-1", Issuer: "iss", Name: "Ann"}
+	to := model.Peer{ID: uuid.New(), Type: model.PeerGroup, Sub: "group-1", Name: "Team"}
+	msg := &model.Message{ID: msgID, ThreadID: threadID, From: from, To: to, Text: "hi", CreatedAt: 100}
+	edited := *msg
+	edited.Text, edited.EditedAt = "hi!", 200
+	threadMsg := func(m *model.Message) *impb.ThreadMessage {
+		return &impb.ThreadMessage{
+			Id: m.ID.String(), ThreadId: m.ThreadID.String(), Text: m.Text,
+			CreatedAt: m.CreatedAt, EditedAt: m.EditedAt, Type: impb.MessageType_TEXT,
+			From: &impb.Peer{Kind: &impb.Peer_UserId{UserId: "user-1"}, Identity: &impb.Identity{Issuer: "iss", Name: "Ann"}},
+			To:   &impb.Peer{Kind: &impb.Peer_ChatId{ChatId: "group-1"}, Identity: &impb.Identity{Name: "Team"}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		ev   event.Eventer
+		want *impb.ServerEvent // Only Payload is compared
+	}{
+		{
+			name: "connected with deprecations",
+			ev: event.NewSystemEvent(userID, event.Connected, event.PriorityNormal, &model.ConnectedPayload{
+				Ok: true, ConnectionID: "conn-1", ServerVersion: "v1", Deprecations: []string{"raw_last_event_id"},
+			}),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_ConnectedEvent{
+				ConnectedEvent: &impb.ConnectedEvent{
+					Ok: true, ConnectionId: "conn-1", ServerVersion: "v1", Deprecations: []string{"raw_last_event_id"},
+				},
+			}},
+		},
+		{
+			name: "ping",
+			ev:   event.NewSystemEvent(userID, event.Ping, event.PriorityLow, &model.PingPayload{ServerTime: 1700000000123}),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_PingEvent{
+				PingEvent: &impb.PingEvent{Echo: "1700000000123"},
+			}},
+		},
+		{
+			name: "new message",
+			ev:   event.NewMessageV1Event(msg, userID, from, to),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_MessageEvent{
+				MessageEvent: &impb.NewMessageEvent{Message: threadMsg(msg)},
+			}},
+		},
+		{
+			name: "message updated",
+			ev:   event.NewMessageUpdatedEvent(&edited, userID, from, to),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_MessageUpdatedEvent{
+				MessageUpdatedEvent: &impb.MessageUpdatedEvent{Message: threadMsg(&edited)},
+			}},
+		},
+		{
+			name: "message deleted",
+			ev:   event.NewMessageDeletedEvent(&model.MessageDeleted{ID: msgID, ThreadID: threadID}, userID),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_MessageDeletedEvent{
+				MessageDeletedEvent: &impb.MessageDeletedEvent{Id: msgID.String(), ThreadId: threadID.String()},
+			}},
+		},
+		{
+			name: "typing from a bare peer",
+			ev: event.NewTypingEvent(&model.TypingPayload{
+				ThreadID: threadID, From: model.Peer{ID: from.ID, Type: model.PeerUser}, ExpiresAt: 300,
+			}, userID, 250),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_TypingEvent{
+				TypingEvent: &impb.TypingEvent{
+					ThreadId:  threadID.String(),
+					From:      &impb.Peer{Kind: &impb.Peer_UserId{UserId: from.ID.String()}},
+					ExpiresAt: 300,
+				},
+			}},
+		},
+		{
+			name: "typing from an enriched peer",
+			ev:   event.NewTypingEvent(&model.TypingPayload{ThreadID: threadID, From: from, ExpiresAt: 300}, userID, 250),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_TypingEvent{
+				TypingEvent: &impb.TypingEvent{
+					ThreadId:  threadID.String(),
+					From:      &impb.Peer{Kind: &impb.Peer_UserId{UserId: "user-1"}, Identity: &impb.Identity{Issuer: "iss", Name: "Ann"}},
+					ExpiresAt: 300,
+				},
+			}},
+		},
+		{
+			name: "message read",
+			ev: event.NewReadReceiptEvent(&model.MessageRead{
+				ThreadID: threadID, Reader: model.Peer{ID: from.ID, Type: model.PeerUser}, LastReadMessageID: msgID,
+			}, userID),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_MessageReadEvent{
+				MessageReadEvent: &impb.MessageReadEvent{
+					ThreadId:          threadID.String(),
+					Reader:            &impb.Peer{Kind: &impb.Peer_UserId{UserId: from.ID.String()}},
+					LastReadMessageId: msgID.String(),
+				},
+			}},
+		},
+		{
+			name: "reaction removed",
+			ev: event.NewReactionEvent(&model.Reaction{
+				MessageID: msgID, ThreadID: threadID, Emoji: "👍", Actor: from,
+			}, userID),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_ReactionEvent{
+				ReactionEvent: &impb.ReactionEvent{
+					MessageId: msgID.String(),
+					ThreadId:  threadID.String(),
+					Emoji:     "👍",
+					Actor:     &impb.Peer{Kind: &impb.Peer_UserId{UserId: "user-1"}, Identity: &impb.Identity{Issuer: "iss", Name: "Ann"}},
+				},
+			}},
+		},
+		{
+			name: "presence online",
+			ev:   event.NewPresenceStatusEvent(&model.PresenceStatusPayload{ContactID: from.ID, Status: model.StatusOnline}, userID),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_PresenceStatusEvent{
+				PresenceStatusEvent: &impb.PresenceStatusEvent{ContactId: from.ID.String(), Status: impb.PresenceStatus_PRESENCE_ONLINE},
+			}},
+		},
+		{
+			name: "presence dnd",
+			ev:   event.NewPresenceStatusEvent(&model.PresenceStatusPayload{ContactID: from.ID, Status: model.StatusDND}, userID),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_PresenceStatusEvent{
+				PresenceStatusEvent: &impb.PresenceStatusEvent{ContactId: from.ID.String(), Status: impb.PresenceStatus_PRESENCE_DND},
+			}},
+		},
+		{
+			name: "presence unknown",
+			ev:   event.NewPresenceStatusEvent(&model.PresenceStatusPayload{ContactID: from.ID, Status: model.PresenceStatus("busy")}, userID),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_PresenceStatusEvent{
+				PresenceStatusEvent: &impb.PresenceStatusEvent{ContactId: from.ID.String(), Status: impb.PresenceStatus_PRESENCE_STATUS_UNSPECIFIED},
+			}},
+		},
+		{
+			name: "replay gap",
+			ev:   event.NewSystemEvent(userID, event.ReplayGap, event.PriorityHigh, &model.ReplayGapPayload{Requested: "7", Oldest: "12"}),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_ReplayGapEvent{
+				ReplayGapEvent: &impb.ReplayGapEvent{Requested: "7", Oldest: "12"},
+			}},
+		},
+		{
+			name: "domain paused",
+			ev:   event.NewSystemEvent(userID, event.DomainPaused, event.PriorityHigh, &model.DomainPausePayload{DomainID: 1, UntilMs: 1700000000000}),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_DomainPausedEvent{
+				DomainPausedEvent: &impb.DomainPausedEvent{Until: 1700000000000},
+			}},
+		},
+		{
+			name: "domain resumed",
+			ev:   event.NewSystemEvent(userID, event.DomainResumed, event.PriorityHigh, &model.DomainPausePayload{DomainID: 1}),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_DomainResumedEvent{
+				DomainResumedEvent: &impb.DomainResumedEvent{},
+			}},
+		},
+		{
+			name: "delivery degraded",
+			ev:   event.NewSystemEvent(userID, event.DeliveryDegraded, event.PriorityHigh, &model.DeliveryDegradedPayload{Dropped: 40, Threshold: 32}),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_DeliveryDegradedEvent{
+				DeliveryDegradedEvent: &impb.DeliveryDegradedEvent{Dropped: 40, Threshold: 32},
+			}},
+		},
+		{
+			name: "encrypted message keeps only the recipient's envelope",
+			ev: event.NewMessageV1Event(&model.Message{
+				ID: msgID, ThreadID: threadID, From: from, To: to, CreatedAt: 100,
+				Encrypted: &model.EncryptedContent{
+					Ciphertext: []byte{0xde, 0xad}, Algorithm: "x25519-aes256gcm", KeyID: "k1",
+					Envelopes: []model.KeyEnvelope{
+						{RecipientID: uuid.New(), WrappedKey: "other"},
+						{RecipientID: userID, WrappedKey: "mine"},
+					},
+				},
+			}, userID, from, to),
+			want: &impb.ServerEvent{Payload: &impb.ServerEvent_EncryptedEvent{
+				EncryptedEvent: &impb.EncryptedEvent{
+					MessageId:  msgID.String(),
+					ThreadId:   threadID.String(),
+					From:       threadMsg(msg).From,
+					CreatedAt:  100,
+					Ciphertext: []byte{0xde, 0xad},
+					KeyId:      "k1",
+					Algorithm:  "x25519-aes256gcm",
+					WrappedKey: "mine",
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MarshallDeliveryEvent(tt.ev)
+			if got.GetId() != tt.ev.GetID() {
+				t.Fatalf("id: got %q, want %q", got.GetId(), tt.ev.GetID())
+			}
+			if !proto.Equal(&impb.ServerEvent{Payload: got.Payload}, tt.want) {
+				t.Fatalf("payload:\n got %v\nwant %v", got.Payload, tt.want.Payload)
+			}
+		})
+	}
+}
+
+func TestCachedEncodingFollowsDeprecationModes(t *testing.T) {
+	name := singleAttachmentFields.Name()
+	t.Cleanup(func() { deprecation.Default.SetMode(name, deprecation.ModeWarn) })
+
+	from := model.Peer{ID: uuid.New(), Type: model.PeerUser}
+	msg := &model.Message{
+		ID: uuid.New(), ThreadID: uuid.New(), From: from, To: from, Text: "see attached",
+		Images: []*model.Image{{ID: "1", FileName: "a.png"}},
+	}
+	ev := event.NewMessageV1Event(msg, uuid.New(), from, from)
+
+	tests := []struct {
+		name     string
+		mode     deprecation.Mode
+		wantType impb.MessageType
+	}{
+		{name: "warn keeps the attachment", mode: deprecation.ModeWarn, wantType: impb.MessageType_IMAGE},
+		{name: "reject rebuilds the cached encoding", mode: deprecation.ModeReject, wantType: impb.MessageType_TEXT},
+		{name: "relax rebuilds it again", mode: deprecation.ModeWarn, wantType: impb.MessageType_IMAGE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := SchemaVersion()
+			deprecation.Default.SetMode(name, tt.mode)
+			if after := SchemaVersion(); after < before {
+				t.Fatalf("schema version went back from %d to %d", before, after)
+			}
+			if got := SchemaVersion() >> 32; got != MarshallerSchemaVersion {
+				t.Fatalf("mapping version: got %d, want %d", got, MarshallerSchemaVersion)
+			}
+
+			got := MarshallDeliveryEvent(ev).GetMessageEvent().GetMessage().GetType()
+			if got != tt.wantType {
+				t.Fatalf("type: got %v, want %v", got, tt.wantType)
+			}
+			if _, ok := ev.Encoded().Proto(SchemaVersion()); !ok {
+				t.Fatal("encoding not cached under the current schema version")
+			}
+		})
+	}
+}
+
+func TestMarshallReplayedEvent(t *testing.T) {
+	from := model.Peer{ID: uuid.New(), Type: model.PeerUser}
+	msg := &model.Message{ID: uuid.New(), ThreadID: uuid.New(), From: from, To: from, Text: "hi"}
+
+	tests := []struct {
+		name     string
+		replayed bool
+	}{
+		{name: "live", replayed: false},
+		{name: "replayed", replayed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ev event.Eventer = event.NewMessageV1Event(msg, uuid.New(), from, from)
+			if tt.replayed {
+				ev = event.NewReplayedEvent(ev)
+			}
+			if got := MarshallDeliveryEvent(ev).GetReplayed(); got != tt.replayed {
+				t.Fatalf("replayed: got %v, want %v", got, tt.replayed)
+			}
+		})
+	}
+}
+
+// TestReplayedFlagLeavesSharedEncoding checks that a replay does not tag the encoding
+// live sessions of the same recipient share.
+func TestReplayedFlagLeavesSharedEncoding(t *testing.T) {
+	from := model.Peer{ID: uuid.New(), Type: model.PeerUser}
+	ev := event.NewMessageV1Event(&model.Message{ID: uuid.New(), ThreadID: uuid.New(), From: from, To: from}, uuid.New(), from, from)
+
+	live := MarshallDeliveryEvent(ev)
+	replayed := MarshallDeliveryEvent(event.NewReplayedEvent(ev))
+	if !replayed.GetReplayed() {
+		t.Fatal("replayed event is not tagged")
+	}
+	if live.GetReplayed() || MarshallDeliveryEvent(ev).GetReplayed() {
+		t.Fatal("the replay tagged the shared live encoding")
+	}
+	if replayed.GetId() != live.GetId() || !proto.Equal(replayed.GetMessageEvent(), live.GetMessageEvent()) {
+		t.Fatalf("replayed copy differs from the live event:\n got %v\nwant %v", replayed, live)
+	}
+}
+
+// TestMarshallShapedEvent checks that a shaped event carries its delay in a copy, leaving
+// the encoding other sessions of the recipient share untagged.
+func TestMarshallShapedEvent(t *testing.T) {
+	from := model.Peer{ID: uuid.New(), Type: model.PeerUser}
+	ev := event.NewMessageV1Event(&model.Message{ID: uuid.New(), ThreadID: uuid.New(), From: from, To: from}, uuid.New(), from, from)
+
+	tests := []struct {
+		name         string
+		ev           event.Eventer
+		wantDelayMs  int64
+		wantReplayed bool
+	}{
+		{name: "live", ev: ev},
+		{name: "shaped", ev: event.NewShapedEvent(ev, 1500*time.Millisecond), wantDelayMs: 1500},
+		{name: "shaped replay", ev: event.NewShapedEvent(event.NewReplayedEvent(ev), 250*time.Millisecond), wantDelayMs: 250, wantReplayed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MarshallDeliveryEvent(tt.ev)
+			if got.GetDebug().GetShapingDelayMs() != tt.wantDelayMs || got.GetReplayed() != tt.wantReplayed {
+				t.Fatalf("debug %v, replayed %v; want delay %dms, replayed %v", got.GetDebug(), got.GetReplayed(), tt.wantDelayMs, tt.wantReplayed)
+			}
+			if (tt.wantDelayMs == 0) != (got.GetDebug() == nil) {
+				t.Fatalf("debug = %v", got.GetDebug())
+			}
+		})
+	}
+	if live := MarshallDeliveryEvent(ev); live.GetDebug() != nil {
+		t.Fatal("shaping tagged the shared live encoding")
+	}
+}
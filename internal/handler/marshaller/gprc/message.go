@@ -1,10 +1,17 @@
 package grpcmarshaller
 
 import (
+	"context"
+
+	"github.com/google/uuid"
 	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
 	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
 )
 
+// singleAttachmentFields guards the Image/Document oneof, which carries only the first attachment.
+var singleAttachmentFields = deprecation.Register("single_attachment_fields", "ThreadMessage image/document oneof (first attachment only)")
+
 // marshalMessagePayload maps domain Message to Protobuf payload wrapper.
 func marshalMessagePayload(m *model.Message) *impb.ServerEvent_MessageEvent {
 	if m == nil {
@@ -18,6 +25,124 @@ func marshalMessagePayload(m *model.Message) *impb.ServerEvent_MessageEvent {
 	}
 }
 
+// marshalEncryptedPayload maps an E2EE message to the EncryptedEvent case.
+// [PRIVACY] Only the envelope addressed to recipientID is included.
+func marshalEncryptedPayload(m *model.Message, recipientID uuid.UUID) *impb.ServerEvent_EncryptedEvent {
+	if !m.IsEncrypted() {
+		return nil
+	}
+	enc := m.Encrypted.ForRecipient(recipientID)
+	res := &impb.EncryptedEvent{
+		MessageId:  m.ID.String(),
+		ThreadId:   m.ThreadID.String(),
+		From:       marshalPeer(m.From),
+		CreatedAt:  m.CreatedAt,
+		Ciphertext: enc.Ciphertext,
+		KeyId:      enc.KeyID,
+		Algorithm:  enc.Algorithm,
+	}
+	if len(enc.Envelopes) > 0 {
+		res.WrappedKey = enc.Envelopes[0].WrappedKey
+	}
+	return &impb.ServerEvent_EncryptedEvent{EncryptedEvent: res}
+}
+
+// marshalMessageUpdatedPayload maps an edited Message to its dedicated oneof case.
+func marshalMessageUpdatedPayload(m *model.Message) *impb.ServerEvent_MessageUpdatedEvent {
+	if m == nil {
+		return nil
+	}
+
+	return &impb.ServerEvent_MessageUpdatedEvent{
+		MessageUpdatedEvent: &impb.MessageUpdatedEvent{
+			Message: mapThreadMessage(m),
+		},
+	}
+}
+
+// marshalMessageDeletedPayload maps a tombstone to its dedicated oneof case.
+func marshalMessageDeletedPayload(m *model.MessageDeleted) *impb.ServerEvent_MessageDeletedEvent {
+	if m == nil {
+		return nil
+	}
+	return &impb.ServerEvent_MessageDeletedEvent{
+		MessageDeletedEvent: &impb.MessageDeletedEvent{Id: m.ID.String(), ThreadId: m.ThreadID.String()},
+	}
+}
+
+// marshalTypingPayload maps a typing indicator to its dedicated oneof case.
+func marshalTypingPayload(t *model.TypingPayload) *impb.ServerEvent_TypingEvent {
+	if t == nil {
+		return nil
+	}
+	return &impb.ServerEvent_TypingEvent{
+		TypingEvent: &impb.TypingEvent{
+			ThreadId:  t.ThreadID.String(),
+			From:      marshalSignalPeer(t.From),
+			ExpiresAt: t.ExpiresAt,
+		},
+	}
+}
+
+// marshalMessageReadPayload maps a read receipt to its dedicated oneof case.
+func marshalMessageReadPayload(r *model.MessageRead) *impb.ServerEvent_MessageReadEvent {
+	if r == nil {
+		return nil
+	}
+	return &impb.ServerEvent_MessageReadEvent{
+		MessageReadEvent: &impb.MessageReadEvent{
+			ThreadId:          r.ThreadID.String(),
+			Reader:            marshalSignalPeer(r.Reader),
+			LastReadMessageId: r.LastReadMessageID.String(),
+		},
+	}
+}
+
+// marshalReactionPayload maps a reaction change to its dedicated oneof case.
+func marshalReactionPayload(r *model.Reaction) *impb.ServerEvent_ReactionEvent {
+	if r == nil {
+		return nil
+	}
+	return &impb.ServerEvent_ReactionEvent{
+		ReactionEvent: &impb.ReactionEvent{
+			MessageId: r.MessageID.String(),
+			ThreadId:  r.ThreadID.String(),
+			Emoji:     r.Emoji,
+			Added:     r.Added,
+			Actor:     marshalSignalPeer(r.Actor),
+		},
+	}
+}
+
+// marshalPresenceStatusPayload maps a watched contact's status to its dedicated oneof case.
+func marshalPresenceStatusPayload(p *model.PresenceStatusPayload) *impb.ServerEvent_PresenceStatusEvent {
+	if p == nil {
+		return nil
+	}
+	return &impb.ServerEvent_PresenceStatusEvent{
+		PresenceStatusEvent: &impb.PresenceStatusEvent{
+			ContactId: p.ContactID.String(),
+			Status:    mapPresenceStatus(p.Status),
+		},
+	}
+}
+
+// mapPresenceStatus converts domain availability to the Protobuf enum.
+func mapPresenceStatus(s model.PresenceStatus) impb.PresenceStatus {
+	switch s {
+	case model.StatusOnline:
+		return impb.PresenceStatus_PRESENCE_ONLINE
+	case model.StatusAway:
+		return impb.PresenceStatus_PRESENCE_AWAY
+	case model.StatusDND:
+		return impb.PresenceStatus_PRESENCE_DND
+	case model.StatusOffline:
+		return impb.PresenceStatus_PRESENCE_OFFLINE
+	default:
+		return impb.PresenceStatus_PRESENCE_STATUS_UNSPECIFIED
+	}
+}
+
 // mapThreadMessage performs detailed mapping of the message body and metadata.
 func mapThreadMessage(m *model.Message) *impb.ThreadMessage {
 	msg := &impb.ThreadMessage{
@@ -28,6 +153,16 @@ func mapThreadMessage(m *model.Message) *impb.ThreadMessage {
 		EditedAt:  m.EditedAt,
 		From:      marshalPeer(m.From),
 		To:        marshalPeer(m.To),
+		Metadata:  marshalMetadata(m.Metadata),
+		IsForward: m.IsForward,
+	}
+
+	// [DEPRECATION] Once rejected, attachments are withheld rather than silently truncated.
+	if len(m.Images) > 0 || len(m.Documents) > 0 {
+		if err := singleAttachmentFields.Use(context.Background(), deprecation.Scope{DomainID: m.DomainID}); err != nil {
+			msg.Type = impb.MessageType_TEXT
+			return msg
+		}
 	}
 
 	// [CONTENT_SELECTION] Map the primary attachment based on domain availability.
@@ -72,6 +207,8 @@ func marshalPeer(p model.Peer) *impb.Peer {
 		res.Kind = &impb.Peer_ChatId{ChatId: p.Sub}
 	case model.PeerChannel:
 		res.Kind = &impb.Peer_ChannelId{ChannelId: p.Sub}
+	case model.PeerBot:
+		res.Kind = &impb.Peer_BotId{BotId: p.Sub}
 	}
 
 	if p.IsEnriched() {
@@ -83,3 +220,16 @@ func marshalPeer(p model.Peer) *impb.Peer {
 
 	return res
 }
+
+// marshalSignalPeer maps the peer of a lightweight signal (typing, read, reaction).
+// Such peers are often not enriched, so the kind falls back to the internal peer ID
+// instead of arriving empty.
+func marshalSignalPeer(p model.Peer) *impb.Peer {
+	if p.IsEnriched() {
+		return marshalPeer(p)
+	}
+	p.Sub = p.ID.String()
+	res := marshalPeer(p)
+	res.Identity = nil
+	return res
+}
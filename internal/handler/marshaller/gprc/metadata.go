@@ -0,0 +1,42 @@
+package grpcmarshaller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// marshalMetadata converts free-form message metadata to a Struct.
+// [LOSSLESS_WHERE_POSSIBLE] Bus payloads decode to JSON types, which map directly; values
+// set in code (typed slices, time.Time) go through JSON, and anything JSON cannot
+// represent is stringified rather than dropping the whole map.
+func marshalMetadata(md map[string]any) *structpb.Struct {
+	if len(md) == 0 {
+		return nil
+	}
+	if s, err := structpb.NewStruct(md); err == nil {
+		return s
+	}
+
+	res := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(md))}
+	for k, v := range md {
+		res.Fields[k] = metadataValue(v)
+	}
+	return res
+}
+
+func metadataValue(v any) *structpb.Value {
+	if val, err := structpb.NewValue(v); err == nil {
+		return val
+	}
+	if b, err := json.Marshal(v); err == nil {
+		var generic any
+		if err := json.Unmarshal(b, &generic); err == nil {
+			if val, err := structpb.NewValue(generic); err == nil {
+				return val
+			}
+		}
+	}
+	return structpb.NewStringValue(fmt.Sprint(v))
+}
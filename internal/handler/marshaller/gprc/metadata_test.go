@@ -0,0 +1,130 @@
+package grpcmarshaller
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	wsmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/ws"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestMarshalMetadata(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ch := make(chan int)
+	tests := []struct {
+		name string
+		md   map[string]any
+		want *structpb.Struct
+	}{
+		{name: "absent", md: nil, want: nil},
+		{name: "empty", md: map[string]any{}, want: nil},
+		{
+			name: "JSON-native values",
+			md:   map[string]any{"s": "x", "n": 1.5, "b": true, "null": nil, "list": []any{"a", 2.0}, "obj": map[string]any{"k": "v"}},
+			want: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"s":    structpb.NewStringValue("x"),
+				"n":    structpb.NewNumberValue(1.5),
+				"b":    structpb.NewBoolValue(true),
+				"null": structpb.NewNullValue(),
+				"list": structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{structpb.NewStringValue("a"), structpb.NewNumberValue(2)}}),
+				"obj":  structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{"k": structpb.NewStringValue("v")}}),
+			}},
+		},
+		{
+			name: "typed values go through JSON",
+			md:   map[string]any{"tags": []string{"a", "b"}, "at": at, "n": 7},
+			want: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"tags": structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{structpb.NewStringValue("a"), structpb.NewStringValue("b")}}),
+				"at":   structpb.NewStringValue("2026-01-02T03:04:05Z"),
+				"n":    structpb.NewNumberValue(7),
+			}},
+		},
+		{
+			name: "non-JSON values are stringified, the rest kept",
+			md:   map[string]any{"ok": "x", "ch": ch},
+			want: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"ok": structpb.NewStringValue("x"),
+				"ch": structpb.NewStringValue(fmt.Sprint(ch)),
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := marshalMetadata(tt.md); !proto.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMessageTransportParity checks that gRPC and WS clients see the same message fields
+// for the same V2 event.
+func TestMessageTransportParity(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string // As decoded from the bus
+		forward  bool
+		editedAt int64
+	}{
+		{name: "plain", metadata: `{}`},
+		{name: "forwarded with metadata", metadata: `{"source":"crm","score":0.5,"tags":["a","b"],"ctx":{"n":1,"ok":true,"none":null}}`, forward: true},
+		{name: "edited", metadata: `{"k":"v"}`, editedAt: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var md map[string]any
+			if err := json.Unmarshal([]byte(tt.metadata), &md); err != nil {
+				t.Fatal(err)
+			}
+			msg := &model.Message{ID: uuid.New(), ThreadID: uuid.New(), Text: "hi", CreatedAt: 100, EditedAt: tt.editedAt, Metadata: md, IsForward: tt.forward}
+			ev := event.NewMessageV2Event(msg, uuid.New(), model.Peer{ID: uuid.New()}, model.Peer{ID: uuid.New()})
+
+			frame, err := wsmarshaller.MarshallVersioned(ev, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ws struct {
+				Payload struct {
+					Metadata  map[string]any `json:"metadata"`
+					IsForward bool           `json:"is_forward"`
+					UpdatedAt int64          `json:"updated_at"`
+				} `json:"payload"`
+			}
+			if err := json.Unmarshal(frame, &ws); err != nil {
+				t.Fatal(err)
+			}
+
+			pb := MarshallDeliveryEvent(ev).GetMessageEvent().GetMessage()
+			var grpcMD map[string]any
+			if pb.GetMetadata() != nil {
+				b, err := protojson.Marshal(pb.GetMetadata())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := json.Unmarshal(b, &grpcMD); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if len(ws.Payload.Metadata) != 0 || len(grpcMD) != 0 {
+				if !reflect.DeepEqual(grpcMD, ws.Payload.Metadata) {
+					t.Fatalf("metadata: grpc %v, ws %v", grpcMD, ws.Payload.Metadata)
+				}
+			}
+			if pb.GetIsForward() != ws.Payload.IsForward || pb.GetIsForward() != tt.forward {
+				t.Fatalf("is_forward: grpc %v, ws %v, want %v", pb.GetIsForward(), ws.Payload.IsForward, tt.forward)
+			}
+			if pb.GetEditedAt() != ws.Payload.UpdatedAt || pb.GetEditedAt() != tt.editedAt {
+				t.Fatalf("edited_at: grpc %d, ws %d, want %d", pb.GetEditedAt(), ws.Payload.UpdatedAt, tt.editedAt)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package grpcmarshaller
 
 import (
+	"strconv"
+
 	impb "github.com/webitel/im-delivery-service/gen/go/delivery/v1"
 	"github.com/webitel/im-delivery-service/internal/domain/model"
 )
@@ -15,10 +17,49 @@ func marshalConnectedPayload(p *model.ConnectedPayload) *impb.ServerEvent_Connec
 			Ok:            p.Ok,
 			ConnectionId:  p.ConnectionID,
 			ServerVersion: p.ServerVersion,
+			Deprecations:  p.Deprecations,
 		},
 	}
 }
 
+// marshalReplayGapPayload maps a resume gap to its dedicated oneof case.
+func marshalReplayGapPayload(p *model.ReplayGapPayload) *impb.ServerEvent_ReplayGapEvent {
+	if p == nil {
+		return nil
+	}
+	return &impb.ServerEvent_ReplayGapEvent{
+		ReplayGapEvent: &impb.ReplayGapEvent{Requested: p.Requested, Oldest: p.Oldest},
+	}
+}
+
+// marshalDomainPausedPayload maps the start of a maintenance window to its oneof case.
+func marshalDomainPausedPayload(p *model.DomainPausePayload) *impb.ServerEvent_DomainPausedEvent {
+	if p == nil {
+		return nil
+	}
+	return &impb.ServerEvent_DomainPausedEvent{
+		DomainPausedEvent: &impb.DomainPausedEvent{Until: p.UntilMs},
+	}
+}
+
+// marshalDomainResumedPayload maps the end of a maintenance window to its oneof case.
+func marshalDomainResumedPayload(p *model.DomainPausePayload) *impb.ServerEvent_DomainResumedEvent {
+	if p == nil {
+		return nil
+	}
+	return &impb.ServerEvent_DomainResumedEvent{DomainResumedEvent: &impb.DomainResumedEvent{}}
+}
+
+// marshalDeliveryDegradedPayload maps a backpressure warning to its dedicated oneof case.
+func marshalDeliveryDegradedPayload(p *model.DeliveryDegradedPayload) *impb.ServerEvent_DeliveryDegradedEvent {
+	if p == nil {
+		return nil
+	}
+	return &impb.ServerEvent_DeliveryDegradedEvent{
+		DeliveryDegradedEvent: &impb.DeliveryDegradedEvent{Dropped: p.Dropped, Threshold: p.Threshold},
+	}
+}
+
 // marshalDisconnectedPayload maps system closure notification to PB.
 func marshalDisconnectedPayload(p *model.DisconnectedPayload) *impb.ServerEvent_DisconnectedEvent {
 	if p == nil {
@@ -31,3 +72,14 @@ func marshalDisconnectedPayload(p *model.DisconnectedPayload) *impb.ServerEvent_
 		},
 	}
 }
+
+// marshalPingPayload maps the idle-stream heartbeat to the PingEvent case.
+// Echo carries the server time (Unix ms) so clients can estimate clock skew and RTT.
+func marshalPingPayload(p *model.PingPayload) *impb.ServerEvent_PingEvent {
+	if p == nil {
+		return nil
+	}
+	return &impb.ServerEvent_PingEvent{
+		PingEvent: &impb.PingEvent{Echo: strconv.FormatInt(p.ServerTime, 10)},
+	}
+}
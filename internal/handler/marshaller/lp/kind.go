@@ -0,0 +1,45 @@
+package lpmarshaller
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// KindUnrecognized is the kind of events this build has no name for; the numeric
+// kind is sent alongside so newer producers stay readable by older clients.
+const KindUnrecognized = "unrecognized"
+
+// kindName turns the EventKind stringer output into snake_case ("MessageCreated" -> "message_created").
+// [FORWARD_COMPAT] Values the stringer does not know render as "EventKind(N)".
+func kindName(k event.EventKind) (string, bool) {
+	s := k.String()
+	if strings.HasPrefix(s, "EventKind(") {
+		return KindUnrecognized, false
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 4)
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), true
+}
+
+func priorityName(p event.EventPriority) string {
+	switch {
+	case p >= event.PriorityHigh:
+		return "high"
+	case p >= event.PriorityNormal:
+		return "normal"
+	default:
+		return "low"
+	}
+}
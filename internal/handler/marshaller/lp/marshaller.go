@@ -9,39 +9,139 @@ import (
 
 // LPEvent represents a single event structured for long-polling consumers.
 type LPEvent struct {
-	Type    string `json:"type"`
-	ID      string `json:"id"`
-	Payload any    `json:"payload"`
+	Type       string `json:"type"`                // Payload-specific name kept for existing clients
+	Kind       string `json:"kind"`                // snake_case EventKind, or "unrecognized"
+	KindCode   int32  `json:"kind_code,omitempty"` // Numeric kind; only set when Kind is "unrecognized"
+	Priority   string `json:"priority"`            // low | normal | high
+	OccurredAt int64  `json:"occurred_at"`         // Unix ms
+	ID         string `json:"id"`
+	Cursor     string `json:"cursor,omitempty"`   // Resume position; send back on the next poll
+	Replay     bool   `json:"replayed,omitempty"` // Re-sent from the replay buffer on resume
+	Payload    any    `json:"payload"`
+	Debug      *Debug `json:"debug,omitempty"`
+}
+
+// Debug carries test-only delivery diagnostics.
+type Debug struct {
+	ShapingDelayMs int64 `json:"shaping_delay_ms"`
 }
 
 // Response defines the top-level JSON array to support event batching.
 type Response struct {
-	Events []LPEvent `json:"events"`
+	Events     []json.RawMessage `json:"events"`                // Each one an encoded LPEvent
+	NextCursor string            `json:"next_cursor,omitempty"` // Cursor of the last sequenced event; pass as ?cursor= next time
 }
 
+// encodingPrefix namespaces LP events in the event's encoding cache.
+const encodingPrefix = "lp.json"
+
 // MarshallEvents converts a slice of domain events into a single JSON batch.
 func MarshallEvents(events []event.Eventer) ([]byte, error) {
 	res := Response{
-		Events: make([]LPEvent, 0, len(events)),
+		Events: make([]json.RawMessage, 0, len(events)),
 	}
 
 	for _, ev := range events {
-		lpEv := LPEvent{
-			ID:      ev.GetID(),
-			Payload: ev.GetPayload(),
+		b, err := marshallEvent(ev)
+		if err != nil {
+			return nil, err
 		}
-
-		// Map domain payload types to string identifiers for the frontend.
-		switch ev.GetPayload().(type) {
-		case *model.Message:
-			lpEv.Type = "message_created"
-		case *model.ConnectedPayload:
-			lpEv.Type = "system_connected"
-		default:
-			lpEv.Type = "unknown"
+		if cur, ok := event.CursorOf(ev); ok {
+			res.NextCursor = cur.String()
 		}
-		res.Events = append(res.Events, lpEv)
+		res.Events = append(res.Events, b)
 	}
 
 	return json.Marshal(res)
 }
+
+// marshallEvent encodes one event.
+// [CACHE] Concurrent polls of one user share the encoding; it is built once per event.
+func marshallEvent(ev event.Eventer) ([]byte, error) {
+	key, ok := event.EncodingKey(encodingPrefix, 0, ev)
+	if !ok {
+		return json.Marshal(mapEvent(ev))
+	}
+
+	enc := ev.Encoded()
+	if b, ok := enc.Bytes(key); ok {
+		return b, nil
+	}
+	b, err := json.Marshal(mapEvent(ev))
+	if err != nil {
+		return nil, err
+	}
+	return enc.StoreBytes(key, b), nil
+}
+
+// mapEvent builds the LPEvent for one domain event.
+func mapEvent(ev event.Eventer) LPEvent {
+	lpEv := LPEvent{
+		ID:         ev.GetID(),
+		Priority:   priorityName(ev.GetPriority()),
+		OccurredAt: ev.GetOccurredAt(),
+		Payload:    ev.GetPayload(),
+	}
+
+	kind, known := kindName(ev.GetKind())
+	lpEv.Kind = kind
+	if !known {
+		lpEv.KindCode = int32(ev.GetKind())
+	}
+
+	if cur, ok := event.CursorOf(ev); ok {
+		lpEv.Cursor = cur.String()
+	}
+	lpEv.Replay = event.IsReplayed(ev)
+
+	if s, ok := ev.(event.Shaped); ok {
+		lpEv.Debug = &Debug{ShapingDelayMs: s.GetShapingDelay().Milliseconds()}
+	}
+
+	// Map domain payload types to string identifiers for the frontend.
+	switch p := ev.GetPayload().(type) {
+	case *model.Message:
+		lpEv.Type = "message_created"
+		if ev.GetKind() == event.MessageUpdated {
+			lpEv.Type = "message_updated"
+		}
+		if p.IsEncrypted() {
+			// [E2EE] Drop key envelopes addressed to other recipients.
+			lpEv.Type = "encrypted_message"
+			lpEv.Payload = p.ForRecipient(ev.GetUserID())
+		}
+	case *model.ConnectedPayload:
+		lpEv.Type = "system_connected"
+	case *model.DisconnectedPayload:
+		lpEv.Type = "system_disconnected"
+	case *model.PingPayload:
+		lpEv.Type = "ping"
+	case *model.PresencePayload:
+		lpEv.Type = "presence_changed"
+	case *model.PresenceStatusPayload:
+		lpEv.Type = "presence_status"
+	case *model.TopicPayload:
+		lpEv.Type = "topic_message"
+	case *model.ReplayGapPayload:
+		lpEv.Type = "replay_gap"
+	case *model.DomainPausePayload:
+		lpEv.Type = "domain_paused"
+		if ev.GetKind() == event.DomainResumed {
+			lpEv.Type = "domain_resumed"
+		}
+	case *model.DeliveryDegradedPayload:
+		lpEv.Type = "delivery_degraded"
+	case *model.MessageDeleted:
+		lpEv.Type = "message_deleted"
+	case *model.TypingPayload:
+		lpEv.Type = "typing"
+	case *model.MessageRead:
+		lpEv.Type = "message_read"
+	case *model.Reaction:
+		lpEv.Type = "message_reaction"
+	default:
+		// [FORWARD_COMPAT] No payload mapping yet: fall back to the kind name.
+		lpEv.Type = kind
+	}
+	return lpEv
+}
@@ -9,30 +9,113 @@ import (
 
 // WSEvent is a generic wrapper for WebSocket messages to provide consistent structure
 type WSEvent struct {
-	Event   string `json:"event"` // e.g., "message_created", "connected"
-	ID      string `json:"id"`    // message or event ID
+	V       int    `json:"v,omitempty"` // Frame version; absent in the deprecated legacy format
+	Event   string `json:"event"`       // e.g., "message_created", "connected"
+	ID      string `json:"id"`          // message or event ID
 	SentAt  int64  `json:"sent_at"`
+	Cursor  string `json:"cursor,omitempty"`   // Resume position; send back on reconnect
+	Replay  bool   `json:"replayed,omitempty"` // Re-sent from the replay buffer on resume
 	Payload any    `json:"payload"`
+	Debug   *Debug `json:"debug,omitempty"`
 }
 
-// MarshallDeliveryEvent prepares data for WebSocket transmission.
+// Debug carries test-only delivery diagnostics.
+type Debug struct {
+	ShapingDelayMs int64 `json:"shaping_delay_ms"`
+}
+
+// MarshallDeliveryEvent prepares data for WebSocket transmission in the legacy, un-versioned format.
 func MarshallDeliveryEvent(ev event.Eventer) ([]byte, error) {
+	return MarshallVersioned(ev, 0)
+}
+
+// MarshallVersioned prepares data for WebSocket transmission in the negotiated frame version.
+// [CACHE] Sessions of one user share the frame; it is encoded once per event and version.
+func MarshallVersioned(ev event.Eventer, version int) ([]byte, error) {
+	key, ok := event.EncodingKey(encodingPrefix, version, ev)
+	if !ok {
+		return json.Marshal(MapVersioned(ev, version))
+	}
+
+	enc := ev.Encoded()
+	if b, ok := enc.Bytes(key); ok {
+		return b, nil
+	}
+	b, err := json.Marshal(MapVersioned(ev, version))
+	if err != nil {
+		return nil, err
+	}
+	return enc.StoreBytes(key, b), nil
+}
+
+// encodingPrefix namespaces WS frames in the event's encoding cache.
+const encodingPrefix = "ws.json"
+
+// MapVersioned builds the JSON frame without encoding it, for transports (SSE) that
+// also need the event name outside the body.
+func MapVersioned(ev event.Eventer, version int) *WSEvent {
 	// We don't use gRPC cache here because WS uses JSON.
 	// Instead, we map domain model to a friendly JSON structure.
 
 	res := &WSEvent{
+		V:      version,
 		ID:     ev.GetID(),
 		SentAt: ev.GetOccurredAt(),
 	}
 
+	if cur, ok := event.CursorOf(ev); ok {
+		res.Cursor = cur.String()
+	}
+	res.Replay = event.IsReplayed(ev)
+
+	if s, ok := ev.(event.Shaped); ok {
+		res.Debug = &Debug{ShapingDelayMs: s.GetShapingDelay().Milliseconds()}
+	}
+
 	switch p := ev.GetPayload().(type) {
 	case *model.Message:
+		if p.IsEncrypted() {
+			// [E2EE] Dedicated shape: ciphertext plus the recipient's own key envelope only.
+			res.Event = "encrypted_message"
+			res.Payload = mapEncryptedMessage(p, ev.GetUserID())
+			break
+		}
 		res.Event = "message_created"
+		if ev.GetKind() == event.MessageUpdated {
+			res.Event = "message_updated"
+		}
 		res.Payload = mapMessage(p)
 	case *model.ConnectedPayload:
 		res.Event = "connected"
 		res.Payload = p
+	case *model.TopicPayload:
+		res.Event = "topic_message"
+		res.Payload = p
+	case *model.ReplayGapPayload:
+		res.Event = "replay_gap"
+		res.Payload = p
+	case *model.DomainPausePayload:
+		res.Event = "domain_paused"
+		if ev.GetKind() == event.DomainResumed {
+			res.Event = "domain_resumed"
+		}
+		res.Payload = p
+	case *model.DeliveryDegradedPayload:
+		res.Event = "delivery_degraded"
+		res.Payload = p
+	case *model.MessageDeleted:
+		res.Event = "message_deleted"
+		res.Payload = p
+	case *model.TypingPayload:
+		res.Event = "typing"
+		res.Payload = p
+	case *model.MessageRead:
+		res.Event = "message_read"
+		res.Payload = p
+	case *model.Reaction:
+		res.Event = "message_reaction"
+		res.Payload = p
 	}
 
-	return json.Marshal(res)
+	return res
 }
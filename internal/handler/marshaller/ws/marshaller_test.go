@@ -0,0 +1,63 @@
+package wsmarshaller
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+func TestEncryptedMessageFrame(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	content := func() *model.EncryptedContent {
+		return &model.EncryptedContent{
+			Ciphertext: []byte("opaque"),
+			Algorithm:  "aes-256-gcm",
+			KeyID:      "k1",
+			Envelopes: []model.KeyEnvelope{
+				{RecipientID: alice, WrappedKey: "for-alice"},
+				{RecipientID: bob, WrappedKey: "for-bob"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		recipient uuid.UUID
+		wantKey   string
+		leakedKey string
+	}{
+		{name: "alice's leg", recipient: alice, wantKey: "for-alice", leakedKey: "for-bob"},
+		{name: "bob's leg", recipient: bob, wantKey: "for-bob", leakedKey: "for-alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &model.Message{ID: uuid.New(), Encrypted: content()}
+			ev := event.NewMessageV1Event(msg, tt.recipient, model.Peer{}, model.Peer{})
+
+			b, err := MarshallVersioned(ev, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var frame struct {
+				Event   string         `json:"event"`
+				Payload map[string]any `json:"payload"`
+			}
+			if err := json.Unmarshal(b, &frame); err != nil {
+				t.Fatal(err)
+			}
+			if frame.Event != "encrypted_message" {
+				t.Fatalf("event = %q, want encrypted_message", frame.Event)
+			}
+			if _, ok := frame.Payload["text"]; ok {
+				t.Fatalf("encrypted frame carries a text field: %s", b)
+			}
+			if !strings.Contains(string(b), tt.wantKey) || strings.Contains(string(b), tt.leakedKey) {
+				t.Fatalf("frame must carry only %q: %s", tt.wantKey, b)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package wsmarshaller
 
 import (
+	"strings"
+
+	"github.com/google/uuid"
 	"github.com/webitel/im-delivery-service/internal/domain/model"
 )
 
@@ -11,9 +14,30 @@ type WSMessage struct {
 	CreatedAt int64          `json:"created_at"`
 	UpdatedAt int64          `json:"updated_at,omitempty"`
 	From      string         `json:"from_id"`
-	Type      string         `json:"type"` // "text", "image", "document"
+	Sender    *WSPeer        `json:"sender,omitempty"` // Enriched profile of From; absent when enrichment failed
+	Type      string         `json:"type"`             // "text", "image", "document"
 	Media     any            `json:"media,omitempty"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
+	IsForward bool           `json:"is_forward,omitempty"`
+}
+
+// WSEncryptedMessage carries an E2EE body untouched; no plaintext fields exist on it.
+type WSEncryptedMessage struct {
+	ID        string                  `json:"id"`
+	ThreadID  string                  `json:"thread_id"`
+	CreatedAt int64                   `json:"created_at"`
+	From      string                  `json:"from_id"`
+	Encrypted *model.EncryptedContent `json:"encrypted"`
+}
+
+func mapEncryptedMessage(m *model.Message, recipientID uuid.UUID) *WSEncryptedMessage {
+	return &WSEncryptedMessage{
+		ID:        m.ID.String(),
+		ThreadID:  m.ThreadID.String(),
+		CreatedAt: m.CreatedAt,
+		From:      m.From.ID.String(),
+		Encrypted: m.Encrypted.ForRecipient(recipientID),
+	}
 }
 
 func mapMessage(m *model.Message) *WSMessage {
@@ -24,7 +48,9 @@ func mapMessage(m *model.Message) *WSMessage {
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.EditedAt,
 		From:      m.From.ID.String(),
+		Sender:    mapPeer(m.From),
 		Metadata:  m.Metadata,
+		IsForward: m.IsForward,
 		Type:      "text",
 	}
 
@@ -39,3 +65,27 @@ func mapMessage(m *model.Message) *WSMessage {
 
 	return msg
 }
+
+// WSPeer is the display profile of an enriched peer.
+type WSPeer struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"` // "user", "group", "channel", "bot"
+	Name      string `json:"name,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+
+	MemberCount int `json:"member_count,omitempty"` // Groups only
+}
+
+func mapPeer(p model.Peer) *WSPeer {
+	if !p.IsEnriched() {
+		return nil
+	}
+	return &WSPeer{
+		ID:        p.ID.String(),
+		Type:      strings.ToLower(strings.TrimPrefix(p.Type.String(), "Peer")),
+		Name:      p.Name,
+		AvatarURL: p.AvatarURL,
+
+		MemberCount: p.MemberCount,
+	}
+}
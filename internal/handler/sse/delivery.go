@@ -0,0 +1,244 @@
+package sse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	wsmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/ws"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+)
+
+const (
+	// defaultHeartbeat keeps proxies from timing out a quiet stream.
+	defaultHeartbeat = 15 * time.Second
+	// defaultWriteTimeout bounds writing one event.
+	defaultWriteTimeout = 10 * time.Second
+	// frameVersion is the WS JSON mapping carried in data lines (ws.CurrentFrameVersion).
+	frameVersion = 1
+)
+
+// LastEventIDHeader is sent by EventSource on reconnect with the last id: it saw.
+const LastEventIDHeader = "Last-Event-ID"
+
+type SSEHandler struct {
+	logger       *slog.Logger
+	deliverer    service.Deliverer
+	heartbeat    time.Duration
+	writeTimeout time.Duration
+}
+
+// Option defines a functional configuration type for the SSEHandler.
+type Option func(*SSEHandler)
+
+// WithSSEHeartbeat sets the comment heartbeat period; zero disables it.
+func WithSSEHeartbeat(d time.Duration) Option {
+	return func(h *SSEHandler) {
+		h.heartbeat = d
+	}
+}
+
+// WithSSEWriteTimeout sets the deadline for writing a single event.
+func WithSSEWriteTimeout(d time.Duration) Option {
+	return func(h *SSEHandler) {
+		h.writeTimeout = d
+	}
+}
+
+func NewSSEHandler(logger *slog.Logger, deliverer service.Deliverer, opts ...Option) *SSEHandler {
+	h := &SSEHandler{
+		logger:       logger,
+		deliverer:    deliverer,
+		heartbeat:    defaultHeartbeat,
+		writeTimeout: defaultWriteTimeout,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Stream serves a text/event-stream session until the client or the server ends it.
+func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	// 1. Identity comes from httpauth.Middleware; the path only has to agree with it.
+	id, ok := httpauth.RequireUser(w, r, chi.URLParam(r, "userID"))
+	if !ok {
+		return
+	}
+	userID := id.UserID
+
+	// [RESUME] Every id: line is the event's cursor when it has one, so the header
+	// EventSource replays on reconnect is usually a cursor; anything else is a raw ID.
+	var (
+		resume      event.Cursor
+		lastEventID string
+	)
+	if raw := r.Header.Get(LastEventIDHeader); raw != "" {
+		if cur, err := event.ParseCursor(raw); err == nil {
+			resume = cur
+		} else {
+			lastEventID = raw
+		}
+	}
+
+	// 2. Subscription for the lifetime of the request.
+	md := connectMetadata(r)
+	conn, err := h.deliverer.Subscribe(r.Context(), userID, service.SubscribeOptions{
+		Transport:   service.TransportSSE,
+		DomainID:    id.Contact.DC,
+		Resume:      resume,
+		LastEventID: lastEventID,
+		Platform:    md.Platform,
+		Metadata:    md,
+	})
+	if errors.Is(err, deprecation.ErrRejected) {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
+	var drainErr *service.DrainingError
+	if errors.As(err, &drainErr) {
+		w.Header().Set("Retry-After", drainErr.Hint.RetryAfterHeader())
+		http.Error(w, registry.ReasonServerDraining, http.StatusServiceUnavailable)
+		return
+	}
+	// [DATA_RESIDENCY] This node may not serve the tenant; the body names the allowed regions.
+	var resErr *service.ResidencyError
+	if errors.As(err, &resErr) {
+		http.Error(w, resErr.Error(), http.StatusMisdirectedRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to subscribe", http.StatusInternalServerError)
+		return
+	}
+	defer h.deliverer.Unsubscribe(userID, conn.GetID())
+
+	events, stopShaping := h.deliverer.Shape(conn, id.Contact.DC)
+	defer stopShaping()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Disables response buffering in nginx-style proxies.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		// The ResponseWriter cannot stream; nothing useful can be sent.
+		return
+	}
+
+	var heartbeat <-chan time.Time
+	if h.heartbeat > 0 {
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	// 3. Pump loop: one write (plus flush) per event.
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-conn.Done():
+			// [RECONNECT_GUIDANCE] retry: sets EventSource's reconnect delay.
+			hint := h.deliverer.DisconnectHint(reconnect.ReasonSessionClosed)
+			_ = h.write(r, rc, w, "retry: "+strconv.FormatInt(hint.RetryAfterMs(), 10)+"\n\n")
+			return
+
+		case <-heartbeat:
+			// Comment lines are ignored by EventSource but keep intermediaries awake.
+			if err := h.write(r, rc, w, ": ping\n\n"); err != nil {
+				return
+			}
+
+		case ev := <-events:
+			marshalStart := time.Now()
+			frame, err := encode(ev)
+			if err != nil {
+				// The stream stays usable; only this event is lost.
+				h.logger.Error("failed to marshal sse event",
+					"error", err,
+					"user_id", userID,
+					"conn_id", conn.GetID(),
+					"event_id", ev.GetID(),
+					"kind", ev.GetKind().String(),
+				)
+				continue
+			}
+			writeStart := time.Now()
+
+			if err := h.write(r, rc, w, frame); err != nil {
+				return
+			}
+			h.deliverer.RecordDelivery(conn, ev, writeStart.Sub(marshalStart), time.Since(writeStart))
+		}
+	}
+}
+
+// encode renders ev as one SSE message: id, event name and the WS JSON frame as data.
+func encode(ev event.Eventer) (string, error) {
+	frame := wsmarshaller.MapVersioned(ev, frameVersion)
+	data, err := json.Marshal(frame)
+	if err != nil {
+		return "", err
+	}
+
+	id := frame.Cursor
+	if id == "" {
+		id = frame.ID
+	}
+	// JSON has no raw newlines, so the body always fits a single data: line.
+	return fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", id, frame.Event, data), nil
+}
+
+// write sends and flushes one chunk under [WRITE_DISCIPLINE].
+func (h *SSEHandler) write(r *http.Request, rc *http.ResponseController, w http.ResponseWriter, chunk string) error {
+	_ = rc.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+	if _, err := w.Write([]byte(chunk)); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			service.RecordStuckWrite(r.Context(), service.TransportSSE, service.StuckWriteDeadline)
+		}
+		return err
+	}
+	return rc.Flush()
+}
+
+// connectMetadata describes the client. EventSource cannot set request headers, so
+// the query parameters "platform", "client_version" and "device_id" are accepted too.
+func connectMetadata(r *http.Request) registry.ConnectMetadata {
+	return registry.ConnectMetadata{
+		Platform:  headerOrQuery(r, "X-Client-Platform", "platform"),
+		Version:   headerOrQuery(r, "X-Client-Version", "client_version"),
+		DeviceID:  headerOrQuery(r, "X-Device-Id", "device_id"),
+		RemoteIP:  remoteIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+func headerOrQuery(r *http.Request, header, param string) string {
+	if v := r.Header.Get(header); v != "" {
+		return v
+	}
+	return r.URL.Query().Get(param)
+}
+
+// remoteIP strips the port from the peer address.
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
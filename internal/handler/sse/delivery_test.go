@@ -0,0 +1,242 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+)
+
+// fakeDeliverer serves one stream from a prepared connector; everything it does not
+// override panics through the nil embedded interface.
+type fakeDeliverer struct {
+	service.Deliverer
+	conn registry.Connector
+
+	mu           sync.Mutex
+	subscribed   service.SubscribeOptions
+	unsubscribed chan struct{}
+}
+
+func newFakeDeliverer(userID uuid.UUID) *fakeDeliverer {
+	return &fakeDeliverer{
+		conn:         registry.NewConnector(context.Background(), userID, 8, registry.ConnectMetadata{}),
+		unsubscribed: make(chan struct{}),
+	}
+}
+
+func (f *fakeDeliverer) Subscribe(_ context.Context, _ uuid.UUID, opts service.SubscribeOptions) (registry.Connector, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscribed = opts
+	return f.conn, nil
+}
+
+func (f *fakeDeliverer) Unsubscribe(_, connID uuid.UUID) {
+	if connID == f.conn.GetID() {
+		close(f.unsubscribed)
+	}
+}
+
+func (f *fakeDeliverer) Shape(conn registry.Connector, _ int64) (<-chan event.Eventer, func()) {
+	return conn.Recv(), func() {}
+}
+
+func (*fakeDeliverer) DisconnectHint(reason reconnect.Reason) reconnect.Hint {
+	return reconnect.Hint{Reason: reason, RetryAfter: time.Second}
+}
+
+func (*fakeDeliverer) RecordDelivery(registry.Connector, event.Eventer, time.Duration, time.Duration) {
+}
+
+func (f *fakeDeliverer) options() service.SubscribeOptions {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.subscribed
+}
+
+// openStream serves h for userID and opens a stream with the given Last-Event-ID.
+// Cancelling ctx disconnects the client.
+func openStream(ctx context.Context, t *testing.T, h *SSEHandler, userID uuid.UUID, lastEventID string) *bufio.Reader {
+	t.Helper()
+	router := chi.NewRouter()
+	router.Get(Route, func(w http.ResponseWriter, r *http.Request) {
+		id := httpauth.Identity{Contact: &model.AuthContact{DC: 1}, UserID: userID}
+		h.Stream(w, r.WithContext(httpauth.WithIdentity(r.Context(), id)))
+	})
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/sse/"+userID.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set(LastEventIDHeader, lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+// lockedBuffer collects the handler's log lines, written from the serving goroutine.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// readMessage returns the lines of the next SSE message, without the blank terminator.
+func readMessage(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v (after %q)", err, lines)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+// TestStreamFraming checks the id:/event:/data: lines of each message: the cursor is
+// the id when the event has one, and an event that cannot be encoded is logged and skipped.
+func TestStreamFraming(t *testing.T) {
+	userID := uuid.New()
+	d := newFakeDeliverer(userID)
+	var logs lockedBuffer
+	h := NewSSEHandler(slog.New(slog.NewTextHandler(&logs, nil)), d, WithSSEHeartbeat(0))
+	r := openStream(t.Context(), t, h, userID, "")
+
+	cur := event.Cursor{Epoch: 7, Seq: 3}
+	sequenced := event.NewSequencedEvent(event.NewSystemEvent(userID, event.Connected, event.PriorityNormal, &model.ConnectedPayload{Ok: true}), cur)
+	broken := event.NewTopicEvent("call.1", []byte("{"))
+	topic := event.NewTopicEvent("call.1", []byte(`{"text":"hi"}`))
+	for _, ev := range []event.Eventer{sequenced, broken, topic} {
+		d.conn.Send(ev, time.Second)
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		event   string
+		payload string
+	}{
+		{name: "sequenced event", id: cur.String(), event: "connected", payload: `"ok":true`},
+		{name: "event without a cursor", id: topic.GetID(), event: "topic_message", payload: `"data":{"text":"hi"}`},
+	}
+	for _, tt := range tests {
+		lines := readMessage(t, r)
+		if len(lines) != 3 {
+			t.Fatalf("%s: message has %d lines, want 3: %q", tt.name, len(lines), lines)
+		}
+		if want := "id: " + tt.id; lines[0] != want {
+			t.Fatalf("%s: %q, want %q", tt.name, lines[0], want)
+		}
+		if want := "event: " + tt.event; lines[1] != want {
+			t.Fatalf("%s: %q, want %q", tt.name, lines[1], want)
+		}
+		if !strings.HasPrefix(lines[2], "data: {") || !strings.Contains(lines[2], tt.payload) {
+			t.Fatalf("%s: data line %q, want a JSON frame with %s", tt.name, lines[2], tt.payload)
+		}
+	}
+	if !strings.Contains(logs.String(), "event_id="+broken.GetID()) {
+		t.Fatalf("encode failure not logged:\n%s", logs.String())
+	}
+}
+
+// TestStreamResume checks how the Last-Event-ID header EventSource sends on reconnect
+// reaches Subscribe: a cursor resumes from it, anything else is a raw event ID.
+func TestStreamResume(t *testing.T) {
+	cur := event.Cursor{Epoch: 7, Seq: 42}
+	tests := []struct {
+		name       string
+		header     string
+		wantResume event.Cursor
+		wantID     string
+	}{
+		{name: "no header"},
+		{name: "cursor", header: cur.String(), wantResume: cur},
+		{name: "raw event id", header: "legacy-event-id", wantID: "legacy-event-id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			d := newFakeDeliverer(userID)
+			h := NewSSEHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), d, WithSSEHeartbeat(0))
+			r := openStream(t.Context(), t, h, userID, tt.header)
+
+			// The first message proves the stream is up, so Subscribe has run.
+			d.conn.Send(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil), time.Second)
+			readMessage(t, r)
+
+			opts := d.options()
+			if opts.Transport != service.TransportSSE || opts.DomainID != 1 {
+				t.Fatalf("subscribed as %s in domain %d", opts.Transport, opts.DomainID)
+			}
+			if opts.Resume != tt.wantResume || opts.LastEventID != tt.wantID {
+				t.Fatalf("resume %v / %q, want %v / %q", opts.Resume, opts.LastEventID, tt.wantResume, tt.wantID)
+			}
+		})
+	}
+}
+
+// TestStreamHeartbeatAndDisconnect checks that a quiet stream sends comment heartbeats
+// and that a client disconnect unsubscribes the session.
+func TestStreamHeartbeatAndDisconnect(t *testing.T) {
+	userID := uuid.New()
+	d := newFakeDeliverer(userID)
+	h := NewSSEHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), d, WithSSEHeartbeat(10*time.Millisecond))
+
+	ctx, disconnect := context.WithCancel(t.Context())
+	r := openStream(ctx, t, h, userID, "")
+	for range 2 {
+		if lines := readMessage(t, r); len(lines) != 1 || lines[0] != ": ping" {
+			t.Fatalf("heartbeat = %q, want a single comment line", lines)
+		}
+	}
+
+	disconnect()
+	select {
+	case <-d.unsubscribed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("session not unsubscribed after the client left")
+	}
+}
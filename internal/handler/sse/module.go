@@ -0,0 +1,28 @@
+package sse
+
+import (
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+	httpsrv "github.com/webitel/im-delivery-service/infra/server/http"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"go.uber.org/fx"
+)
+
+// Route is the event-stream endpoint; {userID} must name the authenticated caller.
+const Route = "/sse/{userID}"
+
+var Module = fx.Module("delivery-sse",
+	fx.Provide(func(logger *slog.Logger, deliverer service.Deliverer) *SSEHandler {
+		return NewSSEHandler(logger, deliverer)
+	}),
+	fx.Invoke(Register),
+)
+
+// Register mounts Stream behind auth, on the same router as the long-poll handler.
+func Register(server *httpsrv.Server, auth httpauth.Middleware, h *SSEHandler) {
+	server.Authenticated(auth, func(r chi.Router) {
+		r.Get(Route, h.Stream)
+	})
+}
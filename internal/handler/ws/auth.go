@@ -0,0 +1,57 @@
+package ws
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+)
+
+const (
+	// TokenSubprotocol marks the [BROWSER_AUTH] subprotocol trick: browsers cannot set
+	// headers on upgrade, so they offer "Sec-WebSocket-Protocol: access_token, <token>".
+	// Only the marker is echoed back; the token never becomes the negotiated protocol.
+	TokenSubprotocol = "access_token"
+)
+
+// accessToken reads the token from, in order: the shared httpauth sources, the
+// "token" query parameter, the subprotocol list.
+func accessToken(r *http.Request) string {
+	if v := httpauth.Token(r); v != "" {
+		return v
+	}
+	if v := r.URL.Query().Get("token"); v != "" {
+		return v
+	}
+	protocols := websocketSubprotocols(r)
+	for i, p := range protocols {
+		if p == TokenSubprotocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
+
+func websocketSubprotocols(r *http.Request) []string {
+	var res []string
+	for _, v := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for part := range strings.SplitSeq(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				res = append(res, part)
+			}
+		}
+	}
+	return res
+}
+
+// authenticate resolves the caller through the same Auther as the gRPC interceptor.
+// An identity already established by httpauth.Middleware wins; otherwise the WS-only
+// token sources (query, subprotocol) are tried on top of the shared ones.
+func (h *WSHandler) authenticate(r *http.Request) (*model.AuthContact, uuid.UUID, error) {
+	if id, ok := httpauth.FromContext(r.Context()); ok {
+		return id.Contact, id.UserID, nil
+	}
+	return httpauth.Authenticate(r, h.auther, accessToken(r))
+}
@@ -0,0 +1,36 @@
+package ws
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	grpcmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/gprc"
+	wsmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/ws"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoSubprotocol selects binary frames carrying serialized impb.ServerEvent messages,
+// the same encoding gRPC clients receive.
+const ProtoSubprotocol = "webitel.delivery.v1+proto"
+
+// frameCodec turns events into frames for one socket.
+type frameCodec struct {
+	binary  bool
+	version int // JSON frame version; unused for binary frames
+}
+
+// codecFor picks the encoding from the negotiated subprotocol.
+func codecFor(ws *websocket.Conn, version int) frameCodec {
+	return frameCodec{binary: ws.Subprotocol() == ProtoSubprotocol, version: version}
+}
+
+// encode returns the frame type and body for ev.
+// [SINGLE_MARSHAL] Binary frames reuse the ServerEvent the gRPC marshaller cached on the
+// event, so every session of the user group shares one mapping whatever its transport.
+func (c frameCodec) encode(ev event.Eventer) (int, []byte, error) {
+	if c.binary {
+		data, err := proto.Marshal(grpcmarshaller.MarshallDeliveryEvent(ev))
+		return websocket.BinaryMessage, data, err
+	}
+	data, err := wsmarshaller.MarshallVersioned(ev, c.version)
+	return websocket.TextMessage, data, err
+}
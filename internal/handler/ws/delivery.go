@@ -1,72 +1,300 @@
 package ws
 
 import (
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"net"
 	"net/http"
+	"strconv"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
-	wsmarshaller "github.com/webitel/im-delivery-service/internal/handler/marshaller/ws"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
 	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+)
+
+// SessionTokenHeader carries the reconnection token (response on upgrade, request on redial).
+const SessionTokenHeader = "WS-Session-Token"
+
+// Client description headers; browsers that cannot set them on upgrade use the
+// "platform" and "device_id" query parameters instead.
+const (
+	PlatformHeader = "X-Client-Platform"
+	DeviceIDHeader = "X-Device-Id"
 )
 
 type WSHandler struct {
 	logger    *slog.Logger
 	deliverer service.Deliverer
+	auther    service.Auther
+	tokens    *service.SessionTokens
+	publisher service.ClientEventPublisher
 	upgrader  websocket.Upgrader
+
+	origins        *originPolicy
+	writeTimeout   time.Duration
+	pingInterval   time.Duration
+	maxFrameBytes  int
+	maxHeaderBytes int
 }
 
-func NewWSHandler(logger *slog.Logger, deliverer service.Deliverer) *WSHandler {
-	return &WSHandler{
+func NewWSHandler(logger *slog.Logger, deliverer service.Deliverer, auther service.Auther, tokens *service.SessionTokens, publisher service.ClientEventPublisher, opts ...Option) *WSHandler {
+	h := &WSHandler{
 		logger:    logger,
 		deliverer: deliverer,
+		auther:    auther,
+		tokens:    tokens,
+		publisher: publisher,
+		origins:   newOriginPolicy(nil, false),
 		upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool { return true }, // Security: adjust for production
+			// [NEGOTIATION] Server order wins: the binary codec is preferred when offered.
+			Subprotocols: []string{ProtoSubprotocol, TokenSubprotocol},
 		},
+		writeTimeout:  defaultWriteTimeout,
+		pingInterval:  defaultPingInterval,
+		maxFrameBytes: defaultMaxFrameBytes,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.upgrader.CheckOrigin = h.origins.allows
+	return h
 }
 
 func (h *WSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// 1. EXTRACT USER ID (In production: from JWT/Cookie)
-	userID := uuid.MustParse("019bb6d7-8bb8-7a5c-b163-8cf8d362a474")
+	// 0. [CSWSH] Refuse foreign origins and oversized headers before any work is done.
+	if h.maxHeaderBytes > 0 && headerBytes(r) > h.maxHeaderBytes {
+		http.Error(w, "request headers too large", http.StatusRequestHeaderFieldsTooLarge)
+		return
+	}
+	if !h.origins.allows(r) {
+		h.logger.Warn("ws origin rejected", "origin", r.Header.Get("Origin"))
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	// 1. AUTHENTICATE BEFORE THE UPGRADE, so a rejection is a plain HTTP status.
+	auth, userID, err := h.authenticate(r)
+	if err != nil {
+		if errors.Is(err, httpauth.ErrInvalidIdentity) {
+			h.logger.Error("[AUTH] failed to parse contact identity", "contact_id", auth.ContactID, "error", err)
+			http.Error(w, "invalid user id format", http.StatusBadRequest)
+			return
+		}
+		h.logger.Debug("ws authentication failed", "error", err)
+		http.Error(w, "authentication failed", http.StatusUnauthorized)
+		return
+	}
+
+	// [RESUME] Optional cursor of the last processed event from a previous connection.
+	var resume event.Cursor
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		if resume, err = event.ParseCursor(raw); err != nil {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+	// [LEGACY_RESUME] Older clients only know the ID of the last event they processed.
+	lastEventID := r.URL.Query().Get("last_event_id")
+	lastEventTS, _ := strconv.ParseInt(r.URL.Query().Get("last_event_ts"), 10, 64)
+
+	// [RECONNECTION_TOKEN] A redial within the window continues where the dropped socket stopped.
+	// An explicit cursor wins; a bad or spent token degrades to a fresh stream.
+	if token := sessionToken(r); token != "" && resume.IsZero() {
+		if cur, err := h.tokens.Redeem(token, userID); err == nil {
+			resume = cur
+		} else {
+			h.logger.Debug("ws session token rejected", "error", err)
+		}
+	}
+
+	var respHeader http.Header
+	session, token, err := h.tokens.Issue(userID)
+	if err != nil {
+		h.logger.Warn("ws session token unavailable", "error", err)
+	} else {
+		respHeader = http.Header{SessionTokenHeader: []string{token}}
+		defer h.tokens.End(session)
+	}
 
 	// 2. UPGRADE TO WEBSOCKET
-	ws, err := h.upgrader.Upgrade(w, r, nil)
+	ws, err := h.upgrader.Upgrade(w, r, respHeader)
 	if err != nil {
 		h.logger.Error("ws upgrade failed", "error", err)
 		return
 	}
 	defer ws.Close()
 
+	// [DEPRECATION] Checked after the upgrade so a rejection reaches the client as a close code.
+	version := frameVersion(r)
+	deprecations, err := connectionDeprecations(r.Context(), version, deprecation.Scope{ClientVersion: clientVersion(r)})
+	if err != nil {
+		closeDeprecated(ws, err)
+		return
+	}
+
 	// 3. SUBSCRIBE VIA THE SAME SERVICE
-	conn, err := h.deliverer.Subscribe(r.Context(), userID)
+	md := connectMetadata(r)
+	conn, err := h.deliverer.Subscribe(r.Context(), userID, service.SubscribeOptions{
+		Transport:   service.TransportWS,
+		DomainID:    auth.DC,
+		Resume:      resume,
+		LastEventID: lastEventID,
+		LastEventTS: lastEventTS,
+		Platform:    md.Platform,
+		Metadata:    md,
+	})
 	if err != nil {
+		if errors.Is(err, deprecation.ErrRejected) {
+			closeDeprecated(ws, err)
+		}
+		if errors.Is(err, service.ErrServerDraining) {
+			h.closeWithHint(ws, reconnect.ReasonShutdown, registry.ReasonServerDraining)
+		}
 		return
 	}
+	if resume.IsZero() && lastEventID != "" {
+		deprecations = append(deprecations, deprecation.Names(service.DeprecatedRawLastEventID)...)
+	}
 	defer h.deliverer.Unsubscribe(userID, conn.GetID())
 
-	h.logger.Info("ws opened", "user_id", userID, "conn_id", conn.GetID())
+	l := h.logger.With("user_id", userID, "domain_id", auth.DC, "conn_id", conn.GetID(), slog.Any("client", md))
+	l.Info("ws opened")
+
+	// [HANDSHAKE] Mirrors the gRPC welcome event so clients see their deprecations in their own telemetry.
+	welcomeEv := event.NewSystemEvent(userID, event.Connected, event.PriorityNormal, &model.ConnectedPayload{
+		Ok:            true,
+		ConnectionID:  conn.GetID().String(),
+		ServerVersion: model.ServerVersion,
+		BufferSize:    cap(conn.Recv()),
+		Capabilities:  h.deliverer.Capabilities(auth.DC),
+		Deprecations:  deprecations,
+	})
+	codec := codecFor(ws, version)
+	if msgType, data, err := codec.encode(welcomeEv); err == nil {
+		if err := h.writeFrame(ws, msgType, data); err != nil {
+			l.Warn("ws handshake delivery failed", "error", err)
+			return
+		}
+	}
+
+	// 4. INBOUND CLIENT FRAMES (topic subscriptions, acks, typing)
+	peerGone := h.readFrames(ws, &clientSession{conn: conn, state: session, domainID: auth.DC, l: l})
+
+	// [DELIVERY_SHAPING]
+	events, stopShaping := h.deliverer.Shape(conn, auth.DC)
+	defer stopShaping()
+
+	// [OWNERSHIP] The reader holds the connector too; stop it before Unsubscribe recycles it.
+	defer func() {
+		_ = ws.Close()
+		<-peerGone
+	}()
 
-	// 4. MAIN WS PUMP LOOP
+	// [KEEPALIVE] Pings share the pump with data frames, keeping it the only writer.
+	pings, stopPings := h.newPingTicker()
+	defer stopPings()
+
+	// 5. MAIN WS PUMP LOOP
 	for {
 		select {
 		case <-r.Context().Done():
 			return
-		case ev, ok := <-conn.Recv():
-			if !ok {
+		case <-peerGone:
+			return
+		case <-pings:
+			if err := h.writePing(ws); err != nil {
+				l.Debug("ws ping failed", "error", err)
 				return
 			}
+		case <-conn.Done():
+			h.closeWithHint(ws, reconnect.ReasonSessionClosed, conn.CloseReason())
+			return
+		case ev := <-events:
 
-			data, err := wsmarshaller.MarshallDeliveryEvent(ev)
+			marshalStart := time.Now()
+			msgType, data, err := codec.encode(ev)
 			if err != nil {
 				h.logger.Error("failed to marshal ws event", "error", err)
 				continue
 			}
+			writeStart := time.Now()
 
-			if err := ws.WriteMessage(websocket.TextMessage, data); err != nil {
+			if err := h.writeFrame(ws, msgType, data); err != nil {
+				if classifyWriteErr(err) {
+					// [STUCK_WRITE] Returning unsubscribes the connector so the Cell stops buffering.
+					l.Warn("ws write stuck, closing as slow consumer", "error", err, "timeout", h.writeTimeout)
+					closeSlowConsumer(ws)
+					return
+				}
 				h.logger.Warn("ws send failed", "error", err)
 				return
 			}
+
+			h.deliverer.RecordDelivery(conn, ev, writeStart.Sub(marshalStart), time.Since(writeStart))
+
+			if session != nil {
+				session.Track(ev)
+			}
 		}
 	}
 }
+
+// sessionToken reads the reconnection token from the header or, for browsers that
+// cannot set headers on upgrade, the "session_token" query parameter.
+func sessionToken(r *http.Request) string {
+	if t := r.Header.Get(SessionTokenHeader); t != "" {
+		return t
+	}
+	return r.URL.Query().Get("session_token")
+}
+
+// closeWithHint sends a "try again later" close frame carrying the reconnect guidance.
+// [FRAME_LIMIT] Close reasons are capped at 123 bytes; the compact JSON below fits.
+func (h *WSHandler) closeWithHint(ws *websocket.Conn, reason reconnect.Reason, detail string) {
+	hint := h.deliverer.DisconnectHint(reason)
+	body, _ := json.Marshal(model.DisconnectedPayload{
+		Reason:        detail,
+		Code:          string(hint.Reason),
+		RetryAfterMs:  hint.RetryAfterMs(),
+		AlternateNode: hint.AlternateNode,
+	})
+	if len(body) > 123 {
+		body, _ = json.Marshal(model.DisconnectedPayload{Code: string(hint.Reason), RetryAfterMs: hint.RetryAfterMs()})
+	}
+
+	msg := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, string(body))
+	_ = ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+}
+
+// connectMetadata describes the client from its headers (or query fallbacks).
+func connectMetadata(r *http.Request) registry.ConnectMetadata {
+	return registry.ConnectMetadata{
+		Platform:  headerOrQuery(r, PlatformHeader, "platform"),
+		Version:   clientVersion(r),
+		DeviceID:  headerOrQuery(r, DeviceIDHeader, "device_id"),
+		RemoteIP:  remoteIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+func headerOrQuery(r *http.Request, header, param string) string {
+	if v := r.Header.Get(header); v != "" {
+		return v
+	}
+	return r.URL.Query().Get(param)
+}
+
+// remoteIP strips the port from the peer address.
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,88 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+)
+
+// hintDeliverer answers DisconnectHint only.
+type hintDeliverer struct {
+	service.Deliverer
+	hint reconnect.Hint
+}
+
+func (d hintDeliverer) DisconnectHint(reason reconnect.Reason) reconnect.Hint {
+	h := d.hint
+	h.Reason = reason
+	return h
+}
+
+// TestCloseWithHint checks the guidance carried by the WS close frame, including the
+// fallback that keeps it within the 123-byte control frame limit.
+func TestCloseWithHint(t *testing.T) {
+	tests := []struct {
+		name   string
+		hint   reconnect.Hint
+		detail string
+		want   model.DisconnectedPayload
+	}{
+		{
+			name:   "full guidance",
+			hint:   reconnect.Hint{RetryAfter: 1500 * time.Millisecond, AlternateNode: "node-b:8081"},
+			detail: "slow_consumer",
+			want:   model.DisconnectedPayload{Reason: "slow_consumer", Code: "SLOW_CONSUMER", RetryAfterMs: 1500, AlternateNode: "node-b:8081"},
+		},
+		{
+			name:   "oversized detail is dropped",
+			hint:   reconnect.Hint{RetryAfter: 1500 * time.Millisecond, AlternateNode: "node-b:8081"},
+			detail: strings.Repeat("x", 100),
+			want:   model.DisconnectedPayload{Code: "SLOW_CONSUMER", RetryAfterMs: 1500},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &WSHandler{deliverer: hintDeliverer{hint: tt.hint}}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := h.upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				h.closeWithHint(conn, reconnect.ReasonSlowConsumer, tt.detail)
+			}))
+			t.Cleanup(srv.Close)
+
+			client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			_, _, err = client.ReadMessage()
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) {
+				t.Fatalf("ReadMessage = %v, want a close frame", err)
+			}
+			if closeErr.Code != websocket.CloseTryAgainLater {
+				t.Fatalf("close code = %d, want %d", closeErr.Code, websocket.CloseTryAgainLater)
+			}
+			var got model.DisconnectedPayload
+			if err := json.Unmarshal([]byte(closeErr.Text), &got); err != nil {
+				t.Fatalf("close reason %q: %v", closeErr.Text, err)
+			}
+			if got != tt.want {
+				t.Fatalf("close payload = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
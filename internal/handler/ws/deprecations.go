@@ -0,0 +1,62 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+)
+
+const (
+	// CurrentFrameVersion is the outbound frame format negotiated via ?frame_version=.
+	CurrentFrameVersion = 1
+
+	// CloseDeprecatedFeature is the application close code for a connection that
+	// relies on a feature escalated to reject.
+	CloseDeprecatedFeature = 4009
+
+	// ClientVersionHeader optionally identifies the client build for deprecation metrics.
+	ClientVersionHeader = "X-Client-Version"
+)
+
+// unversionedFrames guards clients that never negotiated a frame version.
+var unversionedFrames = deprecation.Register("unversioned_ws_frame", "WS frames without the \"v\" version field")
+
+// frameVersion reads the negotiated frame version; absent or invalid means the legacy format (0).
+func frameVersion(r *http.Request) int {
+	v, err := strconv.Atoi(r.URL.Query().Get("frame_version"))
+	if err != nil || v < 0 {
+		return 0
+	}
+	return min(v, CurrentFrameVersion)
+}
+
+// clientVersion reads the client build from the header or, for browsers, the query.
+func clientVersion(r *http.Request) string {
+	if v := r.Header.Get(ClientVersionHeader); v != "" {
+		return v
+	}
+	return r.URL.Query().Get("client_version")
+}
+
+// connectionDeprecations reports the connection-scoped deprecated features in use.
+// A rejected feature aborts the connection with its *deprecation.RejectedError.
+func connectionDeprecations(ctx context.Context, version int, scope deprecation.Scope) ([]string, error) {
+	var used []*deprecation.Feature
+	if version == 0 {
+		if err := unversionedFrames.Use(ctx, scope); err != nil {
+			return nil, err
+		}
+		used = append(used, unversionedFrames)
+	}
+	return deprecation.Names(used...), nil
+}
+
+// closeDeprecated ends the socket with a close frame naming the rejected feature.
+func closeDeprecated(ws *websocket.Conn, err error) {
+	msg := websocket.FormatCloseMessage(CloseDeprecatedFeature, err.Error())
+	_ = ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+}
@@ -0,0 +1,185 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
+)
+
+// Inbound frame types understood by the WS transport.
+const (
+	FrameSubscribeTopic   = "subscribe_topic"
+	FrameUnsubscribeTopic = "unsubscribe_topic"
+	FrameAck              = "ack"
+	FrameTyping           = "typing"
+)
+
+const (
+	// defaultMaxFrameBytes caps one inbound client frame; control frames are tiny.
+	defaultMaxFrameBytes = 4 << 10
+	// typingPublishTimeout bounds relaying one typing indicator; a slow bus drops it.
+	typingPublishTimeout = time.Second
+	// topicAuthTimeout bounds authorizing one topic subscription; the frame reader waits on it.
+	topicAuthTimeout = 2 * time.Second
+)
+
+// ClientFrame is a small JSON control message sent by the client over the socket.
+type ClientFrame struct {
+	Type     string `json:"type"`
+	Key      string `json:"key,omitempty"`
+	TTLMs    int64  `json:"ttl_ms,omitempty"`
+	EventID  string `json:"event_id,omitempty"`
+	ThreadID string `json:"thread_id,omitempty"`
+}
+
+// WithMaxFrameBytes caps inbound client frames; a larger one closes the socket
+// with a policy-violation close code.
+func WithMaxFrameBytes(n int) Option {
+	return func(h *WSHandler) {
+		h.maxFrameBytes = n
+	}
+}
+
+// clientSession is what the reader needs to act on frames for one socket.
+type clientSession struct {
+	conn     registry.Connector
+	state    *service.SessionState // nil when no reconnection token was issued
+	domainID int64
+	l        *slog.Logger
+}
+
+// readFrames consumes client frames until the socket fails.
+// [OWNERSHIP] gorilla/websocket allows a single concurrent reader, so this is the only one.
+// The returned channel is closed once the peer goes away, signalling the pump to exit.
+func (h *WSHandler) readFrames(ws *websocket.Conn, cs *clientSession) <-chan struct{} {
+	done := make(chan struct{})
+	l := cs.l
+
+	h.armReadDeadline(ws)
+
+	go func() {
+		defer close(done)
+
+		for {
+			data, err := h.readFrame(ws)
+			if err != nil {
+				switch {
+				case errors.Is(err, errFrameTooLarge):
+					l.Warn("ws client frame too large, closing", "limit", h.maxFrameBytes)
+					closePolicyViolation(ws, "frame_too_large")
+				case isTimeout(err):
+					// [DEAD_PEER] No pong or frame within pongWait: the socket is half-open.
+					l.Info("ws peer missed pong, closing", "wait", h.pongWait())
+				}
+				return
+			}
+			if h.pingInterval > 0 {
+				_ = ws.SetReadDeadline(time.Now().Add(h.pongWait()))
+			}
+
+			// [RESILIENCE] A malformed frame is the client's bug, not a reason to drop its stream.
+			var frame ClientFrame
+			if err := json.Unmarshal(data, &frame); err != nil {
+				l.Debug("ws malformed client frame", "error", err)
+				continue
+			}
+
+			h.handleFrame(cs, &frame)
+		}
+	}()
+
+	return done
+}
+
+// errFrameTooLarge marks a client frame over maxFrameBytes.
+var errFrameTooLarge = errors.New("ws: client frame exceeds limit")
+
+// readFrame reads one message, refusing to buffer more than maxFrameBytes of it.
+// gorilla's own read limit answers with 1009; the limit here lets the caller choose the close code.
+func (h *WSHandler) readFrame(ws *websocket.Conn) ([]byte, error) {
+	_, r, err := ws.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	if h.maxFrameBytes <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, int64(h.maxFrameBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > h.maxFrameBytes {
+		return nil, errFrameTooLarge
+	}
+	return data, nil
+}
+
+// handleFrame dispatches a single decoded client frame.
+func (h *WSHandler) handleFrame(cs *clientSession, frame *ClientFrame) {
+	switch frame.Type {
+	case FrameSubscribeTopic:
+		ttl := time.Duration(frame.TTLMs) * time.Millisecond
+		ctx, cancel := context.WithTimeout(context.Background(), topicAuthTimeout)
+		err := h.deliverer.SubscribeTopic(ctx, cs.conn, cs.domainID, frame.Key, ttl)
+		cancel()
+		if err != nil {
+			cs.l.Warn("ws topic subscription rejected", "key", frame.Key, "error", err)
+		}
+	case FrameUnsubscribeTopic:
+		h.deliverer.UnsubscribeTopic(cs.conn, cs.domainID, frame.Key)
+	case FrameAck:
+		if !cs.state.Ack(frame.EventID) {
+			cs.l.Debug("ws ack for unknown event", "event_id", frame.EventID)
+		}
+	case FrameTyping:
+		h.relayTyping(cs, frame.ThreadID)
+	default:
+		cs.l.Debug("ws unknown client frame", "type", frame.Type)
+	}
+}
+
+// relayTyping publishes the client's indicator where typing producers publish theirs,
+// so it reaches the other participants through the usual ON_THREAD_TYPING consumer.
+func (h *WSHandler) relayTyping(cs *clientSession, threadID string) {
+	if h.publisher == nil {
+		return
+	}
+	if _, err := uuid.Parse(threadID); err != nil {
+		cs.l.Debug("ws typing frame without a valid thread_id", "thread_id", threadID)
+		return
+	}
+
+	userID := cs.conn.GetUserID()
+	body, err := json.Marshal(dto.TypingV1{
+		ThreadID: threadID,
+		DomainID: int32(cs.domainID),
+		From:     dto.PeerDTO{ID: userID.String(), Type: int(model.PeerUser)},
+	})
+	if err != nil {
+		return
+	}
+
+	routingKey := fmt.Sprintf("im_system.%d.thread.typing.v1", cs.domainID)
+	ev := event.NewOutboundEvent(event.Typing, userID, routingKey, body)
+	if err := h.publisher.PublishWithTimeout(context.Background(), ev, typingPublishTimeout); err != nil {
+		cs.l.Debug("ws typing relay failed", "thread_id", threadID, "error", err)
+	}
+}
+
+// closePolicyViolation best-effort tells the peer why its socket is being dropped.
+func closePolicyViolation(ws *websocket.Conn, reason string) {
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+	_ = ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+}
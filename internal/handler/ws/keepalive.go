@@ -0,0 +1,55 @@
+package ws
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// defaultPingInterval is how often the pump pings an otherwise quiet peer.
+	defaultPingInterval = 25 * time.Second
+	// pongGrace is how much longer than one interval a pong may take before the
+	// peer is considered half-open.
+	pongGrace = 5 * time.Second
+)
+
+// WithPingInterval sets the [KEEPALIVE] ping period; 0 disables pings and read deadlines.
+func WithPingInterval(d time.Duration) Option {
+	return func(h *WSHandler) {
+		h.pingInterval = d
+	}
+}
+
+// pongWait is the read deadline extended by every pong or client frame.
+func (h *WSHandler) pongWait() time.Duration {
+	return h.pingInterval + pongGrace
+}
+
+// armReadDeadline makes the reader fail once the peer has been silent for pongWait.
+// [KEEPALIVE] Pongs arrive through the read loop, so the handler runs on the reader goroutine.
+func (h *WSHandler) armReadDeadline(ws *websocket.Conn) {
+	if h.pingInterval <= 0 {
+		return
+	}
+	extend := func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(h.pongWait()))
+	}
+	_ = extend("")
+	ws.SetPongHandler(extend)
+}
+
+// newPingTicker returns the pump's ping channel (nil, never firing, when disabled) and its stop func.
+func (h *WSHandler) newPingTicker() (<-chan time.Time, func()) {
+	if h.pingInterval <= 0 {
+		return nil, func() {}
+	}
+	t := time.NewTicker(h.pingInterval)
+	return t.C, t.Stop
+}
+
+// writePing sends a ping control frame under the write deadline.
+// [WRITE_DISCIPLINE] Called only from the pump, the sole writer of this socket.
+func (h *WSHandler) writePing(ws *websocket.Conn) error {
+	return ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(h.writeTimeout))
+}
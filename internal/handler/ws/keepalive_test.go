@@ -0,0 +1,122 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/infra/keyring"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"github.com/webitel/im-delivery-service/internal/service"
+)
+
+// writePong sends a masked client pong; the zero mask key leaves the payload as is.
+func writePong(w net.Conn) error {
+	_, err := w.Write([]byte{0x80 | 0x0a, 0x80, 0, 0, 0, 0})
+	return err
+}
+
+// TestMissedPong serves a socket whose peer keeps reading but may stop answering
+// pings, and checks that a silent peer is torn down, and unsubscribed, within one
+// missed-pong interval while a responsive one stays attached.
+func TestMissedPong(t *testing.T) {
+	const interval = 10 * time.Second
+	tests := []struct {
+		name     string
+		answer   bool // The peer answers every ping with a pong
+		wantGone bool
+	}{
+		{name: "silent peer is reaped", wantGone: true},
+		{name: "responsive peer stays", answer: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				ring, err := keyring.New(keyring.Snapshot{Active: "k1", Keys: map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("secret"))}}, slog.New(slog.DiscardHandler))
+				if err != nil {
+					t.Fatal(err)
+				}
+				userID := uuid.New()
+				d := &pumpDeliverer{conn: registry.NewConnector(context.Background(), userID, 8, registry.ConnectMetadata{})}
+				h := NewWSHandler(slog.New(slog.DiscardHandler), d, nil, service.NewSessionTokens(ring), nil,
+					WithPingInterval(interval))
+
+				server, peer := net.Pipe()
+				defer peer.Close()
+
+				req := httptest.NewRequest(http.MethodGet, "/ws?frame_version=1", nil)
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+				req.Header.Set("Sec-WebSocket-Version", "13")
+				req.Header.Set("Sec-WebSocket-Key", base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")))
+				req = req.WithContext(httpauth.WithIdentity(req.Context(), httpauth.Identity{Contact: &model.AuthContact{}, UserID: userID}))
+
+				served := make(chan struct{})
+				go func() {
+					defer close(served)
+					h.ServeHTTP(&hijackWriter{conn: server}, req)
+				}()
+
+				br := bufio.NewReader(peer)
+				resp, err := http.ReadResponse(br, req)
+				if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+					t.Fatalf("upgrade: %v, %v", resp, err)
+				}
+				if _, _, err := readFrame(br); err != nil {
+					t.Fatalf("welcome frame: %v", err)
+				}
+				start := time.Now()
+
+				// The peer drains every frame, so only its pongs decide whether it is alive.
+				pings := 0
+				go func() {
+					for {
+						opcode, _, err := readFrame(br)
+						if err != nil {
+							return
+						}
+						if opcode == 0x9 {
+							pings++
+							if tt.answer && writePong(peer) != nil {
+								return
+							}
+						}
+					}
+				}()
+
+				time.Sleep(3*interval + pongGrace)
+				synctest.Wait()
+
+				d.mu.Lock()
+				released := d.unsubscribedAt
+				d.mu.Unlock()
+				if gone := !released.IsZero(); gone != tt.wantGone {
+					t.Fatalf("unsubscribed = %v, want %v", gone, tt.wantGone)
+				}
+				if tt.wantGone {
+					if got, want := released.Sub(start), interval+pongGrace; got != want {
+						t.Fatalf("reaped after %v, want %v", got, want)
+					}
+					<-served
+					return
+				}
+				if pings < 3 {
+					t.Fatalf("peer saw %d pings, want at least 3", pings)
+				}
+
+				peer.Close()
+				<-served
+			})
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package ws
+
+import (
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/webitel/im-delivery-service/config"
+	httpsrv "github.com/webitel/im-delivery-service/infra/server/http"
+	"github.com/webitel/im-delivery-service/internal/service"
+	"go.uber.org/fx"
+)
+
+// Route is the WebSocket upgrade endpoint.
+const Route = "/ws"
+
+var Module = fx.Module("delivery-ws",
+	fx.Provide(func(
+		cfg *config.Config,
+		logger *slog.Logger,
+		deliverer service.Deliverer,
+		auther service.Auther,
+		tokens *service.SessionTokens,
+		publisher service.ClientEventPublisher,
+	) *WSHandler {
+		return NewWSHandler(logger, deliverer, auther, tokens, publisher, WithWSConfig(cfg.Delivery.WS))
+	}),
+	fx.Invoke(Register),
+)
+
+// Register mounts the upgrade outside httpauth.Middleware: browsers pass the token as
+// a subprotocol or query parameter, which authenticate resolves through the same Auther.
+func Register(server *httpsrv.Server, h *WSHandler) {
+	server.Public(func(r chi.Router) {
+		r.Get(Route, h.ServeHTTP)
+	})
+}
@@ -0,0 +1,113 @@
+package ws
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/webitel/im-delivery-service/config"
+)
+
+// originPolicy decides which browser origins may open a socket.
+//
+// [CSWSH] Browsers attach cookies to cross-site upgrades, so an unchecked Origin lets any
+// page open an authenticated socket. Entries are "scheme://host[:port]" or a bare host;
+// "*.example.com" matches any subdomain (not the apex) on any port unless one is given.
+// "null" must be listed explicitly.
+type originPolicy struct {
+	allowAll bool
+	exact    map[string]struct{} // Normalized entries without wildcards
+	suffixes []originSuffix
+}
+
+type originSuffix struct {
+	scheme string // Empty matches any scheme
+	suffix string // ".example.com"
+	port   string // Empty matches any port
+}
+
+func newOriginPolicy(allowed []string, allowAll bool) *originPolicy {
+	p := &originPolicy{allowAll: allowAll, exact: make(map[string]struct{})}
+	for _, raw := range allowed {
+		entry := strings.ToLower(strings.TrimSpace(raw))
+		if entry == "" {
+			continue
+		}
+		scheme, host, ok := strings.Cut(entry, "://")
+		if !ok {
+			scheme, host = "", entry
+		}
+		if rest, ok := strings.CutPrefix(host, "*."); ok {
+			name, port, _ := strings.Cut(rest, ":")
+			p.suffixes = append(p.suffixes, originSuffix{scheme: scheme, suffix: "." + name, port: port})
+			continue
+		}
+		p.exact[entry] = struct{}{}
+	}
+	return p
+}
+
+// allows reports whether the request's Origin may upgrade.
+// A missing Origin means a non-browser client, which cannot be hijacked this way;
+// the request host itself is always allowed (gorilla's default policy).
+func (p *originPolicy) allows(r *http.Request) bool {
+	if p.allowAll {
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if origin == "null" {
+		_, ok := p.exact["null"]
+		return ok
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	if _, ok := p.exact[u.Scheme+"://"+u.Host]; ok {
+		return true
+	}
+	if _, ok := p.exact[u.Host]; ok {
+		return true
+	}
+	hostname := u.Hostname()
+	for _, s := range p.suffixes {
+		if (s.scheme == "" || s.scheme == u.Scheme) && (s.port == "" || s.port == u.Port()) &&
+			strings.HasSuffix(hostname, s.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithWSConfig applies the origin allow-list and buffer limits from configuration.
+func WithWSConfig(c config.WSConfig) Option {
+	return func(h *WSHandler) {
+		h.origins = newOriginPolicy(c.AllowedOrigins, c.AllowAll)
+		if c.ReadBufferSize > 0 {
+			h.upgrader.ReadBufferSize = c.ReadBufferSize
+		}
+		if c.WriteBufferSize > 0 {
+			h.upgrader.WriteBufferSize = c.WriteBufferSize
+		}
+		h.maxHeaderBytes = c.MaxHeaderBytes
+	}
+}
+
+// headerBytes approximates the wire size of the request headers.
+func headerBytes(r *http.Request) int {
+	n := len(r.Host)
+	for k, vs := range r.Header {
+		for _, v := range vs {
+			n += len(k) + len(v) + 4 // ": " and CRLF
+		}
+	}
+	return n
+}
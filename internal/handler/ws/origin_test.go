@@ -0,0 +1,62 @@
+package ws
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginPolicy(t *testing.T) {
+	allowed := []string{
+		"https://app.example.com",
+		"partner.example.org",
+		"https://*.example.net",
+		"https://*.example.com:8443",
+		"*.example.io",
+	}
+
+	tests := []struct {
+		name     string
+		allowed  []string
+		allowAll bool
+		origin   string // Empty sends no Origin header
+		want     bool
+	}{
+		{name: "exact match", allowed: allowed, origin: "https://app.example.com", want: true},
+		{name: "exact match ignores case", allowed: allowed, origin: "HTTPS://App.Example.com", want: true},
+		{name: "exact entry pins the scheme", allowed: allowed, origin: "http://app.example.com", want: false},
+		{name: "exact entry pins the port", allowed: allowed, origin: "https://app.example.com:9000", want: false},
+		{name: "bare host matches any scheme", allowed: allowed, origin: "http://partner.example.org", want: true},
+		{name: "unlisted host", allowed: allowed, origin: "https://evil.example", want: false},
+		{name: "lookalike host", allowed: allowed, origin: "https://app.example.com.evil.example", want: false},
+		{name: "request host is always allowed", origin: "https://delivery.local", want: true},
+
+		{name: "wildcard subdomain", allowed: allowed, origin: "https://chat.example.net", want: true},
+		{name: "wildcard nested subdomain", allowed: allowed, origin: "https://a.b.example.net", want: true},
+		{name: "wildcard any port", allowed: allowed, origin: "https://chat.example.net:9000", want: true},
+		{name: "wildcard excludes the apex", allowed: allowed, origin: "https://example.net", want: false},
+		{name: "wildcard pins the scheme", allowed: allowed, origin: "http://chat.example.net", want: false},
+		{name: "wildcard suffix is not a substring", allowed: allowed, origin: "https://chatexample.net", want: false},
+		{name: "wildcard with port", allowed: allowed, origin: "https://chat.example.com:8443", want: true},
+		{name: "wildcard with port, other port", allowed: allowed, origin: "https://chat.example.com:9443", want: false},
+		{name: "wildcard with port, default port", allowed: allowed, origin: "https://chat.example.com", want: false},
+		{name: "bare wildcard matches any scheme", allowed: allowed, origin: "http://cdn.example.io", want: true},
+
+		{name: "missing origin", allowed: allowed, want: true},
+		{name: "null origin not listed", allowed: allowed, origin: "null", want: false},
+		{name: "null origin listed", allowed: []string{"null"}, origin: "null", want: true},
+		{name: "malformed origin", allowed: allowed, origin: "://", want: false},
+		{name: "allow all", allowAll: true, origin: "https://evil.example", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newOriginPolicy(tt.allowed, tt.allowAll)
+			r := httptest.NewRequest("GET", "http://delivery.local/ws", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := p.allows(r); got != tt.want {
+				t.Fatalf("allows(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
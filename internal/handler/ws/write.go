@@ -0,0 +1,99 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/webitel/im-delivery-service/internal/service"
+)
+
+const (
+	// defaultWriteTimeout bounds a single frame write.
+	defaultWriteTimeout = 10 * time.Second
+	// watchdogGrace is how long past the deadline a write may stay blocked before
+	// the watchdog tears the socket down (covers paths that missed SetWriteDeadline).
+	watchdogGrace = 2 * time.Second
+	// closeFrameTimeout bounds the close frame sent after a stuck write.
+	closeFrameTimeout = time.Second
+
+	// CloseSlowConsumer is the application close code for a peer that stopped reading.
+	CloseSlowConsumer = 4008
+)
+
+// errWriteStuck marks a write aborted by the watchdog.
+var errWriteStuck = errors.New("ws: write blocked past deadline")
+
+// Option defines a functional configuration type for the WSHandler.
+type Option func(*WSHandler)
+
+// WithWriteTimeout sets the per-frame write deadline.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(h *WSHandler) {
+		h.writeTimeout = d
+	}
+}
+
+// writeFrame writes one frame of msgType under [WRITE_DISCIPLINE]: an explicit deadline
+// on every write plus a watchdog that force-closes the TCP conn if the write is still
+// blocked after deadline+grace.
+func (h *WSHandler) writeFrame(ws *websocket.Conn, msgType int, data []byte) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(h.writeTimeout)); err != nil {
+		return err
+	}
+
+	var fired atomic.Bool
+	watchdog := time.AfterFunc(h.writeTimeout+watchdogGrace, func() {
+		fired.Store(true)
+		_ = ws.NetConn().Close()
+	})
+	err := ws.WriteMessage(msgType, data)
+	watchdog.Stop()
+
+	if err != nil && fired.Load() {
+		return errWriteStuck
+	}
+	return err
+}
+
+// classifyWriteErr separates wedged peers from ordinary socket failures and records them.
+func classifyWriteErr(err error) (stuck bool) {
+	switch {
+	case errors.Is(err, errWriteStuck):
+		service.RecordStuckWrite(context.Background(), service.TransportWS, service.StuckWriteWatchdog)
+		return true
+	case isTimeout(err):
+		service.RecordStuckWrite(context.Background(), service.TransportWS, service.StuckWriteDeadline)
+		return true
+	}
+	return false
+}
+
+// isTimeout reports whether err is a write deadline breach.
+func isTimeout(err error) bool {
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// closeSlowConsumer best-effort notifies the peer before the socket is dropped.
+// [POISONED_WRITER] gorilla refuses every write after a failed one, so the close frame
+// bypasses it and goes to the socket directly. A peer that resumes reading gets the
+// code; one that stays wedged costs at most closeFrameTimeout.
+func closeSlowConsumer(ws *websocket.Conn) {
+	nc := ws.NetConn()
+	_ = nc.SetWriteDeadline(time.Now().Add(closeFrameTimeout))
+	_, _ = nc.Write(serverCloseFrame(CloseSlowConsumer, "slow_consumer"))
+}
+
+// serverCloseFrame encodes an unmasked close frame, as servers send them (RFC 6455 5.5.1).
+func serverCloseFrame(code int, reason string) []byte {
+	payload := websocket.FormatCloseMessage(code, reason)
+	return append([]byte{0x80 | websocket.CloseMessage, byte(len(payload))}, payload...)
+}
@@ -0,0 +1,207 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/webitel/im-delivery-service/infra/keyring"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/handler/httpauth"
+	"github.com/webitel/im-delivery-service/internal/service"
+)
+
+// hijackWriter hands the server end of a pipe to the upgrader.
+type hijackWriter struct {
+	httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (w *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.conn, bufio.NewReadWriter(bufio.NewReader(w.conn), bufio.NewWriter(w.conn)), nil
+}
+
+// deadlineBlind ignores write deadlines, like a code path that forgot to set one.
+type deadlineBlind struct{ net.Conn }
+
+func (deadlineBlind) SetWriteDeadline(time.Time) error { return nil }
+
+// pumpDeliverer hands out one connector and records when it is unsubscribed.
+type pumpDeliverer struct {
+	service.Deliverer
+	conn registry.Connector
+
+	mu             sync.Mutex
+	unsubscribedAt time.Time
+}
+
+func (d *pumpDeliverer) Subscribe(context.Context, uuid.UUID, service.SubscribeOptions) (registry.Connector, error) {
+	return d.conn, nil
+}
+
+func (d *pumpDeliverer) Unsubscribe(uuid.UUID, uuid.UUID) {
+	d.mu.Lock()
+	d.unsubscribedAt = time.Now()
+	d.mu.Unlock()
+	d.conn.Close()
+}
+
+func (d *pumpDeliverer) Shape(conn registry.Connector, _ int64) (<-chan event.Eventer, func()) {
+	return conn.Recv(), func() {}
+}
+
+func (d *pumpDeliverer) Capabilities(int64) []string { return nil }
+
+func (d *pumpDeliverer) RecordDelivery(registry.Connector, event.Eventer, time.Duration, time.Duration) {
+}
+
+// TestStuckWrite wedges a peer behind a net.Pipe that stops reading after the
+// handshake, and checks detection latency, the close code the peer sees once it
+// reads again, and that the connector is released so the Cell stops buffering.
+func TestStuckWrite(t *testing.T) {
+	const timeout = 5 * time.Second
+	tests := []struct {
+		name        string
+		blind       bool // The server socket ignores write deadlines
+		wantLatency time.Duration
+		wantCode    int // 0: the socket is torn down without a close frame
+	}{
+		{name: "deadline breach", wantLatency: timeout, wantCode: CloseSlowConsumer},
+		{name: "watchdog", blind: true, wantLatency: timeout + watchdogGrace},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				ring, err := keyring.New(keyring.Snapshot{Active: "k1", Keys: map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("secret"))}}, slog.New(slog.DiscardHandler))
+				if err != nil {
+					t.Fatal(err)
+				}
+				userID := uuid.New()
+				d := &pumpDeliverer{conn: registry.NewConnector(context.Background(), userID, 8, registry.ConnectMetadata{})}
+				h := NewWSHandler(slog.New(slog.DiscardHandler), d, nil, service.NewSessionTokens(ring), nil,
+					WithWriteTimeout(timeout), WithPingInterval(0))
+
+				server, peer := net.Pipe()
+				defer peer.Close()
+				var serverConn net.Conn = server
+				if tt.blind {
+					serverConn = deadlineBlind{server}
+				}
+
+				req := httptest.NewRequest(http.MethodGet, "/ws?frame_version=1", nil)
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+				req.Header.Set("Sec-WebSocket-Version", "13")
+				req.Header.Set("Sec-WebSocket-Key", base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")))
+				req = req.WithContext(httpauth.WithIdentity(req.Context(), httpauth.Identity{Contact: &model.AuthContact{}, UserID: userID}))
+
+				served := make(chan struct{})
+				go func() {
+					defer close(served)
+					h.ServeHTTP(&hijackWriter{conn: serverConn}, req)
+				}()
+
+				// The peer reads the upgrade response and the welcome frame, then stops.
+				br := bufio.NewReader(peer)
+				resp, err := http.ReadResponse(br, req)
+				if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+					t.Fatalf("upgrade: %v, %v", resp, err)
+				}
+				if _, _, err := readFrame(br); err != nil {
+					t.Fatalf("welcome frame: %v", err)
+				}
+
+				start := time.Now()
+				if !d.conn.Send(event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil), time.Second) {
+					t.Fatal("event not queued")
+				}
+				synctest.Wait()
+
+				// Wedged until the deadline (or the watchdog) fires, then the peer reads again.
+				time.Sleep(tt.wantLatency)
+				synctest.Wait()
+				opcode, payload, err := readFrame(br)
+				switch {
+				case tt.wantCode == 0:
+					if err == nil {
+						t.Fatalf("read frame %d after the watchdog tore the socket down", opcode)
+					}
+				case err != nil:
+					t.Fatalf("close frame: %v", err)
+				case opcode != 8 || len(payload) < 2 || int(binary.BigEndian.Uint16(payload)) != tt.wantCode:
+					t.Fatalf("frame opcode %d payload %q, want close %d", opcode, payload, tt.wantCode)
+				}
+				<-served
+
+				d.mu.Lock()
+				released := d.unsubscribedAt
+				d.mu.Unlock()
+				if released.IsZero() {
+					t.Fatal("connector was never unsubscribed")
+				}
+				if got := released.Sub(start); got != tt.wantLatency {
+					t.Fatalf("stuck write released after %v, want %v", got, tt.wantLatency)
+				}
+			})
+		})
+	}
+}
+
+func TestClassifyWriteErr(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "watchdog abort", err: errWriteStuck, want: true},
+		{name: "deadline exceeded", err: os.ErrDeadlineExceeded, want: true},
+		{name: "wrapped net timeout", err: &net.OpError{Op: "write", Err: os.ErrDeadlineExceeded}, want: true},
+		{name: "peer hung up", err: io.ErrClosedPipe},
+		{name: "protocol error", err: websocket.ErrCloseSent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyWriteErr(tt.err); got != tt.want {
+				t.Fatalf("classifyWriteErr(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// readFrame reads one unmasked server frame with a short payload.
+func readFrame(r io.Reader) (opcode byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	n := int(hdr[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		return 0, nil, errors.New("frame too large for the test peer")
+	}
+	payload = make([]byte, n)
+	_, err = io.ReadFull(r, payload)
+	return hdr[0] & 0x0f, payload, err
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// Interface guard
+var _ registry.DeliveryObserver = (*AnalyticsSampler)(nil)
+
+// AnalyticsSink accepts sampled records for export. Export must not block.
+type AnalyticsSink interface {
+	Export(rec model.AnalyticsRecord)
+}
+
+// transportResolver is satisfied by DeliveryService.
+type transportResolver interface {
+	TransportOf(connID uuid.UUID) (Transport, bool)
+}
+
+// AnalyticsSampler exports a deterministic sample of delivered events.
+//
+// [STRATEGY]
+// The decision hashes the source message ID against the rate configured for
+// (kind, domain), so it is identical on every node and for every recipient leg;
+// it is additionally cached on the event (SamplingMark) so a rate reload mid-fan-out
+// cannot split a message. Rates are read from config per call, which makes them hot-reloadable.
+type AnalyticsSampler struct {
+	cfg        *config.Config
+	sink       AnalyticsSink
+	transports transportResolver
+}
+
+// NewAnalyticsSampler attaches the sampler to the Hub. Without a sink it is inert.
+func NewAnalyticsSampler(hub *registry.Hub, cfg *config.Config, deliverer Deliverer, sink AnalyticsSink) *AnalyticsSampler {
+	s := &AnalyticsSampler{cfg: cfg, sink: sink}
+	s.transports, _ = deliverer.(transportResolver)
+	if sink != nil {
+		hub.AddDeliveryObserver(s)
+	}
+	return s
+}
+
+// ObserveDelivery exports a record for sampled, successfully delivered legs.
+func (s *AnalyticsSampler) ObserveDelivery(ev event.Eventer, connID uuid.UUID, outcome registry.DeliveryOutcome) {
+	rates := s.cfg.Delivery.Analytics.Rates
+	// [ZERO_COST] Nothing configured means a single length check per delivery.
+	if len(rates) == 0 || outcome != registry.OutcomeDelivered {
+		return
+	}
+
+	sourceID, domainID, threadID := sourceOf(ev)
+	kind := ev.GetKind().String()
+
+	decide := func() bool {
+		return sampled(sourceID, rateFor(rates, kind, domainID))
+	}
+
+	var ok bool
+	if c, isCarrier := ev.(event.SamplingCarrier); isCarrier && c.GetSamplingMark() != nil {
+		ok = c.GetSamplingMark().Decide(decide)
+	} else {
+		ok = decide()
+	}
+	if !ok {
+		return
+	}
+
+	transport := "unknown"
+	if s.transports != nil {
+		if t, found := s.transports.TransportOf(connID); found {
+			transport = string(t)
+		}
+	}
+
+	now := time.Now()
+	rec := model.AnalyticsRecord{
+		Kind:       kind,
+		DomainID:   domainID,
+		ThreadID:   threadID,
+		SourceID:   sourceID,
+		Recipients: 1,
+		Transport:  transport,
+		At:         now.UnixMilli(),
+		Bytes:      payloadSize(ev),
+	}
+	if occurred := ev.GetOccurredAt(); occurred > 0 {
+		rec.LatencyMs = max(now.UnixMilli()-occurred, 0)
+	}
+	s.sink.Export(rec)
+}
+
+// sourceOf returns the identity all fan-out legs share, plus the tenant and thread.
+func sourceOf(ev event.Eventer) (sourceID string, domainID int64, threadID string) {
+	if msg, ok := ev.GetPayload().(*model.Message); ok && msg != nil {
+		return msg.ID.String(), msg.DomainID, msg.ThreadID.String()
+	}
+	return ev.GetID(), 0, ""
+}
+
+// rateFor prefers an exact (domain, kind) rate over the kind-wide one.
+func rateFor(rates []config.SamplingRate, kind string, domainID int64) float64 {
+	rate := 0.0
+	for _, r := range rates {
+		if r.Kind != kind {
+			continue
+		}
+		if r.DomainID == domainID {
+			return r.Rate
+		}
+		if r.DomainID == 0 {
+			rate = r.Rate
+		}
+	}
+	return rate
+}
+
+// sampled maps the source ID onto [0,1) with FNV-1a and compares it to the rate.
+func sampled(sourceID string, rate float64) bool {
+	switch {
+	case rate <= 0:
+		return false
+	case rate >= 1:
+		return true
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(sourceID))
+	// Top 53 bits give a uniform float in [0,1) without rounding bias.
+	return float64(h.Sum64()>>11)/(1<<53) < rate
+}
+
+// payloadSize reports the serialized size; only sampled legs pay for it.
+func payloadSize(ev event.Eventer) int {
+	b, err := json.Marshal(ev.GetPayload())
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
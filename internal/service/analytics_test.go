@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// chi2Critical is the 0.999 quantile of the chi-squared distribution with 1 degree
+// of freedom; a correct sampler exceeds it once in a thousand runs of a random input.
+// The inputs below are fixed, so the tests are deterministic.
+const chi2Critical = 10.83
+
+// recordingSink keeps every exported record.
+type recordingSink struct {
+	mu      sync.Mutex
+	records []model.AnalyticsRecord
+}
+
+func (s *recordingSink) Export(rec model.AnalyticsRecord) {
+	s.mu.Lock()
+	s.records = append(s.records, rec)
+	s.mu.Unlock()
+}
+
+// bySource counts exported legs per source message.
+func (s *recordingSink) bySource() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make(map[string]int)
+	for _, r := range s.records {
+		res[r.SourceID]++
+	}
+	return res
+}
+
+func analyticsConfig(rates ...config.SamplingRate) *config.Config {
+	cfg := &config.Config{}
+	cfg.Delivery.Analytics.Rates = rates
+	return cfg
+}
+
+// TestSampledRate checks rate accuracy (chi-squared over a large synthetic run) and
+// that the decision for one source ID never changes.
+func TestSampledRate(t *testing.T) {
+	const n = 200000
+	ids := make([]string, n)
+	for i := range ids {
+		// Deterministic, UUID-shaped IDs keep the run reproducible.
+		ids[i] = uuid.NewSHA1(uuid.NameSpaceOID, fmt.Appendf(nil, "msg-%d", i)).String()
+	}
+
+	for _, rate := range []float64{0.001, 0.01, 0.1, 0.5, 0.9} {
+		t.Run(fmt.Sprint(rate), func(t *testing.T) {
+			hits := 0
+			for _, id := range ids {
+				if sampled(id, rate) {
+					hits++
+				}
+			}
+			expHit, expMiss := n*rate, n*(1-rate)
+			dHit, dMiss := float64(hits)-expHit, float64(n-hits)-expMiss
+			if chi2 := dHit*dHit/expHit + dMiss*dMiss/expMiss; chi2 > chi2Critical {
+				t.Fatalf("sampled %d of %d at rate %v: chi2 = %.2f", hits, n, rate, chi2)
+			}
+
+			for _, id := range ids[:1000] {
+				if sampled(id, rate) != sampled(id, rate) {
+					t.Fatalf("decision for %s is not deterministic", id)
+				}
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		rate float64
+		want bool
+	}{{0, false}, {-1, false}, {1, true}, {2, true}} {
+		if got := sampled(ids[0], tt.rate); got != tt.want {
+			t.Fatalf("sampled at rate %v = %v, want %v", tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestRateFor(t *testing.T) {
+	rates := []config.SamplingRate{
+		{Kind: "MessageCreated", Rate: 0.01},
+		{Kind: "MessageCreated", DomainID: 7, Rate: 1},
+		{Kind: "CallEvent", DomainID: 7, Rate: 0.5},
+	}
+	tests := []struct {
+		name   string
+		kind   string
+		domain int64
+		want   float64
+	}{
+		{name: "kind-wide rate", kind: "MessageCreated", domain: 1, want: 0.01},
+		{name: "domain rate wins", kind: "MessageCreated", domain: 7, want: 1},
+		{name: "domain-only rate elsewhere", kind: "CallEvent", domain: 1, want: 0},
+		{name: "unconfigured kind", kind: "Typing", domain: 7, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateFor(rates, tt.kind, tt.domain); got != tt.want {
+				t.Fatalf("rateFor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestObserveDeliveryAllLegsOrNone fans each message out to many recipients and checks
+// that its legs are exported together or not at all, including across a rate reload
+// that lands mid-fan-out.
+func TestObserveDeliveryAllLegsOrNone(t *testing.T) {
+	const (
+		messages = 400
+		legs     = 25
+	)
+	kind := event.MessageCreated.String()
+	cfg := analyticsConfig(config.SamplingRate{Kind: kind, Rate: 0.5})
+	sink := &recordingSink{}
+	s := &AnalyticsSampler{cfg: cfg, sink: sink}
+
+	for i := range messages {
+		msg := &model.Message{ID: uuid.New(), ThreadID: uuid.New(), DomainID: 1}
+		tmpl := event.NewMessageV1Event(msg, uuid.Nil, model.Peer{}, model.Peer{})
+		for leg := range legs {
+			// [HOT_RELOAD] Flip the rate halfway through one message's fan-out.
+			if i == messages/2 && leg == legs/2 {
+				cfg.Delivery.Analytics.Rates = []config.SamplingRate{{Kind: kind, Rate: 1}}
+			}
+			s.ObserveDelivery(tmpl.Clone(uuid.New()), uuid.New(), registry.OutcomeDelivered)
+		}
+	}
+
+	got := sink.bySource()
+	for source, n := range got {
+		if n != legs {
+			t.Fatalf("message %s exported on %d of %d legs", source, n, legs)
+		}
+	}
+	// Before the reload about half the messages are sampled; after it, all of them.
+	if lo, hi := messages/2+messages/8, messages-messages/8; len(got) < lo || len(got) > hi {
+		t.Fatalf("%d of %d messages sampled, want between %d and %d", len(got), messages, lo, hi)
+	}
+}
+
+func TestObserveDeliverySkips(t *testing.T) {
+	kind := event.MessageCreated.String()
+	tests := []struct {
+		name    string
+		rates   []config.SamplingRate
+		outcome registry.DeliveryOutcome
+	}{
+		{name: "no rates configured", outcome: registry.OutcomeDelivered},
+		{name: "zero rate", rates: []config.SamplingRate{{Kind: kind, Rate: 0}}, outcome: registry.OutcomeDelivered},
+		{name: "undelivered leg", rates: []config.SamplingRate{{Kind: kind, Rate: 1}}, outcome: registry.OutcomeDropped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			s := &AnalyticsSampler{cfg: analyticsConfig(tt.rates...), sink: sink}
+			ev := event.NewMessageV1Event(&model.Message{ID: uuid.New()}, uuid.New(), model.Peer{}, model.Peer{})
+			s.ObserveDelivery(ev, uuid.New(), tt.outcome)
+			if len(sink.records) != 0 {
+				t.Fatalf("exported %d records, want none", len(sink.records))
+			}
+		})
+	}
+}
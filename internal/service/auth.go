@@ -50,5 +50,6 @@ func (s *AuthService) Inspect(ctx context.Context) (*model.AuthContact, error) {
 		Sub:       auth.Contact.Sub,
 		Iss:       auth.Contact.Iss,
 		Name:      auth.Contact.Name,
+		Scope:     auth.GetToken().GetScope(),
 	}, nil
 }
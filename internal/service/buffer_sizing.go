@@ -0,0 +1,101 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// Transport identifies the wire protocol a connection was opened over.
+type Transport string
+
+const (
+	TransportLP   Transport = "lp"
+	TransportWS   Transport = "ws"
+	TransportGRPC Transport = "grpc"
+	TransportSSE  Transport = "sse"
+)
+
+// SubscribeOptions describes the negotiated characteristics of a new connection.
+type SubscribeOptions struct {
+	Transport Transport
+	// Platform is the client-declared platform (e.g. "ios", "web"); optional.
+	Platform string
+	// Batching marks consumers that drain events in bulk and benefit from deeper buffers.
+	Batching bool
+	// BufferSize, when positive, overrides the derived size (still clamped by config).
+	BufferSize int
+	// DomainID is the tenant of the connecting user (0 if unknown).
+	DomainID int64
+	// Resume continues a previous stream from this cursor (zero value: fresh stream).
+	Resume event.Cursor
+	// LastEventID resumes from a raw event ID when no cursor is given.
+	// Deprecated: guarded by DeprecatedRawLastEventID; clients should send the cursor.
+	LastEventID string
+	// LastEventTS (Unix ms of the last event received) locates the position when the ID
+	// is absent or has aged out of the replay buffer.
+	LastEventTS int64
+	// Metadata describes the client for diagnostics (see Deliverer.ListConnections).
+	Metadata registry.ConnectMetadata
+	// WatchPresence lists contacts whose status changes this connection follows.
+	WatchPresence []uuid.UUID
+	// Kinds limits the session to these event kinds (empty: all).
+	Kinds []event.EventKind
+}
+
+// defaultBufferTable is used for transports missing from the configured table.
+// [RATIONALE] LP drains at most a handful of events per poll, WS/gRPC stream continuously,
+// and batching consumers absorb bursts in bulk.
+var defaultBufferTable = map[string]int{
+	"lp":         64,
+	"ws":         512,
+	"grpc":       1024,
+	"grpc_batch": 2048,
+}
+
+const fallbackBufferSize = 1024
+
+// DeriveBufferSize is a [PURE_FUNCTION] mapping transport characteristics to a buffer size.
+// Lookup order: explicit override -> "<transport>:<platform>" -> "<transport>" -> built-in default.
+func DeriveBufferSize(cfg config.BufferConfig, opts SubscribeOptions) int {
+	size := opts.BufferSize
+	if size <= 0 {
+		size = lookupBufferSize(cfg.Table, opts)
+	}
+
+	// [CLAMP] Config bounds apply to overrides too so one client cannot pin excessive memory.
+	if cfg.Min > 0 && size < cfg.Min {
+		size = cfg.Min
+	}
+	if cfg.Max > 0 && size > cfg.Max {
+		size = cfg.Max
+	}
+
+	return size
+}
+
+func lookupBufferSize(table map[string]int, opts SubscribeOptions) int {
+	key := string(opts.Transport)
+	if opts.Batching {
+		key += "_batch"
+	}
+
+	if opts.Platform != "" {
+		if size, ok := table[key+":"+strings.ToLower(opts.Platform)]; ok {
+			return size
+		}
+	}
+	if size, ok := table[key]; ok {
+		return size
+	}
+	if size, ok := defaultBufferTable[key]; ok {
+		return size
+	}
+	if size, ok := defaultBufferTable[string(opts.Transport)]; ok {
+		return size
+	}
+	return fallbackBufferSize
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestDeriveBufferSize(t *testing.T) {
+	table := map[string]int{"ws": 300, "ws:ios": 200, "grpc_batch": 3000}
+	tests := []struct {
+		name string
+		cfg  config.BufferConfig
+		opts SubscribeOptions
+		want int
+	}{
+		{name: "lp default", opts: SubscribeOptions{Transport: TransportLP}, want: 64},
+		{name: "ws default", opts: SubscribeOptions{Transport: TransportWS}, want: 512},
+		{name: "grpc default", opts: SubscribeOptions{Transport: TransportGRPC}, want: 1024},
+		{name: "batching grpc default", opts: SubscribeOptions{Transport: TransportGRPC, Batching: true}, want: 2048},
+		{name: "batching falls back to the transport", opts: SubscribeOptions{Transport: TransportWS, Batching: true}, want: 512},
+		{name: "unknown transport", opts: SubscribeOptions{Transport: "carrier-pigeon"}, want: fallbackBufferSize},
+		{name: "configured transport", cfg: config.BufferConfig{Table: table}, opts: SubscribeOptions{Transport: TransportWS}, want: 300},
+		{name: "configured platform, case-insensitive", cfg: config.BufferConfig{Table: table}, opts: SubscribeOptions{Transport: TransportWS, Platform: "iOS"}, want: 200},
+		{name: "unconfigured platform", cfg: config.BufferConfig{Table: table}, opts: SubscribeOptions{Transport: TransportWS, Platform: "web"}, want: 300},
+		{name: "configured batching", cfg: config.BufferConfig{Table: table}, opts: SubscribeOptions{Transport: TransportGRPC, Batching: true}, want: 3000},
+		{name: "explicit override", opts: SubscribeOptions{Transport: TransportLP, BufferSize: 100}, want: 100},
+		{name: "clamped to min", cfg: config.BufferConfig{Min: 128}, opts: SubscribeOptions{Transport: TransportLP}, want: 128},
+		{name: "clamped to max", cfg: config.BufferConfig{Max: 256}, opts: SubscribeOptions{Transport: TransportGRPC}, want: 256},
+		{name: "override clamped to max", cfg: config.BufferConfig{Max: 256}, opts: SubscribeOptions{Transport: TransportWS, BufferSize: 1 << 20}, want: 256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeriveBufferSize(tt.cfg, tt.opts); got != tt.want {
+				t.Fatalf("DeriveBufferSize = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestDeliveryService(t *testing.T, cfg *config.Config) (*DeliveryService, *registry.Hub) {
+	t.Helper()
+	hub := registry.NewHub()
+	t.Cleanup(hub.Shutdown)
+
+	lc := fxtest.NewLifecycle(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := NewDeliveryService(hub, cfg, NewResidencyPolicy(cfg), NewEventImporter(hub, cfg),
+		NewReconnectAdvisor(hub, cfg, lc), NewSlowDeliveryTracker(cfg, logger, lc))
+	lc.RequireStart()
+	t.Cleanup(lc.RequireStop)
+	return s, hub
+}
+
+// TestSubscribeBufferPerTransport checks each transport path's default buffer and
+// that the session's peak occupancy is recorded under its transport on teardown.
+func TestSubscribeBufferPerTransport(t *testing.T) {
+	testMetrics()
+	tests := []struct {
+		name     string
+		opts     SubscribeOptions
+		queued   int
+		wantSize int
+	}{
+		{name: "long poll", opts: SubscribeOptions{Transport: TransportLP}, queued: 3, wantSize: 64},
+		{name: "websocket", opts: SubscribeOptions{Transport: TransportWS}, queued: 5, wantSize: 512},
+		{name: "grpc", opts: SubscribeOptions{Transport: TransportGRPC}, queued: 1, wantSize: 1024},
+		{name: "batching grpc", opts: SubscribeOptions{Transport: TransportGRPC, Batching: true}, queued: 7, wantSize: 2048},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, hub := newTestDeliveryService(t, &config.Config{})
+
+			userID := uuid.New()
+			conn, err := s.Subscribe(context.Background(), userID, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cap(conn.Recv()); got != tt.wantSize {
+				t.Fatalf("buffer = %d, want %d", got, tt.wantSize)
+			}
+
+			for range tt.queued {
+				hub.Broadcast(newPing(userID))
+			}
+			waitDepth(t, conn, tt.queued)
+			s.Unsubscribe(userID, conn.GetID())
+
+			if got, ok := peakRecorded(t, tt.opts.Transport); !ok || got < int64(tt.queued) {
+				t.Fatalf("peak occupancy for %s = %d (recorded %v), want >= %d", tt.opts.Transport, got, ok, tt.queued)
+			}
+		})
+	}
+}
+
+// peakRecorded returns the largest peak occupancy recorded for the transport.
+func peakRecorded(t *testing.T, transport Transport) (int64, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := testMetrics().Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	want := attribute.String("transport", string(transport))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "im_delivery_connector_buffer_peak" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Histogram[int64]).DataPoints {
+				if v, ok := dp.Attributes.Value(want.Key); ok && v == want.Value {
+					if peak, ok := dp.Max.Value(); ok {
+						return peak, true
+					}
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func newPing(userID uuid.UUID) event.Eventer {
+	return event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil)
+}
+
+// waitDepth waits for the Cell to hand n events to the connector.
+func waitDepth(t *testing.T, conn registry.Connector, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(conn.Recv()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("buffer depth %d, want %d", len(conn.Recv()), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// testMetrics installs one SDK provider for the package: the global delegate forwards
+// package-level instruments only to the first provider ever set.
+var testMetrics = sync.OnceValue(func() *sdkmetric.ManualReader {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return reader
+})
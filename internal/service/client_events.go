@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+// ClientEventPublisher relays client-originated events (e.g. typing) to the bus.
+// Unlike PresencePublisher it targets the system exchange, where the producers of
+// those events publish, so existing consumers pick them up unchanged.
+type ClientEventPublisher interface {
+	PublishWithTimeout(ctx context.Context, ev event.Eventer, timeout time.Duration) error
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// ErrListConnectionsForbidden is returned when a contact asks for another user's sessions.
+var ErrListConnectionsForbidden = errors.New("listing another user's connections requires service scope")
+
+// ErrDisconnectForbidden is returned when a caller without service scope asks to kick sessions.
+var ErrDisconnectForbidden = errors.New("disconnecting sessions requires service scope")
+
+// ListConnections describes the user's connections attached to this node.
+// [AUTHORIZATION] Ordinary contacts may only list their own; support tooling needs
+// ServiceScope. The view is node-local: sessions on other nodes are not included.
+func (s *DeliveryService) ListConnections(_ context.Context, userID uuid.UUID, viewer DeliveryViewer) ([]registry.SessionInfo, error) {
+	if !viewer.ServiceScope && viewer.UserID != userID {
+		return nil, ErrListConnectionsForbidden
+	}
+	return s.hub.Sessions(userID), nil
+}
+
+// Disconnect force-closes one session (connID set) or every session of the user on
+// this node. It returns registry.ErrUserNotOnNode when the user holds none here.
+// [AUTHORIZATION] Operators only; a client ends its own session by hanging up.
+func (s *DeliveryService) Disconnect(_ context.Context, userID, connID uuid.UUID, reason string, viewer DeliveryViewer) (int, error) {
+	if !viewer.ServiceScope {
+		return 0, ErrDisconnectForbidden
+	}
+	if reason == "" {
+		reason = "disconnected_by_operator"
+	}
+	return s.hub.Disconnect(userID, connID, reason)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+func TestListConnections(t *testing.T) {
+	owner, stranger := uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		viewer  DeliveryViewer
+		want    int
+		wantErr error
+	}{
+		{name: "own sessions", viewer: DeliveryViewer{UserID: owner}, want: 2},
+		{name: "another user's sessions", viewer: DeliveryViewer{UserID: stranger}, wantErr: ErrListConnectionsForbidden},
+		{name: "service scope", viewer: DeliveryViewer{ServiceScope: true}, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := registry.NewHub()
+			t.Cleanup(hub.Shutdown)
+			s := &DeliveryService{hub: hub, cfg: &config.Config{}}
+
+			md := registry.ConnectMetadata{Platform: "ios", RemoteIP: "10.0.0.1"}
+			for range 2 {
+				hub.Register(registry.NewConnector(context.Background(), owner, 8, md))
+			}
+
+			got, err := s.ListConnections(context.Background(), owner, tt.viewer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("sessions: got %d, want %d", len(got), tt.want)
+			}
+			for _, s := range got {
+				if s.Metadata != md {
+					t.Fatalf("metadata: got %+v, want %+v", s.Metadata, md)
+				}
+			}
+		})
+	}
+}
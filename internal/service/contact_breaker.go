@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/webitel/im-delivery-service/config"
+	contactv1 "github.com/webitel/im-delivery-service/gen/go/contact/v1"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// contactBreakerState mirrors the contact breaker: 0 closed, 1 half-open, 2 open.
+var contactBreakerState, _ = meter.Int64Gauge(
+	"im_delivery_enrichment_contact_breaker_state",
+	metric.WithDescription("Contact enrichment circuit breaker state (0 closed, 1 half-open, 2 open)"),
+)
+
+// Defaults for a zero EnrichmentConfig.
+const (
+	defaultContactTimeout      = 800 * time.Millisecond
+	defaultBreakerMinRequests  = 20
+	defaultBreakerFailureRatio = 0.5
+	defaultBreakerWindow       = 10 * time.Second
+	defaultBreakerOpenTimeout  = 15 * time.Second
+	defaultBreakerHalfOpen     = 3
+)
+
+// newContactBreaker trips on the failure rate over a rolling window rather than on a
+// streak, so a degraded (not dead) contact service is also cut off.
+// [FAST_FAIL] While open, lookups return immediately and peers stay unenriched.
+func newContactBreaker(c config.BreakerConfig) *gobreaker.CircuitBreaker {
+	minRequests := c.MinRequests
+	if minRequests == 0 {
+		minRequests = defaultBreakerMinRequests
+	}
+	ratio := c.FailureRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = defaultBreakerFailureRatio
+	}
+	window := c.Window
+	if window <= 0 {
+		window = defaultBreakerWindow
+	}
+	openTimeout := c.OpenTimeout
+	if openTimeout <= 0 {
+		openTimeout = defaultBreakerOpenTimeout
+	}
+	halfOpen := c.HalfOpenRequests
+	if halfOpen == 0 {
+		halfOpen = defaultBreakerHalfOpen
+	}
+
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        "im-contact.SearchContact",
+		MaxRequests: halfOpen,
+		Interval:    window,
+		Timeout:     openTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.Requests >= minRequests &&
+				float64(counts.TotalFailures)/float64(counts.Requests) >= ratio
+		},
+		OnStateChange: func(_ string, _, to gobreaker.State) {
+			contactBreakerState.Record(context.Background(), int64(to))
+		},
+	})
+}
+
+// search runs one SearchContact under the per-call timeout and the breaker.
+func (e *ContactEnricher) search(ctx context.Context, req *contactv1.SearchContactRequest) (*contactv1.ContactList, error) {
+	res, err := e.breaker.Execute(func() (any, error) {
+		callCtx, cancel := context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+		return e.contacts.SearchContact(callCtx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*contactv1.ContactList), nil
+}
+
+// BreakerState reports the contact breaker state ("closed", "half-open", "open").
+func (e *ContactEnricher) BreakerState() string {
+	return e.breaker.State().String()
+}
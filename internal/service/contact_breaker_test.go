@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	contactv1 "github.com/webitel/im-delivery-service/gen/go/contact/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// switchableContacts answers every search with one contact, or fails while down.
+type switchableContacts struct {
+	down  bool
+	calls int
+}
+
+func (f *switchableContacts) SearchContact(_ context.Context, req *contactv1.SearchContactRequest) (*contactv1.ContactList, error) {
+	f.calls++
+	if f.down {
+		return nil, errors.New("contact service unavailable")
+	}
+	return &contactv1.ContactList{Contacts: []*contactv1.Contact{
+		{Id: req.GetIds()[0], Name: "Alice", Subject: "alice", DomainId: req.GetDomainId()},
+	}}, nil
+}
+
+// breakerStep is one lookup, after waiting, against a directory that is up or down.
+type breakerStep struct {
+	wait      time.Duration
+	down      bool
+	wantCall  bool   // The directory was reached rather than failed fast
+	wantState string // Breaker state after the lookup
+}
+
+func TestContactBreaker(t *testing.T) {
+	cfg := config.EnrichmentConfig{
+		ContactTimeout: time.Second,
+		Breaker: config.BreakerConfig{
+			MinRequests:      4,
+			FailureRatio:     0.5,
+			Window:           10 * time.Second,
+			OpenTimeout:      5 * time.Second,
+			HalfOpenRequests: 2,
+		},
+	}
+	fail := breakerStep{down: true, wantCall: true, wantState: "closed"}
+	ok := breakerStep{wantCall: true, wantState: "closed"}
+	tripping := breakerStep{down: true, wantCall: true, wantState: "open"}
+
+	tests := []struct {
+		name  string
+		steps []breakerStep
+	}{
+		{
+			name:  "below min requests stays closed",
+			steps: []breakerStep{fail, fail, fail},
+		},
+		{
+			name:  "failure ratio trips, not a streak",
+			steps: []breakerStep{fail, ok, ok, tripping},
+		},
+		{
+			name:  "below the ratio stays closed",
+			steps: []breakerStep{fail, ok, ok, ok, ok},
+		},
+		{
+			name: "open breaker fails fast",
+			steps: []breakerStep{
+				fail, fail, fail, tripping,
+				{down: false, wantCall: false, wantState: "open"},
+				{wait: 4 * time.Second, wantCall: false, wantState: "open"},
+			},
+		},
+		{
+			name: "half-open probes close it again",
+			steps: []breakerStep{
+				fail, fail, fail, tripping,
+				{wait: 6 * time.Second, wantCall: true, wantState: "half-open"},
+				{wantCall: true, wantState: "closed"},
+				ok,
+			},
+		},
+		{
+			name: "failed probe reopens it",
+			steps: []breakerStep{
+				fail, fail, fail, tripping,
+				{wait: 6 * time.Second, down: true, wantCall: true, wantState: "open"},
+				{wantCall: false, wantState: "open"},
+			},
+		},
+		{
+			name: "counts reset with the window",
+			steps: []breakerStep{
+				fail, fail, fail,
+				{wait: 11 * time.Second, down: true, wantCall: true, wantState: "closed"},
+				fail, fail,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				dir := &switchableContacts{}
+				e := NewContactEnricher(dir, cfg)
+				peer := model.Peer{ID: uuid.New(), Type: model.PeerUser}
+
+				for i, step := range tt.steps {
+					time.Sleep(step.wait)
+					dir.down = step.down
+					before := dir.calls
+
+					got, err := e.ResolvePeer(context.Background(), peer, 1)
+					if err != nil {
+						t.Fatalf("step %d: %v", i, err)
+					}
+					if called := dir.calls > before; called != step.wantCall {
+						t.Fatalf("step %d: directory called = %v, want %v", i, called, step.wantCall)
+					}
+					if enriched := got.IsEnriched(); enriched != (step.wantCall && !step.down) {
+						t.Fatalf("step %d: enriched = %v", i, enriched)
+					}
+					if state := e.BreakerState(); state != step.wantState {
+						t.Fatalf("step %d: breaker %s, want %s", i, state, step.wantState)
+					}
+				}
+			})
+		})
+	}
+}
@@ -2,42 +2,176 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
 	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/domain/watch"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // [DELIVERY_SERVICE] PRIMARY INTERFACE FOR TRANSPORT HANDLERS (gRPC/Websocket)
 type Deliverer interface {
-	Subscribe(ctx context.Context, userID uuid.UUID) (registry.Connector, error)
+	Subscribe(ctx context.Context, userID uuid.UUID, opts SubscribeOptions) (registry.Connector, error)
 	Unsubscribe(userID, connID uuid.UUID)
+	// [RESUME] Whether a Subscribe resuming from the cursor would replay without a gap.
+	CanResume(userID uuid.UUID, from event.Cursor) bool
+	// [EPHEMERAL_TOPICS] Temporary, connection-scoped delivery for arbitrary entity keys.
+	// Keys are scoped to the session's domain and authorized per entity.
+	SubscribeTopic(ctx context.Context, conn registry.Connector, domainID int64, key string, ttl time.Duration) error
+	UnsubscribeTopic(conn registry.Connector, domainID int64, key string)
+	// [DELIVERY_SHAPING] Stream to read from instead of conn.Recv(); stop must run before Unsubscribe.
+	Shape(conn registry.Connector, domainID int64) (<-chan event.Eventer, func())
+	Capabilities(domainID int64) []string
+	// [RECONNECT_GUIDANCE] Backoff hint for a server-initiated, retryable disconnect.
+	DisconnectHint(reason reconnect.Reason) reconnect.Hint
+	// [STAGE_TIMING] Reported by transports after each successful write.
+	RecordDelivery(conn registry.Connector, ev event.Eventer, marshal, write time.Duration)
+	// [DOMAIN_PAUSE] Distinct users of a tenant connected to this node.
+	UsersInDomain(domainID int64) []uuid.UUID
+	// [SUPPORT] Connections the user holds on this node; others' only with ServiceScope.
+	ListConnections(ctx context.Context, userID uuid.UUID, viewer DeliveryViewer) ([]registry.SessionInfo, error)
+	// [PRESENCE] Node-local reachability, for push-notification decisions.
+	IsOnline(userID uuid.UUID) Presence
+	CheckPresence(userIDs []uuid.UUID) ([]Presence, error)
+	// [SUPPORT] Force-close sessions; requires ServiceScope.
+	Disconnect(ctx context.Context, userID, connID uuid.UUID, reason string, viewer DeliveryViewer) (int, error)
+	// [SUPPORT] Inject a one-off event without the bus; requires ServiceScope.
+	PushEvent(ctx context.Context, req PushRequest, viewer DeliveryViewer) (PushOutcome, error)
+	// [GRACEFUL_DRAIN] Refuse new sessions and flush queued events ahead of Close.
+	Drain(deadline time.Time)
 	// [GRACEFUL_HUB_SHUTDOWN]
 	Close()
 }
 
 // [IMPLEMENTATION] PRIVATE TO ENFORCE INTERFACE USAGE
 type DeliveryService struct {
-	hub registry.Hubber
+	hub       registry.Hubber
+	cfg       *config.Config
+	shaper    *DeliveryShaper
+	residency *ResidencyPolicy
+	importer  *EventImporter
+	advisor   *reconnect.Advisor
+	slow      *SlowDeliveryTracker
+	watches   *watch.Registry
+	receipts  *DeliveryReceipts
+	topicAuth TopicAuthorizer
+	e2ee      bool
+
+	// [SESSION_TRACKING] connID -> trackedConn, used for per-transport telemetry on teardown.
+	sessions sync.Map
+}
+
+type trackedConn struct {
+	conn      registry.Connector
+	transport Transport
+	domainID  int64
+	stopWatch func() bool
 }
 
 // NewDeliveryService returns a production-ready instance of the service.
-func NewDeliveryService(hub registry.Hubber) *DeliveryService {
-	return &DeliveryService{
-		hub: hub,
+// Option defines a functional configuration type for the DeliveryService.
+type Option func(*DeliveryService)
+
+// WithE2EEncryptionEnabled advertises end-to-end encrypted delivery to clients.
+// Clients only send E2EE bodies to nodes advertising CapabilityE2EE.
+func WithE2EEncryptionEnabled(enabled bool) Option {
+	return func(s *DeliveryService) {
+		s.e2ee = enabled
 	}
 }
 
+// WithPresenceWatches records each session's WatchPresence list in r.
+func WithPresenceWatches(r *watch.Registry) Option {
+	return func(s *DeliveryService) {
+		s.watches = r
+	}
+}
+
+func NewDeliveryService(hub registry.Hubber, cfg *config.Config, residency *ResidencyPolicy, importer *EventImporter, advisor *reconnect.Advisor, slow *SlowDeliveryTracker, opts ...Option) *DeliveryService {
+	s := &DeliveryService{
+		hub:       hub,
+		cfg:       cfg,
+		shaper:    NewDeliveryShaper(cfg),
+		residency: residency,
+		importer:  importer,
+		advisor:   advisor,
+		slow:      slow,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// DisconnectHint delegates to the reconnect advisor.
+func (s *DeliveryService) DisconnectHint(reason reconnect.Reason) reconnect.Hint {
+	return s.advisor.Advise(reason)
+}
+
 // [SUBSCRIBE] HANDLES CONNECTION LIFECYCLE INITIATION
-func (s *DeliveryService) Subscribe(ctx context.Context, userID uuid.UUID) (registry.Connector, error) {
-	// [STRATEGY] We can adjust buffer size based on Platform or User Priority from meta
-	// In the future, StreamRequest settings can be passed here as well.
-	const defaultBufferSize = 1024
+func (s *DeliveryService) Subscribe(ctx context.Context, userID uuid.UUID, opts SubscribeOptions) (registry.Connector, error) {
+	// [GRACEFUL_DRAIN] A session opened now would be closed within seconds; send it elsewhere.
+	if s.hub.Draining() {
+		return nil, &DrainingError{Hint: s.advisor.Advise(reconnect.ReasonShutdown)}
+	}
 
-	// 1. Create a connector (Internal logic uses sync.Pool for zero-allocation)
-	conn := registry.NewConnector(ctx, userID, defaultBufferSize)
+	// [DATA_RESIDENCY] Restricted tenants may only hold sessions in allowed regions.
+	if err := s.residency.Check(opts.DomainID); err != nil {
+		return nil, err
+	}
+
+	// [DEPRECATION] Checked before any state is created so a rejection leaves nothing behind.
+	if opts.Resume.IsZero() && opts.LastEventID != "" {
+		if err := DeprecatedRawLastEventID.Use(ctx, deprecation.Scope{DomainID: opts.DomainID}); err != nil {
+			return nil, err
+		}
+	}
+
+	// [RIGHT_SIZING] Buffer depth follows the transport's drain characteristics.
+	// The config is read on every call so hot-reloaded tables apply to new sessions.
+	bufferSize := DeriveBufferSize(s.cfg.Delivery.Buffer, opts)
+
+	// 1. Create a connector; its buffer comes from the size-class channel pool when
+	// bufferSize is a pooled size class (see registry.acquireChan), otherwise it is allocated.
+	md := opts.Metadata
+	if md.Platform == "" {
+		md.Platform = opts.Platform
+	}
+	conn := registry.NewConnector(ctx, userID, bufferSize, md, registry.WithKindFilter(event.NewKindFilter(opts.Kinds...)))
+	connID := conn.GetID()
+
+	// [CLEANUP_WATCHDOG] Belt-and-braces for a handler whose deferred Unsubscribe never runs:
+	// detach as soon as the stream/request context ends. Release stays with Unsubscribe,
+	// since a live reader may still hold the connector.
+	stopWatch := context.AfterFunc(ctx, func() { s.hub.Unregister(userID, connID) })
+	s.sessions.Store(connID, trackedConn{conn: conn, transport: opts.Transport, domainID: opts.DomainID, stopWatch: stopWatch})
 
 	// 2. Attach to the sharded dispatcher
-	s.hub.Register(conn)
+	switch {
+	case !opts.Resume.IsZero():
+		// [RESUME] Missed events are enqueued ahead of the live feed.
+		s.hub.RegisterResume(conn, opts.Resume)
+	case opts.LastEventID != "" || opts.LastEventTS > 0:
+		s.hub.RegisterResumeID(conn, opts.LastEventID, opts.LastEventTS)
+	default:
+		s.hub.Register(conn)
+	}
+
+	// [PRESENCE_WATCH] Evicted in Unsubscribe together with the connector.
+	if s.watches != nil && len(opts.WatchPresence) > 0 {
+		s.watches.Watch(userID, connID, opts.WatchPresence)
+	}
+
+	// [MIGRATION] History imported while the user was offline follows the attach.
+	s.importer.Flush(userID)
 
 	// 3. Return the connector for the gRPC handler to start streaming
 	return conn, nil
@@ -45,9 +179,115 @@ func (s *DeliveryService) Subscribe(ctx context.Context, userID uuid.UUID) (regi
 
 // [UNSUBSCRIBE] TRIGGERS CLEANUP AND OBJECT RECYCLING
 func (s *DeliveryService) Unsubscribe(userID, connID uuid.UUID) {
-	// Hub.Unregister will call conn.Close(), which resets the object
-	// and puts it back into model.connectPool.
+	// Detach first so the Cell stops writing into the connector.
 	s.hub.Unregister(userID, connID)
+	if s.watches != nil {
+		s.watches.Unwatch(connID)
+	}
+
+	if v, ok := s.sessions.LoadAndDelete(connID); ok {
+		tc := v.(trackedConn)
+		tc.stopWatch()
+		bufferPeakOccupancy.Record(context.Background(), int64(tc.conn.PeakDepth()),
+			metric.WithAttributes(attribute.String("transport", string(tc.transport))),
+		)
+
+		// [RECYCLE] Unsubscribe is the transport's last word on the connector:
+		// its read loop has exited, so the buffers can go back to the pools.
+		tc.conn.Release()
+	}
+}
+
+// CanResume reports whether a resume from the cursor would replay without a gap.
+func (s *DeliveryService) CanResume(userID uuid.UUID, from event.Cursor) bool {
+	return s.hub.CanResume(userID, from)
+}
+
+// RecordDelivery feeds the slow-delivery tracker and, when enabled, the delivery receipts.
+func (s *DeliveryService) RecordDelivery(conn registry.Connector, ev event.Eventer, marshal, write time.Duration) {
+	var tc trackedConn
+	if v, ok := s.sessions.Load(conn.GetID()); ok {
+		tc = v.(trackedConn)
+	}
+	s.slow.Observe(ev, tc.transport, marshal, write, conn.PeakDepth())
+	s.receipts.Record(conn, ev, tc.transport, tc.domainID)
+}
+
+// UsersInDomain lists the distinct users holding a session of the domain on this node.
+func (s *DeliveryService) UsersInDomain(domainID int64) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{})
+	s.sessions.Range(func(_, v any) bool {
+		if tc := v.(trackedConn); tc.domainID == domainID {
+			seen[tc.conn.GetUserID()] = struct{}{}
+		}
+		return true
+	})
+
+	res := make([]uuid.UUID, 0, len(seen))
+	for id := range seen {
+		res = append(res, id)
+	}
+	return res
+}
+
+// TransportOf reports which wire protocol a live connection uses.
+func (s *DeliveryService) TransportOf(connID uuid.UUID) (Transport, bool) {
+	v, ok := s.sessions.Load(connID)
+	if !ok {
+		return "", false
+	}
+	return v.(trackedConn).transport, true
+}
+
+// DomainOf reports the tenant a live connection subscribed under.
+func (s *DeliveryService) DomainOf(connID uuid.UUID) (int64, bool) {
+	v, ok := s.sessions.Load(connID)
+	if !ok {
+		return 0, false
+	}
+	return v.(trackedConn).domainID, true
+}
+
+// [SUBSCRIBE_TOPIC] BINDS A LIVE CONNECTION TO AN EPHEMERAL TOPIC KEY
+// [TENANT_ISOLATION] The key must name the session's domain and an entity the user may follow;
+// the Hub indexes it under the domain, like BindTopic looks it up.
+func (s *DeliveryService) SubscribeTopic(ctx context.Context, conn registry.Connector, domainID int64, key string, ttl time.Duration) error {
+	keyDomain, err := TopicDomain(key)
+	if err != nil {
+		return err
+	}
+	if keyDomain != domainID {
+		return ErrTopicForeignDomain
+	}
+	if s.topicAuth == nil {
+		return ErrTopicForbidden
+	}
+	if err := s.topicAuth.AuthorizeTopic(ctx, conn.GetUserID(), domainID, key); err != nil {
+		return fmt.Errorf("%w: %w", ErrTopicForbidden, err)
+	}
+	return s.hub.SubscribeTopic(conn, registry.DomainTopicKey(domainID, key), ttl)
+}
+
+// [UNSUBSCRIBE_TOPIC] RELEASES AN EPHEMERAL TOPIC KEY BEFORE ITS TTL ELAPSES
+func (s *DeliveryService) UnsubscribeTopic(conn registry.Connector, domainID int64, key string) {
+	s.hub.UnsubscribeTopic(conn.GetID(), registry.DomainTopicKey(domainID, key))
+}
+
+// [SHAPE] WRAPS THE CONNECTOR STREAM WITH STAGING-ONLY DELAY/JITTER RULES
+func (s *DeliveryService) Shape(conn registry.Connector, domainID int64) (<-chan event.Eventer, func()) {
+	return s.shaper.Shape(conn, domainID)
+}
+
+// [CAPABILITIES] FEATURES ADVERTISED TO THE CLIENT IN THE CONNECTED HANDSHAKE
+func (s *DeliveryService) Capabilities(domainID int64) []string {
+	var caps []string
+	if len(s.shaper.rulesFor(domainID)) > 0 {
+		caps = append(caps, CapabilityDeliveryShaping)
+	}
+	if s.e2ee {
+		caps = append(caps, CapabilityE2EE)
+	}
+	return caps
 }
 
 func (s *DeliveryService) Close() {
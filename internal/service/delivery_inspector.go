@@ -0,0 +1,213 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// Interface guard
+var _ registry.DeliveryObserver = (*DeliveryInspector)(nil)
+
+const (
+	inspectorMaxMessages = 10000 // Messages remembered per node
+	inspectorMaxSteps    = 64    // Steps kept per message; later steps are counted, not stored
+)
+
+// ErrDeliveryStatusNotFound is returned when this node has no visible record of the message.
+var ErrDeliveryStatusNotFound = errors.New("delivery status not found")
+
+// StalenessNote is attached to every status so callers do not read it as authoritative.
+const StalenessNote = "node-local, in-memory view; bounded and lost on restart; absence of a step does not prove non-delivery"
+
+// ClusterStalenessNote replaces StalenessNote on merged answers.
+const ClusterStalenessNote = "merged in-memory views of the answering nodes; bounded and lost on restart; unreachable nodes contribute nothing; absence of a step does not prove non-delivery"
+
+// DeliveryViewer identifies who is asking.
+// [AUTHORIZATION] Recipients see only their own leg; ServiceScope (e.g. the sender's
+// backend) sees every leg of the message.
+type DeliveryViewer struct {
+	UserID       uuid.UUID
+	ServiceScope bool
+}
+
+// DeliveryStep is one observed outcome. It never carries message content.
+type DeliveryStep struct {
+	UserID  uuid.UUID `json:"user_id"`
+	ConnID  uuid.UUID `json:"conn_id,omitempty"` // uuid.Nil for mailbox-level steps
+	EventID string    `json:"event_id"`
+	Kind    string    `json:"kind"`
+	Outcome string    `json:"outcome"`
+	At      time.Time `json:"at"`
+	NodeID  string    `json:"node_id"`
+}
+
+// DeliveryStatus is the aggregated timeline returned to clients.
+type DeliveryStatus struct {
+	MessageID  uuid.UUID      `json:"message_id"`
+	Timeline   []DeliveryStep `json:"timeline"`
+	Truncated  bool           `json:"truncated,omitempty"`
+	Staleness  string         `json:"staleness"`
+	ObservedAt time.Time      `json:"observed_at"`
+
+	// [CLUSTER] Nodes that answered, and those that could not be asked.
+	Nodes       []string `json:"nodes"`
+	Unreachable []string `json:"unreachable,omitempty"`
+}
+
+// PeerDeliveryStatus is another node's answer to a cluster query.
+type PeerDeliveryStatus struct {
+	NodeID string
+	Status *DeliveryStatus // nil when the node holds nothing visible to the viewer
+	Err    error           // The node could not be asked
+}
+
+// ClusterQuerier asks every other delivery node for its view of a message.
+// [NODE_QUERY] Implementations forward the caller's credentials, so each node
+// applies the same viewer filter as the serving one.
+type ClusterQuerier interface {
+	QueryDeliveryStatus(ctx context.Context, messageID uuid.UUID) ([]PeerDeliveryStatus, error)
+}
+
+// UnknownPeers marks Unreachable when the node list itself could not be read.
+const UnknownPeers = "*"
+
+type messageTrace struct {
+	mu        sync.Mutex
+	steps     []DeliveryStep
+	truncated bool
+}
+
+// DeliveryInspector answers "what happened to message X" from the Hub's delivery outcomes.
+//
+// [STRATEGY]
+// It is installed as the Hub's DeliveryObserver and keeps a bounded, per-message
+// timeline keyed by message ID. Only identifiers, kinds, timestamps and outcomes are kept.
+type DeliveryInspector struct {
+	traces *lru.Cache[uuid.UUID, *messageTrace]
+	nodeID string
+	peers  ClusterQuerier // nil: cluster queries answer with this node only
+}
+
+// NewDeliveryInspector creates the inspector and attaches it to the Hub.
+func NewDeliveryInspector(hub *registry.Hub, cfg *config.Config, peers ClusterQuerier) *DeliveryInspector {
+	traces, _ := lru.New[uuid.UUID, *messageTrace](inspectorMaxMessages)
+	i := &DeliveryInspector{traces: traces, nodeID: cfg.Service.ID, peers: peers}
+	hub.AddDeliveryObserver(i)
+	return i
+}
+
+// ObserveDelivery records a step for message-bearing events; other events are ignored.
+func (i *DeliveryInspector) ObserveDelivery(ev event.Eventer, connID uuid.UUID, outcome registry.DeliveryOutcome) {
+	msg, ok := ev.GetPayload().(*model.Message)
+	if !ok || msg == nil {
+		return
+	}
+
+	trace, ok := i.traces.Get(msg.ID)
+	if !ok {
+		// [RACE_TOLERANT] A concurrent insert may win; the loser's step lands in the winner.
+		trace = &messageTrace{}
+		if prev, found, _ := i.traces.PeekOrAdd(msg.ID, trace); found {
+			trace = prev
+		}
+	}
+
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+
+	if len(trace.steps) >= inspectorMaxSteps {
+		trace.truncated = true
+		return
+	}
+	trace.steps = append(trace.steps, DeliveryStep{
+		UserID:  ev.GetUserID(),
+		ConnID:  connID,
+		EventID: ev.GetID(),
+		Kind:    ev.GetKind().String(),
+		Outcome: outcome.String(),
+		At:      time.Now(),
+		NodeID:  i.nodeID,
+	})
+}
+
+// GetDeliveryStatus returns the timeline of a message visible to the viewer.
+func (i *DeliveryInspector) GetDeliveryStatus(_ context.Context, messageID uuid.UUID, viewer DeliveryViewer) (*DeliveryStatus, error) {
+	trace, ok := i.traces.Peek(messageID)
+	if !ok {
+		return nil, ErrDeliveryStatusNotFound
+	}
+
+	trace.mu.Lock()
+	steps := make([]DeliveryStep, 0, len(trace.steps))
+	for _, s := range trace.steps {
+		if viewer.ServiceScope || s.UserID == viewer.UserID {
+			steps = append(steps, s)
+		}
+	}
+	truncated := trace.truncated
+	trace.mu.Unlock()
+
+	// [AUTHORIZATION] Another user's leg is indistinguishable from an unknown message.
+	if len(steps) == 0 {
+		return nil, ErrDeliveryStatusNotFound
+	}
+
+	return &DeliveryStatus{
+		MessageID:  messageID,
+		Timeline:   steps,
+		Truncated:  truncated,
+		Staleness:  StalenessNote,
+		ObservedAt: time.Now(),
+		Nodes:      []string{i.nodeID},
+	}, nil
+}
+
+// GetClusterDeliveryStatus merges this node's timeline with every other node's.
+// It fails with ErrDeliveryStatusNotFound only when no answering node knows the message.
+func (i *DeliveryInspector) GetClusterDeliveryStatus(ctx context.Context, messageID uuid.UUID, viewer DeliveryViewer) (*DeliveryStatus, error) {
+	res := &DeliveryStatus{
+		MessageID: messageID,
+		Staleness: ClusterStalenessNote,
+		Nodes:     []string{i.nodeID},
+	}
+	if local, err := i.GetDeliveryStatus(ctx, messageID, viewer); err == nil {
+		res.Timeline = local.Timeline
+		res.Truncated = local.Truncated
+	}
+
+	if i.peers != nil {
+		peers, err := i.peers.QueryDeliveryStatus(ctx, messageID)
+		if err != nil {
+			res.Unreachable = append(res.Unreachable, UnknownPeers)
+		}
+		for _, p := range peers {
+			if p.Err != nil {
+				res.Unreachable = append(res.Unreachable, p.NodeID)
+				continue
+			}
+			res.Nodes = append(res.Nodes, p.NodeID)
+			if p.Status != nil {
+				res.Timeline = append(res.Timeline, p.Status.Timeline...)
+				res.Truncated = res.Truncated || p.Status.Truncated
+			}
+		}
+	}
+
+	if len(res.Timeline) == 0 {
+		return nil, ErrDeliveryStatusNotFound
+	}
+	slices.SortStableFunc(res.Timeline, func(a, b DeliveryStep) int { return cmp.Compare(a.At.UnixNano(), b.At.UnixNano()) })
+	res.ObservedAt = time.Now()
+	return res, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+type fakeClusterQuerier struct {
+	peers []PeerDeliveryStatus
+	err   error
+}
+
+func (f fakeClusterQuerier) QueryDeliveryStatus(context.Context, uuid.UUID) ([]PeerDeliveryStatus, error) {
+	return f.peers, f.err
+}
+
+func TestGetDeliveryStatus(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	type step struct {
+		user    uuid.UUID
+		outcome registry.DeliveryOutcome
+	}
+	tests := []struct {
+		name     string
+		steps    []step
+		viewer   DeliveryViewer
+		outcomes []string
+		wantErr  error
+	}{
+		{
+			name:     "delivered",
+			steps:    []step{{alice, registry.OutcomeQueued}, {alice, registry.OutcomeDelivered}},
+			viewer:   DeliveryViewer{UserID: alice},
+			outcomes: []string{"Queued", "Delivered"},
+		},
+		{
+			name:     "dropped",
+			steps:    []step{{alice, registry.OutcomeQueued}, {alice, registry.OutcomeDropped}},
+			viewer:   DeliveryViewer{UserID: alice},
+			outcomes: []string{"Queued", "Dropped"},
+		},
+		{
+			name:     "still queued",
+			steps:    []step{{alice, registry.OutcomeQueued}},
+			viewer:   DeliveryViewer{UserID: alice},
+			outcomes: []string{"Queued"},
+		},
+		{
+			name:    "unknown message",
+			viewer:  DeliveryViewer{UserID: alice},
+			wantErr: ErrDeliveryStatusNotFound,
+		},
+		{
+			name:     "recipient sees only their leg",
+			steps:    []step{{alice, registry.OutcomeDelivered}, {bob, registry.OutcomeDropped}},
+			viewer:   DeliveryViewer{UserID: bob},
+			outcomes: []string{"Dropped"},
+		},
+		{
+			name:    "another user's leg looks unknown",
+			steps:   []step{{alice, registry.OutcomeDelivered}},
+			viewer:  DeliveryViewer{UserID: bob},
+			wantErr: ErrDeliveryStatusNotFound,
+		},
+		{
+			name:     "service scope sees every leg",
+			steps:    []step{{alice, registry.OutcomeDelivered}, {bob, registry.OutcomeDropped}},
+			viewer:   DeliveryViewer{ServiceScope: true},
+			outcomes: []string{"Delivered", "Dropped"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := registry.NewHub()
+			t.Cleanup(hub.Shutdown)
+			cfg := &config.Config{}
+			cfg.Service.ID = "node-a"
+			inspector := NewDeliveryInspector(hub, cfg, nil)
+
+			msg := &model.Message{ID: uuid.New()}
+			for _, s := range tt.steps {
+				inspector.ObserveDelivery(event.NewMessageV1Event(msg, s.user, model.Peer{}, model.Peer{}), uuid.New(), s.outcome)
+			}
+
+			got, err := inspector.GetDeliveryStatus(context.Background(), msg.ID, tt.viewer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if outcomes := stepOutcomes(got.Timeline); !slices.Equal(outcomes, tt.outcomes) {
+				t.Fatalf("outcomes: got %v, want %v", outcomes, tt.outcomes)
+			}
+			if !slices.Equal(got.Nodes, []string{"node-a"}) {
+				t.Fatalf("nodes: got %v", got.Nodes)
+			}
+		})
+	}
+}
+
+func TestGetClusterDeliveryStatus(t *testing.T) {
+	alice := uuid.New()
+	remote := func(node, outcome string, at time.Time) *DeliveryStatus {
+		return &DeliveryStatus{Timeline: []DeliveryStep{{UserID: alice, Outcome: outcome, At: at, NodeID: node}}}
+	}
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name        string
+		local       bool
+		peers       fakeClusterQuerier
+		outcomes    []string
+		nodes       []string
+		unreachable []string
+		wantErr     error
+	}{
+		{
+			name:     "merged oldest first",
+			local:    true,
+			peers:    fakeClusterQuerier{peers: []PeerDeliveryStatus{{NodeID: "node-b", Status: remote("node-b", "Queued", past)}}},
+			outcomes: []string{"Queued", "Delivered"},
+			nodes:    []string{"node-a", "node-b"},
+		},
+		{
+			name:     "only a peer knows the message",
+			peers:    fakeClusterQuerier{peers: []PeerDeliveryStatus{{NodeID: "node-b", Status: remote("node-b", "Dropped", past)}}},
+			outcomes: []string{"Dropped"},
+			nodes:    []string{"node-a", "node-b"},
+		},
+		{
+			name:     "peer answers without a record",
+			local:    true,
+			peers:    fakeClusterQuerier{peers: []PeerDeliveryStatus{{NodeID: "node-b"}}},
+			outcomes: []string{"Delivered"},
+			nodes:    []string{"node-a", "node-b"},
+		},
+		{
+			name:        "unreachable peer",
+			local:       true,
+			peers:       fakeClusterQuerier{peers: []PeerDeliveryStatus{{NodeID: "node-b", Err: errors.New("timeout")}}},
+			outcomes:    []string{"Delivered"},
+			nodes:       []string{"node-a"},
+			unreachable: []string{"node-b"},
+		},
+		{
+			name:        "node list unavailable",
+			local:       true,
+			peers:       fakeClusterQuerier{err: errors.New("discovery down")},
+			outcomes:    []string{"Delivered"},
+			nodes:       []string{"node-a"},
+			unreachable: []string{UnknownPeers},
+		},
+		{
+			name:    "no node knows the message",
+			peers:   fakeClusterQuerier{peers: []PeerDeliveryStatus{{NodeID: "node-b"}}},
+			wantErr: ErrDeliveryStatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := registry.NewHub()
+			t.Cleanup(hub.Shutdown)
+			cfg := &config.Config{}
+			cfg.Service.ID = "node-a"
+			inspector := NewDeliveryInspector(hub, cfg, tt.peers)
+
+			msg := &model.Message{ID: uuid.New()}
+			if tt.local {
+				inspector.ObserveDelivery(event.NewMessageV1Event(msg, alice, model.Peer{}, model.Peer{}), uuid.New(), registry.OutcomeDelivered)
+			}
+
+			got, err := inspector.GetClusterDeliveryStatus(context.Background(), msg.ID, DeliveryViewer{UserID: alice})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if outcomes := stepOutcomes(got.Timeline); !slices.Equal(outcomes, tt.outcomes) {
+				t.Fatalf("outcomes: got %v, want %v", outcomes, tt.outcomes)
+			}
+			if !slices.Equal(got.Nodes, tt.nodes) {
+				t.Fatalf("nodes: got %v, want %v", got.Nodes, tt.nodes)
+			}
+			if !slices.Equal(got.Unreachable, tt.unreachable) {
+				t.Fatalf("unreachable: got %v, want %v", got.Unreachable, tt.unreachable)
+			}
+			if got.Staleness != ClusterStalenessNote {
+				t.Fatalf("staleness: got %q", got.Staleness)
+			}
+		})
+	}
+}
+
+func stepOutcomes(steps []DeliveryStep) []string {
+	res := make([]string, len(steps))
+	for i, s := range steps {
+		res[i] = s.Outcome
+	}
+	return res
+}
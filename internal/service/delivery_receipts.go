@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+)
+
+var receiptsPublished, _ = meter.Int64Counter(
+	"im_delivery_receipts_published_total",
+	metric.WithDescription("Delivery receipts published to the bus, by transport"),
+)
+
+var receiptsDropped, _ = meter.Int64Counter(
+	"im_delivery_receipts_dropped_total",
+	metric.WithDescription("Delivery receipts not published because too many were in flight or the broker failed"),
+)
+
+// maxInflightReceipts bounds concurrent receipt publishes; beyond it receipts are dropped
+// rather than stalling the transport that reported the write.
+const maxInflightReceipts = 256
+
+// ReceiptPublisher is the slice of the bus dispatcher receipts need.
+type ReceiptPublisher interface {
+	PublishWithTimeout(ctx context.Context, ev event.Eventer, timeout time.Duration) error
+}
+
+// deliveryReceipt is the body published on im_delivery.v1.{domain_id}.message.delivered.
+type deliveryReceipt struct {
+	MessageID    string `json:"message_id"`
+	EventID      string `json:"event_id"`
+	Kind         string `json:"kind"`
+	UserID       string `json:"user_id"`
+	ConnectionID string `json:"connection_id"`
+	Transport    string `json:"transport"`
+	DomainID     int64  `json:"domain_id"`
+	At           int64  `json:"at"` // Unix ms of the successful write
+}
+
+// DeliveryReceipts acknowledges on the bus that an event was written to a client's wire.
+//
+// [STRATEGY]
+// Transports report each successful write through Deliverer.RecordDelivery; only the
+// kinds listed in delivery.receipts.kinds (read per call, so hot-reloadable) produce a
+// receipt. Publishing happens off the transport's path with bounded concurrency.
+type DeliveryReceipts struct {
+	cfg    *config.Config
+	pub    ReceiptPublisher
+	logger *slog.Logger
+	slots  chan struct{}
+
+	mu       sync.Mutex
+	stopped  bool
+	inflight sync.WaitGroup
+}
+
+// NewDeliveryReceipts builds the publisher. Without a bus dispatcher it stays inert.
+func NewDeliveryReceipts(cfg *config.Config, logger *slog.Logger, lc fx.Lifecycle, pub ReceiptPublisher) *DeliveryReceipts {
+	r := &DeliveryReceipts{
+		cfg:    cfg,
+		pub:    pub,
+		logger: logger,
+		slots:  make(chan struct{}, maxInflightReceipts),
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			r.stop()
+			return nil
+		},
+	})
+	return r
+}
+
+// WithDeliveryReceipts publishes a receipt for every qualifying write reported to the service.
+func WithDeliveryReceipts(r *DeliveryReceipts) Option {
+	return func(s *DeliveryService) {
+		s.receipts = r
+	}
+}
+
+// Record publishes a receipt for ev, written to conn, when its kind is selected.
+func (r *DeliveryReceipts) Record(conn registry.Connector, ev event.Eventer, transport Transport, domainID int64) {
+	if r == nil || r.pub == nil || !wantsReceipt(r.cfg.Delivery.Receipts.Kinds, ev.GetKind()) {
+		return
+	}
+	if !receiptable(ev) {
+		return
+	}
+
+	userID := conn.GetUserID()
+	sourceID, msgDomain, _ := sourceOf(ev)
+	if msgDomain != 0 {
+		domainID = msgDomain
+	}
+	now := time.Now().UnixMilli()
+	body, err := json.Marshal(deliveryReceipt{
+		MessageID:    sourceID,
+		EventID:      ev.GetID(),
+		Kind:         ev.GetKind().String(),
+		UserID:       userID.String(),
+		ConnectionID: conn.GetID().String(),
+		Transport:    string(transport),
+		DomainID:     domainID,
+		At:           now,
+	})
+	if err != nil {
+		return
+	}
+	routingKey := fmt.Sprintf("im_delivery.v1.%d.message.delivered", domainID)
+	receipt := event.NewOutboundEvent(event.MessageDelivered, userID, routingKey, body)
+	receipt.Exchange = r.cfg.Delivery.Receipts.Exchange
+
+	attrs := metric.WithAttributes(attribute.String("transport", string(transport)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
+	// [BACKPRESSURE] A slow broker must not turn receipts into unbounded goroutines.
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		receiptsDropped.Add(context.Background(), 1, attrs)
+		return
+	}
+	r.inflight.Go(func() {
+		defer func() { <-r.slots }()
+		if err := r.pub.PublishWithTimeout(context.Background(), receipt, 0); err != nil {
+			receiptsDropped.Add(context.Background(), 1, attrs)
+			r.logger.Debug("RECEIPT_PUBLISH_FAILED",
+				slog.String("event_id", receipt.GetID()),
+				slog.String("user_id", userID.String()),
+				slog.Any("err", err),
+			)
+			return
+		}
+		receiptsPublished.Add(context.Background(), 1, attrs)
+	})
+}
+
+// stop refuses new receipts and waits for the in-flight ones.
+func (r *DeliveryReceipts) stop() {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+	r.inflight.Wait()
+}
+
+// receiptable is false for ephemeral topic traffic and for imported history, whose
+// delivery already happened on the legacy platform. Wrappers are looked through.
+func receiptable(ev event.Eventer) bool {
+	for ev != nil {
+		if e, ok := ev.(event.Ephemeral); ok && e.IsEphemeral() {
+			return false
+		}
+		if m, ok := ev.(*event.MessageV1Event); ok && m.Imported {
+			return false
+		}
+		w, ok := ev.(event.Wrapper)
+		if !ok {
+			break
+		}
+		ev = w.Unwrap()
+	}
+	return true
+}
+
+// wantsReceipt reports whether kind is listed; names match EventKind.String, case-insensitively.
+func wantsReceipt(kinds []string, kind event.EventKind) bool {
+	if len(kinds) == 0 {
+		return false
+	}
+	name := kind.String()
+	for _, k := range kinds {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"go.uber.org/fx/fxtest"
+)
+
+// countingReceipts counts the receipts handed to the bus.
+type countingReceipts struct{ n atomic.Int64 }
+
+func (p *countingReceipts) PublishWithTimeout(context.Context, event.Eventer, time.Duration) error {
+	p.n.Add(1)
+	return nil
+}
+
+func TestDeliveryReceiptsRecord(t *testing.T) {
+	user := uuid.New()
+	message := func(imported bool) *event.MessageV1Event {
+		ev := event.NewMessageV1Event(&model.Message{ID: uuid.New(), DomainID: 1}, user, model.Peer{}, model.Peer{})
+		ev.Imported = imported
+		return ev
+	}
+
+	tests := []struct {
+		name string
+		ev   event.Eventer
+		want int64
+	}{
+		{name: "live message", ev: message(false), want: 1},
+		{name: "replayed live message", ev: event.NewReplayedEvent(message(false)), want: 1},
+		{name: "imported message", ev: message(true)},
+		{name: "replayed imported message", ev: event.NewReplayedEvent(message(true))},
+		{name: "ephemeral topic event", ev: event.NewTopicEvent("thread.42", []byte(`{}`))},
+		{name: "unlisted kind", ev: event.NewSystemEvent(user, event.Ping, event.PriorityLow, &model.PingPayload{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Delivery.Receipts.Kinds = []string{event.MessageCreated.String(), event.TopicMessage.String()}
+
+			pub := &countingReceipts{}
+			r := NewDeliveryReceipts(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), fxtest.NewLifecycle(t), pub)
+			conn := registry.NewConnector(t.Context(), user, 8, registry.ConnectMetadata{})
+
+			r.Record(conn, tt.ev, TransportGRPC, 1)
+			r.stop() // Waits for the in-flight publish
+
+			if got := pub.n.Load(); got != tt.want {
+				t.Fatalf("published %d receipts, want %d", got, tt.want)
+			}
+		})
+	}
+}
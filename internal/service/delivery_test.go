@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// TestSubscribeCleanupWatchdog checks the [CLEANUP_WATCHDOG] fallback: a transport
+// whose deferred Unsubscribe never runs is still detached once its context ends.
+func TestSubscribeCleanupWatchdog(t *testing.T) {
+	tests := []struct {
+		name       string
+		end        func(s *DeliveryService, userID, connID uuid.UUID, cancel context.CancelFunc)
+		wantDetach bool
+	}{
+		{
+			name:       "handler dies before its defer",
+			end:        func(_ *DeliveryService, _, _ uuid.UUID, cancel context.CancelFunc) { cancel() },
+			wantDetach: true,
+		},
+		{
+			name: "regular teardown",
+			end: func(s *DeliveryService, userID, connID uuid.UUID, cancel context.CancelFunc) {
+				s.Unsubscribe(userID, connID)
+				cancel()
+			},
+			wantDetach: true,
+		},
+		{
+			name: "live stream",
+			end:  func(*DeliveryService, uuid.UUID, uuid.UUID, context.CancelFunc) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, hub := newTestDeliveryService(t, &config.Config{})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			userID := uuid.New()
+			conn, err := s.Subscribe(ctx, userID, SubscribeOptions{Transport: TransportGRPC})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Release()
+
+			tt.end(s, userID, conn.GetID(), cancel)
+
+			want := 1
+			if tt.wantDetach {
+				want = 0
+			}
+			deadline := time.Now().Add(time.Second)
+			for hub.ConnectedCount(userID) != want && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if got := hub.ConnectedCount(userID); got != want {
+				t.Fatalf("sessions attached: got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+// TestSubscribeKindFilterRouting attaches sessions of one user with different kind
+// filters and checks that each receives only its own subset of the same traffic.
+func TestSubscribeKindFilterRouting(t *testing.T) {
+	s, hub := newTestDeliveryService(t, &config.Config{})
+	userID := uuid.New()
+
+	sessions := []struct {
+		name  string
+		kinds []event.EventKind
+		want  []event.EventKind
+	}{
+		{name: "messages only", kinds: []event.EventKind{event.MessageCreated}, want: []event.EventKind{event.MessageCreated}},
+		{name: "typing and deletions", kinds: []event.EventKind{event.Typing, event.MessageDeleted}, want: []event.EventKind{event.Typing, event.MessageDeleted}},
+		{name: "unfiltered", want: []event.EventKind{event.MessageCreated, event.Typing, event.MessageDeleted}},
+	}
+	conns := make([]registry.Connector, len(sessions))
+	for i, sess := range sessions {
+		conn, err := s.Subscribe(t.Context(), userID, SubscribeOptions{Transport: TransportGRPC, Kinds: sess.kinds})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Unsubscribe(userID, conn.GetID()) })
+		conns[i] = conn
+	}
+
+	hub.Broadcast(event.NewMessageV1Event(&model.Message{ID: uuid.New(), DomainID: 1}, userID, model.Peer{}, model.Peer{}))
+	hub.Broadcast(event.NewTypingEvent(&model.TypingPayload{}, userID, time.Now().UnixMilli()))
+	hub.Broadcast(event.NewMessageDeletedEvent(&model.MessageDeleted{}, userID))
+	// [SENTINEL] Control signals pass every filter, so the ping marks the end of the traffic.
+	hub.Broadcast(newPing(userID))
+
+	for i, sess := range sessions {
+		t.Run(sess.name, func(t *testing.T) {
+			var got []event.EventKind
+			timeout := time.After(time.Second)
+			for {
+				select {
+				case ev := <-conns[i].Recv():
+					if ev.GetKind() == event.Ping {
+						if !slices.Equal(got, sess.want) {
+							t.Fatalf("received %v, want %v", got, sess.want)
+						}
+						return
+					}
+					got = append(got, ev.GetKind())
+				case <-timeout:
+					t.Fatalf("sentinel not received; got %v", got)
+				}
+			}
+		})
+	}
+}
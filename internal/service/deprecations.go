@@ -0,0 +1,64 @@
+package service
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+)
+
+// Deprecated features without a dedicated home package. Transport-specific ones
+// register next to their code path (see the WS handler and the gRPC marshaller).
+var (
+	// DeprecatedAPIV1 guards the legacy api/v1 gRPC surface.
+	// [NO_CALL_SITE] This build no longer serves it; the entry keeps the name reserved
+	// so dashboards and the reject switch stay stable while older nodes drain.
+	DeprecatedAPIV1 = deprecation.Register("api_v1", "legacy gen/go/api/v1 gRPC surface")
+
+	// DeprecatedRawLastEventID guards resuming from a bare event ID instead of a cursor.
+	DeprecatedRawLastEventID = deprecation.Register("raw_last_event_id", "resume from a raw last_event_id instead of a cursor")
+)
+
+// ConfigureDeprecations applies the configured escalations to the default registry
+// and re-applies them whenever the config file is reloaded.
+func ConfigureDeprecations(cfg *config.Config, logger *slog.Logger) {
+	reg := deprecation.Default
+	reg.SetLogger(logger)
+
+	var (
+		mu     sync.Mutex
+		active []string
+	)
+	apply := func(cfg *config.Config) {
+		mu.Lock()
+		defer mu.Unlock()
+		if cfg.Deprecation.LogInterval > 0 {
+			reg.SetLogInterval(cfg.Deprecation.LogInterval)
+		}
+		active = applyDeprecationModes(reg, active, cfg.Deprecation.Reject, logger)
+	}
+
+	apply(cfg)
+	config.OnReload(apply)
+}
+
+// applyDeprecationModes rejects the features named in next and relaxes the ones that
+// were rejected by prev but are no longer listed. It returns next for the following call.
+func applyDeprecationModes(reg *deprecation.Registry, prev, next []string, logger *slog.Logger) []string {
+	for _, name := range prev {
+		if !slices.Contains(next, name) {
+			reg.SetMode(name, deprecation.ModeWarn)
+			logger.Info("DEPRECATION_RELAXED", slog.String("feature", name), slog.String("mode", deprecation.ModeWarn.String()))
+		}
+	}
+	for _, name := range next {
+		if slices.Contains(prev, name) {
+			continue
+		}
+		reg.SetMode(name, deprecation.ModeReject)
+		logger.Info("DEPRECATION_ESCALATED", slog.String("feature", name), slog.String("mode", deprecation.ModeReject.String()))
+	}
+	return slices.Clone(next)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/webitel/im-delivery-service/pkg/deprecation"
+)
+
+func TestApplyDeprecationModes(t *testing.T) {
+	tests := []struct {
+		name         string
+		prev, next   []string
+		wantRejected []string
+		wantRevision uint64
+	}{
+		{name: "startup", next: []string{"a"}, wantRejected: []string{"a"}, wantRevision: 1},
+		{name: "unchanged reload", prev: []string{"a"}, next: []string{"a"}, wantRejected: []string{"a"}},
+		{name: "reload adds a feature", prev: []string{"a"}, next: []string{"a", "b"}, wantRejected: []string{"a", "b"}, wantRevision: 1},
+		{name: "reload drops a feature", prev: []string{"a", "b"}, next: []string{"b"}, wantRejected: []string{"b"}, wantRevision: 1},
+		{name: "reload clears the list", prev: []string{"a", "b"}, wantRevision: 2},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := deprecation.NewRegistry()
+			features := map[string]*deprecation.Feature{
+				"a": reg.Register("a", "feature a"),
+				"b": reg.Register("b", "feature b"),
+			}
+			for _, name := range tt.prev {
+				reg.SetMode(name, deprecation.ModeReject)
+			}
+			base := reg.Revision()
+
+			got := applyDeprecationModes(reg, tt.prev, tt.next, logger)
+			if !slices.Equal(got, tt.next) {
+				t.Fatalf("active: got %v, want %v", got, tt.next)
+			}
+			for name, f := range features {
+				if want := slices.Contains(tt.wantRejected, name); f.Rejected() != want {
+					t.Errorf("%s rejected: got %v, want %v", name, f.Rejected(), want)
+				}
+			}
+			if rev := reg.Revision() - base; rev != tt.wantRevision {
+				t.Errorf("mode changes: got %d, want %d", rev, tt.wantRevision)
+			}
+		})
+	}
+}
@@ -3,22 +3,100 @@ package servicedi
 import (
 	"log/slog"
 
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/watch"
 	"github.com/webitel/im-delivery-service/internal/service"
 	"go.uber.org/fx"
 )
 
+// enrichment is the Hub-independent core shared by delivery and observer nodes.
+var enrichment = fx.Options(
+	fx.Provide(
+		service.NewResidencyPolicy,
+		service.NewEnrichmentPipeline,
+		fx.Annotate(
+			service.NewPeerEnricherService,
+			// [OPTIONAL_SOURCES] Bot/channel/group directories are not deployed everywhere.
+			fx.ParamTags(``, ``, `optional:"true"`, `optional:"true"`, `optional:"true"`),
+			fx.As(new(service.Enricher)),
+		),
+	),
+
+	// [DEPRECATION_STAGING] Escalations must be in place before transports accept traffic.
+	fx.Invoke(service.ConfigureDeprecations),
+
+	// [ENRICHMENT_STEPS] Registered once at start; the pipeline resolves the decorated Enricher.
+	fx.Invoke(service.RegisterDefaultEnrichment),
+
+	// [DECORATION_LAYER] Intercept Enricher to add cross-cutting concerns
+	fx.Decorate(func(orig service.Enricher, logger *slog.Logger) service.Enricher {
+		return &service.EnricherMiddleware{
+			Next:   orig,
+			Logger: logger,
+		}
+	}),
+)
+
 var Module = fx.Module(
 	"service",
 
+	enrichment,
+
 	fx.Provide(
 		// Domain services
+		service.NewSessionTokens,
+		service.NewEventImporter,
+		service.NewDeliveryShaper,
+		service.NewReconnectAdvisor,
+		service.NewSlowDeliveryTracker,
+		service.NewDomainPauser,
+		fx.Annotate(
+			service.NewAnalyticsSampler,
+			// [OPTIONAL_SINK] Without a broker-backed sink the sampler stays detached.
+			fx.ParamTags(``, ``, ``, `optional:"true"`),
+		),
 		fx.Annotate(
 			service.NewDeliveryService,
+			fx.ParamTags(``, ``, ``, ``, ``, ``, `group:"delivery_options"`),
 			fx.As(new(service.Deliverer)),
 		),
 		fx.Annotate(
-			service.NewPeerEnricherService,
-			fx.As(new(service.Enricher)),
+			func(cfg *config.Config) service.Option {
+				return service.WithE2EEncryptionEnabled(cfg.Delivery.E2EE.Enabled)
+			},
+			fx.ResultTags(`group:"delivery_options"`),
+		),
+		// [PRESENCE_WATCH] Sessions register watches; the AMQP status consumer reads them.
+		watch.NewRegistry,
+		fx.Annotate(
+			service.WithPresenceWatches,
+			fx.ResultTags(`group:"delivery_options"`),
+		),
+		fx.Annotate(
+			service.WithTopicAuthorizer,
+			// [OPTIONAL_AUTHORIZER] Without a call/queue directory every topic subscription is refused.
+			fx.ParamTags(`optional:"true"`),
+			fx.ResultTags(`group:"delivery_options"`),
+		),
+		fx.Annotate(
+			service.NewDeliveryReceipts,
+			// [OPTIONAL_PUBLISHER] Without a bus dispatcher no receipts are published.
+			fx.ParamTags(``, ``, ``, `optional:"true"`),
+		),
+		fx.Annotate(
+			service.WithDeliveryReceipts,
+			fx.ResultTags(`group:"delivery_options"`),
+		),
+		fx.Annotate(
+			service.NewPresenceTracker,
+			// [OPTIONAL_PUBLISHER] Without a bus dispatcher presence stays node-local.
+			fx.ParamTags(``, ``, ``, ``, ``, `optional:"true"`),
+		),
+		// [DIAGNOSTICS] Node-local delivery timelines for support queries.
+		fx.Annotate(
+			service.NewDeliveryInspector,
+			// [OPTIONAL_PEERS] Without a cluster querier the inspector answers for this node only.
+			fx.ParamTags(``, ``, `optional:"true"`),
 		),
 		fx.Annotate(
 			service.NewAuthService,
@@ -26,11 +104,13 @@ var Module = fx.Module(
 		),
 	),
 
-	// [DECORATION_LAYER] Intercept Enricher to add cross-cutting concerns
-	fx.Decorate(func(orig service.Enricher, logger *slog.Logger) service.Enricher {
-		return &service.EnricherMiddleware{
-			Next:   orig,
-			Logger: logger,
-		}
-	}),
+	// [EAGER_INIT] Observers have no consumers; force them so they attach to the Hub.
+	fx.Invoke(func(*service.DeliveryInspector, *service.AnalyticsSampler, *service.PresenceTracker) {}),
+)
+
+// ObserverModule provides only what the consume-enrich-export pipeline needs.
+var ObserverModule = fx.Module(
+	"service-observer",
+
+	enrichment,
 )
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/fx/fxtest"
+)
+
+// domainUsers answers UsersInDomain from a fixed table.
+type domainUsers struct {
+	Deliverer
+	users map[int64][]uuid.UUID
+}
+
+func (d domainUsers) UsersInDomain(domainID int64) []uuid.UUID { return d.users[domainID] }
+
+// pauseFixture is a Hub with one connected user in each of two domains.
+type pauseFixture struct {
+	hub               *registry.Hub
+	pauser            *DomainPauser
+	paused, other     registry.Connector // Users of domain 7 and domain 8
+	pausedID, otherID uuid.UUID
+}
+
+func newPauseFixture(t *testing.T, cfg *config.Config) *pauseFixture {
+	t.Helper()
+	f := &pauseFixture{hub: registry.NewHub(registry.WithShardCount(1), registry.WithEvictionInterval(time.Hour))}
+	t.Cleanup(f.hub.Shutdown)
+
+	f.pausedID, f.otherID = uuid.New(), uuid.New()
+	f.paused = registry.NewConnector(t.Context(), f.pausedID, 64, registry.ConnectMetadata{})
+	f.other = registry.NewConnector(t.Context(), f.otherID, 64, registry.ConnectMetadata{})
+	f.hub.Register(f.paused)
+	f.hub.Register(f.other)
+
+	users := domainUsers{users: map[int64][]uuid.UUID{7: {f.pausedID}, 8: {f.otherID}}}
+	lc := fxtest.NewLifecycle(t)
+	f.pauser = NewDomainPauser(f.hub, users, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), lc)
+	lc.RequireStart()
+	t.Cleanup(lc.RequireStop)
+	return f
+}
+
+// send routes n events for userID through the gate the way Bind does and returns their IDs.
+func (f *pauseFixture) send(domainID int64, userID uuid.UUID, n int) []string {
+	ids := make([]string, 0, n)
+	for range n {
+		ev := event.NewSystemEvent(userID, event.Ping, event.PriorityNormal, nil)
+		ids = append(ids, ev.GetID())
+		if !f.pauser.Hold(domainID, ev) {
+			f.hub.BroadcastIfConnected(ev)
+		}
+	}
+	return ids
+}
+
+// received drains what the connector holds once the bubble is idle.
+func received(conn registry.Connector) (kinds []event.EventKind, pings []string) {
+	synctest.Wait()
+	for {
+		select {
+		case ev := <-conn.Recv():
+			kinds = append(kinds, ev.GetKind())
+			if ev.GetKind() == event.Ping {
+				pings = append(pings, ev.GetID())
+			}
+		default:
+			return kinds, pings
+		}
+	}
+}
+
+func TestDomainPause(t *testing.T) {
+	const window = time.Minute
+	tests := []struct {
+		name      string
+		limit     int
+		sent      int
+		liftAfter time.Duration // 0 lets the pause expire
+		wantAt    time.Duration // Release time, from the start of the pause
+		wantHeld  int           // Leading events released; the rest are shed
+	}{
+		{name: "auto-expiry releases in order", sent: 5, wantAt: window, wantHeld: 5},
+		{name: "early lift", sent: 5, liftAfter: 10 * time.Second, wantAt: 10 * time.Second, wantHeld: 5},
+		{name: "overflow sheds, never delivers early", limit: 3, sent: 5, wantAt: window, wantHeld: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := counterTotal(t, "im_delivery_domain_pause_overflow_total")
+			synctest.Test(t, func(t *testing.T) {
+				cfg := &config.Config{}
+				cfg.Delivery.Pause.MaxEventsPerDomain = tt.limit
+				f := newPauseFixture(t, cfg)
+
+				start := time.Now()
+				if err := f.pauser.Pause(7, start.Add(window), PauseBuffer); err != nil {
+					t.Fatal(err)
+				}
+				if kinds, _ := received(f.paused); !slices.Equal(kinds, []event.EventKind{event.DomainPaused}) {
+					t.Fatalf("paused domain got %v, want the DomainPaused banner", kinds)
+				}
+				if kinds, _ := received(f.other); len(kinds) != 0 {
+					t.Fatalf("other domain got %v", kinds)
+				}
+
+				held := f.send(7, f.pausedID, tt.sent)
+				if st := f.pauser.Paused(); len(st) != 1 || st[0].Held != tt.wantHeld || st[0].Shed != tt.sent-tt.wantHeld {
+					t.Fatalf("Paused() = %+v, want %d held and %d shed", st, tt.wantHeld, tt.sent-tt.wantHeld)
+				}
+
+				if tt.liftAfter > 0 {
+					time.Sleep(tt.liftAfter)
+					if !f.pauser.Resume(7) {
+						t.Fatal("Resume found no active pause")
+					}
+				} else {
+					time.Sleep(tt.wantAt - time.Second)
+					if kinds, _ := received(f.paused); len(kinds) != 0 {
+						t.Fatalf("delivered %v before the window ended", kinds)
+					}
+					time.Sleep(time.Second)
+				}
+
+				kinds, pings := received(f.paused)
+				if elapsed := time.Since(start); elapsed != tt.wantAt {
+					t.Fatalf("released after %v, want %v", elapsed, tt.wantAt)
+				}
+				if !slices.Equal(pings, held[:tt.wantHeld]) {
+					t.Fatalf("released %v, want %v in the original order", pings, held[:tt.wantHeld])
+				}
+				if kinds[len(kinds)-1] != event.DomainResumed {
+					t.Fatalf("last event %v, want DomainResumed after the release", kinds[len(kinds)-1])
+				}
+				if st := f.pauser.Paused(); len(st) != 0 {
+					t.Fatalf("pause still active: %+v", st)
+				}
+
+				// An early lift stops the expiry timer: nothing fires at the original deadline.
+				time.Sleep(window)
+				if kinds, _ := received(f.paused); len(kinds) != 0 {
+					t.Fatalf("got %v after the pause ended", kinds)
+				}
+			})
+			if got := counterTotal(t, "im_delivery_domain_pause_overflow_total") - before; got != int64(tt.sent-tt.wantHeld) {
+				t.Fatalf("im_delivery_domain_pause_overflow_total grew by %d, want %d", got, tt.sent-tt.wantHeld)
+			}
+		})
+	}
+}
+
+// TestDomainPauseIsolation checks that a paused, overflowing tenant costs the others
+// nothing: their events reach the client at the instant they are sent.
+func TestDomainPauseIsolation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Delivery.Pause.MaxEventsPerDomain = 2
+		f := newPauseFixture(t, cfg)
+		if err := f.pauser.Pause(7, time.Now().Add(time.Minute), PauseBuffer); err != nil {
+			t.Fatal(err)
+		}
+		received(f.paused)
+
+		for range 3 {
+			f.send(7, f.pausedID, 10) // Keeps the paused domain shedding throughout
+			start := time.Now()
+			sent := f.send(8, f.otherID, 5)
+			_, got := received(f.other)
+			if !slices.Equal(got, sent) {
+				t.Fatalf("other domain received %v, want %v", got, sent)
+			}
+			if latency := time.Since(start); latency != 0 {
+				t.Fatalf("other domain delivered after %v", latency)
+			}
+			time.Sleep(10 * time.Second)
+		}
+		if _, got := received(f.paused); len(got) != 0 {
+			t.Fatalf("paused domain received %d events", len(got))
+		}
+	})
+}
+
+func TestPauseValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		domainID  int64
+		until     time.Duration // From now
+		policy    PausePolicy
+		maxWindow time.Duration
+		wantErr   string
+		wantUntil time.Duration
+	}{
+		{name: "buffer", domainID: 7, until: time.Minute, policy: PauseBuffer, wantUntil: time.Minute},
+		{name: "clamped to the configured maximum", domainID: 7, until: time.Hour, policy: PauseBuffer, maxWindow: 10 * time.Minute, wantUntil: 10 * time.Minute},
+		{name: "no domain", until: time.Minute, policy: PauseBuffer, wantErr: ErrPauseInvalid.Error()},
+		{name: "deadline in the past", domainID: 7, until: -time.Second, policy: PauseBuffer, wantErr: ErrPauseInvalid.Error()},
+		{name: "defer needs partitioned queues", domainID: 7, until: time.Minute, policy: PauseDefer, wantErr: ErrPauseDeferUnsupported.Error()},
+		{name: "unknown policy", domainID: 7, until: time.Minute, policy: "drop", wantErr: `unknown policy "drop"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				cfg := &config.Config{}
+				cfg.Delivery.Pause.MaxDuration = tt.maxWindow
+				f := newPauseFixture(t, cfg)
+
+				err := f.pauser.Pause(tt.domainID, time.Now().Add(tt.until), tt.policy)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("Pause: got %v, want %q", err, tt.wantErr)
+					}
+					if st := f.pauser.Paused(); len(st) != 0 {
+						t.Fatalf("rejected pause is active: %+v", st)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Pause: %v", err)
+				}
+				if st := f.pauser.Paused(); len(st) != 1 || time.Until(st[0].Until) != tt.wantUntil {
+					t.Fatalf("Paused() = %+v, want one pause ending in %v", st, tt.wantUntil)
+				}
+			})
+		})
+	}
+}
+
+// counterTotal sums an int64 counter across its attribute sets.
+func counterTotal(t *testing.T, name string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := testMetrics().Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			var total int64
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				total += dp.Value
+			}
+			return total
+		}
+	}
+	return 0
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+)
+
+// ErrServerDraining is matched (errors.Is) by transports that only need the category.
+var ErrServerDraining = errors.New("delivery: server is draining")
+
+// DrainingError rejects a subscription while the node drains.
+// It carries the backoff the client should honor before redialing, ideally elsewhere.
+type DrainingError struct {
+	Hint reconnect.Hint
+}
+
+func (e *DrainingError) Error() string { return ErrServerDraining.Error() }
+
+func (e *DrainingError) Unwrap() error { return ErrServerDraining }
+
+// Drain refuses new subscriptions, tells every session the node is going away and
+// waits, up to deadline, for queued events to reach them. Close must still follow.
+func (s *DeliveryService) Drain(deadline time.Time) {
+	// [HERD_SMEARING] Every notice below gets a spread-out shutdown backoff.
+	s.advisor.BeginShutdown()
+	s.hub.BeginDrain(deadline, func(uuid.UUID) *model.DisconnectedPayload {
+		hint := s.advisor.Advise(reconnect.ReasonShutdown)
+		return &model.DisconnectedPayload{
+			Code:          string(hint.Reason),
+			RetryAfterMs:  hint.RetryAfterMs(),
+			AlternateNode: hint.AlternateNode,
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/util"
+)
+
+// ContactChangedV1 is the payload of im_contact.contact.updated.v1 and contact.deleted.v1.
+// Hand-written: im_contact events have no vendored contract yet. Deletions carry
+// only the identifiers; updates may carry the new profile.
+type ContactChangedV1 struct {
+	ContactID string `json:"contact_id"`
+	DomainID  int32  `json:"domain_id"`
+	Name      string `json:"name,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+	Issuer    string `json:"iss_id,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// GetDomainID exposes the tenant for logging before any processing.
+func (d *ContactChangedV1) GetDomainID() int64 { return int64(d.DomainID) }
+
+// ToPeer builds the enriched user peer the update describes. It is not enriched
+// (IsEnriched false) when the payload carries no subject.
+func (d *ContactChangedV1) ToPeer() model.Peer {
+	name := d.Name
+	if name == "" {
+		name = d.Username
+	}
+	return model.NewPeer(util.SafeParseUUID(d.ContactID), model.PeerUser,
+		model.WithIdentity(d.Subject, d.Issuer, name),
+		model.WithAvatar(d.AvatarURL),
+	)
+}
@@ -0,0 +1,215 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files under testdata/")
+
+// contract pairs a vendored example payload with its generated DTO.
+type contract struct {
+	example string // Relative to contracts/
+	lenient func() checker
+	strict  func([]byte) (checker, error)
+}
+
+type checker interface{ CheckContract() error }
+
+func strictly[T checker](decode func([]byte) (T, error)) func([]byte) (checker, error) {
+	return func(data []byte) (checker, error) { return decode(data) }
+}
+
+var contracts = []contract{
+	{"im_message/v1/examples/message_created.json", func() checker { return new(MessageV1) }, strictly(DecodeMessageV1Strict)},
+	{"im_message/v1/examples/message_deleted.json", func() checker { return new(MessageDeletedV1) }, strictly(DecodeMessageDeletedV1Strict)},
+	{"im_message/v1/examples/message_reaction.json", func() checker { return new(ReactionV1) }, strictly(DecodeReactionV1Strict)},
+	{"im_message/v1/examples/message_read.json", func() checker { return new(MessageReadV1) }, strictly(DecodeMessageReadV1Strict)},
+	{"im_message/v1/examples/message_updated.json", func() checker { return new(MessageUpdatedV1) }, strictly(DecodeMessageUpdatedV1Strict)},
+	{"im_message/v2/examples/message_created.json", func() checker { return new(MessageV2) }, strictly(DecodeMessageV2Strict)},
+}
+
+func readExample(t *testing.T, rel string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "contracts", filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// TestContractExamples is the runtime half of the [DRIFT_DETECTION] check: every
+// producer example must pass the strict decoder and CheckContract, and survive a
+// round trip through the generated type without losing a key or a value.
+func TestContractExamples(t *testing.T) {
+	for _, c := range contracts {
+		t.Run(c.example, func(t *testing.T) {
+			data := readExample(t, c.example)
+
+			v, err := c.strict(data)
+			if err != nil {
+				t.Fatalf("strict decode: %v", err)
+			}
+			if err := v.CheckContract(); err != nil {
+				t.Fatalf("CheckContract: %v", err)
+			}
+
+			out, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want, got any
+			if err := json.Unmarshal(data, &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatal(err)
+			}
+			if path, ok := covers(got, want, "$"); !ok {
+				t.Fatalf("round trip lost %s\nexample: %s\ngot:     %s", path, data, out)
+			}
+
+			again, err := c.strict(out)
+			if err != nil {
+				t.Fatalf("strict decode of the re-encoded payload: %v", err)
+			}
+			if !reflect.DeepEqual(again, v) {
+				t.Fatalf("second round trip differs:\n got %+v\nwant %+v", again, v)
+			}
+		})
+	}
+}
+
+// covers reports whether got carries every key and value of want; extra keys in got
+// are zero-valued fields the example omitted.
+func covers(got, want any, at string) (string, bool) {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return at, false
+		}
+		for k, wv := range w {
+			gv, ok := g[k]
+			if !ok {
+				return at + "." + k, false
+			}
+			if path, ok := covers(gv, wv, at+"."+k); !ok {
+				return path, false
+			}
+		}
+		return "", true
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return at, false
+		}
+		for i := range w {
+			if path, ok := covers(g[i], w[i], at); !ok {
+				return path, false
+			}
+		}
+		return "", true
+	default:
+		return at, reflect.DeepEqual(got, want)
+	}
+}
+
+// TestContractGolden pins the bytes the hot-path (lenient) decoder produces for each
+// example. The MessageV1/V2 goldens were recorded with the hand-written DTOs the
+// generated ones replaced, so a generator change that alters the wire shape fails here.
+// Run with -update to re-record after an intentional contract change.
+func TestContractGolden(t *testing.T) {
+	for _, c := range contracts {
+		t.Run(c.example, func(t *testing.T) {
+			v := c.lenient()
+			if err := json.Unmarshal(readExample(t, c.example), v); err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			name := strings.ReplaceAll(strings.Replace(c.example, "/examples/", "/", 1), "/", "_")
+			golden := filepath.Join("testdata", strings.TrimSuffix(name, ".json")+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%s drifted:\n got %s\nwant %s", golden, got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	const valid = `{"message_id":"m1","thread_id":"t1","domain_id":1}`
+	tests := []struct {
+		name       string
+		data       string
+		extensions []string
+		wantErr    bool
+	}{
+		{name: "declared fields only", data: valid},
+		{name: "unknown field", data: `{"message_id":"m1","thread_id":"t1","domain_id":1,"deleted_at":"x"}`, wantErr: true},
+		{name: "allowlisted extension", data: `{"message_id":"m1","thread_id":"t1","domain_id":1,"trace":"x"}`, extensions: []string{"trace"}},
+		{name: "extension does not cover other keys", data: `{"message_id":"m1","domain_id":1,"trace":"x","other":1}`, extensions: []string{"trace"}, wantErr: true},
+		{name: "renamed key", data: `{"messageId":"m1","thread_id":"t1","domain_id":1}`, wantErr: true},
+		{name: "string where an integer is declared", data: `{"message_id":"m1","thread_id":"t1","domain_id":"1"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decodeStrict([]byte(tt.data), new(MessageDeletedV1), tt.extensions)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrContractViolation)) {
+				t.Fatalf("decodeStrict: got %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckContract(t *testing.T) {
+	peer := PeerDTO{ID: "p1", Type: 1}
+	msg := MessageV1{MessageID: "m1", ThreadID: "t1", DomainID: 1, From: peer, To: peer}
+	tests := []struct {
+		name    string
+		in      checker
+		wantErr string
+	}{
+		{name: "complete", in: &msg},
+		{name: "missing message id", in: &MessageV1{ThreadID: "t1", DomainID: 1, From: peer, To: peer}, wantErr: "message_id is required"},
+		{name: "missing domain", in: &MessageDeletedV1{MessageID: "m1", ThreadID: "t1"}, wantErr: "domain_id is required"},
+		{name: "nested peer", in: &MessageV1{MessageID: "m1", ThreadID: "t1", DomainID: 1, To: peer}, wantErr: "from: "},
+		{name: "embedded parent is checked", in: &MessageV2{Metadata: map[string]any{}}, wantErr: "message_id is required"},
+		{name: "encrypted body without a key", in: &MessageV2{MessageV1: msg, Encrypted: &EncryptedDTO{Ciphertext: []byte{1}}}, wantErr: "key_id is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.in.CheckContract()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckContract: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrContractViolation) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CheckContract: got %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package dto
+
+// The raw inbound shapes are generated from the producer contracts; the mapping to
+// domain models (ToDomain, Validate) stays hand-written next to them.
+// See contracts/README.md for the regeneration and drift-check workflow.
+//go:generate go run ../../tools/dtogen -pkg dto -out zz_contracts.go ../../../contracts/im_message/v1/message_created.schema.json ../../../contracts/im_message/v1/message_deleted.schema.json ../../../contracts/im_message/v1/message_reaction.schema.json ../../../contracts/im_message/v1/message_read.schema.json ../../../contracts/im_message/v1/message_updated.schema.json ../../../contracts/im_message/v2/message_created.schema.json
@@ -8,22 +8,14 @@ import (
 	"github.com/webitel/im-delivery-service/internal/domain/util"
 )
 
-type PeerDTO struct {
-	ID   string `json:"id"`
-	Type int    `json:"type"`
-}
+// GetDomainID exposes the tenant for residency checks before any processing.
+func (d *MessageV1) GetDomainID() int64 { return int64(d.DomainID) }
 
-type MessageV1 struct {
-	MessageID  string        `json:"message_id"`
-	ThreadID   string        `json:"thread_id"`
-	DomainID   int32         `json:"domain_id"`
-	From       PeerDTO       `json:"from"`
-	To         PeerDTO       `json:"to"`
-	Body       string        `json:"body"`
-	OccurredAt string        `json:"occurred_at"`
-	Images     []ImageDTO    `json:"images"`
-	Documents  []DocumentDTO `json:"documents"`
-}
+// GetMessageID returns the message's ID.
+func (d *MessageV1) GetMessageID() string { return d.MessageID }
+
+// DedupKey keys consumer-side duplicate suppression of redelivered message.created.
+func (d *MessageV1) DedupKey() string { return d.MessageID }
 
 func (d *MessageV1) ToDomain() *model.Message {
 	return &model.Message{
@@ -70,17 +62,3 @@ func (d *MessageV1) mapDocs() []*model.Document {
 	}
 	return res
 }
-
-type ImageDTO struct {
-	FileID int64  `json:"file_id"`
-	Mime   string `json:"mime"`
-	Name   string `json:"name"`
-	URL    string `json:"url"`
-}
-
-type DocumentDTO struct {
-	FileID int64  `json:"file_id"`
-	Mime   string `json:"mime"`
-	Name   string `json:"name"`
-	Size   int64  `json:"size"`
-}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/util"
+)
+
+// GetDomainID exposes the tenant for residency checks before any processing.
+func (d *MessageDeletedV1) GetDomainID() int64 { return int64(d.DomainID) }
+
+func (d *MessageDeletedV1) ToDomain() *model.MessageDeleted {
+	return &model.MessageDeleted{
+		ID:        util.SafeParseUUID(d.MessageID),
+		ThreadID:  util.SafeParseUUID(d.ThreadID),
+		DomainID:  int64(d.DomainID),
+		DeletedBy: util.SafeParseUUID(d.DeletedBy),
+		DeletedAt: util.SafeParseRFC3339(d.OccurredAt),
+	}
+}
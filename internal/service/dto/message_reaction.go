@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/util"
+)
+
+// GetDomainID exposes the tenant for residency checks before any processing.
+func (d *ReactionV1) GetDomainID() int64 { return int64(d.DomainID) }
+
+func (d *ReactionV1) ToDomain() *model.Reaction {
+	return &model.Reaction{
+		MessageID: util.SafeParseUUID(d.MessageID),
+		ThreadID:  util.SafeParseUUID(d.ThreadID),
+		DomainID:  int64(d.DomainID),
+		Emoji:     d.Emoji,
+		Actor:     d.Actor.ToDomain(),
+		Added:     d.Added,
+		At:        util.SafeParseRFC3339(d.OccurredAt),
+	}
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/util"
+)
+
+// GetDomainID exposes the tenant for residency checks before any processing.
+func (d *MessageReadV1) GetDomainID() int64 { return int64(d.DomainID) }
+
+func (d *MessageReadV1) ToDomain() *model.MessageRead {
+	return &model.MessageRead{
+		ThreadID:          util.SafeParseUUID(d.ThreadID),
+		DomainID:          int64(d.DomainID),
+		Reader:            d.Reader.ToDomain(),
+		LastReadMessageID: util.SafeParseUUID(d.LastReadMessageID),
+		ReadAt:            util.SafeParseRFC3339(d.OccurredAt),
+	}
+}
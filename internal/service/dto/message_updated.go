@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/util"
+)
+
+// ToDomain maps the edited message; EditedAt is what tells it apart from a creation.
+func (d *MessageUpdatedV1) ToDomain() *model.Message {
+	msg := d.MessageV1.ToDomain()
+	msg.EditedAt = util.SafeParseRFC3339(d.EditedAt)
+	return msg
+}
+
+// DedupKey tells successive edits of one message apart; a redelivered edit repeats its edited_at.
+func (d *MessageUpdatedV1) DedupKey() string {
+	if d.EditedAt == "" {
+		return "" // Falls back to the broker envelope.
+	}
+	return d.MessageID + "@" + d.EditedAt
+}
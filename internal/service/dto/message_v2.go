@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/util"
+)
+
+// MaxCiphertextBytes bounds the decoded size of an E2EE body.
+// [NO_TRUNCATION] Oversized ciphertext is rejected: a cut blob cannot be decrypted.
+const MaxCiphertextBytes = 64 * 1024
+
+var ErrCiphertextTooLarge = errors.New("dto: encrypted body exceeds size limit")
+
+// Validate enforces size limits on the encrypted block.
+func (d *MessageV2) Validate() error {
+	if d.Encrypted != nil && len(d.Encrypted.Ciphertext) > MaxCiphertextBytes {
+		return fmt.Errorf("%w: %d bytes", ErrCiphertextTooLarge, len(d.Encrypted.Ciphertext))
+	}
+	return nil
+}
+
+func (d *MessageV2) ToDomain() *model.Message {
+	msg := d.MessageV1.ToDomain()
+	if d.Metadata != nil {
+		msg.Metadata = d.Metadata
+	}
+	msg.IsForward = d.IsForward
+
+	if d.Encrypted != nil {
+		// [E2EE] The body is opaque: never carry a plaintext alongside the ciphertext.
+		msg.Text = ""
+		msg.Encrypted = d.Encrypted.toDomain()
+	}
+
+	return msg
+}
+
+func (d *EncryptedDTO) toDomain() *model.EncryptedContent {
+	res := &model.EncryptedContent{
+		Ciphertext: d.Ciphertext,
+		Algorithm:  d.Algorithm,
+		KeyID:      d.KeyID,
+		Envelopes:  make([]model.KeyEnvelope, 0, len(d.Envelopes)),
+	}
+	for _, env := range d.Envelopes {
+		res.Envelopes = append(res.Envelopes, model.KeyEnvelope{
+			RecipientID: util.SafeParseUUID(env.RecipientID),
+			WrappedKey:  env.WrappedKey,
+		})
+	}
+	return res
+}
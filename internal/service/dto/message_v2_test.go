@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMessageV2ToDomain(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        MessageV2
+		wantMD    map[string]any
+		wantFwd   bool
+		wantText  string
+		encrypted bool
+	}{
+		{
+			name:     "plain",
+			in:       MessageV2{MessageV1: MessageV1{Body: "hi"}},
+			wantMD:   map[string]any{},
+			wantText: "hi",
+		},
+		{
+			name:     "forwarded with metadata",
+			in:       MessageV2{MessageV1: MessageV1{Body: "hi"}, Metadata: map[string]any{"k": "v"}, IsForward: true},
+			wantMD:   map[string]any{"k": "v"},
+			wantFwd:  true,
+			wantText: "hi",
+		},
+		{
+			name:      "encrypted drops the plaintext",
+			in:        MessageV2{MessageV1: MessageV1{Body: "leak"}, IsForward: true, Encrypted: &EncryptedDTO{Ciphertext: []byte{1}, KeyID: "k1"}},
+			wantMD:    map[string]any{},
+			wantFwd:   true,
+			encrypted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in.ToDomain()
+			if !reflect.DeepEqual(got.Metadata, tt.wantMD) {
+				t.Fatalf("metadata: got %v, want %v", got.Metadata, tt.wantMD)
+			}
+			if got.IsForward != tt.wantFwd {
+				t.Fatalf("is_forward: got %v, want %v", got.IsForward, tt.wantFwd)
+			}
+			if got.Text != tt.wantText || got.IsEncrypted() != tt.encrypted {
+				t.Fatalf("body: got text %q encrypted %v", got.Text, got.IsEncrypted())
+			}
+		})
+	}
+}
+
+func TestMessageV2Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      MessageV2
+		wantErr bool
+	}{
+		{name: "plain", in: MessageV2{MessageV1: MessageV1{Body: "hi"}}},
+		{name: "small ciphertext", in: MessageV2{Encrypted: &EncryptedDTO{Ciphertext: make([]byte, 16)}}},
+		{name: "at the limit", in: MessageV2{Encrypted: &EncryptedDTO{Ciphertext: make([]byte, MaxCiphertextBytes)}}},
+		{name: "over the limit is rejected, not truncated", in: MessageV2{Encrypted: &EncryptedDTO{Ciphertext: make([]byte, MaxCiphertextBytes+1)}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := 0
+			if tt.in.Encrypted != nil {
+				size = len(tt.in.Encrypted.Ciphertext)
+			}
+
+			err := tt.in.Validate()
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrCiphertextTooLarge)) {
+				t.Fatalf("Validate: got %v, want error %v", err, tt.wantErr)
+			}
+			if tt.in.Encrypted != nil && len(tt.in.Encrypted.Ciphertext) != size {
+				t.Fatalf("ciphertext resized to %d bytes", len(tt.in.Encrypted.Ciphertext))
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrContractViolation is returned when a payload breaks its vendored contract.
+var ErrContractViolation = errors.New("dto: contract violation")
+
+func missingField(name string) error {
+	return fmt.Errorf("%w: %s is required", ErrContractViolation, name)
+}
+
+func fieldErr(name string, err error) error {
+	return fmt.Errorf("%s: %w", name, err)
+}
+
+// decodeStrict is the [DRIFT_DETECTION] decoder: unlike the lenient json.Unmarshal
+// used on the hot path, an unknown key fails instead of being silently dropped.
+// Top-level keys listed in extensions are tolerated (producer-side additions agreed in the contract).
+func decodeStrict(data []byte, v any, extensions []string) error {
+	if len(extensions) > 0 {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		for _, ext := range extensions {
+			delete(raw, ext)
+		}
+		stripped, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		data = stripped
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrContractViolation, err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/util"
+)
+
+// TypingV1 is the payload of im_system.thread.typing.v1.
+// Hand-written: im_system events have no vendored contract yet.
+type TypingV1 struct {
+	ThreadID  string  `json:"thread_id"`
+	DomainID  int32   `json:"domain_id"`
+	From      PeerDTO `json:"from"`
+	ExpiresAt string  `json:"expires_at"` // RFC3339; the indicator is stale afterwards
+}
+
+// GetDomainID exposes the tenant for residency checks before any processing.
+func (d *TypingV1) GetDomainID() int64 { return int64(d.DomainID) }
+
+// ToDomain leaves ExpiresAt zero when the producer sent none; the handler applies its default.
+func (d *TypingV1) ToDomain() *model.TypingPayload {
+	res := &model.TypingPayload{
+		ThreadID: util.SafeParseUUID(d.ThreadID),
+		DomainID: int64(d.DomainID),
+		From:     d.From.ToDomain(),
+	}
+	if d.ExpiresAt != "" {
+		res.ExpiresAt = util.SafeParseRFC3339(d.ExpiresAt)
+	}
+	return res
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/util"
+)
+
+// UserStatusV1 is the payload of im_system.user.status.v1.
+// Hand-written: im_system events have no vendored contract yet.
+type UserStatusV1 struct {
+	ContactID  string `json:"contact_id"`
+	DomainID   int32  `json:"domain_id"`
+	Status     string `json:"status"` // online | away | dnd | offline
+	OccurredAt string `json:"occurred_at"`
+}
+
+// GetDomainID exposes the tenant for residency checks before any processing.
+func (d *UserStatusV1) GetDomainID() int64 { return int64(d.DomainID) }
+
+func (d *UserStatusV1) ToDomain() *model.PresenceStatusPayload {
+	return &model.PresenceStatusPayload{
+		ContactID: util.SafeParseUUID(d.ContactID),
+		DomainID:  int64(d.DomainID),
+		Status:    model.PresenceStatus(d.Status),
+		At:        util.SafeParseRFC3339(d.OccurredAt),
+	}
+}
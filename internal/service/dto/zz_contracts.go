@@ -0,0 +1,310 @@
+// Code generated by dtogen from contracts/im_message/v1/message_created.schema.json, contracts/im_message/v1/message_deleted.schema.json, contracts/im_message/v1/message_reaction.schema.json, contracts/im_message/v1/message_read.schema.json, contracts/im_message/v1/message_updated.schema.json, contracts/im_message/v2/message_created.schema.json; DO NOT EDIT.
+
+package dto
+
+// MessageV1 mirrors the im_message.v1.message_created contract.
+//
+// Message created notification published by the message service on im_message.v1.*.message.created.
+type MessageV1 struct {
+	MessageID  string        `json:"message_id"`
+	ThreadID   string        `json:"thread_id"`
+	DomainID   int32         `json:"domain_id"`
+	From       PeerDTO       `json:"from"`
+	To         PeerDTO       `json:"to"`
+	Body       string        `json:"body"`
+	OccurredAt string        `json:"occurred_at"`
+	Images     []ImageDTO    `json:"images"`
+	Documents  []DocumentDTO `json:"documents"`
+	Recipients []string      `json:"recipients,omitempty"`
+}
+
+// CheckContract reports the first required field of MessageV1 that is missing.
+func (d *MessageV1) CheckContract() error {
+	if len(d.MessageID) == 0 {
+		return missingField("message_id")
+	}
+	if len(d.ThreadID) == 0 {
+		return missingField("thread_id")
+	}
+	if d.DomainID == 0 {
+		return missingField("domain_id")
+	}
+	if err := d.From.CheckContract(); err != nil {
+		return fieldErr("from", err)
+	}
+	if err := d.To.CheckContract(); err != nil {
+		return fieldErr("to", err)
+	}
+	for i := range d.Images {
+		if err := d.Images[i].CheckContract(); err != nil {
+			return fieldErr("images", err)
+		}
+	}
+	for i := range d.Documents {
+		if err := d.Documents[i].CheckContract(); err != nil {
+			return fieldErr("documents", err)
+		}
+	}
+	return nil
+}
+
+// DecodeMessageV1Strict decodes data, rejecting fields the contract does not declare.
+func DecodeMessageV1Strict(data []byte) (*MessageV1, error) {
+	v := new(MessageV1)
+	if err := decodeStrict(data, v, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+type PeerDTO struct {
+	ID   string `json:"id"`
+	Type int    `json:"type"`
+}
+
+// CheckContract reports the first required field of PeerDTO that is missing.
+func (d *PeerDTO) CheckContract() error {
+	if len(d.ID) == 0 {
+		return missingField("id")
+	}
+	return nil
+}
+
+type ImageDTO struct {
+	FileID int64  `json:"file_id"`
+	Mime   string `json:"mime"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+}
+
+// CheckContract reports the first required field of ImageDTO that is missing.
+func (d *ImageDTO) CheckContract() error {
+	return nil
+}
+
+type DocumentDTO struct {
+	FileID int64  `json:"file_id"`
+	Mime   string `json:"mime"`
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+}
+
+// CheckContract reports the first required field of DocumentDTO that is missing.
+func (d *DocumentDTO) CheckContract() error {
+	return nil
+}
+
+// MessageDeletedV1 mirrors the im_message.v1.message_deleted contract.
+//
+// Message deleted notification published by the message service on im_message.v1.*.message.deleted.
+type MessageDeletedV1 struct {
+	MessageID  string `json:"message_id"`
+	ThreadID   string `json:"thread_id"`
+	DeletedBy  string `json:"deleted_by"`
+	DomainID   int32  `json:"domain_id"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+// CheckContract reports the first required field of MessageDeletedV1 that is missing.
+func (d *MessageDeletedV1) CheckContract() error {
+	if len(d.MessageID) == 0 {
+		return missingField("message_id")
+	}
+	if len(d.ThreadID) == 0 {
+		return missingField("thread_id")
+	}
+	if d.DomainID == 0 {
+		return missingField("domain_id")
+	}
+	return nil
+}
+
+// DecodeMessageDeletedV1Strict decodes data, rejecting fields the contract does not declare.
+func DecodeMessageDeletedV1Strict(data []byte) (*MessageDeletedV1, error) {
+	v := new(MessageDeletedV1)
+	if err := decodeStrict(data, v, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ReactionV1 mirrors the im_message.v1.message_reaction contract.
+//
+// Reaction added to or removed from a message, published by the message service on im_message.v1.*.message.reaction and routed to the message author.
+type ReactionV1 struct {
+	MessageID  string  `json:"message_id"`
+	ThreadID   string  `json:"thread_id"`
+	DomainID   int32   `json:"domain_id"`
+	Emoji      string  `json:"emoji"`
+	Actor      PeerDTO `json:"actor"`
+	Added      bool    `json:"added"`
+	OccurredAt string  `json:"occurred_at"`
+}
+
+// CheckContract reports the first required field of ReactionV1 that is missing.
+func (d *ReactionV1) CheckContract() error {
+	if len(d.MessageID) == 0 {
+		return missingField("message_id")
+	}
+	if len(d.ThreadID) == 0 {
+		return missingField("thread_id")
+	}
+	if d.DomainID == 0 {
+		return missingField("domain_id")
+	}
+	if len(d.Emoji) == 0 {
+		return missingField("emoji")
+	}
+	if err := d.Actor.CheckContract(); err != nil {
+		return fieldErr("actor", err)
+	}
+	return nil
+}
+
+// DecodeReactionV1Strict decodes data, rejecting fields the contract does not declare.
+func DecodeReactionV1Strict(data []byte) (*ReactionV1, error) {
+	v := new(ReactionV1)
+	if err := decodeStrict(data, v, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// MessageReadV1 mirrors the im_message.v1.message_read contract.
+//
+// Read receipt published by the message service on im_message.v1.*.message.read and routed to the sender of the messages read.
+type MessageReadV1 struct {
+	ThreadID          string  `json:"thread_id"`
+	DomainID          int32   `json:"domain_id"`
+	Reader            PeerDTO `json:"reader"`
+	LastReadMessageID string  `json:"last_read_message_id"`
+	OccurredAt        string  `json:"occurred_at"`
+}
+
+// CheckContract reports the first required field of MessageReadV1 that is missing.
+func (d *MessageReadV1) CheckContract() error {
+	if len(d.ThreadID) == 0 {
+		return missingField("thread_id")
+	}
+	if d.DomainID == 0 {
+		return missingField("domain_id")
+	}
+	if err := d.Reader.CheckContract(); err != nil {
+		return fieldErr("reader", err)
+	}
+	if len(d.LastReadMessageID) == 0 {
+		return missingField("last_read_message_id")
+	}
+	return nil
+}
+
+// DecodeMessageReadV1Strict decodes data, rejecting fields the contract does not declare.
+func DecodeMessageReadV1Strict(data []byte) (*MessageReadV1, error) {
+	v := new(MessageReadV1)
+	if err := decodeStrict(data, v, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// MessageUpdatedV1 mirrors the im_message.v1.message_updated contract.
+//
+// Message edited notification published by the message service on im_message.v1.*.message.updated: the created shape with the new body plus the edit time.
+type MessageUpdatedV1 struct {
+	MessageV1
+
+	EditedAt string `json:"edited_at"`
+}
+
+// CheckContract reports the first required field of MessageUpdatedV1 that is missing.
+func (d *MessageUpdatedV1) CheckContract() error {
+	if err := d.MessageV1.CheckContract(); err != nil {
+		return err
+	}
+	if len(d.EditedAt) == 0 {
+		return missingField("edited_at")
+	}
+	return nil
+}
+
+// DecodeMessageUpdatedV1Strict decodes data, rejecting fields the contract does not declare.
+func DecodeMessageUpdatedV1Strict(data []byte) (*MessageUpdatedV1, error) {
+	v := new(MessageUpdatedV1)
+	if err := decodeStrict(data, v, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// MessageV2 mirrors the im_message.v2.message_created contract.
+//
+// V2 message notification: the V1 shape plus free-form metadata and an optional E2EE body.
+type MessageV2 struct {
+	MessageV1
+
+	Metadata  map[string]any `json:"metadata"`
+	IsForward bool           `json:"is_forward,omitempty"`
+	Encrypted *EncryptedDTO  `json:"encrypted,omitempty"`
+}
+
+// CheckContract reports the first required field of MessageV2 that is missing.
+func (d *MessageV2) CheckContract() error {
+	if err := d.MessageV1.CheckContract(); err != nil {
+		return err
+	}
+	if d.Encrypted != nil {
+		if err := d.Encrypted.CheckContract(); err != nil {
+			return fieldErr("encrypted", err)
+		}
+	}
+	return nil
+}
+
+// DecodeMessageV2Strict decodes data, rejecting fields the contract does not declare.
+func DecodeMessageV2Strict(data []byte) (*MessageV2, error) {
+	v := new(MessageV2)
+	if err := decodeStrict(data, v, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// EncryptedDTO is the wire shape of an E2EE body. Ciphertext is base64 (decoded by encoding/json).
+type EncryptedDTO struct {
+	Ciphertext []byte           `json:"ciphertext"`
+	Algorithm  string           `json:"algorithm"`
+	KeyID      string           `json:"key_id"`
+	Envelopes  []KeyEnvelopeDTO `json:"envelopes"`
+}
+
+// CheckContract reports the first required field of EncryptedDTO that is missing.
+func (d *EncryptedDTO) CheckContract() error {
+	if len(d.Ciphertext) == 0 {
+		return missingField("ciphertext")
+	}
+	if len(d.KeyID) == 0 {
+		return missingField("key_id")
+	}
+	for i := range d.Envelopes {
+		if err := d.Envelopes[i].CheckContract(); err != nil {
+			return fieldErr("envelopes", err)
+		}
+	}
+	return nil
+}
+
+type KeyEnvelopeDTO struct {
+	RecipientID string `json:"recipient_id"`
+	WrappedKey  string `json:"wrapped_key"`
+}
+
+// CheckContract reports the first required field of KeyEnvelopeDTO that is missing.
+func (d *KeyEnvelopeDTO) CheckContract() error {
+	if len(d.RecipientID) == 0 {
+		return missingField("recipient_id")
+	}
+	if len(d.WrappedKey) == 0 {
+		return missingField("wrapped_key")
+	}
+	return nil
+}
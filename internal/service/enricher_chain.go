@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sony/gobreaker"
+	"github.com/webitel/im-delivery-service/config"
+	contactv1 "github.com/webitel/im-delivery-service/gen/go/contact/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"golang.org/x/sync/errgroup"
+)
+
+// Interface guards
+var (
+	_ Enricher = (*EnricherChain)(nil)
+	_ Enricher = (*ContactEnricher)(nil)
+	_ Enricher = (*BotEnricher)(nil)
+	_ Enricher = (*ChannelEnricher)(nil)
+	_ Enricher = (*GroupEnricher)(nil)
+)
+
+// ContactClient is the narrow contract required from the contact directory.
+type ContactClient interface {
+	SearchContact(ctx context.Context, req *contactv1.SearchContactRequest) (*contactv1.ContactList, error)
+}
+
+// BotClient is the narrow contract required from a bot directory.
+type BotClient interface {
+	LookupBot(ctx context.Context, id uuid.UUID, domainID int32) (name, sub, issuer string, err error)
+}
+
+// ChannelClient is the narrow contract required from a broadcast channel directory.
+type ChannelClient interface {
+	LookupChannel(ctx context.Context, id uuid.UUID, domainID int32) (name, sub, issuer string, err error)
+}
+
+// GroupProfile is what a group directory knows about one group.
+type GroupProfile struct {
+	Title       string
+	MemberCount int
+	AvatarURL   string
+}
+
+// GroupClient is the narrow contract required from a group (thread) directory.
+type GroupClient interface {
+	LookupGroup(ctx context.Context, id uuid.UUID, domainID int32) (GroupProfile, error)
+}
+
+// GroupMembers is the narrow contract required to expand a group thread into its members.
+type GroupMembers interface {
+	ListGroupMembers(ctx context.Context, groupID uuid.UUID, domainID int32) ([]uuid.UUID, error)
+}
+
+// EnricherChain implements [CHAIN_OF_RESPONSIBILITY] over a set of data sources.
+// Each link is tried in order; the first one returning an enriched peer wins.
+type EnricherChain struct {
+	enrichers []Enricher
+}
+
+// NewEnricherChain composes enrichers in priority order.
+func NewEnricherChain(enrichers ...Enricher) *EnricherChain {
+	return &EnricherChain{enrichers: enrichers}
+}
+
+// ResolvePeers enriches both participants concurrently through the chain.
+func (c *EnricherChain) ResolvePeers(ctx context.Context, from, to model.Peer, domainID int32) (model.Peer, model.Peer, error) {
+	return resolvePeerPair(ctx, c.ResolvePeer, from, to, domainID)
+}
+
+// ResolvePeer walks the chain until a link yields an enriched peer.
+// If no link succeeds, the original peer is returned along with the last error (if any).
+func (c *EnricherChain) ResolvePeer(ctx context.Context, peer model.Peer, domainID int32) (model.Peer, error) {
+	var lastErr error
+
+	for _, e := range c.enrichers {
+		res, err := e.ResolvePeer(ctx, peer, domainID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		// [SHORT_CIRCUIT] Stop at the first source that knows this identity.
+		if res.IsEnriched() {
+			return res, nil
+		}
+	}
+
+	return peer, lastErr
+}
+
+const (
+	// contactAvatarKey is the Contact metadata entry holding the profile picture URL.
+	contactAvatarKey = "avatar_url"
+	// contactPageSize is the most IDs sent in one SearchContact request.
+	contactPageSize = 100
+	// directoryLookupConcurrency bounds parallel lookups against directories without a bulk API.
+	directoryLookupConcurrency = 8
+)
+
+// ResolvePeersBulk hands each link only the peers no earlier link could enrich.
+func (c *EnricherChain) ResolvePeersBulk(ctx context.Context, peers []model.Peer, domainID int32) (map[uuid.UUID]model.Peer, error) {
+	pending := dedupePeers(peers)
+	res := make(map[uuid.UUID]model.Peer, len(pending))
+	for _, p := range pending {
+		res[p.ID] = p
+	}
+
+	var lastErr error
+	for _, e := range c.enrichers {
+		if len(pending) == 0 {
+			break
+		}
+		got, err := e.ResolvePeersBulk(ctx, pending, domainID)
+		if err != nil {
+			lastErr = err
+		}
+
+		next := make([]model.Peer, 0, len(pending))
+		for _, p := range pending {
+			// [SHORT_CIRCUIT] Enriched peers leave the chain.
+			if r, ok := got[p.ID]; ok && r.IsEnriched() {
+				res[p.ID] = r
+				continue
+			}
+			next = append(next, p)
+		}
+		pending = next
+	}
+
+	if len(pending) == 0 {
+		return res, nil
+	}
+	return res, lastErr
+}
+
+// Invalidate is a no-op: links hold no state; caching lives in PeerEnricher.
+func (c *EnricherChain) Invalidate(uuid.UUID, int32) {}
+
+// ContactEnricher resolves [PeerUser] identities via the Contact service.
+type ContactEnricher struct {
+	contacts ContactClient
+	timeout  time.Duration
+	breaker  *gobreaker.CircuitBreaker
+}
+
+// NewContactEnricher wraps the contact client into a chain link guarded by a
+// per-call timeout and a circuit breaker.
+func NewContactEnricher(contacts ContactClient, cfg config.EnrichmentConfig) *ContactEnricher {
+	timeout := cfg.ContactTimeout
+	if timeout <= 0 {
+		timeout = defaultContactTimeout
+	}
+	return &ContactEnricher{
+		contacts: contacts,
+		timeout:  timeout,
+		breaker:  newContactBreaker(cfg.Breaker),
+	}
+}
+
+func (e *ContactEnricher) ResolvePeers(ctx context.Context, from, to model.Peer, domainID int32) (model.Peer, model.Peer, error) {
+	return resolvePeerPair(ctx, e.ResolvePeer, from, to, domainID)
+}
+
+// ResolvePeer communicates with the gRPC Contact service.
+func (e *ContactEnricher) ResolvePeer(ctx context.Context, peer model.Peer, domainID int32) (model.Peer, error) {
+	if peer.Type != model.PeerUser || e.contacts == nil {
+		return peer, nil
+	}
+
+	res, err := e.search(ctx, &contactv1.SearchContactRequest{
+		Ids:      []string{peer.ID.String()},
+		DomainId: domainID,
+		Size:     1,
+	})
+	if err != nil {
+		// [RESILIENCE] Graceful fallback (timeout, error or open breaker): return the
+		// original peer to keep the message moving
+		return peer, nil
+	}
+
+	contacts := res.GetContacts()
+	if len(contacts) == 0 || !contactInDomain(contacts[0], domainID) {
+		return peer, nil
+	}
+
+	applyContact(&peer, contacts[0])
+	return peer, nil
+}
+
+// ResolvePeersBulk looks every user up with one SearchContact per page of IDs.
+func (e *ContactEnricher) ResolvePeersBulk(ctx context.Context, peers []model.Peer, domainID int32) (map[uuid.UUID]model.Peer, error) {
+	res := make(map[uuid.UUID]model.Peer, len(peers))
+	ids := make([]string, 0, len(peers))
+	for _, p := range peers {
+		res[p.ID] = p
+		if p.Type == model.PeerUser && p.ID != uuid.Nil {
+			ids = append(ids, p.ID.String())
+		}
+	}
+	if e.contacts == nil || len(ids) == 0 {
+		return res, nil
+	}
+
+	for chunk := range slices.Chunk(ids, contactPageSize) {
+		out, err := e.search(ctx, &contactv1.SearchContactRequest{
+			Ids:      chunk,
+			DomainId: domainID,
+			Size:     int32(len(chunk)),
+		})
+		if err != nil {
+			// [RESILIENCE] Same fallback as ResolvePeer: this page stays unenriched.
+			continue
+		}
+		for _, contact := range out.GetContacts() {
+			id, err := uuid.Parse(contact.GetId())
+			if err != nil || !contactInDomain(contact, domainID) {
+				continue
+			}
+			if peer, ok := res[id]; ok {
+				applyContact(&peer, contact)
+				res[id] = peer
+			}
+		}
+	}
+	return res, nil
+}
+
+// Invalidate is a no-op: the link reads the directory on every call.
+func (e *ContactEnricher) Invalidate(uuid.UUID, int32) {}
+
+// contactInDomain rejects a contact the directory returned for another tenant.
+// [TENANT_ISOLATION] A zero domain on the contact means the directory did not say.
+func contactInDomain(contact *contactv1.Contact, domainID int32) bool {
+	return contact.GetDomainId() == 0 || contact.GetDomainId() == domainID
+}
+
+// applyContact copies the contact's identity onto the peer.
+func applyContact(peer *model.Peer, contact *contactv1.Contact) {
+	name := contact.GetName()
+	if name == "" {
+		name = contact.GetUsername()
+	}
+
+	// [SUCCESS] Populate peer with identity data
+	peer.Name = name
+	peer.Sub = contact.GetSubject()
+	peer.Issuer = contact.GetIssId()
+	// [NO_PROTO_FIELD] Contact has no avatar field; the directory keeps it in metadata.
+	model.WithAvatar(contact.GetMetadata()[contactAvatarKey])(peer)
+}
+
+// BotEnricher resolves [PeerBot] identities via the bot directory.
+type BotEnricher struct {
+	bots BotClient
+}
+
+// NewBotEnricher wraps a bot directory client into a chain link.
+func NewBotEnricher(botClient BotClient) *BotEnricher {
+	return &BotEnricher{bots: botClient}
+}
+
+func (e *BotEnricher) ResolvePeers(ctx context.Context, from, to model.Peer, domainID int32) (model.Peer, model.Peer, error) {
+	return resolvePeerPair(ctx, e.ResolvePeer, from, to, domainID)
+}
+
+func (e *BotEnricher) ResolvePeer(ctx context.Context, peer model.Peer, domainID int32) (model.Peer, error) {
+	if peer.Type != model.PeerBot || e.bots == nil {
+		return peer, nil
+	}
+
+	name, sub, issuer, err := e.bots.LookupBot(ctx, peer.ID, domainID)
+	if err != nil {
+		return peer, fmt.Errorf("bot lookup failed: %w", err)
+	}
+
+	model.WithIdentity(sub, issuer, name)(&peer)
+	return peer, nil
+}
+
+// ResolvePeersBulk has no bulk directory call to use, so it fans ResolvePeer out with a bound.
+func (e *BotEnricher) ResolvePeersBulk(ctx context.Context, peers []model.Peer, domainID int32) (map[uuid.UUID]model.Peer, error) {
+	return resolveEach(ctx, e.ResolvePeer, peers, domainID)
+}
+
+// Invalidate is a no-op: the link reads the directory on every call.
+func (e *BotEnricher) Invalidate(uuid.UUID, int32) {}
+
+// ChannelEnricher resolves [PeerChannel] identities via the channel directory.
+type ChannelEnricher struct {
+	channels ChannelClient
+}
+
+// NewChannelEnricher wraps a channel directory client into a chain link.
+func NewChannelEnricher(channelClient ChannelClient) *ChannelEnricher {
+	return &ChannelEnricher{channels: channelClient}
+}
+
+func (e *ChannelEnricher) ResolvePeers(ctx context.Context, from, to model.Peer, domainID int32) (model.Peer, model.Peer, error) {
+	return resolvePeerPair(ctx, e.ResolvePeer, from, to, domainID)
+}
+
+func (e *ChannelEnricher) ResolvePeer(ctx context.Context, peer model.Peer, domainID int32) (model.Peer, error) {
+	if peer.Type != model.PeerChannel || e.channels == nil {
+		return peer, nil
+	}
+
+	name, sub, issuer, err := e.channels.LookupChannel(ctx, peer.ID, domainID)
+	if err != nil {
+		return peer, fmt.Errorf("channel lookup failed: %w", err)
+	}
+
+	model.WithIdentity(sub, issuer, name)(&peer)
+	return peer, nil
+}
+
+// ResolvePeersBulk has no bulk directory call to use, so it fans ResolvePeer out with a bound.
+func (e *ChannelEnricher) ResolvePeersBulk(ctx context.Context, peers []model.Peer, domainID int32) (map[uuid.UUID]model.Peer, error) {
+	return resolveEach(ctx, e.ResolvePeer, peers, domainID)
+}
+
+// Invalidate is a no-op: the link reads the directory on every call.
+func (e *ChannelEnricher) Invalidate(uuid.UUID, int32) {}
+
+// GroupEnricher resolves [PeerGroup] profiles via the group directory.
+type GroupEnricher struct {
+	groups GroupClient
+}
+
+// NewGroupEnricher wraps a group directory client into a chain link.
+func NewGroupEnricher(groupClient GroupClient) *GroupEnricher {
+	return &GroupEnricher{groups: groupClient}
+}
+
+func (e *GroupEnricher) ResolvePeers(ctx context.Context, from, to model.Peer, domainID int32) (model.Peer, model.Peer, error) {
+	return resolvePeerPair(ctx, e.ResolvePeer, from, to, domainID)
+}
+
+func (e *GroupEnricher) ResolvePeer(ctx context.Context, peer model.Peer, domainID int32) (model.Peer, error) {
+	if peer.Type != model.PeerGroup || e.groups == nil {
+		return peer, nil
+	}
+
+	g, err := e.groups.LookupGroup(ctx, peer.ID, domainID)
+	if err != nil || g.Title == "" {
+		// [RESILIENCE] Same fallback as contacts: the message moves on unenriched.
+		return peer, nil
+	}
+
+	// [WIRE_IDENTITY] Groups have no external subject; the chat ID is what clients address.
+	model.WithIdentity(peer.ID.String(), "", g.Title)(&peer)
+	model.WithAvatar(g.AvatarURL)(&peer)
+	peer.MemberCount = g.MemberCount
+	return peer, nil
+}
+
+// ResolvePeersBulk has no bulk directory call to use, so it fans ResolvePeer out with a bound.
+func (e *GroupEnricher) ResolvePeersBulk(ctx context.Context, peers []model.Peer, domainID int32) (map[uuid.UUID]model.Peer, error) {
+	return resolveEach(ctx, e.ResolvePeer, peers, domainID)
+}
+
+// Invalidate is a no-op: the link reads the directory on every call.
+func (e *GroupEnricher) Invalidate(uuid.UUID, int32) {}
+
+// resolvePeerPair executes parallel enrichment flows for 'from' and 'to' peers.
+// [CONCURRENCY_OPTIMIZATION] Uses errgroup to ensure both lookups complete or fail together.
+func resolvePeerPair(
+	ctx context.Context,
+	resolve func(context.Context, model.Peer, int32) (model.Peer, error),
+	from, to model.Peer,
+	domainID int32,
+) (model.Peer, model.Peer, error) {
+	g, gCtx := errgroup.WithContext(ctx)
+
+	// Clone peers to avoid side effects during concurrent execution
+	resFrom := from
+	resTo := to
+
+	g.Go(func() error {
+		var err error
+		resFrom, err = resolve(gCtx, from, domainID)
+		return err
+	})
+
+	g.Go(func() error {
+		var err error
+		resTo, err = resolve(gCtx, to, domainID)
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		return from, to, fmt.Errorf("parallel enrichment failed: %w", err)
+	}
+
+	return resFrom, resTo, nil
+}
+
+// resolveEach runs a single-peer resolver over many peers with bounded concurrency.
+// Failed lookups keep the original peer; their errors are joined.
+func resolveEach(
+	ctx context.Context,
+	resolve func(context.Context, model.Peer, int32) (model.Peer, error),
+	peers []model.Peer,
+	domainID int32,
+) (map[uuid.UUID]model.Peer, error) {
+	var (
+		mu   sync.Mutex
+		errs []error
+		g    errgroup.Group
+	)
+	res := make(map[uuid.UUID]model.Peer, len(peers))
+	g.SetLimit(directoryLookupConcurrency)
+
+	for _, p := range peers {
+		g.Go(func() error {
+			r, err := resolve(ctx, p, domainID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				r = p
+			}
+			res[p.ID] = r
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return res, errors.Join(errs...)
+}
+
+// dedupePeers drops nil IDs and repeated peers, keeping the first occurrence.
+func dedupePeers(peers []model.Peer) []model.Peer {
+	seen := make(map[uuid.UUID]struct{}, len(peers))
+	res := make([]model.Peer, 0, len(peers))
+	for _, p := range peers {
+		if p.ID == uuid.Nil {
+			continue
+		}
+		if _, dup := seen[p.ID]; dup {
+			continue
+		}
+		seen[p.ID] = struct{}{}
+		res = append(res, p)
+	}
+	return res
+}
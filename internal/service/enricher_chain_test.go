@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+type fakeGroupDirectory struct {
+	group   GroupProfile
+	channel [3]string // name, sub, issuer
+	err     error
+}
+
+func (f fakeGroupDirectory) LookupGroup(context.Context, uuid.UUID, int32) (GroupProfile, error) {
+	return f.group, f.err
+}
+
+func (f fakeGroupDirectory) LookupChannel(context.Context, uuid.UUID, int32) (string, string, string, error) {
+	return f.channel[0], f.channel[1], f.channel[2], f.err
+}
+
+func TestGroupAndChannelEnrichment(t *testing.T) {
+	groupID, channelID := uuid.New(), uuid.New()
+	found := fakeGroupDirectory{
+		group:   GroupProfile{Title: "Team", MemberCount: 12, AvatarURL: "https://cdn/team.png"},
+		channel: [3]string{"News", "news", "iss"},
+	}
+	tests := []struct {
+		name      string
+		directory *fakeGroupDirectory // nil: no groups service deployed
+		peer      model.Peer
+		want      model.Peer
+	}{
+		{
+			name:      "group",
+			directory: &found,
+			peer:      model.Peer{ID: groupID, Type: model.PeerGroup},
+			want:      model.Peer{ID: groupID, Type: model.PeerGroup, Sub: groupID.String(), Name: "Team", AvatarURL: "https://cdn/team.png", MemberCount: 12},
+		},
+		{
+			name:      "channel",
+			directory: &found,
+			peer:      model.Peer{ID: channelID, Type: model.PeerChannel},
+			want:      model.Peer{ID: channelID, Type: model.PeerChannel, Sub: "news", Issuer: "iss", Name: "News"},
+		},
+		{
+			name:      "directory down falls back to the bare peer",
+			directory: &fakeGroupDirectory{err: errors.New("unavailable")},
+			peer:      model.Peer{ID: groupID, Type: model.PeerGroup},
+			want:      model.Peer{ID: groupID, Type: model.PeerGroup},
+		},
+		{
+			name: "no groups service",
+			peer: model.Peer{ID: groupID, Type: model.PeerGroup},
+			want: model.Peer{ID: groupID, Type: model.PeerGroup},
+		},
+		{
+			name:      "users are left to the contact link",
+			directory: &found,
+			peer:      model.Peer{ID: groupID, Type: model.PeerUser},
+			want:      model.Peer{ID: groupID, Type: model.PeerUser},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// [OPTIONAL_SOURCES] Mirrors fx leaving an unprovided client as a nil interface.
+			var groups GroupClient
+			var channels ChannelClient
+			if tt.directory != nil {
+				groups, channels = *tt.directory, *tt.directory
+			}
+			chain := NewEnricherChain(NewChannelEnricher(channels), NewGroupEnricher(groups))
+
+			got, _ := chain.ResolvePeer(context.Background(), tt.peer, 1)
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/webitel/im-delivery-service/internal/domain/model"
 )
 
@@ -39,11 +40,13 @@ func (m *EnricherMiddleware) ResolvePeers(ctx context.Context, from, to model.Pe
 			"from_id", from.ID,
 			"to_id", to.ID,
 			"duration_ms", duration.Milliseconds(),
+			"contact_breaker", m.breakerState(),
 		)
 	} else {
 		m.Logger.Debug("PEER_ENRICHMENT_BATCH_COMPLETED",
 			"duration_ms", duration.Milliseconds(),
 			"domain_id", domainID,
+			"contact_breaker", m.breakerState(),
 		)
 	}
 
@@ -61,8 +64,78 @@ func (m *EnricherMiddleware) ResolvePeer(ctx context.Context, peer model.Peer, d
 			"peer_type", peer.Type,
 			"err", err,
 			"duration_ms", time.Since(start).Milliseconds(),
+			"contact_breaker", m.breakerState(),
 		)
 	}
 
 	return res, err
 }
+
+// ResolvePeersBulk wraps a bulk lookup with timing and the share served from the cache.
+func (m *EnricherMiddleware) ResolvePeersBulk(ctx context.Context, peers []model.Peer, domainID int32) (map[uuid.UUID]model.Peer, error) {
+	start := time.Now()
+
+	st := &bulkStats{}
+	res, err := m.Next.ResolvePeersBulk(withBulkStats(ctx, st), peers, domainID)
+
+	attrs := []any{
+		"requested", len(peers),
+		"unique", st.peers,
+		"cache_hits", st.cacheHits,
+		"domain_id", domainID,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"contact_breaker", m.breakerState(),
+	}
+	if st.peers > 0 {
+		attrs = append(attrs, "cache_hit_ratio", float64(st.cacheHits)/float64(st.peers))
+	}
+
+	if err != nil {
+		m.Logger.Warn("PEER_ENRICHMENT_BULK_PARTIAL", append(attrs, "err", err)...)
+	} else {
+		m.Logger.Debug("PEER_ENRICHMENT_BULK_COMPLETED", attrs...)
+	}
+
+	return res, err
+}
+
+// Invalidate forwards the eviction and records it for cache auditing.
+func (m *EnricherMiddleware) Invalidate(contactID uuid.UUID, domainID int32) {
+	m.Next.Invalidate(contactID, domainID)
+	m.Logger.Debug("PEER_CACHE_INVALIDATED", "contact_id", contactID, "domain_id", domainID)
+}
+
+// Prime forwards a bus-delivered profile to a caching layer that supports it.
+func (m *EnricherMiddleware) Prime(peer model.Peer, domainID int32) {
+	if p, ok := m.Next.(interface{ Prime(model.Peer, int32) }); ok {
+		p.Prime(peer, domainID)
+		return
+	}
+	m.Next.Invalidate(peer.ID, domainID)
+}
+
+// breakerState reports the contact breaker of the decorated enricher, if it has one.
+func (m *EnricherMiddleware) breakerState() string {
+	if b, ok := m.Next.(interface{ BreakerState() string }); ok {
+		return b.BreakerState()
+	}
+	return "none"
+}
+
+// bulkStats is filled by the caching layer so the decorator can report hit ratios
+// without widening the Enricher contract.
+type bulkStats struct {
+	peers     int
+	cacheHits int
+}
+
+type bulkStatsKey struct{}
+
+func withBulkStats(ctx context.Context, st *bulkStats) context.Context {
+	return context.WithValue(ctx, bulkStatsKey{}, st)
+}
+
+func bulkStatsFrom(ctx context.Context) *bulkStats {
+	st, _ := ctx.Value(bulkStatsKey{}).(*bulkStats)
+	return st
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// EnrichmentStep transforms an event before delivery. Returning a nil event drops it.
+type EnrichmentStep func(ctx context.Context, ev event.Eventer, domainID int32) (event.Eventer, error)
+
+// EnrichmentPipeline runs the steps registered for an event kind in registration order.
+// [EXTENSION_POINT] Each concern (peers, avatars, metadata) is an independent step
+// registered at module start instead of living inside one listener.
+type EnrichmentPipeline struct {
+	mu    sync.RWMutex
+	steps map[event.EventKind][]EnrichmentStep
+}
+
+func NewEnrichmentPipeline() *EnrichmentPipeline {
+	return &EnrichmentPipeline{steps: make(map[event.EventKind][]EnrichmentStep)}
+}
+
+// AddStep appends a step for the given kind.
+func (p *EnrichmentPipeline) AddStep(kind event.EventKind, step EnrichmentStep) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.steps[kind] = append(p.steps[kind], step)
+}
+
+// Run executes every step for ev.GetKind(); the first error aborts the pipeline.
+// [E2EE] Encrypted events bypass every step: the sender's device embedded the names
+// before encryption and the service must not touch the envelope.
+func (p *EnrichmentPipeline) Run(ctx context.Context, ev event.Eventer, domainID int32) (event.Eventer, error) {
+	if ev.IsEncrypted() {
+		return ev, nil
+	}
+
+	p.mu.RLock()
+	steps := p.steps[ev.GetKind()]
+	p.mu.RUnlock()
+
+	for i, step := range steps {
+		next, err := step(ctx, ev, domainID)
+		if err != nil {
+			return nil, fmt.Errorf("enrichment step %d for %s: %w", i, ev.GetKind(), err)
+		}
+		if next == nil {
+			return nil, nil // [FILTERED] A step decided the event must not be delivered.
+		}
+		ev = next
+	}
+	return ev, nil
+}
+
+// PeerEnrichmentStep resolves the From/To profiles of a message payload.
+func PeerEnrichmentStep(enricher Enricher) EnrichmentStep {
+	return func(ctx context.Context, ev event.Eventer, domainID int32) (event.Eventer, error) {
+		msg, ok := ev.GetPayload().(*model.Message)
+		if !ok {
+			return ev, nil
+		}
+
+		from, to, err := enricher.ResolvePeers(ctx, msg.From, msg.To, domainID)
+		if err != nil {
+			return nil, err
+		}
+		msg.From, msg.To = from, to
+		return ev, nil
+	}
+}
+
+// ReactionActorStep resolves the profile of the peer who reacted, so clients can
+// render the actor's name without a lookup of their own.
+func ReactionActorStep(enricher Enricher) EnrichmentStep {
+	return func(ctx context.Context, ev event.Eventer, domainID int32) (event.Eventer, error) {
+		r, ok := ev.GetPayload().(*model.Reaction)
+		if !ok {
+			return ev, nil
+		}
+
+		actor, err := enricher.ResolvePeer(ctx, r.Actor, domainID)
+		if err != nil {
+			return nil, err
+		}
+		r.Actor = actor
+		return ev, nil
+	}
+}
+
+// RegisterDefaultEnrichment wires the built-in steps.
+func RegisterDefaultEnrichment(p *EnrichmentPipeline, enricher Enricher) {
+	p.AddStep(event.MessageCreated, PeerEnrichmentStep(enricher))
+	// [EDITS] An edited payload replaces the client's copy, so it needs the same names.
+	p.AddStep(event.MessageUpdated, PeerEnrichmentStep(enricher))
+	p.AddStep(event.MessageReaction, ReactionActorStep(enricher))
+}
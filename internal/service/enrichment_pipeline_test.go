@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+// TestEnrichmentSkipsEncryptedMessages checks that no step, body-dependent or not,
+// sees an E2EE message, while plain messages run every registered step.
+func TestEnrichmentSkipsEncryptedMessages(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       *model.Message
+		wantSteps int
+	}{
+		{name: "plain", msg: &model.Message{Text: "hi"}, wantSteps: 2},
+		{
+			name: "encrypted",
+			msg: &model.Message{Encrypted: &model.EncryptedContent{
+				Ciphertext: []byte{1, 2, 3}, KeyID: "k1",
+				Envelopes: []model.KeyEnvelope{{RecipientID: uuid.New(), WrappedKey: "w"}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := 0
+			// A body-rewriting step (masking, previews) must never reach the ciphertext.
+			rewrite := func(_ context.Context, ev event.Eventer, _ int32) (event.Eventer, error) {
+				steps++
+				msg := ev.GetPayload().(*model.Message)
+				msg.Text = "[masked]"
+				if msg.Encrypted != nil {
+					msg.Encrypted.Ciphertext = nil
+				}
+				return ev, nil
+			}
+			p := NewEnrichmentPipeline()
+			p.AddStep(event.MessageCreated, rewrite)
+			p.AddStep(event.MessageCreated, rewrite)
+
+			ev := event.NewMessageV1Event(tt.msg, uuid.New(), model.Peer{}, model.Peer{})
+			got, err := p.Run(context.Background(), ev, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if steps != tt.wantSteps {
+				t.Fatalf("ran %d steps, want %d", steps, tt.wantSteps)
+			}
+			if msg := got.GetPayload().(*model.Message); msg.IsEncrypted() && (msg.Text != "" || len(msg.Encrypted.Ciphertext) != 3) {
+				t.Fatalf("encrypted body touched: %+v", msg)
+			}
+		})
+	}
+}
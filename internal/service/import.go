@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// ImportFormatV1 is the only accepted version of the migration export format.
+const ImportFormatV1 = 1
+
+var (
+	ErrImportUnsupportedVersion = errors.New("unsupported export format version")
+	ErrImportMissingUser        = errors.New("user_id is required")
+	ErrImportMissingMessage     = errors.New("message with id is required")
+	ErrImportSpillFull          = errors.New("offline spill limit reached for user")
+)
+
+// ImportRecord is one pre-enriched historical notification from the legacy export.
+type ImportRecord struct {
+	Version int            `json:"version"`
+	UserID  uuid.UUID      `json:"user_id"`
+	Message *model.Message `json:"message"` // From/To are expected to be enriched already
+}
+
+// ImportRejection explains why a record was not accepted.
+type ImportRejection struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// ImportSummary reports the outcome of one batch.
+type ImportSummary struct {
+	Accepted      int               `json:"accepted"`
+	DeliveredLive int               `json:"delivered_live"`
+	Stored        int               `json:"stored"`
+	Rejected      []ImportRejection `json:"rejected,omitempty"`
+}
+
+// EventImporter injects migrated history into live delivery.
+//
+// [STRATEGY]
+// Records bypass enrichment and are marked Imported, which lowers their priority and
+// suppresses re-publication. Connected users receive them through the normal Hub path;
+// for everyone else they are spilled in memory and flushed on the user's first Subscribe.
+type EventImporter struct {
+	hub registry.Hubber
+	cfg *config.Config
+
+	mu    sync.Mutex
+	spill map[uuid.UUID][]event.Eventer
+}
+
+func NewEventImporter(hub registry.Hubber, cfg *config.Config) *EventImporter {
+	return &EventImporter{hub: hub, cfg: cfg, spill: make(map[uuid.UUID][]event.Eventer)}
+}
+
+// Import processes a batch, honouring the configured rate. It stops early if ctx ends.
+func (i *EventImporter) Import(ctx context.Context, records []ImportRecord) (*ImportSummary, error) {
+	summary := &ImportSummary{}
+
+	var tick <-chan time.Time
+	if rate := i.cfg.Delivery.Import.RatePerSecond; rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for idx, rec := range records {
+		// [THROTTLE] Migration traffic must not crowd out live delivery.
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				return summary, ctx.Err()
+			}
+		}
+
+		if err := validateImportRecord(rec); err != nil {
+			summary.Rejected = append(summary.Rejected, ImportRejection{Index: idx, Reason: err.Error()})
+			continue
+		}
+
+		ev := event.NewMessageV1Event(rec.Message, rec.UserID, rec.Message.From, rec.Message.To)
+		ev.Imported = true
+
+		if i.hub.BroadcastIfConnected(ev).Queued {
+			summary.Accepted++
+			summary.DeliveredLive++
+			continue
+		}
+
+		if err := i.store(rec.UserID, ev); err != nil {
+			summary.Rejected = append(summary.Rejected, ImportRejection{Index: idx, Reason: err.Error()})
+			continue
+		}
+		summary.Accepted++
+		summary.Stored++
+	}
+
+	return summary, nil
+}
+
+// store spills an event for an offline user, bounded per user.
+func (i *EventImporter) store(userID uuid.UUID, ev event.Eventer) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if limit := i.cfg.Delivery.Import.SpillPerUser; limit > 0 && len(i.spill[userID]) >= limit {
+		return ErrImportSpillFull
+	}
+	i.spill[userID] = append(i.spill[userID], ev)
+	return nil
+}
+
+// Flush hands spilled events for a newly connected user to the Hub.
+// [ORDER] Called after the connector is attached, so the events are sequenced like live ones.
+func (i *EventImporter) Flush(userID uuid.UUID) {
+	i.mu.Lock()
+	pending := i.spill[userID]
+	delete(i.spill, userID)
+	i.mu.Unlock()
+
+	for _, ev := range pending {
+		i.hub.Broadcast(ev)
+	}
+}
+
+func validateImportRecord(rec ImportRecord) error {
+	switch {
+	case rec.Version != ImportFormatV1:
+		return fmt.Errorf("%w: %d", ErrImportUnsupportedVersion, rec.Version)
+	case rec.UserID == uuid.Nil:
+		return ErrImportMissingUser
+	case rec.Message == nil || rec.Message.ID == uuid.Nil:
+		return ErrImportMissingMessage
+	}
+	return nil
+}
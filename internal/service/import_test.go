@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+func importRecord(userID uuid.UUID) ImportRecord {
+	return ImportRecord{Version: ImportFormatV1, UserID: userID, Message: &model.Message{ID: uuid.New(), Text: "legacy"}}
+}
+
+func TestValidateImportRecord(t *testing.T) {
+	user := uuid.New()
+	tests := []struct {
+		name    string
+		mutate  func(r *ImportRecord)
+		wantErr error
+	}{
+		{name: "valid", mutate: func(*ImportRecord) {}},
+		{name: "unknown version", mutate: func(r *ImportRecord) { r.Version = 2 }, wantErr: ErrImportUnsupportedVersion},
+		{name: "missing version", mutate: func(r *ImportRecord) { r.Version = 0 }, wantErr: ErrImportUnsupportedVersion},
+		{name: "missing user", mutate: func(r *ImportRecord) { r.UserID = uuid.Nil }, wantErr: ErrImportMissingUser},
+		{name: "missing message", mutate: func(r *ImportRecord) { r.Message = nil }, wantErr: ErrImportMissingMessage},
+		{name: "message without id", mutate: func(r *ImportRecord) { r.Message.ID = uuid.Nil }, wantErr: ErrImportMissingMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := importRecord(user)
+			tt.mutate(&rec)
+			if err := validateImportRecord(rec); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateImportRecord = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// unwrapEvent strips delivery wrappers (sequencing, replay) down to the domain event.
+func unwrapEvent(ev event.Eventer) event.Eventer {
+	for {
+		w, ok := ev.(event.Wrapper)
+		if !ok {
+			return ev
+		}
+		ev = w.Unwrap()
+	}
+}
+
+// TestImportLiveVsStored checks the split between connected and offline users, the
+// spill bound, and that spilled records reach the user on first connect.
+func TestImportLiveVsStored(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Delivery.Import.SpillPerUser = 2
+	s, hub := newTestDeliveryService(t, cfg)
+
+	online, offline := uuid.New(), uuid.New()
+	conn := registry.NewConnector(context.Background(), online, 16, registry.ConnectMetadata{})
+	hub.Register(conn)
+
+	invalid := importRecord(offline)
+	invalid.Version = 0
+	records := []ImportRecord{
+		importRecord(online), importRecord(online),
+		importRecord(offline), importRecord(offline),
+		importRecord(offline), // Over the spill bound
+		invalid,
+	}
+	summary, err := s.importer.Import(t.Context(), records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ImportSummary{Accepted: 4, DeliveredLive: 2, Stored: 2}
+	if summary.Accepted != want.Accepted || summary.DeliveredLive != want.DeliveredLive || summary.Stored != want.Stored {
+		t.Fatalf("summary = %+v, want %+v", summary, want)
+	}
+	if len(summary.Rejected) != 2 || summary.Rejected[0].Index != 4 || summary.Rejected[1].Index != 5 {
+		t.Fatalf("rejected = %+v, want indexes 4 and 5", summary.Rejected)
+	}
+
+	assertImported := func(conn registry.Connector, n int) {
+		t.Helper()
+		for range n {
+			select {
+			case ev := <-conn.Recv():
+				msg, ok := unwrapEvent(ev).(*event.MessageV1Event)
+				if !ok || !msg.Imported {
+					t.Fatalf("received %T, want an imported message", ev)
+				}
+				// [SUPPRESSION] Imported history is low priority and never re-published.
+				if msg.GetPriority() != event.PriorityLow || msg.GetRoutingKey() != "" {
+					t.Fatalf("imported event priority %v, routing key %q", msg.GetPriority(), msg.GetRoutingKey())
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("imported event never arrived")
+			}
+		}
+	}
+	assertImported(conn, 2)
+
+	late, err := s.Subscribe(t.Context(), offline, SubscribeOptions{Transport: TransportWS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Unsubscribe(late.GetUserID(), late.GetID()) })
+	assertImported(late, 2)
+}
+
+func TestImportRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     int
+		records  int
+		deadline time.Duration // Context deadline; 0 for none
+		want     time.Duration // Elapsed (fake) time
+		wantErr  error
+	}{
+		{name: "unbounded", rate: 0, records: 1000, want: 0},
+		{name: "bounded", rate: 100, records: 1000, want: 10 * time.Second},
+		{name: "cancelled mid-batch", rate: 100, records: 1000, deadline: 2 * time.Second, want: 2 * time.Second, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				hub := registry.NewHub()
+				defer hub.Shutdown()
+				cfg := &config.Config{}
+				cfg.Delivery.Import.RatePerSecond = tt.rate
+				importer := NewEventImporter(hub, cfg)
+
+				ctx := t.Context()
+				if tt.deadline > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+					defer cancel()
+				}
+
+				records := make([]ImportRecord, tt.records)
+				for i := range records {
+					records[i] = importRecord(uuid.New())
+				}
+				start := time.Now()
+				summary, err := importer.Import(ctx, records)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Import error = %v, want %v", err, tt.wantErr)
+				}
+				if got := time.Since(start); got != tt.want {
+					t.Fatalf("batch took %v, want %v", got, tt.want)
+				}
+				if tt.wantErr == nil && summary.Stored != tt.records {
+					t.Fatalf("stored %d records, want %d", summary.Stored, tt.records)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meter is the [OBSERVABILITY] entry point for service-level instruments.
+// Instruments are no-ops until the OTel SDK installs a global MeterProvider.
+var meter = otel.Meter("github.com/webitel/im-delivery-service/internal/service")
+
+// bufferPeakOccupancy records the highest connector buffer depth seen per session,
+// letting operators tune the buffer derivation table per transport.
+var bufferPeakOccupancy, _ = meter.Int64Histogram(
+	"im_delivery_connector_buffer_peak",
+	metric.WithDescription("Peak connector buffer occupancy observed during a session"),
+	metric.WithUnit("{event}"),
+)
+
+// stuckWrites counts transport writes that breached their deadline or had to be
+// aborted by a watchdog, so wedged peers are told apart from generic slow consumers.
+var stuckWrites, _ = meter.Int64Counter(
+	"im_delivery_transport_stuck_writes_total",
+	metric.WithDescription("Transport writes aborted by a write deadline or watchdog"),
+)
+
+// Stuck-write causes.
+const (
+	StuckWriteDeadline = "deadline"
+	StuckWriteWatchdog = "watchdog"
+)
+
+// RecordStuckWrite reports an aborted write for the given transport.
+func RecordStuckWrite(ctx context.Context, transport Transport, cause string) {
+	stuckWrites.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("transport", string(transport)),
+		attribute.String("cause", cause),
+	))
+}
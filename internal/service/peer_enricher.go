@@ -2,14 +2,13 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/google/uuid"
-	lru "github.com/hashicorp/golang-lru/v2"
-	contactv1 "github.com/webitel/im-delivery-service/gen/go/contact/v1"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/webitel/im-delivery-service/config"
 	imcontact "github.com/webitel/im-delivery-service/infra/client/im-contact"
 	"github.com/webitel/im-delivery-service/internal/domain/model"
-	"golang.org/x/sync/errgroup"
 )
 
 // Enricher defines the high-level contract for participant data augmentation.
@@ -18,53 +17,104 @@ type Enricher interface {
 	ResolvePeers(ctx context.Context, from, to model.Peer, domainID int32) (model.Peer, model.Peer, error)
 	// ResolvePeer handles the logic for a single participant based on their type.
 	ResolvePeer(ctx context.Context, peer model.Peer, domainID int32) (model.Peer, error)
+	// ResolvePeersBulk enriches many participants at once, keyed by peer ID.
+	// Peers no source knows come back unchanged; the map covers every non-nil input ID.
+	ResolvePeersBulk(ctx context.Context, peers []model.Peer, domainID int32) (map[uuid.UUID]model.Peer, error)
+	// Invalidate drops whatever is cached for the contact in that domain.
+	Invalidate(contactID uuid.UUID, domainID int32)
 }
 
 type PeerEnricher struct {
-	contacts *imcontact.Client
-	cache    *lru.Cache[string, model.Peer]
+	chain    *EnricherChain
+	contacts *ContactEnricher
+	cache    *expirable.LRU[peerCacheKey, model.Peer]
+
+	// [NEGATIVE_CACHE] Failed or empty lookups, kept briefly so ghosts and outages
+	// do not hammer the directories yet recover soon after. Nil when disabled.
+	misses *expirable.LRU[peerCacheKey, model.Peer]
 }
 
-// NewPeerEnricherService provides a thread-safe service with an internal LRU cache.
-func NewPeerEnricherService(contacts *imcontact.Client) *PeerEnricher {
-	// [MEMORY_MANAGEMENT] Pre-allocated LRU cache to minimize GC pressure and store "hot" identities.
-	cache, _ := lru.New[string, model.Peer](10000)
+// NewPeerEnricherService provides a thread-safe service with internal expiring LRU caches.
+// Bot, channel and group clients are optional: without them those peer types pass through unenriched.
+func NewPeerEnricherService(cfg *config.Config, contacts *imcontact.Client, bots BotClient, channels ChannelClient, groups GroupClient) *PeerEnricher {
+	c := cfg.Enrichment
+	size := c.CacheSize
+	if size <= 0 {
+		size = defaultPeerCacheSize
+	}
+	ttl := c.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultPeerCacheTTL
+	}
 
-	return &PeerEnricher{
-		contacts: contacts,
-		cache:    cache,
+	// [OPTIONAL_SOURCES] A missing contact client must stay a nil interface.
+	var contactClient ContactClient
+	if contacts != nil {
+		contactClient = contacts
+	}
+	contactLink := NewContactEnricher(contactClient, c)
+	e := &PeerEnricher{
+		// [POLYMORPHIC_DISPATCH] Each link only handles its own PeerType and skips the rest.
+		chain: NewEnricherChain(
+			contactLink,
+			NewBotEnricher(bots),
+			NewChannelEnricher(channels),
+			NewGroupEnricher(groups),
+		),
+		contacts: contactLink,
+		// [FRESHNESS] Profiles expire so renames reach clients without a restart.
+		cache: expirable.NewLRU[peerCacheKey, model.Peer](size, nil, ttl),
 	}
+	if c.NegativeTTL > 0 {
+		e.misses = expirable.NewLRU[peerCacheKey, model.Peer](size, nil, c.NegativeTTL)
+	}
+	return e
 }
 
-// ResolvePeers executes parallel enrichment flows for 'from' and 'to' peers.
-// [CONCURRENCY_OPTIMIZATION] Uses errgroup to ensure both lookups complete or fail together.
-func (e *PeerEnricher) ResolvePeers(ctx context.Context, from, to model.Peer, domainID int32) (model.Peer, model.Peer, error) {
-	g, gCtx := errgroup.WithContext(ctx)
-
-	// Clone peers to avoid side effects during concurrent execution
-	resFrom := from
-	resTo := to
+const (
+	defaultPeerCacheSize = 10000
+	defaultPeerCacheTTL  = 5 * time.Minute
+)
 
-	g.Go(func() error {
-		var err error
-		resFrom, err = e.ResolvePeer(gCtx, from, domainID)
-		return err
-	})
+// peerCacheKey scopes cached profiles to the tenant they were looked up in.
+// [TENANT_ISOLATION] Directory lookups are domain-scoped, so a profile resolved for
+// one domain must never answer for another, even for the same peer UUID.
+type peerCacheKey struct {
+	domainID int32
+	id       uuid.UUID
+}
 
-	g.Go(func() error {
-		var err error
-		resTo, err = e.ResolvePeer(gCtx, to, domainID)
-		return err
-	})
+// lookup checks the resolved cache, then the negative one.
+func (e *PeerEnricher) lookup(key peerCacheKey) (model.Peer, bool) {
+	if p, ok := e.cache.Get(key); ok {
+		return p, true
+	}
+	if e.misses != nil {
+		return e.misses.Get(key)
+	}
+	return model.Peer{}, false
+}
 
-	if err := g.Wait(); err != nil {
-		return from, to, fmt.Errorf("parallel enrichment failed: %w", err)
+// remember files the outcome of a lookup: enriched peers in the main cache, anything
+// else (a link's degraded fallback, unknown contact) in the short-lived negative cache.
+// [NO_ERROR_CACHING] Callers skip it for lookups that failed with an error: the error
+// NACKs the message, and a cached fallback would turn the retry into a silent success.
+func (e *PeerEnricher) remember(key peerCacheKey, p model.Peer) {
+	if p.IsEnriched() {
+		e.cache.Add(key, p)
+		return
+	}
+	if e.misses != nil {
+		e.misses.Add(key, p)
 	}
+}
 
-	return resFrom, resTo, nil
+// ResolvePeers executes parallel enrichment flows for 'from' and 'to' peers.
+func (e *PeerEnricher) ResolvePeers(ctx context.Context, from, to model.Peer, domainID int32) (model.Peer, model.Peer, error) {
+	return resolvePeerPair(ctx, e.ResolvePeer, from, to, domainID)
 }
 
-// ResolvePeer orchestrates the cache-aside strategy and polymorphic dispatching.
+// ResolvePeer orchestrates the cache-aside strategy in front of the enricher chain.
 func (e *PeerEnricher) ResolvePeer(ctx context.Context, peer model.Peer, domainID int32) (model.Peer, error) {
 	// [IDENTITY_GUARD] Ensure we have a valid ID before proceeding
 	if peer.ID == uuid.Nil {
@@ -72,76 +122,76 @@ func (e *PeerEnricher) ResolvePeer(ctx context.Context, peer model.Peer, domainI
 	}
 
 	// [HOT_PATH] Check LRU cache first to avoid unnecessary network/logic overhead
-	cacheKey := peer.ID.String()
-	if cached, ok := e.cache.Get(cacheKey); ok {
+	cacheKey := peerCacheKey{domainID: domainID, id: peer.ID}
+	if cached, ok := e.lookup(cacheKey); ok {
 		return cached, nil
 	}
 
-	var enriched model.Peer
-	var err error
-
-	// [POLYMORPHIC_DISPATCH] Route enrichment logic based on PeerType
-	switch peer.Type {
-	case model.PeerUser:
-		// [EXTERNAL_GRPC_CALL] Fetch data from Contact Service
-		enriched, err = e.enrichFromContacts(ctx, peer, domainID)
-
-	case model.PeerGroup:
-		// [STUB] Future logic for Chat Groups/Rooms metadata
-		enriched = e.mockEnrich(peer, "Peer Group")
-
-	case model.PeerChannel:
-		// [STUB] Future logic for Broadcast Channels
-		enriched = e.mockEnrich(peer, "Peer Channel")
-
-	default:
-		// [FALLBACK] Return original peer if type is unknown or doesn't require enrichment
-		enriched = peer
+	enriched, err := e.chain.ResolvePeer(ctx, peer, domainID)
+	if err != nil {
+		return enriched, err
 	}
 
-	// [CACHE_POPULATION] Save successful result (even if it's a fallback)
-	if err == nil {
-		e.cache.Add(cacheKey, enriched)
-	}
+	// [CACHE_POPULATION] Fallbacks go to the negative cache, so they expire quickly.
+	e.remember(cacheKey, enriched)
 
-	return enriched, err
+	return enriched, nil
 }
 
-// enrichFromContacts communicates with the gRPC Contact service.
-func (e *PeerEnricher) enrichFromContacts(ctx context.Context, peer model.Peer, domainID int32) (model.Peer, error) {
-	res, err := e.contacts.SearchContact(ctx, &contactv1.SearchContactRequest{
-		Ids:      []string{peer.ID.String()},
-		DomainId: domainID,
-		Size:     1,
-	})
-	if err != nil {
-		// [RESILIENCE] Graceful fallback: return original peer to keep the message moving
-		return peer, nil
+// ResolvePeersBulk serves what it can from the LRU and sends only the misses down the chain.
+func (e *PeerEnricher) ResolvePeersBulk(ctx context.Context, peers []model.Peer, domainID int32) (map[uuid.UUID]model.Peer, error) {
+	unique := dedupePeers(peers)
+	res := make(map[uuid.UUID]model.Peer, len(unique))
+
+	// [HOT_PATH] Cache hits never reach the network.
+	misses := make([]model.Peer, 0, len(unique))
+	for _, p := range unique {
+		if cached, ok := e.lookup(peerCacheKey{domainID: domainID, id: p.ID}); ok {
+			res[p.ID] = cached
+			continue
+		}
+		misses = append(misses, p)
 	}
-
-	contacts := res.GetContacts()
-	if len(contacts) == 0 {
-		return peer, nil
+	if st := bulkStatsFrom(ctx); st != nil {
+		st.peers, st.cacheHits = len(unique), len(unique)-len(misses)
 	}
-
-	contact := contacts[0]
-	name := contact.GetName()
-	if name == "" {
-		name = contact.GetUsername()
+	if len(misses) == 0 {
+		return res, nil
 	}
 
-	// [SUCCESS] Populate peer with identity data
-	peer.Name = name
-	peer.Sub = contact.GetSubject()
-	peer.Issuer = contact.GetIssId()
+	resolved, err := e.chain.ResolvePeersBulk(ctx, misses, domainID)
+	for _, p := range misses {
+		r, ok := resolved[p.ID]
+		if !ok {
+			r = p
+		}
+		res[p.ID] = r
+		// [CACHE_POPULATION] Same rule as ResolvePeer; the joined error does not say
+		// which peers failed, so after one only the enriched ones are kept.
+		if err == nil || r.IsEnriched() {
+			e.remember(peerCacheKey{domainID: domainID, id: p.ID}, r)
+		}
+	}
+	return res, err
+}
 
-	return peer, nil
+// Invalidate evicts the contact from both caches, so the next lookup hits the directory.
+func (e *PeerEnricher) Invalidate(contactID uuid.UUID, domainID int32) {
+	key := peerCacheKey{domainID: domainID, id: contactID}
+	e.cache.Remove(key)
+	if e.misses != nil {
+		e.misses.Remove(key)
+	}
 }
 
-// mockEnrich is a helper for types not yet fully implemented.
-func (e *PeerEnricher) mockEnrich(peer model.Peer, placeholder string) model.Peer {
-	if peer.Name == "" {
-		peer.Name = fmt.Sprintf("%s (%s)", placeholder, peer.ID.String()[:8])
+// Prime stores a profile that arrived from the bus, saving the next directory lookup.
+// Unenriched peers are only evicted: a partial profile must not shadow the directory.
+func (e *PeerEnricher) Prime(peer model.Peer, domainID int32) {
+	e.Invalidate(peer.ID, domainID)
+	if peer.IsEnriched() {
+		e.cache.Add(peerCacheKey{domainID: domainID, id: peer.ID}, peer)
 	}
-	return peer
 }
+
+// BreakerState reports the contact directory breaker for the middleware logs.
+func (e *PeerEnricher) BreakerState() string { return e.contacts.BreakerState() }
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	contactv1 "github.com/webitel/im-delivery-service/gen/go/contact/v1"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
+)
+
+// domainBots names every bot after the tenant it is looked up in and counts lookups.
+// Tenants missing from names do not know the bot.
+type domainBots struct {
+	mu    sync.Mutex
+	names map[int32]string
+	err   error
+	calls int
+}
+
+func (f *domainBots) LookupBot(_ context.Context, _ uuid.UUID, domainID int32) (string, string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return "", "", "", f.err
+	}
+	name, ok := f.names[domainID]
+	if !ok {
+		return "", "", "", nil // Unknown to the directory
+	}
+	return name, "bot", "iss", nil
+}
+
+func (f *domainBots) set(names map[int32]string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.names, f.err = names, err
+}
+
+func (f *domainBots) lookups() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func newTestPeerEnricher(bots BotClient, negativeTTL time.Duration) *PeerEnricher {
+	cfg := &config.Config{}
+	cfg.Enrichment = config.EnrichmentConfig{CacheSize: 100, CacheTTL: time.Minute, NegativeTTL: negativeTTL}
+	return NewPeerEnricherService(cfg, nil, bots, nil, nil)
+}
+
+// TestPeerCacheIsTenantScoped resolves one peer UUID in two domains: each must get
+// its own tenant's profile, whichever domain warmed the cache first.
+func TestPeerCacheIsTenantScoped(t *testing.T) {
+	botID := uuid.New()
+	peer := model.Peer{ID: botID, Type: model.PeerBot}
+	names := map[int32]string{1: "Acme helper", 2: "Globex helper"}
+
+	resolvers := []struct {
+		name    string
+		resolve func(e *PeerEnricher, domainID int32) (model.Peer, error)
+	}{
+		{name: "ResolvePeer", resolve: func(e *PeerEnricher, domainID int32) (model.Peer, error) {
+			return e.ResolvePeer(context.Background(), peer, domainID)
+		}},
+		{name: "ResolvePeersBulk", resolve: func(e *PeerEnricher, domainID int32) (model.Peer, error) {
+			res, err := e.ResolvePeersBulk(context.Background(), []model.Peer{peer}, domainID)
+			return res[botID], err
+		}},
+	}
+	for _, r := range resolvers {
+		t.Run(r.name, func(t *testing.T) {
+			bots := &domainBots{names: names}
+			e := newTestPeerEnricher(bots, 0)
+
+			// Twice per domain: the second round is served from the cache.
+			for range 2 {
+				for _, domainID := range []int32{1, 2} {
+					got, err := r.resolve(e, domainID)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if got.Name != names[domainID] {
+						t.Fatalf("domain %d: got name %q, want %q", domainID, got.Name, names[domainID])
+					}
+				}
+			}
+			if n := bots.lookups(); n != 2 {
+				t.Fatalf("directory lookups = %d, want one per domain", n)
+			}
+		})
+	}
+}
+
+func TestPeerNegativeCache(t *testing.T) {
+	botID := uuid.New()
+	peer := model.Peer{ID: botID, Type: model.PeerBot}
+	known := map[int32]string{1: "Acme helper"}
+	outage := errors.New("bot directory unavailable")
+
+	tests := []struct {
+		name        string
+		negativeTTL time.Duration
+		names       map[int32]string // First lookup; the directory knows the bot afterwards
+		err         error
+		wait        time.Duration // Before the second lookup
+		wantErr     bool          // From the first lookup
+		wantName    string        // From the second lookup
+		wantLookups int
+	}{
+		{name: "unknown peer is cached briefly", negativeTTL: time.Minute, wantLookups: 1},
+		{name: "unknown peer is asked again after the ttl", negativeTTL: 20 * time.Millisecond, wait: 100 * time.Millisecond, wantName: "Acme helper", wantLookups: 2},
+		{name: "negative caching disabled", negativeTTL: 0, wantName: "Acme helper", wantLookups: 2},
+		{name: "failed lookup is not cached", negativeTTL: time.Minute, err: outage, wantErr: true, wantName: "Acme helper", wantLookups: 2},
+		{name: "known peer is cached", negativeTTL: time.Minute, names: known, wantName: "Acme helper", wantLookups: 1},
+	}
+	resolvers := []struct {
+		name    string
+		resolve func(e *PeerEnricher) (model.Peer, error)
+	}{
+		{name: "ResolvePeer", resolve: func(e *PeerEnricher) (model.Peer, error) {
+			return e.ResolvePeer(context.Background(), peer, 1)
+		}},
+		{name: "ResolvePeersBulk", resolve: func(e *PeerEnricher) (model.Peer, error) {
+			res, err := e.ResolvePeersBulk(context.Background(), []model.Peer{peer}, 1)
+			return res[botID], err
+		}},
+	}
+	for _, r := range resolvers {
+		for _, tt := range tests {
+			t.Run(r.name+"/"+tt.name, func(t *testing.T) {
+				bots := &domainBots{names: tt.names, err: tt.err}
+				e := newTestPeerEnricher(bots, tt.negativeTTL)
+
+				if _, err := r.resolve(e); (err != nil) != tt.wantErr {
+					t.Fatalf("first lookup: err = %v, want error %v", err, tt.wantErr)
+				}
+				bots.set(known, nil)
+				time.Sleep(tt.wait)
+
+				got, err := r.resolve(e)
+				if err != nil {
+					t.Fatalf("second lookup: %v", err)
+				}
+				if got.Name != tt.wantName {
+					t.Fatalf("second lookup: got name %q, want %q", got.Name, tt.wantName)
+				}
+				if n := bots.lookups(); n != tt.wantLookups {
+					t.Fatalf("directory lookups = %d, want %d", n, tt.wantLookups)
+				}
+			})
+		}
+	}
+}
+
+// renamingContacts answers every search with the current name and counts searches.
+type renamingContacts struct {
+	mu    sync.Mutex
+	name  string
+	calls int
+}
+
+func (f *renamingContacts) SearchContact(_ context.Context, req *contactv1.SearchContactRequest) (*contactv1.ContactList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return &contactv1.ContactList{Contacts: []*contactv1.Contact{
+		{Id: req.GetIds()[0], Name: f.name, Subject: "alice", DomainId: req.GetDomainId()},
+	}}, nil
+}
+
+func (f *renamingContacts) rename(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.name = name
+}
+
+func (f *renamingContacts) searches() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// TestPeerCacheContactChanged enriches a contact, applies a contact.updated or
+// contact.deleted payload the way the bus consumer does, and enriches it again.
+func TestPeerCacheContactChanged(t *testing.T) {
+	contactID := uuid.New()
+	tests := []struct {
+		name         string
+		payload      map[string]any
+		deleted      bool
+		wantName     string
+		wantSearches int
+	}{
+		{
+			name:         "update without a profile evicts",
+			payload:      map[string]any{"contact_id": contactID, "domain_id": 1},
+			wantName:     "Alice Renamed",
+			wantSearches: 2,
+		},
+		{
+			name:         "update with a profile primes the cache",
+			payload:      map[string]any{"contact_id": contactID, "domain_id": 1, "subject": "alice", "iss_id": "iss", "name": "Alice From Bus"},
+			wantName:     "Alice From Bus",
+			wantSearches: 1,
+		},
+		{
+			name:         "deletion evicts",
+			payload:      map[string]any{"contact_id": contactID, "domain_id": 1},
+			deleted:      true,
+			wantName:     "Alice Renamed",
+			wantSearches: 2,
+		},
+		{
+			name:         "another tenant's update keeps the entry",
+			payload:      map[string]any{"contact_id": contactID, "domain_id": 2},
+			wantName:     "Alice",
+			wantSearches: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Enrichment = config.EnrichmentConfig{CacheSize: 100, CacheTTL: time.Minute}
+			dir := &renamingContacts{name: "Alice"}
+			e := NewPeerEnricherService(cfg, nil, nil, nil, nil)
+			e.contacts = NewContactEnricher(dir, cfg.Enrichment)
+			e.chain = NewEnricherChain(e.contacts)
+			peer := model.Peer{ID: contactID, Type: model.PeerUser}
+
+			if got, err := e.ResolvePeer(context.Background(), peer, 1); err != nil || got.Name != "Alice" {
+				t.Fatalf("first lookup: got %q, %v", got.Name, err)
+			}
+			dir.rename("Alice Renamed")
+
+			body, _ := json.Marshal(tt.payload)
+			var changed dto.ContactChangedV1
+			if err := json.Unmarshal(body, &changed); err != nil {
+				t.Fatal(err)
+			}
+			if tt.deleted {
+				e.Invalidate(contactID, changed.DomainID)
+			} else {
+				e.Prime(changed.ToPeer(), changed.DomainID)
+			}
+
+			got, err := e.ResolvePeer(context.Background(), peer, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != tt.wantName {
+				t.Fatalf("second lookup: got name %q, want %q", got.Name, tt.wantName)
+			}
+			if n := dir.searches(); n != tt.wantSearches {
+				t.Fatalf("directory searches = %d, want %d", n, tt.wantSearches)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MaxPresenceBatch bounds CheckPresence so one request cannot walk the whole registry.
+const MaxPresenceBatch = 500
+
+// ErrPresenceBatchTooLarge is returned when CheckPresence receives more than MaxPresenceBatch IDs.
+var ErrPresenceBatchTooLarge = fmt.Errorf("presence batch exceeds %d user ids", MaxPresenceBatch)
+
+// Presence is whether a user can be reached through this node right now.
+//
+// [SCOPE] Online is cluster-wide when the global presence registry is enabled and
+// reachable, node-local otherwise. Sessions counts this node only; NodeID names the
+// node holding the user (this node when Sessions > 0).
+type Presence struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Online   bool      `json:"online"`
+	Sessions int       `json:"sessions"`
+	NodeID   string    `json:"node_id"`
+}
+
+// IsOnline reports the user's presence, asking the global registry only when the
+// user holds no session here.
+func (s *DeliveryService) IsOnline(userID uuid.UUID) Presence {
+	if n := s.hub.ConnectedCount(userID); n > 0 {
+		return Presence{UserID: userID, Online: true, Sessions: n, NodeID: s.cfg.Service.ID}
+	}
+	nodeID, online := s.hub.IsConnectedGlobal(userID)
+	return Presence{UserID: userID, Online: online, NodeID: nodeID}
+}
+
+// CheckPresence is the batched IsOnline; results follow the order of userIDs.
+func (s *DeliveryService) CheckPresence(userIDs []uuid.UUID) ([]Presence, error) {
+	if len(userIDs) > MaxPresenceBatch {
+		return nil, ErrPresenceBatchTooLarge
+	}
+	res := make([]Presence, len(userIDs))
+	for i, id := range userIDs {
+		res[i] = s.IsOnline(id)
+	}
+	return res, nil
+}
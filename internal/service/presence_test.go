@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+func TestCheckPresence(t *testing.T) {
+	online, offline := uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		ids     []uuid.UUID
+		want    []Presence
+		wantErr error
+	}{
+		{
+			name: "keeps request order",
+			ids:  []uuid.UUID{offline, online},
+			want: []Presence{
+				{UserID: offline},
+				{UserID: online, Online: true, Sessions: 2, NodeID: "node-1"},
+			},
+		},
+		{name: "empty batch", ids: nil, want: []Presence{}},
+		{name: "batch too large", ids: make([]uuid.UUID, MaxPresenceBatch+1), wantErr: ErrPresenceBatchTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := registry.NewHub()
+			t.Cleanup(hub.Shutdown)
+			cfg := &config.Config{}
+			cfg.Service.ID = "node-1"
+			s := &DeliveryService{hub: hub, cfg: cfg}
+			for range 2 {
+				hub.Register(registry.NewConnector(context.Background(), online, 8, registry.ConnectMetadata{}))
+			}
+
+			got, err := s.CheckPresence(tt.ids)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("presences: got %d, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("presence %d: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
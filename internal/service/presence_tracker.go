@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+)
+
+var presencePublished, _ = meter.Int64Counter(
+	"im_delivery_presence_published_total",
+	metric.WithDescription("Presence changes published to the bus, by direction"),
+)
+
+var presenceFlaps, _ = meter.Int64Counter(
+	"im_delivery_presence_flaps_suppressed_total",
+	metric.WithDescription("Offline transitions cancelled by a reconnect inside the grace window"),
+)
+
+// PresencePublisher is the slice of the bus dispatcher the tracker needs.
+type PresencePublisher interface {
+	PublishBatch(ctx context.Context, events []event.Eventer) error
+}
+
+// partialPublish is satisfied by the dispatcher's batch error.
+type partialPublish interface {
+	error
+	FailedEventIDs() []string
+}
+
+// presenceBatchTimeout bounds one batched presence publish.
+const presenceBatchTimeout = 5 * time.Second
+
+// domainResolver is satisfied by DeliveryService.
+type domainResolver interface {
+	DomainOf(connID uuid.UUID) (int64, bool)
+}
+
+// PresenceTracker publishes a PresenceChanged event when a user's first session
+// attaches to this node and when the last one leaves it.
+//
+// [DEBOUNCE]
+// Online is published at once. Offline is held for the grace window
+// (delivery.presence.grace): a reconnect inside it cancels the pending notice, so a
+// flapping mobile client produces no bus traffic at all. When the window elapses the
+// Hub is asked again, so a notice is never published for a user who is back.
+type PresenceTracker struct {
+	hub     *registry.Hub
+	cfg     *config.Config
+	pub     PresencePublisher
+	domains domainResolver
+	logger  *slog.Logger
+
+	mu       sync.Mutex
+	online   map[uuid.UUID]int64           // Users announced online, with the domain they were announced in
+	pending  map[uuid.UUID]*pendingOffline // Offline notices waiting out the grace window
+	queue    []event.Eventer               // Notices waiting for the next batch
+	flushing bool                          // A flush goroutine owns queue
+	stopped  bool
+	inflight sync.WaitGroup
+}
+
+// pendingOffline is an offline notice waiting out the grace window.
+type pendingOffline struct {
+	timer *time.Timer
+	left  int64 // Unix ms when the last session left
+}
+
+// NewPresenceTracker attaches the tracker to the Hub. Without a publisher, or with
+// delivery.presence.enabled off, it stays detached.
+func NewPresenceTracker(hub *registry.Hub, cfg *config.Config, deliverer Deliverer, logger *slog.Logger, lc fx.Lifecycle, pub PresencePublisher) *PresenceTracker {
+	t := &PresenceTracker{
+		hub:     hub,
+		cfg:     cfg,
+		pub:     pub,
+		logger:  logger,
+		online:  make(map[uuid.UUID]int64),
+		pending: make(map[uuid.UUID]*pendingOffline),
+	}
+	t.domains, _ = deliverer.(domainResolver)
+	if pub == nil || !cfg.Delivery.Presence.Enabled {
+		return t
+	}
+	hub.SetPresenceNotifier(t.notify)
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			hub.SetPresenceNotifier(nil)
+			t.stop()
+			return nil
+		},
+	})
+	return t
+}
+
+// notify is the Hub's PresenceNotifier.
+func (t *PresenceTracker) notify(conn registry.Connector, online bool) {
+	userID := conn.GetUserID()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+
+	if !online {
+		if _, announced := t.online[userID]; !announced {
+			return
+		}
+		if prev, ok := t.pending[userID]; ok {
+			prev.timer.Stop()
+		}
+		// [IDENTITY] expire compares the entry, never the timer field, which is written
+		// after AfterFunc returns and may still be unset when a short window fires.
+		p := &pendingOffline{left: time.Now().UnixMilli()}
+		p.timer = time.AfterFunc(t.cfg.Delivery.Presence.Grace, func() { t.expire(userID, p) })
+		t.pending[userID] = p
+		return
+	}
+
+	// [FLAP] Back inside the grace window: the offline notice is never sent.
+	if p, ok := t.pending[userID]; ok {
+		p.timer.Stop()
+		delete(t.pending, userID)
+		presenceFlaps.Add(context.Background(), 1)
+		return
+	}
+	if _, announced := t.online[userID]; announced {
+		return
+	}
+	var domainID int64
+	if t.domains != nil {
+		domainID, _ = t.domains.DomainOf(conn.GetID())
+	}
+	t.online[userID] = domainID
+	t.publish(userID, domainID, true, time.Now().UnixMilli())
+}
+
+// expire publishes the offline notice once the grace window has elapsed.
+func (t *PresenceTracker) expire(userID uuid.UUID, p *pendingOffline) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// [SUPERSEDED] A reconnect or a newer offline notice replaced this entry.
+	if t.stopped || t.pending[userID] != p {
+		return
+	}
+	delete(t.pending, userID)
+	if t.hub.ConnectedCount(userID) > 0 {
+		return
+	}
+	domainID := t.online[userID]
+	delete(t.online, userID)
+	t.publish(userID, domainID, false, p.left)
+}
+
+// publish queues the event for the bus off the caller's path.
+// [BURSTS] Notices raised while a batch is in flight ride the next one together.
+// [LOCKED] Called with t.mu held, so inflight.Add never races stop's Wait.
+func (t *PresenceTracker) publish(userID uuid.UUID, domainID int64, online bool, at int64) {
+	t.queue = append(t.queue, event.NewPresenceEvent(userID, domainID, online, t.cfg.Service.ID, at))
+	if t.flushing {
+		return
+	}
+	t.flushing = true
+	t.inflight.Go(t.flush)
+}
+
+// flush publishes queued notices until the queue stays empty.
+func (t *PresenceTracker) flush() {
+	for {
+		t.mu.Lock()
+		batch := t.queue
+		t.queue = nil
+		if len(batch) == 0 {
+			t.flushing = false
+			t.mu.Unlock()
+			return
+		}
+		t.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), presenceBatchTimeout)
+		err := t.pub.PublishBatch(ctx, batch)
+		cancel()
+
+		failed := make(map[string]struct{})
+		if err != nil {
+			var partial partialPublish
+			if errors.As(err, &partial) {
+				for _, id := range partial.FailedEventIDs() {
+					failed[id] = struct{}{}
+				}
+			} else {
+				for _, ev := range batch {
+					failed[ev.GetID()] = struct{}{}
+				}
+			}
+		}
+
+		for _, ev := range batch {
+			direction := "offline"
+			if p, ok := ev.GetPayload().(*model.PresencePayload); ok && p.Online {
+				direction = "online"
+			}
+			if _, bad := failed[ev.GetID()]; bad {
+				t.logger.Warn("PRESENCE_PUBLISH_FAILED",
+					slog.String("user_id", ev.GetUserID().String()),
+					slog.String("direction", direction),
+					slog.Any("err", err),
+				)
+				continue
+			}
+			presencePublished.Add(context.Background(), 1, metric.WithAttributes(attribute.String("direction", direction)))
+		}
+	}
+}
+
+// stop cancels pending offline notices and waits for in-flight publishes.
+// Users still online are not announced offline: the node going away is the bus's
+// (and its consumers' heartbeat) concern, not a presence transition.
+func (t *PresenceTracker) stop() {
+	t.mu.Lock()
+	t.stopped = true
+	for id, p := range t.pending {
+		p.timer.Stop()
+		delete(t.pending, id)
+	}
+	t.mu.Unlock()
+	t.inflight.Wait()
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"go.uber.org/fx/fxtest"
+)
+
+// presenceBus records every published presence notice.
+type presenceBus struct {
+	mu      sync.Mutex
+	notices []*model.PresencePayload
+}
+
+func (b *presenceBus) PublishBatch(_ context.Context, events []event.Eventer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ev := range events {
+		b.notices = append(b.notices, ev.GetPayload().(*model.PresencePayload))
+	}
+	return nil
+}
+
+// domainOf resolves every connection to one domain.
+type domainOf struct {
+	Deliverer
+	domain int64
+}
+
+func (d domainOf) DomainOf(uuid.UUID) (int64, bool) { return d.domain, true }
+
+// presenceFixture drives one user's sessions through a real Hub.
+type presenceFixture struct {
+	hub     *registry.Hub
+	lc      *fxtest.Lifecycle
+	bus     *presenceBus
+	userID  uuid.UUID
+	conns   []registry.Connector
+	leftAt  []int64 // Unix ms of every detach, for the offline timestamp
+	stopped bool
+}
+
+type presenceStep func(f *presenceFixture)
+
+func attach(f *presenceFixture) {
+	conn := registry.NewConnector(context.Background(), f.userID, 8, registry.ConnectMetadata{})
+	f.conns = append(f.conns, conn)
+	f.hub.Register(conn)
+}
+
+func detach(f *presenceFixture) {
+	conn := f.conns[len(f.conns)-1]
+	f.conns = f.conns[:len(f.conns)-1]
+	f.leftAt = append(f.leftAt, time.Now().UnixMilli())
+	f.hub.Unregister(f.userID, conn.GetID())
+	conn.Release()
+}
+
+func wait(d time.Duration) presenceStep {
+	return func(*presenceFixture) { time.Sleep(d) }
+}
+
+func stopTracker(f *presenceFixture) {
+	f.lc.RequireStop()
+	f.stopped = true
+}
+
+func TestPresenceTrackerDebounce(t *testing.T) {
+	const grace = 10 * time.Second
+	tests := []struct {
+		name      string
+		steps     []presenceStep
+		want      []bool // Online flags of the published notices, in order
+		wantFlaps int64
+	}{
+		{
+			name:  "online is published at once",
+			steps: []presenceStep{attach},
+			want:  []bool{true},
+		},
+		{
+			name:  "second session is not a transition",
+			steps: []presenceStep{attach, attach, detach},
+			want:  []bool{true},
+		},
+		{
+			name:  "offline waits out the grace window",
+			steps: []presenceStep{attach, detach, wait(grace - time.Second)},
+			want:  []bool{true},
+		},
+		{
+			name:  "offline after the grace window",
+			steps: []presenceStep{attach, detach, wait(grace + time.Second)},
+			want:  []bool{true, false},
+		},
+		{
+			name:      "reconnect inside the window suppresses the flap",
+			steps:     []presenceStep{attach, detach, wait(grace / 2), attach, wait(2 * grace)},
+			want:      []bool{true},
+			wantFlaps: 1,
+		},
+		{
+			name: "a later disconnect restarts the window",
+			steps: []presenceStep{
+				attach, detach, wait(grace / 2), attach, wait(time.Second), detach,
+				wait(grace - time.Second), // Past the first window, inside the second
+			},
+			want:      []bool{true},
+			wantFlaps: 1,
+		},
+		{
+			name: "reconnect after the window is a new transition",
+			steps: []presenceStep{
+				attach, detach, wait(grace + time.Second), attach,
+			},
+			want: []bool{true, false, true},
+		},
+		{
+			name:  "stop cancels a pending offline notice",
+			steps: []presenceStep{attach, detach, stopTracker, wait(2 * grace)},
+			want:  []bool{true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaps := counterTotal(t, "im_delivery_presence_flaps_suppressed_total")
+			synctest.Test(t, func(t *testing.T) {
+				cfg := &config.Config{}
+				cfg.Service.ID = "node-1"
+				cfg.Delivery.Presence = config.PresenceConfig{Enabled: true, Grace: grace}
+
+				f := &presenceFixture{
+					hub:    registry.NewHub(registry.WithShardCount(1), registry.WithEvictionInterval(time.Hour)),
+					lc:     fxtest.NewLifecycle(t),
+					bus:    &presenceBus{},
+					userID: uuid.New(),
+				}
+				defer f.hub.Shutdown()
+				NewPresenceTracker(f.hub, cfg, domainOf{domain: 7}, slog.New(slog.NewTextHandler(io.Discard, nil)), f.lc, f.bus)
+				f.lc.RequireStart()
+
+				for _, step := range tt.steps {
+					step(f)
+					synctest.Wait()
+				}
+				if !f.stopped {
+					f.lc.RequireStop()
+				}
+
+				got := make([]bool, 0, len(f.bus.notices))
+				offline := 0
+				for _, p := range f.bus.notices {
+					got = append(got, p.Online)
+					if p.UserID != f.userID.String() || p.DomainID != 7 || p.NodeID != "node-1" {
+						t.Fatalf("notice addressed wrong: %+v", p)
+					}
+					if !p.Online {
+						// The timestamp is the last detach before the window ran out.
+						if want := f.leftAt[offline]; p.At != want {
+							t.Fatalf("offline notice at %d, want the detach time %d", p.At, want)
+						}
+						offline++
+					}
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Fatalf("notices (online flags): got %v, want %v", got, tt.want)
+				}
+			})
+			if got := counterTotal(t, "im_delivery_presence_flaps_suppressed_total") - flaps; got != tt.wantFlaps {
+				t.Fatalf("flaps suppressed: got %d, want %d", got, tt.wantFlaps)
+			}
+		})
+	}
+}
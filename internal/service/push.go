@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/internal/service/dto"
+)
+
+var (
+	// ErrPushForbidden is returned when a caller without service scope pushes an event.
+	ErrPushForbidden = errors.New("pushing events requires service scope")
+	// ErrPushDisabled is returned when delivery.push.max_payload_bytes is 0.
+	ErrPushDisabled = errors.New("event push is disabled on this node")
+	// ErrPushTooLarge is returned for payloads above delivery.push.max_payload_bytes.
+	ErrPushTooLarge = errors.New("pushed payload exceeds the configured limit")
+	// ErrPushInvalid is returned for a kind that may not be pushed or a malformed payload.
+	ErrPushInvalid = errors.New("invalid pushed event")
+)
+
+// PushOutcome reports what the Hub did with a pushed event.
+type PushOutcome string
+
+const (
+	PushAccepted     PushOutcome = "accepted"      // Queued in the user's mailbox
+	PushDropped      PushOutcome = "dropped"       // Mailbox full; nothing was queued
+	PushNotConnected PushOutcome = "not_connected" // The user holds no session on this node
+)
+
+// PushRequest is a one-off event injected by internal tooling, bypassing the bus.
+type PushRequest struct {
+	UserID   uuid.UUID
+	Kind     string // One of pushableKinds, e.g. "message_created"
+	Payload  []byte // JSON in the bus contract of Kind, e.g. a dto.MessageV1
+	Priority event.EventPriority
+}
+
+// pushDecoder builds the event a bus consumer would have built from payload.
+type pushDecoder func(userID uuid.UUID, payload []byte) (event.Eventer, error)
+
+// pushableKinds is the [WHITELIST] of kinds tooling may inject. Each is decoded from its
+// bus contract, so clients receive it exactly as the live event. System kinds
+// (Connected, ReplayGap, DomainPaused, ...) belong to the node and are never accepted.
+var pushableKinds = map[string]pushDecoder{
+	"message_created": func(userID uuid.UUID, payload []byte) (event.Eventer, error) {
+		raw, err := decodePushed[dto.MessageV1](payload)
+		if err != nil {
+			return nil, err
+		}
+		return event.NewMessageV1Event(raw.ToDomain(), userID, raw.From.ToDomain(), raw.To.ToDomain()), nil
+	},
+	"message_updated": func(userID uuid.UUID, payload []byte) (event.Eventer, error) {
+		raw, err := decodePushed[dto.MessageUpdatedV1](payload)
+		if err != nil {
+			return nil, err
+		}
+		return event.NewMessageUpdatedEvent(raw.ToDomain(), userID, raw.From.ToDomain(), raw.To.ToDomain()), nil
+	},
+	"message_deleted": func(userID uuid.UUID, payload []byte) (event.Eventer, error) {
+		raw, err := decodePushed[dto.MessageDeletedV1](payload)
+		if err != nil {
+			return nil, err
+		}
+		return event.NewMessageDeletedEvent(raw.ToDomain(), userID), nil
+	},
+	"message_read": func(userID uuid.UUID, payload []byte) (event.Eventer, error) {
+		raw, err := decodePushed[dto.MessageReadV1](payload)
+		if err != nil {
+			return nil, err
+		}
+		return event.NewReadReceiptEvent(raw.ToDomain(), userID), nil
+	},
+	"message_reaction": func(userID uuid.UUID, payload []byte) (event.Eventer, error) {
+		raw, err := decodePushed[dto.ReactionV1](payload)
+		if err != nil {
+			return nil, err
+		}
+		return event.NewReactionEvent(raw.ToDomain(), userID), nil
+	},
+}
+
+// decodePushed unmarshals payload into a bus DTO and enforces its contract.
+func decodePushed[T any, PT interface {
+	*T
+	CheckContract() error
+}](payload []byte) (PT, error) {
+	raw := PT(new(T))
+	if err := json.Unmarshal(payload, raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPushInvalid, err)
+	}
+	if err := raw.CheckContract(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPushInvalid, err)
+	}
+	return raw, nil
+}
+
+// PushEvent delivers req to the user's sessions on this node.
+// [AUTHORIZATION] Service scope only. The event is node-local: it is neither
+// enriched nor re-published, so the caller must target the node holding the user.
+func (s *DeliveryService) PushEvent(_ context.Context, req PushRequest, viewer DeliveryViewer) (PushOutcome, error) {
+	if !viewer.ServiceScope {
+		return "", ErrPushForbidden
+	}
+	limit := s.cfg.Delivery.Push.MaxPayloadBytes
+	if limit <= 0 {
+		return "", ErrPushDisabled
+	}
+	if len(req.Payload) > limit {
+		return "", fmt.Errorf("%w: %d > %d bytes", ErrPushTooLarge, len(req.Payload), limit)
+	}
+
+	ev, err := buildPushed(req)
+	if err != nil {
+		return "", err
+	}
+
+	switch res := s.hub.BroadcastIfConnected(ev); res.Reason {
+	case registry.ReasonNotRegistered:
+		return PushNotConnected, nil
+	case registry.ReasonMailboxFull:
+		return PushDropped, nil
+	}
+	return PushAccepted, nil
+}
+
+// buildPushed decodes a whitelisted kind and caps its priority; see event.NewPushedEvent.
+func buildPushed(req PushRequest) (event.Eventer, error) {
+	decode, ok := pushableKinds[req.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: kind %q may not be pushed", ErrPushInvalid, req.Kind)
+	}
+	ev, err := decode(req.UserID, req.Payload)
+	if err != nil {
+		return nil, err
+	}
+	priority := req.Priority
+	if priority == 0 {
+		priority = event.PriorityNormal
+	}
+	return event.NewPushedEvent(ev, priority), nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+func deletedJSON(domainID int) []byte {
+	return fmt.Appendf(nil, `{"message_id":%q,"thread_id":%q,"domain_id":%d}`, uuid.New(), uuid.New(), domainID)
+}
+
+func TestPushEvent(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          PushRequest
+		serviceScope bool
+		maxBytes     int
+		connected    bool
+		want         PushOutcome
+		wantErr      error
+		wantKind     event.EventKind
+		wantPriority event.EventPriority
+	}{
+		{
+			name:         "whitelisted kind defaults to normal priority",
+			req:          PushRequest{Kind: "message_deleted", Payload: deletedJSON(1)},
+			serviceScope: true, maxBytes: 1 << 10, connected: true,
+			want: PushAccepted, wantKind: event.MessageDeleted, wantPriority: event.PriorityNormal,
+		},
+		{
+			name:         "high priority is capped",
+			req:          PushRequest{Kind: "message_deleted", Payload: deletedJSON(1), Priority: event.PriorityHigh},
+			serviceScope: true, maxBytes: 1 << 10, connected: true,
+			want: PushAccepted, wantKind: event.MessageDeleted, wantPriority: event.PriorityNormal,
+		},
+		{
+			name:         "low priority is kept",
+			req:          PushRequest{Kind: "message_deleted", Payload: deletedJSON(1), Priority: event.PriorityLow},
+			serviceScope: true, maxBytes: 1 << 10, connected: true,
+			want: PushAccepted, wantKind: event.MessageDeleted, wantPriority: event.PriorityLow,
+		},
+		{
+			name:         "user not on this node",
+			req:          PushRequest{Kind: "message_deleted", Payload: deletedJSON(1)},
+			serviceScope: true, maxBytes: 1 << 10,
+			want: PushNotConnected,
+		},
+		{
+			name:     "caller without service scope",
+			req:      PushRequest{Kind: "message_deleted", Payload: deletedJSON(1)},
+			maxBytes: 1 << 10, connected: true,
+			wantErr: ErrPushForbidden,
+		},
+		{
+			name:         "push disabled",
+			req:          PushRequest{Kind: "message_deleted", Payload: deletedJSON(1)},
+			serviceScope: true, connected: true,
+			wantErr: ErrPushDisabled,
+		},
+		{
+			name:         "payload too large",
+			req:          PushRequest{Kind: "message_deleted", Payload: deletedJSON(1)},
+			serviceScope: true, maxBytes: 16, connected: true,
+			wantErr: ErrPushTooLarge,
+		},
+		{
+			name:         "system kind rejected",
+			req:          PushRequest{Kind: "domain_paused", Payload: []byte(`{}`)},
+			serviceScope: true, maxBytes: 1 << 10, connected: true,
+			wantErr: ErrPushInvalid,
+		},
+		{
+			name:         "kind name in another spelling rejected",
+			req:          PushRequest{Kind: "MessageDeleted", Payload: deletedJSON(1)},
+			serviceScope: true, maxBytes: 1 << 10, connected: true,
+			wantErr: ErrPushInvalid,
+		},
+		{
+			name:         "payload breaking the bus contract",
+			req:          PushRequest{Kind: "message_deleted", Payload: deletedJSON(0)},
+			serviceScope: true, maxBytes: 1 << 10, connected: true,
+			wantErr: ErrPushInvalid,
+		},
+		{
+			name:         "malformed JSON",
+			req:          PushRequest{Kind: "message_created", Payload: []byte(`{`)},
+			serviceScope: true, maxBytes: 1 << 10, connected: true,
+			wantErr: ErrPushInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := registry.NewHub()
+			t.Cleanup(hub.Shutdown)
+			cfg := &config.Config{}
+			cfg.Delivery.Push.MaxPayloadBytes = tt.maxBytes
+			s := &DeliveryService{hub: hub, cfg: cfg}
+
+			tt.req.UserID = uuid.New()
+			conn := registry.NewConnector(context.Background(), tt.req.UserID, 8, registry.ConnectMetadata{})
+			if tt.connected {
+				hub.Register(conn)
+			}
+
+			got, err := s.PushEvent(context.Background(), tt.req, DeliveryViewer{ServiceScope: tt.serviceScope})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("outcome: got %q, want %q", got, tt.want)
+			}
+			if tt.want != PushAccepted {
+				return
+			}
+
+			select {
+			case ev := <-conn.Recv():
+				if ev.GetKind() != tt.wantKind {
+					t.Fatalf("kind: got %v, want %v", ev.GetKind(), tt.wantKind)
+				}
+				if ev.GetPriority() != tt.wantPriority {
+					t.Fatalf("priority: got %v, want %v", ev.GetPriority(), tt.wantPriority)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("accepted event never reached the session")
+			}
+		})
+	}
+}
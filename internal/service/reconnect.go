@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+	"github.com/webitel/im-delivery-service/pkg/reconnect"
+	"go.uber.org/fx"
+)
+
+// NewReconnectAdvisor builds the backoff advisor from live Hub load.
+// [SHUTDOWN_PHASE] Service hooks stop before the registry's, so the advisor enters
+// the shutdown phase before Hub.Shutdown starts closing sessions.
+func NewReconnectAdvisor(hub registry.Hubber, cfg *config.Config, lc fx.Lifecycle) *reconnect.Advisor {
+	rc := cfg.Delivery.Reconnect
+
+	var opts []reconnect.Option
+	if rc.BaseWindow > 0 && rc.MaxWindow >= rc.BaseWindow {
+		opts = append(opts, reconnect.WithWindow(rc.BaseWindow, rc.MaxWindow))
+	}
+
+	a := reconnect.NewAdvisor(func() reconnect.Load {
+		return reconnect.Load{
+			Connected: hub.ConnectedUsers(),
+			Capacity:  cfg.Delivery.Reconnect.Capacity,
+		}
+	}, opts...)
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			a.BeginShutdown()
+			return nil
+		},
+	})
+	return a
+}
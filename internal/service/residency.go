@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/webitel/im-delivery-service/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// residencyViolations is a [SECURITY] signal: restricted-domain traffic reached delivery
+// on a node outside the allowed regions. Any non-zero value indicates misconfiguration.
+var residencyViolations, _ = meter.Int64Counter(
+	"im_delivery_residency_violations_total",
+	metric.WithDescription("Restricted-domain events blocked on a node outside the allowed regions"),
+)
+
+// ResidencyError rejects work for a residency-restricted domain on this node.
+// It carries the allowed regions so the gateway or client can redial the right one.
+type ResidencyError struct {
+	DomainID       int64
+	NodeRegion     string
+	AllowedRegions []string
+}
+
+func (e *ResidencyError) Error() string {
+	return fmt.Sprintf("domain %d is restricted to regions [%s]; this node is in %q",
+		e.DomainID, strings.Join(e.AllowedRegions, ","), e.NodeRegion)
+}
+
+// ResidencyPolicy decides whether this node may hold sessions and process events for a domain.
+//
+// [SOURCES] Static config (delivery.residency) is the baseline; domain policy events
+// consumed from the bus override it per domain at runtime.
+type ResidencyPolicy struct {
+	cfg *config.Config
+
+	mu        sync.RWMutex
+	overrides map[int64][]string // domain -> allowed regions; empty slice lifts the restriction
+}
+
+func NewResidencyPolicy(cfg *config.Config) *ResidencyPolicy {
+	return &ResidencyPolicy{cfg: cfg, overrides: make(map[int64][]string)}
+}
+
+// Region returns this node's region label.
+func (p *ResidencyPolicy) Region() string { return p.cfg.Service.Region }
+
+// SetDomainRegions applies a runtime policy update. An empty list removes the restriction.
+func (p *ResidencyPolicy) SetDomainRegions(domainID int64, regions []string) {
+	p.mu.Lock()
+	p.overrides[domainID] = slices.Clone(regions)
+	p.mu.Unlock()
+}
+
+// allowedRegions returns the regions a domain is restricted to (nil when unrestricted).
+func (p *ResidencyPolicy) allowedRegions(domainID int64) []string {
+	p.mu.RLock()
+	regions, ok := p.overrides[domainID]
+	p.mu.RUnlock()
+	if ok {
+		return regions
+	}
+
+	for _, d := range p.cfg.Delivery.Residency.Domains {
+		if d.DomainID == domainID {
+			return d.Regions
+		}
+	}
+	return nil
+}
+
+// Check returns a *ResidencyError if this node may not serve the domain.
+func (p *ResidencyPolicy) Check(domainID int64) error {
+	regions := p.allowedRegions(domainID)
+	if len(regions) == 0 || slices.Contains(regions, p.Region()) {
+		return nil
+	}
+	return &ResidencyError{DomainID: domainID, NodeRegion: p.Region(), AllowedRegions: regions}
+}
+
+// ReportViolation records a hard-blocked delivery attempt.
+func (p *ResidencyPolicy) ReportViolation(ctx context.Context, domainID int64) {
+	residencyViolations.Add(ctx, 1, metric.WithAttributes(
+		attribute.Int64("domain_id", domainID),
+		attribute.String("region", p.Region()),
+	))
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+)
+
+func residencyConfig(region string, domains ...config.DomainResidency) *config.Config {
+	cfg := &config.Config{}
+	cfg.Service.Region = region
+	cfg.Delivery.Residency.Domains = domains
+	return cfg
+}
+
+func TestResidencyPolicyCheck(t *testing.T) {
+	euOnly := config.DomainResidency{DomainID: 7, Regions: []string{"eu"}}
+	tests := []struct {
+		name        string
+		region      string
+		override    []string // Applied via SetDomainRegions when non-nil
+		wantAllowed []string // Regions carried by the error; nil expects no error
+	}{
+		{name: "allowed region", region: "eu"},
+		{name: "disallowed region", region: "us", wantAllowed: []string{"eu"}},
+		{name: "unlabelled node", region: "", wantAllowed: []string{"eu"}},
+		{name: "policy event widens the restriction", region: "us", override: []string{"eu", "us"}},
+		{name: "policy event narrows the restriction", region: "eu", override: []string{"us"}, wantAllowed: []string{"us"}},
+		{name: "policy event lifts the restriction", region: "us", override: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewResidencyPolicy(residencyConfig(tt.region, euOnly))
+			if tt.override != nil {
+				p.SetDomainRegions(euOnly.DomainID, tt.override)
+			}
+			if err := p.Check(1); err != nil {
+				t.Fatalf("unrestricted domain rejected: %v", err)
+			}
+
+			err := p.Check(euOnly.DomainID)
+			var rerr *ResidencyError
+			if tt.wantAllowed == nil {
+				if err != nil {
+					t.Fatalf("Check = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &rerr) {
+				t.Fatalf("Check = %v, want *ResidencyError", err)
+			}
+			if rerr.NodeRegion != tt.region || !slices.Equal(rerr.AllowedRegions, tt.wantAllowed) {
+				t.Fatalf("error = %+v, want node %q allowed %v", rerr, tt.region, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+// TestSubscribeResidency simulates one node per region and checks that a restricted
+// tenant can only hold sessions on the allowed one.
+func TestSubscribeResidency(t *testing.T) {
+	restricted := config.DomainResidency{DomainID: 7, Regions: []string{"eu"}}
+	tests := []struct {
+		name    string
+		region  string
+		domain  int64
+		wantErr bool
+	}{
+		{name: "restricted tenant in its region", region: "eu", domain: 7},
+		{name: "restricted tenant elsewhere", region: "us", domain: 7, wantErr: true},
+		{name: "unrestricted tenant elsewhere", region: "us", domain: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestDeliveryService(t, residencyConfig(tt.region, restricted))
+			conn, err := s.Subscribe(t.Context(), uuid.New(), SubscribeOptions{Transport: TransportWS, DomainID: tt.domain})
+			var rerr *ResidencyError
+			if got := errors.As(err, &rerr); got != tt.wantErr {
+				t.Fatalf("Subscribe error = %v, want residency rejection = %v", err, tt.wantErr)
+			}
+			if conn != nil {
+				s.Unsubscribe(conn.GetUserID(), conn.GetID())
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/infra/keyring"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+)
+
+const (
+	// sessionReconnectWindow is how long a dropped session stays resumable.
+	sessionReconnectWindow = 30 * time.Second
+	// sessionTokenMaxAge caps a token's validity regardless of session activity.
+	sessionTokenMaxAge = 24 * time.Hour
+	// maxPendingAcks bounds the written-but-unacknowledged window kept per session.
+	maxPendingAcks = 256
+)
+
+var (
+	ErrSessionTokenInvalid = errors.New("session token is invalid")
+	ErrSessionTokenExpired = errors.New("session token is expired or already used")
+)
+
+// sessionClaims is the signed body of a session token.
+type sessionClaims struct {
+	UserID    uuid.UUID `json:"u"`
+	SessionID uuid.UUID `json:"s"`
+	ExpiresAt int64     `json:"e"` // Unix seconds
+}
+
+// SessionState is the resumable state of one transport session.
+type SessionState struct {
+	ID     uuid.UUID
+	userID uuid.UUID
+	cursor atomic.Pointer[event.Cursor]
+	// endedAt is UnixNano when the transport went away; 0 while it is live.
+	endedAt atomic.Int64
+
+	// [CLIENT_ACKS] Once the client acknowledges anything, resumption starts after the
+	// last acked event instead of the last written one: written-but-unacked events are
+	// replayed (at-least-once) rather than lost with the socket buffer.
+	mu      sync.Mutex
+	pending []pendingAck
+	acked   *event.Cursor
+}
+
+// pendingAck is a written, sequenced event awaiting the client's ack.
+type pendingAck struct {
+	id     string
+	cursor event.Cursor
+}
+
+// Track records the cursor of the last event written to the wire.
+func (s *SessionState) Track(ev event.Eventer) {
+	cur, ok := event.CursorOf(ev)
+	if !ok {
+		return
+	}
+	s.cursor.Store(&cur)
+
+	s.mu.Lock()
+	if len(s.pending) == maxPendingAcks {
+		s.pending = append(s.pending[:0], s.pending[1:]...)
+	}
+	s.pending = append(s.pending, pendingAck{id: ev.GetID(), cursor: cur})
+	s.mu.Unlock()
+}
+
+// Ack marks the event and everything written before it as processed by the client.
+// It reports false for unknown IDs (never written, or already outside the window).
+func (s *SessionState) Ack(eventID string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, p := range s.pending {
+		if p.id == eventID {
+			cur := p.cursor
+			s.acked = &cur
+			s.pending = append(s.pending[:0], s.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// resumeCursor is where a redeemed session continues from (nil: nothing sequenced).
+func (s *SessionState) resumeCursor() *event.Cursor {
+	s.mu.Lock()
+	acked := s.acked
+	s.mu.Unlock()
+	if acked != nil {
+		return acked
+	}
+	return s.cursor.Load()
+}
+
+// SessionTokens issues and redeems [RECONNECTION_TOKENS] for stateful transports.
+//
+// [STRATEGY]
+// A token is an HMAC-signed (userID, sessionID, expiry) triple; the state it points to
+// lives in memory on this node. Redeeming is single-use and only succeeds within the
+// reconnect window after the previous transport ended.
+type SessionTokens struct {
+	ring *keyring.KeyRing
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*SessionState
+}
+
+func NewSessionTokens(ring *keyring.KeyRing) *SessionTokens {
+	return &SessionTokens{ring: ring, sessions: make(map[uuid.UUID]*SessionState)}
+}
+
+// Issue opens a new resumable session for the user and returns its token.
+func (t *SessionTokens) Issue(userID uuid.UUID) (*SessionState, string, error) {
+	state := &SessionState{ID: uuid.New(), userID: userID}
+
+	body, err := json.Marshal(sessionClaims{
+		UserID:    userID,
+		SessionID: state.ID,
+		ExpiresAt: time.Now().Add(sessionTokenMaxAge).Unix(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := t.ring.SignToken(body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	t.mu.Lock()
+	t.purgeLocked()
+	t.sessions[state.ID] = state
+	t.mu.Unlock()
+
+	return state, token, nil
+}
+
+// Redeem consumes a token and returns the cursor to resume from.
+// The zero cursor is returned when the previous session delivered nothing sequenced.
+func (t *SessionTokens) Redeem(token string, userID uuid.UUID) (event.Cursor, error) {
+	body, err := t.ring.VerifyToken(token)
+	if err != nil {
+		return event.Cursor{}, ErrSessionTokenInvalid
+	}
+
+	var claims sessionClaims
+	if err := json.Unmarshal(body, &claims); err != nil || claims.UserID != userID {
+		return event.Cursor{}, ErrSessionTokenInvalid
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return event.Cursor{}, ErrSessionTokenExpired
+	}
+
+	t.mu.Lock()
+	state, ok := t.sessions[claims.SessionID]
+	// [SINGLE_USE] Only an ended session inside its window may be taken over.
+	if ok && state.resumable(time.Now()) {
+		delete(t.sessions, claims.SessionID)
+	} else {
+		ok = false
+	}
+	t.mu.Unlock()
+
+	if !ok {
+		return event.Cursor{}, ErrSessionTokenExpired
+	}
+	if cur := state.resumeCursor(); cur != nil {
+		return *cur, nil
+	}
+	return event.Cursor{}, nil
+}
+
+// End marks the session's transport as gone, starting its reconnect window.
+func (t *SessionTokens) End(state *SessionState) {
+	state.endedAt.Store(time.Now().UnixNano())
+}
+
+func (s *SessionState) resumable(now time.Time) bool {
+	ended := s.endedAt.Load()
+	return ended != 0 && now.Sub(time.Unix(0, ended)) <= sessionReconnectWindow
+}
+
+// purgeLocked drops sessions whose reconnect window has elapsed.
+func (t *SessionTokens) purgeLocked() {
+	now := time.Now()
+	for id, s := range t.sessions {
+		if s.endedAt.Load() != 0 && !s.resumable(now) {
+			delete(t.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// CapabilityDeliveryShaping is advertised in the Connected payload while shaping is active.
+const CapabilityDeliveryShaping = "delivery_shaping"
+
+// CapabilityE2EE is advertised when the node relays end-to-end encrypted messages.
+const CapabilityE2EE = "e2ee"
+
+// DeliveryShaper injects synthetic delay, jitter and reordering for client UX testing.
+//
+// [STRATEGY]
+// Shaping sits in the transport send path, after the connector dequeue, so Hub and Cell
+// behaviour stay untouched. Rules are read from the live config on every connection,
+// which makes them hot-reloadable for new sessions.
+type DeliveryShaper struct {
+	cfg *config.Config
+}
+
+func NewDeliveryShaper(cfg *config.Config) *DeliveryShaper {
+	return &DeliveryShaper{cfg: cfg}
+}
+
+// Rules lists the rules currently in effect (empty when the environment guard applies).
+func (s *DeliveryShaper) Rules() []config.ShapingRule {
+	// [ENV_GUARD] Never shape production traffic, whatever the config says.
+	if s.cfg.Service.Environment == config.EnvProduction {
+		return nil
+	}
+	return s.cfg.Delivery.Shaping.Rules
+}
+
+// rulesFor returns the rules matching the domain, keyed by event kind name ("*" for any).
+func (s *DeliveryShaper) rulesFor(domainID int64) map[string]config.ShapingRule {
+	var res map[string]config.ShapingRule
+	for _, r := range s.Rules() {
+		if r.DomainID != 0 && r.DomainID != domainID {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]config.ShapingRule)
+		}
+		res[r.Kind] = r
+	}
+	return res
+}
+
+// Shape returns the stream a transport should read from, and a stop function that
+// must run before the connector is released.
+// [ZERO_OVERHEAD] Without matching rules the connector's own channel is returned as is.
+func (s *DeliveryShaper) Shape(conn registry.Connector, domainID int64) (<-chan event.Eventer, func()) {
+	rules := s.rulesFor(domainID)
+	if len(rules) == 0 {
+		return conn.Recv(), func() {}
+	}
+
+	out := make(chan event.Eventer, cap(conn.Recv()))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	forward := func(ev event.Eventer) {
+		select {
+		case out <- ev:
+		case <-done:
+		}
+	}
+
+	wg.Go(func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-conn.Done():
+				return
+			case ev := <-conn.Recv():
+				r, ok := rules[ev.GetKind().String()]
+				if !ok {
+					r, ok = rules["*"]
+				}
+				if !ok {
+					forward(ev)
+					continue
+				}
+
+				delay := shapingDelay(r)
+				shaped := event.NewShapedEvent(ev, delay)
+				// [REORDER] Each event is held independently, so later events may overtake it.
+				time.AfterFunc(delay, func() { forward(shaped) })
+			}
+		}
+	})
+
+	// [OWNERSHIP] Stop returns only after the reader goroutine has left conn.Recv().
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+	return out, stop
+}
+
+// shapingDelay draws the delay for one event under a rule.
+func shapingDelay(r config.ShapingRule) time.Duration {
+	ms := r.DelayMs
+	if r.JitterMs > 0 {
+		ms += rand.IntN(r.JitterMs + 1)
+	}
+	if r.ReorderMs > 0 && rand.Float64()*100 < r.ReorderPercent {
+		ms += rand.IntN(r.ReorderMs + 1)
+	}
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+func shapingConfig(env string, rules ...config.ShapingRule) *config.Config {
+	cfg := &config.Config{}
+	cfg.Service.Environment = env
+	cfg.Delivery.Shaping.Rules = rules
+	return cfg
+}
+
+func TestShapingDelayDistribution(t *testing.T) {
+	const draws = 20000
+	tests := []struct {
+		name       string
+		rule       config.ShapingRule
+		min, max   int     // Bounds in ms
+		mean       float64 // Expected mean in ms
+		wantHeld   float64 // Expected share of draws above min, 0..1
+		tolerance  float64 // Absolute tolerance on mean (ms) and share
+		checkShare bool
+	}{
+		{name: "fixed delay", rule: config.ShapingRule{DelayMs: 200}, min: 200, max: 200, mean: 200},
+		{name: "delay with jitter", rule: config.ShapingRule{DelayMs: 100, JitterMs: 50}, min: 100, max: 150, mean: 125, tolerance: 2},
+		{name: "reorder never drawn", rule: config.ShapingRule{ReorderMs: 40}, min: 0, max: 0, mean: 0},
+		{name: "reorder always drawn", rule: config.ShapingRule{ReorderMs: 40, ReorderPercent: 100}, min: 0, max: 40, mean: 20, tolerance: 1},
+		{
+			name: "reorder drawn for a share of events",
+			rule: config.ShapingRule{DelayMs: 10, ReorderMs: 100, ReorderPercent: 30},
+			min:  10, max: 110, mean: 10 + 0.3*50, tolerance: 2,
+			// One in 101 held draws adds zero, hence the slightly lower share.
+			wantHeld: 0.3 * 100 / 101, checkShare: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sum float64
+			held := 0
+			for range draws {
+				ms := int(shapingDelay(tt.rule) / time.Millisecond)
+				if ms < tt.min || ms > tt.max {
+					t.Fatalf("delay %dms outside [%d, %d]", ms, tt.min, tt.max)
+				}
+				if ms > tt.min {
+					held++
+				}
+				sum += float64(ms)
+			}
+			if mean := sum / draws; mean < tt.mean-tt.tolerance || mean > tt.mean+tt.tolerance {
+				t.Fatalf("mean delay %.2fms, want %.2f±%.2f", mean, tt.mean, tt.tolerance)
+			}
+			if share := float64(held) / draws; tt.checkShare && (share < tt.wantHeld-0.02 || share > tt.wantHeld+0.02) {
+				t.Fatalf("held share %.3f, want %.3f±0.02", share, tt.wantHeld)
+			}
+		})
+	}
+}
+
+// TestShapeActivation checks the environment guard, domain scoping and that the
+// connector's own channel is handed back untouched when nothing applies.
+func TestShapeActivation(t *testing.T) {
+	typing := config.ShapingRule{Kind: event.Typing.String(), DelayMs: 10}
+	scoped := config.ShapingRule{DomainID: 2, Kind: "*", DelayMs: 10}
+	tests := []struct {
+		name   string
+		cfg    *config.Config
+		domain int64
+		active bool
+		listed int // Rules reported by the admin listing
+	}{
+		{name: "no rules", cfg: shapingConfig("staging"), domain: 1},
+		{name: "production ignores rules", cfg: shapingConfig(config.EnvProduction, typing), domain: 1},
+		{name: "staging applies rules", cfg: shapingConfig("staging", typing), domain: 1, active: true, listed: 1},
+		{name: "rule for another domain", cfg: shapingConfig("staging", scoped), domain: 1, listed: 1},
+		{name: "rule for this domain", cfg: shapingConfig("staging", scoped), domain: 2, active: true, listed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestDeliveryService(t, tt.cfg)
+			conn := registry.NewConnector(context.Background(), uuid.New(), 8, registry.ConnectMetadata{})
+			t.Cleanup(conn.Close)
+
+			recv, stop := s.Shape(conn, tt.domain)
+			t.Cleanup(stop)
+			if passthrough := recv == conn.Recv(); passthrough == tt.active {
+				t.Fatalf("passthrough = %v with shaping active = %v", passthrough, tt.active)
+			}
+			if got := slices.Contains(s.Capabilities(tt.domain), CapabilityDeliveryShaping); got != tt.active {
+				t.Fatalf("capability advertised = %v, want %v", got, tt.active)
+			}
+			if got := len(s.shaper.Rules()); got != tt.listed {
+				t.Fatalf("Rules lists %d rules, want %d", got, tt.listed)
+			}
+		})
+	}
+}
+
+// TestShapeReorderWindow checks that a held event is overtaken by a later, unshaped
+// one and arrives tagged with the delay it was held for.
+func TestShapeReorderWindow(t *testing.T) {
+	const hold = 50 * time.Millisecond
+	tests := []struct {
+		name  string
+		rule  config.ShapingRule
+		order []event.EventKind // Arrival order; sent as Typing, then Ping
+	}{
+		{
+			name:  "held kind is overtaken",
+			rule:  config.ShapingRule{Kind: event.Typing.String(), DelayMs: int(hold / time.Millisecond)},
+			order: []event.EventKind{event.Ping, event.Typing},
+		},
+		{
+			name:  "unrelated kind keeps its order",
+			rule:  config.ShapingRule{Kind: event.ReadReceipt.String(), DelayMs: int(hold / time.Millisecond)},
+			order: []event.EventKind{event.Typing, event.Ping},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shaper := NewDeliveryShaper(shapingConfig("staging", tt.rule))
+			userID := uuid.New()
+			conn := registry.NewConnector(context.Background(), userID, 8, registry.ConnectMetadata{})
+			t.Cleanup(conn.Close)
+
+			recv, stop := shaper.Shape(conn, 0)
+			t.Cleanup(stop)
+
+			for _, kind := range []event.EventKind{event.Typing, event.Ping} {
+				if !conn.Send(event.NewSystemEvent(userID, kind, event.PriorityNormal, nil), time.Second) {
+					t.Fatalf("send %v rejected", kind)
+				}
+			}
+
+			var got []event.EventKind
+			timeout := time.After(time.Second)
+			for len(got) < len(tt.order) {
+				select {
+				case ev := <-recv:
+					got = append(got, ev.GetKind())
+					shaped, ok := ev.(event.Shaped)
+					if want := ev.GetKind().String() == tt.rule.Kind; ok != want {
+						t.Fatalf("%v tagged as shaped = %v, want %v", ev.GetKind(), ok, want)
+					}
+					if ok && shaped.GetShapingDelay() != hold {
+						t.Fatalf("tagged delay %v, want %v", shaped.GetShapingDelay(), hold)
+					}
+				case <-timeout:
+					t.Fatalf("received %v, want %v", got, tt.order)
+				}
+			}
+			if !slices.Equal(got, tt.order) {
+				t.Fatalf("arrival order %v, want %v", got, tt.order)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+)
+
+const (
+	slowWindow        = time.Minute
+	slowWindowHistory = 60 // Closed windows kept for GetSlowDeliveries
+)
+
+// ErrSlowWindowNotFound is returned for windows that are open, unknown or expired.
+var ErrSlowWindowNotFound = errors.New("slow delivery window not available")
+
+var slowDeliveryLatency, _ = meter.Float64Histogram(
+	"im_delivery_slow_delivery_latency_seconds",
+	metric.WithDescription("End-to-end latency of deliveries above the exemplar threshold"),
+	metric.WithUnit("s"),
+)
+
+// StageBreakdown is the per-stage latency of one delivery leg.
+type StageBreakdown struct {
+	Enrich  time.Duration `json:"enrich"`  // consume -> enrich
+	Hub     time.Duration `json:"hub"`     // broadcast -> dequeue (mailbox wait)
+	Marshal time.Duration `json:"marshal"` // transport encoding
+	Write   time.Duration `json:"write"`   // transport write
+}
+
+// slowest names the stage that contributed most.
+func (b StageBreakdown) slowest() string {
+	stage, worst := "enrich", b.Enrich
+	for name, d := range map[string]time.Duration{"hub": b.Hub, "marshal": b.Marshal, "write": b.Write} {
+		if d > worst {
+			stage, worst = name, d
+		}
+	}
+	return stage
+}
+
+// SlowDelivery is one exemplar. User and domain are hashed; no content is kept.
+type SlowDelivery struct {
+	Kind         string         `json:"kind"`
+	EventID      string         `json:"event_id"`
+	SourceID     string         `json:"source_id"`
+	UserHash     string         `json:"user_hash"`
+	DomainHash   string         `json:"domain_hash"`
+	Transport    string         `json:"transport"`
+	Total        time.Duration  `json:"total"`
+	Stages       StageBreakdown `json:"stages"`
+	SlowestStage string         `json:"slowest_stage"`
+	BufferPeak   int            `json:"buffer_peak"` // Connector pressure at write time
+	At           time.Time      `json:"at"`
+}
+
+type slowWindowRecord struct {
+	start time.Time
+	top   map[string][]SlowDelivery // kind -> slowest first
+}
+
+// SlowDeliveryTracker keeps the K slowest deliveries per kind of each one-minute window.
+//
+// [STRATEGY]
+// Every message event carries coarse stage stamps (event.StageTimes plus the Cell's
+// dequeue stamp) at the cost of a few int64 stores. Transports report marshal/write
+// durations after each write; anything under the threshold returns after one comparison,
+// and only qualifying legs pay for hashing, locking and the breakdown.
+type SlowDeliveryTracker struct {
+	cfg    *config.Config
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	current slowWindowRecord
+	history []slowWindowRecord // Oldest first
+}
+
+func NewSlowDeliveryTracker(cfg *config.Config, logger *slog.Logger, lc fx.Lifecycle) *SlowDeliveryTracker {
+	t := &SlowDeliveryTracker{
+		cfg:     cfg,
+		logger:  logger,
+		current: slowWindowRecord{start: time.Now().Truncate(slowWindow), top: make(map[string][]SlowDelivery)},
+	}
+
+	stop := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go t.run(stop)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			close(stop)
+			return nil
+		},
+	})
+	return t
+}
+
+// run closes windows on time so exemplars are logged even when traffic stops.
+func (t *SlowDeliveryTracker) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(slowWindow / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			t.mu.Lock()
+			t.rotate(now)
+			t.mu.Unlock()
+		}
+	}
+}
+
+// Observe is called by transports after a successful write.
+func (t *SlowDeliveryTracker) Observe(ev event.Eventer, transport Transport, marshal, write time.Duration, bufferPeak int) {
+	threshold := time.Duration(t.cfg.Delivery.Slow.ThresholdMs) * time.Millisecond
+	if threshold <= 0 {
+		return
+	}
+
+	stages, dequeued, ok := event.StagesOf(ev)
+	if !ok {
+		return
+	}
+	total := time.Duration(event.Monotime() - stages.Consume)
+	if total < threshold {
+		return // [FAST_PATH] One subtraction and compare for healthy deliveries.
+	}
+
+	breakdown := StageBreakdown{
+		Enrich:  time.Duration(stages.Enrich - stages.Consume),
+		Marshal: marshal,
+		Write:   write,
+	}
+	if dequeued != 0 {
+		breakdown.Hub = time.Duration(dequeued - stages.Broadcast)
+	}
+
+	sourceID, domainID, _ := sourceOf(ev)
+	rec := SlowDelivery{
+		Kind:         ev.GetKind().String(),
+		EventID:      ev.GetID(),
+		SourceID:     sourceID,
+		UserHash:     hashID(ev.GetUserID().String()),
+		DomainHash:   hashID(strconv.FormatInt(domainID, 10)),
+		Transport:    string(transport),
+		Total:        total,
+		Stages:       breakdown,
+		SlowestStage: breakdown.slowest(),
+		BufferPeak:   bufferPeak,
+		At:           time.Now(),
+	}
+
+	slowDeliveryLatency.Record(context.Background(), total.Seconds(), metric.WithAttributes(
+		attribute.String("kind", rec.Kind),
+		attribute.String("stage", rec.SlowestStage),
+	))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rotate(rec.At)
+	t.insert(rec)
+}
+
+// insert keeps the per-kind list sorted slowest first and capped at TopK.
+func (t *SlowDeliveryTracker) insert(rec SlowDelivery) {
+	k := max(t.cfg.Delivery.Slow.TopK, 1)
+	list := t.current.top[rec.Kind]
+
+	if len(list) == k && list[k-1].Total >= rec.Total {
+		return
+	}
+	i, _ := slices.BinarySearchFunc(list, rec.Total, func(d SlowDelivery, total time.Duration) int {
+		// Descending order.
+		switch {
+		case d.Total > total:
+			return -1
+		case d.Total < total:
+			return 1
+		}
+		return 0
+	})
+	list = slices.Insert(list, i, rec)
+	if len(list) > k {
+		list = list[:k]
+	}
+	t.current.top[rec.Kind] = list
+}
+
+// rotate closes the current window if now is past it. Must hold t.mu.
+func (t *SlowDeliveryTracker) rotate(now time.Time) {
+	start := now.Truncate(slowWindow)
+	if !start.After(t.current.start) {
+		return
+	}
+
+	closed := t.current
+	for kind, list := range closed.top {
+		for rank, rec := range list {
+			t.logger.Warn("SLOW_DELIVERY_EXEMPLAR",
+				"window", closed.start,
+				"kind", kind,
+				"rank", rank+1,
+				"event_id", rec.EventID,
+				"source_id", rec.SourceID,
+				"user_hash", rec.UserHash,
+				"domain_hash", rec.DomainHash,
+				"transport", rec.Transport,
+				"total", rec.Total,
+				"slowest_stage", rec.SlowestStage,
+				"enrich", rec.Stages.Enrich,
+				"hub", rec.Stages.Hub,
+				"marshal", rec.Stages.Marshal,
+				"write", rec.Stages.Write,
+				"buffer_peak", rec.BufferPeak,
+			)
+		}
+	}
+
+	if len(closed.top) > 0 {
+		t.history = append(t.history, closed)
+		if len(t.history) > slowWindowHistory {
+			t.history = t.history[len(t.history)-slowWindowHistory:]
+		}
+	}
+	t.current = slowWindowRecord{start: start, top: make(map[string][]SlowDelivery)}
+}
+
+// SlowWindowStart returns the start of the exemplar window containing ts.
+func SlowWindowStart(ts time.Time) time.Time {
+	return ts.Truncate(slowWindow)
+}
+
+// GetSlowDeliveries returns the exemplars of the closed window containing ts.
+// An empty kind returns every kind. It backs the DeliveryAdmin.GetSlowDeliveries RPC.
+func (t *SlowDeliveryTracker) GetSlowDeliveries(ts time.Time, kind string) ([]SlowDelivery, error) {
+	start := SlowWindowStart(ts)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, w := range t.history {
+		if !w.start.Equal(start) {
+			continue
+		}
+		if kind != "" {
+			return slices.Clone(w.top[kind]), nil
+		}
+		var all []SlowDelivery
+		for _, list := range w.top {
+			all = append(all, list...)
+		}
+		slices.SortFunc(all, func(a, b SlowDelivery) int { return cmp.Compare(b.Total, a.Total) })
+		return all, nil
+	}
+	return nil, ErrSlowWindowNotFound
+}
+
+// hashID pseudonymizes identifiers in exemplars.
+func hashID(id string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/event"
+	"github.com/webitel/im-delivery-service/internal/domain/model"
+)
+
+func newTestSlowTracker(topK int) *SlowDeliveryTracker {
+	cfg := &config.Config{}
+	cfg.Delivery.Slow.ThresholdMs = 100
+	cfg.Delivery.Slow.TopK = topK
+	return &SlowDeliveryTracker{
+		cfg:     cfg,
+		logger:  slog.New(slog.DiscardHandler),
+		current: slowWindowRecord{start: SlowWindowStart(time.Now()), top: make(map[string][]SlowDelivery)},
+	}
+}
+
+// stagedEvent builds a message event whose stamps place the given delays at the enrich
+// and hub stages. Transport time is reported, not stamped, so it only shifts Consume back.
+func stagedEvent(enrich, hub, transport time.Duration) event.Eventer {
+	ev := event.NewMessageV1Event(&model.Message{ID: uuid.New()}, uuid.New(), model.Peer{}, model.Peer{})
+	dequeued := event.Monotime() - int64(transport)
+	ev.Stages = event.StageTimes{
+		Consume:   dequeued - int64(hub) - int64(enrich),
+		Enrich:    dequeued - int64(hub),
+		Broadcast: dequeued - int64(hub),
+	}
+	return &event.SequencedEvent{Eventer: ev, DequeuedAt: dequeued}
+}
+
+func TestSlowDeliveryTracker_Observe(t *testing.T) {
+	tests := []struct {
+		name           string
+		enrich, hub    time.Duration
+		marshal, write time.Duration
+		wantStage      string // empty: below the threshold, nothing captured
+	}{
+		{name: "fast delivery", enrich: time.Millisecond, hub: time.Millisecond},
+		{name: "slow enrichment", enrich: 150 * time.Millisecond, hub: time.Millisecond, wantStage: "enrich"},
+		{name: "mailbox wait", enrich: time.Millisecond, hub: 150 * time.Millisecond, wantStage: "hub"},
+		{name: "slow marshal", enrich: 60 * time.Millisecond, hub: time.Millisecond, marshal: 80 * time.Millisecond, wantStage: "marshal"},
+		{name: "slow write", enrich: 60 * time.Millisecond, hub: time.Millisecond, write: 90 * time.Millisecond, wantStage: "write"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTestSlowTracker(5)
+			ev := stagedEvent(tt.enrich, tt.hub, tt.marshal+tt.write)
+			tr.Observe(ev, TransportGRPC, tt.marshal, tt.write, 3)
+
+			got := tr.current.top[event.MessageCreated.String()]
+			if tt.wantStage == "" {
+				if len(got) != 0 {
+					t.Fatalf("captured %d exemplars below the threshold", len(got))
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("exemplars: got %d, want 1", len(got))
+			}
+			if got[0].SlowestStage != tt.wantStage {
+				t.Fatalf("slowest stage: got %q, want %q (%+v)", got[0].SlowestStage, tt.wantStage, got[0].Stages)
+			}
+			if got[0].Transport != string(TransportGRPC) || got[0].BufferPeak != 3 {
+				t.Fatalf("leg state not kept: %+v", got[0])
+			}
+		})
+	}
+}
+
+func TestSlowDeliveryTracker_TopKAcrossWindows(t *testing.T) {
+	base := SlowWindowStart(time.Now()).Add(-10 * slowWindow)
+	rec := func(kind string, total time.Duration, at time.Time) SlowDelivery {
+		return SlowDelivery{Kind: kind, Total: total, At: at}
+	}
+	tests := []struct {
+		name    string
+		topK    int
+		records []SlowDelivery
+		window  time.Time
+		kind    string
+		want    []time.Duration
+		wantErr error
+	}{
+		{
+			name: "keeps the slowest K",
+			topK: 2,
+			records: []SlowDelivery{
+				rec("a", 200*time.Millisecond, base),
+				rec("a", 500*time.Millisecond, base.Add(time.Second)),
+				rec("a", 300*time.Millisecond, base.Add(2*time.Second)),
+			},
+			window: base,
+			kind:   "a",
+			want:   []time.Duration{500 * time.Millisecond, 300 * time.Millisecond},
+		},
+		{
+			name: "windows do not mix",
+			topK: 2,
+			records: []SlowDelivery{
+				rec("a", 900*time.Millisecond, base),
+				rec("a", 200*time.Millisecond, base.Add(slowWindow)),
+				rec("a", 100*time.Millisecond, base.Add(slowWindow+time.Second)),
+			},
+			window: base.Add(slowWindow + 30*time.Second),
+			kind:   "a",
+			want:   []time.Duration{200 * time.Millisecond, 100 * time.Millisecond},
+		},
+		{
+			name: "every kind, slowest first",
+			topK: 1,
+			records: []SlowDelivery{
+				rec("a", 200*time.Millisecond, base),
+				rec("b", 400*time.Millisecond, base),
+			},
+			window: base,
+			want:   []time.Duration{400 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:    "window without exemplars",
+			topK:    1,
+			records: []SlowDelivery{rec("a", 200*time.Millisecond, base)},
+			window:  base.Add(slowWindow),
+			wantErr: ErrSlowWindowNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTestSlowTracker(tt.topK)
+			tr.current = slowWindowRecord{start: base, top: make(map[string][]SlowDelivery)}
+			for _, r := range tt.records {
+				tr.rotate(r.At)
+				tr.insert(r)
+			}
+			// Close the last window, as the ticker would.
+			tr.rotate(base.Add(5 * slowWindow))
+
+			got, err := tr.GetSlowDeliveries(tt.window, tt.kind)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tt.wantErr)
+			}
+			totals := make([]time.Duration, len(got))
+			for i, d := range got {
+				totals[i] = d.Total
+			}
+			if !slices.Equal(totals, tt.want) {
+				t.Fatalf("totals: got %v, want %v", totals, tt.want)
+			}
+		})
+	}
+}
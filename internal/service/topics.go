@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTopicKeyInvalid    = errors.New("topic key must be <exchange>.<kind>.<domain_id>.<entity_id>")
+	ErrTopicForeignDomain = errors.New("topic key belongs to another domain")
+	ErrTopicForbidden     = errors.New("topic not authorized for the user")
+)
+
+// TopicDomainSegment is the position of the tenant in a topic key. Topic keys are the
+// routing keys of the ephemeral-topic exchanges, e.g. im_call.transcript.{domain_id}.{call_id}.
+const TopicDomainSegment = 2
+
+// TopicAuthorizer decides whether a user may follow the entity behind a topic key,
+// e.g. whether they take part in the call or serve the queue.
+type TopicAuthorizer interface {
+	AuthorizeTopic(ctx context.Context, userID uuid.UUID, domainID int64, key string) error
+}
+
+// WithTopicAuthorizer checks every topic subscription against a.
+// [FAIL_CLOSED] Without an authorizer every subscription is refused.
+func WithTopicAuthorizer(a TopicAuthorizer) Option {
+	return func(s *DeliveryService) {
+		s.topicAuth = a
+	}
+}
+
+// TopicDomain reads the tenant a topic key belongs to.
+func TopicDomain(key string) (int64, error) {
+	parts := strings.Split(key, ".")
+	if len(parts) <= TopicDomainSegment+1 || parts[len(parts)-1] == "" {
+		return 0, ErrTopicKeyInvalid
+	}
+	domainID, err := strconv.ParseInt(parts[TopicDomainSegment], 10, 64)
+	if err != nil || domainID <= 0 {
+		return 0, ErrTopicKeyInvalid
+	}
+	return domainID, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/webitel/im-delivery-service/config"
+	"github.com/webitel/im-delivery-service/internal/domain/registry"
+)
+
+// callMembers authorizes each user for the topic keys listed for them.
+type callMembers map[uuid.UUID][]string
+
+var errNotInCall = errors.New("not a participant of the call")
+
+func (m callMembers) AuthorizeTopic(_ context.Context, userID uuid.UUID, _ int64, key string) error {
+	for _, k := range m[userID] {
+		if k == key {
+			return nil
+		}
+	}
+	return errNotInCall
+}
+
+func TestTopicDomain(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    int64
+		wantErr error
+	}{
+		{key: "im_call.transcript.7.call-1", want: 7},
+		{key: "im_call.transcript.call-1", wantErr: ErrTopicKeyInvalid},
+		{key: "im_call.transcript.0.call-1", wantErr: ErrTopicKeyInvalid},
+		{key: "im_call.transcript.7.", wantErr: ErrTopicKeyInvalid},
+		{key: "", wantErr: ErrTopicKeyInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := TopicDomain(tt.key)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Fatalf("TopicDomain = %d, %v; want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSubscribeTopicTenantIsolation checks that a session only follows keys of its own
+// domain whose entity it is authorized for, and that the Hub indexes them per domain.
+func TestSubscribeTopicTenantIsolation(t *testing.T) {
+	const own = "im_call.transcript.1.call-1"
+	userID := uuid.New()
+	tests := []struct {
+		name    string
+		auth    TopicAuthorizer
+		key     string
+		wantErr error
+	}{
+		{name: "authorized call in own domain", auth: callMembers{userID: {own}}, key: own},
+		{name: "foreign domain", auth: callMembers{userID: {own, "im_call.transcript.2.call-1"}}, key: "im_call.transcript.2.call-1", wantErr: ErrTopicForeignDomain},
+		{name: "not a participant", auth: callMembers{userID: {own}}, key: "im_call.transcript.1.call-2", wantErr: ErrTopicForbidden},
+		{name: "no authorizer", key: own, wantErr: ErrTopicForbidden},
+		{name: "key without a domain", auth: callMembers{userID: {"im_call.transcript.call-1"}}, key: "im_call.transcript.call-1", wantErr: ErrTopicKeyInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, hub := newTestDeliveryService(t, &config.Config{})
+			WithTopicAuthorizer(tt.auth)(s)
+			conn := registry.NewConnector(t.Context(), userID, 8, registry.ConnectMetadata{})
+
+			err := s.SubscribeTopic(t.Context(), conn, 1, tt.key, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SubscribeTopic = %v, want %v", err, tt.wantErr)
+			}
+			if got := hub.HasTopicSubscribers(registry.DomainTopicKey(1, tt.key)); got != (tt.wantErr == nil) {
+				t.Fatalf("subscribed = %v after %v", got, err)
+			}
+			if hub.HasTopicSubscribers(tt.key) {
+				t.Fatal("topic indexed without its domain")
+			}
+		})
+	}
+}
@@ -0,0 +1,542 @@
+// Command dtogen generates the inbound DTO structs from the JSON Schemas vendored
+// under contracts/. It understands the subset of JSON Schema the producers publish
+// (objects, arrays, scalars, $ref, allOf for embedding) plus a few x-go-* hints.
+//
+// Usage (see internal/service/dto/generate.go):
+//
+//	go run ../../tools/dtogen -pkg dto -out zz_contracts.go <schema>...
+//	go run ../../tools/dtogen -pkg dto -out zz_contracts.go -verify <schema>...
+//
+// -verify is the drift check: it fails if the checked-in file differs from a fresh
+// generation, or if any vendored example payload (<schema dir>/examples/<event>*.json,
+// where <event>.schema.json is the schema) does not conform to it (unknown field, wrong type, missing required field).
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+func main() {
+	pkg := flag.String("pkg", "dto", "package name of the generated file")
+	out := flag.String("out", "zz_contracts.go", "output file")
+	verify := flag.Bool("verify", false, "check the output is current and the examples conform")
+	flag.Parse()
+
+	if err := run(*pkg, *out, *verify, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "dtogen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, out string, verify bool, paths []string) error {
+	if len(paths) == 0 {
+		return errors.New("no schema files given")
+	}
+
+	reg := &registry{byPath: make(map[string]*schema)}
+	var roots []*schema
+	for _, p := range paths {
+		s, err := reg.load(p)
+		if err != nil {
+			return err
+		}
+		roots = append(roots, s)
+	}
+
+	src, err := generate(pkg, reg, roots)
+	if err != nil {
+		return err
+	}
+
+	if !verify {
+		return os.WriteFile(out, src, 0o644)
+	}
+
+	current, err := os.ReadFile(out)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, src) {
+		return fmt.Errorf("%s is stale; run go generate", out)
+	}
+
+	var failures []string
+	for _, root := range roots {
+		// [PAIRING] Several events share a directory; examples are matched by event name.
+		name := strings.TrimSuffix(filepath.Base(root.path), ".schema.json")
+		examples, _ := filepath.Glob(filepath.Join(filepath.Dir(root.path), "examples", name+"*.json"))
+		for _, ex := range examples {
+			if err := reg.validateFile(ex, root); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", ex, err))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New("contract drift:\n  " + strings.Join(failures, "\n  "))
+	}
+	return nil
+}
+
+// schema is the supported JSON Schema subset.
+type schema struct {
+	Ref             string      `json:"$ref"`
+	ID              string      `json:"$id"`
+	Type            string      `json:"type"`
+	Format          string      `json:"format"`
+	ContentEncoding string      `json:"contentEncoding"`
+	Description     string      `json:"description"`
+	Items           *schema     `json:"items"`
+	AllOf           []*schema   `json:"allOf"`
+	Properties      orderedDefs `json:"properties"`
+	Defs            orderedDefs `json:"$defs"`
+	Required        []string    `json:"required"`
+
+	GoName     string   `json:"x-go-name"`
+	GoType     string   `json:"x-go-type"`
+	GoPointer  bool     `json:"x-go-pointer"`
+	OmitEmpty  bool     `json:"x-omitempty"`
+	Extensions []string `json:"x-allowed-extensions"`
+
+	path string // Set on file roots
+}
+
+// orderedDefs keeps declaration order so generated fields follow the contract.
+type orderedDefs struct {
+	keys []string
+	m    map[string]*schema
+}
+
+func (o *orderedDefs) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	o.m = make(map[string]*schema)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		s := new(schema)
+		if err := dec.Decode(s); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		o.keys = append(o.keys, key)
+		o.m[key] = s
+	}
+	_, err := dec.Token()
+	return err
+}
+
+type registry struct {
+	byPath map[string]*schema
+}
+
+func (r *registry) load(path string) (*schema, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := r.byPath[abs]; ok {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	s := new(schema)
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.GoName == "" {
+		return nil, fmt.Errorf("%s: root schema needs x-go-name", path)
+	}
+	s.path = abs
+	r.byPath[abs] = s
+	return s, nil
+}
+
+// resolve follows a $ref from within root; it returns the target, its Go name and its file root.
+func (r *registry) resolve(root *schema, ref string) (*schema, string, *schema, error) {
+	if def, ok := strings.CutPrefix(ref, "#/$defs/"); ok {
+		s, found := root.Defs.m[def]
+		if !found {
+			return nil, "", nil, fmt.Errorf("unknown $ref %q", ref)
+		}
+		return s, defName(def, s), root, nil
+	}
+
+	target, err := r.load(filepath.Join(filepath.Dir(root.path), ref))
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return target, target.GoName, target, nil
+}
+
+func defName(key string, s *schema) string {
+	if s.GoName != "" {
+		return s.GoName
+	}
+	return key + "DTO"
+}
+
+var initialisms = map[string]string{"id": "ID", "url": "URL", "uuid": "UUID"}
+
+// goField turns a snake_case JSON key into an exported Go identifier.
+func goField(key string) string {
+	var b strings.Builder
+	for part := range strings.SplitSeq(key, "_") {
+		if up, ok := initialisms[part]; ok {
+			b.WriteString(up)
+			continue
+		}
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+// --- generation ---
+
+type generator struct {
+	reg     *registry
+	buf     bytes.Buffer
+	emitted map[string]bool
+}
+
+func generate(pkg string, reg *registry, roots []*schema) ([]byte, error) {
+	g := &generator{reg: reg, emitted: make(map[string]bool)}
+
+	var sources []string
+	for _, root := range roots {
+		rel, err := filepath.Rel(filepath.Dir(roots[0].path), root.path)
+		if err != nil {
+			rel = filepath.Base(root.path)
+		}
+		sources = append(sources, contractPath(root.path, rel))
+	}
+
+	fmt.Fprintf(&g.buf, "// Code generated by dtogen from %s; DO NOT EDIT.\n\n", strings.Join(sources, ", "))
+	fmt.Fprintf(&g.buf, "package %s\n\n", pkg)
+
+	for _, root := range roots {
+		if err := g.object(root.GoName, root, root, true); err != nil {
+			return nil, err
+		}
+		for _, key := range root.Defs.keys {
+			def := root.Defs.m[key]
+			if err := g.object(defName(key, def), def, root, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format: %w\n%s", err, g.buf.Bytes())
+	}
+	return src, nil
+}
+
+// contractPath renders a schema path from the contracts/ directory for the header.
+func contractPath(abs, fallback string) string {
+	if i := strings.LastIndex(abs, string(filepath.Separator)+"contracts"+string(filepath.Separator)); i >= 0 {
+		return filepath.ToSlash(abs[i+1:])
+	}
+	return filepath.ToSlash(fallback)
+}
+
+type field struct {
+	name, jsonKey, goType string
+	required              bool
+	nested                string // Go type name of an object-typed field (value, pointer or slice element)
+	kind                  string // "value", "pointer", "slice", "map", "scalar"
+}
+
+func (g *generator) object(name string, s, root *schema, isRoot bool) error {
+	if g.emitted[name] {
+		return nil
+	}
+	g.emitted[name] = true
+
+	if isRoot && s.ID != "" {
+		fmt.Fprintf(&g.buf, "// %s mirrors the %s contract.\n", name, s.ID)
+	}
+	if s.Description != "" {
+		if isRoot && s.ID != "" {
+			fmt.Fprintln(&g.buf, "//")
+		}
+		fmt.Fprintf(&g.buf, "// %s\n", s.Description)
+	}
+	fmt.Fprintf(&g.buf, "type %s struct {\n", name)
+
+	var embedded []string
+	for _, parent := range s.AllOf {
+		_, parentName, _, err := g.reg.resolve(root, parent.Ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		embedded = append(embedded, parentName)
+		fmt.Fprintf(&g.buf, "\t%s\n", parentName)
+	}
+	if len(embedded) > 0 {
+		fmt.Fprintln(&g.buf)
+	}
+
+	var fields []field
+	for _, key := range s.Properties.keys {
+		f, err := g.field(key, s.Properties.m[key], root)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, key, err)
+		}
+		f.required = slices.Contains(s.Required, key)
+		fields = append(fields, f)
+
+		tag := key
+		if s.Properties.m[key].OmitEmpty {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s `json:%q`\n", f.name, f.goType, tag)
+	}
+	fmt.Fprint(&g.buf, "}\n\n")
+
+	g.checkContract(name, embedded, fields)
+	if isRoot {
+		g.strictDecoder(name, s.Extensions)
+	}
+	return nil
+}
+
+func (g *generator) field(key string, p, root *schema) (field, error) {
+	f := field{name: goField(key), jsonKey: key, kind: "scalar"}
+
+	switch {
+	case p.GoType != "":
+		f.goType = p.GoType
+	case p.Ref != "":
+		_, typeName, _, err := g.reg.resolve(root, p.Ref)
+		if err != nil {
+			return f, err
+		}
+		f.nested, f.goType, f.kind = typeName, typeName, "value"
+		if p.GoPointer {
+			f.goType, f.kind = "*"+typeName, "pointer"
+		}
+	case p.Type == "array":
+		if p.Items == nil {
+			return f, errors.New("array without items")
+		}
+		elem, err := g.field(key, p.Items, root)
+		if err != nil {
+			return f, err
+		}
+		f.goType, f.kind = "[]"+elem.goType, "slice"
+		if elem.kind == "value" {
+			f.nested = elem.nested
+		}
+	case p.Type == "object":
+		if len(p.Properties.keys) > 0 {
+			return f, errors.New("inline objects are not supported; use $defs")
+		}
+		f.goType, f.kind = "map[string]any", "map"
+	case p.Type == "string":
+		f.goType = "string"
+		if p.ContentEncoding == "base64" {
+			f.goType, f.kind = "[]byte", "slice"
+		}
+	case p.Type == "integer":
+		switch p.Format {
+		case "int32":
+			f.goType = "int32"
+		default:
+			f.goType = "int64"
+		}
+	case p.Type == "number":
+		f.goType = "float64"
+	case p.Type == "boolean":
+		f.goType = "bool"
+	default:
+		return f, fmt.Errorf("unsupported type %q", p.Type)
+	}
+	return f, nil
+}
+
+func (g *generator) checkContract(name string, embedded []string, fields []field) {
+	fmt.Fprintf(&g.buf, "// CheckContract reports the first required field of %s that is missing.\n", name)
+	fmt.Fprintf(&g.buf, "func (d *%s) CheckContract() error {\n", name)
+	for _, e := range embedded {
+		fmt.Fprintf(&g.buf, "\tif err := d.%s.CheckContract(); err != nil {\n\t\treturn err\n\t}\n", e)
+	}
+
+	for _, f := range fields {
+		switch {
+		case f.kind == "value":
+			if f.required {
+				fmt.Fprintf(&g.buf, "\tif err := d.%s.CheckContract(); err != nil {\n\t\treturn fieldErr(%q, err)\n\t}\n", f.name, f.jsonKey)
+			}
+		case f.kind == "pointer":
+			if f.required {
+				fmt.Fprintf(&g.buf, "\tif d.%s == nil {\n\t\treturn missingField(%q)\n\t}\n", f.name, f.jsonKey)
+			}
+			fmt.Fprintf(&g.buf, "\tif d.%s != nil {\n\t\tif err := d.%s.CheckContract(); err != nil {\n\t\t\treturn fieldErr(%q, err)\n\t\t}\n\t}\n", f.name, f.name, f.jsonKey)
+		case f.nested != "":
+			if f.required {
+				fmt.Fprintf(&g.buf, "\tif d.%s == nil {\n\t\treturn missingField(%q)\n\t}\n", f.name, f.jsonKey)
+			}
+			fmt.Fprintf(&g.buf, "\tfor i := range d.%s {\n\t\tif err := d.%s[i].CheckContract(); err != nil {\n\t\t\treturn fieldErr(%q, err)\n\t\t}\n\t}\n", f.name, f.name, f.jsonKey)
+		case !f.required:
+		case f.goType == "string" || f.goType == "[]byte":
+			fmt.Fprintf(&g.buf, "\tif len(d.%s) == 0 {\n\t\treturn missingField(%q)\n\t}\n", f.name, f.jsonKey)
+		case f.kind == "slice" || f.kind == "map":
+			fmt.Fprintf(&g.buf, "\tif d.%s == nil {\n\t\treturn missingField(%q)\n\t}\n", f.name, f.jsonKey)
+		case f.goType != "bool":
+			fmt.Fprintf(&g.buf, "\tif d.%s == 0 {\n\t\treturn missingField(%q)\n\t}\n", f.name, f.jsonKey)
+		}
+	}
+	fmt.Fprint(&g.buf, "\treturn nil\n}\n\n")
+}
+
+func (g *generator) strictDecoder(name string, extensions []string) {
+	exts := "nil"
+	if len(extensions) > 0 {
+		exts = fmt.Sprintf("%#v", extensions)
+	}
+	fmt.Fprintf(&g.buf, "// Decode%sStrict decodes data, rejecting fields the contract does not declare.\n", name)
+	fmt.Fprintf(&g.buf, "func Decode%sStrict(data []byte) (*%s, error) {\n", name, name)
+	fmt.Fprintf(&g.buf, "\tv := new(%s)\n\tif err := decodeStrict(data, v, %s); err != nil {\n\t\treturn nil, err\n\t}\n\treturn v, nil\n}\n\n", name, exts)
+}
+
+// --- example validation ---
+
+func (r *registry) validateFile(path string, root *schema) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return r.validate(v, root, root, "$")
+}
+
+// objectShape merges the properties, required keys and extensions contributed by allOf parents.
+func (r *registry) objectShape(s, root *schema) (map[string]*schema, map[string]*schema, []string, []string, error) {
+	props := make(map[string]*schema)
+	owners := make(map[string]*schema) // Property -> file root used to resolve its refs
+	required := slices.Clone(s.Required)
+	exts := slices.Clone(s.Extensions)
+
+	for _, parent := range s.AllOf {
+		target, _, targetRoot, err := r.resolve(root, parent.Ref)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		pp, po, pr, pe, err := r.objectShape(target, targetRoot)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		for k, v := range pp {
+			props[k], owners[k] = v, po[k]
+		}
+		required = append(required, pr...)
+		exts = append(exts, pe...)
+	}
+	for k, v := range s.Properties.m {
+		props[k], owners[k] = v, root
+	}
+	return props, owners, required, exts, nil
+}
+
+func (r *registry) validate(v any, s, root *schema, at string) error {
+	if s.Ref != "" {
+		target, _, targetRoot, err := r.resolve(root, s.Ref)
+		if err != nil {
+			return err
+		}
+		if v == nil && s.GoPointer {
+			return nil
+		}
+		return r.validate(v, target, targetRoot, at)
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: want object", at)
+		}
+		props, owners, required, exts, err := r.objectShape(s, root)
+		if err != nil {
+			return err
+		}
+		if len(props) == 0 {
+			return nil // Free-form map
+		}
+		for _, key := range required {
+			if _, ok := obj[key]; !ok {
+				return fmt.Errorf("%s.%s: required field missing", at, key)
+			}
+		}
+		for key, val := range obj {
+			p, ok := props[key]
+			if !ok {
+				if slices.Contains(exts, key) {
+					continue
+				}
+				return fmt.Errorf("%s.%s: field not in contract", at, key)
+			}
+			if err := r.validate(val, p, owners[key], at+"."+key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: want array", at)
+		}
+		for i, item := range arr {
+			if err := r.validate(item, s.Items, root, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: want string", at)
+		}
+		if s.ContentEncoding == "base64" {
+			if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+				return fmt.Errorf("%s: invalid base64", at)
+			}
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s: want integer", at)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: want number", at)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: want boolean", at)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCheckedInContracts is the CI drift check: the generated DTOs must be current
+// and every vendored example must conform to its schema.
+func TestCheckedInContracts(t *testing.T) {
+	schemas, err := filepath.Glob(filepath.Join("..", "..", "..", "contracts", "*", "*", "*.schema.json"))
+	if err != nil || len(schemas) == 0 {
+		t.Fatalf("no vendored schemas: %v", err)
+	}
+	if err := run("dto", filepath.Join("..", "..", "service", "dto", "zz_contracts.go"), true, schemas); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const testSchema = `{
+  "$id": "test.v1.ping",
+  "type": "object",
+  "x-go-name": "PingV1",
+  "x-allowed-extensions": ["trace"],
+  "properties": {
+    "ping_id": { "type": "string" },
+    "seq": { "type": "integer" },
+    "payload": { "type": "string", "contentEncoding": "base64", "x-go-type": "[]byte" },
+    "peer": { "$ref": "#/$defs/Peer" }
+  },
+  "required": ["ping_id", "seq"],
+  "$defs": {
+    "Peer": {
+      "type": "object",
+      "properties": { "id": { "type": "string" } },
+      "required": ["id"]
+    }
+  }
+}`
+
+func TestRunVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		example string // Empty writes no example
+		stale   bool
+		wantErr string
+	}{
+		{name: "conforming example", example: `{"ping_id":"p","seq":1,"payload":"aGk=","peer":{"id":"x"}}`},
+		{name: "allowlisted extension", example: `{"ping_id":"p","seq":1,"trace":"t"}`},
+		{name: "no examples"},
+		{name: "stale output", stale: true, wantErr: "is stale"},
+		{name: "unknown field", example: `{"ping_id":"p","seq":1,"pong":true}`, wantErr: "$.pong: field not in contract"},
+		{name: "renamed key", example: `{"pingId":"p","seq":1}`, wantErr: "$.ping_id: required field missing"},
+		{name: "type change", example: `{"ping_id":"p","seq":"1"}`, wantErr: "$.seq: want integer"},
+		{name: "fractional integer", example: `{"ping_id":"p","seq":1.5}`, wantErr: "$.seq: want integer"},
+		{name: "bad base64", example: `{"ping_id":"p","seq":1,"payload":"!"}`, wantErr: "$.payload: invalid base64"},
+		{name: "nested required field", example: `{"ping_id":"p","seq":1,"peer":{}}`, wantErr: "$.peer.id: required field missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			schema := filepath.Join(dir, "ping.schema.json")
+			out := filepath.Join(dir, "zz_contracts.go")
+			if err := os.WriteFile(schema, []byte(testSchema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.example != "" {
+				if err := os.Mkdir(filepath.Join(dir, "examples"), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "examples", "ping.json"), []byte(tt.example), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := run("dto", out, false, []string{schema}); err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			if tt.stale {
+				if err := os.WriteFile(out, []byte("package dto\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := run("dto", out, true, []string{schema})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("verify: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("verify: got %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ping.schema.json")
+	if err := os.WriteFile(path, []byte(testSchema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg := &registry{byPath: make(map[string]*schema)}
+	root, err := reg.load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate("dto", reg, []*schema{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"// PingV1 mirrors the test.v1.ping contract.",
+		"PingID  string  `json:\"ping_id\"`",
+		"Payload []byte  `json:\"payload\"`",
+		"Peer    PeerDTO `json:\"peer\"`",
+		"func (d *PingV1) CheckContract() error {",
+		`return missingField("ping_id")`,
+		`decodeStrict(data, v, []string{"trace"})`,
+		"type PeerDTO struct {",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated source lacks %q", want)
+		}
+	}
+	if t.Failed() {
+		t.Logf("generated:\n%s", src)
+	}
+}
+
+func TestGoField(t *testing.T) {
+	tests := []struct{ key, want string }{
+		{"message_id", "MessageID"},
+		{"url", "URL"},
+		{"recipient_uuid", "RecipientUUID"},
+		{"is_forward", "IsForward"},
+		{"body", "Body"},
+		{"trailing_", "Trailing"},
+	}
+	for _, tt := range tests {
+		if got := goField(tt.key); got != tt.want {
+			t.Errorf("goField(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
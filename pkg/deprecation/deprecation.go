@@ -0,0 +1,304 @@
+// Package deprecation instruments legacy code paths so they can be removed on data.
+//
+// Each deprecated feature is registered once, usually as a package-level variable
+// next to the code path it guards:
+//
+//	var legacyFrames = deprecation.Register("unversioned_ws_frame", "WS frames without a version")
+//
+// and reports every use:
+//
+//	if err := legacyFrames.Use(ctx, deprecation.Scope{DomainID: dc}); err != nil { ... }
+//
+// A use increments a per-feature, per-domain, per-client-version counter and emits a
+// rate-limited warning. Features switched to ModeReject return a *RejectedError instead,
+// so removal can be staged: warn, reject, delete. Paths that are not deprecated never
+// touch this package.
+package deprecation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Mode is the escalation level of a deprecated feature.
+type Mode int32
+
+const (
+	ModeWarn Mode = iota
+	ModeReject
+)
+
+func (m Mode) String() string {
+	if m == ModeReject {
+		return "reject"
+	}
+	return "warn"
+}
+
+// ErrRejected matches every *RejectedError via errors.Is.
+var ErrRejected = errors.New("deprecation: feature rejected")
+
+// RejectedError reports a use of a feature escalated to ModeReject.
+type RejectedError struct {
+	Feature string
+}
+
+func (e *RejectedError) Error() string {
+	return "deprecation: feature " + strconv.Quote(e.Feature) + " is no longer supported"
+}
+
+func (e *RejectedError) Unwrap() error { return ErrRejected }
+
+// Scope identifies who used a feature; zero fields are reported as unknown.
+type Scope struct {
+	DomainID      int64
+	ClientVersion string
+}
+
+// Usage is a point-in-time view of one feature.
+type Usage struct {
+	Feature     string
+	Description string
+	Mode        Mode
+	Count       int64
+	LastSeen    time.Time // Zero when never used
+}
+
+var meter = otel.Meter("github.com/webitel/im-delivery-service/pkg/deprecation")
+
+var usageCounter, _ = meter.Int64Counter(
+	"im_delivery_deprecated_usage_total",
+	metric.WithDescription("Uses of deprecated features by domain and client version"),
+)
+
+const defaultLogInterval = time.Minute
+
+// Registry holds the registered features. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	features map[string]*Feature
+	pending  map[string]Mode // Modes configured before the feature registered
+	onChange []func()        // Run after a registered feature changes mode
+
+	revision    atomic.Uint64
+	logger      atomic.Pointer[slog.Logger]
+	logInterval atomic.Int64
+}
+
+// NewRegistry creates an empty registry logging through slog.Default.
+func NewRegistry() *Registry {
+	r := &Registry{
+		features: make(map[string]*Feature),
+		pending:  make(map[string]Mode),
+	}
+	r.logInterval.Store(int64(defaultLogInterval))
+	return r
+}
+
+// Default is the process-wide registry used by the package-level helpers.
+var Default = NewRegistry()
+
+// Register returns the feature with the given name, creating it on first call.
+func (r *Registry) Register(name, description string) *Feature {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.features[name]; ok {
+		return f
+	}
+
+	f := &Feature{name: name, description: description, reg: r}
+	if m, ok := r.pending[name]; ok {
+		f.mode.Store(int32(m))
+		delete(r.pending, name)
+	}
+	r.features[name] = f
+	return f
+}
+
+// SetMode escalates or relaxes a feature. Unknown names are remembered and applied
+// when the feature registers, so config may be loaded before every package initialises.
+// An actual change of a registered feature bumps Revision and runs the OnModeChange hooks.
+func (r *Registry) SetMode(name string, m Mode) {
+	r.mu.Lock()
+	f, ok := r.features[name]
+	if !ok {
+		r.pending[name] = m
+		r.mu.Unlock()
+		return
+	}
+	changed := Mode(f.mode.Swap(int32(m))) != m
+	hooks := r.onChange
+	r.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	r.revision.Add(1)
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// Revision counts mode changes of registered features. Output that depends on
+// feature modes (e.g. a rejected field left out of an encoding) keys its caches by it.
+func (r *Registry) Revision() uint64 { return r.revision.Load() }
+
+// OnModeChange registers fn to run after a registered feature changes mode.
+// Hooks run synchronously on the caller of SetMode, after Revision is bumped.
+func (r *Registry) OnModeChange(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = slices.Clip(append(r.onChange, fn))
+}
+
+// SetLogger replaces the warning logger.
+func (r *Registry) SetLogger(l *slog.Logger) { r.logger.Store(l) }
+
+// SetLogInterval sets the minimum gap between two warnings for the same feature.
+func (r *Registry) SetLogInterval(d time.Duration) { r.logInterval.Store(int64(d)) }
+
+// Usage returns every registered feature ordered by name.
+func (r *Registry) Usage() []Usage {
+	r.mu.RLock()
+	res := make([]Usage, 0, len(r.features))
+	for _, f := range r.features {
+		res = append(res, f.usage())
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(res, func(a, b Usage) int { return strings.Compare(a.Feature, b.Feature) })
+	return res
+}
+
+func (r *Registry) log() *slog.Logger {
+	if l := r.logger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// Feature is a registered deprecated code path.
+type Feature struct {
+	name        string
+	description string
+	reg         *Registry
+
+	mode       atomic.Int32
+	count      atomic.Int64
+	lastSeen   atomic.Int64 // UnixNano
+	lastLogged atomic.Int64 // UnixNano
+	suppressed atomic.Int64 // Uses since the last warning
+}
+
+// Name returns the registered feature name.
+func (f *Feature) Name() string { return f.name }
+
+// Rejected reports whether the feature is escalated to ModeReject.
+func (f *Feature) Rejected() bool { return Mode(f.mode.Load()) == ModeReject }
+
+// Use records one use and returns a *RejectedError when the feature is rejected.
+// Rejected uses are still counted so the effect of escalation stays visible.
+func (f *Feature) Use(ctx context.Context, s Scope) error {
+	now := time.Now().UnixNano()
+	f.count.Add(1)
+	f.lastSeen.Store(now)
+
+	mode := Mode(f.mode.Load())
+	usageCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("feature", f.name),
+		attribute.String("domain_id", scopeDomain(s.DomainID)),
+		attribute.String("client_version", scopeVersion(s.ClientVersion)),
+		attribute.String("mode", mode.String()),
+	))
+
+	f.warn(now, mode, s)
+
+	if mode == ModeReject {
+		return &RejectedError{Feature: f.name}
+	}
+	return nil
+}
+
+// warn logs at most once per interval per feature, folding suppressed uses into the next line.
+func (f *Feature) warn(now int64, mode Mode, s Scope) {
+	suppressed := f.suppressed.Add(1)
+
+	last := f.lastLogged.Load()
+	if last != 0 && now-last < f.reg.logInterval.Load() {
+		return
+	}
+	if !f.lastLogged.CompareAndSwap(last, now) {
+		return
+	}
+	f.suppressed.Add(-suppressed)
+
+	f.reg.log().Warn("DEPRECATED_FEATURE_USED",
+		slog.String("feature", f.name),
+		slog.String("description", f.description),
+		slog.String("mode", mode.String()),
+		slog.Int64("domain_id", s.DomainID),
+		slog.String("client_version", s.ClientVersion),
+		slog.Int64("uses_since_last_warning", suppressed),
+		slog.Int64("total", f.count.Load()),
+	)
+}
+
+func (f *Feature) usage() Usage {
+	u := Usage{
+		Feature:     f.name,
+		Description: f.description,
+		Mode:        Mode(f.mode.Load()),
+		Count:       f.count.Load(),
+	}
+	if ns := f.lastSeen.Load(); ns != 0 {
+		u.LastSeen = time.Unix(0, ns)
+	}
+	return u
+}
+
+func scopeDomain(id int64) string {
+	if id == 0 {
+		return "unknown"
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func scopeVersion(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
+
+// Register registers a feature on the Default registry.
+func Register(name, description string) *Feature { return Default.Register(name, description) }
+
+// GetDeprecationUsage returns the Default registry's counts and last-seen timestamps.
+func GetDeprecationUsage() []Usage { return Default.Usage() }
+
+// OnModeChange registers fn with the default registry; see Registry.OnModeChange.
+func OnModeChange(fn func()) { Default.OnModeChange(fn) }
+
+// Names returns the names of the features in fs that the caller just used, for handshakes.
+func Names(fs ...*Feature) []string {
+	if len(fs) == 0 {
+		return nil
+	}
+	res := make([]string, 0, len(fs))
+	for _, f := range fs {
+		res = append(res, f.name)
+	}
+	return res
+}
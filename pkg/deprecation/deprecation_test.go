@@ -0,0 +1,135 @@
+package deprecation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeatureUse(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        Mode
+		preset      bool // SetMode before Register
+		uses        int
+		logInterval time.Duration
+		wantCount   int64
+		wantLogs    int
+		wantErr     error
+	}{
+		{name: "single use", mode: ModeWarn, uses: 1, logInterval: time.Hour, wantCount: 1, wantLogs: 1},
+		{name: "warnings are rate limited per interval", mode: ModeWarn, uses: 5, logInterval: time.Hour, wantCount: 5, wantLogs: 1},
+		{name: "no rate limit logs each use", mode: ModeWarn, uses: 3, logInterval: -1, wantCount: 3, wantLogs: 3},
+		{name: "reject still counts", mode: ModeReject, uses: 2, logInterval: time.Hour, wantCount: 2, wantLogs: 1, wantErr: ErrRejected},
+		{name: "mode set before registration", mode: ModeReject, preset: true, uses: 1, logInterval: time.Hour, wantCount: 1, wantLogs: 1, wantErr: ErrRejected},
+		{name: "unused feature", mode: ModeWarn, logInterval: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r := NewRegistry()
+			r.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+			r.SetLogInterval(tt.logInterval)
+
+			if tt.preset {
+				r.SetMode("legacy", tt.mode)
+			}
+			f := r.Register("legacy", "legacy path")
+			if !tt.preset {
+				r.SetMode("legacy", tt.mode)
+			}
+
+			var err error
+			for range tt.uses {
+				err = f.Use(context.Background(), Scope{DomainID: 1, ClientVersion: "1.0"})
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tt.wantErr)
+			}
+			if f.Rejected() != (tt.mode == ModeReject) {
+				t.Fatalf("rejected: got %v", f.Rejected())
+			}
+
+			usage := r.Usage()
+			if len(usage) != 1 || usage[0].Count != tt.wantCount || usage[0].Mode != tt.mode {
+				t.Fatalf("usage: got %+v", usage)
+			}
+			if usage[0].LastSeen.IsZero() != (tt.uses == 0) {
+				t.Fatalf("last seen: got %v", usage[0].LastSeen)
+			}
+			if logs := strings.Count(buf.String(), "DEPRECATED_FEATURE_USED"); logs != tt.wantLogs {
+				t.Fatalf("warnings: got %d, want %d", logs, tt.wantLogs)
+			}
+		})
+	}
+}
+
+func TestModeChange(t *testing.T) {
+	type step struct {
+		name string
+		mode Mode
+	}
+	tests := []struct {
+		name         string
+		steps        []step
+		wantRevision uint64
+	}{
+		{name: "escalation", steps: []step{{"legacy", ModeReject}}, wantRevision: 1},
+		{name: "escalate and relax", steps: []step{{"legacy", ModeReject}, {"legacy", ModeWarn}}, wantRevision: 2},
+		{name: "repeated mode is not a change", steps: []step{{"legacy", ModeReject}, {"legacy", ModeReject}}, wantRevision: 1},
+		{name: "default mode is not a change", steps: []step{{"legacy", ModeWarn}}},
+		{name: "unregistered feature is pending", steps: []step{{"unknown", ModeReject}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			r.Register("legacy", "legacy path")
+
+			var calls uint64
+			var seen []uint64
+			r.OnModeChange(func() {
+				calls++
+				seen = append(seen, r.Revision())
+			})
+
+			for _, s := range tt.steps {
+				r.SetMode(s.name, s.mode)
+			}
+			if got := r.Revision(); got != tt.wantRevision {
+				t.Fatalf("revision: got %d, want %d", got, tt.wantRevision)
+			}
+			if calls != tt.wantRevision {
+				t.Fatalf("hooks: got %d calls, want %d", calls, tt.wantRevision)
+			}
+			for i, rev := range seen {
+				if rev != uint64(i+1) {
+					t.Fatalf("hook %d saw revision %d; it must run after the bump", i, rev)
+				}
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	r := NewRegistry()
+	a, b := r.Register("a", ""), r.Register("b", "")
+	tests := []struct {
+		name string
+		fs   []*Feature
+		want string
+	}{
+		{name: "none", want: ""},
+		{name: "in order", fs: []*Feature{b, a}, want: "b,a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(Names(tt.fs...), ","); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package reconnect computes the backoff guidance im-delivery-service attaches to
+// every retryable disconnect, and helps clients honor it.
+//
+// Identical client backoffs make a restarted node receive its whole population in
+// synchronized waves. The server therefore picks each client's wait from a jittered
+// window whose width grows with load:
+//
+//	window = base * (1 + utilisation * loadFactor)   // capped at max
+//	storm mode widens the window further (stormFactor)
+//	during shutdown the n-th disconnected client waits an extra n*spreadStep
+//
+// The hint travels as retry_after_ms in DisconnectedPayload / the WS close frame,
+// as RetryInfo on gRPC statuses and as Retry-After on HTTP responses.
+package reconnect
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reason classifies a retryable disconnect.
+type Reason string
+
+const (
+	ReasonShutdown      Reason = "SHUTDOWN"
+	ReasonRebalancing   Reason = "REBALANCING"
+	ReasonSlowConsumer  Reason = "SLOW_CONSUMER"
+	ReasonAdmission     Reason = "ADMISSION_REJECTED"
+	ReasonSessionClosed Reason = "SESSION_CLOSED"
+)
+
+// Load is the node state the window is derived from.
+type Load struct {
+	Connected int  // Users with a live session on this node
+	Capacity  int  // Soft capacity; utilisation is Connected/Capacity
+	Storm     bool // Reconnect storm detected
+}
+
+// LoadFunc samples the current load; injected so the policy stays testable.
+type LoadFunc func() Load
+
+// Hint is the guidance sent to a disconnected client.
+type Hint struct {
+	Reason        Reason
+	RetryAfter    time.Duration
+	AlternateNode string // Optional: a healthier peer known to discovery
+}
+
+// RetryAfterMs renders the wait for JSON payloads.
+func (h Hint) RetryAfterMs() int64 { return h.RetryAfter.Milliseconds() }
+
+// RetryAfterHeader renders the wait as an HTTP Retry-After value (whole seconds, rounded up).
+func (h Hint) RetryAfterHeader() string {
+	secs := (h.RetryAfter + time.Second - 1) / time.Second
+	return strconv.FormatInt(int64(max(secs, 1)), 10)
+}
+
+// Advisor computes hints. It is safe for concurrent use.
+type Advisor struct {
+	load      LoadFunc
+	alternate func() string
+
+	base        time.Duration
+	max         time.Duration
+	loadFactor  float64
+	stormFactor float64
+	spreadStep  time.Duration
+
+	storm        atomic.Bool
+	shuttingDown atomic.Bool
+	shutdownSeq  atomic.Int64
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// Option defines a functional configuration type for the Advisor.
+type Option func(*Advisor)
+
+// WithWindow sets the base jitter window and its upper bound.
+func WithWindow(base, max time.Duration) Option {
+	return func(a *Advisor) {
+		a.base, a.max = base, max
+	}
+}
+
+// WithShutdownSpread sets the extra wait added per client disconnected during shutdown.
+func WithShutdownSpread(step time.Duration) Option {
+	return func(a *Advisor) {
+		a.spreadStep = step
+	}
+}
+
+// WithAlternateNode injects a lookup for a healthier peer address ("" when unknown).
+func WithAlternateNode(fn func() string) Option {
+	return func(a *Advisor) {
+		a.alternate = fn
+	}
+}
+
+// WithSeed makes the jitter reproducible.
+func WithSeed(seed uint64) Option {
+	return func(a *Advisor) {
+		a.rng = rand.New(rand.NewPCG(seed, seed))
+	}
+}
+
+// NewAdvisor creates an Advisor; a nil load func means an idle node.
+func NewAdvisor(load LoadFunc, opts ...Option) *Advisor {
+	if load == nil {
+		load = func() Load { return Load{} }
+	}
+
+	a := &Advisor{
+		load:        load,
+		base:        time.Second,
+		max:         30 * time.Second,
+		loadFactor:  4,
+		stormFactor: 3,
+		spreadStep:  5 * time.Millisecond,
+		rng:         rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// SetStorm toggles storm-mode widening.
+func (a *Advisor) SetStorm(on bool) { a.storm.Store(on) }
+
+// BeginShutdown starts the shutdown phase; from now on every hint is spread by order of disconnect.
+func (a *Advisor) BeginShutdown() { a.shuttingDown.Store(true) }
+
+// ShuttingDown reports whether BeginShutdown was called.
+func (a *Advisor) ShuttingDown() bool { return a.shuttingDown.Load() }
+
+// Window returns the current jitter window width.
+func (a *Advisor) Window() time.Duration {
+	l := a.load()
+
+	w := float64(a.base)
+	if l.Capacity > 0 {
+		util := min(float64(l.Connected)/float64(l.Capacity), 1)
+		w *= 1 + util*a.loadFactor
+	}
+	if l.Storm || a.storm.Load() {
+		w *= a.stormFactor
+	}
+	return min(time.Duration(w), a.max)
+}
+
+// Advise returns the hint for one disconnecting client.
+func (a *Advisor) Advise(reason Reason) Hint {
+	if a.shuttingDown.Load() {
+		reason = ReasonShutdown
+	}
+
+	var offset time.Duration
+	if reason == ReasonShutdown {
+		// [HERD_SMEARING] Later-disconnected clients wait longer.
+		offset = time.Duration(a.shutdownSeq.Add(1)) * a.spreadStep
+	}
+
+	window := a.Window()
+	a.mu.Lock()
+	jitter := time.Duration(a.rng.Int64N(int64(window) + 1))
+	a.mu.Unlock()
+
+	h := Hint{Reason: reason, RetryAfter: offset + jitter}
+	if a.alternate != nil {
+		h.AlternateNode = a.alternate()
+	}
+	return h
+}
+
+// Wait blocks for the hinted duration or until ctx ends; clients call it before redialing.
+func Wait(ctx context.Context, retryAfter time.Duration) error {
+	if retryAfter <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(retryAfter)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,153 @@
+package reconnect
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name  string
+		load  Load
+		storm bool // Set through SetStorm rather than the load sample
+		want  time.Duration
+	}{
+		{name: "idle node", want: time.Second},
+		{name: "no capacity configured", load: Load{Connected: 5000}, want: time.Second},
+		{name: "half loaded", load: Load{Connected: 500, Capacity: 1000}, want: 3 * time.Second},
+		{name: "fully loaded", load: Load{Connected: 1000, Capacity: 1000}, want: 5 * time.Second},
+		{name: "overloaded counts as full", load: Load{Connected: 4000, Capacity: 1000}, want: 5 * time.Second},
+		{name: "storm from the load sample", load: Load{Storm: true}, want: 3 * time.Second},
+		{name: "storm mode toggled", storm: true, want: 3 * time.Second},
+		{name: "storm on a full node is capped", load: Load{Connected: 1000, Capacity: 1000}, storm: true, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAdvisor(func() Load { return tt.load }, WithWindow(time.Second, 10*time.Second))
+			a.SetStorm(tt.storm)
+			if got := a.Window(); got != tt.want {
+				t.Fatalf("Window = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAdviseJitter checks that hints spread uniformly over the current window.
+func TestAdviseJitter(t *testing.T) {
+	const (
+		draws   = 50000
+		buckets = 10
+	)
+	tests := []struct {
+		name   string
+		load   Load
+		window time.Duration
+	}{
+		{name: "idle", window: time.Second},
+		{name: "loaded", load: Load{Connected: 1000, Capacity: 1000}, window: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAdvisor(func() Load { return tt.load }, WithWindow(time.Second, 30*time.Second), WithSeed(1))
+
+			var counts [buckets]int
+			for range draws {
+				h := a.Advise(ReasonSlowConsumer)
+				if h.Reason != ReasonSlowConsumer {
+					t.Fatalf("reason %s, want %s", h.Reason, ReasonSlowConsumer)
+				}
+				if h.RetryAfter < 0 || h.RetryAfter > tt.window {
+					t.Fatalf("retry after %v outside [0, %v]", h.RetryAfter, tt.window)
+				}
+				counts[min(int(h.RetryAfter*buckets/tt.window), buckets-1)]++
+			}
+
+			// Chi-squared over 10 buckets; 27.88 is the 0.999 quantile for 9 degrees of freedom.
+			expected := float64(draws) / buckets
+			var chi2 float64
+			for _, c := range counts {
+				d := float64(c) - expected
+				chi2 += d * d / expected
+			}
+			if chi2 > 27.88 {
+				t.Fatalf("jitter is not uniform: chi2 = %.2f, buckets %v", chi2, counts)
+			}
+		})
+	}
+}
+
+// TestAdviseShutdownSpread checks that later-disconnected clients wait longer once
+// shutdown begins, whatever reason the caller reports.
+func TestAdviseShutdownSpread(t *testing.T) {
+	const step = time.Second
+	a := NewAdvisor(nil, WithWindow(100*time.Millisecond, 100*time.Millisecond), WithShutdownSpread(step), WithSeed(1))
+
+	if h := a.Advise(ReasonSlowConsumer); h.Reason != ReasonSlowConsumer || h.RetryAfter > 100*time.Millisecond {
+		t.Fatalf("before shutdown: %+v", h)
+	}
+
+	a.BeginShutdown()
+	for n := 1; n <= 5; n++ {
+		h := a.Advise(ReasonSlowConsumer)
+		lo := time.Duration(n) * step
+		if h.Reason != ReasonShutdown || h.RetryAfter < lo || h.RetryAfter > lo+100*time.Millisecond {
+			t.Fatalf("client %d: %+v, want %s within [%v, %v]", n, h, ReasonShutdown, lo, lo+100*time.Millisecond)
+		}
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	tests := []struct {
+		after time.Duration
+		want  string
+	}{
+		{0, "1"},
+		{300 * time.Millisecond, "1"},
+		{time.Second, "1"},
+		{1001 * time.Millisecond, "2"},
+		{12 * time.Second, "12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.after.String(), func(t *testing.T) {
+			if got := (Hint{RetryAfter: tt.after}).RetryAfterHeader(); got != tt.want {
+				t.Fatalf("RetryAfterHeader = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWait checks that a client sleeps exactly the hinted duration before redialing.
+func TestWait(t *testing.T) {
+	tests := []struct {
+		name        string
+		retryAfter  time.Duration
+		cancelAfter time.Duration // 0: never
+		want        time.Duration
+		wantErr     error
+	}{
+		{name: "no hint", want: 0},
+		{name: "hinted wait", retryAfter: 2500 * time.Millisecond, want: 2500 * time.Millisecond},
+		{name: "cancelled while waiting", retryAfter: 5 * time.Second, cancelAfter: time.Second, want: time.Second, wantErr: context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(t.Context())
+				defer cancel()
+				if tt.cancelAfter > 0 {
+					time.AfterFunc(tt.cancelAfter, cancel)
+				}
+
+				start := time.Now()
+				if err := Wait(ctx, tt.retryAfter); err != tt.wantErr {
+					t.Fatalf("Wait = %v, want %v", err, tt.wantErr)
+				}
+				if got := time.Since(start); got != tt.want {
+					t.Fatalf("waited %v, want %v", got, tt.want)
+				}
+			})
+		})
+	}
+}
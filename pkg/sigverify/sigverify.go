@@ -0,0 +1,74 @@
+// Package sigverify verifies webhook signatures emitted by im-delivery-service.
+//
+// The service sends an HMAC-SHA256 of the raw request body in the header
+//
+//	X-Webitel-Signature: v1,kid=<key id>,sig=<base64 signature>
+//
+// Consumers hold one or more shared secrets keyed by id; during rotation both
+// the old and the new secret should be configured until the old id stops appearing.
+package sigverify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// HeaderName is the HTTP header carrying the signature.
+const HeaderName = "X-Webitel-Signature"
+
+var (
+	ErrMalformedHeader = errors.New("sigverify: malformed signature header")
+	ErrUnknownKey      = errors.New("sigverify: unknown key id")
+	ErrMismatch        = errors.New("sigverify: signature mismatch")
+)
+
+// Parse splits a header value into its key id and raw signature.
+func Parse(header string) (keyID string, sig []byte, err error) {
+	parts := strings.Split(header, ",")
+	if len(parts) != 3 || parts[0] != "v1" {
+		return "", nil, ErrMalformedHeader
+	}
+
+	for _, p := range parts[1:] {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok {
+			return "", nil, ErrMalformedHeader
+		}
+		switch k {
+		case "kid":
+			keyID = v
+		case "sig":
+			if sig, err = base64.StdEncoding.DecodeString(v); err != nil {
+				return "", nil, ErrMalformedHeader
+			}
+		}
+	}
+
+	if keyID == "" || len(sig) == 0 {
+		return "", nil, ErrMalformedHeader
+	}
+	return keyID, sig, nil
+}
+
+// Verify checks the header against body using the secret registered for its key id.
+func Verify(secrets map[string][]byte, body []byte, header string) error {
+	keyID, sig, err := Parse(header)
+	if err != nil {
+		return err
+	}
+
+	secret, ok := secrets[keyID]
+	if !ok {
+		return ErrUnknownKey
+	}
+
+	m := hmac.New(sha256.New, secret)
+	m.Write(body)
+	if !hmac.Equal(m.Sum(nil), sig) {
+		return ErrMismatch
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package sigverify_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/webitel/im-delivery-service/infra/keyring"
+	"github.com/webitel/im-delivery-service/pkg/sigverify"
+)
+
+func TestVerify(t *testing.T) {
+	kr, err := keyring.New(keyring.Snapshot{
+		Active: "k2",
+		Keys: map[string]string{
+			"k2": base64.StdEncoding.EncodeToString([]byte("two")),
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"event":"message_created"}`)
+	header, err := kr.SignatureHeader(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotating := map[string][]byte{"k1": []byte("one"), "k2": []byte("two")}
+	tests := []struct {
+		name    string
+		secrets map[string][]byte
+		body    []byte
+		header  string
+		wantErr error
+	}{
+		{name: "valid", secrets: rotating, body: body, header: header},
+		{name: "consumer lacks the key", secrets: map[string][]byte{"k1": []byte("one")}, body: body, header: header, wantErr: sigverify.ErrUnknownKey},
+		{name: "wrong secret", secrets: map[string][]byte{"k2": []byte("other")}, body: body, header: header, wantErr: sigverify.ErrMismatch},
+		{name: "tampered body", secrets: rotating, body: []byte(`{}`), header: header, wantErr: sigverify.ErrMismatch},
+		{name: "empty header", secrets: rotating, body: body, header: "", wantErr: sigverify.ErrMalformedHeader},
+		{name: "unknown version", secrets: rotating, body: body, header: "v2,kid=k2,sig=AA==", wantErr: sigverify.ErrMalformedHeader},
+		{name: "missing key id", secrets: rotating, body: body, header: "v1,kid=,sig=AA==", wantErr: sigverify.ErrMalformedHeader},
+		{name: "signature not base64", secrets: rotating, body: body, header: "v1,kid=k2,sig=%%", wantErr: sigverify.ErrMalformedHeader},
+		{name: "field without a value", secrets: rotating, body: body, header: "v1,kid,sig=AA==", wantErr: sigverify.ErrMalformedHeader},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := sigverify.Verify(tt.secrets, tt.body, tt.header); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}